@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"k8c.io/kubermatic/v2/pkg/backupstore"
+	"k8c.io/kubermatic/v2/pkg/etcd/encryption"
+	"k8c.io/kubermatic/v2/pkg/log"
+)
+
+func main() {
+	logOpts := log.NewDefaultOptions()
+	logOpts.AddFlags(flag.CommandLine)
+	storeOpts := backupstore.NewDefaultOptions()
+	storeOpts.AddFlags(flag.CommandLine)
+
+	clusterName := flag.String("cluster", "", "Name of the user cluster the backup belongs to")
+	backupName := flag.String("backup", "", "Name of the backup to download")
+	file := flag.String("file", "/backup/snapshot.db", "The file to download the backup to")
+	encryptionKeysDir := flag.String("encryption-keys", "", "Directory containing the base64 encoded keys to decrypt encrypted backups")
+	flag.Parse()
+
+	rawLog := log.New(logOpts.Debug, logOpts.Format)
+	logger := rawLog.Sugar()
+	defer func() {
+		if err := logger.Sync(); err != nil {
+			fmt.Println(err)
+		}
+	}()
+
+	if *clusterName == "" || *backupName == "" {
+		logger.Fatal("Both 'cluster' and 'backup' must be set!")
+	}
+
+	objectName := fmt.Sprintf("%s-%s", *clusterName, *backupName)
+	logger = logger.With("bucket", storeOpts.Bucket, "object", objectName, "file", *file)
+
+	ctx := context.Background()
+	store, err := storeOpts.Store(ctx)
+	if err != nil {
+		logger.Fatalw("Failed to create backup store client", zap.Error(err))
+	}
+
+	var keys encryption.KeyRing
+	if *encryptionKeysDir != "" {
+		if keys, err = encryption.LoadKeyRing(*encryptionKeysDir); err != nil {
+			logger.Fatalw("Failed to load encryption keys", zap.Error(err))
+		}
+	}
+
+	logger.Info("Downloading backup")
+	if err := backupstore.DownloadFile(ctx, store, objectName, *file); err != nil {
+		logger.Fatalw("Failed to download backup", zap.Error(err))
+	}
+
+	encrypted, err := encryption.DecryptFileInPlace(*file, keys)
+	if err != nil {
+		logger.Fatalw("Failed to decrypt backup", zap.Error(err))
+	}
+	if encrypted {
+		logger.Info("Decrypted backup")
+	}
+
+	logger.Info("Backup downloaded")
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+
+	"k8c.io/kubermatic/v2/pkg/etcd/encryption"
+	"k8c.io/kubermatic/v2/pkg/log"
+)
+
+func main() {
+	logOpts := log.NewDefaultOptions()
+	logOpts.AddFlags(flag.CommandLine)
+
+	file := flag.String("file", "/backup/snapshot.db", "The snapshot to encrypt in place")
+	encryptionKeysDir := flag.String("encryption-keys", "", "Directory containing the base64 encoded encryption keys")
+	keyID := flag.String("key-id", "", "ID of the key used to encrypt the snapshot")
+	flag.Parse()
+
+	rawLog := log.New(logOpts.Debug, logOpts.Format)
+	logger := rawLog.Sugar()
+	defer func() {
+		if err := logger.Sync(); err != nil {
+			fmt.Println(err)
+		}
+	}()
+
+	if *encryptionKeysDir == "" || *keyID == "" {
+		logger.Fatal("Both 'encryption-keys' and 'key-id' must be set!")
+	}
+
+	logger = logger.With("file", *file, "key", *keyID)
+
+	keys, err := encryption.LoadKeyRing(*encryptionKeysDir)
+	if err != nil {
+		logger.Fatalw("Failed to load encryption keys", zap.Error(err))
+	}
+	key, ok := keys[*keyID]
+	if !ok {
+		logger.Fatal("Encryption key not found")
+	}
+
+	// the init container might be restarted after it already encrypted the snapshot
+	encrypted, err := encryption.IsEncryptedFile(*file)
+	if err != nil {
+		logger.Fatalw("Failed to read snapshot", zap.Error(err))
+	}
+	if encrypted {
+		logger.Info("Snapshot is already encrypted")
+		return
+	}
+
+	tmpFile := *file + ".encrypted"
+	if err := encryption.EncryptFile(*file, tmpFile, *keyID, key); err != nil {
+		logger.Fatalw("Failed to encrypt snapshot", zap.Error(err))
+	}
+	if err := os.Rename(tmpFile, *file); err != nil {
+		logger.Fatalw("Failed to replace snapshot", zap.Error(err))
+	}
+
+	logger.Info("Snapshot encrypted")
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"k8c.io/kubermatic/v2/pkg/backupstore"
+	"k8c.io/kubermatic/v2/pkg/log"
+)
+
+// etcd-backup-store uploads and deletes backups for destinations which are not S3 compatible and
+// can therefore not be handled by the configurable store and delete containers.
+func main() {
+	logOpts := log.NewDefaultOptions()
+	logOpts.AddFlags(flag.CommandLine)
+	storeOpts := backupstore.NewDefaultOptions()
+	storeOpts.AddFlags(flag.CommandLine)
+
+	clusterName := flag.String("cluster", "", "Name of the user cluster the backups belong to")
+	backupName := flag.String("backup", "", "Name of the backup to upload or delete")
+	file := flag.String("file", "/backup/snapshot.db", "The snapshot file to upload")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] upload|delete|delete-all\n", flag.CommandLine.Name())
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	rawLog := log.New(logOpts.Debug, logOpts.Format)
+	logger := rawLog.Sugar()
+	defer func() {
+		if err := logger.Sync(); err != nil {
+			fmt.Println(err)
+		}
+	}()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		logger.Fatal("Exactly one command must be given")
+	}
+	command := flag.Arg(0)
+
+	if *clusterName == "" {
+		logger.Fatal("'cluster' must be set!")
+	}
+	if *backupName == "" && command != "delete-all" {
+		logger.Fatal("'backup' must be set!")
+	}
+
+	objectName := fmt.Sprintf("%s-%s", *clusterName, *backupName)
+	logger = logger.With("bucket", storeOpts.Bucket)
+
+	ctx := context.Background()
+	store, err := storeOpts.Store(ctx)
+	if err != nil {
+		logger.Fatalw("Failed to create backup store client", zap.Error(err))
+	}
+
+	switch command {
+	case "upload":
+		logger = logger.With("object", objectName, "file", *file)
+
+		if err := store.EnsureBucket(ctx); err != nil {
+			logger.Fatalw("Failed to create bucket", zap.Error(err))
+		}
+
+		logger.Info("Uploading backup")
+		if err := backupstore.UploadFile(ctx, store, objectName, *file); err != nil {
+			logger.Fatalw("Failed to upload backup", zap.Error(err))
+		}
+		logger.Info("Backup uploaded")
+
+	case "delete":
+		logger = logger.With("object", objectName)
+
+		logger.Info("Deleting backup")
+		if err := store.Delete(ctx, objectName); err != nil {
+			logger.Fatalw("Failed to delete backup", zap.Error(err))
+		}
+		logger.Info("Backup deleted")
+
+	case "delete-all":
+		prefix := *clusterName + "-"
+		logger = logger.With("prefix", prefix)
+
+		objects, err := store.List(ctx, prefix)
+		if err != nil {
+			logger.Fatalw("Failed to list backups", zap.Error(err))
+		}
+
+		deleted := 0
+		for _, object := range objects {
+			// revision segments of continuous backups share the prefix, but are not ours to delete
+			if strings.Contains(strings.TrimPrefix(object.Name, prefix), "/") {
+				continue
+			}
+			if err := store.Delete(ctx, object.Name); err != nil {
+				logger.Fatalw("Failed to delete backup", "object", object.Name, zap.Error(err))
+			}
+			deleted++
+		}
+		logger.Infow("Backups deleted", "count", deleted)
+
+	default:
+		flag.Usage()
+		logger.Fatalf("Unknown command %q", command)
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"go.etcd.io/etcd/etcdutl/v3/snapshot"
+	"go.uber.org/zap"
+
+	"k8c.io/kubermatic/v2/pkg/backupstore"
+	"k8c.io/kubermatic/v2/pkg/etcd/encryption"
+	"k8c.io/kubermatic/v2/pkg/etcd/verification"
+	"k8c.io/kubermatic/v2/pkg/log"
+)
+
+func main() {
+	logOpts := log.NewDefaultOptions()
+	logOpts.AddFlags(flag.CommandLine)
+	storeOpts := backupstore.NewDefaultOptions()
+	storeOpts.AddFlags(flag.CommandLine)
+
+	clusterName := flag.String("cluster", "", "Name of the user cluster the backup belongs to")
+	backupName := flag.String("backup", "", "Name of the backup to verify")
+	restore := flag.Bool("restore", false, "Restore the snapshot into a temporary data directory after checking it")
+	encryptionKeysDir := flag.String("encryption-keys", "", "Directory containing the base64 encoded keys to decrypt encrypted backups")
+	terminationLog := flag.String("termination-log", "/dev/termination-log", "File to write the verification result to")
+	flag.Parse()
+
+	rawLog := log.New(logOpts.Debug, logOpts.Format)
+	logger := rawLog.Sugar()
+	defer func() {
+		if err := logger.Sync(); err != nil {
+			fmt.Println(err)
+		}
+	}()
+
+	if *clusterName == "" || *backupName == "" {
+		logger.Fatal("Both 'cluster' and 'backup' must be set!")
+	}
+
+	objectName := fmt.Sprintf("%s-%s", *clusterName, *backupName)
+	logger = logger.With("bucket", storeOpts.Bucket, "object", objectName)
+
+	ctx := context.Background()
+	store, err := storeOpts.Store(ctx)
+	if err != nil {
+		logger.Fatalw("Failed to create backup store client", zap.Error(err))
+	}
+
+	var keys encryption.KeyRing
+	if *encryptionKeysDir != "" {
+		if keys, err = encryption.LoadKeyRing(*encryptionKeysDir); err != nil {
+			logger.Fatalw("Failed to load encryption keys", zap.Error(err))
+		}
+	}
+
+	result, err := verify(ctx, logger, store, objectName, keys, *restore)
+	if err != nil {
+		// make the reason visible in the pod status, the controller picks it up from there
+		_ = ioutil.WriteFile(*terminationLog, []byte(err.Error()), 0644)
+		logger.Fatalw("Backup verification failed", zap.Error(err))
+	}
+
+	message, err := result.Encode()
+	if err != nil {
+		logger.Fatalw("Failed to encode verification result", zap.Error(err))
+	}
+	if err := ioutil.WriteFile(*terminationLog, []byte(message), 0644); err != nil {
+		logger.Fatalw("Failed to write verification result", zap.Error(err))
+	}
+
+	logger.Infow("Backup verified", "revision", result.Revision, "keys", result.KeyCount, "hash", result.Hash)
+}
+
+func verify(ctx context.Context, logger *zap.SugaredLogger, store backupstore.Store, objectName string, keys encryption.KeyRing, restore bool) (*verification.Result, error) {
+	tmpDir, err := ioutil.TempDir("", "etcd-backup-verifier")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	snapshotFile := filepath.Join(tmpDir, "snapshot.db")
+	logger.Info("Downloading backup")
+	if err := backupstore.DownloadFile(ctx, store, objectName, snapshotFile); err != nil {
+		return nil, fmt.Errorf("failed to download backup: %w", err)
+	}
+
+	encrypted, err := encryption.DecryptFileInPlace(snapshotFile, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt backup: %w", err)
+	}
+	if encrypted {
+		logger.Info("Decrypted backup")
+	}
+
+	sp := snapshot.NewV3(logger.Desugar())
+
+	status, err := sp.Status(snapshotFile)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot is corrupt: %w", err)
+	}
+
+	result := &verification.Result{
+		Revision: status.Revision,
+		KeyCount: int64(status.TotalKey),
+		Hash:     fmt.Sprintf("%08x", status.Hash),
+	}
+
+	if restore {
+		logger.Info("Restoring backup into temporary data directory")
+		dataDir := filepath.Join(tmpDir, "data")
+		if err := sp.Restore(snapshot.RestoreConfig{
+			SnapshotPath:        snapshotFile,
+			Name:                "verifier",
+			OutputDataDir:       dataDir,
+			OutputWALDir:        filepath.Join(dataDir, "member", "wal"),
+			PeerURLs:            []string{"http://127.0.0.1:2380"},
+			InitialCluster:      "verifier=http://127.0.0.1:2380",
+			InitialClusterToken: "verifier",
+			SkipHashCheck:       false,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to restore snapshot: %w", err)
+		}
+		result.Restored = true
+	}
+
+	return result, nil
+}
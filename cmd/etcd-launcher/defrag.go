@@ -0,0 +1,407 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	client "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1/helper"
+	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// defaultQuotaBackendBytes is the space quota etcd uses if none is configured.
+	defaultQuotaBackendBytes = 2 * 1024 * 1024 * 1024
+
+	defaultFragmentationThresholdPercent = 50
+	// alarmDisarmThreshold is the share of the quota all members have to be below before
+	// NOSPACE alarms are disarmed, so that the cluster does not immediately run full again.
+	alarmDisarmThreshold = 0.8
+
+	metricsAddress = ":2382"
+
+	defragmentationInterval = 10 * time.Minute
+	timeoutDefragment       = 5 * time.Minute
+	timeoutMemberStatus     = 5 * time.Second
+	timeoutMemberHealthy    = 2 * time.Minute
+)
+
+var defaultMinDBSize = resource.MustParse("100Mi")
+
+var (
+	defragmentations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "etcd_launcher",
+		Name:      "defragmentations_total",
+		Help:      "The number of defragmentations of etcd members by result",
+	}, []string{"member", "result"})
+
+	lastDefragmentation = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "etcd_launcher",
+		Name:      "last_defragmentation_timestamp_seconds",
+		Help:      "The time of the last successful defragmentation of etcd members",
+	}, []string{"member"})
+
+	spaceAlarmActive = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "etcd_launcher",
+		Name:      "space_alarm_active",
+		Help:      "Whether a NOSPACE alarm is raised in the etcd cluster",
+	})
+
+	alarmDisarms = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "etcd_launcher",
+		Name:      "alarm_disarms_total",
+		Help:      "The number of NOSPACE alarms disarmed after the database shrunk below the quota",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(defragmentations, lastDefragmentation, spaceAlarmActive, alarmDisarms)
+}
+
+// memberStatus is the database status of a single etcd member.
+type memberStatus struct {
+	member   *etcdserverpb.Member
+	endpoint string
+	dbSize   int64
+	inUse    int64
+	leader   bool
+}
+
+func (s memberStatus) fragmentedPercent() int64 {
+	if s.dbSize == 0 {
+		return 0
+	}
+	return (s.dbSize - s.inUse) * 100 / s.dbSize
+}
+
+// adjustQuotaToDBSize raises the configured quota if the existing database is already larger,
+// as etcd would otherwise immediately raise a NOSPACE alarm and refuse all writes.
+func (e *etcdCluster) adjustQuotaToDBSize(log *zap.SugaredLogger) {
+	if e.quotaBackendBytes <= 0 {
+		return
+	}
+
+	info, err := os.Stat(filepath.Join(e.dataDir, "member", "snap", "db"))
+	if err != nil {
+		return
+	}
+
+	if info.Size() >= e.quotaBackendBytes {
+		quota := info.Size() / 4 * 5
+		log.Warnw("database is larger than the configured quota, raising the quota", "size", info.Size(), "configured", e.quotaBackendBytes, "quota", quota)
+		e.quotaBackendBytes = quota
+	}
+}
+
+// maintainDatabase runs the defragmentation and alarm handling periodically. All members
+// run it, but only the leader acts, so that at most one member is defragmented at a time.
+func (e *etcdCluster) maintainDatabase(clusterClient ctrlruntimeclient.Client, log *zap.SugaredLogger) {
+	wait.Forever(func() {
+		leader, err := e.isLeader(log)
+		if err != nil {
+			log.Warnw("failed to determine if member is cluster leader", zap.Error(err))
+			return
+		}
+		if !leader {
+			return
+		}
+
+		if err := e.reconcileDatabase(clusterClient, log); err != nil {
+			log.Warnw("failed to maintain etcd database", zap.Error(err))
+		}
+	}, defragmentationInterval)
+}
+
+func (e *etcdCluster) reconcileDatabase(clusterClient ctrlruntimeclient.Client, log *zap.SugaredLogger) error {
+	cluster, err := getK8cCluster(clusterClient, strings.ReplaceAll(e.namespace, "cluster-", ""), log)
+	if err != nil {
+		return fmt.Errorf("failed to get cluster: %w", err)
+	}
+	settings := cluster.Spec.ComponentsOverride.Etcd.Defragmentation
+
+	statuses, err := e.memberStatuses(log)
+	if err != nil {
+		return err
+	}
+
+	alarms, err := e.spaceAlarms(log)
+	if err != nil {
+		return err
+	}
+
+	if settings == nil || !settings.Disabled {
+		// defragmenting blocks the member, so the leader goes last to avoid repeated elections
+		var candidates []memberStatus
+		for _, status := range statuses {
+			if needsDefragmentation(status, settings) {
+				candidates = append(candidates, status)
+			}
+		}
+
+		if len(candidates) > 0 {
+			e.setDatabaseCondition(clusterClient, cluster, corev1.ConditionFalse, kubermaticv1.ReasonEtcdDefragmentationInProgress,
+				fmt.Sprintf("defragmenting %d member(s)", len(candidates)), log)
+		}
+
+		for _, status := range orderForDefragmentation(candidates) {
+			if err := e.defragment(status, log); err != nil {
+				e.setDatabaseCondition(clusterClient, cluster, corev1.ConditionFalse, kubermaticv1.ReasonEtcdDefragmentationFailed,
+					fmt.Sprintf("failed to defragment member %s: %v", status.member.Name, err), log)
+				return err
+			}
+		}
+
+		if len(candidates) > 0 {
+			if statuses, err = e.memberStatuses(log); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(alarms) > 0 {
+		if belowAlarmThreshold(statuses, e.quotaBackendBytes) {
+			if err := e.disarmSpaceAlarms(alarms, log); err != nil {
+				return err
+			}
+		} else {
+			e.setDatabaseCondition(clusterClient, cluster, corev1.ConditionFalse, kubermaticv1.ReasonEtcdDatabaseSpaceExceeded,
+				"the etcd database exceeds its space quota, writes are rejected", log)
+			return nil
+		}
+	}
+
+	e.setDatabaseCondition(clusterClient, cluster, corev1.ConditionTrue, "", "", log)
+	return nil
+}
+
+func needsDefragmentation(status memberStatus, settings *kubermaticv1.EtcdDefragmentationSettings) bool {
+	threshold := int64(defaultFragmentationThresholdPercent)
+	minDBSize := defaultMinDBSize.Value()
+	if settings != nil {
+		if settings.FragmentationThresholdPercent != nil {
+			threshold = int64(*settings.FragmentationThresholdPercent)
+		}
+		if settings.MinDBSize != nil {
+			minDBSize = settings.MinDBSize.Value()
+		}
+	}
+
+	return status.dbSize >= minDBSize && status.fragmentedPercent() >= threshold
+}
+
+func orderForDefragmentation(statuses []memberStatus) []memberStatus {
+	ordered := make([]memberStatus, 0, len(statuses))
+	var leader *memberStatus
+	for i := range statuses {
+		if statuses[i].leader {
+			leader = &statuses[i]
+			continue
+		}
+		ordered = append(ordered, statuses[i])
+	}
+	if leader != nil {
+		ordered = append(ordered, *leader)
+	}
+	return ordered
+}
+
+func belowAlarmThreshold(statuses []memberStatus, quota int64) bool {
+	if quota <= 0 {
+		quota = defaultQuotaBackendBytes
+	}
+	for _, status := range statuses {
+		if float64(status.dbSize) >= float64(quota)*alarmDisarmThreshold {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *etcdCluster) memberStatuses(log *zap.SugaredLogger) ([]memberStatus, error) {
+	members, err := e.listMembers(log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members: %w", err)
+	}
+
+	var statuses []memberStatus
+	for _, member := range members {
+		if len(member.ClientURLs) == 0 {
+			// not started yet
+			continue
+		}
+		endpoint := member.ClientURLs[0]
+
+		status, err := e.endpointStatus(endpoint, log)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get status of member %s: %w", member.Name, err)
+		}
+
+		statuses = append(statuses, memberStatus{
+			member:   member,
+			endpoint: endpoint,
+			dbSize:   status.DbSize,
+			inUse:    status.DbSizeInUse,
+			leader:   status.Header.MemberId == status.Leader,
+		})
+	}
+
+	return statuses, nil
+}
+
+func (e *etcdCluster) endpointStatus(endpoint string, log *zap.SugaredLogger) (*client.StatusResponse, error) {
+	cli, err := e.getClientWithEndpoints([]string{endpoint})
+	if err != nil {
+		return nil, err
+	}
+	defer close(cli, log)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeoutMemberStatus)
+	defer cancel()
+
+	return cli.Status(ctx, endpoint)
+}
+
+func (e *etcdCluster) defragment(status memberStatus, log *zap.SugaredLogger) error {
+	name := status.member.Name
+
+	// never take a member out while another one is unavailable
+	healthy, err := e.isClusterHealthy(log)
+	if err != nil {
+		return err
+	}
+	if !healthy {
+		return fmt.Errorf("cluster is not healthy")
+	}
+
+	log.Infow("defragmenting member", "member", name, "dbSize", status.dbSize, "inUse", status.inUse)
+
+	err = func() error {
+		cli, err := e.getClientWithEndpoints([]string{status.endpoint})
+		if err != nil {
+			return err
+		}
+		defer close(cli, log)
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeoutDefragment)
+		defer cancel()
+
+		_, err = cli.Defragment(ctx, status.endpoint)
+		return err
+	}()
+	if err != nil {
+		defragmentations.WithLabelValues(name, "failure").Inc()
+		return err
+	}
+
+	if err := wait.Poll(5*time.Second, timeoutMemberHealthy, func() (bool, error) {
+		return e.isHealthyWithEndpoints([]string{status.endpoint}, log)
+	}); err != nil {
+		defragmentations.WithLabelValues(name, "failure").Inc()
+		return fmt.Errorf("member did not become healthy after defragmentation: %w", err)
+	}
+
+	defragmentations.WithLabelValues(name, "success").Inc()
+	lastDefragmentation.WithLabelValues(name).SetToCurrentTime()
+	log.Infow("defragmented member", "member", name)
+
+	return nil
+}
+
+func (e *etcdCluster) spaceAlarms(log *zap.SugaredLogger) ([]*etcdserverpb.AlarmMember, error) {
+	cli, err := e.getClusterClient()
+	if err != nil {
+		return nil, err
+	}
+	defer close(cli, log)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeoutMemberStatus)
+	defer cancel()
+
+	resp, err := cli.AlarmList(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alarms: %w", err)
+	}
+
+	var alarms []*etcdserverpb.AlarmMember
+	for _, alarm := range resp.Alarms {
+		if alarm.Alarm == etcdserverpb.AlarmType_NOSPACE {
+			alarms = append(alarms, alarm)
+		}
+	}
+
+	if len(alarms) > 0 {
+		spaceAlarmActive.Set(1)
+	} else {
+		spaceAlarmActive.Set(0)
+	}
+
+	return alarms, nil
+}
+
+func (e *etcdCluster) disarmSpaceAlarms(alarms []*etcdserverpb.AlarmMember, log *zap.SugaredLogger) error {
+	cli, err := e.getClusterClient()
+	if err != nil {
+		return err
+	}
+	defer close(cli, log)
+
+	for _, alarm := range alarms {
+		ctx, cancel := context.WithTimeout(context.Background(), timeoutMemberStatus)
+		_, err := cli.AlarmDisarm(ctx, (*client.AlarmMember)(alarm))
+		cancel()
+		if err != nil {
+			return fmt.Errorf("failed to disarm alarm of member %d: %w", alarm.MemberID, err)
+		}
+
+		alarmDisarms.Inc()
+		log.Infow("disarmed NOSPACE alarm", "member", alarm.MemberID)
+	}
+
+	spaceAlarmActive.Set(0)
+	return nil
+}
+
+func (e *etcdCluster) setDatabaseCondition(clusterClient ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster, status corev1.ConditionStatus,
+	reason, message string, log *zap.SugaredLogger) {
+	oldCluster := cluster.DeepCopy()
+	kubermaticv1helper.SetClusterCondition(cluster, kubermatic.NewDefaultVersions(), kubermaticv1.ClusterConditionEtcdDatabaseHealthy, status, reason, message)
+	if reflect.DeepEqual(oldCluster, cluster) {
+		return
+	}
+
+	patch := ctrlruntimeclient.MergeFromWithOptions(oldCluster, ctrlruntimeclient.MergeFromWithOptimisticLock{})
+	if err := clusterClient.Patch(context.Background(), cluster, patch); err != nil {
+		log.Warnw("failed to update etcd database condition", zap.Error(err))
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+)
+
+const mib = 1024 * 1024
+
+func TestNeedsDefragmentation(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   memberStatus
+		settings *kubermaticv1.EtcdDefragmentationSettings
+		expected bool
+	}{
+		{
+			name:     "fragmented database",
+			status:   memberStatus{dbSize: 400 * mib, inUse: 100 * mib},
+			expected: true,
+		},
+		{
+			name:     "compact database",
+			status:   memberStatus{dbSize: 400 * mib, inUse: 300 * mib},
+			expected: false,
+		},
+		{
+			name:     "small database",
+			status:   memberStatus{dbSize: 50 * mib, inUse: 1 * mib},
+			expected: false,
+		},
+		{
+			name:   "custom threshold and minimum size",
+			status: memberStatus{dbSize: 50 * mib, inUse: 40 * mib},
+			settings: &kubermaticv1.EtcdDefragmentationSettings{
+				FragmentationThresholdPercent: pointer.Int32Ptr(20),
+				MinDBSize:                     resource.NewQuantity(10*mib, resource.BinarySI),
+			},
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := needsDefragmentation(test.status, test.settings); result != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestOrderForDefragmentation(t *testing.T) {
+	statuses := []memberStatus{
+		{member: &etcdserverpb.Member{Name: "etcd-0"}, leader: true},
+		{member: &etcdserverpb.Member{Name: "etcd-1"}},
+		{member: &etcdserverpb.Member{Name: "etcd-2"}},
+	}
+
+	ordered := orderForDefragmentation(statuses)
+	if len(ordered) != len(statuses) {
+		t.Fatalf("expected %d members, got %d", len(statuses), len(ordered))
+	}
+	if last := ordered[len(ordered)-1]; last.member.Name != "etcd-0" {
+		t.Errorf("expected the leader to be defragmented last, got %s", last.member.Name)
+	}
+}
+
+func TestBelowAlarmThreshold(t *testing.T) {
+	statuses := []memberStatus{{dbSize: 700 * mib}, {dbSize: 900 * mib}}
+
+	if belowAlarmThreshold(statuses, 1024*mib) {
+		t.Error("expected members above 80% of the quota to keep the alarm armed")
+	}
+	if !belowAlarmThreshold(statuses, 2048*mib) {
+		t.Error("expected alarm to be disarmable when all members are below 80% of the quota")
+	}
+	if !belowAlarmThreshold(statuses, 0) {
+		t.Error("expected the default quota to be used if none is configured")
+	}
+}
@@ -28,7 +28,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/minio/minio-go"
 	"github.com/pkg/errors"
 	"go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
@@ -37,8 +36,12 @@ import (
 	"go.etcd.io/etcd/etcdutl/v3/snapshot"
 	"go.uber.org/zap"
 
+	"k8c.io/kubermatic/v2/pkg/backupstore"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/etcd/encryption"
+	"k8c.io/kubermatic/v2/pkg/etcd/revisions"
 	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+	"k8c.io/kubermatic/v2/pkg/metrics"
 	"k8c.io/kubermatic/v2/pkg/resources"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -75,6 +78,7 @@ type etcdCluster struct {
 	enableCorruptionCheck bool
 	initialState          string
 	usePeerTLSOnly        bool
+	quotaBackendBytes     int64
 }
 
 func main() {
@@ -97,6 +101,16 @@ func main() {
 		log.Panicw("failed to set cluster size", zap.Error(err))
 	}
 
+	k8cCluster, err := getK8cCluster(clusterClient, strings.ReplaceAll(e.namespace, "cluster-", ""), log)
+	if err != nil {
+		log.Panicw("failed to get user cluster", zap.Error(err))
+	}
+	if e.wasRemovedForScaleDown(k8cCluster) {
+		// joining again would undo the scale-down, so wait for the pod to be deleted
+		log.Info("member was removed from the etcd cluster to scale it down, waiting for the pod to be deleted")
+		select {}
+	}
+
 	if err := e.setInitialState(clusterClient, log); err != nil {
 		log.Panicw("failed to set initialState", zap.Error(err))
 	}
@@ -118,6 +132,8 @@ func main() {
 		}
 	}
 
+	e.adjustQuotaToDBSize(log)
+
 	// setup and start etcd command
 	etcdCmd, err := startEtcdCmd(e, log)
 	if err != nil {
@@ -163,12 +179,20 @@ func main() {
 			// refresh the cluster size so the etcd-launcher is aware of scaling operations
 			if err := e.setClusterSize(clusterClient); err != nil {
 				log.Warnw("failed to refresh cluster size", zap.Error(err))
-			} else if _, err := deleteUnwantedDeadMembers(e, log); err != nil {
+				return
+			}
+			if _, err := deleteUnwantedDeadMembers(e, log); err != nil {
 				log.Warnw("failed to remove dead members", zap.Error(err))
 			}
+			if err := e.reconcileMembership(clusterClient, log); err != nil {
+				log.Warnw("failed to reconcile members", zap.Error(err))
+			}
 		}, 30*time.Second)
 	}()
 
+	go e.maintainDatabase(clusterClient, log)
+	go metrics.ServeForever(metricsAddress, "/metrics")
+
 	if err = etcdCmd.Wait(); err != nil {
 		log.Panic(err)
 	}
@@ -392,6 +416,7 @@ func (e *etcdCluster) parseConfigFlags() error {
 	flag.StringVar(&e.etcdctlAPIVersion, "api-version", defaultEtcdctlAPIVersion, "etcdctl API version")
 	flag.StringVar(&e.token, "token", "", "etcd database token")
 	flag.BoolVar(&e.enableCorruptionCheck, "enable-corruption-check", false, "enable etcd experimental corruption check")
+	flag.Int64Var(&e.quotaBackendBytes, "quota-backend-bytes", 0, "etcd space quota in bytes, uses the etcd default if not set")
 	flag.Parse()
 
 	if e.namespace == "" {
@@ -413,6 +438,10 @@ func (e *etcdCluster) parseConfigFlags() error {
 		return errors.New("-token is not set")
 	}
 
+	if e.quotaBackendBytes < 0 {
+		return errors.New("-quota-backend-bytes must not be negative")
+	}
+
 	e.dataDir = fmt.Sprintf("/var/run/etcd/pod_%s/", e.podName)
 
 	return nil
@@ -459,6 +488,10 @@ func etcdCmd(config *etcdCluster) []string {
 			"--experimental-corrupt-check-time=10m",
 		}...)
 	}
+
+	if config.quotaBackendBytes > 0 {
+		cmd = append(cmd, fmt.Sprintf("--quota-backend-bytes=%d", config.quotaBackendBytes))
+	}
 	return cmd
 }
 
@@ -667,16 +700,45 @@ func (e *etcdCluster) restoreDatadirFromBackupIfNeeded(ctx context.Context, k8cC
 
 	log.Infow("restoring datadir from backup", "backup-name", activeRestore.Spec.BackupName)
 
-	s3Client, bucketName, err := resources.GetEtcdRestoreS3Client(ctx, activeRestore, false, client, k8cCluster, nil)
+	store, err := resources.GetEtcdRestoreBackupStore(ctx, activeRestore, false, client, k8cCluster, nil)
 	if err != nil {
-		return fmt.Errorf("failed to get s3 client: %w", err)
+		return fmt.Errorf("failed to get backup store client: %w", err)
 	}
 
-	objectName := fmt.Sprintf("%s-%s", k8cCluster.GetName(), activeRestore.Spec.BackupName)
+	objectName := fmt.Sprintf("%s-%s", activeRestore.BackupClusterName(), activeRestore.Spec.BackupName)
 	downloadedSnapshotFile := fmt.Sprintf("/tmp/%s", objectName)
 
-	if err := s3Client.FGetObject(bucketName, objectName, downloadedSnapshotFile, minio.GetObjectOptions{}); err != nil {
-		return fmt.Errorf("failed to download backup (%s/%s): %w", bucketName, objectName, err)
+	if err := backupstore.DownloadFile(ctx, store, objectName, downloadedSnapshotFile); err != nil {
+		return fmt.Errorf("failed to download backup %s: %w", objectName, err)
+	}
+
+	keys, err := resources.GetEtcdRestoreEncryptionKeys(ctx, activeRestore, client, k8cCluster)
+	if err != nil {
+		return fmt.Errorf("failed to get backup encryption keys: %w", err)
+	}
+
+	encrypted, err := encryption.DecryptFileInPlace(downloadedSnapshotFile, keys)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt backup %s: %w", objectName, err)
+	}
+	if encrypted {
+		log.Info("decrypted backup")
+	}
+
+	if activeRestore.IsPointInTimeRestore() {
+		target := revisions.Target{Revision: activeRestore.Spec.RestoreToRevision}
+		if activeRestore.Spec.RestoreToTime != nil {
+			restoreToTime := activeRestore.Spec.RestoreToTime.Time
+			target.Time = &restoreToTime
+		}
+
+		log.Infow("replaying shipped revisions on top of the backup", "revision", target.Revision, "time", target.Time)
+
+		replayedSnapshotFile := downloadedSnapshotFile + "-pitr"
+		if err := replayOntoSnapshot(ctx, log, store, activeRestore.BackupClusterName(), keys, downloadedSnapshotFile, replayedSnapshotFile, target); err != nil {
+			return fmt.Errorf("failed to replay revisions: %w", err)
+		}
+		downloadedSnapshotFile = replayedSnapshotFile
 	}
 
 	if err := os.RemoveAll(e.dataDir); err != nil {
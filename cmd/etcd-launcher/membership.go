@@ -0,0 +1,283 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1/helper"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const timeoutMoveLeader = time.Second * 30
+
+// reconcileMembership removes members from the etcd cluster before their pods are deleted on scale-down
+// and handles requests to replace single members. Only the leader acts, so that there is at most one
+// membership change in flight.
+func (e *etcdCluster) reconcileMembership(clusterClient ctrlruntimeclient.Client, log *zap.SugaredLogger) error {
+	leader, err := e.isLeader(log)
+	if err != nil {
+		return fmt.Errorf("failed to determine if member is cluster leader: %w", err)
+	}
+	if !leader {
+		return nil
+	}
+
+	cluster, err := getK8cCluster(clusterClient, strings.ReplaceAll(e.namespace, "cluster-", ""), log)
+	if err != nil {
+		return fmt.Errorf("failed to get cluster: %w", err)
+	}
+
+	members, err := e.listMembers(log)
+	if err != nil {
+		return fmt.Errorf("failed to list members: %w", err)
+	}
+
+	if name := cluster.Annotations[resources.EtcdReplaceMemberAnnotation]; name != "" {
+		return e.replaceMember(clusterClient, cluster, members, name, log)
+	}
+
+	desiredSize := int(cluster.Spec.ComponentsOverride.Etcd.GetClusterSize())
+	if desiredSize < e.clusterSize {
+		return e.scaleDown(clusterClient, cluster, members, log)
+	}
+
+	if desiredSize == e.clusterSize && len(members) == e.clusterSize && e.membersHealthy(members, "", log) {
+		e.updateMembershipStatus(clusterClient, cluster, corev1.ConditionTrue, "", "", func(c *kubermaticv1.Cluster) {
+			delete(c.Annotations, resources.EtcdRemovedMemberAnnotation)
+		}, log)
+	}
+
+	return nil
+}
+
+// scaleDown removes the member of the pod with the highest ordinal, which the statefulset deletes first.
+// The statefulset is only scaled down once the member is gone, see EtcdRemovedMemberAnnotation.
+func (e *etcdCluster) scaleDown(clusterClient ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster, members []*etcdserverpb.Member, log *zap.SugaredLogger) error {
+	target := fmt.Sprintf("%s-%d", resources.EtcdStatefulSetName, e.clusterSize-1)
+
+	member := e.findMember(members, target)
+	if member == nil {
+		// already removed, waiting for the statefulset to be scaled down
+		e.recordRemovedMember(clusterClient, cluster, target, log)
+		return nil
+	}
+
+	// a previous member removal is still in progress
+	if len(members) != e.clusterSize {
+		return nil
+	}
+
+	if !e.membersHealthy(members, "", log) {
+		log.Infow("waiting for all members to be healthy before scaling down", "member", target)
+		return nil
+	}
+
+	if target == e.podName {
+		e.updateMembershipStatus(clusterClient, cluster, corev1.ConditionFalse, kubermaticv1.ReasonEtcdScalingDown,
+			fmt.Sprintf("moving leadership away from %s", target), nil, log)
+		return e.moveLeadership(members, log)
+	}
+
+	e.updateMembershipStatus(clusterClient, cluster, corev1.ConditionFalse, kubermaticv1.ReasonEtcdScalingDown,
+		fmt.Sprintf("removing member %s", target), nil, log)
+
+	if err := e.removeMember(member, log); err != nil {
+		return fmt.Errorf("failed to remove member %s: %w", target, err)
+	}
+
+	e.recordRemovedMember(clusterClient, cluster, target, log)
+	return nil
+}
+
+// replaceMember removes the named member from the etcd cluster. Its pod then fails, restarts and joins
+// the cluster again as a fresh member with an empty data directory.
+func (e *etcdCluster) replaceMember(clusterClient ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster, members []*etcdserverpb.Member, name string, log *zap.SugaredLogger) error {
+	if !contains(peerHostsList(e.clusterSize, e.namespace), fmt.Sprintf("%s.etcd.%s.svc.cluster.local", name, e.namespace)) {
+		e.updateMembershipStatus(clusterClient, cluster, corev1.ConditionFalse, kubermaticv1.ReasonEtcdMemberReplacementFailed,
+			fmt.Sprintf("%s is not a member of the etcd cluster", name), removeReplaceRequest, log)
+		return nil
+	}
+
+	member := e.findMember(members, name)
+	if member == nil {
+		// the member is already being replaced and joins the cluster on its own
+		e.updateMembershipStatus(clusterClient, cluster, corev1.ConditionFalse, kubermaticv1.ReasonEtcdReplacingMember,
+			fmt.Sprintf("waiting for %s to join the etcd cluster", name), removeReplaceRequest, log)
+		return nil
+	}
+
+	// the member to be replaced might be the broken one, but losing any other member would break quorum
+	if !e.membersHealthy(members, name, log) {
+		log.Infow("waiting for all other members to be healthy before replacing member", "member", name)
+		return nil
+	}
+
+	if name == e.podName {
+		e.updateMembershipStatus(clusterClient, cluster, corev1.ConditionFalse, kubermaticv1.ReasonEtcdReplacingMember,
+			fmt.Sprintf("moving leadership away from %s", name), nil, log)
+		return e.moveLeadership(members, log)
+	}
+
+	// the request is dropped before acting on it, so that a failed status update can't remove the re-joined member again
+	if !e.updateMembershipStatus(clusterClient, cluster, corev1.ConditionFalse, kubermaticv1.ReasonEtcdReplacingMember,
+		fmt.Sprintf("removing member %s, it re-joins the etcd cluster with an empty data directory", name), removeReplaceRequest, log) {
+		return nil
+	}
+
+	if err := e.removeMember(member, log); err != nil {
+		e.updateMembershipStatus(clusterClient, cluster, corev1.ConditionFalse, kubermaticv1.ReasonEtcdMemberReplacementFailed,
+			fmt.Sprintf("failed to remove member %s: %v", name, err), nil, log)
+		return err
+	}
+
+	return nil
+}
+
+func removeReplaceRequest(cluster *kubermaticv1.Cluster) {
+	delete(cluster.Annotations, resources.EtcdReplaceMemberAnnotation)
+}
+
+func (e *etcdCluster) recordRemovedMember(clusterClient ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster, name string, log *zap.SugaredLogger) {
+	e.updateMembershipStatus(clusterClient, cluster, corev1.ConditionFalse, kubermaticv1.ReasonEtcdMemberRemoved,
+		fmt.Sprintf("member %s was removed from the etcd cluster, waiting for its pod to be deleted", name), func(c *kubermaticv1.Cluster) {
+			if c.Annotations == nil {
+				c.Annotations = map[string]string{}
+			}
+			c.Annotations[resources.EtcdRemovedMemberAnnotation] = name
+		}, log)
+}
+
+// findMember returns the member of the named pod. Members that did not start yet have no name,
+// so they are matched by their peer URL.
+func (e *etcdCluster) findMember(members []*etcdserverpb.Member, name string) *etcdserverpb.Member {
+	for _, member := range members {
+		if member.Name == name {
+			return member
+		}
+		for _, peerURL := range member.PeerURLs {
+			u, err := url.Parse(peerURL)
+			if err == nil && u.Hostname() == fmt.Sprintf("%s.etcd.%s.svc.cluster.local", name, e.namespace) {
+				return member
+			}
+		}
+	}
+	return nil
+}
+
+// membersHealthy checks every member except the given one on its own endpoint.
+func (e *etcdCluster) membersHealthy(members []*etcdserverpb.Member, except string, log *zap.SugaredLogger) bool {
+	for _, member := range members {
+		if member.Name == except && except != "" {
+			continue
+		}
+		if len(member.ClientURLs) == 0 {
+			return false
+		}
+		// we use the cluster FQDN endpoint url here, the certificates don't include Pod IP addresses.
+		healthy, err := e.isHealthyWithEndpoints(member.ClientURLs[len(member.ClientURLs)-1:], log)
+		if err != nil || !healthy {
+			return false
+		}
+	}
+	return true
+}
+
+// moveLeadership hands leadership to another started member, it must be called on the leader.
+func (e *etcdCluster) moveLeadership(members []*etcdserverpb.Member, log *zap.SugaredLogger) error {
+	var transferee *etcdserverpb.Member
+	for _, member := range members {
+		if member.Name != e.podName && len(member.ClientURLs) > 0 {
+			transferee = member
+			break
+		}
+	}
+	if transferee == nil {
+		return fmt.Errorf("no member to move leadership to")
+	}
+
+	client, err := e.getLocalClient()
+	if err != nil {
+		return err
+	}
+	defer close(client, log)
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), timeoutMoveLeader)
+	defer cancelFunc()
+
+	log.Infow("moving leadership", "member", transferee.Name)
+	if _, err := client.MoveLeader(ctx, transferee.ID); err != nil {
+		return fmt.Errorf("failed to move leadership to %s: %w", transferee.Name, err)
+	}
+	return nil
+}
+
+func (e *etcdCluster) removeMember(member *etcdserverpb.Member, log *zap.SugaredLogger) error {
+	client, err := e.getClusterClient()
+	if err != nil {
+		return fmt.Errorf("can't find cluster client: %w", err)
+	}
+	defer close(client, log)
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), timeoutRemoveMember)
+	defer cancelFunc()
+
+	log.Infow("removing member from cluster", "member-name", member.Name)
+	_, err = client.MemberRemove(ctx, member.ID)
+	return err
+}
+
+// updateMembershipStatus sets the EtcdMembershipReconciled condition and applies the given
+// modification to the cluster. It returns whether the cluster is up to date.
+func (e *etcdCluster) updateMembershipStatus(clusterClient ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster, status corev1.ConditionStatus,
+	reason, message string, modify func(*kubermaticv1.Cluster), log *zap.SugaredLogger) bool {
+	oldCluster := cluster.DeepCopy()
+	kubermaticv1helper.SetClusterCondition(cluster, kubermatic.NewDefaultVersions(), kubermaticv1.ClusterConditionEtcdMembershipReconciled, status, reason, message)
+	if modify != nil {
+		modify(cluster)
+	}
+	if reflect.DeepEqual(oldCluster, cluster) {
+		return true
+	}
+
+	patch := ctrlruntimeclient.MergeFromWithOptions(oldCluster, ctrlruntimeclient.MergeFromWithOptimisticLock{})
+	if err := clusterClient.Patch(context.Background(), cluster, patch); err != nil {
+		log.Warnw("failed to update etcd membership condition", zap.Error(err))
+		return false
+	}
+	return true
+}
+
+// wasRemovedForScaleDown returns true if the member of this pod was removed to scale the etcd cluster
+// down. The pod must not join the cluster again, it is deleted by the statefulset shortly.
+func (e *etcdCluster) wasRemovedForScaleDown(cluster *kubermaticv1.Cluster) bool {
+	return cluster.Annotations[resources.EtcdRemovedMemberAnnotation] == e.podName &&
+		int(cluster.Spec.ComponentsOverride.Etcd.GetClusterSize()) < e.clusterSize
+}
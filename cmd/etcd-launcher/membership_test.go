@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
+
+	"k8s.io/utils/pointer"
+)
+
+func TestFindMember(t *testing.T) {
+	e := &etcdCluster{namespace: "cluster-abcd"}
+	members := []*etcdserverpb.Member{
+		{ID: 1, Name: "etcd-0", PeerURLs: []string{"https://etcd-0.etcd.cluster-abcd.svc.cluster.local:2381"}},
+		// not started yet
+		{ID: 2, PeerURLs: []string{"https://etcd-1.etcd.cluster-abcd.svc.cluster.local:2381"}},
+	}
+
+	if member := e.findMember(members, "etcd-0"); member == nil || member.ID != 1 {
+		t.Errorf("expected to find etcd-0 by name, got %v", member)
+	}
+	if member := e.findMember(members, "etcd-1"); member == nil || member.ID != 2 {
+		t.Errorf("expected to find etcd-1 by peer URL, got %v", member)
+	}
+	if member := e.findMember(members, "etcd-2"); member != nil {
+		t.Errorf("expected etcd-2 not to be found, got %v", member)
+	}
+}
+
+func TestWasRemovedForScaleDown(t *testing.T) {
+	cluster := &kubermaticv1.Cluster{}
+	cluster.Annotations = map[string]string{resources.EtcdRemovedMemberAnnotation: "etcd-4"}
+	cluster.Spec.ComponentsOverride.Etcd.ClusterSize = pointer.Int32Ptr(3)
+
+	if e := (&etcdCluster{podName: "etcd-4", clusterSize: 5}); !e.wasRemovedForScaleDown(cluster) {
+		t.Error("expected the removed member not to join the cluster again")
+	}
+	if e := (&etcdCluster{podName: "etcd-3", clusterSize: 5}); e.wasRemovedForScaleDown(cluster) {
+		t.Error("expected other members to start normally")
+	}
+	if e := (&etcdCluster{podName: "etcd-4", clusterSize: 3}); e.wasRemovedForScaleDown(cluster) {
+		t.Error("expected members to start normally once the statefulset was scaled down")
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.etcd.io/etcd/etcdutl/v3/snapshot"
+	"go.etcd.io/etcd/server/v3/embed"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3client"
+	"go.uber.org/zap"
+
+	"k8c.io/kubermatic/v2/pkg/backupstore"
+	"k8c.io/kubermatic/v2/pkg/etcd/encryption"
+	"k8c.io/kubermatic/v2/pkg/etcd/revisions"
+)
+
+const (
+	replayMemberName   = "pitr"
+	replayClientURL    = "http://127.0.0.1:12379"
+	replayPeerURL      = "http://127.0.0.1:12380"
+	replayStartTimeout = 1 * time.Minute
+)
+
+// replayOntoSnapshot restores the snapshot at snapshotPath into a temporary,
+// single-member etcd, replays the shipped revisions up to the target on top of
+// it and writes a new snapshot to outputPath. The resulting snapshot can then
+// be restored like any regular backup. Encrypted segments are decrypted using keys.
+func replayOntoSnapshot(ctx context.Context, log *zap.SugaredLogger, store backupstore.Store, clusterName string, keys encryption.KeyRing,
+	snapshotPath, outputPath string, target revisions.Target) error {
+	status, err := snapshot.NewV3(log.Desugar()).Status(snapshotPath)
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot status: %w", err)
+	}
+
+	segments, err := revisions.ListSegments(ctx, store, clusterName)
+	if err != nil {
+		return fmt.Errorf("failed to list revision segments: %w", err)
+	}
+	selected, err := revisions.SelectSegments(segments, status.Revision, target)
+	if err != nil {
+		return err
+	}
+
+	var records []revisions.Record
+	for _, segment := range selected {
+		segmentRecords, err := revisions.DownloadSegment(ctx, store, clusterName, segment, keys)
+		if err != nil {
+			return fmt.Errorf("failed to download segment %s: %w", segment.ObjectName(clusterName), err)
+		}
+		records = append(records, segmentRecords...)
+	}
+
+	tmpDir, err := ioutil.TempDir("", "etcd-pitr")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	dataDir := filepath.Join(tmpDir, "data")
+	if err := snapshot.NewV3(log.Desugar()).Restore(snapshot.RestoreConfig{
+		SnapshotPath:        snapshotPath,
+		Name:                replayMemberName,
+		OutputDataDir:       dataDir,
+		OutputWALDir:        filepath.Join(dataDir, "member", "wal"),
+		PeerURLs:            []string{replayPeerURL},
+		InitialCluster:      fmt.Sprintf("%s=%s", replayMemberName, replayPeerURL),
+		InitialClusterToken: replayMemberName,
+	}); err != nil {
+		return fmt.Errorf("failed to restore snapshot: %w", err)
+	}
+
+	server, err := startReplayServer(dataDir)
+	if err != nil {
+		return err
+	}
+	defer server.Close()
+
+	etcdClient := v3client.New(server.Server)
+	defer etcdClient.Close()
+
+	lastRevision, _, err := revisions.Replay(ctx, etcdClient, records, status.Revision, target)
+	if err != nil {
+		return err
+	}
+	log.Infow("Replayed shipped revisions", "snapshot-revision", status.Revision, "last-revision", lastRevision)
+
+	reader, err := etcdClient.Snapshot(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	defer reader.Close()
+
+	output, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", outputPath, err)
+	}
+	defer output.Close()
+
+	if _, err := io.Copy(output, reader); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	return output.Sync()
+}
+
+func startReplayServer(dataDir string) (*embed.Etcd, error) {
+	clientURL, err := url.Parse(replayClientURL)
+	if err != nil {
+		return nil, err
+	}
+	peerURL, err := url.Parse(replayPeerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := embed.NewConfig()
+	cfg.Name = replayMemberName
+	cfg.Dir = dataDir
+	cfg.LCUrls = []url.URL{*clientURL}
+	cfg.ACUrls = []url.URL{*clientURL}
+	cfg.LPUrls = []url.URL{*peerURL}
+	cfg.APUrls = []url.URL{*peerURL}
+	cfg.InitialCluster = cfg.InitialClusterFromName(replayMemberName)
+	cfg.LogLevel = "error"
+
+	server, err := embed.StartEtcd(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start etcd: %w", err)
+	}
+
+	select {
+	case <-server.Server.ReadyNotify():
+		return server, nil
+	case err := <-server.Err():
+		server.Close()
+		return nil, fmt.Errorf("etcd failed to start: %w", err)
+	case <-time.After(replayStartTimeout):
+		server.Server.Stop()
+		server.Close()
+		return nil, fmt.Errorf("etcd did not become ready within %v", replayStartTimeout)
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.etcd.io/etcd/client/pkg/v3/transport"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+
+	"k8c.io/kubermatic/v2/pkg/backupstore"
+	"k8c.io/kubermatic/v2/pkg/etcd/encryption"
+	"k8c.io/kubermatic/v2/pkg/etcd/revisions"
+	"k8c.io/kubermatic/v2/pkg/log"
+
+	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
+)
+
+func main() {
+	logOpts := log.NewDefaultOptions()
+	logOpts.AddFlags(flag.CommandLine)
+	storeOpts := backupstore.NewDefaultOptions()
+	storeOpts.AddFlags(flag.CommandLine)
+
+	clusterName := flag.String("cluster", "", "Name of the user cluster whose etcd revisions are shipped")
+	etcdEndpoints := flag.String("etcd-endpoints", "", "Comma separated list of etcd client endpoints")
+	etcdCAFile := flag.String("etcd-ca-file", "", "CA certificate used to verify the etcd server certificates")
+	etcdCertFile := flag.String("etcd-cert-file", "", "Client certificate used to authenticate against etcd")
+	etcdKeyFile := flag.String("etcd-key-file", "", "Client key used to authenticate against etcd")
+	flushInterval := flag.Duration("flush-interval", time.Minute, "Maximum amount of time changes are buffered before being uploaded")
+	retention := flag.Duration("retention", 7*24*time.Hour, "Amount of time shipped revisions are kept (0 to keep forever)")
+	encryptionKeysDir := flag.String("encryption-keys", "", "Directory containing the base64 encoded encryption keys (if not given, revisions are uploaded unencrypted)")
+	encryptionKeyID := flag.String("encryption-key-id", "", "ID of the key used to encrypt revisions")
+	flag.Parse()
+
+	rawLog := log.New(logOpts.Debug, logOpts.Format)
+	logger := rawLog.Sugar()
+	defer func() {
+		if err := logger.Sync(); err != nil {
+			fmt.Println(err)
+		}
+	}()
+
+	if *clusterName == "" || *etcdEndpoints == "" {
+		logger.Fatal("Both 'cluster' and 'etcd-endpoints' must be set!")
+	}
+	if *flushInterval <= 0 {
+		logger.Fatal("'flush-interval' must be positive")
+	}
+
+	logger = logger.With("cluster", *clusterName, "bucket", storeOpts.Bucket)
+
+	store, err := storeOpts.Store(context.Background())
+	if err != nil {
+		logger.Fatalw("Failed to create backup store client", zap.Error(err))
+	}
+
+	var encryptionKey []byte
+	if *encryptionKeysDir != "" {
+		keys, err := encryption.LoadKeyRing(*encryptionKeysDir)
+		if err != nil {
+			logger.Fatalw("Failed to load encryption keys", zap.Error(err))
+		}
+		var ok bool
+		if encryptionKey, ok = keys[*encryptionKeyID]; !ok {
+			logger.Fatalw("Encryption key not found", "key", *encryptionKeyID)
+		}
+	}
+
+	tlsInfo := transport.TLSInfo{
+		CertFile:      *etcdCertFile,
+		KeyFile:       *etcdKeyFile,
+		TrustedCAFile: *etcdCAFile,
+	}
+	tlsConfig, err := tlsInfo.ClientConfig()
+	if err != nil {
+		logger.Fatalw("Failed to create etcd TLS config", zap.Error(err))
+	}
+
+	etcdClient, err := clientv3.New(clientv3.Config{
+		Endpoints:   strings.Split(*etcdEndpoints, ","),
+		DialTimeout: 5 * time.Second,
+		TLS:         tlsConfig,
+	})
+	if err != nil {
+		logger.Fatalw("Failed to create etcd client", zap.Error(err))
+	}
+	defer etcdClient.Close()
+
+	shipper := &revisions.Shipper{
+		Client:          etcdClient,
+		Store:           store,
+		ClusterName:     *clusterName,
+		FlushInterval:   *flushInterval,
+		Retention:       *retention,
+		EncryptionKeyID: *encryptionKeyID,
+		EncryptionKey:   encryptionKey,
+		Log:             logger,
+	}
+
+	logger.Info("Starting to ship etcd revisions")
+	if err := shipper.Run(signals.SetupSignalHandler()); err != nil {
+		logger.Fatalw("Failed to ship revisions", zap.Error(err))
+	}
+	logger.Info("Shutting down")
+}
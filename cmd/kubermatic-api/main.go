@@ -66,6 +66,7 @@ import (
 	"k8c.io/kubermatic/v2/pkg/handler/auth"
 	"k8c.io/kubermatic/v2/pkg/handler/v1/common"
 	v2 "k8c.io/kubermatic/v2/pkg/handler/v2"
+	"k8c.io/kubermatic/v2/pkg/handler/v2/preset"
 	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
 	metricspkg "k8c.io/kubermatic/v2/pkg/metrics"
 	"k8c.io/kubermatic/v2/pkg/pprof"
@@ -165,6 +166,9 @@ func main() {
 	log.Fatalw("failed to start API server", "error", http.ListenAndServe(options.listenAddress, handlers.CombinedLoggingHandler(os.Stdout, apiHandler)))
 }
 
+// leasedCredentialsRefreshInterval is how often the API checks whether leased preset credentials have been renewed.
+const leasedCredentialsRefreshInterval = time.Minute
+
 func createInitProviders(ctx context.Context, options serverRunOptions, masterCfg *rest.Config, mgr manager.Manager) (providers, error) {
 	// create other providers
 	kubeMasterClient := kubernetes.NewForConfigOrDie(masterCfg)
@@ -214,10 +218,19 @@ func createInitProviders(ctx context.Context, options serverRunOptions, masterCf
 	seedClientGetter := provider.SeedClientGetterFactory(seedKubeconfigGetter)
 	clusterProviderGetter := clusterProviderFactory(mgr.GetRESTMapper(), seedKubeconfigGetter, seedClientGetter, options)
 
-	presetProvider, err := kubernetesprovider.NewPresetProvider(ctx, client, options.presetsFile, options.dynamicPresets)
+	secretStore, err := options.secretStore.Store()
+	if err != nil {
+		return providers{}, fmt.Errorf("failed to create secret store: %w", err)
+	}
+	presetProvider, err := kubernetesprovider.NewPresetProvider(ctx, client, options.presetsFile, options.dynamicPresets, secretStore)
 	if err != nil {
 		return providers{}, err
 	}
+	// leased preset credentials have to be copied to the clusters using them before their lease ends
+	if secretStore != nil {
+		refresher := preset.NewLeasedCredentialsRefresher(presetProvider, seedsGetter, clusterProviderGetter, options.caBundle.CertPool(), kubermaticlog.Logger)
+		go refresher.Start(ctx, leasedCredentialsRefreshInterval)
+	}
 	admissionPluginProvider := kubernetesprovider.NewAdmissionPluginsProvider(ctx, client)
 	// Warm up the restMapper cache. Log but ignore errors encountered here, maybe there are stale seeds
 	go func() {
@@ -319,6 +332,11 @@ func createInitProviders(ctx context.Context, options serverRunOptions, masterCf
 
 	privilegedMLAAdminSettingProviderGetter := kubernetesprovider.PrivilegedMLAAdminSettingProviderFactory(mgr.GetRESTMapper(), seedKubeconfigGetter)
 
+	privilegedClusterMigrationProvider, err := kubernetesprovider.NewClusterMigrationPrivilegedProvider(mgr.GetClient())
+	if err != nil {
+		return providers{}, fmt.Errorf("failed to create cluster migration provider due to %v", err)
+	}
+
 	seedProvider := kubernetesprovider.NewSeedProvider(mgr.GetClient())
 
 	settingsWatcher, err := kuberneteswatcher.NewSettingsWatcher(settingsProvider)
@@ -377,6 +395,7 @@ func createInitProviders(ctx context.Context, options serverRunOptions, masterCf
 		etcdRestoreProjectProviderGetter:        etcdRestoreProjectProviderGetter,
 		backupCredentialsProviderGetter:         backupCredentialsProviderGetter,
 		privilegedMLAAdminSettingProviderGetter: privilegedMLAAdminSettingProviderGetter,
+		privilegedClusterMigrationProvider:      privilegedClusterMigrationProvider,
 		seedProvider:                            seedProvider,
 	}, nil
 }
@@ -496,6 +515,7 @@ func createAPIHandler(options serverRunOptions, prov providers, oidcIssuerVerifi
 		EtcdRestoreProjectProviderGetter:        prov.etcdRestoreProjectProviderGetter,
 		BackupCredentialsProviderGetter:         prov.backupCredentialsProviderGetter,
 		PrivilegedMLAAdminSettingProviderGetter: prov.privilegedMLAAdminSettingProviderGetter,
+		PrivilegedClusterMigrationProvider:      prov.privilegedClusterMigrationProvider,
 		SeedProvider:                            prov.seedProvider,
 		Versions:                                options.versions,
 		CABundle:                                options.caBundle.CertPool(),
@@ -32,6 +32,7 @@ import (
 	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
 	"k8c.io/kubermatic/v2/pkg/provider"
 	"k8c.io/kubermatic/v2/pkg/resources/certificates"
+	"k8c.io/kubermatic/v2/pkg/secretstore"
 	"k8c.io/kubermatic/v2/pkg/serviceaccount"
 	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
 	"k8c.io/kubermatic/v2/pkg/watcher"
@@ -50,6 +51,7 @@ type serverRunOptions struct {
 	namespace      string
 	log            kubermaticlog.Options
 	caBundle       *certificates.CABundle
+	secretStore    secretstore.Options
 
 	// for development purposes, a local configuration file
 	// can be used to provide the KubermaticConfiguration
@@ -84,6 +86,9 @@ func newServerRunOptions() (serverRunOptions, error) {
 	s.log = kubermaticlog.NewDefaultOptions()
 	s.log.AddFlags(flag.CommandLine)
 
+	s.secretStore = secretstore.NewDefaultOptions()
+	s.secretStore.AddFlags(flag.CommandLine)
+
 	flag.StringVar(&s.listenAddress, "address", ":8080", "The address to listen on")
 	flag.StringVar(&s.internalAddr, "internal-address", "127.0.0.1:8085", "The address on which the internal handler should be exposed")
 	flag.StringVar(&s.prometheusURL, "prometheus-url", "http://prometheus.monitoring.svc.local:web", "The URL on which this API can talk to Prometheus")
@@ -190,6 +195,7 @@ type providers struct {
 	etcdRestoreProjectProviderGetter        provider.EtcdRestoreProjectProviderGetter
 	backupCredentialsProviderGetter         provider.BackupCredentialsProviderGetter
 	privilegedMLAAdminSettingProviderGetter provider.PrivilegedMLAAdminSettingProviderGetter
+	privilegedClusterMigrationProvider      provider.PrivilegedClusterMigrationProvider
 	seedProvider                            provider.SeedProvider
 }
 
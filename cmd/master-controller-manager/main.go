@@ -24,6 +24,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 
+	"k8c.io/kubermatic/v2/pkg/collectors"
 	operatorv1alpha1 "k8c.io/kubermatic/v2/pkg/crd/operator/v1alpha1"
 	"k8c.io/kubermatic/v2/pkg/features"
 	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
@@ -34,6 +35,7 @@ import (
 	"k8c.io/kubermatic/v2/pkg/util/cli"
 	"k8c.io/kubermatic/v2/pkg/util/workerlabel"
 	"k8c.io/kubermatic/v2/pkg/webhook"
+	presetvalidation "k8c.io/kubermatic/v2/pkg/webhook/preset/validation"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -179,6 +181,9 @@ func main() {
 			log.Fatalw("failed to build Seed validation handler", zap.Error(err))
 		}
 		h.SetupWebhookWithManager(mgr)
+
+		// Register Preset validation handler
+		presetvalidation.NewAdmissionHandler().SetupWebhookWithManager(mgr)
 	} else {
 		log.Info("the validatingAdmissionWebhook server cannot be started because certificate was not configured")
 	}
@@ -187,6 +192,11 @@ func main() {
 		log.Fatalw("could not create all controllers", zap.Error(err))
 	}
 
+	// Use the API reader as the cache-backed reader will only contain data when we are leader
+	// and return errors otherwise.
+	log.Debug("Starting external clusters collector")
+	collectors.MustRegisterExternalClusterCollector(prometheus.DefaultRegisterer, mgr.GetAPIReader())
+
 	if err := mgr.Add(metricserver.New(runOpts.internalAddr)); err != nil {
 		log.Fatalw("failed to add metrics server", zap.Error(err))
 	}
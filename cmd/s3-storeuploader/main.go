@@ -24,6 +24,8 @@ import (
 	"github.com/urfave/cli"
 	"go.uber.org/zap"
 
+	"k8c.io/kubermatic/v2/pkg/etcd/encryption"
+	"k8c.io/kubermatic/v2/pkg/etcd/retention"
 	"k8c.io/kubermatic/v2/pkg/log"
 	"k8c.io/kubermatic/v2/pkg/resources/certificates"
 	"k8c.io/kubermatic/v2/pkg/storeuploader"
@@ -78,6 +80,15 @@ func main() {
 		Name:  "ca-bundle",
 		Usage: "Filename of the CA bundle to use (if not given, default system certificates are used)",
 	}
+	encryptionKeysFlag := cli.StringFlag{
+		Name:  "encryption-keys",
+		Usage: "Directory containing the base64 encoded encryption keys (if not given, files are uploaded unencrypted)",
+	}
+	encryptionKeyIDFlag := cli.StringFlag{
+		Name:   "encryption-key-id",
+		EnvVar: "ENCRYPTION_KEY_ID",
+		Usage:  "ID of the key used to encrypt files",
+	}
 	createBucketFlag := cli.BoolFlag{
 		Name:  "create-bucket",
 		Usage: "creates the bucket if it does not exist yet",
@@ -87,6 +98,22 @@ func main() {
 		Value: 20,
 		Usage: "Maximum number of revisions of the file to keep in S3. Older ones will be deleted",
 	}
+	keepHourlyFlag := cli.IntFlag{
+		Name:  "keep-hourly",
+		Usage: "Additionally keep the newest revision of each of the given number of most recent hours",
+	}
+	keepDailyFlag := cli.IntFlag{
+		Name:  "keep-daily",
+		Usage: "Additionally keep the newest revision of each of the given number of most recent days",
+	}
+	keepWeeklyFlag := cli.IntFlag{
+		Name:  "keep-weekly",
+		Usage: "Additionally keep the newest revision of each of the given number of most recent weeks",
+	}
+	keepMonthlyFlag := cli.IntFlag{
+		Name:  "keep-monthly",
+		Usage: "Additionally keep the newest revision of each of the given number of most recent months",
+	}
 
 	logDebugFlag := cli.BoolFlag{
 		Name:  "log-debug",
@@ -120,11 +147,13 @@ func main() {
 				prefixFlag,
 				fileFlag,
 				createBucketFlag,
+				encryptionKeysFlag,
+				encryptionKeyIDFlag,
 			},
 		},
 		{
 			Name:   "delete-old-revisions",
-			Usage:  "Deletes backups which are older than max-revisions",
+			Usage:  "Deletes backups which are older than max-revisions and not selected by any of the keep-* flags",
 			Action: deleteOldRevisions,
 			Flags: []cli.Flag{
 				endpointFlag,
@@ -135,6 +164,10 @@ func main() {
 				bucketFlag,
 				prefixFlag,
 				maxRevisionsFlag,
+				keepHourlyFlag,
+				keepDailyFlag,
+				keepWeeklyFlag,
+				keepMonthlyFlag,
 				fileFlag, // unused but kept for BC compatibility with old cleanup scripts
 			},
 		},
@@ -213,6 +246,19 @@ func store(c *cli.Context) error {
 		return err
 	}
 
+	if keysDir := c.String("encryption-keys"); keysDir != "" {
+		keys, err := encryption.LoadKeyRing(keysDir)
+		if err != nil {
+			return fmt.Errorf("failed to load encryption keys: %v", err)
+		}
+		keyID := c.String("encryption-key-id")
+		key, ok := keys[keyID]
+		if !ok {
+			return fmt.Errorf("encryption key %q not found", keyID)
+		}
+		uploader.SetEncryptionKey(keyID, key)
+	}
+
 	return uploader.Store(
 		c.String("file"),
 		c.String("bucket"),
@@ -230,7 +276,13 @@ func deleteOldRevisions(c *cli.Context) error {
 	return uploader.DeleteOldBackups(
 		c.String("bucket"),
 		c.String("prefix"),
-		c.Int("max-revisions"),
+		retention.Policy{
+			Last:    c.Int("max-revisions"),
+			Hourly:  c.Int("keep-hourly"),
+			Daily:   c.Int("keep-daily"),
+			Weekly:  c.Int("keep-weekly"),
+			Monthly: c.Int("keep-monthly"),
+		},
 	)
 }
 
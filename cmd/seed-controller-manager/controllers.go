@@ -27,10 +27,14 @@ import (
 	"k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/addon"
 	"k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/addoninstaller"
 	backupcontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/backup"
+	"k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/certificaterotation"
 	cloudcontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/cloud"
 	clustertemplatecontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/cluster-template-controller"
+	"k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/clusterclone"
 	seedconstraintsynchronizer "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/constraint-controller"
 	constrainttemplatecontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/constraint-template-controller"
+	"k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/credentialrotation"
+	"k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/encryption"
 	etcdbackupcontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/etcdbackup"
 	etcdrestorecontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/etcdrestore"
 	"k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/initialmachinedeployment"
@@ -60,6 +64,10 @@ var AllControllers = map[string]controllerCreator{
 	etcdbackupcontroller.ControllerName:           createEtcdBackupController,
 	backupcontroller.ControllerName:               createBackupController,
 	etcdrestorecontroller.ControllerName:          createEtcdRestoreController,
+	clusterclone.ControllerName:                   createClusterCloneController,
+	credentialrotation.ControllerName:             createCredentialRotationController,
+	encryption.ControllerName:                     createEncryptionController,
+	certificaterotation.ControllerName:            createCertificateRotationController,
 	monitoring.ControllerName:                     createMonitoringController,
 	cloudcontroller.ControllerName:                createCloudController,
 	seedresourcesuptodatecondition.ControllerName: createSeedConditionUpToDateController,
@@ -245,6 +253,7 @@ func createEtcdBackupController(ctrlCtx *controllerContext) error {
 		deleteContainer,
 		cleanupContainer,
 		ctrlCtx.runOptions.backupContainerImage,
+		ctrlCtx.runOptions.etcdLauncherImage,
 		ctrlCtx.versions,
 		ctrlCtx.runOptions.caBundle,
 		ctrlCtx.seedGetter,
@@ -416,6 +425,50 @@ func createConstraintTemplateController(ctrlCtx *controllerContext) error {
 	)
 }
 
+func createClusterCloneController(ctrlCtx *controllerContext) error {
+	return clusterclone.Add(
+		ctrlCtx.mgr,
+		ctrlCtx.runOptions.workerCount,
+		ctrlCtx.runOptions.workerName,
+		ctrlCtx.clientProvider,
+		ctrlCtx.log,
+	)
+}
+
+func createCredentialRotationController(ctrlCtx *controllerContext) error {
+	return credentialrotation.Add(
+		ctrlCtx.mgr,
+		ctrlCtx.runOptions.workerCount,
+		ctrlCtx.runOptions.workerName,
+		ctrlCtx.clientProvider,
+		ctrlCtx.log,
+		ctrlCtx.versions,
+	)
+}
+
+func createEncryptionController(ctrlCtx *controllerContext) error {
+	return encryption.Add(
+		ctrlCtx.mgr,
+		ctrlCtx.runOptions.workerCount,
+		ctrlCtx.runOptions.workerName,
+		ctrlCtx.clientProvider,
+		ctrlCtx.log,
+		ctrlCtx.versions,
+	)
+}
+
+func createCertificateRotationController(ctrlCtx *controllerContext) error {
+	certificaterotation.MustRegisterMetrics(prometheus.DefaultRegisterer)
+
+	return certificaterotation.Add(
+		ctrlCtx.mgr,
+		ctrlCtx.runOptions.workerCount,
+		ctrlCtx.runOptions.workerName,
+		ctrlCtx.log,
+		ctrlCtx.versions,
+	)
+}
+
 func createInitialMachineDeploymentController(ctrlCtx *controllerContext) error {
 	return initialmachinedeployment.Add(
 		ctrlCtx.ctx,
@@ -205,13 +205,13 @@ Please install the VerticalPodAutoscaler according to the documentation: https:/
 
 		// Setup the admission handler for kubermatic Seed CRDs
 		h.SetupWebhookWithManager(mgr)
-		// Setup the validation admission handler for kubermatic Cluster CRDs
-		clustervalidation.NewAdmissionHandler(options.featureGates).SetupWebhookWithManager(mgr)
-		// Setup the mutation admission handler for kubermatic Cluster CRDs
 		getter, err := seedGetterFactory(rootCtx, mgr.GetAPIReader(), options)
 		if err != nil {
 			log.Fatalf("make seed getter with api reader: %v", err)
 		}
+		// Setup the validation admission handler for kubermatic Cluster CRDs
+		clustervalidation.NewAdmissionHandler(options.featureGates, getter).SetupWebhookWithManager(mgr)
+		// Setup the mutation admission handler for kubermatic Cluster CRDs
 		seed, err := getter()
 		if err != nil {
 			log.Fatalf("could not get seed resource: %v", err)
@@ -94,8 +94,7 @@ type DatacenterSpec struct {
 	RequiredEmailDomain  string   `json:"requiredEmailDomain,omitempty"`
 	RequiredEmailDomains []string `json:"requiredEmailDomains,omitempty"`
 
-	// EnforceAuditLogging enforces audit logging on every cluster within the DC,
-	// ignoring cluster-specific settings.
+	// EnforceAuditLogging enforces audit logging on every cluster within the DC.
 	EnforceAuditLogging bool `json:"enforceAuditLogging"`
 
 	// EnforcePodSecurityPolicy enforces pod security policy plugin on every clusters within the DC,
@@ -828,6 +827,9 @@ type ClusterSpec struct {
 
 	// CNIPlugin contains the spec of the CNI plugin to be installed in the cluster.
 	CNIPlugin *kubermaticv1.CNIPluginSettings `json:"cniPlugin,omitempty"`
+
+	// APIServerAllowedIPRanges restricts access to the apiserver endpoint of the cluster to the given CIDRs.
+	APIServerAllowedIPRanges *kubermaticv1.NetworkRanges `json:"apiServerAllowedIPRanges,omitempty"`
 }
 
 // MarshalJSON marshals ClusterSpec object into JSON. It is overwritten to control data
@@ -853,6 +855,7 @@ func (cs *ClusterSpec) MarshalJSON() ([]byte, error) {
 		ContainerRuntime                     string                                 `json:"containerRuntime,omitempty"`
 		ClusterNetwork                       *kubermaticv1.ClusterNetworkingConfig  `json:"clusterNetwork,omitempty"`
 		CNIPlugin                            *kubermaticv1.CNIPluginSettings        `json:"cniPlugin,omitempty"`
+		APIServerAllowedIPRanges             *kubermaticv1.NetworkRanges            `json:"apiServerAllowedIPRanges,omitempty"`
 	}{
 		Cloud: PublicCloudSpec{
 			DatacenterName: cs.Cloud.DatacenterName,
@@ -888,6 +891,7 @@ func (cs *ClusterSpec) MarshalJSON() ([]byte, error) {
 		ContainerRuntime:                     cs.ContainerRuntime,
 		ClusterNetwork:                       cs.ClusterNetwork,
 		CNIPlugin:                            cs.CNIPlugin,
+		APIServerAllowedIPRanges:             cs.APIServerAllowedIPRanges,
 	})
 
 	return ret, err
@@ -2264,6 +2268,8 @@ const (
 	UserClusterRoleCleanupFinalizer = "kubermatic.io/user-cluster-role"
 	// ExternalClusterKubeconfigCleanupFinalizer indicates that secrets for kubeconfig still need cleanup
 	ExternalClusterKubeconfigCleanupFinalizer = "kubermatic.io/cleanup-kubeconfig-secret"
+	// ExternalClusterCloudCleanupFinalizer indicates that a cluster created by Kubermatic on a cloud provider still needs to be deleted there
+	ExternalClusterCloudCleanupFinalizer = "kubermatic.io/cleanup-external-cluster-cloud"
 	// EtcdBackConfigCleanupFinalizer indicates that EtcdBackupConfigs for the cluster still need cleanup
 	EtcdBackupConfigCleanupFinalizer = "kubermatic.io/cleanup-etcdbackupconfigs"
 	// GatekeeperConstraintTemplateCleanupFinalizer indicates that synced gatekeeper Constraint Templates on user cluster need cleanup
@@ -140,6 +140,32 @@ type PresetProvider struct {
 	Enabled bool                  `json:"enabled"`
 }
 
+// PresetCredentialsRotation represents the result of rotating the credentials of all clusters using a preset
+// swagger:model PresetCredentialsRotation
+type PresetCredentialsRotation struct {
+	// RotatedClusters contains the IDs of the clusters whose credentials have been rotated
+	RotatedClusters []string `json:"rotatedClusters"`
+	// FailedClusters maps the IDs of the clusters whose credentials could not be rotated to the error
+	FailedClusters map[string]string `json:"failedClusters,omitempty"`
+}
+
+// PresetStatus represents the usage of a preset
+// swagger:model PresetStatus
+type PresetStatus struct {
+	Enabled bool `json:"enabled"`
+	// Clusters are the clusters that use the credentials of the preset
+	Clusters []PresetCluster `json:"clusters"`
+}
+
+// PresetCluster represents a cluster that uses the credentials of a preset
+// swagger:model PresetCluster
+type PresetCluster struct {
+	// ID is the ID of the cluster
+	ID        string `json:"id"`
+	ProjectID string `json:"projectID,omitempty"`
+	Seed      string `json:"seed"`
+}
+
 // Alertmanager represents an Alertmanager Configuration
 // swagger:model Alertmanager
 type Alertmanager struct {
@@ -237,18 +263,25 @@ type EtcdBackupConfigStatus struct {
 
 type BackupStatus struct {
 	// ScheduledTime will always be set when the BackupStatus is created, so it'll never be nil
-	ScheduledTime      *apiv1.Time                `json:"scheduledTime,omitempty"`
-	BackupName         string                     `json:"backupName,omitempty"`
-	JobName            string                     `json:"jobName,omitempty"`
-	BackupStartTime    *apiv1.Time                `json:"backupStartTime,omitempty"`
-	BackupFinishedTime *apiv1.Time                `json:"backupFinishedTime,omitempty"`
-	BackupPhase        crdapiv1.BackupStatusPhase `json:"backupPhase,omitempty"`
-	BackupMessage      string                     `json:"backupMessage,omitempty"`
-	DeleteJobName      string                     `json:"deleteJobName,omitempty"`
-	DeleteStartTime    *apiv1.Time                `json:"deleteStartTime,omitempty"`
-	DeleteFinishedTime *apiv1.Time                `json:"deleteFinishedTime,omitempty"`
-	DeletePhase        crdapiv1.BackupStatusPhase `json:"deletePhase,omitempty"`
-	DeleteMessage      string                     `json:"deleteMessage,omitempty"`
+	ScheduledTime            *apiv1.Time                `json:"scheduledTime,omitempty"`
+	BackupName               string                     `json:"backupName,omitempty"`
+	JobName                  string                     `json:"jobName,omitempty"`
+	BackupStartTime          *apiv1.Time                `json:"backupStartTime,omitempty"`
+	BackupFinishedTime       *apiv1.Time                `json:"backupFinishedTime,omitempty"`
+	BackupPhase              crdapiv1.BackupStatusPhase `json:"backupPhase,omitempty"`
+	BackupMessage            string                     `json:"backupMessage,omitempty"`
+	DeleteJobName            string                     `json:"deleteJobName,omitempty"`
+	DeleteStartTime          *apiv1.Time                `json:"deleteStartTime,omitempty"`
+	DeleteFinishedTime       *apiv1.Time                `json:"deleteFinishedTime,omitempty"`
+	DeletePhase              crdapiv1.BackupStatusPhase `json:"deletePhase,omitempty"`
+	DeleteMessage            string                     `json:"deleteMessage,omitempty"`
+	VerificationJobName      string                     `json:"verificationJobName,omitempty"`
+	VerificationFinishedTime *apiv1.Time                `json:"verificationFinishedTime,omitempty"`
+	VerificationPhase        crdapiv1.BackupStatusPhase `json:"verificationPhase,omitempty"`
+	VerificationMessage      string                     `json:"verificationMessage,omitempty"`
+	SnapshotRevision         int64                      `json:"snapshotRevision,omitempty"`
+	SnapshotKeyCount         int64                      `json:"snapshotKeyCount,omitempty"`
+	SnapshotHash             string                     `json:"snapshotHash,omitempty"`
 }
 
 type EtcdBackupConfigCondition struct {
@@ -282,9 +315,49 @@ type EtcdBackupConfigSpec struct {
 	// Keep is the number of backups to keep around before deleting the oldest one
 	// If not set, defaults to DefaultKeptBackupsCount. Only used if Schedule is set.
 	Keep *int `json:"keep,omitempty"`
+	// Retention configures a grandfather-father-son retention policy. If set, Keep does not default to
+	// DefaultKeptBackupsCount. Only used if Schedule is set.
+	Retention *EtcdBackupRetention `json:"retention,omitempty"`
 	// Destination indicates where the backup will be stored. The destination name should correspond to a destination in
 	// the cluster's Seed.Spec.EtcdBackupRestore. If empty, it will use the legacy destination in Seed.Spec.BackupRestore
 	Destination string `json:"destination,omitempty"`
+	// Continuous configures shipping of incremental etcd revisions between snapshots, which allows point-in-time restores
+	Continuous *EtcdContinuousBackup `json:"continuous,omitempty"`
+	// Verification configures an integrity check of every backup after it has been uploaded
+	Verification *EtcdBackupVerification `json:"verification,omitempty"`
+}
+
+// EtcdBackupRetention configures a grandfather-father-son retention policy
+// swagger:model EtcdBackupRetention
+type EtcdBackupRetention struct {
+	// Hourly is the number of hours for which the newest backup is kept
+	Hourly int `json:"hourly,omitempty"`
+	// Daily is the number of days for which the newest backup is kept
+	Daily int `json:"daily,omitempty"`
+	// Weekly is the number of weeks for which the newest backup is kept
+	Weekly int `json:"weekly,omitempty"`
+	// Monthly is the number of months for which the newest backup is kept
+	Monthly int `json:"monthly,omitempty"`
+}
+
+// EtcdBackupVerification configures the verification of uploaded backups
+// swagger:model EtcdBackupVerification
+type EtcdBackupVerification struct {
+	// Enabled starts a verification job for every completed backup
+	Enabled bool `json:"enabled"`
+	// Restore additionally restores the snapshot into a temporary data directory
+	Restore bool `json:"restore,omitempty"`
+}
+
+// EtcdContinuousBackup configures the continuous shipping of etcd revisions
+// swagger:model EtcdContinuousBackup
+type EtcdContinuousBackup struct {
+	// Enabled starts shipping etcd revisions to the backup destination
+	Enabled bool `json:"enabled"`
+	// FlushInterval is the maximum amount of time changes are buffered before being uploaded, e.g. "1m"
+	FlushInterval string `json:"flushInterval,omitempty"`
+	// Retention is the amount of time shipped revisions are kept, e.g. "168h"
+	Retention string `json:"retention,omitempty"`
 }
 
 // EtcdRestore represents an object holding the configuration for etcd backup restore
@@ -311,6 +384,70 @@ type EtcdRestoreSpec struct {
 	// BackupDownloadCredentialsSecret is the name of a secret in the cluster-xxx namespace containing
 	// credentials needed to download the backup
 	BackupDownloadCredentialsSecret string `json:"backupDownloadCredentialsSecret,omitempty"`
+	// RestoreToTime is the point in time to restore to. Revisions shipped by a continuous backup after
+	// the backup was taken are replayed up to this time. Mutually exclusive with RestoreToRevision.
+	RestoreToTime *apiv1.Time `json:"restoreToTime,omitempty"`
+	// RestoreToRevision is the etcd revision to restore to. Revisions shipped by a continuous backup after
+	// the backup was taken are replayed up to this revision. Mutually exclusive with RestoreToTime.
+	RestoreToRevision int64 `json:"restoreToRevision,omitempty"`
+}
+
+// ClusterCloneSpec represents an object holding the specification of a new cluster that is created
+// from an etcd backup of an existing cluster
+// swagger:model ClusterCloneSpec
+type ClusterCloneSpec struct {
+	// Name is the name of the new cluster. If not set, "<cluster name>-clone" is used.
+	Name string `json:"name,omitempty"`
+	// BackupName is the name of the backup to restore
+	BackupName string `json:"backupName"`
+	// Destination indicates where the backup was stored. The destination name should correspond to a destination in
+	// the cluster's Seed.Spec.EtcdBackupRestore. If empty, the legacy destination configured in Seed.Spec.BackupRestore is used.
+	Destination string `json:"destination,omitempty"`
+	// RestoreToTime is the point in time to restore to. Revisions shipped by a continuous backup after
+	// the backup was taken are replayed up to this time. Mutually exclusive with RestoreToRevision.
+	RestoreToTime *apiv1.Time `json:"restoreToTime,omitempty"`
+	// RestoreToRevision is the etcd revision to restore to. Revisions shipped by a continuous backup after
+	// the backup was taken are replayed up to this revision. Mutually exclusive with RestoreToTime.
+	RestoreToRevision int64 `json:"restoreToRevision,omitempty"`
+}
+
+// ClusterMigration represents an object holding the migration of a cluster control plane to another seed
+// swagger:model ClusterMigration
+type ClusterMigration struct {
+	Name string `json:"name"`
+
+	Spec   ClusterMigrationSpec   `json:"spec"`
+	Status ClusterMigrationStatus `json:"status"`
+}
+
+// ClusterMigrationSpec represents an object holding the cluster migration specification
+// swagger:model ClusterMigrationSpec
+type ClusterMigrationSpec struct {
+	// ClusterID is the id of the cluster which will be migrated
+	ClusterID string `json:"clusterId"`
+	// SourceSeed is the name of the seed currently hosting the control plane of the cluster
+	SourceSeed string `json:"sourceSeed"`
+	// TargetSeed is the name of the seed the control plane will be moved to
+	TargetSeed string `json:"targetSeed"`
+	// TargetDatacenter optionally overrides the datacenter of the cluster. It must belong to the target seed.
+	TargetDatacenter string `json:"targetDatacenter,omitempty"`
+	// Destination is the name of the backup destination used to transfer the etcd data. It must be configured
+	// in Spec.EtcdBackupRestore of both the source and the target seed and point to the same bucket.
+	Destination string `json:"destination"`
+}
+
+// ClusterMigrationStatus represents the observed state of a cluster migration
+// swagger:model ClusterMigrationStatus
+type ClusterMigrationStatus struct {
+	Phase      crdapiv1.ClusterMigrationPhase `json:"phase,omitempty"`
+	Message    string                         `json:"message,omitempty"`
+	BackupName string                         `json:"backupName,omitempty"`
+	// TargetURL is the URL of the apiserver on the target seed
+	TargetURL string `json:"targetURL,omitempty"`
+	// TargetIP is the IP the DNS records of the cluster have to point to after the migration
+	TargetIP       string      `json:"targetIP,omitempty"`
+	StartTime      *apiv1.Time `json:"startTime,omitempty"`
+	CompletionTime *apiv1.Time `json:"completionTime,omitempty"`
 }
 
 // OIDCSpec contains OIDC params that can be used to access user cluster.
@@ -326,6 +463,10 @@ type OIDCSpec struct {
 type BackupCredentials struct {
 	// S3BackupCredentials holds credentials for a S3 client compatible backup destination
 	S3BackupCredentials S3BackupCredentials `json:"s3,omitempty"`
+	// AzureBlobBackupCredentials holds credentials for an Azure Blob Storage backup destination
+	AzureBlobBackupCredentials *AzureBlobBackupCredentials `json:"azureblob,omitempty"`
+	// GCSBackupCredentials holds credentials for a Google Cloud Storage backup destination
+	GCSBackupCredentials *GCSBackupCredentials `json:"gcs,omitempty"`
 	// Destination corresponds to the Seeds Seed.Spec.EtcdBackupRestore.Destinations, it defines for which destination
 	// the backup credentials will be created. If set, it updates the credentials ref in the related Seed BackupDestination
 	Destination string `json:"destination,omitempty"`
@@ -338,6 +479,20 @@ type S3BackupCredentials struct {
 	SecretAccessKey string `json:"secretAccessKey,omitempty"`
 }
 
+// AzureBlobBackupCredentials contains credentials for Azure Blob Storage etcd backups
+// swagger:model AzureBlobBackupCredentials
+type AzureBlobBackupCredentials struct {
+	AccountName string `json:"accountName,omitempty"`
+	AccountKey  string `json:"accountKey,omitempty"`
+}
+
+// GCSBackupCredentials contains credentials for Google Cloud Storage etcd backups
+// swagger:model GCSBackupCredentials
+type GCSBackupCredentials struct {
+	// ServiceAccount is the JSON key of the service account used to access the bucket
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+}
+
 // MLAAdminSetting represents an object holding admin setting options for user cluster MLA (Monitoring, Logging and Alerting).
 // swagger:model MLAAdminSetting
 type MLAAdminSetting struct {
@@ -352,6 +507,47 @@ type MLAAdminSetting struct {
 type ExternalCluster struct {
 	apiv1.Cluster `json:",inline"`
 	Cloud         *ExternalClusterCloudSpec `json:"cloud,omitempty"`
+	Status        ExternalClusterStatus     `json:"status"`
+}
+
+// ExternalClusterStatus represents the lifecycle status of an external cluster
+// swagger:model ExternalClusterStatus
+type ExternalClusterStatus struct {
+	// Phase is the lifecycle phase of the cluster on its cloud provider. It is empty
+	// for clusters that were imported from a kubeconfig.
+	Phase crdapiv1.ExternalClusterPhase `json:"phase,omitempty"`
+	// StatusMessage contains the state reported by the cloud provider or the last
+	// error that occurred while talking to it.
+	StatusMessage string `json:"statusMessage,omitempty"`
+	// Version is the Kubernetes version reported by the API server of the cluster.
+	Version string `json:"version,omitempty"`
+	// NodeCount is the number of nodes in the cluster.
+	NodeCount int32 `json:"nodeCount,omitempty"`
+	// LastSeen is the last time the API server of the cluster could be reached.
+	LastSeen *apiv1.Time `json:"lastSeen,omitempty"`
+	// KubeconfigExpiry is the time the credentials of the stored kubeconfig expire.
+	KubeconfigExpiry *apiv1.Time `json:"kubeconfigExpiry,omitempty"`
+	// Conditions contains conditions the cluster is in.
+	Conditions []ExternalClusterCondition `json:"conditions,omitempty"`
+}
+
+type ExternalClusterCondition struct {
+	// Type of external cluster condition.
+	Type crdapiv1.ExternalClusterConditionType `json:"type"`
+	// Status of the condition, one of True, False, Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+	// Last time we got an update on a given condition.
+	// +optional
+	LastHeartbeatTime apiv1.Time `json:"lastHeartbeatTime,omitempty"`
+	// Last time the condition transit from one status to another.
+	// +optional
+	LastTransitionTime apiv1.Time `json:"lastTransitionTime,omitempty"`
+	// (brief) reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Human readable message indicating details about last transition.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // ExternalClusterCloudSpec represents an object holding cluster cloud details
@@ -366,6 +562,24 @@ type GKECloudSpec struct {
 	Name           string `json:"name"`
 	ServiceAccount string `json:"serviceAccount,omitempty"`
 	Zone           string `json:"zone"`
+	// ClusterSpec is set when the cluster should be created on GKE instead of being imported.
+	ClusterSpec *GKEClusterSpec `json:"clusterSpec,omitempty"`
+}
+
+// GKEClusterSpec represents the parameters used to create a new GKE cluster
+// swagger:model GKEClusterSpec
+type GKEClusterSpec struct {
+	// InitialClusterVersion is the Kubernetes version of the control plane. GKE picks its
+	// default version when it is empty.
+	InitialClusterVersion string `json:"initialClusterVersion,omitempty"`
+	// InitialNodeCount is the number of nodes of the default node pool.
+	InitialNodeCount int64 `json:"initialNodeCount,omitempty"`
+	// MachineType is the machine type of the default node pool.
+	MachineType string `json:"machineType,omitempty"`
+	// Network is the name of the Compute Engine network the cluster is connected to.
+	Network string `json:"network,omitempty"`
+	// Subnetwork is the name of the Compute Engine subnetwork the cluster is connected to.
+	Subnetwork string `json:"subnetwork,omitempty"`
 }
 
 type EKSCloudSpec struct {
@@ -373,6 +587,23 @@ type EKSCloudSpec struct {
 	AccessKeyID     string `json:"accessKeyID"`
 	SecretAccessKey string `json:"secretAccessKey"`
 	Region          string `json:"region"`
+	// ClusterSpec is set when the cluster should be created on EKS instead of being imported.
+	ClusterSpec *EKSClusterSpec `json:"clusterSpec,omitempty"`
+}
+
+// EKSClusterSpec represents the parameters used to create a new EKS cluster
+// swagger:model EKSClusterSpec
+type EKSClusterSpec struct {
+	// RoleArn is the ARN of the IAM role that allows EKS to manage AWS resources on your behalf.
+	RoleArn string `json:"roleArn"`
+	// Version is the Kubernetes version of the control plane. EKS picks its default
+	// version when it is empty.
+	Version string `json:"version,omitempty"`
+	// SubnetIDs are the subnets in which EKS places the control plane network interfaces.
+	// At least two subnets in different availability zones are required.
+	SubnetIDs []string `json:"subnetIDs"`
+	// SecurityGroupIDs are the security groups applied to the control plane network interfaces.
+	SecurityGroupIDs []string `json:"securityGroupIDs,omitempty"`
 }
 
 type AKSCloudSpec struct {
@@ -382,6 +613,24 @@ type AKSCloudSpec struct {
 	ClientID       string `json:"clientID"`
 	ClientSecret   string `json:"clientSecret"`
 	ResourceGroup  string `json:"resourceGroup"`
+	// ClusterSpec is set when the cluster should be created on AKS instead of being imported.
+	ClusterSpec *AKSClusterSpec `json:"clusterSpec,omitempty"`
+}
+
+// AKSClusterSpec represents the parameters used to create a new AKS cluster
+// swagger:model AKSClusterSpec
+type AKSClusterSpec struct {
+	// Location is the Azure region of the cluster.
+	Location string `json:"location"`
+	// KubernetesVersion is the Kubernetes version of the control plane. AKS picks its
+	// default version when it is empty.
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+	// DNSPrefix is the DNS prefix of the control plane. The cluster name is used when it is empty.
+	DNSPrefix string `json:"dnsPrefix,omitempty"`
+	// NodeCount is the number of nodes of the system node pool.
+	NodeCount int32 `json:"nodeCount,omitempty"`
+	// VMSize is the VM size of the system node pool.
+	VMSize string `json:"vmSize,omitempty"`
 }
 
 // ExternalClusterNode represents an object holding external cluster node
@@ -47,6 +47,27 @@ const (
 
 	// ForceRestartAnnotation is key of the annotation used to restart machine deployments.
 	ForceRestartAnnotation = "forceRestart"
+
+	// ExternalNameAnnotation overrides the external name of a cluster. It is set on clusters
+	// that were migrated from another seed, so that the apiserver certificate and existing
+	// kubeconfigs stay valid after the migration. It is not removed automatically.
+	ExternalNameAnnotation = "kubermatic.io/external-name"
+
+	// CloneRequestAnnotation is set on clusters that are created from the etcd backup of another
+	// cluster. It contains the JSON encoded EtcdRestoreSpec used to restore the backup. As long as
+	// it is set, all controllers that could act on the cloud resources of the source cluster are
+	// kept scaled down.
+	CloneRequestAnnotation = "kubermatic.io/clone-request"
+
+	// PresetNameAnnotation is set on clusters whose cloud credentials were taken from a preset. It
+	// contains the name of the preset and is used to find the clusters whose credentials have to be
+	// rotated when the preset changes.
+	PresetNameAnnotation = "kubermatic.io/preset-name"
+
+	// CredentialsRevisionAnnotation contains a checksum of the cloud credentials the control plane
+	// of the cluster was last rolled out with. It is maintained by the credential rotation controller
+	// and copied to the pod templates of all control plane components that use the credentials.
+	CredentialsRevisionAnnotation = "kubermatic.io/credentials-revision"
 )
 
 const (
@@ -124,6 +145,13 @@ type ClusterSpec struct {
 	// or via a dedicated LoadBalancer
 	ExposeStrategy ExposeStrategy `json:"exposeStrategy"`
 
+	// APIServerAllowedIPRanges restricts access to the apiserver endpoint of the cluster to the
+	// given CIDRs. It is enforced by the nodeport-proxy for the NodePort and Tunneling expose
+	// strategies, which requires the seed to preserve the source IPs (nodeportProxy.preserveSourceIPs),
+	// and by the LoadBalancer for the LoadBalancer expose strategy. As the nodes access the
+	// apiserver through the same endpoint, their addresses must be allowed as well.
+	APIServerAllowedIPRanges *NetworkRanges `json:"apiServerAllowedIPRanges,omitempty"`
+
 	// Pause tells that this cluster is currently not managed by the controller.
 	// It indicates that the user needs to do some action to resolve the pause.
 	Pause bool `json:"pause"`
@@ -192,6 +220,13 @@ type ClusterSpec struct {
 
 	// CNIPlugin contains the spec of the CNI plugin to be installed in the cluster.
 	CNIPlugin *CNIPluginSettings `json:"cniPlugin,omitempty"`
+
+	// EncryptionConfiguration configures the encryption at rest of resources like Secrets in the
+	// etcd of the cluster.
+	EncryptionConfiguration *EncryptionConfiguration `json:"encryptionConfiguration,omitempty"`
+
+	// CertificateRotation configures the renewal of the certificates of the control plane.
+	CertificateRotation *CertificateRotationSettings `json:"certificateRotation,omitempty"`
 }
 
 // CNIPluginSettings contains the spec of the CNI plugin used by the Cluster.
@@ -222,6 +257,92 @@ const (
 	CNIPluginTypeNone CNIPluginType = "none"
 )
 
+// EncryptionConfiguration configures the encryption at rest of resources of the user cluster.
+// The keys are generated by Kubermatic and stored in the cluster namespace, unless a KMS plugin
+// is used.
+type EncryptionConfiguration struct {
+	// Enabled turns on the encryption of the configured resources. Disabling it again decrypts
+	// all resources.
+	Enabled bool `json:"enabled,omitempty"`
+	// Resources are the resources that are encrypted, e.g. secrets or configmaps. Defaults to secrets.
+	Resources []string `json:"resources,omitempty"`
+	// Provider is the cipher used with the keys generated by Kubermatic, either aescbc or
+	// secretbox. Defaults to aescbc. It is ignored if KMS is configured.
+	// +kubebuilder:validation:Enum="";aescbc;secretbox
+	Provider EncryptionProvider `json:"provider,omitempty"`
+	// KeyGeneration is the generation of the key generated by Kubermatic. Increasing it rotates
+	// the key: a new key is added to the apiserver, all resources are rewritten with it and the
+	// previous key is removed.
+	KeyGeneration int64 `json:"keyGeneration,omitempty"`
+	// KMS delegates the encryption to a KMS v2 plugin instead of using keys generated by Kubermatic.
+	KMS *KMSEncryptionConfiguration `json:"kms,omitempty"`
+}
+
+// KMSEncryptionConfiguration configures a KMS v2 plugin used to encrypt resources.
+type KMSEncryptionConfiguration struct {
+	// Name of the KMS plugin. It is stored with every encrypted resource, so changing it
+	// rewrites all resources.
+	Name string `json:"name"`
+	// Endpoint is the gRPC server the KMS plugin listens on. It has to be a unix socket in
+	// /var/run/kmsplugin, for example unix:///var/run/kmsplugin/socket.sock, on the seed
+	// nodes the apiserver of the cluster runs on.
+	Endpoint string `json:"endpoint"`
+	// Timeout for calls to the KMS plugin. Defaults to 3s.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// EncryptionProvider is a provider of the encryption configuration of the apiserver.
+type EncryptionProvider string
+
+const (
+	EncryptionProviderAESCBC    EncryptionProvider = "aescbc"
+	EncryptionProviderSecretbox EncryptionProvider = "secretbox"
+	EncryptionProviderKMS       EncryptionProvider = "kms"
+	// EncryptionProviderIdentity stores resources unencrypted.
+	EncryptionProviderIdentity EncryptionProvider = "identity"
+)
+
+// EncryptionKey identifies a key of the encryption configuration of the apiserver.
+type EncryptionKey struct {
+	// Provider of the key.
+	// +kubebuilder:validation:Enum=aescbc;secretbox;kms;identity
+	Provider EncryptionProvider `json:"provider"`
+	// Name of the key, or of the KMS plugin.
+	Name string `json:"name"`
+	// KMS is the configuration of the KMS plugin providing the key.
+	KMS *KMSEncryptionConfiguration `json:"kms,omitempty"`
+}
+
+// ClusterEncryptionStatus describes the encryption configuration the apiserver uses.
+type ClusterEncryptionStatus struct {
+	// Keys are the keys the apiserver is configured with. The first key is used to encrypt
+	// resources, all keys can be used to decrypt them.
+	Keys []EncryptionKey `json:"keys,omitempty"`
+	// EncryptedResources are the resources that have been rewritten with the first key.
+	EncryptedResources []string `json:"encryptedResources,omitempty"`
+}
+
+// CertificateRotationSettings configures the renewal of the certificates of the control plane.
+type CertificateRotationSettings struct {
+	// RenewBefore is the remaining validity at which certificates of the control plane are
+	// renewed. It has to be between 30 and 180 days and defaults to 30 days.
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+}
+
+// ClusterCertificatesStatus describes the certificates of the control plane.
+type ClusterCertificatesStatus struct {
+	// Certificates lists when the certificates of the control plane expire.
+	Certificates []CertificateExpiry `json:"certificates,omitempty"`
+}
+
+// CertificateExpiry describes when the certificate stored in a Secret expires.
+type CertificateExpiry struct {
+	// SecretName is the name of the Secret in the cluster namespace that contains the certificate.
+	SecretName string `json:"secretName"`
+	// NotAfter is the time the certificate expires.
+	NotAfter metav1.Time `json:"notAfter"`
+}
+
 const (
 	// ClusterFeatureExternalCloudProvider describes the external cloud provider feature. It is
 	// only supported on a limited set of providers for a specific set of Kube versions. It must
@@ -245,7 +366,7 @@ const (
 	ApiserverNetworkPolicy = "apiserverNetworkPolicy"
 )
 
-// +kubebuilder:validation:Enum="";SeedResourcesUpToDate;ClusterControllerReconciledSuccessfully;AddonControllerReconciledSuccessfully;AddonInstallerControllerReconciledSuccessfully;BackupControllerReconciledSuccessfully;CloudControllerReconcilledSuccessfully;UpdateControllerReconciledSuccessfully;MonitoringControllerReconciledSuccessfully;MachineDeploymentReconciledSuccessfully;MLAControllerReconciledSuccessfully;ClusterInitialized;RancherInitializedSuccessfully;RancherClusterImportedSuccessfully;EtcdClusterInitialized;CSIKubeletMigrationCompleted;ClusterUpdateSuccessful;ClusterUpdateInProgress;CSIKubeletMigrationSuccess;CSIKubeletMigrationInProgress;
+// +kubebuilder:validation:Enum="";SeedResourcesUpToDate;ClusterControllerReconciledSuccessfully;AddonControllerReconciledSuccessfully;AddonInstallerControllerReconciledSuccessfully;BackupControllerReconciledSuccessfully;CloudControllerReconcilledSuccessfully;UpdateControllerReconciledSuccessfully;MonitoringControllerReconciledSuccessfully;MachineDeploymentReconciledSuccessfully;MLAControllerReconciledSuccessfully;ClusterInitialized;RancherInitializedSuccessfully;RancherClusterImportedSuccessfully;EtcdClusterInitialized;EtcdDatabaseHealthy;EtcdMembershipReconciled;CloudCredentialsRotated;EncryptionReady;CertificatesValid;CSIKubeletMigrationCompleted;ClusterUpdateSuccessful;ClusterUpdateInProgress;CSIKubeletMigrationSuccess;CSIKubeletMigrationInProgress;
 
 // ClusterConditionType is used to indicate the type of a cluster condition. For all condition
 // types, the `true` value must indicate success. All condition types must be registered within
@@ -278,6 +399,21 @@ const (
 	ClusterConditionRancherClusterImported ClusterConditionType = "RancherClusterImportedSuccessfully"
 
 	ClusterConditionEtcdClusterInitialized ClusterConditionType = "EtcdClusterInitialized"
+	// ClusterConditionEtcdDatabaseHealthy indicates that the etcd database has not run out of space and
+	// no member is waiting to be defragmented. It is maintained by etcd-launcher.
+	ClusterConditionEtcdDatabaseHealthy ClusterConditionType = "EtcdDatabaseHealthy"
+	// ClusterConditionEtcdMembershipReconciled indicates that the etcd cluster has the requested number of
+	// members and no member is being removed or replaced. It is maintained by etcd-launcher.
+	ClusterConditionEtcdMembershipReconciled ClusterConditionType = "EtcdMembershipReconciled"
+	// ClusterConditionCloudCredentialsRotated indicates that all control plane components have been
+	// restarted with the current cloud credentials after they were rotated.
+	ClusterConditionCloudCredentialsRotated ClusterConditionType = "CloudCredentialsRotated"
+	// ClusterConditionEncryptionReady indicates that all configured resources are encrypted with
+	// the current key and no key rotation is in progress.
+	ClusterConditionEncryptionReady ClusterConditionType = "EncryptionReady"
+	// ClusterConditionCertificatesValid indicates that no certificate of the control plane is due
+	// for renewal and no rotation of the root CA is in progress.
+	ClusterConditionCertificatesValid ClusterConditionType = "CertificatesValid"
 
 	// ClusterConditionNone is a special value indicating that no cluster condition should be set
 	ClusterConditionNone ClusterConditionType = ""
@@ -291,6 +427,28 @@ const (
 	ReasonClusterUpdateInProgress             = "ClusterUpdateInProgress"
 	ReasonClusterCSIKubeletMigrationCompleted = "CSIKubeletMigrationSuccess"
 	ReasonClusterCCMMigrationInProgress       = "CSIKubeletMigrationInProgress"
+
+	ReasonEtcdDefragmentationInProgress = "DefragmentationInProgress"
+	ReasonEtcdDefragmentationFailed     = "DefragmentationFailed"
+	ReasonEtcdDatabaseSpaceExceeded     = "DatabaseSpaceExceeded"
+
+	ReasonEtcdScalingDown             = "ScalingDown"
+	ReasonEtcdMemberRemoved           = "MemberRemoved"
+	ReasonEtcdReplacingMember         = "ReplacingMember"
+	ReasonEtcdMemberReplacementFailed = "MemberReplacementFailed"
+
+	ReasonCredentialsRotationInProgress = "RotationInProgress"
+	ReasonCredentialsRotationSucceeded  = "RotationSucceeded"
+
+	ReasonEncryptionKeyRolloutInProgress = "KeyRolloutInProgress"
+	ReasonEncryptionRewriteInProgress    = "RewriteInProgress"
+	ReasonEncryptionRewriteFailed        = "RewriteFailed"
+	ReasonEncryptionSucceeded            = "EncryptionSucceeded"
+	ReasonEncryptionDisabled             = "EncryptionDisabled"
+
+	ReasonCertificatesValid    = "CertificatesValid"
+	ReasonCertificatesRenewing = "CertificatesRenewing"
+	ReasonCertificatesExpiring = "CertificatesExpiring"
 )
 
 var AllClusterConditionTypes = []ClusterConditionType{
@@ -369,6 +527,14 @@ type ClusterStatus struct {
 
 	// InheritedLabels are labels the cluster inherited from the project. They are read-only for users.
 	InheritedLabels map[string]string `json:"inheritedLabels,omitempty"`
+
+	// Encryption describes the encryption configuration of the apiserver. It is maintained by the
+	// encryption controller and only set while resources are encrypted.
+	Encryption *ClusterEncryptionStatus `json:"encryption,omitempty"`
+
+	// Certificates describes the certificates of the control plane. It is maintained by the
+	// certificate rotation controller.
+	Certificates *ClusterCertificatesStatus `json:"certificates,omitempty"`
 }
 
 // HasConditionValue returns true if the cluster status has the given condition with the given status.
@@ -417,6 +583,112 @@ const (
 type AuditLoggingSettings struct {
 	Enabled      bool              `json:"enabled,omitempty"`
 	PolicyPreset AuditPolicyPreset `json:"policyPreset,omitempty"`
+	// Policy is a custom audit policy (an audit.k8s.io/v1 Policy) as YAML, which is used instead of
+	// the policy preset.
+	Policy string `json:"policy,omitempty"`
+	// PolicyName selects an audit policy from the audit policies of the seed, which is used instead of
+	// the policy preset. It is ignored if a custom policy is set.
+	PolicyName string `json:"policyName,omitempty"`
+	// Sinks are the backends audit events are sent to. Clusters without sinks use the default audit
+	// sinks of their datacenter. Without any sinks, audit events are written to the log of the
+	// audit-logs sidecar of the apiserver.
+	Sinks []AuditSink `json:"sinks,omitempty"`
+	// MLAIngestionEnabled sends audit events to the user cluster MLA stack of the seed as well, which
+	// stores them in Loki with the cluster as tenant. User cluster MLA must be enabled for the seed.
+	MLAIngestionEnabled bool `json:"mlaIngestionEnabled,omitempty"`
+}
+
+// AuditSink is a backend audit events are sent to. Exactly one of its backends must be set.
+type AuditSink struct {
+	// Name identifies the sink.
+	Name string `json:"name"`
+	// Webhook sends audit events to a webhook using the audit webhook backend of the apiserver.
+	// Only one webhook sink is supported.
+	Webhook *AuditWebhookSink `json:"webhook,omitempty"`
+	// HTTP posts audit events as JSON to an HTTP endpoint.
+	HTTP *AuditHTTPSink `json:"http,omitempty"`
+	// Loki pushes audit events to Loki.
+	Loki *AuditLokiSink `json:"loki,omitempty"`
+	// S3 uploads batches of audit events to an S3 bucket. Only one S3 sink is supported.
+	S3 *AuditS3Sink `json:"s3,omitempty"`
+	// Syslog sends audit events to a syslog server.
+	Syslog *AuditSyslogSink `json:"syslog,omitempty"`
+}
+
+type AuditWebhookSink struct {
+	// URL is the HTTPS endpoint batches of audit events are posted to as audit.k8s.io/v1 EventList.
+	URL string `json:"url"`
+	// CABundle contains the PEM-encoded CA certificates to verify the webhook. The system CAs are used
+	// if it is empty.
+	CABundle string `json:"caBundle,omitempty"`
+	// Token is the bearer token to authenticate with. It is moved into the credentials secret of the
+	// cluster when the cluster is created or updated using the API.
+	Token string `json:"token,omitempty"`
+	// CredentialsReference references a Secret with the bearer token to authenticate with in the
+	// "token" key, prefixed with the key of the reference and a dash if it is set. For clusters, it
+	// can only reference the audit sinks credentials secret of the cluster.
+	CredentialsReference *providerconfig.GlobalSecretKeySelector `json:"credentialsReference,omitempty"`
+}
+
+type AuditHTTPSink struct {
+	// URL is the endpoint audit events are posted to.
+	URL string `json:"url"`
+	// Username and Password are used to authenticate with using basic auth. They are moved into the
+	// credentials secret of the cluster when the cluster is created or updated using the API.
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	// CredentialsReference references a Secret with the "username" and "password" to authenticate
+	// with using basic auth, prefixed with the key of the reference and a dash if it is set. For
+	// clusters, it can only reference the audit sinks credentials secret of the cluster.
+	CredentialsReference *providerconfig.GlobalSecretKeySelector `json:"credentialsReference,omitempty"`
+}
+
+type AuditLokiSink struct {
+	// URL is the base URL of Loki, e.g. https://loki.example.com.
+	URL string `json:"url"`
+	// TenantID is the tenant the audit events are stored for in a multi-tenant Loki.
+	TenantID string `json:"tenantID,omitempty"`
+	// Username and Password are used to authenticate with using basic auth. They are moved into the
+	// credentials secret of the cluster when the cluster is created or updated using the API.
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	// CredentialsReference references a Secret with the "username" and "password" to authenticate
+	// with using basic auth, prefixed with the key of the reference and a dash if it is set. For
+	// clusters, it can only reference the audit sinks credentials secret of the cluster.
+	CredentialsReference *providerconfig.GlobalSecretKeySelector `json:"credentialsReference,omitempty"`
+}
+
+type AuditS3Sink struct {
+	// Bucket is the name of the bucket audit events are uploaded to.
+	Bucket string `json:"bucket"`
+	// Region is the region of the bucket.
+	Region string `json:"region"`
+	// Endpoint is the URL of an S3 compatible storage. AWS S3 is used if it is empty.
+	Endpoint string `json:"endpoint,omitempty"`
+	// AccessKeyID and SecretAccessKey are the credentials to upload to the bucket with. They are moved
+	// into the credentials secret of the cluster when the cluster is created or updated using the API.
+	AccessKeyID     string `json:"accessKeyId,omitempty"`
+	SecretAccessKey string `json:"secretAccessKey,omitempty"`
+	// CredentialsReference references a Secret with the "accessKeyId" and "secretAccessKey" to
+	// upload to the bucket with, prefixed with the key of the reference and a dash if it is set. For
+	// clusters, it can only reference the audit sinks credentials secret of the cluster.
+	CredentialsReference *providerconfig.GlobalSecretKeySelector `json:"credentialsReference,omitempty"`
+}
+
+// +kubebuilder:validation:Enum="";udp;tcp;tls
+type AuditSyslogProtocol string
+
+const (
+	AuditSyslogProtocolUDP AuditSyslogProtocol = "udp"
+	AuditSyslogProtocolTCP AuditSyslogProtocol = "tcp"
+	AuditSyslogProtocolTLS AuditSyslogProtocol = "tls"
+)
+
+type AuditSyslogSink struct {
+	// Address of the syslog server as host:port.
+	Address string `json:"address"`
+	// Protocol used to send audit events to the syslog server, defaults to tcp.
+	Protocol AuditSyslogProtocol `json:"protocol,omitempty"`
 }
 
 type EventRateLimitConfig struct {
@@ -504,6 +776,37 @@ type EtcdStatefulSetSettings struct {
 	DiskSize     *resource.Quantity           `json:"diskSize,omitempty"`
 	Resources    *corev1.ResourceRequirements `json:"resources,omitempty"`
 	Tolerations  []corev1.Toleration          `json:"tolerations,omitempty"`
+	// QuotaBackendBytes is the space quota of the etcd database. If not set, it is derived from the disk
+	// size, between etcd's default of 2Gi and the recommended maximum of 8Gi. Requires etcd-launcher.
+	QuotaBackendBytes *resource.Quantity `json:"quotaBackendBytes,omitempty"`
+	// Defragmentation configures the automatic defragmentation of etcd members. Requires etcd-launcher.
+	Defragmentation *EtcdDefragmentationSettings `json:"defragmentation,omitempty"`
+}
+
+// GetClusterSize returns the configured number of etcd members, limited to the supported range.
+func (s EtcdStatefulSetSettings) GetClusterSize() int32 {
+	if s.ClusterSize == nil {
+		return DefaultEtcdClusterSize
+	}
+	if *s.ClusterSize < MinEtcdClusterSize {
+		return MinEtcdClusterSize
+	}
+	if *s.ClusterSize > MaxEtcdClusterSize {
+		return MaxEtcdClusterSize
+	}
+	return *s.ClusterSize
+}
+
+// EtcdDefragmentationSettings configures when etcd-launcher defragments the members of the etcd cluster.
+// Members are defragmented one at a time, the leader last.
+type EtcdDefragmentationSettings struct {
+	// Disabled turns off the automatic defragmentation.
+	Disabled bool `json:"disabled,omitempty"`
+	// FragmentationThresholdPercent is the share of the database file that has to be unused before
+	// a member is defragmented. Defaults to 50.
+	FragmentationThresholdPercent *int32 `json:"fragmentationThresholdPercent,omitempty"`
+	// MinDBSize is the database size below which members are not defragmented. Defaults to 100Mi.
+	MinDBSize *resource.Quantity `json:"minDBSize,omitempty"`
 }
 
 type LeaderElectionSettings struct {
@@ -635,6 +938,12 @@ type DigitaloceanCloudSpec struct {
 	CredentialsReference *providerconfig.GlobalSecretKeySelector `json:"credentialsReference,omitempty"`
 
 	Token string `json:"token,omitempty"` // Token is used to authenticate with the DigitalOcean API.
+	// FirewallID is the ID of the cloud firewall applied to the droplets of the cluster.
+	// If set by the user, the firewall is used as-is and not managed by Kubermatic.
+	FirewallID string `json:"firewallID,omitempty"`
+	// NodePortsAllowedIPRange is the range of addresses allowed to access the cluster nodeports.
+	// If not specified, only the seed's nodeport-proxy and konnectivity address of the cluster is allowed.
+	NodePortsAllowedIPRange string `json:"nodePortsAllowedIPRange,omitempty"`
 }
 
 // HetznerCloudSpec specifies access data to hetzner cloud.
@@ -643,11 +952,15 @@ type HetznerCloudSpec struct {
 
 	// Token is used to authenticate with the Hetzner cloud API.
 	Token string `json:"token,omitempty"`
-	// Network is the pre-existing Hetzner network in which the machines are running.
+	// Network is the Hetzner network in which the machines are running.
 	// While machines can be in multiple networks, a single one must be chosen for the
 	// HCloud CCM to work.
-	// If this is empty, the network configured on the datacenter will be used.
+	// If this is empty, the network configured on the datacenter will be used. If neither
+	// is set, a network owned by the cluster is created.
 	Network string `json:"network,omitempty"`
+	// Firewall is the Hetzner Cloud firewall applied to the machines. If this is empty,
+	// a firewall owned by the cluster is created.
+	Firewall string `json:"firewall,omitempty"`
 }
 
 // AzureCloudSpec specifies access credentials to Azure cloud.
@@ -816,6 +1129,9 @@ type KubevirtCloudSpec struct {
 	CredentialsReference *providerconfig.GlobalSecretKeySelector `json:"credentialsReference,omitempty"`
 
 	Kubeconfig string `json:"kubeconfig,omitempty"`
+	// Namespace is the namespace created for the cluster in the KubeVirt infra cluster, which holds
+	// its virtual machines. Machine-controller and the CCM are restricted to this namespace.
+	Namespace string `json:"namespace,omitempty"`
 }
 
 // AlibabaCloudSpec specifies the access data to Alibaba.
@@ -915,6 +1231,13 @@ func NewBytes(b64 string) Bytes {
 	return Bytes(bs)
 }
 
+// IsClonePending returns true if the cluster has been created from the etcd backup of
+// another cluster and has not yet been detached from the resources of that cluster.
+func (cluster *Cluster) IsClonePending() bool {
+	_, ok := cluster.Annotations[CloneRequestAnnotation]
+	return ok
+}
+
 func (cluster *Cluster) GetSecretName() string {
 	if cluster.Spec.Cloud.AWS != nil {
 		return fmt.Sprintf("%s-aws-%s", CredentialPrefix, cluster.Name)
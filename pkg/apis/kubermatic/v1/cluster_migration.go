@@ -0,0 +1,142 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+const (
+	// ClusterMigrationResourceName represents "Resource" defined in Kubernetes
+	ClusterMigrationResourceName = "clustermigrations"
+
+	// ClusterMigrationKindName represents "Kind" defined in Kubernetes
+	ClusterMigrationKindName = "ClusterMigration"
+
+	// ClusterMigrationPhasePending indicates that the migration has not been started yet.
+	ClusterMigrationPhasePending ClusterMigrationPhase = "Pending"
+
+	// ClusterMigrationPhaseBackingUp indicates that the source control plane has been frozen
+	// and its etcd is being backed up.
+	ClusterMigrationPhaseBackingUp ClusterMigrationPhase = "BackingUp"
+
+	// ClusterMigrationPhaseProvisioning indicates that the cluster is being created on the target seed.
+	ClusterMigrationPhaseProvisioning ClusterMigrationPhase = "Provisioning"
+
+	// ClusterMigrationPhaseRestoring indicates that the backup is being restored into the target control plane.
+	ClusterMigrationPhaseRestoring ClusterMigrationPhase = "Restoring"
+
+	// ClusterMigrationPhaseCleaningUp indicates that the source control plane is being torn down.
+	ClusterMigrationPhaseCleaningUp ClusterMigrationPhase = "CleaningUp"
+
+	// ClusterMigrationPhaseCompleted indicates that the cluster has been moved to the target seed.
+	ClusterMigrationPhaseCompleted ClusterMigrationPhase = "Completed"
+
+	// ClusterMigrationPhaseFailed indicates that the migration failed. The source control plane
+	// is resumed if the failure happened before the target control plane took over.
+	ClusterMigrationPhaseFailed ClusterMigrationPhase = "Failed"
+)
+
+// +kubebuilder:validation:Enum=Pending;BackingUp;Provisioning;Restoring;CleaningUp;Completed;Failed
+
+// ClusterMigrationPhase represents the lifecycle phase of a ClusterMigration.
+type ClusterMigrationPhase string
+
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:JSONPath=".spec.clusterName",name="Cluster",type="string"
+// +kubebuilder:printcolumn:JSONPath=".spec.sourceSeed",name="Source",type="string"
+// +kubebuilder:printcolumn:JSONPath=".spec.targetSeed",name="Target",type="string"
+// +kubebuilder:printcolumn:JSONPath=".status.phase",name="Phase",type="string"
+
+// ClusterMigration moves the control plane of a user cluster from one seed to another.
+type ClusterMigration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterMigrationSpec   `json:"spec,omitempty"`
+	Status ClusterMigrationStatus `json:"status,omitempty"`
+}
+
+// ClusterMigrationSpec specifies details of a cluster migration.
+type ClusterMigrationSpec struct {
+	// ClusterName is the name of the cluster to migrate.
+	ClusterName string `json:"clusterName"`
+	// SourceSeed is the name of the seed currently hosting the control plane of the cluster.
+	SourceSeed string `json:"sourceSeed"`
+	// TargetSeed is the name of the seed that the control plane will be moved to.
+	TargetSeed string `json:"targetSeed"`
+	// TargetDatacenter optionally overrides the datacenter of the cluster. It must belong
+	// to the target seed. If empty, the datacenter of the cluster is kept, which requires
+	// the target seed to define a datacenter with the same name.
+	TargetDatacenter string `json:"targetDatacenter,omitempty"`
+	// Destination is the name of the backup destination used to transfer the etcd data.
+	// It must be configured in Spec.EtcdBackupRestore of both the source and the target seed
+	// and point to the same bucket.
+	Destination string `json:"destination"`
+}
+
+// ClusterMigrationStatus contains the observed state of a cluster migration.
+type ClusterMigrationStatus struct {
+	// Phase is the current phase of the migration.
+	Phase ClusterMigrationPhase `json:"phase,omitempty"`
+	// Message contains details about the current phase, e.g. the reason of a failure.
+	Message string `json:"message,omitempty"`
+	// BackupName is the name of the etcd backup that is used to transfer the cluster state.
+	BackupName string `json:"backupName,omitempty"`
+	// SourceApiserverReplicas is the apiserver replica override of the cluster before its
+	// control plane was frozen. It is restored on the target cluster, or on the source
+	// cluster if the migration fails.
+	SourceApiserverReplicas *int32 `json:"sourceApiserverReplicas,omitempty"`
+	// SourceAddress is the address of the control plane on the source seed.
+	SourceAddress *ClusterMigrationAddress `json:"sourceAddress,omitempty"`
+	// TargetAddress is the address of the control plane on the target seed. DNS records
+	// for the external name of the cluster have to be updated to point to it.
+	TargetAddress *ClusterMigrationAddress `json:"targetAddress,omitempty"`
+	// StartTime is the time the migration was started.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time the migration completed or failed.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// ClusterMigrationAddress is the address under which the control plane of a cluster is reachable on a seed.
+type ClusterMigrationAddress struct {
+	// URL under which the apiserver is available.
+	URL string `json:"url,omitempty"`
+	// ExternalName is the DNS name of the cluster.
+	ExternalName string `json:"externalName,omitempty"`
+	// IP is the external IP under which the apiserver is available.
+	IP string `json:"ip,omitempty"`
+	// Port is the port the apiserver listens on.
+	Port int32 `json:"port,omitempty"`
+}
+
+// IsFinished returns true if the migration has either completed or failed.
+func (m *ClusterMigration) IsFinished() bool {
+	return m.Status.Phase == ClusterMigrationPhaseCompleted || m.Status.Phase == ClusterMigrationPhaseFailed
+}
+
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=true
+
+// ClusterMigrationList is a list of cluster migrations.
+type ClusterMigrationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ClusterMigration `json:"items"`
+}
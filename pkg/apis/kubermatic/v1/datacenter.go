@@ -165,6 +165,9 @@ type SeedSpec struct {
 	BackupRestore *SeedBackupRestoreConfiguration `json:"backupRestore,omitempty"`
 	// EtcdBackupRestore holds the configuration of the automatic etcd backup restores for the Seed
 	EtcdBackupRestore *EtcdBackupRestore `json:"etcdBackupRestore,omitempty"`
+	// AuditPolicies is a library of audit policies (audit.k8s.io/v1 Policies as YAML) by name, which
+	// clusters can select and datacenters can enforce as minimum.
+	AuditPolicies map[string]string `json:"auditPolicies,omitempty"`
 }
 
 // SeedBackupRestoreConfiguration defines the bucket name and endpoint as a backup destination.
@@ -183,14 +186,73 @@ type EtcdBackupRestore struct {
 	Destinations map[string]*BackupDestination `json:"destinations,omitempty"`
 }
 
+// BackupDestinationType is the kind of storage backing a backup destination.
+type BackupDestinationType string
+
+const (
+	// BackupDestinationTypeS3 stores backups in an S3 compatible object storage.
+	BackupDestinationTypeS3 BackupDestinationType = "s3"
+	// BackupDestinationTypeAzureBlob stores backups in an Azure Blob Storage container.
+	BackupDestinationTypeAzureBlob BackupDestinationType = "azureblob"
+	// BackupDestinationTypeGCS stores backups in a Google Cloud Storage bucket.
+	BackupDestinationTypeGCS BackupDestinationType = "gcs"
+	// BackupDestinationTypeFilesystem stores backups in a volume mounted into the backup jobs.
+	BackupDestinationTypeFilesystem BackupDestinationType = "filesystem"
+)
+
 // BackupDestination defines the bucket name and endpoint as a backup destination, and holds reference to the credentials secret.
 type BackupDestination struct {
-	// Endpoint is the API endpoint to use for backup and restore.
-	Endpoint string `json:"endpoint"`
-	// BucketName is the bucket name to use for backup and restore.
+	// Type is the kind of storage backing the destination. Defaults to s3.
+	// +kubebuilder:validation:Enum=s3;azureblob;gcs;filesystem
+	Type BackupDestinationType `json:"type,omitempty"`
+	// Endpoint is the API endpoint to use for backup and restore. For azureblob destinations, it is the
+	// storage endpoint suffix (e.g. core.windows.net) and defaults to the public Azure cloud. It is ignored
+	// for gcs and filesystem destinations.
+	Endpoint string `json:"endpoint,omitempty"`
+	// BucketName is the bucket name to use for backup and restore. For azureblob destinations, it is the
+	// name of the container, for filesystem destinations the directory in the volume backups are stored in.
 	BucketName string `json:"bucketName"`
-	// Credentials hold the ref to the secret with backup credentials
+	// Credentials hold the ref to the secret with backup credentials. The keys of the secret depend on the
+	// type of the destination: ACCESS_KEY_ID and SECRET_ACCESS_KEY for s3, AZURE_STORAGE_ACCOUNT and
+	// AZURE_STORAGE_KEY for azureblob and GOOGLE_SERVICE_ACCOUNT (a service account key in JSON format) for gcs.
+	// Filesystem destinations do not need credentials.
 	Credentials *corev1.SecretReference `json:"credentials,omitempty"`
+	// Filesystem configures the volume backups are stored in. Required for filesystem destinations.
+	Filesystem *FilesystemBackupDestination `json:"filesystem,omitempty"`
+	// Encryption configures the client-side encryption of backups before they are
+	// uploaded to this destination. If not set, backups are stored unencrypted.
+	Encryption *BackupEncryption `json:"encryption,omitempty"`
+}
+
+// BackupEncryption configures the client-side encryption of etcd backups.
+type BackupEncryption struct {
+	// KeySecret references the secret holding the encryption keys. Every entry of
+	// the secret is a base64 encoded 256 bit AES key (e.g. generated using
+	// `openssl rand -base64 32`), the entry name is used as the key ID.
+	KeySecret *corev1.SecretReference `json:"keySecret"`
+	// ActiveKey is the ID of the key used to encrypt new backups. To rotate keys,
+	// add a new key to the secret and point ActiveKey to it; existing backups can
+	// be restored as long as the key they were encrypted with remains in the secret.
+	ActiveKey string `json:"activeKey"`
+}
+
+// FilesystemBackupDestination configures the volume backing a filesystem backup destination. Exactly one
+// of NFS and PersistentVolumeClaim must be set.
+type FilesystemBackupDestination struct {
+	// NFS is an NFS export backups are stored in.
+	NFS *corev1.NFSVolumeSource `json:"nfs,omitempty"`
+	// PersistentVolumeClaim is the name of a ReadWriteMany claim backups are stored in. As claims are
+	// namespaced, backup jobs use the claim in the kube-system namespace and restores use a claim of the
+	// same name in the cluster namespace, both need to be bound to the same storage.
+	PersistentVolumeClaim string `json:"persistentVolumeClaim,omitempty"`
+}
+
+// GetType returns the type of the backup destination, defaulting to s3.
+func (d *BackupDestination) GetType() BackupDestinationType {
+	if d.Type == "" {
+		return BackupDestinationTypeS3
+	}
+	return d.Type
 }
 
 type NodeportProxyConfig struct {
@@ -201,6 +263,11 @@ type NodeportProxyConfig struct {
 	// Annotations are used to further tweak the LoadBalancer integration with the
 	// cloud provider where the seed cluster is running.
 	Annotations map[string]string `json:"annotations,omitempty"`
+	// PreserveSourceIPs routes the traffic of the LoadBalancer only to nodes running
+	// Envoy, so that the client IPs are preserved. This is required to restrict the
+	// access to clusters exposed via the nodeport-proxy to allowed IP ranges and must
+	// only be enabled if the LoadBalancer does not proxy the connections itself.
+	PreserveSourceIPs bool `json:"preserveSourceIPs,omitempty"`
 	// Envoy configures the Envoy application itself.
 	Envoy NodeportProxyComponent `json:"envoy,omitempty"`
 	// EnvoyManager configures the Kubermatic-internal Envoy manager.
@@ -260,10 +327,18 @@ type DatacenterSpec struct {
 	// exactly (i.e. "example.com" will not match "user@test.example.com").
 	RequiredEmails []string `json:"requiredEmails,omitempty"`
 
-	// EnforceAuditLogging enforces audit logging on every cluster within the DC,
-	// ignoring cluster-specific settings.
+	// EnforceAuditLogging enforces audit logging on every cluster within the DC.
 	EnforceAuditLogging bool `json:"enforceAuditLogging,omitempty"`
 
+	// MinimumAuditPolicy is the name of an audit policy of the seed which is enforced on every cluster
+	// within the DC if audit logging is enforced. Requests are logged at the higher of the levels this
+	// policy and the audit policy of the cluster select for them.
+	MinimumAuditPolicy string `json:"minimumAuditPolicy,omitempty"`
+
+	// DefaultAuditSinks are the audit sinks of clusters within the DC that do not configure audit
+	// sinks themselves.
+	DefaultAuditSinks []AuditSink `json:"defaultAuditSinks,omitempty"`
+
 	// EnforcePodSecurityPolicy enforces pod security policy plugin on every clusters within the DC,
 	// ignoring cluster-specific settings
 	EnforcePodSecurityPolicy bool `json:"enforcePodSecurityPolicy,omitempty"`
@@ -414,6 +489,10 @@ type DatacenterSpecGCP struct {
 	// zones in your chosen region.
 	ZoneSuffixes []string `json:"zoneSuffixes"`
 
+	// Optional: DedicatedNetwork creates a VPC network with a single subnetwork in the region
+	// for every cluster that does not specify a network, instead of using the default network.
+	DedicatedNetwork bool `json:"dedicatedNetwork,omitempty"`
+
 	// Optional: Regional clusters spread their resources across multiple availability zones.
 	// Refer to the official documentation for more details on this:
 	// https://cloud.google.com/kubernetes-engine/docs/concepts/regional-clusters
@@ -438,6 +517,10 @@ type DatacenterSpecKubevirt struct {
 	// DNSConfig represents the DNS parameters of a pod. Parameters specified here will be merged to the generated DNS
 	// configuration based on DNSPolicy.
 	DNSConfig *corev1.PodDNSConfig `json:"dnsConfig,omitempty"`
+
+	// Optional: ResourceQuota limits the resources of the namespace created in the KubeVirt infra cluster
+	// for every user cluster, for example "requests.cpu" or "persistentvolumeclaims".
+	ResourceQuota corev1.ResourceList `json:"resourceQuota,omitempty"`
 }
 
 // DatacenterSpecAlibaba describes a alibaba datacenter.
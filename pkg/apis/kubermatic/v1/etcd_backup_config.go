@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -31,6 +33,9 @@ const (
 	DefaultKeptBackupsCount = 20
 	MaxKeptBackupsCount     = 50
 
+	DefaultContinuousBackupFlushInterval = 1 * time.Minute
+	DefaultContinuousBackupRetention     = 7 * 24 * time.Hour
+
 	// BackupStatusPhase value indicating that the corresponding job has started
 	BackupStatusPhaseRunning = "Running"
 
@@ -69,9 +74,81 @@ type EtcdBackupConfigSpec struct {
 	// Keep is the number of backups to keep around before deleting the oldest one
 	// If not set, defaults to DefaultKeptBackupsCount. Only used if Schedule is set.
 	Keep *int `json:"keep,omitempty"`
+	// Retention configures a grandfather-father-son retention policy. If set, a completed backup is kept
+	// as long as it is selected by the policy or is one of the last Keep backups; Keep does not default to
+	// DefaultKeptBackupsCount in this case. Only used if Schedule is set.
+	Retention *EtcdBackupRetention `json:"retention,omitempty"`
 	// Destination indicates where the backup will be stored. The destination name should correspond to a destination in
 	// the cluster's Seed.Spec.EtcdBackupRestore. If empty, it will use the legacy destination in Seed.Spec.BackupRestore
 	Destination string `json:"destination,omitempty"`
+	// Continuous configures shipping of incremental etcd revisions to the backup destination between
+	// two snapshots. Together with a snapshot, the shipped revisions allow restoring etcd to a point in time.
+	Continuous *EtcdContinuousBackup `json:"continuous,omitempty"`
+	// Verification configures an integrity check of every backup after it has been uploaded.
+	Verification *EtcdBackupVerification `json:"verification,omitempty"`
+	// Replicas are additional destinations every completed backup is copied to. If verification is
+	// enabled, only verified backups are replicated. Restores can fall back to a replica if the backup
+	// is no longer available in Destination.
+	Replicas []EtcdBackupReplica `json:"replicas,omitempty"`
+}
+
+// EtcdBackupReplica configures a destination backups are replicated to.
+type EtcdBackupReplica struct {
+	// Destination is the name of a destination in the cluster's Seed.Spec.EtcdBackupRestore. It must
+	// differ from the destination of the EtcdBackupConfig and of all other replicas.
+	Destination string `json:"destination"`
+	// Keep is the number of backups to keep in this destination. If neither Keep nor Retention are set,
+	// the Keep and Retention of the EtcdBackupConfig are used.
+	Keep *int `json:"keep,omitempty"`
+	// Retention configures a grandfather-father-son retention policy for this destination. If set,
+	// Keep does not default to DefaultKeptBackupsCount.
+	Retention *EtcdBackupRetention `json:"retention,omitempty"`
+}
+
+// EtcdContinuousBackup configures the continuous shipping of etcd revisions.
+type EtcdContinuousBackup struct {
+	// Enabled starts a revision shipper for the cluster which watches etcd and uploads all
+	// changes to the backup destination. Only one EtcdBackupConfig per cluster and destination
+	// should enable continuous backups.
+	Enabled bool `json:"enabled"`
+	// FlushInterval is the maximum amount of time changes are buffered before being uploaded.
+	// If not set, defaults to DefaultContinuousBackupFlushInterval.
+	FlushInterval *metav1.Duration `json:"flushInterval,omitempty"`
+	// Retention is the amount of time shipped revisions are kept before being deleted.
+	// If not set, defaults to DefaultContinuousBackupRetention.
+	Retention *metav1.Duration `json:"retention,omitempty"`
+}
+
+// EtcdBackupRetention configures a grandfather-father-son retention policy. For every period type, the
+// newest backup of each of the given number of most recent periods that contain a backup is kept.
+// Periods are evaluated in UTC, weeks start on Monday. The most recent backup is always kept.
+type EtcdBackupRetention struct {
+	// Hourly is the number of hours for which the newest backup is kept.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=50
+	Hourly int `json:"hourly,omitempty"`
+	// Daily is the number of days for which the newest backup is kept.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=50
+	Daily int `json:"daily,omitempty"`
+	// Weekly is the number of weeks for which the newest backup is kept.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=50
+	Weekly int `json:"weekly,omitempty"`
+	// Monthly is the number of months for which the newest backup is kept.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=50
+	Monthly int `json:"monthly,omitempty"`
+}
+
+// EtcdBackupVerification configures the verification of uploaded backups.
+type EtcdBackupVerification struct {
+	// Enabled starts a verification job for every completed backup, which downloads the
+	// snapshot and checks its integrity.
+	Enabled bool `json:"enabled"`
+	// Restore additionally restores the snapshot into a temporary data directory to make
+	// sure it can actually be used for a restore.
+	Restore bool `json:"restore,omitempty"`
 }
 
 // +kubebuilder:object:generate=true
@@ -110,6 +187,33 @@ type BackupStatus struct {
 	DeleteFinishedTime *metav1.Time      `json:"deleteFinishedTime,omitempty"`
 	DeletePhase        BackupStatusPhase `json:"deletePhase,omitempty"`
 	DeleteMessage      string            `json:"deleteMessage,omitempty"`
+	// VerificationJobName is only set if verification is enabled for the EtcdBackupConfig.
+	VerificationJobName      string            `json:"verificationJobName,omitempty"`
+	VerificationFinishedTime *metav1.Time      `json:"verificationFinishedTime,omitempty"`
+	VerificationPhase        BackupStatusPhase `json:"verificationPhase,omitempty"`
+	VerificationMessage      string            `json:"verificationMessage,omitempty"`
+	// SnapshotRevision is the etcd revision of the snapshot, as determined by the verification.
+	SnapshotRevision int64 `json:"snapshotRevision,omitempty"`
+	// SnapshotKeyCount is the number of keys in the snapshot, as determined by the verification.
+	SnapshotKeyCount int64 `json:"snapshotKeyCount,omitempty"`
+	// SnapshotHash is the hash of the snapshot's keyspace, as determined by the verification.
+	SnapshotHash string `json:"snapshotHash,omitempty"`
+	// Replicas tracks the replication of the backup to each of the replica destinations.
+	Replicas []BackupReplicaStatus `json:"replicas,omitempty"`
+}
+
+// BackupReplicaStatus tracks the upload and deletion of a backup in a replica destination.
+type BackupReplicaStatus struct {
+	// Destination is the name of the replica destination.
+	Destination        string            `json:"destination"`
+	JobName            string            `json:"jobName,omitempty"`
+	BackupFinishedTime *metav1.Time      `json:"backupFinishedTime,omitempty"`
+	BackupPhase        BackupStatusPhase `json:"backupPhase,omitempty"`
+	BackupMessage      string            `json:"backupMessage,omitempty"`
+	DeleteJobName      string            `json:"deleteJobName,omitempty"`
+	DeleteFinishedTime *metav1.Time      `json:"deleteFinishedTime,omitempty"`
+	DeletePhase        BackupStatusPhase `json:"deletePhase,omitempty"`
+	DeleteMessage      string            `json:"deleteMessage,omitempty"`
 }
 
 type EtcdBackupConfigCondition struct {
@@ -145,14 +249,49 @@ const (
 )
 
 func (bc *EtcdBackupConfig) GetKeptBackupsCount() int {
-	if bc.Spec.Keep == nil {
+	return keptBackupsCount(bc.Spec.Keep)
+}
+
+// GetKeptBackupsCount returns the number of backups to keep in the replica destination.
+func (r *EtcdBackupReplica) GetKeptBackupsCount() int {
+	return keptBackupsCount(r.Keep)
+}
+
+func keptBackupsCount(keep *int) int {
+	if keep == nil {
 		return DefaultKeptBackupsCount
 	}
-	if *bc.Spec.Keep <= 0 {
+	if *keep <= 0 {
 		return 1
 	}
-	if *bc.Spec.Keep > MaxKeptBackupsCount {
+	if *keep > MaxKeptBackupsCount {
 		return MaxKeptBackupsCount
 	}
-	return *bc.Spec.Keep
+	return *keep
+}
+
+// IsContinuousBackupEnabled returns true if etcd revisions should be shipped between snapshots.
+func (bc *EtcdBackupConfig) IsContinuousBackupEnabled() bool {
+	return bc.Spec.Continuous != nil && bc.Spec.Continuous.Enabled
+}
+
+// IsVerificationEnabled returns true if uploaded backups should be verified.
+func (bc *EtcdBackupConfig) IsVerificationEnabled() bool {
+	return bc.Spec.Verification != nil && bc.Spec.Verification.Enabled
+}
+
+// GetContinuousBackupFlushInterval returns the interval after which buffered revisions are uploaded.
+func (bc *EtcdBackupConfig) GetContinuousBackupFlushInterval() time.Duration {
+	if bc.Spec.Continuous == nil || bc.Spec.Continuous.FlushInterval == nil || bc.Spec.Continuous.FlushInterval.Duration <= 0 {
+		return DefaultContinuousBackupFlushInterval
+	}
+	return bc.Spec.Continuous.FlushInterval.Duration
+}
+
+// GetContinuousBackupRetention returns the amount of time shipped revisions are kept.
+func (bc *EtcdBackupConfig) GetContinuousBackupRetention() time.Duration {
+	if bc.Spec.Continuous == nil || bc.Spec.Continuous.Retention == nil || bc.Spec.Continuous.Retention.Duration <= 0 {
+		return DefaultContinuousBackupRetention
+	}
+	return bc.Spec.Continuous.Retention.Duration
 }
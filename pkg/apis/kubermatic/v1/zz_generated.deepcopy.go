@@ -647,9 +647,36 @@ func (in *AnexiaCloudSpec) DeepCopy() *AnexiaCloudSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditHTTPSink) DeepCopyInto(out *AuditHTTPSink) {
+	*out = *in
+	if in.CredentialsReference != nil {
+		in, out := &in.CredentialsReference, &out.CredentialsReference
+		*out = new(types.GlobalSecretKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditHTTPSink.
+func (in *AuditHTTPSink) DeepCopy() *AuditHTTPSink {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditHTTPSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuditLoggingSettings) DeepCopyInto(out *AuditLoggingSettings) {
 	*out = *in
+	if in.Sinks != nil {
+		in, out := &in.Sinks, &out.Sinks
+		*out = make([]AuditSink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditLoggingSettings.
@@ -662,6 +689,121 @@ func (in *AuditLoggingSettings) DeepCopy() *AuditLoggingSettings {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditLokiSink) DeepCopyInto(out *AuditLokiSink) {
+	*out = *in
+	if in.CredentialsReference != nil {
+		in, out := &in.CredentialsReference, &out.CredentialsReference
+		*out = new(types.GlobalSecretKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditLokiSink.
+func (in *AuditLokiSink) DeepCopy() *AuditLokiSink {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditLokiSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditS3Sink) DeepCopyInto(out *AuditS3Sink) {
+	*out = *in
+	if in.CredentialsReference != nil {
+		in, out := &in.CredentialsReference, &out.CredentialsReference
+		*out = new(types.GlobalSecretKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditS3Sink.
+func (in *AuditS3Sink) DeepCopy() *AuditS3Sink {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditS3Sink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditSink) DeepCopyInto(out *AuditSink) {
+	*out = *in
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(AuditWebhookSink)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(AuditHTTPSink)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Loki != nil {
+		in, out := &in.Loki, &out.Loki
+		*out = new(AuditLokiSink)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(AuditS3Sink)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Syslog != nil {
+		in, out := &in.Syslog, &out.Syslog
+		*out = new(AuditSyslogSink)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditSink.
+func (in *AuditSink) DeepCopy() *AuditSink {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditSyslogSink) DeepCopyInto(out *AuditSyslogSink) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditSyslogSink.
+func (in *AuditSyslogSink) DeepCopy() *AuditSyslogSink {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditSyslogSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditWebhookSink) DeepCopyInto(out *AuditWebhookSink) {
+	*out = *in
+	if in.CredentialsReference != nil {
+		in, out := &in.CredentialsReference, &out.CredentialsReference
+		*out = new(types.GlobalSecretKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditWebhookSink.
+func (in *AuditWebhookSink) DeepCopy() *AuditWebhookSink {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditWebhookSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Azure) DeepCopyInto(out *Azure) {
 	*out = *in
@@ -711,6 +853,16 @@ func (in *BackupDestination) DeepCopyInto(out *BackupDestination) {
 		*out = new(corev1.SecretReference)
 		**out = **in
 	}
+	if in.Filesystem != nil {
+		in, out := &in.Filesystem, &out.Filesystem
+		*out = new(FilesystemBackupDestination)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(BackupEncryption)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupDestination.
@@ -723,6 +875,49 @@ func (in *BackupDestination) DeepCopy() *BackupDestination {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupEncryption) DeepCopyInto(out *BackupEncryption) {
+	*out = *in
+	if in.KeySecret != nil {
+		in, out := &in.KeySecret, &out.KeySecret
+		*out = new(corev1.SecretReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupEncryption.
+func (in *BackupEncryption) DeepCopy() *BackupEncryption {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupEncryption)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupReplicaStatus) DeepCopyInto(out *BackupReplicaStatus) {
+	*out = *in
+	if in.BackupFinishedTime != nil {
+		in, out := &in.BackupFinishedTime, &out.BackupFinishedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.DeleteFinishedTime != nil {
+		in, out := &in.DeleteFinishedTime, &out.DeleteFinishedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupReplicaStatus.
+func (in *BackupReplicaStatus) DeepCopy() *BackupReplicaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupReplicaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupStatus) DeepCopyInto(out *BackupStatus) {
 	*out = *in
@@ -746,6 +941,17 @@ func (in *BackupStatus) DeepCopyInto(out *BackupStatus) {
 		in, out := &in.DeleteFinishedTime, &out.DeleteFinishedTime
 		*out = (*in).DeepCopy()
 	}
+	if in.VerificationFinishedTime != nil {
+		in, out := &in.VerificationFinishedTime, &out.VerificationFinishedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make([]BackupReplicaStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupStatus.
@@ -807,6 +1013,42 @@ func (in *CNIPluginSettings) DeepCopy() *CNIPluginSettings {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateExpiry) DeepCopyInto(out *CertificateExpiry) {
+	*out = *in
+	in.NotAfter.DeepCopyInto(&out.NotAfter)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateExpiry.
+func (in *CertificateExpiry) DeepCopy() *CertificateExpiry {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateExpiry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateRotationSettings) DeepCopyInto(out *CertificateRotationSettings) {
+	*out = *in
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateRotationSettings.
+func (in *CertificateRotationSettings) DeepCopy() *CertificateRotationSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateRotationSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CleanupOptions) DeepCopyInto(out *CleanupOptions) {
 	*out = *in
@@ -945,6 +1187,28 @@ func (in *ClusterAddress) DeepCopy() *ClusterAddress {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterCertificatesStatus) DeepCopyInto(out *ClusterCertificatesStatus) {
+	*out = *in
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = make([]CertificateExpiry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCertificatesStatus.
+func (in *ClusterCertificatesStatus) DeepCopy() *ClusterCertificatesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCertificatesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterCondition) DeepCopyInto(out *ClusterCondition) {
 	*out = *in
@@ -962,6 +1226,33 @@ func (in *ClusterCondition) DeepCopy() *ClusterCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterEncryptionStatus) DeepCopyInto(out *ClusterEncryptionStatus) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]EncryptionKey, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EncryptedResources != nil {
+		in, out := &in.EncryptedResources, &out.EncryptedResources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterEncryptionStatus.
+func (in *ClusterEncryptionStatus) DeepCopy() *ClusterEncryptionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterEncryptionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterList) DeepCopyInto(out *ClusterList) {
 	*out = *in
@@ -994,6 +1285,133 @@ func (in *ClusterList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterMigration) DeepCopyInto(out *ClusterMigration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterMigration.
+func (in *ClusterMigration) DeepCopy() *ClusterMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterMigration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterMigrationAddress) DeepCopyInto(out *ClusterMigrationAddress) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterMigrationAddress.
+func (in *ClusterMigrationAddress) DeepCopy() *ClusterMigrationAddress {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterMigrationAddress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterMigrationList) DeepCopyInto(out *ClusterMigrationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterMigration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterMigrationList.
+func (in *ClusterMigrationList) DeepCopy() *ClusterMigrationList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterMigrationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterMigrationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterMigrationSpec) DeepCopyInto(out *ClusterMigrationSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterMigrationSpec.
+func (in *ClusterMigrationSpec) DeepCopy() *ClusterMigrationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterMigrationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterMigrationStatus) DeepCopyInto(out *ClusterMigrationStatus) {
+	*out = *in
+	if in.SourceApiserverReplicas != nil {
+		in, out := &in.SourceApiserverReplicas, &out.SourceApiserverReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SourceAddress != nil {
+		in, out := &in.SourceAddress, &out.SourceAddress
+		*out = new(ClusterMigrationAddress)
+		**out = **in
+	}
+	if in.TargetAddress != nil {
+		in, out := &in.TargetAddress, &out.TargetAddress
+		*out = new(ClusterMigrationAddress)
+		**out = **in
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterMigrationStatus.
+func (in *ClusterMigrationStatus) DeepCopy() *ClusterMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterNetworkingConfig) DeepCopyInto(out *ClusterNetworkingConfig) {
 	*out = *in
@@ -1039,6 +1457,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		}
 	}
 	out.Version = in.Version.DeepCopy()
+	if in.APIServerAllowedIPRanges != nil {
+		in, out := &in.APIServerAllowedIPRanges, &out.APIServerAllowedIPRanges
+		*out = new(NetworkRanges)
+		(*in).DeepCopyInto(*out)
+	}
 	in.ComponentsOverride.DeepCopyInto(&out.ComponentsOverride)
 	out.OIDC = in.OIDC
 	if in.Features != nil {
@@ -1078,7 +1501,7 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 	if in.AuditLogging != nil {
 		in, out := &in.AuditLogging, &out.AuditLogging
 		*out = new(AuditLoggingSettings)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.OPAIntegration != nil {
 		in, out := &in.OPAIntegration, &out.OPAIntegration
@@ -1100,6 +1523,16 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(CNIPluginSettings)
 		**out = **in
 	}
+	if in.EncryptionConfiguration != nil {
+		in, out := &in.EncryptionConfiguration, &out.EncryptionConfiguration
+		*out = new(EncryptionConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CertificateRotation != nil {
+		in, out := &in.CertificateRotation, &out.CertificateRotation
+		*out = new(CertificateRotationSettings)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpec.
@@ -1170,6 +1603,16 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(ClusterEncryptionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = new(ClusterCertificatesStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
@@ -1726,6 +2169,13 @@ func (in *DatacenterSpec) DeepCopyInto(out *DatacenterSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.DefaultAuditSinks != nil {
+		in, out := &in.DefaultAuditSinks, &out.DefaultAuditSinks
+		*out = make([]AuditSink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.ProviderReconciliationInterval != nil {
 		in, out := &in.ProviderReconciliationInterval, &out.ProviderReconciliationInterval
 		*out = new(metav1.Duration)
@@ -1898,6 +2348,13 @@ func (in *DatacenterSpecKubevirt) DeepCopyInto(out *DatacenterSpecKubevirt) {
 		*out = new(corev1.PodDNSConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ResourceQuota != nil {
+		in, out := &in.ResourceQuota, &out.ResourceQuota
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatacenterSpecKubevirt.
@@ -2089,6 +2546,51 @@ func (in *EKS) DeepCopy() *EKS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EncryptionConfiguration) DeepCopyInto(out *EncryptionConfiguration) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.KMS != nil {
+		in, out := &in.KMS, &out.KMS
+		*out = new(KMSEncryptionConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EncryptionConfiguration.
+func (in *EncryptionConfiguration) DeepCopy() *EncryptionConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(EncryptionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EncryptionKey) DeepCopyInto(out *EncryptionKey) {
+	*out = *in
+	if in.KMS != nil {
+		in, out := &in.KMS, &out.KMS
+		*out = new(KMSEncryptionConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EncryptionKey.
+func (in *EncryptionKey) DeepCopy() *EncryptionKey {
+	if in == nil {
+		return nil
+	}
+	out := new(EncryptionKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EtcdBackupConfig) DeepCopyInto(out *EtcdBackupConfig) {
 	*out = *in
@@ -2174,6 +2676,28 @@ func (in *EtcdBackupConfigSpec) DeepCopyInto(out *EtcdBackupConfigSpec) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(EtcdBackupRetention)
+		**out = **in
+	}
+	if in.Continuous != nil {
+		in, out := &in.Continuous, &out.Continuous
+		*out = new(EtcdContinuousBackup)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(EtcdBackupVerification)
+		**out = **in
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make([]EtcdBackupReplica, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdBackupConfigSpec.
@@ -2215,6 +2739,31 @@ func (in *EtcdBackupConfigStatus) DeepCopy() *EtcdBackupConfigStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdBackupReplica) DeepCopyInto(out *EtcdBackupReplica) {
+	*out = *in
+	if in.Keep != nil {
+		in, out := &in.Keep, &out.Keep
+		*out = new(int)
+		**out = **in
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(EtcdBackupRetention)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdBackupReplica.
+func (in *EtcdBackupReplica) DeepCopy() *EtcdBackupReplica {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdBackupReplica)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EtcdBackupRestore) DeepCopyInto(out *EtcdBackupRestore) {
 	*out = *in
@@ -2245,12 +2794,92 @@ func (in *EtcdBackupRestore) DeepCopy() *EtcdBackupRestore {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdBackupRetention) DeepCopyInto(out *EtcdBackupRetention) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdBackupRetention.
+func (in *EtcdBackupRetention) DeepCopy() *EtcdBackupRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdBackupRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdBackupVerification) DeepCopyInto(out *EtcdBackupVerification) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdBackupVerification.
+func (in *EtcdBackupVerification) DeepCopy() *EtcdBackupVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdBackupVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdContinuousBackup) DeepCopyInto(out *EtcdContinuousBackup) {
+	*out = *in
+	if in.FlushInterval != nil {
+		in, out := &in.FlushInterval, &out.FlushInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdContinuousBackup.
+func (in *EtcdContinuousBackup) DeepCopy() *EtcdContinuousBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdContinuousBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdDefragmentationSettings) DeepCopyInto(out *EtcdDefragmentationSettings) {
+	*out = *in
+	if in.FragmentationThresholdPercent != nil {
+		in, out := &in.FragmentationThresholdPercent, &out.FragmentationThresholdPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinDBSize != nil {
+		in, out := &in.MinDBSize, &out.MinDBSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdDefragmentationSettings.
+func (in *EtcdDefragmentationSettings) DeepCopy() *EtcdDefragmentationSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdDefragmentationSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EtcdRestore) DeepCopyInto(out *EtcdRestore) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -2308,6 +2937,15 @@ func (in *EtcdRestoreList) DeepCopyObject() runtime.Object {
 func (in *EtcdRestoreSpec) DeepCopyInto(out *EtcdRestoreSpec) {
 	*out = *in
 	out.Cluster = in.Cluster
+	if in.FallbackDestinations != nil {
+		in, out := &in.FallbackDestinations, &out.FallbackDestinations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RestoreToTime != nil {
+		in, out := &in.RestoreToTime, &out.RestoreToTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdRestoreSpec.
@@ -2364,6 +3002,16 @@ func (in *EtcdStatefulSetSettings) DeepCopyInto(out *EtcdStatefulSetSettings) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.QuotaBackendBytes != nil {
+		in, out := &in.QuotaBackendBytes, &out.QuotaBackendBytes
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Defragmentation != nil {
+		in, out := &in.Defragmentation, &out.Defragmentation
+		*out = new(EtcdDefragmentationSettings)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdStatefulSetSettings.
@@ -2498,6 +3146,7 @@ func (in *ExternalCluster) DeepCopyInto(out *ExternalCluster) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalCluster.
@@ -2568,6 +3217,23 @@ func (in *ExternalClusterCloudSpec) DeepCopy() *ExternalClusterCloudSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalClusterCondition) DeepCopyInto(out *ExternalClusterCondition) {
+	*out = *in
+	in.LastHeartbeatTime.DeepCopyInto(&out.LastHeartbeatTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalClusterCondition.
+func (in *ExternalClusterCondition) DeepCopy() *ExternalClusterCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalClusterCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalClusterEKSCloudSpec) DeepCopyInto(out *ExternalClusterEKSCloudSpec) {
 	*out = *in
@@ -2608,6 +3274,26 @@ func (in *ExternalClusterGKECloudSpec) DeepCopy() *ExternalClusterGKECloudSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalClusterKubeconfigRotation) DeepCopyInto(out *ExternalClusterKubeconfigRotation) {
+	*out = *in
+	if in.TokenValidity != nil {
+		in, out := &in.TokenValidity, &out.TokenValidity
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalClusterKubeconfigRotation.
+func (in *ExternalClusterKubeconfigRotation) DeepCopy() *ExternalClusterKubeconfigRotation {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalClusterKubeconfigRotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalClusterList) DeepCopyInto(out *ExternalClusterList) {
 	*out = *in
@@ -2653,6 +3339,11 @@ func (in *ExternalClusterSpec) DeepCopyInto(out *ExternalClusterSpec) {
 		*out = new(ExternalClusterCloudSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.KubeconfigRotation != nil {
+		in, out := &in.KubeconfigRotation, &out.KubeconfigRotation
+		*out = new(ExternalClusterKubeconfigRotation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalClusterSpec.
@@ -2665,6 +3356,33 @@ func (in *ExternalClusterSpec) DeepCopy() *ExternalClusterSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalClusterStatus) DeepCopyInto(out *ExternalClusterStatus) {
+	*out = *in
+	in.LastSeen.DeepCopyInto(&out.LastSeen)
+	if in.KubeconfigExpiry != nil {
+		in, out := &in.KubeconfigExpiry, &out.KubeconfigExpiry
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ExternalClusterCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalClusterStatus.
+func (in *ExternalClusterStatus) DeepCopy() *ExternalClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Fake) DeepCopyInto(out *Fake) {
 	*out = *in
@@ -2696,6 +3414,26 @@ func (in *FakeCloudSpec) DeepCopy() *FakeCloudSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemBackupDestination) DeepCopyInto(out *FilesystemBackupDestination) {
+	*out = *in
+	if in.NFS != nil {
+		in, out := &in.NFS, &out.NFS
+		*out = new(corev1.NFSVolumeSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemBackupDestination.
+func (in *FilesystemBackupDestination) DeepCopy() *FilesystemBackupDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemBackupDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GCP) DeepCopyInto(out *GCP) {
 	*out = *in
@@ -2855,6 +3593,26 @@ func (in *Incompatibility) DeepCopy() *Incompatibility {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KMSEncryptionConfiguration) DeepCopyInto(out *KMSEncryptionConfiguration) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KMSEncryptionConfiguration.
+func (in *KMSEncryptionConfiguration) DeepCopy() *KMSEncryptionConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(KMSEncryptionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeyCert) DeepCopyInto(out *KeyCert) {
 	*out = *in
@@ -3950,6 +4708,7 @@ func (in *Preset) DeepCopyInto(out *Preset) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Preset.
@@ -3970,6 +4729,36 @@ func (in *Preset) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PresetClusterReference) DeepCopyInto(out *PresetClusterReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PresetClusterReference.
+func (in *PresetClusterReference) DeepCopy() *PresetClusterReference {
+	if in == nil {
+		return nil
+	}
+	out := new(PresetClusterReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PresetExternalSecrets) DeepCopyInto(out *PresetExternalSecrets) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PresetExternalSecrets.
+func (in *PresetExternalSecrets) DeepCopy() *PresetExternalSecrets {
+	if in == nil {
+		return nil
+	}
+	out := new(PresetExternalSecrets)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PresetList) DeepCopyInto(out *PresetList) {
 	*out = *in
@@ -4080,6 +4869,21 @@ func (in *PresetSpec) DeepCopyInto(out *PresetSpec) {
 		*out = new(Fake)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ExternalSecrets != nil {
+		in, out := &in.ExternalSecrets, &out.ExternalSecrets
+		*out = new(PresetExternalSecrets)
+		**out = **in
+	}
+	if in.Projects != nil {
+		in, out := &in.Projects, &out.Projects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProjectSelector != nil {
+		in, out := &in.ProjectSelector, &out.ProjectSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.RequiredEmails != nil {
 		in, out := &in.RequiredEmails, &out.RequiredEmails
 		*out = make([]string, len(*in))
@@ -4102,6 +4906,26 @@ func (in *PresetSpec) DeepCopy() *PresetSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PresetStatus) DeepCopyInto(out *PresetStatus) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]PresetClusterReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PresetStatus.
+func (in *PresetStatus) DeepCopy() *PresetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PresetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Project) DeepCopyInto(out *Project) {
 	*out = *in
@@ -4501,6 +5325,13 @@ func (in *SeedSpec) DeepCopyInto(out *SeedSpec) {
 		*out = new(EtcdBackupRestore)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AuditPolicies != nil {
+		in, out := &in.AuditPolicies, &out.AuditPolicies
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedSpec.
@@ -0,0 +1,153 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupstore
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/storage"
+)
+
+// azureBlockSize is the size of the blocks uploaded blobs are split into, a single
+// put request is limited to 256 MiB, which large snapshots exceed.
+const azureBlockSize = 8 * 1024 * 1024
+
+type azureBlobStore struct {
+	container *storage.Container
+}
+
+func newAzureBlobStore(cfg Config) (*azureBlobStore, error) {
+	if cfg.AzureAccountName == "" || cfg.AzureAccountKey == "" {
+		return nil, errors.New("storage account name and key must be set")
+	}
+
+	baseURL := cfg.Endpoint
+	if baseURL == "" {
+		baseURL = storage.DefaultBaseURL
+	}
+
+	client, err := storage.NewClient(cfg.AzureAccountName, cfg.AzureAccountKey, baseURL, storage.DefaultAPIVersion, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Azure storage client: %w", err)
+	}
+
+	blobService := client.GetBlobService()
+
+	return &azureBlobStore{container: blobService.GetContainerReference(cfg.Bucket)}, nil
+}
+
+func (s *azureBlobStore) EnsureBucket(ctx context.Context) error {
+	_, err := s.container.CreateIfNotExists(nil)
+	return err
+}
+
+func (s *azureBlobStore) Upload(ctx context.Context, name string, r io.Reader, size int64) error {
+	blob := s.container.GetBlobReference(name)
+
+	var blocks []storage.Block
+	chunk := make([]byte, azureBlockSize)
+	for {
+		n, err := io.ReadFull(r, chunk)
+		if n > 0 {
+			// block IDs must have the same length for all blocks of a blob
+			id := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%08d", len(blocks))))
+			if err := blob.PutBlock(id, chunk[:n], nil); err != nil {
+				return fmt.Errorf("failed to upload block %d: %w", len(blocks), err)
+			}
+			blocks = append(blocks, storage.Block{ID: id, Status: storage.BlockStatusUncommitted})
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return blob.PutBlockList(blocks, nil)
+}
+
+func (s *azureBlobStore) Download(ctx context.Context, name string) (io.ReadCloser, error) {
+	r, err := s.container.GetBlobReference(name).Get(nil)
+	if err != nil {
+		return nil, azureError(err)
+	}
+	return r, nil
+}
+
+func (s *azureBlobStore) Stat(ctx context.Context, name string) (*ObjectInfo, error) {
+	blob := s.container.GetBlobReference(name)
+	if err := blob.GetProperties(nil); err != nil {
+		return nil, azureError(err)
+	}
+
+	return &ObjectInfo{
+		Name:         name,
+		Size:         blob.Properties.ContentLength,
+		LastModified: time.Time(blob.Properties.LastModified).UTC(),
+	}, nil
+}
+
+func (s *azureBlobStore) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+
+	params := storage.ListBlobsParameters{Prefix: prefix}
+	for {
+		resp, err := s.container.ListBlobs(params)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, blob := range resp.Blobs {
+			objects = append(objects, ObjectInfo{
+				Name:         blob.Name,
+				Size:         blob.Properties.ContentLength,
+				LastModified: time.Time(blob.Properties.LastModified).UTC(),
+			})
+		}
+
+		if resp.NextMarker == "" {
+			return objects, nil
+		}
+		params.Marker = resp.NextMarker
+	}
+}
+
+func (s *azureBlobStore) Delete(ctx context.Context, name string) error {
+	_, err := s.container.GetBlobReference(name).DeleteIfExists(nil)
+	return err
+}
+
+func azureError(err error) error {
+	var serviceErr storage.AzureStorageServiceError
+	if errors.As(err, &serviceErr) && serviceErr.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+
+	var statusErr storage.UnexpectedStatusCodeError
+	if errors.As(err, &statusErr) && statusErr.Got() == http.StatusNotFound {
+		return ErrNotFound
+	}
+
+	return err
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// filesystemStore stores objects as files in a directory. Slashes in object
+// names are mapped to subdirectories, like the "folders" of object storages.
+type filesystemStore struct {
+	dir string
+}
+
+func newFilesystemStore(cfg Config) (*filesystemStore, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("path must be set")
+	}
+
+	return &filesystemStore{dir: filepath.Join(cfg.Path, cfg.Bucket)}, nil
+}
+
+func (s *filesystemStore) path(name string) (string, error) {
+	if name == "" || path.IsAbs(name) || path.Clean(name) != name || strings.ContainsRune(name, '\\') {
+		return "", fmt.Errorf("invalid object name %q", name)
+	}
+	for _, element := range strings.Split(name, "/") {
+		// also rejects "..", files starting with a dot are temporary uploads
+		if strings.HasPrefix(element, ".") {
+			return "", fmt.Errorf("invalid object name %q", name)
+		}
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(name)), nil
+}
+
+func (s *filesystemStore) EnsureBucket(ctx context.Context) error {
+	return os.MkdirAll(s.dir, 0755)
+}
+
+func (s *filesystemStore) Upload(ctx context.Context, name string, r io.Reader, size int64) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	// write to a temporary file first, so that an interrupted upload never
+	// leaves a truncated object behind
+	tmpFile, err := ioutil.TempFile(dir, ".upload-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := io.Copy(tmpFile, r); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), path)
+}
+
+func (s *filesystemStore) Download(ctx context.Context, name string) (io.ReadCloser, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (s *filesystemStore) Stat(ctx context.Context, name string) (*ObjectInfo, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &ObjectInfo{Name: name, Size: info.Size(), LastModified: info.ModTime().UTC()}, nil
+}
+
+func (s *filesystemStore) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	err := filepath.Walk(s.dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		// skip uploads in progress
+		if strings.HasPrefix(info.Name(), ".") || info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.dir, file)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if strings.HasPrefix(name, prefix) {
+			objects = append(objects, ObjectInfo{Name: name, Size: info.Size(), LastModified: info.ModTime().UTC()})
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+func (s *filesystemStore) Delete(ctx context.Context, name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	storage "google.golang.org/api/storage/v1"
+)
+
+type gcsStore struct {
+	service   *storage.Service
+	bucket    string
+	projectID string
+}
+
+func newGCSStore(ctx context.Context, cfg Config) (*gcsStore, error) {
+	if cfg.GCSServiceAccount == "" {
+		return nil, errors.New("service account must be set")
+	}
+
+	// the project is only needed to create the bucket
+	serviceAccount := struct {
+		ProjectID string `json:"project_id"`
+	}{}
+	if err := json.Unmarshal([]byte(cfg.GCSServiceAccount), &serviceAccount); err != nil {
+		return nil, fmt.Errorf("invalid service account: %w", err)
+	}
+
+	service, err := storage.NewService(ctx, option.WithCredentialsJSON([]byte(cfg.GCSServiceAccount)), option.WithScopes(storage.DevstorageReadWriteScope))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCS client: %w", err)
+	}
+
+	return &gcsStore{service: service, bucket: cfg.Bucket, projectID: serviceAccount.ProjectID}, nil
+}
+
+func (s *gcsStore) EnsureBucket(ctx context.Context) error {
+	_, err := s.service.Buckets.Get(s.bucket).Context(ctx).Do()
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(gcsError(err), ErrNotFound) {
+		return err
+	}
+
+	_, err = s.service.Buckets.Insert(s.projectID, &storage.Bucket{Name: s.bucket}).Context(ctx).Do()
+	return err
+}
+
+func (s *gcsStore) Upload(ctx context.Context, name string, r io.Reader, size int64) error {
+	object := &storage.Object{Name: name, ContentType: "application/octet-stream"}
+	_, err := s.service.Objects.Insert(s.bucket, object).Media(r).Context(ctx).Do()
+	return err
+}
+
+func (s *gcsStore) Download(ctx context.Context, name string) (io.ReadCloser, error) {
+	resp, err := s.service.Objects.Get(s.bucket, name).Context(ctx).Download()
+	if err != nil {
+		return nil, gcsError(err)
+	}
+	return resp.Body, nil
+}
+
+func (s *gcsStore) Stat(ctx context.Context, name string) (*ObjectInfo, error) {
+	object, err := s.service.Objects.Get(s.bucket, name).Context(ctx).Do()
+	if err != nil {
+		return nil, gcsError(err)
+	}
+	return gcsObjectInfo(object)
+}
+
+func (s *gcsStore) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+
+	err := s.service.Objects.List(s.bucket).Prefix(prefix).Pages(ctx, func(page *storage.Objects) error {
+		for _, object := range page.Items {
+			info, err := gcsObjectInfo(object)
+			if err != nil {
+				return err
+			}
+			objects = append(objects, *info)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+func (s *gcsStore) Delete(ctx context.Context, name string) error {
+	err := s.service.Objects.Delete(s.bucket, name).Context(ctx).Do()
+	if errors.Is(gcsError(err), ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+func gcsObjectInfo(object *storage.Object) (*ObjectInfo, error) {
+	updated, err := time.Parse(time.RFC3339, object.Updated)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modification time of object %s: %w", object.Name, err)
+	}
+
+	return &ObjectInfo{Name: object.Name, Size: int64(object.Size), LastModified: updated}, nil
+}
+
+func gcsError(err error) error {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupstore
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// TypeEnvVar is the environment variable the store type is read from if the -type flag is not given.
+const TypeEnvVar = "BACKUP_STORE_TYPE"
+
+// Options exports the store flags shared by the backup tools.
+type Options struct {
+	Type              string
+	Endpoint          string
+	Bucket            string
+	AccessKeyID       string
+	SecretAccessKey   string
+	CABundleFile      string
+	AzureAccountName  string
+	AzureAccountKey   string
+	GCSServiceAccount string
+	Path              string
+}
+
+func NewDefaultOptions() Options {
+	return Options{
+		Bucket: "kubermatic-etcd-backups",
+	}
+}
+
+func (o *Options) AddFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.Type, "type", o.Type, "The kind of backup store, one of s3, azureblob, gcs or filesystem, defaults to the BACKUP_STORE_TYPE environment variable or s3")
+	fs.StringVar(&o.Endpoint, "endpoint", o.Endpoint, "The s3 endpoint, e.G. https://my-s3.com:9000, or the Azure storage endpoint suffix")
+	fs.StringVar(&o.Bucket, "bucket", o.Bucket, "The bucket, Azure container or directory the backups are stored in")
+	fs.StringVar(&o.AccessKeyID, "access-key-id", o.AccessKeyID, "S3 Access key, defaults to the ACCESS_KEY_ID environment variable")
+	fs.StringVar(&o.SecretAccessKey, "secret-access-key", o.SecretAccessKey, "S3 Secret Access Key, defaults to the SECRET_ACCESS_KEY environment variable")
+	fs.StringVar(&o.CABundleFile, "ca-bundle", o.CABundleFile, "Filename of the CA bundle to use for S3 (if not given, default system certificates are used)")
+	fs.StringVar(&o.AzureAccountName, "azure-account-name", o.AzureAccountName, "Azure storage account name, defaults to the AZURE_STORAGE_ACCOUNT environment variable")
+	fs.StringVar(&o.AzureAccountKey, "azure-account-key", o.AzureAccountKey, "Azure storage account key, defaults to the AZURE_STORAGE_KEY environment variable")
+	fs.StringVar(&o.GCSServiceAccount, "gcs-service-account", o.GCSServiceAccount, "Google service account JSON key, defaults to the GOOGLE_SERVICE_ACCOUNT environment variable")
+	fs.StringVar(&o.Path, "path", o.Path, "Directory the filesystem backup store is mounted at")
+}
+
+// Config returns the store config, filling unset credentials from the environment.
+func (o *Options) Config() (Config, error) {
+	cfg := Config{
+		Type:              Type(valueOrEnv(o.Type, TypeEnvVar)),
+		Endpoint:          o.Endpoint,
+		Bucket:            o.Bucket,
+		AccessKeyID:       valueOrEnv(o.AccessKeyID, AccessKeyIDKey),
+		SecretAccessKey:   valueOrEnv(o.SecretAccessKey, SecretAccessKeyKey),
+		AzureAccountName:  valueOrEnv(o.AzureAccountName, AzureAccountNameKey),
+		AzureAccountKey:   valueOrEnv(o.AzureAccountKey, AzureAccountKeyKey),
+		GCSServiceAccount: valueOrEnv(o.GCSServiceAccount, GCSServiceAccountKey),
+		Path:              o.Path,
+	}
+
+	if cfg.Type == "" {
+		cfg.Type = TypeS3
+	}
+	if cfg.Type == TypeS3 && cfg.Endpoint == "" {
+		return cfg, errors.New("'endpoint' must be set for s3 stores")
+	}
+
+	if o.CABundleFile != "" {
+		pem, err := ioutil.ReadFile(o.CABundleFile)
+		if err != nil {
+			return cfg, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return cfg, errors.New("CA bundle does not contain any valid certificates")
+		}
+	}
+
+	return cfg, nil
+}
+
+// Store creates the store described by the options.
+func (o *Options) Store(ctx context.Context) (Store, error) {
+	cfg, err := o.Config()
+	if err != nil {
+		return nil, err
+	}
+	return New(ctx, cfg)
+}
+
+func valueOrEnv(value, envVar string) string {
+	if value != "" {
+		return value
+	}
+	return os.Getenv(envVar)
+}
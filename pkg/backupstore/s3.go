@@ -0,0 +1,143 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupstore
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go"
+)
+
+const defaultS3Endpoint = "s3.amazonaws.com"
+
+type s3Store struct {
+	client *minio.Client
+	bucket string
+}
+
+func newS3Store(cfg Config) (*s3Store, error) {
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, errors.New("access key ID and secret access key must be set")
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = defaultS3Endpoint
+	}
+
+	secure := !strings.HasPrefix(endpoint, "http://")
+	endpoint = strings.TrimPrefix(endpoint, "http://")
+	endpoint = strings.TrimPrefix(endpoint, "https://")
+
+	client, err := minio.New(endpoint, cfg.AccessKeyID, cfg.SecretAccessKey, secure)
+	if err != nil {
+		return nil, err
+	}
+	client.SetAppInfo("kubermatic", "v0.1")
+
+	if cfg.RootCAs != nil {
+		client.SetCustomTransport(&http.Transport{
+			TLSClientConfig:    &tls.Config{RootCAs: cfg.RootCAs},
+			DisableCompression: true,
+		})
+	}
+
+	return &s3Store{client: client, bucket: cfg.Bucket}, nil
+}
+
+// NewS3Store returns a Store for the given bucket, accessed using an already configured client.
+func NewS3Store(client *minio.Client, bucket string) Store {
+	return &s3Store{client: client, bucket: bucket}
+}
+
+func (s *s3Store) EnsureBucket(ctx context.Context) error {
+	exists, err := s.client.BucketExists(s.bucket)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	return s.client.MakeBucket(s.bucket, "")
+}
+
+func (s *s3Store) Upload(ctx context.Context, name string, r io.Reader, size int64) error {
+	_, err := s.client.PutObjectWithContext(ctx, s.bucket, name, r, size, minio.PutObjectOptions{ContentType: "application/octet-stream"})
+	return err
+}
+
+func (s *s3Store) Download(ctx context.Context, name string) (io.ReadCloser, error) {
+	object, err := s.client.GetObjectWithContext(ctx, s.bucket, name, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, s3Error(err)
+	}
+
+	// the object is fetched lazily, so stat it to report missing objects right away
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		return nil, s3Error(err)
+	}
+
+	return object, nil
+}
+
+func (s *s3Store) Stat(ctx context.Context, name string) (*ObjectInfo, error) {
+	info, err := s.client.StatObject(s.bucket, name, minio.StatObjectOptions{})
+	if err != nil {
+		return nil, s3Error(err)
+	}
+
+	return &ObjectInfo{Name: info.Key, Size: info.Size, LastModified: info.LastModified}, nil
+}
+
+func (s *s3Store) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	var objects []ObjectInfo
+	for object := range s.client.ListObjects(s.bucket, prefix, true, doneCh) {
+		if object.Err != nil {
+			return nil, object.Err
+		}
+		objects = append(objects, ObjectInfo{Name: object.Key, Size: object.Size, LastModified: object.LastModified})
+	}
+
+	return objects, nil
+}
+
+func (s *s3Store) Delete(ctx context.Context, name string) error {
+	// S3 does not report deleting missing objects as an error
+	return s.client.RemoveObject(s.bucket, name)
+}
+
+func (s *s3Store) PresignedURL(ctx context.Context, name string, expiry time.Duration) (*url.URL, error) {
+	return s.client.PresignedGetObject(s.bucket, name, expiry, nil)
+}
+
+func s3Error(err error) error {
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backupstore provides access to the storage backing etcd backup destinations,
+// independent of whether it is an S3 compatible object storage, Azure Blob Storage,
+// Google Cloud Storage or a plain filesystem.
+package backupstore
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"time"
+)
+
+// Type is the kind of storage backing a Store.
+type Type string
+
+const (
+	TypeS3         Type = "s3"
+	TypeAzureBlob  Type = "azureblob"
+	TypeGCS        Type = "gcs"
+	TypeFilesystem Type = "filesystem"
+)
+
+const (
+	// AccessKeyIDKey and SecretAccessKeyKey hold the credentials of S3 destinations.
+	AccessKeyIDKey     = "ACCESS_KEY_ID"
+	SecretAccessKeyKey = "SECRET_ACCESS_KEY"
+	// AzureAccountNameKey and AzureAccountKeyKey hold the credentials of Azure Blob Storage destinations.
+	AzureAccountNameKey = "AZURE_STORAGE_ACCOUNT"
+	AzureAccountKeyKey  = "AZURE_STORAGE_KEY"
+	// GCSServiceAccountKey holds the JSON service account key of Google Cloud Storage destinations.
+	GCSServiceAccountKey = "GOOGLE_SERVICE_ACCOUNT"
+)
+
+// ErrNotFound is returned if an object does not exist.
+var ErrNotFound = errors.New("object not found")
+
+// ObjectInfo describes a stored object.
+type ObjectInfo struct {
+	Name         string
+	Size         int64
+	LastModified time.Time
+}
+
+// Store is a bucket (or container, or directory) holding backups.
+type Store interface {
+	// EnsureBucket creates the bucket if it does not exist yet.
+	EnsureBucket(ctx context.Context) error
+	// Upload stores size bytes read from r under the given name, replacing any existing object.
+	Upload(ctx context.Context, name string, r io.Reader, size int64) error
+	// Download returns the content of the object. The caller has to close it.
+	Download(ctx context.Context, name string) (io.ReadCloser, error)
+	// Stat returns information about the object, or ErrNotFound.
+	Stat(ctx context.Context, name string) (*ObjectInfo, error)
+	// List returns all objects whose name starts with the given prefix.
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	// Delete deletes the object. Deleting an object which does not exist is not an error.
+	Delete(ctx context.Context, name string) error
+}
+
+// Presigner is implemented by stores which can hand out time-limited URLs for downloading
+// objects without credentials. Only S3 stores support this.
+type Presigner interface {
+	PresignedURL(ctx context.Context, name string, expiry time.Duration) (*url.URL, error)
+}
+
+// Config configures a Store. Only the fields relevant for the Type need to be set.
+type Config struct {
+	Type Type
+	// Endpoint is the S3 endpoint or the Azure storage endpoint suffix (e.g. core.windows.net).
+	Endpoint string
+	// Bucket is the S3 or GCS bucket, the Azure container or the directory below Path.
+	Bucket string
+
+	AccessKeyID     string
+	SecretAccessKey string
+	// RootCAs are used to verify the S3 endpoint's certificate, the system pool is used if nil.
+	RootCAs *x509.CertPool
+
+	AzureAccountName string
+	AzureAccountKey  string
+
+	GCSServiceAccount string
+
+	// Path is the directory the filesystem destination's volume is mounted at.
+	Path string
+}
+
+// New returns the Store described by the config.
+func New(ctx context.Context, cfg Config) (Store, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("bucket must be set")
+	}
+
+	switch cfg.Type {
+	case "", TypeS3:
+		return newS3Store(cfg)
+	case TypeAzureBlob:
+		return newAzureBlobStore(cfg)
+	case TypeGCS:
+		return newGCSStore(ctx, cfg)
+	case TypeFilesystem:
+		return newFilesystemStore(cfg)
+	default:
+		return nil, fmt.Errorf("unknown backup store type %q", cfg.Type)
+	}
+}
+
+// UploadFile uploads the given file.
+func UploadFile(ctx context.Context, store Store, name, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	return store.Upload(ctx, name, f, info.Size())
+}
+
+// DownloadFile downloads the object into the given file.
+func DownloadFile(ctx context.Context, store Store, name, file string) error {
+	r, err := store.Download(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupstore
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestFilesystemStore(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := New(ctx, Config{Type: TypeFilesystem, Path: t.TempDir(), Bucket: "backups"})
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	if err := store.EnsureBucket(ctx); err != nil {
+		t.Fatalf("failed to create bucket: %v", err)
+	}
+
+	if _, err := store.Stat(ctx, "cluster-a-backup-1"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for a missing object, got %v", err)
+	}
+
+	for _, name := range []string{"cluster-a-backup-1", "cluster-a-backup-2", "cluster-a-revisions/segment-1", "cluster-b-backup-1"} {
+		if err := store.Upload(ctx, name, strings.NewReader(name), int64(len(name))); err != nil {
+			t.Fatalf("failed to upload %s: %v", name, err)
+		}
+	}
+
+	objects, err := store.List(ctx, "cluster-a-")
+	if err != nil {
+		t.Fatalf("failed to list objects: %v", err)
+	}
+	if len(objects) != 3 {
+		t.Fatalf("expected 3 objects of cluster-a, got %v", objects)
+	}
+
+	objects, err = store.List(ctx, "cluster-a-revisions/")
+	if err != nil {
+		t.Fatalf("failed to list objects: %v", err)
+	}
+	if len(objects) != 1 || objects[0].Name != "cluster-a-revisions/segment-1" {
+		t.Fatalf("expected the segment of cluster-a, got %v", objects)
+	}
+
+	r, err := store.Download(ctx, "cluster-a-backup-2")
+	if err != nil {
+		t.Fatalf("failed to download object: %v", err)
+	}
+	content, err := ioutil.ReadAll(r)
+	r.Close()
+	if err != nil {
+		t.Fatalf("failed to read object: %v", err)
+	}
+	if string(content) != "cluster-a-backup-2" {
+		t.Errorf("unexpected object content %q", content)
+	}
+
+	if err := store.Delete(ctx, "cluster-a-backup-2"); err != nil {
+		t.Fatalf("failed to delete object: %v", err)
+	}
+	if err := store.Delete(ctx, "cluster-a-backup-2"); err != nil {
+		t.Fatalf("deleting a missing object must not fail: %v", err)
+	}
+	if _, err := store.Download(ctx, "cluster-a-backup-2"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a deleted object, got %v", err)
+	}
+
+	for _, name := range []string{"../escape", "/absolute", "cluster-a-revisions/../escape", ".upload-1"} {
+		if err := store.Upload(ctx, name, strings.NewReader(""), 0); err == nil {
+			t.Errorf("expected object name %q to be rejected", name)
+		}
+	}
+}
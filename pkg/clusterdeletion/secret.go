@@ -32,8 +32,10 @@ import (
 )
 
 func (d *Deletion) cleanUpCredentialsSecrets(ctx context.Context, cluster *kubermaticv1.Cluster) error {
-	if err := d.deleteSecret(ctx, cluster); err != nil {
-		return err
+	for _, secretName := range []string{cluster.GetSecretName(), cluster.GetAuditSinksSecretName()} {
+		if err := d.deleteSecret(ctx, secretName); err != nil {
+			return err
+		}
 	}
 
 	oldCluster := cluster.DeepCopy()
@@ -41,8 +43,7 @@ func (d *Deletion) cleanUpCredentialsSecrets(ctx context.Context, cluster *kuber
 	return d.seedClient.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster))
 }
 
-func (d *Deletion) deleteSecret(ctx context.Context, cluster *kubermaticv1.Cluster) error {
-	secretName := cluster.GetSecretName()
+func (d *Deletion) deleteSecret(ctx context.Context, secretName string) error {
 	if secretName == "" {
 		return nil
 	}
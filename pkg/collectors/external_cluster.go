@@ -0,0 +1,193 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collectors
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1/helper"
+
+	corev1 "k8s.io/api/core/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	externalClusterPrefix = "kubermatic_external_cluster_"
+)
+
+// ExternalClusterCollector exports metrics for external cluster resources
+type ExternalClusterCollector struct {
+	client ctrlruntimeclient.Reader
+
+	externalClusterCreated   *prometheus.Desc
+	externalClusterDeleted   *prometheus.Desc
+	externalClusterInfo      *prometheus.Desc
+	externalClusterReachable *prometheus.Desc
+	externalClusterNodes     *prometheus.Desc
+	externalClusterLastSeen  *prometheus.Desc
+}
+
+// MustRegisterExternalClusterCollector registers the external cluster collector at the given prometheus registry
+func MustRegisterExternalClusterCollector(registry prometheus.Registerer, client ctrlruntimeclient.Reader) {
+	cc := &ExternalClusterCollector{
+		client: client,
+		externalClusterCreated: prometheus.NewDesc(
+			externalClusterPrefix+"created",
+			"Unix creation timestamp",
+			[]string{"cluster"},
+			nil,
+		),
+		externalClusterDeleted: prometheus.NewDesc(
+			externalClusterPrefix+"deleted",
+			"Unix deletion timestamp",
+			[]string{"cluster"},
+			nil,
+		),
+		externalClusterInfo: prometheus.NewDesc(
+			externalClusterPrefix+"info",
+			"External cluster information like provider or version",
+			[]string{
+				"name",
+				"display_name",
+				"provider",
+				"phase",
+				"version",
+			},
+			nil,
+		),
+		externalClusterReachable: prometheus.NewDesc(
+			externalClusterPrefix+"reachable",
+			"Whether the API server of the cluster is reachable",
+			[]string{"cluster"},
+			nil,
+		),
+		externalClusterNodes: prometheus.NewDesc(
+			externalClusterPrefix+"nodes",
+			"Number of nodes in the cluster",
+			[]string{"cluster"},
+			nil,
+		),
+		externalClusterLastSeen: prometheus.NewDesc(
+			externalClusterPrefix+"last_seen",
+			"Unix timestamp of the last time the API server of the cluster was reachable",
+			[]string{"cluster"},
+			nil,
+		),
+	}
+
+	registry.MustRegister(cc)
+}
+
+// Describe returns the metrics descriptors
+func (cc ExternalClusterCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cc.externalClusterCreated
+	ch <- cc.externalClusterDeleted
+	ch <- cc.externalClusterInfo
+	ch <- cc.externalClusterReachable
+	ch <- cc.externalClusterNodes
+	ch <- cc.externalClusterLastSeen
+}
+
+// Collect gets called by prometheus to collect the metrics
+func (cc ExternalClusterCollector) Collect(ch chan<- prometheus.Metric) {
+	clusters := &kubermaticv1.ExternalClusterList{}
+	if err := cc.client.List(context.Background(), clusters); err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to list external clusters in ExternalClusterCollector: %v", err))
+		return
+	}
+
+	for _, cluster := range clusters.Items {
+		cc.collectExternalCluster(ch, &cluster)
+	}
+}
+
+func (cc *ExternalClusterCollector) collectExternalCluster(ch chan<- prometheus.Metric, c *kubermaticv1.ExternalCluster) {
+	ch <- prometheus.MustNewConstMetric(
+		cc.externalClusterCreated,
+		prometheus.GaugeValue,
+		float64(c.CreationTimestamp.Unix()),
+		c.Name,
+	)
+
+	if c.DeletionTimestamp != nil {
+		ch <- prometheus.MustNewConstMetric(
+			cc.externalClusterDeleted,
+			prometheus.GaugeValue,
+			float64(c.DeletionTimestamp.Unix()),
+			c.Name,
+		)
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		cc.externalClusterInfo,
+		prometheus.GaugeValue,
+		1,
+		c.Name,
+		c.Spec.HumanReadableName,
+		externalClusterProvider(c),
+		string(c.Status.Phase),
+		c.Status.Version,
+	)
+
+	reachable := 0.0
+	if helper.ExternalClusterConditionHasStatus(c, kubermaticv1.ExternalClusterConditionReachable, corev1.ConditionTrue) {
+		reachable = 1
+	}
+	ch <- prometheus.MustNewConstMetric(
+		cc.externalClusterReachable,
+		prometheus.GaugeValue,
+		reachable,
+		c.Name,
+	)
+
+	ch <- prometheus.MustNewConstMetric(
+		cc.externalClusterNodes,
+		prometheus.GaugeValue,
+		float64(c.Status.NodeCount),
+		c.Name,
+	)
+
+	if !c.Status.LastSeen.IsZero() {
+		ch <- prometheus.MustNewConstMetric(
+			cc.externalClusterLastSeen,
+			prometheus.GaugeValue,
+			float64(c.Status.LastSeen.Unix()),
+			c.Name,
+		)
+	}
+}
+
+func externalClusterProvider(cluster *kubermaticv1.ExternalCluster) string {
+	cloud := cluster.Spec.CloudSpec
+	switch {
+	case cloud == nil:
+		return "kubeconfig"
+	case cloud.GKE != nil:
+		return "gke"
+	case cloud.EKS != nil:
+		return "eks"
+	case cloud.AKS != nil:
+		return "aks"
+	default:
+		return ""
+	}
+}
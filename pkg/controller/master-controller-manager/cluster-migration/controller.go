@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustermigration
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/provider"
+
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// ControllerName is the name of this very controller.
+	ControllerName = "cluster-migration-controller"
+
+	// CleanupFinalizer is put on ClusterMigrations so that unfinished migrations
+	// can be rolled back when they are deleted.
+	CleanupFinalizer = "kubermatic.io/cleanup-cluster-migration"
+
+	// MigrationAnnotation is put on the cluster created in the target seed and
+	// contains the name of the ClusterMigration that created it.
+	MigrationAnnotation = "kubermatic.io/cluster-migration"
+)
+
+// Add creates a new cluster migration controller and sets up Watches.
+func Add(
+	ctx context.Context,
+	mgr manager.Manager,
+	numWorkers int,
+	log *zap.SugaredLogger,
+	seedsGetter provider.SeedsGetter,
+	seedKubeconfigGetter provider.SeedKubeconfigGetter,
+) error {
+	reconciler := &Reconciler{
+		Client:           mgr.GetClient(),
+		recorder:         mgr.GetEventRecorderFor(ControllerName),
+		log:              log.Named(ControllerName),
+		seedsGetter:      seedsGetter,
+		seedClientGetter: provider.SeedClientGetterFactory(seedKubeconfigGetter),
+		lookupHost:       net.DefaultResolver.LookupHost,
+	}
+
+	ctrlOptions := controller.Options{Reconciler: reconciler, MaxConcurrentReconciles: numWorkers}
+	c, err := controller.New(ControllerName, mgr, ctrlOptions)
+	if err != nil {
+		return err
+	}
+
+	if err := c.Watch(&source.Kind{Type: &kubermaticv1.ClusterMigration{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return fmt.Errorf("failed to create watcher: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package clustermigration contains a controller that moves the control plane of a user cluster
+from one seed to another. It backs up etcd on the source seed into a backup destination shared
+by both seeds, recreates the cluster with its certificates and keys on the target seed, restores
+the backup there and finally removes the control plane from the source seed.
+
+DNS records are not managed by KKP, so the controller does not update them. The migrated cluster
+keeps its external name through the kubermatic.io/external-name annotation and the record for that
+name has to be pointed to the nodeport-proxy of the target seed by the administrator, as reported
+in the migration status. Until the name resolves to that address, the migration stays in the
+CleaningUp phase and the control plane on the source seed keeps serving the cluster.
+The annotation is never removed by the controller; it has to be kept for as long as nodes and
+kubeconfigs use the old name.
+*/
+package clustermigration
@@ -0,0 +1,553 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustermigration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"time"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/provider"
+	"k8c.io/kubermatic/v2/pkg/resources"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// pollInterval is the interval in which long-running steps like backups
+// and restores are checked.
+const pollInterval = 30 * time.Second
+
+var errSeedNotFound = errors.New("seed not found")
+
+// hostLookupFunc resolves a host name to its addresses.
+type hostLookupFunc func(ctx context.Context, host string) ([]string, error)
+
+// Reconciler moves clusters between seeds. All progress is tracked in the
+// status of the ClusterMigration, so that every phase can safely be retried.
+type Reconciler struct {
+	ctrlruntimeclient.Client
+
+	seedsGetter      provider.SeedsGetter
+	seedClientGetter provider.SeedClientGetter
+	log              *zap.SugaredLogger
+	recorder         record.EventRecorder
+	lookupHost       hostLookupFunc
+}
+
+// migration bundles a ClusterMigration with the seeds involved in it.
+type migration struct {
+	*kubermaticv1.ClusterMigration
+
+	sourceSeed   *kubermaticv1.Seed
+	targetSeed   *kubermaticv1.Seed
+	sourceClient ctrlruntimeclient.Client
+	targetClient ctrlruntimeclient.Client
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	logger := r.log.With("migration", request.Name)
+	logger.Debug("Reconciling")
+
+	clusterMigration := &kubermaticv1.ClusterMigration{}
+	if err := r.Get(ctx, request.NamespacedName, clusterMigration); err != nil {
+		if kerrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+
+		return reconcile.Result{}, fmt.Errorf("failed to get cluster migration: %w", err)
+	}
+
+	logger = logger.With("cluster", clusterMigration.Spec.ClusterName)
+
+	result, err := r.reconcile(ctx, logger, clusterMigration)
+	if err != nil {
+		logger.Errorw("Reconciling failed", zap.Error(err))
+		r.recorder.Event(clusterMigration, corev1.EventTypeWarning, "ReconcilingError", err.Error())
+	}
+
+	if result == nil {
+		result = &reconcile.Result{}
+	}
+	return *result, err
+}
+
+func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, clusterMigration *kubermaticv1.ClusterMigration) (*reconcile.Result, error) {
+	if clusterMigration.DeletionTimestamp != nil {
+		return nil, r.cleanupDeletedMigration(ctx, log, clusterMigration)
+	}
+
+	if clusterMigration.IsFinished() {
+		return nil, nil
+	}
+
+	if err := r.updateMigration(ctx, clusterMigration, func(cm *kubermaticv1.ClusterMigration) {
+		kuberneteshelper.AddFinalizer(cm, CleanupFinalizer)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to add finalizer: %w", err)
+	}
+
+	m, err := r.getMigration(clusterMigration)
+	if err != nil {
+		// a migration that has not started yet can safely be marked as failed,
+		// a running one has to wait for the seed to come back
+		if errors.Is(err, errSeedNotFound) && isPending(clusterMigration) {
+			return r.fail(ctx, log, m, err.Error())
+		}
+
+		return nil, err
+	}
+
+	switch clusterMigration.Status.Phase {
+	case "", kubermaticv1.ClusterMigrationPhasePending:
+		return r.start(ctx, log, m)
+	case kubermaticv1.ClusterMigrationPhaseBackingUp:
+		return r.backup(ctx, log, m)
+	case kubermaticv1.ClusterMigrationPhaseProvisioning:
+		return r.provision(ctx, log, m)
+	case kubermaticv1.ClusterMigrationPhaseRestoring:
+		return r.restore(ctx, log, m)
+	case kubermaticv1.ClusterMigrationPhaseCleaningUp:
+		return r.cleanupSource(ctx, log, m)
+	}
+
+	return nil, fmt.Errorf("unknown migration phase %q", clusterMigration.Status.Phase)
+}
+
+// cleanupDeletedMigration rolls back migrations that are deleted before the target
+// control plane has taken over.
+func (r *Reconciler) cleanupDeletedMigration(ctx context.Context, log *zap.SugaredLogger, clusterMigration *kubermaticv1.ClusterMigration) error {
+	if !kuberneteshelper.HasFinalizer(clusterMigration, CleanupFinalizer) {
+		return nil
+	}
+
+	if needsRollback(clusterMigration) {
+		m, err := r.getMigration(clusterMigration)
+		switch {
+		case errors.Is(err, errSeedNotFound):
+			log.Warnw("Cannot roll back migration", zap.Error(err))
+		case err != nil:
+			return err
+		default:
+			log.Info("Migration was deleted before it finished, rolling back")
+			if err := r.rollback(ctx, log, m); err != nil {
+				return fmt.Errorf("failed to roll back migration: %w", err)
+			}
+		}
+	}
+
+	return r.updateMigration(ctx, clusterMigration, func(cm *kubermaticv1.ClusterMigration) {
+		kuberneteshelper.RemoveFinalizer(cm, CleanupFinalizer)
+	})
+}
+
+func (r *Reconciler) getMigration(clusterMigration *kubermaticv1.ClusterMigration) (*migration, error) {
+	m := &migration{ClusterMigration: clusterMigration}
+
+	seeds, err := r.seedsGetter()
+	if err != nil {
+		return m, fmt.Errorf("failed to get seeds: %w", err)
+	}
+
+	var ok bool
+	if m.sourceSeed, ok = seeds[clusterMigration.Spec.SourceSeed]; !ok {
+		return m, fmt.Errorf("%w: %q", errSeedNotFound, clusterMigration.Spec.SourceSeed)
+	}
+	if m.targetSeed, ok = seeds[clusterMigration.Spec.TargetSeed]; !ok {
+		return m, fmt.Errorf("%w: %q", errSeedNotFound, clusterMigration.Spec.TargetSeed)
+	}
+
+	if m.sourceClient, err = r.seedClientGetter(m.sourceSeed); err != nil {
+		return m, fmt.Errorf("failed to create client for seed %q: %w", m.sourceSeed.Name, err)
+	}
+	if m.targetClient, err = r.seedClientGetter(m.targetSeed); err != nil {
+		return m, fmt.Errorf("failed to create client for seed %q: %w", m.targetSeed.Name, err)
+	}
+
+	return m, nil
+}
+
+// start validates the migration and records the state of the source cluster.
+func (r *Reconciler) start(ctx context.Context, log *zap.SugaredLogger, m *migration) (*reconcile.Result, error) {
+	if m.sourceSeed.Name == m.targetSeed.Name {
+		return r.fail(ctx, log, m, "source and target seed must be different")
+	}
+
+	for _, seed := range []*kubermaticv1.Seed{m.sourceSeed, m.targetSeed} {
+		if _, err := getDestination(seed, m.Spec.Destination); err != nil {
+			return r.fail(ctx, log, m, err.Error())
+		}
+	}
+
+	cluster, err := getCluster(ctx, m.sourceClient, m.Spec.ClusterName)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return r.fail(ctx, log, m, fmt.Sprintf("cluster %q does not exist in seed %q", m.Spec.ClusterName, m.sourceSeed.Name))
+		}
+		return nil, err
+	}
+
+	if cluster.DeletionTimestamp != nil {
+		return r.fail(ctx, log, m, "cluster is being deleted")
+	}
+	if cluster.Spec.Pause {
+		return r.fail(ctx, log, m, "cluster is paused")
+	}
+
+	datacenter := targetDatacenter(m.ClusterMigration, cluster)
+	if _, ok := m.targetSeed.Spec.Datacenters[datacenter]; !ok {
+		return r.fail(ctx, log, m, fmt.Sprintf("datacenter %q does not exist in seed %q", datacenter, m.targetSeed.Name))
+	}
+
+	existing, err := getCluster(ctx, m.targetClient, m.Spec.ClusterName)
+	if err == nil && existing.Annotations[MigrationAnnotation] != m.Name {
+		return r.fail(ctx, log, m, fmt.Sprintf("a cluster named %q already exists in seed %q", m.Spec.ClusterName, m.targetSeed.Name))
+	} else if err != nil && !kerrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	log.Info("Starting migration")
+	r.recorder.Eventf(m.ClusterMigration, corev1.EventTypeNormal, "MigrationStarted", "Freezing control plane in seed %s", m.sourceSeed.Name)
+
+	now := metav1.Now()
+	return nil, r.updateMigration(ctx, m.ClusterMigration, func(cm *kubermaticv1.ClusterMigration) {
+		cm.Status.Phase = kubermaticv1.ClusterMigrationPhaseBackingUp
+		cm.Status.Message = ""
+		cm.Status.StartTime = &now
+		cm.Status.BackupName = backupName(cm)
+		cm.Status.SourceApiserverReplicas = cluster.Spec.ComponentsOverride.Apiserver.Replicas
+		cm.Status.SourceAddress = migrationAddress(cluster.Address)
+	})
+}
+
+// backup freezes the source control plane and takes a backup of its etcd.
+func (r *Reconciler) backup(ctx context.Context, log *zap.SugaredLogger, m *migration) (*reconcile.Result, error) {
+	cluster, err := getCluster(ctx, m.sourceClient, m.Spec.ClusterName)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return r.fail(ctx, log, m, fmt.Sprintf("cluster %q does not exist in seed %q anymore", m.Spec.ClusterName, m.sourceSeed.Name))
+		}
+		return nil, err
+	}
+
+	// no changes must be made to the cluster after the backup has been taken. Pausing the
+	// cluster is not an option, as that would also stop the backup controller, so instead
+	// the apiserver is scaled down.
+	if err := updateCluster(ctx, m.sourceClient, cluster, func(c *kubermaticv1.Cluster) {
+		c.Spec.ComponentsOverride.Apiserver.Replicas = resources.Int32(0)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to scale down apiserver: %w", err)
+	}
+
+	deployment := &appsv1.Deployment{}
+	key := types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: resources.ApiserverDeploymentName}
+	if err := m.sourceClient.Get(ctx, key, deployment); err != nil && !kerrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get apiserver deployment: %w", err)
+	}
+	if deployment.Status.Replicas > 0 {
+		log.Debug("Waiting for apiserver to be scaled down")
+		return &reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	backupConfig, err := ensureBackupConfig(ctx, m, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, backup := range backupConfig.Status.CurrentBackups {
+		if backup.BackupName != m.Status.BackupName {
+			continue
+		}
+
+		switch backup.BackupPhase {
+		case kubermaticv1.BackupStatusPhaseCompleted:
+			log.Infow("Backup completed", "backup", backup.BackupName)
+			return nil, r.updateMigration(ctx, m.ClusterMigration, func(cm *kubermaticv1.ClusterMigration) {
+				cm.Status.Phase = kubermaticv1.ClusterMigrationPhaseProvisioning
+			})
+		case kubermaticv1.BackupStatusPhaseFailed:
+			return r.fail(ctx, log, m, fmt.Sprintf("backup failed: %s", backup.BackupMessage))
+		}
+	}
+
+	log.Debug("Waiting for backup to complete")
+	return &reconcile.Result{RequeueAfter: pollInterval}, nil
+}
+
+// provision creates the cluster in the target seed, together with everything that has to
+// be identical to the source control plane for existing nodes and kubeconfigs to keep working.
+func (r *Reconciler) provision(ctx context.Context, log *zap.SugaredLogger, m *migration) (*reconcile.Result, error) {
+	source, err := getCluster(ctx, m.sourceClient, m.Spec.ClusterName)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return r.fail(ctx, log, m, fmt.Sprintf("cluster %q does not exist in seed %q anymore", m.Spec.ClusterName, m.sourceSeed.Name))
+		}
+		return nil, err
+	}
+
+	target, err := ensureTargetCluster(ctx, m, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cluster in target seed: %w", err)
+	}
+
+	if err := ensureNamespace(ctx, m.targetClient, target); err != nil {
+		return nil, fmt.Errorf("failed to create cluster namespace in target seed: %w", err)
+	}
+
+	for _, name := range migratedSecrets {
+		if err := copySecret(ctx, m, source.Status.NamespaceName, name, ownerRef(target)); err != nil {
+			return nil, fmt.Errorf("failed to copy secret %s: %w", name, err)
+		}
+	}
+
+	for _, name := range []string{source.GetSecretName(), source.GetAuditSinksSecretName()} {
+		if name == "" {
+			continue
+		}
+		if err := copySecret(ctx, m, resources.KubermaticNamespace, name, nil); err != nil {
+			return nil, fmt.Errorf("failed to copy credentials secret: %w", err)
+		}
+	}
+
+	if err := copyCABundle(ctx, m, source, target); err != nil {
+		return nil, fmt.Errorf("failed to copy CA bundle: %w", err)
+	}
+
+	portKept, err := ensureApiserverService(ctx, m, source, target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create apiserver service in target seed: %w", err)
+	}
+	if !portKept {
+		r.recorder.Event(m.ClusterMigration, corev1.EventTypeWarning, "ApiserverPortChanged",
+			"The apiserver port of the cluster is not available in the target seed, the cluster URL will change")
+	}
+
+	log.Info("Control plane prepared in target seed")
+	return nil, r.updateMigration(ctx, m.ClusterMigration, func(cm *kubermaticv1.ClusterMigration) {
+		cm.Status.Phase = kubermaticv1.ClusterMigrationPhaseRestoring
+	})
+}
+
+// restore restores the backup into the target control plane and waits for it to come up.
+func (r *Reconciler) restore(ctx context.Context, log *zap.SugaredLogger, m *migration) (*reconcile.Result, error) {
+	cluster, err := getCluster(ctx, m.targetClient, m.Spec.ClusterName)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return r.fail(ctx, log, m, fmt.Sprintf("cluster %q does not exist in seed %q anymore", m.Spec.ClusterName, m.targetSeed.Name))
+		}
+		return nil, err
+	}
+
+	restore, err := ensureRestore(ctx, m, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	if restore.Status.Phase != kubermaticv1.EtcdRestorePhaseCompleted {
+		log.Debug("Waiting for restore to complete")
+		return &reconcile.Result{RequeueAfter: pollInterval}, nil
+	}
+
+	if cluster.Spec.Pause || cluster.Status.ExtendedHealth.Apiserver != kubermaticv1.HealthStatusUp || cluster.Address.URL == "" {
+		log.Debug("Waiting for apiserver in target seed")
+		return &reconcile.Result{RequeueAfter: pollInterval}, nil
+	}
+
+	address := migrationAddress(cluster.Address)
+	// the external name of the cluster still points to the source seed, so the address under which the
+	// control plane is reachable in the target seed is the one of its nodeport-proxy
+	if cluster.Spec.ExposeStrategy != kubermaticv1.ExposeStrategyLoadBalancer {
+		ip, err := nodePortProxyIP(ctx, m.targetClient, m.targetSeed, r.lookupHost)
+		if err != nil {
+			return nil, err
+		}
+		if ip == "" {
+			log.Debug("Waiting for nodeport-proxy address in target seed")
+			return &reconcile.Result{RequeueAfter: pollInterval}, nil
+		}
+		address.IP = ip
+	}
+	log.Infow("Control plane is running in target seed", "url", address.URL, "ip", address.IP)
+
+	return nil, r.updateMigration(ctx, m.ClusterMigration, func(cm *kubermaticv1.ClusterMigration) {
+		cm.Status.Phase = kubermaticv1.ClusterMigrationPhaseCleaningUp
+		cm.Status.TargetAddress = address
+	})
+}
+
+// cleanupSource removes the control plane from the source seed once the external name of the
+// cluster points to the target seed, so that nodes and kubeconfigs keep reaching the cluster.
+func (r *Reconciler) cleanupSource(ctx context.Context, log *zap.SugaredLogger, m *migration) (*reconcile.Result, error) {
+	cluster, err := getCluster(ctx, m.sourceClient, m.Spec.ClusterName)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	if err == nil {
+		switched, err := r.dnsSwitched(ctx, log, m.Status.TargetAddress)
+		if err != nil {
+			return nil, err
+		}
+		if !switched {
+			address := m.Status.TargetAddress
+			message := fmt.Sprintf("Waiting for the DNS records of %s to point to %s in seed %s.", address.ExternalName, address.IP, m.targetSeed.Name)
+			if m.Status.Message != message {
+				r.recorder.Event(m.ClusterMigration, corev1.EventTypeNormal, "WaitingForDNS", message)
+			}
+			return &reconcile.Result{RequeueAfter: pollInterval}, r.updateMigration(ctx, m.ClusterMigration, func(cm *kubermaticv1.ClusterMigration) {
+				cm.Status.Message = message
+			})
+		}
+
+		if err := removeCluster(ctx, m.sourceClient, cluster); err != nil {
+			return nil, fmt.Errorf("failed to remove cluster from seed %q: %w", m.sourceSeed.Name, err)
+		}
+	}
+
+	message := fmt.Sprintf("Cluster has been migrated to seed %s.", m.targetSeed.Name)
+
+	log.Info("Migration completed")
+	r.recorder.Event(m.ClusterMigration, corev1.EventTypeNormal, "MigrationCompleted", message)
+
+	now := metav1.Now()
+	return nil, r.updateMigration(ctx, m.ClusterMigration, func(cm *kubermaticv1.ClusterMigration) {
+		cm.Status.Phase = kubermaticv1.ClusterMigrationPhaseCompleted
+		cm.Status.Message = message
+		cm.Status.CompletionTime = &now
+	})
+}
+
+// dnsSwitched returns true once the external name of the cluster resolves to the address of the target
+// seed. Clusters whose external name is an IP address have nothing to switch.
+func (r *Reconciler) dnsSwitched(ctx context.Context, log *zap.SugaredLogger, address *kubermaticv1.ClusterMigrationAddress) (bool, error) {
+	if address == nil {
+		return false, errors.New("the address of the target seed has not been recorded")
+	}
+	if address.ExternalName == "" || net.ParseIP(address.ExternalName) != nil {
+		return true, nil
+	}
+
+	ips, err := lookupIPv4(ctx, r.lookupHost, address.ExternalName)
+	if err != nil {
+		log.Debugw("External name cannot be resolved", zap.Error(err))
+		return false, nil
+	}
+
+	return sets.NewString(ips...).Has(address.IP), nil
+}
+
+// fail marks the migration as failed. Unless the target control plane has already
+// taken over, the migration is rolled back first.
+func (r *Reconciler) fail(ctx context.Context, log *zap.SugaredLogger, m *migration, reason string) (*reconcile.Result, error) {
+	log.Infow("Migration failed", "reason", reason)
+
+	if needsRollback(m.ClusterMigration) {
+		if err := r.rollback(ctx, log, m); err != nil {
+			return nil, fmt.Errorf("failed to roll back migration: %w", err)
+		}
+	}
+
+	r.recorder.Event(m.ClusterMigration, corev1.EventTypeWarning, "MigrationFailed", reason)
+
+	now := metav1.Now()
+	return nil, r.updateMigration(ctx, m.ClusterMigration, func(cm *kubermaticv1.ClusterMigration) {
+		cm.Status.Phase = kubermaticv1.ClusterMigrationPhaseFailed
+		cm.Status.Message = reason
+		cm.Status.CompletionTime = &now
+	})
+}
+
+// rollback removes the cluster from the target seed and resumes the source control plane.
+func (r *Reconciler) rollback(ctx context.Context, log *zap.SugaredLogger, m *migration) error {
+	target, err := getCluster(ctx, m.targetClient, m.Spec.ClusterName)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+
+	// never touch a cluster that was not created by this migration
+	if err == nil && target.Annotations[MigrationAnnotation] == m.Name {
+		if err := removeCluster(ctx, m.targetClient, target); err != nil {
+			return fmt.Errorf("failed to remove cluster from seed %q: %w", m.targetSeed.Name, err)
+		}
+
+		for _, name := range []string{target.GetSecretName(), target.GetAuditSinksSecretName()} {
+			if name == "" {
+				continue
+			}
+			secret := &corev1.Secret{}
+			secret.Name = name
+			secret.Namespace = resources.KubermaticNamespace
+			if err := m.targetClient.Delete(ctx, secret); err != nil && !kerrors.IsNotFound(err) {
+				return fmt.Errorf("failed to delete credentials secret: %w", err)
+			}
+		}
+	}
+
+	source, err := getCluster(ctx, m.sourceClient, m.Spec.ClusterName)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if err := updateCluster(ctx, m.sourceClient, source, func(c *kubermaticv1.Cluster) {
+		c.Spec.ComponentsOverride.Apiserver.Replicas = m.Status.SourceApiserverReplicas
+	}); err != nil {
+		return fmt.Errorf("failed to scale up apiserver: %w", err)
+	}
+
+	log.Info("Resumed control plane in source seed")
+	return nil
+}
+
+func (r *Reconciler) updateMigration(ctx context.Context, clusterMigration *kubermaticv1.ClusterMigration, modify func(*kubermaticv1.ClusterMigration)) error {
+	oldMigration := clusterMigration.DeepCopy()
+	modify(clusterMigration)
+	if reflect.DeepEqual(oldMigration, clusterMigration) {
+		return nil
+	}
+	return r.Patch(ctx, clusterMigration, ctrlruntimeclient.MergeFrom(oldMigration))
+}
+
+func isPending(clusterMigration *kubermaticv1.ClusterMigration) bool {
+	return clusterMigration.Status.Phase == "" || clusterMigration.Status.Phase == kubermaticv1.ClusterMigrationPhasePending
+}
+
+// needsRollback returns true if the source control plane has been frozen, but the target
+// control plane has not taken over yet.
+func needsRollback(clusterMigration *kubermaticv1.ClusterMigration) bool {
+	switch clusterMigration.Status.Phase {
+	case kubermaticv1.ClusterMigrationPhaseBackingUp, kubermaticv1.ClusterMigrationPhaseProvisioning, kubermaticv1.ClusterMigrationPhaseRestoring:
+		return true
+	}
+	return false
+}
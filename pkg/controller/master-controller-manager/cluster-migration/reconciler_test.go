@@ -0,0 +1,624 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustermigration
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	clusterName      = "abcd1234"
+	clusterNamespace = "cluster-abcd1234"
+	destinationName  = "s3"
+	migrationName    = "move-abcd1234"
+)
+
+func genSeed(name string) *kubermaticv1.Seed {
+	return &kubermaticv1.Seed{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "kubermatic",
+		},
+		Spec: kubermaticv1.SeedSpec{
+			Datacenters: map[string]kubermaticv1.Datacenter{
+				name + "-dc": {},
+			},
+			EtcdBackupRestore: &kubermaticv1.EtcdBackupRestore{
+				Destinations: map[string]*kubermaticv1.BackupDestination{
+					destinationName: {Endpoint: "s3.example.com", BucketName: "backups"},
+				},
+			},
+		},
+	}
+}
+
+func genCluster() *kubermaticv1.Cluster {
+	return &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       clusterName,
+			UID:        "source-uid",
+			Labels:     map[string]string{kubermaticv1.ProjectIDLabelKey: "my-project"},
+			Finalizers: []string{"kubermatic.io/delete-nodes"},
+		},
+		Spec: kubermaticv1.ClusterSpec{
+			Cloud:          kubermaticv1.CloudSpec{DatacenterName: "source-dc"},
+			ExposeStrategy: kubermaticv1.ExposeStrategyNodePort,
+		},
+		Address: kubermaticv1.ClusterAddress{
+			URL:          "https://abcd1234.source.example.com:30000",
+			ExternalName: "abcd1234.source.example.com",
+			IP:           "1.2.3.4",
+			Port:         30000,
+			AdminToken:   "admin-token",
+		},
+		Status: kubermaticv1.ClusterStatus{
+			NamespaceName: clusterNamespace,
+			UserEmail:     "owner@example.com",
+		},
+	}
+}
+
+func genMigration(phase kubermaticv1.ClusterMigrationPhase) *kubermaticv1.ClusterMigration {
+	migration := &kubermaticv1.ClusterMigration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: migrationName,
+		},
+		Spec: kubermaticv1.ClusterMigrationSpec{
+			ClusterName:      clusterName,
+			SourceSeed:       "source",
+			TargetSeed:       "target",
+			TargetDatacenter: "target-dc",
+			Destination:      destinationName,
+		},
+		Status: kubermaticv1.ClusterMigrationStatus{
+			Phase: phase,
+		},
+	}
+
+	if phase != kubermaticv1.ClusterMigrationPhasePending {
+		migration.Status.BackupName = "migration-" + migrationName
+		migration.Status.SourceApiserverReplicas = resources.Int32(2)
+	}
+
+	return migration
+}
+
+func genSecret(namespace, name string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{"key": []byte(name)},
+	}
+}
+
+func genNodePortProxyService(ip string) *corev1.Service {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "nodeport-proxy",
+			Namespace: "kubermatic",
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeLoadBalancer,
+		},
+	}
+	if ip != "" {
+		service.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: ip}}
+	}
+	return service
+}
+
+func TestReconcile(t *testing.T) {
+	frozenCluster := genCluster()
+	frozenCluster.Spec.ComponentsOverride.Apiserver.Replicas = resources.Int32(0)
+
+	encryptedCluster := frozenCluster.DeepCopy()
+	encryptedCluster.Spec.EncryptionConfiguration = &kubermaticv1.EncryptionConfiguration{Enabled: true}
+	encryptedCluster.Status.Encryption = &kubermaticv1.ClusterEncryptionStatus{
+		Keys:               []kubermaticv1.EncryptionKey{{Provider: kubermaticv1.EncryptionProviderAESCBC, Name: "key-0"}},
+		EncryptedResources: []string{"secrets"},
+	}
+
+	backupConfig := &kubermaticv1.EtcdBackupConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "migration-" + migrationName,
+			Namespace:  clusterNamespace,
+			Finalizers: []string{"kubermatic.io/delete-all-backups"},
+		},
+	}
+
+	completedBackupConfig := backupConfig.DeepCopy()
+	completedBackupConfig.Status.CurrentBackups = []kubermaticv1.BackupStatus{{
+		BackupName:  backupConfig.Name,
+		BackupPhase: kubermaticv1.BackupStatusPhaseCompleted,
+	}}
+
+	failedBackupConfig := backupConfig.DeepCopy()
+	failedBackupConfig.Status.CurrentBackups = []kubermaticv1.BackupStatus{{
+		BackupName:    backupConfig.Name,
+		BackupPhase:   kubermaticv1.BackupStatusPhaseFailed,
+		BackupMessage: "bucket is gone",
+	}}
+
+	targetCluster := genCluster()
+	targetCluster.UID = "target-uid"
+	targetCluster.Annotations = map[string]string{MigrationAnnotation: migrationName}
+	targetCluster.Address.URL = "https://abcd1234.source.example.com:30000"
+	// the external name is kept and still resolves to the source seed
+	targetCluster.Address.IP = "1.2.3.4"
+	targetCluster.Status.ExtendedHealth.Apiserver = kubermaticv1.HealthStatusUp
+
+	completedRestore := &kubermaticv1.EtcdRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "migration-" + migrationName,
+			Namespace: clusterNamespace,
+		},
+		Status: kubermaticv1.EtcdRestoreStatus{
+			Phase: kubermaticv1.EtcdRestorePhaseCompleted,
+		},
+	}
+
+	testCases := []struct {
+		name           string
+		migration      *kubermaticv1.ClusterMigration
+		sourceObjects  []ctrlruntimeclient.Object
+		targetObjects  []ctrlruntimeclient.Object
+		expectedPhase  kubermaticv1.ClusterMigrationPhase
+		dns            map[string][]string
+		expectedResult reconcile.Result
+		validate       func(t *testing.T, migration *kubermaticv1.ClusterMigration, sourceClient, targetClient ctrlruntimeclient.Client)
+	}{
+		{
+			name:          "migration is started",
+			migration:     genMigration(kubermaticv1.ClusterMigrationPhasePending),
+			sourceObjects: []ctrlruntimeclient.Object{genCluster()},
+			expectedPhase: kubermaticv1.ClusterMigrationPhaseBackingUp,
+			validate: func(t *testing.T, migration *kubermaticv1.ClusterMigration, _, _ ctrlruntimeclient.Client) {
+				if migration.Status.BackupName != "migration-"+migrationName {
+					t.Errorf("expected backup name to be set, got %q", migration.Status.BackupName)
+				}
+				if migration.Status.SourceAddress == nil || migration.Status.SourceAddress.IP != "1.2.3.4" {
+					t.Errorf("expected source address to be recorded, got %+v", migration.Status.SourceAddress)
+				}
+				if migration.Status.StartTime == nil {
+					t.Error("expected start time to be set")
+				}
+			},
+		},
+		{
+			name:          "migration fails if the cluster does not exist",
+			migration:     genMigration(kubermaticv1.ClusterMigrationPhasePending),
+			expectedPhase: kubermaticv1.ClusterMigrationPhaseFailed,
+		},
+		{
+			name: "migration fails if the destination does not exist in the target seed",
+			migration: func() *kubermaticv1.ClusterMigration {
+				m := genMigration(kubermaticv1.ClusterMigrationPhasePending)
+				m.Spec.Destination = "missing"
+				return m
+			}(),
+			sourceObjects: []ctrlruntimeclient.Object{genCluster()},
+			expectedPhase: kubermaticv1.ClusterMigrationPhaseFailed,
+		},
+		{
+			name:          "migration fails if the cluster already exists in the target seed",
+			migration:     genMigration(kubermaticv1.ClusterMigrationPhasePending),
+			sourceObjects: []ctrlruntimeclient.Object{genCluster()},
+			targetObjects: []ctrlruntimeclient.Object{genCluster()},
+			expectedPhase: kubermaticv1.ClusterMigrationPhaseFailed,
+		},
+		{
+			name:           "apiserver is scaled down and a backup is taken",
+			migration:      genMigration(kubermaticv1.ClusterMigrationPhaseBackingUp),
+			sourceObjects:  []ctrlruntimeclient.Object{genCluster()},
+			expectedPhase:  kubermaticv1.ClusterMigrationPhaseBackingUp,
+			expectedResult: reconcile.Result{RequeueAfter: pollInterval},
+			validate: func(t *testing.T, _ *kubermaticv1.ClusterMigration, sourceClient, _ ctrlruntimeclient.Client) {
+				cluster := &kubermaticv1.Cluster{}
+				if err := sourceClient.Get(context.Background(), types.NamespacedName{Name: clusterName}, cluster); err != nil {
+					t.Fatalf("failed to get source cluster: %v", err)
+				}
+				if replicas := cluster.Spec.ComponentsOverride.Apiserver.Replicas; replicas == nil || *replicas != 0 {
+					t.Errorf("expected apiserver to be scaled down, got replicas %v", replicas)
+				}
+
+				backupConfig := &kubermaticv1.EtcdBackupConfig{}
+				if err := sourceClient.Get(context.Background(), types.NamespacedName{Namespace: clusterNamespace, Name: "migration-" + migrationName}, backupConfig); err != nil {
+					t.Fatalf("failed to get backup config: %v", err)
+				}
+				if backupConfig.Spec.Schedule != "" || backupConfig.Spec.Destination != destinationName {
+					t.Errorf("expected a one-shot backup to %q, got %+v", destinationName, backupConfig.Spec)
+				}
+			},
+		},
+		{
+			name:      "backup waits for apiserver to be scaled down",
+			migration: genMigration(kubermaticv1.ClusterMigrationPhaseBackingUp),
+			sourceObjects: []ctrlruntimeclient.Object{
+				frozenCluster,
+				&appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Name: resources.ApiserverDeploymentName, Namespace: clusterNamespace},
+					Status:     appsv1.DeploymentStatus{Replicas: 1},
+				},
+			},
+			expectedPhase:  kubermaticv1.ClusterMigrationPhaseBackingUp,
+			expectedResult: reconcile.Result{RequeueAfter: 10 * time.Second},
+			validate: func(t *testing.T, _ *kubermaticv1.ClusterMigration, sourceClient, _ ctrlruntimeclient.Client) {
+				backupConfig := &kubermaticv1.EtcdBackupConfig{}
+				err := sourceClient.Get(context.Background(), types.NamespacedName{Namespace: clusterNamespace, Name: "migration-" + migrationName}, backupConfig)
+				if !kerrors.IsNotFound(err) {
+					t.Errorf("expected no backup to be taken yet, got %v", err)
+				}
+			},
+		},
+		{
+			name:          "completed backup proceeds to provisioning",
+			migration:     genMigration(kubermaticv1.ClusterMigrationPhaseBackingUp),
+			sourceObjects: []ctrlruntimeclient.Object{frozenCluster, completedBackupConfig},
+			expectedPhase: kubermaticv1.ClusterMigrationPhaseProvisioning,
+		},
+		{
+			name:          "failed backup resumes the source control plane",
+			migration:     genMigration(kubermaticv1.ClusterMigrationPhaseBackingUp),
+			sourceObjects: []ctrlruntimeclient.Object{frozenCluster, failedBackupConfig},
+			expectedPhase: kubermaticv1.ClusterMigrationPhaseFailed,
+			validate: func(t *testing.T, migration *kubermaticv1.ClusterMigration, sourceClient, _ ctrlruntimeclient.Client) {
+				cluster := &kubermaticv1.Cluster{}
+				if err := sourceClient.Get(context.Background(), types.NamespacedName{Name: clusterName}, cluster); err != nil {
+					t.Fatalf("failed to get source cluster: %v", err)
+				}
+				if replicas := cluster.Spec.ComponentsOverride.Apiserver.Replicas; replicas == nil || *replicas != 2 {
+					t.Errorf("expected apiserver replicas to be restored to 2, got %v", replicas)
+				}
+				if migration.Status.Message != "backup failed: bucket is gone" {
+					t.Errorf("unexpected message %q", migration.Status.Message)
+				}
+			},
+		},
+		{
+			name:      "control plane is prepared in the target seed",
+			migration: genMigration(kubermaticv1.ClusterMigrationPhaseProvisioning),
+			sourceObjects: []ctrlruntimeclient.Object{
+				frozenCluster,
+				genSecret(clusterNamespace, resources.CASecretName),
+				genSecret(clusterNamespace, resources.ServiceAccountKeySecretName),
+				genSecret(clusterNamespace, "apiserver-tls"),
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cluster-abcd1234-ca-bundle", Namespace: metav1.NamespaceSystem}},
+				&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Name: resources.ApiserverServiceName, Namespace: clusterNamespace},
+					Spec: corev1.ServiceSpec{
+						Type:  corev1.ServiceTypeNodePort,
+						Ports: []corev1.ServicePort{{Name: "secure", Port: 443, NodePort: 30000}},
+					},
+				},
+			},
+			expectedPhase: kubermaticv1.ClusterMigrationPhaseRestoring,
+			validate: func(t *testing.T, _ *kubermaticv1.ClusterMigration, _, targetClient ctrlruntimeclient.Client) {
+				ctx := context.Background()
+
+				cluster := &kubermaticv1.Cluster{}
+				if err := targetClient.Get(ctx, types.NamespacedName{Name: clusterName}, cluster); err != nil {
+					t.Fatalf("failed to get target cluster: %v", err)
+				}
+				if !cluster.Spec.Pause {
+					t.Error("expected target cluster to be paused")
+				}
+				if len(cluster.Finalizers) != 0 {
+					t.Errorf("expected target cluster to have no finalizers, got %v", cluster.Finalizers)
+				}
+				if cluster.Spec.Cloud.DatacenterName != "target-dc" {
+					t.Errorf("expected datacenter target-dc, got %q", cluster.Spec.Cloud.DatacenterName)
+				}
+				if replicas := cluster.Spec.ComponentsOverride.Apiserver.Replicas; replicas == nil || *replicas != 2 {
+					t.Errorf("expected apiserver replicas 2, got %v", replicas)
+				}
+				if name := cluster.Annotations[kubermaticv1.ExternalNameAnnotation]; name != "abcd1234.source.example.com" {
+					t.Errorf("expected external name to be kept, got %q", name)
+				}
+				if cluster.Address.AdminToken != "admin-token" || cluster.Status.NamespaceName != clusterNamespace {
+					t.Errorf("expected admin token and namespace to be kept, got %+v / %q", cluster.Address, cluster.Status.NamespaceName)
+				}
+
+				for _, name := range []string{resources.CASecretName, resources.ServiceAccountKeySecretName} {
+					secret := &corev1.Secret{}
+					if err := targetClient.Get(ctx, types.NamespacedName{Namespace: clusterNamespace, Name: name}, secret); err != nil {
+						t.Errorf("expected secret %s to be copied: %v", name, err)
+					} else if string(secret.Data["key"]) != name {
+						t.Errorf("secret %s has unexpected data", name)
+					}
+				}
+
+				if err := targetClient.Get(ctx, types.NamespacedName{Namespace: clusterNamespace, Name: "apiserver-tls"}, &corev1.Secret{}); !kerrors.IsNotFound(err) {
+					t.Errorf("expected derived certificates not to be copied, got %v", err)
+				}
+
+				service := &corev1.Service{}
+				if err := targetClient.Get(ctx, types.NamespacedName{Namespace: clusterNamespace, Name: resources.ApiserverServiceName}, service); err != nil {
+					t.Fatalf("failed to get apiserver service: %v", err)
+				}
+				if service.Spec.Ports[0].NodePort != 30000 {
+					t.Errorf("expected node port 30000, got %d", service.Spec.Ports[0].NodePort)
+				}
+
+				if err := targetClient.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: "cluster-abcd1234-ca-bundle"}, &corev1.ConfigMap{}); err != nil {
+					t.Errorf("expected CA bundle to be copied: %v", err)
+				}
+			},
+		},
+		{
+			name:      "encryption keys are copied into the target seed",
+			migration: genMigration(kubermaticv1.ClusterMigrationPhaseProvisioning),
+			sourceObjects: []ctrlruntimeclient.Object{
+				encryptedCluster,
+				genSecret(clusterNamespace, resources.EncryptionKeysSecretName),
+				genSecret(clusterNamespace, resources.EncryptionConfigurationSecretName),
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cluster-abcd1234-ca-bundle", Namespace: metav1.NamespaceSystem}},
+			},
+			expectedPhase: kubermaticv1.ClusterMigrationPhaseRestoring,
+			validate: func(t *testing.T, _ *kubermaticv1.ClusterMigration, _, targetClient ctrlruntimeclient.Client) {
+				ctx := context.Background()
+
+				cluster := &kubermaticv1.Cluster{}
+				if err := targetClient.Get(ctx, types.NamespacedName{Name: clusterName}, cluster); err != nil {
+					t.Fatalf("failed to get target cluster: %v", err)
+				}
+				if !reflect.DeepEqual(cluster.Status.Encryption, encryptedCluster.Status.Encryption) {
+					t.Errorf("expected encryption status %+v, got %+v", encryptedCluster.Status.Encryption, cluster.Status.Encryption)
+				}
+
+				for _, name := range []string{resources.EncryptionKeysSecretName, resources.EncryptionConfigurationSecretName} {
+					secret := &corev1.Secret{}
+					if err := targetClient.Get(ctx, types.NamespacedName{Namespace: clusterNamespace, Name: name}, secret); err != nil {
+						t.Errorf("expected secret %s to be copied: %v", name, err)
+					} else if string(secret.Data["key"]) != name {
+						t.Errorf("secret %s has unexpected data", name)
+					}
+				}
+			},
+		},
+		{
+			name:           "restore is started in the target seed",
+			migration:      genMigration(kubermaticv1.ClusterMigrationPhaseRestoring),
+			targetObjects:  []ctrlruntimeclient.Object{targetCluster},
+			expectedPhase:  kubermaticv1.ClusterMigrationPhaseRestoring,
+			expectedResult: reconcile.Result{RequeueAfter: pollInterval},
+			validate: func(t *testing.T, _ *kubermaticv1.ClusterMigration, _, targetClient ctrlruntimeclient.Client) {
+				restore := &kubermaticv1.EtcdRestore{}
+				if err := targetClient.Get(context.Background(), types.NamespacedName{Namespace: clusterNamespace, Name: "migration-" + migrationName}, restore); err != nil {
+					t.Fatalf("failed to get restore: %v", err)
+				}
+				if restore.Spec.BackupName != "migration-"+migrationName || restore.Spec.Destination != destinationName {
+					t.Errorf("unexpected restore spec %+v", restore.Spec)
+				}
+				if restore.Spec.Cluster.UID != "target-uid" {
+					t.Errorf("expected restore to reference the target cluster, got %+v", restore.Spec.Cluster)
+				}
+			},
+		},
+		{
+			name:          "completed restore records the address of the target seed",
+			migration:     genMigration(kubermaticv1.ClusterMigrationPhaseRestoring),
+			targetObjects: []ctrlruntimeclient.Object{targetCluster, completedRestore, genNodePortProxyService("5.6.7.8")},
+			expectedPhase: kubermaticv1.ClusterMigrationPhaseCleaningUp,
+			validate: func(t *testing.T, migration *kubermaticv1.ClusterMigration, _, _ ctrlruntimeclient.Client) {
+				if migration.Status.TargetAddress == nil || migration.Status.TargetAddress.IP != "5.6.7.8" {
+					t.Errorf("expected target address to be recorded, got %+v", migration.Status.TargetAddress)
+				}
+			},
+		},
+		{
+			name:           "completed restore waits for the nodeport-proxy of the target seed",
+			migration:      genMigration(kubermaticv1.ClusterMigrationPhaseRestoring),
+			targetObjects:  []ctrlruntimeclient.Object{targetCluster, completedRestore, genNodePortProxyService("")},
+			expectedPhase:  kubermaticv1.ClusterMigrationPhaseRestoring,
+			expectedResult: reconcile.Result{RequeueAfter: pollInterval},
+		},
+		{
+			name: "source control plane is kept until the DNS records point to the target seed",
+			migration: func() *kubermaticv1.ClusterMigration {
+				m := genMigration(kubermaticv1.ClusterMigrationPhaseCleaningUp)
+				m.Status.TargetAddress = migrationAddress(targetCluster.Address)
+				m.Status.TargetAddress.IP = "5.6.7.8"
+				return m
+			}(),
+			sourceObjects:  []ctrlruntimeclient.Object{frozenCluster, completedBackupConfig},
+			targetObjects:  []ctrlruntimeclient.Object{targetCluster},
+			dns:            map[string][]string{"abcd1234.source.example.com": {"1.2.3.4"}},
+			expectedPhase:  kubermaticv1.ClusterMigrationPhaseCleaningUp,
+			expectedResult: reconcile.Result{RequeueAfter: pollInterval},
+			validate: func(t *testing.T, migration *kubermaticv1.ClusterMigration, sourceClient, _ ctrlruntimeclient.Client) {
+				if err := sourceClient.Get(context.Background(), types.NamespacedName{Name: clusterName}, &kubermaticv1.Cluster{}); err != nil {
+					t.Errorf("expected source cluster to be kept: %v", err)
+				}
+
+				expected := "Waiting for the DNS records of abcd1234.source.example.com to point to 5.6.7.8 in seed target."
+				if migration.Status.Message != expected {
+					t.Errorf("expected message %q, got %q", expected, migration.Status.Message)
+				}
+			},
+		},
+		{
+			name: "source control plane is removed",
+			migration: func() *kubermaticv1.ClusterMigration {
+				m := genMigration(kubermaticv1.ClusterMigrationPhaseCleaningUp)
+				m.Status.TargetAddress = migrationAddress(targetCluster.Address)
+				m.Status.TargetAddress.IP = "5.6.7.8"
+				return m
+			}(),
+			sourceObjects: []ctrlruntimeclient.Object{frozenCluster, completedBackupConfig},
+			targetObjects: []ctrlruntimeclient.Object{targetCluster},
+			dns:           map[string][]string{"abcd1234.source.example.com": {"5.6.7.8"}},
+			expectedPhase: kubermaticv1.ClusterMigrationPhaseCompleted,
+			validate: func(t *testing.T, migration *kubermaticv1.ClusterMigration, sourceClient, targetClient ctrlruntimeclient.Client) {
+				ctx := context.Background()
+
+				if err := sourceClient.Get(ctx, types.NamespacedName{Name: clusterName}, &kubermaticv1.Cluster{}); !kerrors.IsNotFound(err) {
+					t.Errorf("expected source cluster to be deleted, got %v", err)
+				}
+
+				backupConfig := &kubermaticv1.EtcdBackupConfig{}
+				if err := sourceClient.Get(ctx, types.NamespacedName{Namespace: clusterNamespace, Name: completedBackupConfig.Name}, backupConfig); err != nil {
+					t.Fatalf("failed to get backup config: %v", err)
+				}
+				if len(backupConfig.Finalizers) != 0 {
+					t.Errorf("expected finalizers to be removed from backup config, got %v", backupConfig.Finalizers)
+				}
+
+				if err := targetClient.Get(ctx, types.NamespacedName{Name: clusterName}, &kubermaticv1.Cluster{}); err != nil {
+					t.Errorf("expected target cluster to be untouched: %v", err)
+				}
+
+				expected := "Cluster has been migrated to seed target."
+				if migration.Status.Message != expected {
+					t.Errorf("expected message %q, got %q", expected, migration.Status.Message)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			masterClient := fakectrlruntimeclient.NewClientBuilder().WithObjects(tc.migration).Build()
+			sourceClient := fakectrlruntimeclient.NewClientBuilder().WithObjects(tc.sourceObjects...).Build()
+			targetClient := fakectrlruntimeclient.NewClientBuilder().WithObjects(tc.targetObjects...).Build()
+
+			seeds := map[string]*kubermaticv1.Seed{
+				"source": genSeed("source"),
+				"target": genSeed("target"),
+			}
+
+			reconciler := &Reconciler{
+				Client:   masterClient,
+				log:      zap.NewNop().Sugar(),
+				recorder: record.NewFakeRecorder(10),
+				seedsGetter: func() (map[string]*kubermaticv1.Seed, error) {
+					return seeds, nil
+				},
+				seedClientGetter: func(seed *kubermaticv1.Seed) (ctrlruntimeclient.Client, error) {
+					if seed.Name == "source" {
+						return sourceClient, nil
+					}
+					return targetClient, nil
+				},
+				lookupHost: func(_ context.Context, host string) ([]string, error) {
+					if addresses, ok := tc.dns[host]; ok {
+						return addresses, nil
+					}
+					return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+				},
+			}
+
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: migrationName}})
+			if err != nil {
+				t.Fatalf("reconciling failed: %v", err)
+			}
+			if result != tc.expectedResult {
+				t.Errorf("expected result %+v, got %+v", tc.expectedResult, result)
+			}
+
+			migration := &kubermaticv1.ClusterMigration{}
+			if err := masterClient.Get(ctx, types.NamespacedName{Name: migrationName}, migration); err != nil {
+				t.Fatalf("failed to get migration: %v", err)
+			}
+
+			if migration.Status.Phase != tc.expectedPhase {
+				t.Fatalf("expected phase %q, got %q (message: %q)", tc.expectedPhase, migration.Status.Phase, migration.Status.Message)
+			}
+
+			if tc.validate != nil {
+				tc.validate(t, migration, sourceClient, targetClient)
+			}
+		})
+	}
+}
+
+func TestDeletedMigrationIsRolledBack(t *testing.T) {
+	ctx := context.Background()
+
+	migration := genMigration(kubermaticv1.ClusterMigrationPhaseRestoring)
+	migration.Finalizers = []string{CleanupFinalizer}
+	now := metav1.Now()
+	migration.DeletionTimestamp = &now
+
+	source := genCluster()
+	source.Spec.ComponentsOverride.Apiserver.Replicas = resources.Int32(0)
+
+	target := genCluster()
+	target.Annotations = map[string]string{MigrationAnnotation: migrationName}
+
+	masterClient := fakectrlruntimeclient.NewClientBuilder().WithObjects(migration).Build()
+	sourceClient := fakectrlruntimeclient.NewClientBuilder().WithObjects(source).Build()
+	targetClient := fakectrlruntimeclient.NewClientBuilder().WithObjects(target).Build()
+
+	reconciler := &Reconciler{
+		Client:   masterClient,
+		log:      zap.NewNop().Sugar(),
+		recorder: record.NewFakeRecorder(10),
+		seedsGetter: func() (map[string]*kubermaticv1.Seed, error) {
+			return map[string]*kubermaticv1.Seed{"source": genSeed("source"), "target": genSeed("target")}, nil
+		},
+		seedClientGetter: func(seed *kubermaticv1.Seed) (ctrlruntimeclient.Client, error) {
+			if seed.Name == "source" {
+				return sourceClient, nil
+			}
+			return targetClient, nil
+		},
+	}
+
+	if _, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: migrationName}}); err != nil {
+		t.Fatalf("reconciling failed: %v", err)
+	}
+
+	if err := targetClient.Get(ctx, types.NamespacedName{Name: clusterName}, &kubermaticv1.Cluster{}); !kerrors.IsNotFound(err) {
+		t.Errorf("expected target cluster to be removed, got %v", err)
+	}
+
+	cluster := &kubermaticv1.Cluster{}
+	if err := sourceClient.Get(ctx, types.NamespacedName{Name: clusterName}, cluster); err != nil {
+		t.Fatalf("failed to get source cluster: %v", err)
+	}
+	if replicas := cluster.Spec.ComponentsOverride.Apiserver.Replicas; replicas == nil || *replicas != 2 {
+		t.Errorf("expected apiserver replicas to be restored to 2, got %v", replicas)
+	}
+
+	remaining := &kubermaticv1.ClusterMigration{}
+	if err := masterClient.Get(ctx, types.NamespacedName{Name: migrationName}, remaining); err == nil && len(remaining.Finalizers) != 0 {
+		t.Errorf("expected finalizer to be removed, got %v", remaining.Finalizers)
+	}
+}
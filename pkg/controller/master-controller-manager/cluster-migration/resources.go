@@ -0,0 +1,470 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustermigration
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+
+	"k8c.io/kubermatic/v2/pkg/controller/operator/seed/resources/nodeportproxy"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// migratedSecrets are the secrets in the cluster namespace that contain the CAs and keys of
+// the cluster. All other certificates are derived from them and recreated in the target seed.
+// Without the encryption keys, the restored etcd data of encrypted clusters cannot be read.
+var migratedSecrets = []string{
+	resources.CASecretName,
+	resources.FrontProxyCASecretName,
+	resources.ServiceAccountKeySecretName,
+	resources.OpenVPNCASecretName,
+	resources.TokensSecretName,
+	resources.ViewerTokenSecretName,
+	resources.MLAGatewayCASecretName,
+	resources.EncryptionKeysSecretName,
+	resources.EncryptionConfigurationSecretName,
+}
+
+func backupName(clusterMigration *kubermaticv1.ClusterMigration) string {
+	return fmt.Sprintf("migration-%s", clusterMigration.Name)
+}
+
+func targetDatacenter(clusterMigration *kubermaticv1.ClusterMigration, cluster *kubermaticv1.Cluster) string {
+	if clusterMigration.Spec.TargetDatacenter != "" {
+		return clusterMigration.Spec.TargetDatacenter
+	}
+	return cluster.Spec.Cloud.DatacenterName
+}
+
+func getDestination(seed *kubermaticv1.Seed, name string) (*kubermaticv1.BackupDestination, error) {
+	if seed.Spec.EtcdBackupRestore != nil {
+		if destination, ok := seed.Spec.EtcdBackupRestore.Destinations[name]; ok {
+			return destination, nil
+		}
+	}
+	return nil, fmt.Errorf("backup destination %q does not exist in seed %q", name, seed.Name)
+}
+
+func migrationAddress(address kubermaticv1.ClusterAddress) *kubermaticv1.ClusterMigrationAddress {
+	return &kubermaticv1.ClusterMigrationAddress{
+		URL:          address.URL,
+		ExternalName: address.ExternalName,
+		IP:           address.IP,
+		Port:         address.Port,
+	}
+}
+
+// nodePortProxyIP returns the external IP of the nodeport-proxy of the seed, which exposes the control
+// planes of all clusters that are not exposed through a load balancer of their own. It is empty while
+// the load balancer of the nodeport-proxy has no address yet.
+func nodePortProxyIP(ctx context.Context, client ctrlruntimeclient.Client, seed *kubermaticv1.Seed, lookupHost hostLookupFunc) (string, error) {
+	service := &corev1.Service{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: seed.Namespace, Name: nodeportproxy.ServiceName}, service); err != nil {
+		return "", fmt.Errorf("failed to get nodeport-proxy service: %w", err)
+	}
+
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			return ingress.IP, nil
+		}
+		if ingress.Hostname != "" {
+			ips, err := lookupIPv4(ctx, lookupHost, ingress.Hostname)
+			if err != nil {
+				return "", err
+			}
+			if len(ips) > 0 {
+				return ips[0], nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// lookupIPv4 returns the sorted IPv4 addresses the host resolves to.
+func lookupIPv4(ctx context.Context, lookupHost hostLookupFunc, host string) ([]string, error) {
+	addresses, err := lookupHost(ctx, host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up %s: %w", host, err)
+	}
+
+	ips := sets.NewString()
+	for _, address := range addresses {
+		if ip := net.ParseIP(address); ip != nil && ip.To4() != nil {
+			ips.Insert(ip.String())
+		}
+	}
+	return ips.List(), nil
+}
+
+func ownerRef(cluster *kubermaticv1.Cluster) *metav1.OwnerReference {
+	return metav1.NewControllerRef(cluster, kubermaticv1.SchemeGroupVersion.WithKind(kubermaticv1.ClusterKindName))
+}
+
+func clusterReference(cluster *kubermaticv1.Cluster) corev1.ObjectReference {
+	return corev1.ObjectReference{
+		APIVersion: kubermaticv1.SchemeGroupVersion.String(),
+		Kind:       kubermaticv1.ClusterKindName,
+		Name:       cluster.Name,
+		UID:        cluster.UID,
+	}
+}
+
+func getCluster(ctx context.Context, client ctrlruntimeclient.Client, name string) (*kubermaticv1.Cluster, error) {
+	cluster := &kubermaticv1.Cluster{}
+	if err := client.Get(ctx, types.NamespacedName{Name: name}, cluster); err != nil {
+		return nil, err
+	}
+	return cluster, nil
+}
+
+func updateCluster(ctx context.Context, client ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster, modify func(*kubermaticv1.Cluster)) error {
+	oldCluster := cluster.DeepCopy()
+	modify(cluster)
+	if reflect.DeepEqual(oldCluster, cluster) {
+		return nil
+	}
+	return client.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster))
+}
+
+// ensureBackupConfig creates a one-shot EtcdBackupConfig for the cluster in the source seed.
+func ensureBackupConfig(ctx context.Context, m *migration, cluster *kubermaticv1.Cluster) (*kubermaticv1.EtcdBackupConfig, error) {
+	backupConfig := &kubermaticv1.EtcdBackupConfig{}
+	key := types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: m.Status.BackupName}
+
+	err := m.sourceClient.Get(ctx, key, backupConfig)
+	if err == nil || !kerrors.IsNotFound(err) {
+		return backupConfig, err
+	}
+
+	backupConfig = &kubermaticv1.EtcdBackupConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            key.Name,
+			Namespace:       key.Namespace,
+			OwnerReferences: []metav1.OwnerReference{*ownerRef(cluster)},
+		},
+		Spec: kubermaticv1.EtcdBackupConfigSpec{
+			Name:        key.Name,
+			Cluster:     clusterReference(cluster),
+			Destination: m.Spec.Destination,
+		},
+	}
+
+	if err := m.sourceClient.Create(ctx, backupConfig); err != nil {
+		return nil, fmt.Errorf("failed to create EtcdBackupConfig: %w", err)
+	}
+
+	return backupConfig, nil
+}
+
+// ensureRestore creates the EtcdRestore for the cluster in the target seed.
+func ensureRestore(ctx context.Context, m *migration, cluster *kubermaticv1.Cluster) (*kubermaticv1.EtcdRestore, error) {
+	restore := &kubermaticv1.EtcdRestore{}
+	key := types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: m.Status.BackupName}
+
+	err := m.targetClient.Get(ctx, key, restore)
+	if err == nil || !kerrors.IsNotFound(err) {
+		return restore, err
+	}
+
+	restore = &kubermaticv1.EtcdRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            key.Name,
+			Namespace:       key.Namespace,
+			OwnerReferences: []metav1.OwnerReference{*ownerRef(cluster)},
+		},
+		Spec: kubermaticv1.EtcdRestoreSpec{
+			Name:        key.Name,
+			Cluster:     clusterReference(cluster),
+			BackupName:  m.Status.BackupName,
+			Destination: m.Spec.Destination,
+		},
+	}
+
+	if err := m.targetClient.Create(ctx, restore); err != nil {
+		return nil, fmt.Errorf("failed to create EtcdRestore: %w", err)
+	}
+
+	return restore, nil
+}
+
+// targetCluster returns the cluster to create in the target seed. It is created paused and
+// is resumed by the restore controller once the backup has been restored.
+func targetCluster(clusterMigration *kubermaticv1.ClusterMigration, source *kubermaticv1.Cluster) *kubermaticv1.Cluster {
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        source.Name,
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+		},
+		Spec: *source.Spec.DeepCopy(),
+		Address: kubermaticv1.ClusterAddress{
+			AdminToken: source.Address.AdminToken,
+		},
+		Status: kubermaticv1.ClusterStatus{
+			NamespaceName:          source.Status.NamespaceName,
+			UserName:               source.Status.UserName,
+			UserEmail:              source.Status.UserEmail,
+			RootCA:                 source.Status.RootCA.DeepCopy(),
+			ApiserverCert:          source.Status.ApiserverCert.DeepCopy(),
+			KubeletCert:            source.Status.KubeletCert.DeepCopy(),
+			ApiserverSSHKey:        source.Status.ApiserverSSHKey.DeepCopy(),
+			ServiceAccountKey:      source.Status.ServiceAccountKey,
+			CloudMigrationRevision: source.Status.CloudMigrationRevision,
+			InheritedLabels:        source.Status.InheritedLabels,
+			Encryption:             source.Status.Encryption.DeepCopy(),
+		},
+	}
+
+	for k, v := range source.Labels {
+		cluster.Labels[k] = v
+	}
+	for k, v := range source.Annotations {
+		cluster.Annotations[k] = v
+	}
+
+	cluster.Annotations[MigrationAnnotation] = clusterMigration.Name
+
+	// keep the external name, so that the apiserver certificate and all kubeconfigs
+	// remain valid; a load balancer gets a new address in any case
+	if source.Spec.ExposeStrategy != kubermaticv1.ExposeStrategyLoadBalancer && source.Address.ExternalName != "" {
+		cluster.Annotations[kubermaticv1.ExternalNameAnnotation] = source.Address.ExternalName
+	}
+
+	cluster.Spec.Cloud.DatacenterName = targetDatacenter(clusterMigration, source)
+	cluster.Spec.ComponentsOverride.Apiserver.Replicas = clusterMigration.Status.SourceApiserverReplicas
+	cluster.Spec.Pause = true
+	cluster.Spec.PauseReason = fmt.Sprintf("Cluster is being migrated by %s", clusterMigration.Name)
+
+	return cluster
+}
+
+func ensureTargetCluster(ctx context.Context, m *migration, source *kubermaticv1.Cluster) (*kubermaticv1.Cluster, error) {
+	cluster, err := getCluster(ctx, m.targetClient, source.Name)
+	if err == nil || !kerrors.IsNotFound(err) {
+		return cluster, err
+	}
+
+	cluster = targetCluster(m.ClusterMigration, source)
+	if err := m.targetClient.Create(ctx, cluster); err != nil {
+		return nil, err
+	}
+
+	return cluster, nil
+}
+
+func ensureNamespace(ctx context.Context, client ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster) error {
+	namespace := &corev1.Namespace{}
+	err := client.Get(ctx, types.NamespacedName{Name: cluster.Status.NamespaceName}, namespace)
+	if err == nil || !kerrors.IsNotFound(err) {
+		return err
+	}
+
+	namespace = &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            cluster.Status.NamespaceName,
+			OwnerReferences: []metav1.OwnerReference{*ownerRef(cluster)},
+		},
+	}
+
+	return client.Create(ctx, namespace)
+}
+
+// copySecret copies a secret from the source into the target seed. Secrets
+// that do not exist in the source seed or already exist in the target seed
+// are skipped.
+func copySecret(ctx context.Context, m *migration, namespace, name string, owner *metav1.OwnerReference) error {
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+
+	source := &corev1.Secret{}
+	if err := m.sourceClient.Get(ctx, key, source); err != nil {
+		return ctrlruntimeclient.IgnoreNotFound(err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Labels:      source.Labels,
+			Annotations: source.Annotations,
+		},
+		Type: source.Type,
+		Data: source.Data,
+	}
+	if owner != nil {
+		secret.OwnerReferences = []metav1.OwnerReference{*owner}
+	}
+
+	if err := m.targetClient.Create(ctx, secret); err != nil && !kerrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	return nil
+}
+
+// copyCABundle copies the CA bundle used by the backup and restore jobs
+// into the target seed, where it is needed to access the backup destination.
+func copyCABundle(ctx context.Context, m *migration, source, target *kubermaticv1.Cluster) error {
+	key := types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: resources.BackupCABundleConfigMapName(source)}
+
+	configMap := &corev1.ConfigMap{}
+	if err := m.sourceClient.Get(ctx, key, configMap); err != nil {
+		return err
+	}
+
+	bundle := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            key.Name,
+			Namespace:       key.Namespace,
+			Labels:          configMap.Labels,
+			OwnerReferences: []metav1.OwnerReference{*ownerRef(target)},
+		},
+		Data: configMap.Data,
+	}
+
+	if err := m.targetClient.Create(ctx, bundle); err != nil && !kerrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	return nil
+}
+
+// ensureApiserverService creates the apiserver service in the target seed with the same
+// ports as in the source seed, so that the cluster URL does not change. It returns false
+// if the node port is already allocated in the target seed, in which case the service
+// is left to the cluster controller.
+func ensureApiserverService(ctx context.Context, m *migration, source, target *kubermaticv1.Cluster) (bool, error) {
+	key := types.NamespacedName{Namespace: source.Status.NamespaceName, Name: resources.ApiserverServiceName}
+
+	sourceService := &corev1.Service{}
+	if err := m.sourceClient.Get(ctx, key, sourceService); err != nil {
+		return true, ctrlruntimeclient.IgnoreNotFound(err)
+	}
+
+	service := &corev1.Service{}
+	if err := m.targetClient.Get(ctx, key, service); err == nil || !kerrors.IsNotFound(err) {
+		return true, err
+	}
+
+	service = &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            key.Name,
+			Namespace:       key.Namespace,
+			Labels:          sourceService.Labels,
+			Annotations:     sourceService.Annotations,
+			OwnerReferences: []metav1.OwnerReference{*ownerRef(target)},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:     sourceService.Spec.Type,
+			Ports:    sourceService.Spec.Ports,
+			Selector: sourceService.Spec.Selector,
+		},
+	}
+
+	if err := m.targetClient.Create(ctx, service); err != nil {
+		if kerrors.IsInvalid(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// removeCluster deletes a cluster from a seed without any of the cleanup that is usually
+// done for deleted clusters: nodes, volumes, cloud provider resources and backups are still
+// in use by the same cluster in the other seed.
+func removeCluster(ctx context.Context, client ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster) error {
+	// stop all seed controllers from working on the cluster first
+	if err := updateCluster(ctx, client, cluster, func(c *kubermaticv1.Cluster) {
+		c.Spec.Pause = true
+	}); err != nil {
+		return fmt.Errorf("failed to pause cluster: %w", err)
+	}
+
+	if cluster.Status.NamespaceName != "" {
+		if err := releaseNamespace(ctx, client, cluster.Status.NamespaceName); err != nil {
+			return err
+		}
+	}
+
+	if err := updateCluster(ctx, client, cluster, func(c *kubermaticv1.Cluster) {
+		c.Finalizers = nil
+	}); err != nil {
+		return fmt.Errorf("failed to remove finalizers: %w", err)
+	}
+
+	// the cluster namespace and everything in it is owned by the cluster
+	// and will be garbage collected
+	if err := client.Delete(ctx, cluster); err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete cluster: %w", err)
+	}
+
+	return nil
+}
+
+// releaseNamespace removes the finalizers from all KKP resources in a cluster namespace.
+// Their controllers would otherwise clean up things that are still in use, or get stuck
+// because the cluster is gone.
+func releaseNamespace(ctx context.Context, client ctrlruntimeclient.Client, namespace string) error {
+	lists := []ctrlruntimeclient.ObjectList{
+		&kubermaticv1.AddonList{},
+		&kubermaticv1.AlertmanagerList{},
+		&kubermaticv1.ConstraintList{},
+		&kubermaticv1.EtcdBackupConfigList{},
+		&kubermaticv1.EtcdRestoreList{},
+		&kubermaticv1.MLAAdminSettingList{},
+		&kubermaticv1.RuleGroupList{},
+	}
+
+	for _, list := range lists {
+		if err := client.List(ctx, list, ctrlruntimeclient.InNamespace(namespace)); err != nil {
+			return fmt.Errorf("failed to list %T: %w", list, err)
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			obj := item.(ctrlruntimeclient.Object)
+			if len(obj.GetFinalizers()) == 0 {
+				continue
+			}
+
+			oldObj := obj.DeepCopyObject().(ctrlruntimeclient.Object)
+			obj.SetFinalizers(nil)
+			if err := client.Patch(ctx, obj, ctrlruntimeclient.MergeFrom(oldObj)); err != nil {
+				return fmt.Errorf("failed to remove finalizers from %s: %w", obj.GetName(), err)
+			}
+		}
+	}
+
+	return nil
+}
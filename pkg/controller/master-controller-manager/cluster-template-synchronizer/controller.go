@@ -157,14 +157,24 @@ func (r *reconciler) handleDeletion(ctx context.Context, log *zap.SugaredLogger,
 	}
 
 	if kuberneteshelper.HasFinalizer(template, kubermaticapiv1.CredentialsSecretsCleanupFinalizer) {
+		// the credentials of the audit sinks are stored for the cluster the template was created from
+		partialCluster := &kubermaticv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: template.Name}}
 		if err := r.syncAllSeeds(log, template, func(seedClient ctrlruntimeclient.Client, template *kubermaticv1.ClusterTemplate) error {
-			err := seedClient.Delete(ctx, &corev1.Secret{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      template.Credential,
-					Namespace: resources.KubermaticNamespace,
-				},
-			})
-			return ctrlruntimeclient.IgnoreNotFound(err)
+			for _, name := range []string{template.Credential, partialCluster.GetAuditSinksSecretName()} {
+				if name == "" {
+					continue
+				}
+				err := seedClient.Delete(ctx, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: resources.KubermaticNamespace,
+					},
+				})
+				if err := ctrlruntimeclient.IgnoreNotFound(err); err != nil {
+					return err
+				}
+			}
+			return nil
 		}); err != nil {
 			return err
 		}
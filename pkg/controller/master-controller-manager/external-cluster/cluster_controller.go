@@ -19,24 +19,30 @@ package externalcluster
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/aws/aws-sdk-go/service/eks"
 	"go.uber.org/zap"
 
 	providerconfig "github.com/kubermatic/machine-controller/pkg/providerconfig/types"
 	kubermaticapiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1/helper"
 	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/provider"
 	"k8c.io/kubermatic/v2/pkg/provider/cloud/aws"
 	"k8c.io/kubermatic/v2/pkg/provider/cloud/azure"
 	"k8c.io/kubermatic/v2/pkg/provider/cloud/gcp"
 	"k8c.io/kubermatic/v2/pkg/resources"
 
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -49,12 +55,33 @@ import (
 
 const (
 	ControllerName = "external_cluster_controller"
+
+	// statusPollInterval is used to follow clusters whose cloud provider reports a transitional
+	// state, e.g. while they are being provisioned, upgraded or deleted.
+	statusPollInterval = 30 * time.Second
+
+	// healthCheckInterval is used to refresh the health status of reachable clusters. It also keeps
+	// the kubeconfig tokens of GKE (valid 1h) and EKS (valid 14min) clusters fresh.
+	healthCheckInterval = 5 * time.Minute
+
+	// clusterClientTimeout limits how long a single request against the API server of an
+	// external cluster may take, so an unreachable cluster does not block the controller.
+	clusterClientTimeout = 10 * time.Second
 )
 
+// clusterClientFunc returns a client for the API server of the given external cluster.
+type clusterClientFunc func(ctx context.Context, cluster *kubermaticv1.ExternalCluster) (kubernetes.Interface, error)
+
+// kubeconfigClientFunc returns a client for the API server of an external cluster that uses the
+// current context of the given kubeconfig.
+type kubeconfigClientFunc func(config *api.Config) (kubernetes.Interface, error)
+
 // Reconciler is a controller which is responsible for managing clusters
 type Reconciler struct {
 	ctrlruntimeclient.Client
-	log *zap.SugaredLogger
+	log              *zap.SugaredLogger
+	clusterClient    clusterClientFunc
+	kubeconfigClient kubeconfigClientFunc
 }
 
 // Add creates a cluster controller.
@@ -66,6 +93,8 @@ func Add(
 		log:    log.Named(ControllerName),
 		Client: mgr.GetClient(),
 	}
+	reconciler.clusterClient = reconciler.getClusterClient
+	reconciler.kubeconfigClient = newKubeconfigClient
 	c, err := controller.New(ControllerName, mgr, controller.Options{Reconciler: reconciler})
 	if err != nil {
 		return err
@@ -94,6 +123,18 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 	}
 
 	if icl.DeletionTimestamp != nil {
+		// The cloud cluster has to be gone before the credentials secret is removed, as the
+		// credentials are required to delete it.
+		if kuberneteshelper.HasFinalizer(icl, kubermaticapiv1.ExternalClusterCloudCleanupFinalizer) {
+			deleted, err := r.cleanUpCloudCluster(ctx, icl)
+			if err != nil {
+				log.Errorf("Could not delete cloud cluster, %v", err)
+				return reconcile.Result{}, err
+			}
+			if !deleted {
+				return reconcile.Result{RequeueAfter: statusPollInterval}, nil
+			}
+		}
 		if kuberneteshelper.HasFinalizer(icl, kubermaticapiv1.ExternalClusterKubeconfigCleanupFinalizer) {
 			if err := r.cleanUpKubeconfigSecret(ctx, icl); err != nil {
 				log.Errorf("Could not delete kubeconfig secret, %v", err)
@@ -106,6 +147,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 				return reconcile.Result{}, err
 			}
 		}
+		return reconcile.Result{}, nil
 	}
 
 	return r.reconcile(ctx, icl)
@@ -114,38 +156,312 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 func (r *Reconciler) reconcile(ctx context.Context, cluster *kubermaticv1.ExternalCluster) (reconcile.Result, error) {
 	cloud := cluster.Spec.CloudSpec
 	if cloud == nil {
-		return reconcile.Result{}, nil
+		// imported from a kubeconfig, the cluster is only observed
+		if err := r.reconcileKubeconfigCredentials(ctx, cluster); err != nil {
+			// the health check still runs, it records whether the cluster can be reached at all
+			r.log.Errorf("failed to reconcile kubeconfig of cluster %s: %v", cluster.Name, err)
+		}
+		return r.reconcileHealth(ctx, cluster)
+	}
+
+	state, phase, err := r.getCloudClusterStatus(ctx, cluster)
+	if err != nil {
+		if errors.Is(err, provider.ErrNotFound) {
+			return reconcile.Result{RequeueAfter: statusPollInterval}, r.updateStatus(ctx, cluster, kubermaticv1.ExternalClusterPhaseError, "cluster not found on the cloud provider")
+		}
+		if statusErr := r.updateStatus(ctx, cluster, kubermaticv1.ExternalClusterPhaseUnknown, err.Error()); statusErr != nil {
+			r.log.Errorf("failed to update status of cluster %s: %v", cluster.Name, statusErr)
+		}
+		return reconcile.Result{}, err
+	}
+	if err := r.updateStatus(ctx, cluster, phase, state); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	switch phase {
+	case kubermaticv1.ExternalClusterPhaseRunning:
+		if err := r.reconcileKubeconfig(ctx, cluster); err != nil {
+			return reconcile.Result{}, err
+		}
+		return r.reconcileHealth(ctx, cluster)
+	case kubermaticv1.ExternalClusterPhaseReconciling:
+		// the control plane stays reachable during upgrades, so the kubeconfig and the health status
+		// are kept up to date but the cluster is polled until it is running again
+		if err := r.reconcileKubeconfig(ctx, cluster); err != nil {
+			return reconcile.Result{}, err
+		}
+		if _, err := r.reconcileHealth(ctx, cluster); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{RequeueAfter: statusPollInterval}, nil
+	default:
+		// there is no control plane to talk to yet
+		return reconcile.Result{RequeueAfter: statusPollInterval}, nil
+	}
+}
+
+func (r *Reconciler) reconcileKubeconfig(ctx context.Context, cluster *kubermaticv1.ExternalCluster) error {
+	var err error
+	cloud := cluster.Spec.CloudSpec
+	switch {
+	case cloud.GKE != nil:
+		r.log.Debugf("reconcile GKE cluster %v", cluster.Name)
+		err = r.createOrUpdateGKEKubeconfig(ctx, cluster)
+	case cloud.EKS != nil:
+		r.log.Debugf("reconcile EKS cluster %v", cluster.Name)
+		err = r.createOrUpdateEKSKubeconfig(ctx, cluster)
+	case cloud.AKS != nil:
+		r.log.Debugf("reconcile AKS cluster %v", cluster.Name)
+		err = r.createOrUpdateAKSKubeconfig(ctx, cluster)
+	}
+	if err != nil {
+		r.log.Errorf("failed to create or update kubeconfig secret %v", err)
+	}
+	return err
+}
+
+// reconcileHealth connects to the API server of the cluster and records its reachability,
+// version and node count in the status.
+func (r *Reconciler) reconcileHealth(ctx context.Context, cluster *kubermaticv1.ExternalCluster) (reconcile.Result, error) {
+	if cluster.Spec.KubeconfigReference == nil {
+		// the kubeconfig has not been stored yet
+		return reconcile.Result{RequeueAfter: statusPollInterval}, nil
+	}
+
+	oldCluster := cluster.DeepCopy()
+	if err := r.checkHealth(ctx, cluster); err != nil {
+		r.log.Debugf("cluster %s is not reachable: %v", cluster.Name, err)
+		helper.SetExternalClusterCondition(cluster, kubermaticv1.ExternalClusterConditionReachable, corev1.ConditionFalse, "Unreachable", err.Error())
+		helper.SetExternalClusterCondition(cluster, kubermaticv1.ExternalClusterConditionNodesReady, corev1.ConditionUnknown, "Unreachable", "the API server of the cluster is not reachable")
+	}
+
+	if !apiequality.Semantic.DeepEqual(oldCluster.Status, cluster.Status) {
+		if err := r.Status().Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to update status of cluster %s: %v", cluster.Name, err)
+		}
+	}
+
+	return reconcile.Result{RequeueAfter: healthCheckInterval}, nil
+}
+
+func (r *Reconciler) checkHealth(ctx context.Context, cluster *kubermaticv1.ExternalCluster) error {
+	client, err := r.clusterClient(ctx, cluster)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %v", err)
+	}
+
+	version, err := client.Discovery().ServerVersion()
+	if err != nil {
+		return fmt.Errorf("failed to get server version: %v", err)
+	}
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list nodes: %v", err)
+	}
+
+	readyNodes := 0
+	for _, node := range nodes.Items {
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+				readyNodes++
+			}
+		}
+	}
+
+	cluster.Status.Version = version.GitVersion
+	cluster.Status.NodeCount = int32(len(nodes.Items))
+	cluster.Status.LastSeen = metav1.Now()
+	helper.SetExternalClusterCondition(cluster, kubermaticv1.ExternalClusterConditionReachable, corev1.ConditionTrue, "", "")
+
+	nodesReady := corev1.ConditionTrue
+	if readyNodes < len(nodes.Items) {
+		nodesReady = corev1.ConditionFalse
+	}
+	helper.SetExternalClusterCondition(cluster, kubermaticv1.ExternalClusterConditionNodesReady, nodesReady, "", fmt.Sprintf("%d of %d nodes are ready", readyNodes, len(nodes.Items)))
+
+	return nil
+}
+
+func (r *Reconciler) getClusterClient(ctx context.Context, cluster *kubermaticv1.ExternalCluster) (kubernetes.Interface, error) {
+	config, err := r.getKubeconfig(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	return newKubeconfigClient(config)
+}
+
+func newKubeconfigClient(config *api.Config) (kubernetes.Interface, error) {
+	cfg, err := clientcmd.NewDefaultClientConfig(*config, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	cfg.Timeout = clusterClientTimeout
+	return kubernetes.NewForConfig(cfg)
+}
+
+func (r *Reconciler) getKubeconfig(ctx context.Context, cluster *kubermaticv1.ExternalCluster) (*api.Config, error) {
+	secretKeyGetter := provider.SecretKeySelectorValueFuncFactory(ctx, r.Client)
+	rawKubeconfig, err := secretKeyGetter(cluster.Spec.KubeconfigReference, resources.ExternalClusterKubeconfig)
+	if err != nil {
+		return nil, err
 	}
+	kubeconfig, err := base64.StdEncoding.DecodeString(rawKubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	return clientcmd.Load(kubeconfig)
+}
+
+// getCloudClusterStatus returns the state reported by the cloud provider of the cluster and the
+// phase it maps to. provider.ErrNotFound is returned when the cluster does not exist.
+func (r *Reconciler) getCloudClusterStatus(ctx context.Context, cluster *kubermaticv1.ExternalCluster) (string, kubermaticv1.ExternalClusterPhase, error) {
+	cloud := cluster.Spec.CloudSpec
 	if cloud.GKE != nil {
-		r.log.Debugf("reconcile GKE cluster")
-		err := r.createOrUpdateGKEKubeconfig(ctx, cluster)
+		cred, err := resources.GetGCPGKECredentials(ctx, r.Client, cluster)
 		if err != nil {
-			r.log.Errorf("failed to create or update kubeconfig secret %v", err)
-			return reconcile.Result{}, err
+			return "", "", err
 		}
-		// the kubeconfig token is valid 1h, it will update token every 30min
-		return reconcile.Result{RequeueAfter: time.Minute * 30}, nil
+		state, err := gcp.GetGKEClusterStatus(ctx, cred.ServiceAccount, cloud.GKE.Name, cloud.GKE.Zone)
+		return state, convertGKEStatus(state), err
 	}
 	if cloud.EKS != nil {
-		r.log.Debugf("reconcile EKS cluster %v", cluster.Name)
-		err := r.createOrUpdateEKSKubeconfig(ctx, cluster)
+		cred, err := resources.GetAWSEKSCredentials(ctx, r.Client, cluster)
 		if err != nil {
-			r.log.Errorf("failed to create or update kubeconfig secret %v", err)
-			return reconcile.Result{}, err
+			return "", "", err
 		}
-		// the kubeconfig token is valid 14min, it will update token every 10min
-		return reconcile.Result{RequeueAfter: time.Minute * 10}, nil
+		state, err := aws.GetEKSClusterStatus(cred.AccessKeyID, cred.SecretAccessKey, cloud.EKS.Name, cloud.EKS.Region)
+		return state, convertEKSStatus(state), err
 	}
 	if cloud.AKS != nil {
-		r.log.Debugf("reconcile AKS cluster %v", cluster.Name)
-		err := r.createOrUpdateAKSKubeconfig(ctx, cluster)
+		cred, err := resources.GetAzureAKSCredentials(ctx, r.Client, cluster)
 		if err != nil {
-			r.log.Errorf("failed to create or update kubeconfig secret %v", err)
-			return reconcile.Result{}, err
+			return "", "", err
 		}
-		return reconcile.Result{}, nil
+		state, err := azure.GetAKSClusterStatus(ctx, azure.Credentials(cred), cloud.AKS.Name, cloud.AKS.ResourceGroup)
+		return state, convertAKSStatus(state), err
+	}
+	return "", kubermaticv1.ExternalClusterPhaseUnknown, nil
+}
+
+func (r *Reconciler) deleteCloudCluster(ctx context.Context, cluster *kubermaticv1.ExternalCluster) error {
+	cloud := cluster.Spec.CloudSpec
+	if cloud.GKE != nil {
+		cred, err := resources.GetGCPGKECredentials(ctx, r.Client, cluster)
+		if err != nil {
+			return err
+		}
+		return gcp.DeleteGKECluster(ctx, cred.ServiceAccount, cloud.GKE.Name, cloud.GKE.Zone)
+	}
+	if cloud.EKS != nil {
+		cred, err := resources.GetAWSEKSCredentials(ctx, r.Client, cluster)
+		if err != nil {
+			return err
+		}
+		return aws.DeleteEKSCluster(cred.AccessKeyID, cred.SecretAccessKey, cloud.EKS.Name, cloud.EKS.Region)
+	}
+	if cloud.AKS != nil {
+		cred, err := resources.GetAzureAKSCredentials(ctx, r.Client, cluster)
+		if err != nil {
+			return err
+		}
+		return azure.DeleteAKSCluster(ctx, azure.Credentials(cred), cloud.AKS.Name, cloud.AKS.ResourceGroup)
+	}
+	return nil
+}
+
+// cleanUpCloudCluster deletes clusters which were created by Kubermatic from their cloud provider.
+// It returns true once the cluster is gone and the finalizer has been removed.
+func (r *Reconciler) cleanUpCloudCluster(ctx context.Context, cluster *kubermaticv1.ExternalCluster) (bool, error) {
+	if cluster.Spec.CloudSpec == nil {
+		return true, r.removeFinalizer(ctx, cluster, kubermaticapiv1.ExternalClusterCloudCleanupFinalizer)
+	}
+
+	state, phase, err := r.getCloudClusterStatus(ctx, cluster)
+	if errors.Is(err, provider.ErrNotFound) {
+		return true, r.removeFinalizer(ctx, cluster, kubermaticapiv1.ExternalClusterCloudCleanupFinalizer)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if phase != kubermaticv1.ExternalClusterPhaseDeleting {
+		if err := r.deleteCloudCluster(ctx, cluster); err != nil {
+			if statusErr := r.updateStatus(ctx, cluster, phase, err.Error()); statusErr != nil {
+				r.log.Errorf("failed to update status of cluster %s: %v", cluster.Name, statusErr)
+			}
+			return false, err
+		}
+	}
+
+	return false, r.updateStatus(ctx, cluster, kubermaticv1.ExternalClusterPhaseDeleting, state)
+}
+
+func (r *Reconciler) removeFinalizer(ctx context.Context, cluster *kubermaticv1.ExternalCluster, finalizer string) error {
+	oldCluster := cluster.DeepCopy()
+	kuberneteshelper.RemoveFinalizer(cluster, finalizer)
+	return r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster))
+}
+
+func (r *Reconciler) updateStatus(ctx context.Context, cluster *kubermaticv1.ExternalCluster, phase kubermaticv1.ExternalClusterPhase, message string) error {
+	if cluster.Status.Phase == phase && cluster.Status.StatusMessage == message {
+		return nil
+	}
+
+	oldCluster := cluster.DeepCopy()
+	cluster.Status.Phase = phase
+	cluster.Status.StatusMessage = message
+	return r.Status().Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster))
+}
+
+func convertGKEStatus(state string) kubermaticv1.ExternalClusterPhase {
+	switch state {
+	case "PROVISIONING":
+		return kubermaticv1.ExternalClusterPhaseProvisioning
+	case "RUNNING":
+		return kubermaticv1.ExternalClusterPhaseRunning
+	case "RECONCILING":
+		return kubermaticv1.ExternalClusterPhaseReconciling
+	case "STOPPING":
+		return kubermaticv1.ExternalClusterPhaseDeleting
+	case "ERROR", "DEGRADED":
+		return kubermaticv1.ExternalClusterPhaseError
+	default:
+		return kubermaticv1.ExternalClusterPhaseUnknown
+	}
+}
+
+func convertEKSStatus(state string) kubermaticv1.ExternalClusterPhase {
+	switch state {
+	case eks.ClusterStatusCreating:
+		return kubermaticv1.ExternalClusterPhaseProvisioning
+	case eks.ClusterStatusActive:
+		return kubermaticv1.ExternalClusterPhaseRunning
+	case eks.ClusterStatusUpdating:
+		return kubermaticv1.ExternalClusterPhaseReconciling
+	case eks.ClusterStatusDeleting:
+		return kubermaticv1.ExternalClusterPhaseDeleting
+	case eks.ClusterStatusFailed:
+		return kubermaticv1.ExternalClusterPhaseError
+	default:
+		return kubermaticv1.ExternalClusterPhaseUnknown
+	}
+}
+
+func convertAKSStatus(state string) kubermaticv1.ExternalClusterPhase {
+	switch state {
+	case "Creating":
+		return kubermaticv1.ExternalClusterPhaseProvisioning
+	case "Succeeded":
+		return kubermaticv1.ExternalClusterPhaseRunning
+	case "Updating", "Upgrading", "Scaling", "Starting", "Stopping":
+		return kubermaticv1.ExternalClusterPhaseReconciling
+	case "Deleting":
+		return kubermaticv1.ExternalClusterPhaseDeleting
+	case "Failed", "Canceled":
+		return kubermaticv1.ExternalClusterPhaseError
+	default:
+		return kubermaticv1.ExternalClusterPhaseUnknown
 	}
-	return reconcile.Result{}, nil
 }
 
 func (r *Reconciler) cleanUpKubeconfigSecret(ctx context.Context, cluster *kubermaticv1.ExternalCluster) error {
@@ -18,11 +18,13 @@ package externalcluster
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	providerconfig "github.com/kubermatic/machine-controller/pkg/providerconfig/types"
 	kubermaticapiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1/helper"
 	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
 	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
 	"k8c.io/kubermatic/v2/pkg/resources"
@@ -31,6 +33,10 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes"
+	fakekubernetes "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/kubernetes/scheme"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -107,6 +113,116 @@ func TestReconcile(t *testing.T) {
 	}
 }
 
+func TestReconcileHealth(t *testing.T) {
+	tests := []struct {
+		name              string
+		clusterClient     clusterClientFunc
+		expectedVersion   string
+		expectedNodeCount int32
+		expectedReachable corev1.ConditionStatus
+		expectedReady     corev1.ConditionStatus
+	}{
+		{
+			name: "scenario 1: reachable cluster with one of two nodes ready",
+			clusterClient: func(_ context.Context, _ *kubermaticv1.ExternalCluster) (kubernetes.Interface, error) {
+				client := fakekubernetes.NewSimpleClientset(genNode("node-1", corev1.ConditionTrue), genNode("node-2", corev1.ConditionFalse))
+				client.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.21.3"}
+				return client, nil
+			},
+			expectedVersion:   "v1.21.3",
+			expectedNodeCount: 2,
+			expectedReachable: corev1.ConditionTrue,
+			expectedReady:     corev1.ConditionFalse,
+		},
+		{
+			name: "scenario 2: unreachable cluster",
+			clusterClient: func(_ context.Context, _ *kubermaticv1.ExternalCluster) (kubernetes.Interface, error) {
+				return nil, errors.New("connection refused")
+			},
+			expectedReachable: corev1.ConditionFalse,
+			expectedReady:     corev1.ConditionUnknown,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			testCluster := genExternalCluster("test", metav1.Now())
+			testCluster.DeletionTimestamp = nil
+			kubermaticFakeClient := fake.
+				NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(testCluster).
+				Build()
+
+			ctx := context.Background()
+			target := Reconciler{
+				Client:        kubermaticFakeClient,
+				log:           kubermaticlog.Logger,
+				clusterClient: test.clusterClient,
+			}
+
+			result, err := target.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: testCluster.Name}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.RequeueAfter != healthCheckInterval {
+				t.Fatalf("expected requeue after %v, got %v", healthCheckInterval, result.RequeueAfter)
+			}
+
+			cluster := &kubermaticv1.ExternalCluster{}
+			if err := kubermaticFakeClient.Get(ctx, ctrlruntimeclient.ObjectKey{Name: testCluster.Name}, cluster); err != nil {
+				t.Fatal(err)
+			}
+			if cluster.Status.Version != test.expectedVersion {
+				t.Fatalf("expected version %q, got %q", test.expectedVersion, cluster.Status.Version)
+			}
+			if cluster.Status.NodeCount != test.expectedNodeCount {
+				t.Fatalf("expected %d nodes, got %d", test.expectedNodeCount, cluster.Status.NodeCount)
+			}
+			if !helper.ExternalClusterConditionHasStatus(cluster, kubermaticv1.ExternalClusterConditionReachable, test.expectedReachable) {
+				t.Fatalf("expected condition %s to be %s, got %v", kubermaticv1.ExternalClusterConditionReachable, test.expectedReachable, cluster.Status.Conditions)
+			}
+			if !helper.ExternalClusterConditionHasStatus(cluster, kubermaticv1.ExternalClusterConditionNodesReady, test.expectedReady) {
+				t.Fatalf("expected condition %s to be %s, got %v", kubermaticv1.ExternalClusterConditionNodesReady, test.expectedReady, cluster.Status.Conditions)
+			}
+		})
+	}
+}
+
+func TestConvertCloudClusterStatus(t *testing.T) {
+	tests := []struct {
+		name          string
+		convert       func(string) kubermaticv1.ExternalClusterPhase
+		state         string
+		expectedPhase kubermaticv1.ExternalClusterPhase
+	}{
+		{name: "GKE provisioning", convert: convertGKEStatus, state: "PROVISIONING", expectedPhase: kubermaticv1.ExternalClusterPhaseProvisioning},
+		{name: "GKE running", convert: convertGKEStatus, state: "RUNNING", expectedPhase: kubermaticv1.ExternalClusterPhaseRunning},
+		{name: "GKE upgrading", convert: convertGKEStatus, state: "RECONCILING", expectedPhase: kubermaticv1.ExternalClusterPhaseReconciling},
+		{name: "GKE stopping", convert: convertGKEStatus, state: "STOPPING", expectedPhase: kubermaticv1.ExternalClusterPhaseDeleting},
+		{name: "GKE degraded", convert: convertGKEStatus, state: "DEGRADED", expectedPhase: kubermaticv1.ExternalClusterPhaseError},
+		{name: "GKE unspecified", convert: convertGKEStatus, state: "STATUS_UNSPECIFIED", expectedPhase: kubermaticv1.ExternalClusterPhaseUnknown},
+		{name: "EKS creating", convert: convertEKSStatus, state: "CREATING", expectedPhase: kubermaticv1.ExternalClusterPhaseProvisioning},
+		{name: "EKS active", convert: convertEKSStatus, state: "ACTIVE", expectedPhase: kubermaticv1.ExternalClusterPhaseRunning},
+		{name: "EKS updating", convert: convertEKSStatus, state: "UPDATING", expectedPhase: kubermaticv1.ExternalClusterPhaseReconciling},
+		{name: "EKS deleting", convert: convertEKSStatus, state: "DELETING", expectedPhase: kubermaticv1.ExternalClusterPhaseDeleting},
+		{name: "EKS failed", convert: convertEKSStatus, state: "FAILED", expectedPhase: kubermaticv1.ExternalClusterPhaseError},
+		{name: "AKS creating", convert: convertAKSStatus, state: "Creating", expectedPhase: kubermaticv1.ExternalClusterPhaseProvisioning},
+		{name: "AKS succeeded", convert: convertAKSStatus, state: "Succeeded", expectedPhase: kubermaticv1.ExternalClusterPhaseRunning},
+		{name: "AKS upgrading", convert: convertAKSStatus, state: "Upgrading", expectedPhase: kubermaticv1.ExternalClusterPhaseReconciling},
+		{name: "AKS deleting", convert: convertAKSStatus, state: "Deleting", expectedPhase: kubermaticv1.ExternalClusterPhaseDeleting},
+		{name: "AKS canceled", convert: convertAKSStatus, state: "Canceled", expectedPhase: kubermaticv1.ExternalClusterPhaseError},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if phase := test.convert(test.state); phase != test.expectedPhase {
+				t.Fatalf("expected phase %q for state %q, got %q", test.expectedPhase, test.state, phase)
+			}
+		})
+	}
+}
+
 func genExternalCluster(name string, deletionTimestamp metav1.Time) *kubermaticv1.ExternalCluster {
 	cluster := &kubermaticv1.ExternalCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -129,3 +245,16 @@ func genExternalCluster(name string, deletionTimestamp metav1.Time) *kubermaticv
 
 	return cluster
 }
+
+func genNode(name string, ready corev1.ConditionStatus) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: ready},
+			},
+		},
+	}
+}
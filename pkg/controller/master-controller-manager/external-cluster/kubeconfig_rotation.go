@@ -0,0 +1,324 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalcluster
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1/helper"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd/api"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// kubeconfigServiceAccountName is the ServiceAccount installed in imported clusters whose
+	// tokens replace the credentials of their kubeconfig.
+	kubeconfigServiceAccountName      = "kubermatic-external-cluster"
+	kubeconfigServiceAccountNamespace = metav1.NamespaceSystem
+	kubeconfigServiceAccountAuthInfo  = "kubermatic-service-account"
+	// kubeconfigOriginalContext is a copy of the context the kubeconfig used before its credentials
+	// were first replaced with a ServiceAccount token.
+	kubeconfigOriginalContext = "kubermatic-original"
+
+	defaultKubeconfigTokenValidity = 24 * time.Hour
+	// minKubeconfigTokenValidity is the shortest validity the API server accepts for requested tokens.
+	minKubeconfigTokenValidity = 10 * time.Minute
+
+	// kubeconfigExpiryWarningPeriod is how long before the credentials of a kubeconfig expire
+	// the KubeconfigValid condition turns false, unless the kubeconfig is rotated by Kubermatic.
+	kubeconfigExpiryWarningPeriod = 7 * 24 * time.Hour
+)
+
+// reconcileKubeconfigCredentials records when the credentials of the kubeconfig of an imported
+// cluster expire and rotates them if this was requested for the cluster.
+func (r *Reconciler) reconcileKubeconfigCredentials(ctx context.Context, cluster *kubermaticv1.ExternalCluster) error {
+	if cluster.Spec.KubeconfigReference == nil {
+		return nil
+	}
+
+	config, err := r.getKubeconfig(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	if rotation := cluster.Spec.KubeconfigRotation; rotation != nil {
+		validity := defaultKubeconfigTokenValidity
+		if rotation.TokenValidity != nil {
+			validity = rotation.TokenValidity.Duration
+		}
+		if validity < minKubeconfigTokenValidity {
+			validity = minKubeconfigTokenValidity
+		}
+		if needsKubeconfigRotation(config, validity, time.Now()) {
+			r.log.Debugf("rotate kubeconfig of cluster %s", cluster.Name)
+			if err := r.rotateKubeconfig(ctx, cluster, config, validity); err != nil {
+				return fmt.Errorf("failed to rotate kubeconfig: %v", err)
+			}
+		}
+	}
+
+	expiry, err := kubeconfigExpiry(config)
+	if err != nil {
+		return fmt.Errorf("failed to determine the expiry of the kubeconfig: %v", err)
+	}
+
+	oldCluster := cluster.DeepCopy()
+	setKubeconfigExpiry(cluster, expiry, time.Now())
+	if apiequality.Semantic.DeepEqual(oldCluster.Status, cluster.Status) {
+		return nil
+	}
+	return r.Status().Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster))
+}
+
+// rotateKubeconfig switches the current context of the kubeconfig to a new token of the
+// Kubermatic ServiceAccount and stores it.
+func (r *Reconciler) rotateKubeconfig(ctx context.Context, cluster *kubermaticv1.ExternalCluster, config *api.Config, validity time.Duration) error {
+	token, err := r.rotateServiceAccountToken(ctx, cluster, config, validity)
+	if err != nil {
+		return err
+	}
+	if err := useServiceAccountToken(config, token); err != nil {
+		return err
+	}
+
+	return r.updateKubeconfigSecret(ctx, config, cluster)
+}
+
+// rotateServiceAccountToken requests a new token of the Kubermatic ServiceAccount with the current
+// credentials of the kubeconfig, or with its original ones once the current token has expired or
+// has been rejected, e.g. because the controller was not running for longer than it was valid.
+func (r *Reconciler) rotateServiceAccountToken(ctx context.Context, cluster *kubermaticv1.ExternalCluster, config *api.Config, validity time.Duration) (string, error) {
+	current := config
+	original := originalKubeconfig(config)
+	if original != nil && serviceAccountTokenExpired(config, time.Now()) {
+		current = original
+	}
+
+	token, err := r.requestServiceAccountToken(ctx, current, validity)
+	if current != original && original != nil && (kerrors.IsUnauthorized(err) || kerrors.IsForbidden(err)) {
+		r.log.Debugf("request token for cluster %s with the original credentials of its kubeconfig: %v", cluster.Name, err)
+		return r.requestServiceAccountToken(ctx, original, validity)
+	}
+	return token, err
+}
+
+func (r *Reconciler) requestServiceAccountToken(ctx context.Context, config *api.Config, validity time.Duration) (string, error) {
+	client, err := r.kubeconfigClient(config)
+	if err != nil {
+		return "", fmt.Errorf("failed to create client: %v", err)
+	}
+	return requestServiceAccountToken(ctx, client, validity)
+}
+
+// originalKubeconfig returns a copy of the kubeconfig that uses its original credentials instead
+// of a ServiceAccount token, or nil if the current context uses its original credentials.
+func originalKubeconfig(config *api.Config) *api.Config {
+	currentContext, ok := config.Contexts[config.CurrentContext]
+	if !ok || currentContext.AuthInfo != kubeconfigServiceAccountAuthInfo {
+		return nil
+	}
+	if _, ok := config.Contexts[kubeconfigOriginalContext]; !ok {
+		return nil
+	}
+
+	original := config.DeepCopy()
+	original.CurrentContext = kubeconfigOriginalContext
+	return original
+}
+
+// serviceAccountTokenExpired tells if the ServiceAccount token of the kubeconfig has expired.
+func serviceAccountTokenExpired(config *api.Config, now time.Time) bool {
+	authInfo, ok := config.AuthInfos[kubeconfigServiceAccountAuthInfo]
+	if !ok {
+		return false
+	}
+	expiry := tokenExpiry(authInfo.Token)
+	return expiry != nil && !now.Before(*expiry)
+}
+
+// requestServiceAccountToken makes sure the ServiceAccount for Kubermatic exists in the cluster
+// and requests a new token for it.
+func requestServiceAccountToken(ctx context.Context, client kubernetes.Interface, validity time.Duration) (string, error) {
+	if err := ensureKubeconfigServiceAccount(ctx, client); err != nil {
+		return "", err
+	}
+
+	expirationSeconds := int64(validity.Seconds())
+	tokenRequest := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			ExpirationSeconds: &expirationSeconds,
+		},
+	}
+	tokenRequest, err := client.CoreV1().ServiceAccounts(kubeconfigServiceAccountNamespace).CreateToken(ctx, kubeconfigServiceAccountName, tokenRequest, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to request ServiceAccount token: %w", err)
+	}
+
+	return tokenRequest.Status.Token, nil
+}
+
+// useServiceAccountToken makes the current context of the kubeconfig use the given token. The
+// original credentials are kept in the kubeconfig, together with a copy of the original context.
+func useServiceAccountToken(config *api.Config, token string) error {
+	currentContext, ok := config.Contexts[config.CurrentContext]
+	if !ok {
+		return errors.New("the kubeconfig has no current context")
+	}
+	if currentContext.AuthInfo != kubeconfigServiceAccountAuthInfo {
+		config.Contexts[kubeconfigOriginalContext] = currentContext.DeepCopy()
+	}
+
+	authInfo := api.NewAuthInfo()
+	authInfo.Token = token
+	config.AuthInfos[kubeconfigServiceAccountAuthInfo] = authInfo
+	currentContext.AuthInfo = kubeconfigServiceAccountAuthInfo
+
+	return nil
+}
+
+func ensureKubeconfigServiceAccount(ctx context.Context, client kubernetes.Interface) error {
+	serviceAccount := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kubeconfigServiceAccountName,
+			Namespace: kubeconfigServiceAccountNamespace,
+		},
+	}
+	if _, err := client.CoreV1().ServiceAccounts(kubeconfigServiceAccountNamespace).Create(ctx, serviceAccount, metav1.CreateOptions{}); err != nil && !kerrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create ServiceAccount: %w", err)
+	}
+
+	clusterRoleBinding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("%s:cluster-admin", kubeconfigServiceAccountName),
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      kubeconfigServiceAccountName,
+				Namespace: kubeconfigServiceAccountNamespace,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     "cluster-admin",
+		},
+	}
+	if _, err := client.RbacV1().ClusterRoleBindings().Create(ctx, clusterRoleBinding, metav1.CreateOptions{}); err != nil && !kerrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create ClusterRoleBinding: %w", err)
+	}
+
+	return nil
+}
+
+// needsKubeconfigRotation tells if the current context of the kubeconfig does not use a token of
+// the Kubermatic ServiceAccount yet or if less than a third of the validity of the token is left.
+func needsKubeconfigRotation(config *api.Config, validity time.Duration, now time.Time) bool {
+	currentContext, ok := config.Contexts[config.CurrentContext]
+	if !ok || currentContext.AuthInfo != kubeconfigServiceAccountAuthInfo {
+		return true
+	}
+	authInfo, ok := config.AuthInfos[kubeconfigServiceAccountAuthInfo]
+	if !ok {
+		return true
+	}
+
+	expiry := tokenExpiry(authInfo.Token)
+	if expiry == nil {
+		return true
+	}
+	return expiry.Sub(now) < validity/3
+}
+
+// kubeconfigExpiry returns the time the credentials of the current context of the kubeconfig
+// expire, or nil if they do not expire or their expiry can not be determined.
+func kubeconfigExpiry(config *api.Config) (*time.Time, error) {
+	currentContext, ok := config.Contexts[config.CurrentContext]
+	if !ok {
+		return nil, nil
+	}
+	authInfo, ok := config.AuthInfos[currentContext.AuthInfo]
+	if !ok {
+		return nil, nil
+	}
+
+	if len(authInfo.ClientCertificateData) > 0 {
+		block, _ := pem.Decode(authInfo.ClientCertificateData)
+		if block == nil {
+			return nil, errors.New("the client certificate is not PEM encoded")
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse client certificate: %v", err)
+		}
+		return &cert.NotAfter, nil
+	}
+
+	return tokenExpiry(authInfo.Token), nil
+}
+
+// tokenExpiry returns the expiry of a JWT bearer token. Static tokens or tokens without an
+// expiry yield nil.
+func tokenExpiry(token string) *time.Time {
+	parsed, err := jwt.ParseSigned(token)
+	if err != nil {
+		return nil
+	}
+	claims := jwt.Claims{}
+	if err := parsed.UnsafeClaimsWithoutVerification(&claims); err != nil || claims.Expiry == nil {
+		return nil
+	}
+	expiry := claims.Expiry.Time()
+	return &expiry
+}
+
+func setKubeconfigExpiry(cluster *kubermaticv1.ExternalCluster, expiry *time.Time, now time.Time) {
+	if expiry == nil {
+		cluster.Status.KubeconfigExpiry = nil
+		helper.SetExternalClusterCondition(cluster, kubermaticv1.ExternalClusterConditionKubeconfigValid, corev1.ConditionTrue, "", "")
+		return
+	}
+
+	expiryTime := metav1.NewTime(*expiry)
+	cluster.Status.KubeconfigExpiry = &expiryTime
+	formattedExpiry := expiry.UTC().Format(time.RFC3339)
+
+	switch {
+	case !now.Before(*expiry):
+		helper.SetExternalClusterCondition(cluster, kubermaticv1.ExternalClusterConditionKubeconfigValid, corev1.ConditionFalse, "KubeconfigExpired", fmt.Sprintf("the credentials of the kubeconfig expired at %s", formattedExpiry))
+	case cluster.Spec.KubeconfigRotation == nil && expiry.Sub(now) < kubeconfigExpiryWarningPeriod:
+		helper.SetExternalClusterCondition(cluster, kubermaticv1.ExternalClusterConditionKubeconfigValid, corev1.ConditionFalse, "KubeconfigExpiring", fmt.Sprintf("the credentials of the kubeconfig expire at %s", formattedExpiry))
+	default:
+		helper.SetExternalClusterCondition(cluster, kubermaticv1.ExternalClusterConditionKubeconfigValid, corev1.ConditionTrue, "", fmt.Sprintf("the credentials of the kubeconfig expire at %s", formattedExpiry))
+	}
+}
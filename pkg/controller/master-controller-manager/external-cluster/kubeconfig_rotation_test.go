@@ -0,0 +1,281 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalcluster
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1/helper"
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	fakekubernetes "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestKubeconfigRotation(t *testing.T) {
+	tokenExpiry := time.Now().Add(defaultKubeconfigTokenValidity).Truncate(time.Second)
+	token := genToken(t, tokenExpiry)
+
+	userClusterClient := fakekubernetes.NewSimpleClientset()
+	userClusterClient.PrependReactor("create", "serviceaccounts", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "token" {
+			return false, nil, nil
+		}
+		return true, &authenticationv1.TokenRequest{Status: authenticationv1.TokenRequestStatus{Token: token}}, nil
+	})
+
+	kubeconfig, err := clientcmd.Load([]byte(testKubeconfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !needsKubeconfigRotation(kubeconfig, defaultKubeconfigTokenValidity, time.Now()) {
+		t.Fatal("expected kubeconfig with static token to be rotated")
+	}
+
+	ctx := context.Background()
+	requestedToken, err := requestServiceAccountToken(ctx, userClusterClient, defaultKubeconfigTokenValidity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requestedToken != token {
+		t.Fatalf("expected token %q, got %q", token, requestedToken)
+	}
+	if _, err := userClusterClient.CoreV1().ServiceAccounts(kubeconfigServiceAccountNamespace).Get(ctx, kubeconfigServiceAccountName, metav1.GetOptions{}); err != nil {
+		t.Fatalf("expected ServiceAccount to be created: %v", err)
+	}
+	if _, err := userClusterClient.RbacV1().ClusterRoleBindings().Get(ctx, kubeconfigServiceAccountName+":cluster-admin", metav1.GetOptions{}); err != nil {
+		t.Fatalf("expected ClusterRoleBinding to be created: %v", err)
+	}
+
+	// a second rotation reuses the existing ServiceAccount
+	if _, err := requestServiceAccountToken(ctx, userClusterClient, defaultKubeconfigTokenValidity); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := useServiceAccountToken(kubeconfig, requestedToken); err != nil {
+		t.Fatal(err)
+	}
+	if authInfo := kubeconfig.Contexts[kubeconfig.CurrentContext].AuthInfo; authInfo != kubeconfigServiceAccountAuthInfo {
+		t.Fatalf("expected current context to use %q, got %q", kubeconfigServiceAccountAuthInfo, authInfo)
+	}
+	if err := useServiceAccountToken(kubeconfig, requestedToken); err != nil {
+		t.Fatal(err)
+	}
+	if original := originalKubeconfig(kubeconfig); original == nil || original.Contexts[original.CurrentContext].AuthInfo != "admin" {
+		t.Fatal("expected kubeconfig to keep its original context")
+	}
+
+	expiry, err := kubeconfigExpiry(kubeconfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expiry == nil || !expiry.Equal(tokenExpiry) {
+		t.Fatalf("expected kubeconfig expiry %v, got %v", tokenExpiry, expiry)
+	}
+
+	if needsKubeconfigRotation(kubeconfig, defaultKubeconfigTokenValidity, time.Now()) {
+		t.Fatal("expected fresh token not to be rotated")
+	}
+	if !needsKubeconfigRotation(kubeconfig, defaultKubeconfigTokenValidity, tokenExpiry.Add(-time.Hour)) {
+		t.Fatal("expected token which is about to expire to be rotated")
+	}
+}
+
+func TestRotateServiceAccountToken(t *testing.T) {
+	tests := []struct {
+		name             string
+		tokenExpiry      time.Time
+		tokenRejected    bool
+		expectedAuthInfo []string
+	}{
+		{
+			name:             "valid token is rotated with itself",
+			tokenExpiry:      time.Now().Add(time.Hour),
+			expectedAuthInfo: []string{kubeconfigServiceAccountAuthInfo},
+		},
+		{
+			name:             "expired token is rotated with the original credentials",
+			tokenExpiry:      time.Now().Add(-time.Hour),
+			expectedAuthInfo: []string{"admin"},
+		},
+		{
+			name:             "rejected token is rotated with the original credentials",
+			tokenExpiry:      time.Now().Add(time.Hour),
+			tokenRejected:    true,
+			expectedAuthInfo: []string{kubeconfigServiceAccountAuthInfo, "admin"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			kubeconfig, err := clientcmd.Load([]byte(testKubeconfig))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := useServiceAccountToken(kubeconfig, genToken(t, test.tokenExpiry)); err != nil {
+				t.Fatal(err)
+			}
+
+			cluster := genExternalCluster("test", metav1.Now())
+			cluster.DeletionTimestamp = nil
+
+			newToken := genToken(t, time.Now().Add(defaultKubeconfigTokenValidity))
+			var usedAuthInfo []string
+			r := &Reconciler{
+				log: kubermaticlog.Logger,
+				kubeconfigClient: func(config *api.Config) (kubernetes.Interface, error) {
+					authInfo := config.Contexts[config.CurrentContext].AuthInfo
+					usedAuthInfo = append(usedAuthInfo, authInfo)
+
+					client := fakekubernetes.NewSimpleClientset()
+					client.PrependReactor("create", "serviceaccounts", func(action k8stesting.Action) (bool, runtime.Object, error) {
+						if action.GetSubresource() != "token" {
+							return false, nil, nil
+						}
+						if authInfo == kubeconfigServiceAccountAuthInfo && test.tokenRejected {
+							return true, nil, kerrors.NewUnauthorized("token has been revoked")
+						}
+						return true, &authenticationv1.TokenRequest{Status: authenticationv1.TokenRequestStatus{Token: newToken}}, nil
+					})
+					return client, nil
+				},
+			}
+
+			token, err := r.rotateServiceAccountToken(ctx, cluster, kubeconfig, defaultKubeconfigTokenValidity)
+			if err != nil {
+				t.Fatalf("failed to rotate token: %v", err)
+			}
+			if token != newToken {
+				t.Errorf("expected the new token, got %q", token)
+			}
+			if fmt.Sprint(usedAuthInfo) != fmt.Sprint(test.expectedAuthInfo) {
+				t.Errorf("expected token to be requested with %v, got %v", test.expectedAuthInfo, usedAuthInfo)
+			}
+		})
+	}
+}
+
+func TestSetKubeconfigExpiry(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name           string
+		expiry         *time.Time
+		rotation       *kubermaticv1.ExternalClusterKubeconfigRotation
+		expectedStatus corev1.ConditionStatus
+		expectedReason string
+	}{
+		{
+			name:           "scenario 1: credentials without expiry",
+			expectedStatus: corev1.ConditionTrue,
+		},
+		{
+			name:           "scenario 2: expired credentials",
+			expiry:         timePtr(now.Add(-time.Minute)),
+			expectedStatus: corev1.ConditionFalse,
+			expectedReason: "KubeconfigExpired",
+		},
+		{
+			name:           "scenario 3: credentials which are about to expire",
+			expiry:         timePtr(now.Add(24 * time.Hour)),
+			expectedStatus: corev1.ConditionFalse,
+			expectedReason: "KubeconfigExpiring",
+		},
+		{
+			name:           "scenario 4: rotated credentials",
+			expiry:         timePtr(now.Add(24 * time.Hour)),
+			rotation:       &kubermaticv1.ExternalClusterKubeconfigRotation{},
+			expectedStatus: corev1.ConditionTrue,
+		},
+		{
+			name:           "scenario 5: long-lived credentials",
+			expiry:         timePtr(now.Add(365 * 24 * time.Hour)),
+			expectedStatus: corev1.ConditionTrue,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cluster := &kubermaticv1.ExternalCluster{}
+			cluster.Spec.KubeconfigRotation = test.rotation
+
+			setKubeconfigExpiry(cluster, test.expiry, now)
+
+			_, condition := helper.GetExternalClusterCondition(cluster, kubermaticv1.ExternalClusterConditionKubeconfigValid)
+			if condition == nil {
+				t.Fatal("expected KubeconfigValid condition to be set")
+			}
+			if condition.Status != test.expectedStatus || condition.Reason != test.expectedReason {
+				t.Fatalf("expected condition %s/%q, got %s/%q", test.expectedStatus, test.expectedReason, condition.Status, condition.Reason)
+			}
+			if (test.expiry == nil) != (cluster.Status.KubeconfigExpiry == nil) {
+				t.Fatalf("expected kubeconfig expiry %v, got %v", test.expiry, cluster.Status.KubeconfigExpiry)
+			}
+		})
+	}
+}
+
+func genToken(t *testing.T, expiry time.Time) string {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte("0123456789abcdef0123456789abcdef")}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwt.Signed(signer).Claims(jwt.Claims{Expiry: jwt.NewNumericDate(expiry)}).CompactSerialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://test.example.com
+  name: test
+contexts:
+- context:
+    cluster: test
+    user: admin
+  name: default
+current-context: default
+users:
+- name: admin
+  user:
+    token: static-token
+`
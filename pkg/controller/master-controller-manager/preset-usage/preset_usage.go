@@ -0,0 +1,195 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package presetusage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	controllerutil "k8c.io/kubermatic/v2/pkg/controller/util"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/util/workerlabel"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const ControllerName = "kubermatic_preset_usage_controller"
+
+type reconciler struct {
+	log          *zap.SugaredLogger
+	masterClient ctrlruntimeclient.Client
+	seedClients  map[string]ctrlruntimeclient.Client
+}
+
+// requestFromCluster returns a reconcile.Request for the preset the given
+// cluster uses, if any.
+func requestFromCluster(log *zap.SugaredLogger) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(mo ctrlruntimeclient.Object) []reconcile.Request {
+		cluster, ok := mo.(*kubermaticv1.Cluster)
+		if !ok {
+			err := fmt.Errorf("Object was not a cluster but a %T", mo)
+			log.Error(err)
+			utilruntime.HandleError(err)
+			return nil
+		}
+
+		presetName := cluster.Annotations[kubermaticv1.PresetNameAnnotation]
+		if presetName == "" {
+			return nil
+		}
+
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: presetName}}}
+	})
+}
+
+func Add(
+	ctx context.Context,
+	masterManager manager.Manager,
+	seedManagers map[string]manager.Manager,
+	log *zap.SugaredLogger,
+	numWorkers int,
+	workerName string,
+) error {
+	log = log.Named(ControllerName)
+	r := &reconciler{
+		log:          log,
+		masterClient: masterManager.GetClient(),
+		seedClients:  map[string]ctrlruntimeclient.Client{},
+	}
+
+	ctrlOpts := controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: numWorkers,
+	}
+	c, err := controller.New(ControllerName, masterManager, ctrlOpts)
+	if err != nil {
+		return fmt.Errorf("failed to construct controller: %v", err)
+	}
+
+	for seedName, seedManager := range seedManagers {
+		r.seedClients[seedName] = seedManager.GetClient()
+
+		// clusters stop using a preset when their credentials are rotated, so both the old
+		// and the new preset of an updated cluster are enqueued
+		seedClusterWatch := &source.Kind{Type: &kubermaticv1.Cluster{}}
+		if err := seedClusterWatch.InjectCache(seedManager.GetCache()); err != nil {
+			return fmt.Errorf("failed to inject cache for seed %q into watch: %v", seedName, err)
+		}
+		if err := c.Watch(seedClusterWatch, requestFromCluster(log), workerlabel.Predicates(workerName)); err != nil {
+			return fmt.Errorf("failed to watch clusters in seed %q: %v", seedName, err)
+		}
+	}
+
+	if err := c.Watch(&source.Kind{Type: &kubermaticv1.Preset{}}, &handler.EnqueueRequestForObject{}, workerlabel.Predicates(workerName)); err != nil {
+		return fmt.Errorf("failed to watch presets: %v", err)
+	}
+
+	return nil
+}
+
+func (r *reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	log := r.log.With("preset", request.Name)
+	log.Debug("Processing")
+
+	err := r.reconcile(ctx, log, request)
+	if controllerutil.IsCacheNotStarted(err) {
+		return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+	if err != nil {
+		log.Errorw("ReconcilingError", zap.Error(err))
+	}
+	return reconcile.Result{}, err
+}
+
+func (r *reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, request reconcile.Request) error {
+	preset := &kubermaticv1.Preset{}
+	if err := r.masterClient.Get(ctx, request.NamespacedName, preset); err != nil {
+		if kerrors.IsNotFound(err) {
+			log.Debug("Didn't find preset, returning")
+			return nil
+		}
+
+		return fmt.Errorf("failed to get preset %s: %w", request.Name, err)
+	}
+
+	clusters := []kubermaticv1.PresetClusterReference{}
+
+	var errs []error
+	for seedName, seedClient := range r.seedClients {
+		clusterList := &kubermaticv1.ClusterList{}
+		if err := seedClient.List(ctx, clusterList); err != nil {
+			if controllerutil.IsCacheNotStarted(err) {
+				return err
+			}
+			errs = append(errs, fmt.Errorf("failed to list clusters in seed %q: %w", seedName, err))
+			continue
+		}
+
+		for _, cluster := range clusterList.Items {
+			if cluster.Annotations[kubermaticv1.PresetNameAnnotation] != preset.Name {
+				continue
+			}
+
+			clusters = append(clusters, kubermaticv1.PresetClusterReference{
+				Name:      cluster.Name,
+				ProjectID: cluster.Labels[kubermaticv1.ProjectIDLabelKey],
+				Seed:      seedName,
+			})
+		}
+	}
+
+	// An incomplete index would allow to delete presets that are still in use,
+	// so the status is only updated once all seeds have been listed.
+	if len(errs) > 0 {
+		return utilerrors.NewAggregate(errs)
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].Seed != clusters[j].Seed {
+			return clusters[i].Seed < clusters[j].Seed
+		}
+		return clusters[i].Name < clusters[j].Name
+	})
+
+	if equality.Semantic.DeepEqual(preset.Status.Clusters, clusters) {
+		return nil
+	}
+
+	oldPreset := preset.DeepCopy()
+	preset.Status.Clusters = clusters
+
+	log.Debugw("Updating preset usage", "clusters", len(clusters))
+	if err := r.masterClient.Status().Patch(ctx, preset, ctrlruntimeclient.MergeFrom(oldPreset)); err != nil {
+		return fmt.Errorf("failed to update status of preset %s: %w", preset.Name, err)
+	}
+
+	return nil
+}
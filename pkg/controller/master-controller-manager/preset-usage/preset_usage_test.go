@@ -0,0 +1,126 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package presetusage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-test/deep"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const presetName = "my-preset"
+
+func TestReconciliation(t *testing.T) {
+	testCases := []struct {
+		name             string
+		preset           *kubermaticv1.Preset
+		seedClusters     map[string][]ctrlruntimeclient.Object
+		expectedClusters []kubermaticv1.PresetClusterReference
+	}{
+		{
+			name:   "clusters of all seeds are recorded",
+			preset: genPreset(),
+			seedClusters: map[string][]ctrlruntimeclient.Object{
+				"seed-b": {genCluster("cluster-c", presetName, "project-2")},
+				"seed-a": {
+					genCluster("cluster-b", presetName, "project-1"),
+					genCluster("cluster-a", presetName, "project-1"),
+					genCluster("other-cluster", "other-preset", "project-1"),
+					genCluster("no-preset", "", "project-1"),
+				},
+			},
+			expectedClusters: []kubermaticv1.PresetClusterReference{
+				{Name: "cluster-a", ProjectID: "project-1", Seed: "seed-a"},
+				{Name: "cluster-b", ProjectID: "project-1", Seed: "seed-a"},
+				{Name: "cluster-c", ProjectID: "project-2", Seed: "seed-b"},
+			},
+		},
+		{
+			name: "clusters that no longer use the preset are removed",
+			preset: genPreset(kubermaticv1.PresetClusterReference{
+				Name: "rotated-cluster", ProjectID: "project-1", Seed: "seed-a",
+			}),
+			seedClusters: map[string][]ctrlruntimeclient.Object{
+				"seed-a": {genCluster("rotated-cluster", "", "project-1")},
+			},
+			expectedClusters: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			masterClient := fakectrlruntimeclient.NewClientBuilder().WithObjects(tc.preset).Build()
+
+			seedClients := map[string]ctrlruntimeclient.Client{}
+			for seedName, clusters := range tc.seedClusters {
+				seedClients[seedName] = fakectrlruntimeclient.NewClientBuilder().WithObjects(clusters...).Build()
+			}
+
+			r := &reconciler{
+				log:          kubermaticlog.Logger,
+				masterClient: masterClient,
+				seedClients:  seedClients,
+			}
+
+			ctx := context.Background()
+			request := reconcile.Request{NamespacedName: types.NamespacedName{Name: presetName}}
+			if _, err := r.Reconcile(ctx, request); err != nil {
+				t.Fatalf("reconciling failed: %v", err)
+			}
+
+			preset := &kubermaticv1.Preset{}
+			if err := masterClient.Get(ctx, request.NamespacedName, preset); err != nil {
+				t.Fatalf("failed to get preset: %v", err)
+			}
+
+			if diff := deep.Equal(preset.Status.Clusters, tc.expectedClusters); diff != nil {
+				t.Fatalf("usage of preset differs from expected one, diff: %v", diff)
+			}
+		})
+	}
+}
+
+func genPreset(clusters ...kubermaticv1.PresetClusterReference) *kubermaticv1.Preset {
+	return &kubermaticv1.Preset{
+		ObjectMeta: metav1.ObjectMeta{Name: presetName},
+		Status:     kubermaticv1.PresetStatus{Clusters: clusters},
+	}
+}
+
+func genCluster(name, presetName, projectID string) *kubermaticv1.Cluster {
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      map[string]string{kubermaticv1.ProjectIDLabelKey: projectID},
+			Annotations: map[string]string{},
+		},
+	}
+	if presetName != "" {
+		cluster.Annotations[kubermaticv1.PresetNameAnnotation] = presetName
+	}
+	return cluster
+}
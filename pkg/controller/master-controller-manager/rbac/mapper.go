@@ -233,6 +233,49 @@ func generateClusterRBACRoleBindingForResourceWithServiceAccount(resourceName, k
 	return binding
 }
 
+// generateClusterRBACRoleForNamedResourceAndServiceAccount generates a ClusterRole granting the given verbs on a named
+// resource, which is meant to be bound to a single ServiceAccount instead of a project group
+func generateClusterRBACRoleForNamedResourceAndServiceAccount(kind, policyResource, policyAPIGroups, policyResourceName, sa string, verbs []string, oRef metav1.OwnerReference) *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            generateRBACRoleNameForNamedResourceWithServiceAccount(kind, policyResourceName, sa),
+			OwnerReferences: []metav1.OwnerReference{oRef},
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups:     []string{policyAPIGroups},
+				Resources:     []string{policyResource},
+				ResourceNames: []string{policyResourceName},
+				Verbs:         verbs,
+			},
+		},
+	}
+}
+
+// generateClusterRBACRoleBindingForNamedResourceAndServiceAccount binds the ClusterRole generated by
+// generateClusterRBACRoleForNamedResourceAndServiceAccount to the ServiceAccount
+func generateClusterRBACRoleBindingForNamedResourceAndServiceAccount(kind, resourceName, sa, namespace string, oRef metav1.OwnerReference) *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            generateRBACRoleNameForNamedResourceWithServiceAccount(kind, resourceName, sa),
+			OwnerReferences: []metav1.OwnerReference{oRef},
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				APIGroup:  "",
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      sa,
+				Namespace: namespace,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     generateRBACRoleNameForNamedResourceWithServiceAccount(kind, resourceName, sa),
+		},
+	}
+}
+
 func generateRBACRoleBindingForResource(resourceName, groupName, namespace string) *rbacv1.RoleBinding {
 	binding := &rbacv1.RoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
@@ -35,6 +35,9 @@ import (
 
 const (
 	EtcdLauncherServiceAccountName = "etcd-launcher"
+
+	// etcdLauncherClusterStatusKind names the RBAC resources allowing etcd-launcher to report conditions on its Cluster
+	etcdLauncherClusterStatusKind = "ClusterStatus"
 )
 
 // syncClusterScopedProjectResource generates RBAC Role and Binding for a cluster-scoped resource that belongs to a project.
@@ -1236,6 +1239,9 @@ func (c *resourcesController) ensureRBACForEtcdLauncher(ctx context.Context, cli
 	if err := c.ensureClusterRBACRoleBindingForEtcdLauncher(ctx, fmt.Sprintf("cluster-%s-ca-bundle", cluster.Name), "Configmap", cluster.Status.NamespaceName, projectName, metaObject); err != nil {
 		return fmt.Errorf("failed to sync RBAC ClusterRoleBinding for %s resource for %s cluster provider: %v", rmapping, c.providerName, err)
 	}
+	if err := c.ensureClusterRBACForEtcdLauncherClusterStatus(ctx, cluster); err != nil {
+		return fmt.Errorf("failed to sync etcd launcher RBAC for reporting the status of %s resource for %s cluster provider: %v", rmapping, c.providerName, err)
+	}
 	if err := c.ensureRBACRoleForEtcdLauncher(ctx, metaObject, kubermaticv1.EtcdRestoreResourceName, kubermaticv1.GroupName, kubermaticv1.EtcdRestoreKindName); err != nil {
 		return fmt.Errorf("failed to sync etcd restore RBAC Role for %s resource for %s cluster provider in namespace %s, due to = %v", rmapping, c.providerName, metaObject.GetNamespace(), err)
 	}
@@ -1257,6 +1263,66 @@ func (c *resourcesController) ensureRBACForEtcdLauncher(ctx context.Context, cli
 	return nil
 }
 
+// ensureClusterRBACForEtcdLauncherClusterStatus ensures the ClusterRole and ClusterRoleBinding allowing the etcd launcher
+// to patch its Cluster, which it needs to maintain the etcd database condition
+func (c *resourcesController) ensureClusterRBACForEtcdLauncherClusterStatus(ctx context.Context, cluster *kubermaticv1.Cluster) error {
+	oRef := metav1.OwnerReference{
+		APIVersion: kubermaticv1.SchemeGroupVersion.String(),
+		Kind:       kubermaticv1.ClusterKindName,
+		UID:        cluster.GetUID(),
+		Name:       cluster.GetName(),
+	}
+
+	generatedClusterRole := generateClusterRBACRoleForNamedResourceAndServiceAccount(
+		etcdLauncherClusterStatusKind,
+		kubermaticv1.ClusterResourceName,
+		kubermaticv1.SchemeGroupVersion.Group,
+		cluster.Name,
+		EtcdLauncherServiceAccountName,
+		[]string{"patch"},
+		oRef,
+	)
+
+	var existingClusterRole rbacv1.ClusterRole
+	if err := c.client.Get(ctx, ctrlruntimeclient.ObjectKey{Name: generatedClusterRole.Name}, &existingClusterRole); err != nil {
+		if !kerrors.IsNotFound(err) {
+			return err
+		}
+		if err := c.client.Create(ctx, generatedClusterRole); err != nil {
+			return err
+		}
+	} else if !equality.Semantic.DeepEqual(existingClusterRole.Rules, generatedClusterRole.Rules) {
+		updatedClusterRole := existingClusterRole.DeepCopy()
+		updatedClusterRole.Rules = generatedClusterRole.Rules
+		if err := c.client.Update(ctx, updatedClusterRole); err != nil {
+			return err
+		}
+	}
+
+	generatedClusterRoleBinding := generateClusterRBACRoleBindingForNamedResourceAndServiceAccount(
+		etcdLauncherClusterStatusKind,
+		cluster.Name,
+		EtcdLauncherServiceAccountName,
+		cluster.Status.NamespaceName,
+		oRef,
+	)
+
+	var existingClusterRoleBinding rbacv1.ClusterRoleBinding
+	if err := c.client.Get(ctx, ctrlruntimeclient.ObjectKey{Name: generatedClusterRoleBinding.Name}, &existingClusterRoleBinding); err != nil {
+		if kerrors.IsNotFound(err) {
+			return c.client.Create(ctx, generatedClusterRoleBinding)
+		}
+		return err
+	}
+
+	if equality.Semantic.DeepEqual(existingClusterRoleBinding.Subjects, generatedClusterRoleBinding.Subjects) {
+		return nil
+	}
+	updatedClusterRoleBinding := existingClusterRoleBinding.DeepCopy()
+	updatedClusterRoleBinding.Subjects = generatedClusterRoleBinding.Subjects
+	return c.client.Update(ctx, updatedClusterRoleBinding)
+}
+
 func (c *resourcesController) ensureRBACRoleForEtcdBackupConfigs(ctx context.Context, projectName string, object metav1.Object) error {
 	cluster, ok := object.(*kubermaticv1.Cluster)
 	if !ok {
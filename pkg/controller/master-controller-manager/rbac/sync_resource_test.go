@@ -159,6 +159,29 @@ func TestSyncProjectResourcesClusterWide(t *testing.T) {
 						},
 					},
 				},
+
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "kubermatic:clusterstatus-abcd:etcd-launcher",
+						OwnerReferences: []metav1.OwnerReference{
+							{
+								APIVersion: kubermaticv1.SchemeGroupVersion.String(),
+								Kind:       kubermaticv1.ClusterKindName,
+								Name:       "abcd",
+								UID:        "abcdID", // set manually
+							},
+						},
+						ResourceVersion: "1",
+					},
+					Rules: []rbacv1.PolicyRule{
+						{
+							APIGroups:     []string{kubermaticv1.SchemeGroupVersion.Group},
+							Resources:     []string{kubermaticv1.ClusterResourceName},
+							ResourceNames: []string{"abcd"},
+							Verbs:         []string{"patch"},
+						},
+					},
+				},
 			},
 
 			expectedClusterRoleBindings: []*rbacv1.ClusterRoleBinding{
@@ -295,6 +318,33 @@ func TestSyncProjectResourcesClusterWide(t *testing.T) {
 						Name:     "kubermatic:configmap-cluster-abcd-ca-bundle:viewers-thunderball",
 					},
 				},
+
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "kubermatic:clusterstatus-abcd:etcd-launcher",
+						OwnerReferences: []metav1.OwnerReference{
+							{
+								APIVersion: kubermaticv1.SchemeGroupVersion.String(),
+								Kind:       kubermaticv1.ClusterKindName,
+								Name:       "abcd",
+								UID:        "abcdID", // set manually
+							},
+						},
+						ResourceVersion: "1",
+					},
+					Subjects: []rbacv1.Subject{
+						{
+							Namespace: "cluster-abcd",
+							Kind:      "ServiceAccount",
+							Name:      "etcd-launcher",
+						},
+					},
+					RoleRef: rbacv1.RoleRef{
+						APIGroup: rbacv1.GroupName,
+						Kind:     "ClusterRole",
+						Name:     "kubermatic:clusterstatus-abcd:etcd-launcher",
+					},
+				},
 			},
 		},
 
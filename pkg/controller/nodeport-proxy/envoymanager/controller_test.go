@@ -26,6 +26,7 @@ import (
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	envoyclusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	envoycorev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
@@ -310,6 +311,56 @@ func TestSync(t *testing.T) {
 				"sni_listener":       makeSNIListener(t, 8443, hostClusterName{Cluster: "test/my-service-https", Hostname: "host.com"}),
 			},
 		},
+		{
+			name: "both-sni-and-tunneling-with-source-ranges",
+			resources: []ctrlruntimeclient.Object{
+				test.NewServiceBuilder(test.NamespacedName{Name: "my-service", Namespace: "test"}).
+					WithCreationTimestamp(timeRef.Add(1*time.Hour)).
+					WithAnnotation(nodeportproxy.DefaultExposeAnnotationKey, "SNI,Tunneling").
+					WithAnnotation(nodeportproxy.PortHostMappingAnnotationKey, `{"https": "host.com"}`).
+					WithAnnotation(nodeportproxy.SourceRangesAnnotationKey, "10.0.0.0/8, 192.168.1.1/32").
+					WithServicePort("https", 443, 0, intstr.FromString("https"), corev1.ProtocolTCP).
+					Build(),
+				test.NewEndpointsBuilder(test.NamespacedName{Name: "my-service", Namespace: "test"}).
+					WithEndpointsSubset().
+					WithEndpointPort("https", 8443, corev1.ProtocolTCP).
+					WithReadyAddressIP("172.16.0.1").
+					DoneWithEndpointSubset().Build(),
+			},
+			tunnelingListenerPort: 8080,
+			sniListenerPort:       8443,
+			expectedClusters: map[string]*envoyclusterv3.Cluster{
+				"test/my-service-https": makeCluster(t, "test/my-service-https", 8443, "172.16.0.1"),
+			},
+			expectedListener: map[string]*envoylistenerv3.Listener{
+				"tunneling_listener": makeTunnelingListener(t, 8080, hostClusterName{Cluster: "test/my-service-https", Hostname: "my-service.test.svc.cluster.local:443", SourceRanges: []*envoycorev3.CidrRange{
+					{AddressPrefix: "10.0.0.0", PrefixLen: wrapperspb.UInt32(8)},
+					{AddressPrefix: "192.168.1.1", PrefixLen: wrapperspb.UInt32(32)},
+				}}),
+				"sni_listener": makeSNIListener(t, 8443, hostClusterName{Cluster: "test/my-service-https", Hostname: "host.com", SourceRanges: []*envoycorev3.CidrRange{
+					{AddressPrefix: "10.0.0.0", PrefixLen: wrapperspb.UInt32(8)},
+					{AddressPrefix: "192.168.1.1", PrefixLen: wrapperspb.UInt32(32)},
+				}}),
+			},
+		},
+		{
+			name: "nodeport-invalid-source-ranges",
+			resources: []ctrlruntimeclient.Object{
+				test.NewServiceBuilder(test.NamespacedName{Name: "my-nodeport", Namespace: "test"}).
+					WithServiceType(corev1.ServiceTypeNodePort).
+					WithAnnotation(nodeportproxy.DefaultExposeAnnotationKey, "NodePort").
+					WithAnnotation(nodeportproxy.SourceRangesAnnotationKey, "10.0.0.0").
+					WithServicePort("http", 80, 32001, intstr.FromString("http"), corev1.ProtocolTCP).
+					Build(),
+				test.NewEndpointsBuilder(test.NamespacedName{Name: "my-nodeport", Namespace: "test"}).
+					WithEndpointsSubset().
+					WithEndpointPort("http", 8080, corev1.ProtocolTCP).
+					WithReadyAddressIP("172.16.0.1").
+					DoneWithEndpointSubset().Build(),
+			},
+			expectedListener: map[string]*envoylistenerv3.Listener{},
+			expectedClusters: map[string]*envoyclusterv3.Cluster{},
+		},
 		{
 			name: "both-sni-and-http2-connect-invalid-sni-mapping",
 			resources: []ctrlruntimeclient.Object{
@@ -421,8 +472,9 @@ func makeNodePortListener(t *testing.T, name string, portValue uint32) *envoylis
 }
 
 type hostClusterName struct {
-	Hostname string
-	Cluster  string
+	Hostname     string
+	Cluster      string
+	SourceRanges []*envoycorev3.CidrRange
 }
 
 func makeSNIListener(t *testing.T, portValue uint32, hostClusterNames ...hostClusterName) *envoylistenerv3.Listener {
@@ -452,8 +504,9 @@ func makeSNIListener(t *testing.T, portValue uint32, hostClusterNames ...hostClu
 				},
 			},
 			FilterChainMatch: &envoylistenerv3.FilterChainMatch{
-				ServerNames:       []string{hc.Hostname},
-				TransportProtocol: "tls",
+				ServerNames:        []string{hc.Hostname},
+				TransportProtocol:  "tls",
+				SourcePrefixRanges: hc.SourceRanges,
 			},
 		})
 	}
@@ -466,9 +519,16 @@ func makeSNIListener(t *testing.T, portValue uint32, hostClusterNames ...hostClu
 func makeTunnelingListener(t *testing.T, portValue int, hostClusterNames ...hostClusterName) *envoylistenerv3.Listener {
 	var vhs []*envoyroutev3.VirtualHost
 	for _, hostClusterName := range hostClusterNames {
+		var perFilterConfig map[string]*anypb.Any
+		if hostClusterName.SourceRanges != nil {
+			perFilterConfig = map[string]*anypb.Any{
+				envoywellknown.HTTPRoleBasedAccessControl: makeSourceRangesRBAC(hostClusterName.SourceRanges),
+			}
+		}
 		vhs = append(vhs, &envoyroutev3.VirtualHost{
-			Name:    hostClusterName.Cluster,
-			Domains: []string{hostClusterName.Hostname},
+			Name:                 hostClusterName.Cluster,
+			Domains:              []string{hostClusterName.Hostname},
+			TypedPerFilterConfig: perFilterConfig,
 			Routes: []*envoyroutev3.Route{
 				{
 					Match: &envoyroutev3.RouteMatch{
@@ -35,9 +35,11 @@ import (
 	envoycorev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoyendpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
 	envoylistenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoyrbacv3 "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
 	envoyroutev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	envoylistenerlogv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/file/v3"
 	envoyhealthv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/health_check/v3"
+	envoyrbacfilterv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/rbac/v3"
 	envoyhttpconnectionmanagerv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	envoytcpfilterv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
 	envoycachetype "github.com/envoyproxy/go-control-plane/pkg/cache/types"
@@ -102,6 +104,12 @@ func (sb *snapshotBuilder) addService(svc *corev1.Service, eps *corev1.Endpoints
 	if len(expTypes) == 0 {
 		svcLog.Debug("skipping service: no expose types provided")
 	}
+	// Rather not expose the service at all than exposing it to anyone.
+	sourceRanges, err := sourceRangesFromAnnotation(svc)
+	if err != nil {
+		svcLog.Warnw("skipping service: invalid source ranges", "error", err)
+		return
+	}
 
 	// Exclude all ports by default, to avoid creating unused clusters.
 	var includePorts sets.String
@@ -112,20 +120,20 @@ func (sb *snapshotBuilder) addService(svc *corev1.Service, eps *corev1.Endpoints
 			svcLog.Warn("skipping service: it is not of type NodePort", "service")
 		} else {
 			// Add listeners for nodeport services
-			ls, ports := sb.makeListenersForNodePortService(svc)
+			ls, ports := sb.makeListenersForNodePortService(svc, sourceRanges)
 			includePorts = ports.Union(includePorts)
 			sb.listeners = append(sb.listeners, ls...)
 		}
 	}
 	// Create filter chains for SNIType
 	if expTypes.Has(nodeportproxy.SNIType) && sb.IsSNIEnabled() {
-		fcs, ports := sb.makeSNIFilterChains(svcLog, svc)
+		fcs, ports := sb.makeSNIFilterChains(svcLog, svc, sourceRanges)
 		includePorts = ports.Union(includePorts)
 		sb.fcs = append(sb.fcs, fcs...)
 	}
 	// Create virtual hosts for TunnelingType
 	if expTypes.Has(nodeportproxy.TunnelingType) && sb.IsTunnelingEnabled() {
-		vhs, ports := sb.makeTunnelingVirtualHosts(svc)
+		vhs, ports := sb.makeTunnelingVirtualHosts(svc, sourceRanges)
 		includePorts = ports.Union(includePorts)
 		sb.vhs = append(sb.vhs, vhs...)
 	}
@@ -138,7 +146,7 @@ func (sb *snapshotBuilder) addService(svc *corev1.Service, eps *corev1.Endpoints
 // makeSNIFilterChains returns the FilterChains for the given service and the
 // set of ports that are exposed. Note that the set can be nil, don't try to
 // write to it before doing a nil check.
-func (sb *snapshotBuilder) makeSNIFilterChains(svcLog *zap.SugaredLogger, svc *corev1.Service) ([]*envoylistenerv3.FilterChain, sets.String) {
+func (sb *snapshotBuilder) makeSNIFilterChains(svcLog *zap.SugaredLogger, svc *corev1.Service, sourceRanges []*envoycorev3.CidrRange) ([]*envoylistenerv3.FilterChain, sets.String) {
 	m, err := sb.portHostMappingGetter(svc)
 	if err != nil {
 		svcLog.Warnw("port host mapping is required with SNI expose type", "error", err)
@@ -163,7 +171,7 @@ func (sb *snapshotBuilder) makeSNIFilterChains(svcLog *zap.SugaredLogger, svc *c
 
 	svcLog.Debugw("creating sni filter chains", "portHostMapping", m)
 	// Besides the filter chains returns the ports that are exposed.
-	return makeSNIFilterChains(svc, m), ports
+	return makeSNIFilterChains(svc, m, sourceRanges), ports
 }
 
 // build returns a new Snapshot from the resources derived by the Services
@@ -205,7 +213,7 @@ func makeAccessLog() []*envoyaccesslogv3.AccessLog {
 	return accessLog
 }
 
-func makeSNIFilterChains(service *corev1.Service, p portHostMapping) []*envoylistenerv3.FilterChain {
+func makeSNIFilterChains(service *corev1.Service, p portHostMapping, sourceRanges []*envoycorev3.CidrRange) []*envoylistenerv3.FilterChain {
 	var sniFilterChains []*envoylistenerv3.FilterChain
 
 	serviceKey := ServiceKey(service)
@@ -235,9 +243,12 @@ func makeSNIFilterChains(service *corev1.Service, p portHostMapping) []*envoylis
 						},
 					},
 				},
+				// Connections from other sources do not match any filter
+				// chain and are closed.
 				FilterChainMatch: &envoylistenerv3.FilterChainMatch{
-					ServerNames:       []string{name},
-					TransportProtocol: "tls",
+					ServerNames:        []string{name},
+					TransportProtocol:  "tls",
+					SourcePrefixRanges: sourceRanges,
 				},
 			})
 		}
@@ -274,11 +285,18 @@ func (sb *snapshotBuilder) makeSNIListener(fcs ...*envoylistenerv3.FilterChain)
 	return sniListener
 }
 
-func (sb *snapshotBuilder) makeTunnelingVirtualHosts(service *corev1.Service) (vhs []*envoyroutev3.VirtualHost, ports sets.String) {
+func (sb *snapshotBuilder) makeTunnelingVirtualHosts(service *corev1.Service, sourceRanges []*envoycorev3.CidrRange) (vhs []*envoyroutev3.VirtualHost, ports sets.String) {
 
 	serviceKey := ServiceKey(service)
 	ports = sets.NewString()
 
+	var perFilterConfig map[string]*anypb.Any
+	if sourceRanges != nil {
+		perFilterConfig = map[string]*anypb.Any{
+			envoywellknown.HTTPRoleBasedAccessControl: makeSourceRangesRBAC(sourceRanges),
+		}
+	}
+
 	for _, servicePort := range service.Spec.Ports {
 		servicePortKey := ServicePortKey(serviceKey, &servicePort)
 		if servicePort.Protocol != corev1.ProtocolTCP {
@@ -292,6 +310,7 @@ func (sb *snapshotBuilder) makeTunnelingVirtualHosts(service *corev1.Service) (v
 			Domains: []string{
 				fmt.Sprintf("%s.%s.svc.cluster.local:%d", service.Name, service.Namespace, servicePort.Port),
 			},
+			TypedPerFilterConfig: perFilterConfig,
 			Routes: []*envoyroutev3.Route{
 				{
 					Match: &envoyroutev3.RouteMatch{
@@ -319,7 +338,63 @@ func (sb *snapshotBuilder) makeTunnelingVirtualHosts(service *corev1.Service) (v
 	return
 }
 
+// makeSourceRangesRBAC returns the RBAC configuration of a virtual host that can
+// only be accessed from the given source ranges.
+func makeSourceRangesRBAC(sourceRanges []*envoycorev3.CidrRange) *anypb.Any {
+	var principals []*envoyrbacv3.Principal
+	for _, sourceRange := range sourceRanges {
+		principals = append(principals, &envoyrbacv3.Principal{
+			Identifier: &envoyrbacv3.Principal_DirectRemoteIp{DirectRemoteIp: sourceRange},
+		})
+	}
+
+	rbacConfig, err := anypb.New(&envoyrbacfilterv3.RBACPerRoute{
+		Rbac: &envoyrbacfilterv3.RBAC{
+			Rules: &envoyrbacv3.RBAC{
+				Action: envoyrbacv3.RBAC_ALLOW,
+				Policies: map[string]*envoyrbacv3.Policy{
+					"source-ranges": {
+						Permissions: []*envoyrbacv3.Permission{
+							{Rule: &envoyrbacv3.Permission_Any{Any: true}},
+						},
+						Principals: principals,
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		panic(errors.Wrap(err, "failed to marshal RBAC config"))
+	}
+	return rbacConfig
+}
+
 func (sb *snapshotBuilder) makeTunnelingListener(vhs ...*envoyroutev3.VirtualHost) *envoylistenerv3.Listener {
+	httpFilters := []*envoyhttpconnectionmanagerv3.HttpFilter{
+		{
+			Name: wellknown.Router,
+		},
+	}
+	// The RBAC filter allows all requests unless the virtual host restricts
+	// the source ranges.
+	for _, vh := range vhs {
+		if _, ok := vh.TypedPerFilterConfig[envoywellknown.HTTPRoleBasedAccessControl]; ok {
+			rbacConfig, err := anypb.New(&envoyrbacfilterv3.RBAC{})
+			if err != nil {
+				panic(errors.Wrap(err, "failed to marshal RBAC config"))
+			}
+			httpFilters = append([]*envoyhttpconnectionmanagerv3.HttpFilter{
+				{
+					Name: envoywellknown.HTTPRoleBasedAccessControl,
+					ConfigType: &envoyhttpconnectionmanagerv3.HttpFilter_TypedConfig{
+						TypedConfig: rbacConfig,
+					},
+				},
+			}, httpFilters...)
+			break
+		}
+	}
+
 	hcm := &envoyhttpconnectionmanagerv3.HttpConnectionManager{
 		CodecType:  envoyhttpconnectionmanagerv3.HttpConnectionManager_AUTO,
 		StatPrefix: "ingress_http",
@@ -329,12 +404,8 @@ func (sb *snapshotBuilder) makeTunnelingListener(vhs ...*envoyroutev3.VirtualHos
 				VirtualHosts: vhs,
 			},
 		},
-		AccessLog: makeAccessLog(),
-		HttpFilters: []*envoyhttpconnectionmanagerv3.HttpFilter{
-			{
-				Name: wellknown.Router,
-			},
-		},
+		AccessLog:   makeAccessLog(),
+		HttpFilters: httpFilters,
 		Http2ProtocolOptions: &envoycorev3.Http2ProtocolOptions{
 			AllowConnect: true,
 		},
@@ -418,7 +489,7 @@ func (sb *snapshotBuilder) makeClusters(service *corev1.Service, endpoints *core
 	return
 }
 
-func (sb *snapshotBuilder) makeListenersForNodePortService(service *corev1.Service) (listeners []envoycachetype.Resource, exposedPorts sets.String) {
+func (sb *snapshotBuilder) makeListenersForNodePortService(service *corev1.Service, sourceRanges []*envoycorev3.CidrRange) (listeners []envoycachetype.Resource, exposedPorts sets.String) {
 	serviceKey := ServiceKey(service)
 	exposedPorts = sets.NewString()
 	for _, servicePort := range service.Spec.Ports {
@@ -469,6 +540,11 @@ func (sb *snapshotBuilder) makeListenersForNodePortService(service *corev1.Servi
 				},
 			},
 		}
+		if sourceRanges != nil {
+			listener.FilterChains[0].FilterChainMatch = &envoylistenerv3.FilterChainMatch{
+				SourcePrefixRanges: sourceRanges,
+			}
+		}
 		listeners = append(listeners, listener)
 	}
 	return
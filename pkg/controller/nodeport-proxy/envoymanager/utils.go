@@ -19,10 +19,14 @@ package envoymanager
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	envoycorev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 
 	"k8c.io/kubermatic/v2/pkg/resources/nodeportproxy"
 
@@ -147,3 +151,25 @@ func (p portHostMapping) validate(svc *corev1.Service) error {
 	}
 	return nil
 }
+
+// sourceRangesFromAnnotation returns the CIDR ranges the given Service can be
+// accessed from, which are nil if the access is not restricted.
+func sourceRangesFromAnnotation(svc *corev1.Service) ([]*envoycorev3.CidrRange, error) {
+	val := svc.GetAnnotations()[nodeportproxy.SourceRangesAnnotationKey]
+	if val == "" {
+		return nil, nil
+	}
+	var ranges []*envoycorev3.CidrRange
+	for _, cidr := range strings.Split(val, ",") {
+		_, ipNet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse source range")
+		}
+		prefixLen, _ := ipNet.Mask.Size()
+		ranges = append(ranges, &envoycorev3.CidrRange{
+			AddressPrefix: ipNet.IP.String(),
+			PrefixLen:     wrapperspb.UInt32(uint32(prefixLen)),
+		})
+	}
+	return ranges, nil
+}
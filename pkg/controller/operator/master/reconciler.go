@@ -162,6 +162,10 @@ func (r *Reconciler) cleanupDeletedConfiguration(ctx context.Context, config *op
 		return fmt.Errorf("failed to clean up ValidatingWebhookConfiguration: %v", err)
 	}
 
+	if err := common.CleanupClusterResource(r, &admissionregistrationv1.ValidatingWebhookConfiguration{}, kubermatic.PresetAdmissionWebhookName(config)); err != nil {
+		return fmt.Errorf("failed to clean up Preset ValidatingWebhookConfiguration: %v", err)
+	}
+
 	oldConfig := config.DeepCopy()
 	kubernetes.RemoveFinalizer(config, common.CleanupFinalizer)
 
@@ -329,6 +333,7 @@ func (r *Reconciler) reconcileAdmissionWebhooks(ctx context.Context, config *ope
 
 	creators := []reconciling.NamedValidatingWebhookConfigurationCreatorGetter{
 		common.SeedAdmissionWebhookCreator(config, r.Client),
+		kubermatic.PresetAdmissionWebhookCreator(config, r.Client),
 	}
 
 	if err := reconciling.ReconcileValidatingWebhookConfigurations(ctx, creators, "", r.Client); err != nil {
@@ -46,6 +46,15 @@ func ServiceCreator(seed *kubermaticv1.Seed) reconciling.NamedServiceCreatorGett
 				common.NameLabel: EnvoyDeploymentName,
 			}
 
+			// Envoy can only restrict the access to allowed IP ranges if it sees the
+			// client IPs, which kube-proxy replaces with a node IP unless the traffic
+			// is only routed to local endpoints.
+			if seed.Spec.NodeportProxy.PreserveSourceIPs {
+				s.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyTypeLocal
+			} else {
+				s.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyTypeCluster
+			}
+
 			// Copy custom annotations if supplied by seed spec.
 			if seed.Spec.NodeportProxy.Annotations != nil {
 				s.Annotations = seed.Spec.NodeportProxy.Annotations
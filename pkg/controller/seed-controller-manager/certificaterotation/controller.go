@@ -0,0 +1,402 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificaterotation
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	controllerutil "k8c.io/kubermatic/v2/pkg/controller/util"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1/helper"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	metricsserver "k8c.io/kubermatic/v2/pkg/resources/metrics-server"
+	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
+	certutil "k8s.io/client-go/util/cert"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	ControllerName = "kubermatic_certificate_rotation_controller"
+
+	// defaultRenewBefore is the remaining validity at which certificates are renewed by default.
+	// The cluster controller also reissues certificates on its own once they reach it.
+	defaultRenewBefore = 30 * 24 * time.Hour
+	// progressRequeueAfter is the time after which the certificates of a cluster are checked again
+	// while they are renewed.
+	progressRequeueAfter = 30 * time.Second
+	// maxRequeueAfter is the longest time after which the certificates of a cluster are checked again.
+	maxRequeueAfter = 24 * time.Hour
+)
+
+var (
+	// certificateSecrets are the Secrets with certificates of the control plane, which the cluster
+	// controller reissues once they are marked for renewal.
+	certificateSecrets = []string{
+		resources.ApiserverTLSSecretName,
+		resources.KubeletClientCertificatesSecretName,
+		resources.ApiserverEtcdClientCertificateSecretName,
+		resources.ApiserverFrontProxyClientCertificateSecretName,
+		resources.EtcdTLSCertificateSecretName,
+		resources.KonnectivityProxyTLSSecretName,
+		resources.KonnectivityKubeconfigSecretName,
+		resources.OpenVPNServerCertificatesSecretName,
+		resources.OpenVPNClientCertificatesSecretName,
+		resources.MachineControllerWebhookServingCertSecretName,
+		metricsserver.ServingCertSecretName,
+		resources.SchedulerKubeconfigSecretName,
+		resources.MachineControllerKubeconfigSecretName,
+		resources.ControllerManagerKubeconfigSecretName,
+		resources.KubeStateMetricsKubeconfigSecretName,
+		resources.InternalUserClusterAdminKubeconfigSecretName,
+		resources.KubernetesDashboardKubeconfigSecretName,
+		resources.ClusterAutoscalerKubeconfigSecretName,
+		resources.MetricsServerKubeconfigSecretName,
+		resources.KubeletDnatControllerKubeconfigSecretName,
+		resources.CloudControllerManagerKubeconfigSecretName,
+	}
+
+	// caSecrets are the Secrets with the CAs of the cluster, which are not renewed automatically.
+	caSecrets = []string{
+		resources.CASecretName,
+		resources.FrontProxyCASecretName,
+		resources.OpenVPNCASecretName,
+	}
+)
+
+type Reconciler struct {
+	ctrlruntimeclient.Client
+
+	workerName string
+	recorder   record.EventRecorder
+	log        *zap.SugaredLogger
+	versions   kubermatic.Versions
+}
+
+// Add creates a new certificate rotation controller
+func Add(
+	mgr manager.Manager,
+	numWorkers int,
+	workerName string,
+	log *zap.SugaredLogger,
+	versions kubermatic.Versions,
+) error {
+	reconciler := &Reconciler{
+		Client: mgr.GetClient(),
+
+		workerName: workerName,
+		recorder:   mgr.GetEventRecorderFor(ControllerName),
+		log:        log.Named(ControllerName),
+		versions:   versions,
+	}
+
+	c, err := controller.New(ControllerName, mgr, controller.Options{
+		Reconciler:              reconciler,
+		MaxConcurrentReconciles: numWorkers,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create controller: %v", err)
+	}
+
+	if err := c.Watch(&source.Kind{Type: &kubermaticv1.Cluster{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return fmt.Errorf("failed to create cluster watch: %v", err)
+	}
+
+	if err := c.Watch(&source.Kind{Type: &corev1.Secret{}}, controllerutil.EnqueueClusterForNamespacedObject(mgr.GetClient())); err != nil {
+		return fmt.Errorf("failed to create secret watch: %v", err)
+	}
+
+	return nil
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	log := r.log.With("cluster", request.Name)
+
+	cluster := &kubermaticv1.Cluster{}
+	if err := r.Get(ctx, request.NamespacedName, cluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			deleteMetrics(request.Name)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if cluster.Labels[kubermaticv1.WorkerNameLabelKey] != r.workerName {
+		return reconcile.Result{}, nil
+	}
+
+	if cluster.DeletionTimestamp != nil {
+		deleteMetrics(cluster.Name)
+		return reconcile.Result{}, nil
+	}
+
+	if cluster.Spec.Pause || cluster.Status.NamespaceName == "" {
+		return reconcile.Result{}, nil
+	}
+
+	result, err := r.reconcile(ctx, log, cluster)
+	if err != nil {
+		log.Errorw("Reconciling failed", zap.Error(err))
+		r.recorder.Event(cluster, corev1.EventTypeWarning, "ReconcilingError", err.Error())
+	}
+
+	if result == nil {
+		result = &reconcile.Result{}
+	}
+	return *result, err
+}
+
+// certificateSecret is a Secret with certificates of the control plane.
+type certificateSecret struct {
+	secret *corev1.Secret
+	// certificates are the certificates in the Secret, for CAs only the one that signs certificates.
+	certificates []*x509.Certificate
+}
+
+// notAfter returns when the first of the certificates expires.
+func (c *certificateSecret) notAfter() time.Time {
+	var notAfter time.Time
+	for _, cert := range c.certificates {
+		if notAfter.IsZero() || cert.NotAfter.Before(notAfter) {
+			notAfter = cert.NotAfter
+		}
+	}
+	return notAfter
+}
+
+// reconcile records when the certificates in the cluster namespace expire and marks the Secrets
+// of certificates due for renewal with the kubermatic.io/renew-certificate annotation, which makes
+// the cluster controller reissue them.
+func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	if err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: resources.CASecretName}, &corev1.Secret{}); err != nil {
+		if kerrors.IsNotFound(err) {
+			log.Debug("Waiting for the root CA to be created")
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get root CA: %v", err)
+	}
+
+	leafs, err := r.getCertificateSecrets(ctx, cluster, certificateSecrets, false)
+	if err != nil {
+		return nil, err
+	}
+	cas, err := r.getCertificateSecrets(ctx, cluster, caSecrets, true)
+	if err != nil {
+		return nil, err
+	}
+
+	oldCluster := cluster.DeepCopy()
+	status := cluster.Status.Certificates.DeepCopy()
+	if status == nil {
+		status = &kubermaticv1.ClusterCertificatesStatus{}
+	}
+	renewBefore := getRenewBefore(cluster)
+
+	var renewing []string
+	for _, leaf := range leafs {
+		if time.Until(leaf.notAfter()) >= renewBefore {
+			continue
+		}
+		renewing = append(renewing, leaf.secret.Name)
+		if err := r.markForRenewal(ctx, log, cluster, leaf); err != nil {
+			return nil, err
+		}
+	}
+
+	var expiringCAs []string
+	for _, ca := range cas {
+		if time.Until(ca.notAfter()) < renewBefore {
+			expiringCAs = append(expiringCAs, ca.secret.Name)
+		}
+	}
+
+	all := append(leafs, cas...)
+	sort.Slice(all, func(i, j int) bool { return all[i].secret.Name < all[j].secret.Name })
+	status.Certificates = nil
+	var nextExpiry *certificateSecret
+	for i, s := range all {
+		status.Certificates = append(status.Certificates, kubermaticv1.CertificateExpiry{
+			SecretName: s.secret.Name,
+			NotAfter:   metav1.NewTime(s.notAfter()),
+		})
+		certificateExpiry.WithLabelValues(cluster.Name, s.secret.Name).Set(float64(s.notAfter().Unix()))
+		if nextExpiry == nil || s.notAfter().Before(nextExpiry.notAfter()) {
+			nextExpiry = &all[i]
+		}
+	}
+	cluster.Status.Certificates = status
+
+	switch {
+	case len(renewing) > 0:
+		r.setCondition(cluster, corev1.ConditionFalse, kubermaticv1.ReasonCertificatesRenewing,
+			fmt.Sprintf("Renewing the certificates in %s", strings.Join(renewing, ", ")))
+	case len(expiringCAs) > 0:
+		r.setCondition(cluster, corev1.ConditionFalse, kubermaticv1.ReasonCertificatesExpiring,
+			fmt.Sprintf("The CAs in %s expire soon and cannot be rotated automatically", strings.Join(expiringCAs, ", ")))
+	case nextExpiry != nil:
+		r.setCondition(cluster, corev1.ConditionTrue, kubermaticv1.ReasonCertificatesValid,
+			fmt.Sprintf("The next certificate expires at %s (%s)", nextExpiry.notAfter().UTC().Format(time.RFC3339), nextExpiry.secret.Name))
+	}
+
+	if !apiequality.Semantic.DeepEqual(oldCluster.Status, cluster.Status) {
+		if err := r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+			return nil, fmt.Errorf("failed to update certificates status: %v", err)
+		}
+	}
+
+	if len(renewing) > 0 {
+		return &reconcile.Result{RequeueAfter: progressRequeueAfter}, nil
+	}
+
+	// check the certificates again once the next one is due for renewal
+	requeueAfter := maxRequeueAfter
+	for _, s := range all {
+		if due := time.Until(s.notAfter()) - renewBefore; due < requeueAfter {
+			requeueAfter = due
+		}
+	}
+	if requeueAfter < progressRequeueAfter {
+		requeueAfter = progressRequeueAfter
+	}
+	return &reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+func (r *Reconciler) setCondition(cluster *kubermaticv1.Cluster, status corev1.ConditionStatus, reason, message string) {
+	kubermaticv1helper.SetClusterCondition(cluster, r.versions, kubermaticv1.ClusterConditionCertificatesValid, status, reason, message)
+}
+
+// markForRenewal marks the Secret for the cluster controller to reissue its certificates.
+func (r *Reconciler) markForRenewal(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster, s certificateSecret) error {
+	if _, marked := s.secret.Annotations[resources.CertificateRenewalAnnotation]; marked {
+		return nil
+	}
+
+	oldSecret := s.secret.DeepCopy()
+	if s.secret.Annotations == nil {
+		s.secret.Annotations = map[string]string{}
+	}
+	s.secret.Annotations[resources.CertificateRenewalAnnotation] = "true"
+	if err := r.Patch(ctx, s.secret, ctrlruntimeclient.MergeFrom(oldSecret)); err != nil {
+		return fmt.Errorf("failed to mark Secret %s for renewal: %v", s.secret.Name, err)
+	}
+
+	log.Infow("Renewing certificates", "secret", s.secret.Name, "notAfter", s.notAfter())
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, "CertificateRenewal", "Renewing the certificates in %s, which expire at %s",
+		s.secret.Name, s.notAfter().UTC().Format(time.RFC3339))
+
+	return nil
+}
+
+// getCertificateSecrets returns the certificates of the given Secrets in the cluster namespace
+// that exist and contain certificates.
+func (r *Reconciler) getCertificateSecrets(ctx context.Context, cluster *kubermaticv1.Cluster, names []string, isCA bool) ([]certificateSecret, error) {
+	var secrets []certificateSecret
+	for _, name := range names {
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: name}, secret); err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get Secret %s: %v", name, err)
+		}
+
+		var certs []*x509.Certificate
+		if isCA {
+			caCerts, err := certutil.ParseCertsPEM(secret.Data[resources.CACertSecretKey])
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse CA certificate in Secret %s: %v", name, err)
+			}
+			// the certificate that signs certificates is always the first one
+			certs = caCerts[:1]
+		} else {
+			certs = leafCertificates(secret)
+		}
+
+		if len(certs) > 0 {
+			secrets = append(secrets, certificateSecret{secret: secret, certificates: certs})
+		}
+	}
+	return secrets, nil
+}
+
+// leafCertificates returns the certificates stored in the Secret, either PEM-encoded or as client
+// certificates of kubeconfigs. The copies of the CA most Secrets include are skipped.
+func leafCertificates(secret *corev1.Secret) []*x509.Certificate {
+	var certs []*x509.Certificate
+	for key, value := range secret.Data {
+		var parsed []*x509.Certificate
+		switch key {
+		case resources.CACertSecretKey:
+			continue
+		case resources.KubeconfigSecretKey, resources.KonnectivityServerConf:
+			config, err := clientcmd.Load(value)
+			if err != nil {
+				continue
+			}
+			for _, authInfo := range config.AuthInfos {
+				if clientCerts, err := certutil.ParseCertsPEM(authInfo.ClientCertificateData); err == nil {
+					parsed = append(parsed, clientCerts...)
+				}
+			}
+		default:
+			if !bytes.Contains(value, []byte("CERTIFICATE")) {
+				continue
+			}
+			parsed, _ = certutil.ParseCertsPEM(value)
+		}
+
+		for _, cert := range parsed {
+			if !cert.IsCA {
+				certs = append(certs, cert)
+			}
+		}
+	}
+	return certs
+}
+
+func getRenewBefore(cluster *kubermaticv1.Cluster) time.Duration {
+	if settings := cluster.Spec.CertificateRotation; settings != nil && settings.RenewBefore != nil {
+		return settings.RenewBefore.Duration
+	}
+	return defaultRenewBefore
+}
+
+func deleteMetrics(clusterName string) {
+	for _, name := range append(certificateSecrets, caSecrets...) {
+		certificateExpiry.DeleteLabelValues(clusterName, name)
+	}
+}
@@ -0,0 +1,270 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificaterotation
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1/helper"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/certificates/triple"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	certutil "k8s.io/client-go/util/cert"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	clusterName      = "testcluster"
+	clusterNamespace = "cluster-" + clusterName
+	workerName       = ""
+)
+
+func genCluster(settings *kubermaticv1.CertificateRotationSettings) *kubermaticv1.Cluster {
+	return &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: clusterName,
+		},
+		Spec: kubermaticv1.ClusterSpec{
+			CertificateRotation: settings,
+		},
+		Status: kubermaticv1.ClusterStatus{
+			NamespaceName: clusterNamespace,
+		},
+	}
+}
+
+func genCASecret(t *testing.T) (*corev1.Secret, *triple.KeyPair) {
+	ca, err := triple.NewCA("root-ca")
+	if err != nil {
+		t.Fatalf("failed to create CA: %v", err)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resources.CASecretName,
+			Namespace: clusterNamespace,
+		},
+		Data: map[string][]byte{
+			resources.CACertSecretKey: triple.EncodeCertPEM(ca.Cert),
+			resources.CAKeySecretKey:  triple.EncodePrivateKeyPEM(ca.Key),
+		},
+	}, ca
+}
+
+// genCertificateSecret returns a Secret with a certificate signed by the CA that expires after the
+// given duration.
+func genCertificateSecret(t *testing.T, name string, ca *triple.KeyPair, validFor time.Duration) *corev1.Secret {
+	key, err := triple.NewPrivateKey()
+	if err != nil {
+		t.Fatalf("failed to create key: %v", err)
+	}
+	template := &x509.Certificate{
+		Subject:      pkix.Name{CommonName: name},
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(validFor),
+		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.Cert, key.Public(), ca.Key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: clusterNamespace,
+		},
+		Data: map[string][]byte{
+			"tls.crt":                 triple.EncodeCertPEM(cert),
+			"tls.key":                 triple.EncodePrivateKeyPEM(key),
+			resources.CACertSecretKey: triple.EncodeCertPEM(ca.Cert),
+		},
+	}
+}
+
+func newReconciler(client ctrlruntimeclient.Client) *Reconciler {
+	return &Reconciler{
+		Client:     client,
+		workerName: workerName,
+		recorder:   &record.FakeRecorder{},
+		log:        zap.NewNop().Sugar(),
+	}
+}
+
+func getCluster(t *testing.T, client ctrlruntimeclient.Client) *kubermaticv1.Cluster {
+	cluster := &kubermaticv1.Cluster{}
+	if err := client.Get(context.Background(), types.NamespacedName{Name: clusterName}, cluster); err != nil {
+		t.Fatalf("failed to get cluster: %v", err)
+	}
+	return cluster
+}
+
+func getSecret(t *testing.T, client ctrlruntimeclient.Client, name string) *corev1.Secret {
+	secret := &corev1.Secret{}
+	if err := client.Get(context.Background(), types.NamespacedName{Namespace: clusterNamespace, Name: name}, secret); err != nil {
+		t.Fatalf("failed to get Secret %s: %v", name, err)
+	}
+	return secret
+}
+
+func TestReconcileRenewal(t *testing.T) {
+	ctx := context.Background()
+
+	caSecret, ca := genCASecret(t)
+	client := fakectrlruntimeclient.
+		NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(
+			genCluster(nil),
+			caSecret,
+			genCertificateSecret(t, resources.ApiserverTLSSecretName, ca, 10*24*time.Hour),
+			genCertificateSecret(t, resources.EtcdTLSCertificateSecretName, ca, 365*24*time.Hour),
+		).
+		Build()
+	r := newReconciler(client)
+
+	result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: clusterName}})
+	if err != nil {
+		t.Fatalf("reconciling failed: %v", err)
+	}
+	if result.RequeueAfter != progressRequeueAfter {
+		t.Errorf("expected requeue after %v while renewing, got %v", progressRequeueAfter, result.RequeueAfter)
+	}
+
+	if _, marked := getSecret(t, client, resources.ApiserverTLSSecretName).Annotations[resources.CertificateRenewalAnnotation]; !marked {
+		t.Error("expected expiring certificate to be marked for renewal")
+	}
+	if _, marked := getSecret(t, client, resources.EtcdTLSCertificateSecretName).Annotations[resources.CertificateRenewalAnnotation]; marked {
+		t.Error("expected valid certificate not to be marked for renewal")
+	}
+
+	cluster := getCluster(t, client)
+	_, condition := kubermaticv1helper.GetClusterCondition(cluster, kubermaticv1.ClusterConditionCertificatesValid)
+	if condition == nil || condition.Status != corev1.ConditionFalse || condition.Reason != kubermaticv1.ReasonCertificatesRenewing {
+		t.Errorf("expected condition to report the renewal, got %+v", condition)
+	}
+	var secretNames []string
+	for _, expiry := range cluster.Status.Certificates.Certificates {
+		secretNames = append(secretNames, expiry.SecretName)
+	}
+	expectedNames := []string{resources.ApiserverTLSSecretName, resources.CASecretName, resources.EtcdTLSCertificateSecretName}
+	if fmt.Sprint(secretNames) != fmt.Sprint(expectedNames) {
+		t.Errorf("expected expiry of %v to be reported, got %v", expectedNames, secretNames)
+	}
+
+	// reissue the certificate like the cluster controller does
+	renewed := genCertificateSecret(t, resources.ApiserverTLSSecretName, ca, 365*24*time.Hour)
+	secret := getSecret(t, client, resources.ApiserverTLSSecretName)
+	secret.Annotations = nil
+	secret.Data = renewed.Data
+	if err := client.Update(ctx, secret); err != nil {
+		t.Fatalf("failed to update Secret: %v", err)
+	}
+
+	result, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: clusterName}})
+	if err != nil {
+		t.Fatalf("reconciling failed: %v", err)
+	}
+	if result.RequeueAfter != maxRequeueAfter {
+		t.Errorf("expected requeue after %v, got %v", maxRequeueAfter, result.RequeueAfter)
+	}
+
+	_, condition = kubermaticv1helper.GetClusterCondition(getCluster(t, client), kubermaticv1.ClusterConditionCertificatesValid)
+	if condition == nil || condition.Status != corev1.ConditionTrue {
+		t.Errorf("expected certificates to be valid, got %+v", condition)
+	}
+}
+
+func TestReconcileExpiringCA(t *testing.T) {
+	ctx := context.Background()
+
+	caSecret, _ := genCASecret(t)
+	// re-sign the root CA with a validity that ends before it is due for renewal
+	key, err := triple.ParsePrivateKeyPEM(caSecret.Data[resources.CAKeySecretKey])
+	if err != nil {
+		t.Fatalf("failed to parse CA key: %v", err)
+	}
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "root-ca"},
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(10 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	signer := key.(*rsa.PrivateKey)
+	der, err := x509.CreateCertificate(rand.Reader, template, template, signer.Public(), signer)
+	if err != nil {
+		t.Fatalf("failed to create CA certificate: %v", err)
+	}
+	caCert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse CA certificate: %v", err)
+	}
+	caSecret.Data[resources.CACertSecretKey] = triple.EncodeCertPEM(caCert)
+
+	client := fakectrlruntimeclient.
+		NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(
+			genCluster(nil),
+			caSecret,
+		).
+		Build()
+	r := newReconciler(client)
+
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: clusterName}}); err != nil {
+		t.Fatalf("reconciling failed: %v", err)
+	}
+
+	// the root CA is not rotated, as the nodes of the cluster would stop trusting the control plane
+	caCerts, err := certutil.ParseCertsPEM(getSecret(t, client, resources.CASecretName).Data[resources.CACertSecretKey])
+	if err != nil {
+		t.Fatalf("failed to parse CA: %v", err)
+	}
+	if len(caCerts) != 1 || !caCerts[0].Equal(caCert) {
+		t.Errorf("expected root CA to be left as is, got %d certificates", len(caCerts))
+	}
+
+	_, condition := kubermaticv1helper.GetClusterCondition(getCluster(t, client), kubermaticv1.ClusterConditionCertificatesValid)
+	if condition == nil || condition.Status != corev1.ConditionFalse || condition.Reason != kubermaticv1.ReasonCertificatesExpiring {
+		t.Errorf("expected condition to report the expiring root CA, got %+v", condition)
+	}
+}
@@ -0,0 +1,32 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificaterotation
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	certificateExpiry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "kubermatic",
+		Subsystem: "cluster",
+		Name:      "certificate_expiry_timestamp_seconds",
+		Help:      "The time the certificate in a Secret of the control plane of a usercluster expires, in seconds since the epoch",
+	}, []string{"cluster", "secret"})
+)
+
+func MustRegisterMetrics(c prometheus.Registerer) {
+	c.MustRegister(certificateExpiry)
+}
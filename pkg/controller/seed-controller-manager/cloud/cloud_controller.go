@@ -34,7 +34,11 @@ import (
 	"k8c.io/kubermatic/v2/pkg/provider"
 	"k8c.io/kubermatic/v2/pkg/provider/cloud"
 	"k8c.io/kubermatic/v2/pkg/provider/cloud/azure"
+	"k8c.io/kubermatic/v2/pkg/provider/cloud/gcp"
+	"k8c.io/kubermatic/v2/pkg/provider/cloud/kubevirt"
 	"k8c.io/kubermatic/v2/pkg/provider/cloud/openstack"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/reconciling"
 	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
 
 	corev1 "k8s.io/api/core/v1"
@@ -59,9 +63,15 @@ const (
 	// awsHarcodedAZMigrationRevision is the migration revision for moving AWS clusters away from
 	// hardcoded AZs and Subnets towards multi-AZ support.
 	awsHarcodedAZMigrationRevision = 2
+	// gcpFirewallOwnershipMigrationRevision is the migration revision that will be set on the cluster
+	// after the firewall rules of GCP clusters were marked as owned by the cluster, so they get reconciled.
+	gcpFirewallOwnershipMigrationRevision = 3
+	// kubevirtSharedNamespaceMigrationRevision is the migration revision that will be set on the cluster
+	// after KubeVirt clusters created before namespace isolation were marked to keep their shared namespace.
+	kubevirtSharedNamespaceMigrationRevision = 4
 	// currentMigrationRevision describes the current migration revision. If this is set on the
 	// cluster, certain migrations won't get executed. This must never be decremented.
-	CurrentMigrationRevision = awsHarcodedAZMigrationRevision
+	CurrentMigrationRevision = kubevirtSharedNamespaceMigrationRevision
 )
 
 // Check if the Reconciler fulfills the interface
@@ -179,6 +189,18 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, clus
 		}
 	}
 
+	if cluster.Status.CloudMigrationRevision < gcpFirewallOwnershipMigrationRevision {
+		if err := r.migrateGCPFirewallOwnership(ctx, log, cluster, prov); err != nil {
+			return nil, err
+		}
+	}
+
+	if cluster.Status.CloudMigrationRevision < kubevirtSharedNamespaceMigrationRevision {
+		if err := r.migrateKubevirtSharedNamespace(ctx, log, cluster, prov); err != nil {
+			return nil, err
+		}
+	}
+
 	handleProviderError := func(err error) (*reconcile.Result, error) {
 		if kerrors.IsConflict(err) {
 			// In case of conflict we just re-enqueue the item for later
@@ -201,6 +223,7 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, clus
 	// To prevent reconciling right after initialization (would cause a bunch of unneeded API
 	// calls), we distinguish early between the providers and use _only_ reconciling when
 	// provider implements it.
+	reconciled := false
 	if betterProvider, ok := prov.(provider.ReconcilingCloudProvider); ok {
 		last := cluster.Status.LastProviderReconciliation
 
@@ -235,6 +258,7 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, clus
 
 			// update metrics
 			successfulProviderReconciliations.WithLabelValues(cluster.Name, providerName).Inc()
+			reconciled = true
 		}
 	} else {
 		// the provider only offers a one-time init :-(
@@ -244,6 +268,10 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, clus
 		}
 	}
 
+	if err := r.reconcileKubevirtKubeconfig(ctx, cluster, prov, reconciled); err != nil {
+		return nil, err
+	}
+
 	if _, err := r.updateCluster(cluster.Name, func(c *kubermaticv1.Cluster) {
 		c.Status.ExtendedHealth.CloudProviderInfrastructure = kubermaticv1.HealthStatusUp
 	}); err != nil {
@@ -278,6 +306,100 @@ func (r *Reconciler) migrateICMP(ctx context.Context, log *zap.SugaredLogger, cl
 	return nil
 }
 
+func (r *Reconciler) migrateGCPFirewallOwnership(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster, cloudProvider provider.CloudProvider) error {
+	if prov, ok := cloudProvider.(*gcp.GCP); ok {
+		if err := prov.AdoptFirewallRules(cluster); err != nil {
+			return fmt.Errorf("failed to adopt firewall rules of cluster %q: %w", cluster.Name, err)
+		}
+		log.Info("Successfully adopted firewall rules of cluster")
+	}
+
+	if _, err := r.updateCluster(cluster.Name, func(c *kubermaticv1.Cluster) {
+		c.Status.CloudMigrationRevision = gcpFirewallOwnershipMigrationRevision
+	}); err != nil {
+		return fmt.Errorf("failed to update cluster %q after successfully executing its cloudProvider migration: %w",
+			cluster.Name, err)
+	}
+
+	return nil
+}
+
+func (r *Reconciler) migrateKubevirtSharedNamespace(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster, cloudProvider provider.CloudProvider) error {
+	if _, ok := cloudProvider.(*kubevirt.Provider); ok && isKubevirtSharedNamespaceCluster(cluster) {
+		var err error
+		if cluster, err = r.updateCluster(cluster.Name, func(c *kubermaticv1.Cluster) {
+			if c.Annotations == nil {
+				c.Annotations = map[string]string{}
+			}
+			c.Annotations[kubevirt.SharedNamespaceAnnotation] = "true"
+		}); err != nil {
+			return fmt.Errorf("failed to mark cluster %q as using a shared namespace: %w", cluster.Name, err)
+		}
+		log.Info("Marked cluster as using a shared namespace in the KubeVirt infra cluster")
+	}
+
+	if _, err := r.updateCluster(cluster.Name, func(c *kubermaticv1.Cluster) {
+		c.Status.CloudMigrationRevision = kubevirtSharedNamespaceMigrationRevision
+	}); err != nil {
+		return fmt.Errorf("failed to update cluster %q after successfully executing its cloudProvider migration: %w",
+			cluster.Name, err)
+	}
+
+	return nil
+}
+
+// isKubevirtSharedNamespaceCluster returns whether the KubeVirt cluster has been provisioned before
+// every cluster got its own namespace in the infra cluster. Clusters that are created as Cluster
+// resources instead of through the API start at migration revision 0 as well, but have not been
+// provisioned yet.
+func isKubevirtSharedNamespaceCluster(cluster *kubermaticv1.Cluster) bool {
+	return cluster.Spec.Cloud.Kubevirt.Namespace == "" &&
+		!kuberneteshelper.HasFinalizer(cluster, kubevirt.FinalizerNamespace) &&
+		cluster.Status.ExtendedHealth.CloudProviderInfrastructure == kubermaticv1.HealthStatusUp
+}
+
+// reconcileKubevirtKubeconfig stores the kubeconfig for the namespace of the cluster in the KubeVirt
+// infra cluster in the cluster namespace, so it can be handed to the components of the cluster
+// instead of the kubeconfig of the infra cluster. To save calls to the infra cluster, an existing
+// secret is only updated when the cloud provider was reconciled.
+func (r *Reconciler) reconcileKubevirtKubeconfig(ctx context.Context, cluster *kubermaticv1.Cluster, cloudProvider provider.CloudProvider, force bool) error {
+	prov, ok := cloudProvider.(*kubevirt.Provider)
+	if !ok || cluster.Spec.Cloud.Kubevirt.Namespace == "" {
+		return nil
+	}
+
+	if cluster.Status.NamespaceName == "" {
+		return fmt.Errorf("namespace of cluster %q has not been created yet", cluster.Name)
+	}
+
+	if !force {
+		secret := &corev1.Secret{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: resources.KubevirtInfraKubeconfigSecretName}, secret)
+		if err == nil {
+			return nil
+		}
+		if !kerrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get KubeVirt kubeconfig: %w", err)
+		}
+	}
+
+	kubeconfig, err := prov.ScopedKubeconfig(cluster)
+	if err != nil {
+		return fmt.Errorf("failed to create KubeVirt kubeconfig: %w", err)
+	}
+
+	creator := func() (string, reconciling.SecretCreator) {
+		return resources.KubevirtInfraKubeconfigSecretName, func(s *corev1.Secret) (*corev1.Secret, error) {
+			s.Data = map[string][]byte{
+				resources.KubevirtKubeConfig: []byte(kubeconfig),
+			}
+			return s, nil
+		}
+	}
+
+	return reconciling.ReconcileSecrets(ctx, []reconciling.NamedSecretCreatorGetter{creator}, cluster.Status.NamespaceName, r.Client)
+}
+
 func (r *Reconciler) updateCluster(name string, modify func(*kubermaticv1.Cluster), options ...provider.UpdaterOption) (*kubermaticv1.Cluster, error) {
 	cluster := &kubermaticv1.Cluster{}
 	if err := r.Get(context.Background(), types.NamespacedName{Name: name}, cluster); err != nil {
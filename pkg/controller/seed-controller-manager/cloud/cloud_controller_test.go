@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/provider/cloud/kubevirt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMigrateKubevirtSharedNamespace(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cluster      *kubermaticv1.Cluster
+		expectShared bool
+	}{
+		{
+			name: "cluster provisioned before namespace isolation keeps the shared namespace",
+			cluster: &kubermaticv1.Cluster{
+				Status: kubermaticv1.ClusterStatus{
+					ExtendedHealth: kubermaticv1.ExtendedClusterHealth{
+						CloudProviderInfrastructure: kubermaticv1.HealthStatusUp,
+					},
+				},
+			},
+			expectShared: true,
+		},
+		{
+			name: "new cluster created as Cluster resource gets its own namespace",
+			cluster: &kubermaticv1.Cluster{
+				Status: kubermaticv1.ClusterStatus{
+					ExtendedHealth: kubermaticv1.ExtendedClusterHealth{
+						CloudProviderInfrastructure: kubermaticv1.HealthStatusProvisioning,
+					},
+				},
+			},
+			expectShared: false,
+		},
+		{
+			name: "cluster that is being provisioned in its own namespace",
+			cluster: &kubermaticv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Finalizers: []string{kubevirt.FinalizerNamespace},
+				},
+				Status: kubermaticv1.ClusterStatus{
+					ExtendedHealth: kubermaticv1.ExtendedClusterHealth{
+						CloudProviderInfrastructure: kubermaticv1.HealthStatusUp,
+					},
+				},
+			},
+			expectShared: false,
+		},
+		{
+			name: "cluster with its own namespace",
+			cluster: &kubermaticv1.Cluster{
+				Spec: kubermaticv1.ClusterSpec{
+					Cloud: kubermaticv1.CloudSpec{
+						Kubevirt: &kubermaticv1.KubevirtCloudSpec{Namespace: "cluster-test"},
+					},
+				},
+				Status: kubermaticv1.ClusterStatus{
+					ExtendedHealth: kubermaticv1.ExtendedClusterHealth{
+						CloudProviderInfrastructure: kubermaticv1.HealthStatusUp,
+					},
+				},
+			},
+			expectShared: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			cluster := test.cluster
+			cluster.Name = "test"
+			if cluster.Spec.Cloud.Kubevirt == nil {
+				cluster.Spec.Cloud.Kubevirt = &kubermaticv1.KubevirtCloudSpec{}
+			}
+
+			prov, err := kubevirt.NewCloudProvider(&kubermaticv1.Datacenter{
+				Spec: kubermaticv1.DatacenterSpec{Kubevirt: &kubermaticv1.DatacenterSpecKubevirt{}},
+			}, nil)
+			if err != nil {
+				t.Fatalf("failed to create provider: %v", err)
+			}
+
+			r := &Reconciler{
+				Client: fakectrlruntimeclient.
+					NewClientBuilder().
+					WithScheme(scheme.Scheme).
+					WithObjects(cluster).
+					Build(),
+				log: zap.NewNop().Sugar(),
+			}
+
+			if err := r.migrateKubevirtSharedNamespace(ctx, r.log, cluster, prov); err != nil {
+				t.Fatalf("migration failed: %v", err)
+			}
+
+			migrated := &kubermaticv1.Cluster{}
+			if err := r.Get(ctx, types.NamespacedName{Name: cluster.Name}, migrated); err != nil {
+				t.Fatalf("failed to get cluster: %v", err)
+			}
+
+			if shared := migrated.Annotations[kubevirt.SharedNamespaceAnnotation] == "true"; shared != test.expectShared {
+				t.Errorf("expected cluster to use a shared namespace: %v, got %v", test.expectShared, shared)
+			}
+			if migrated.Status.CloudMigrationRevision != kubevirtSharedNamespaceMigrationRevision {
+				t.Errorf("expected migration revision %d, got %d", kubevirtSharedNamespaceMigrationRevision, migrated.Status.CloudMigrationRevision)
+			}
+		})
+	}
+}
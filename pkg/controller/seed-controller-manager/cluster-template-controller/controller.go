@@ -182,10 +182,13 @@ func (r *reconciler) createClusters(ctx context.Context, instance *kubermaticv1.
 			newCluster := genNewCluster(template, instance, r.workerName)
 
 			// Here partialCluster is used to copy credentials to the new cluster
-			err := resources.CopyCredentials(resources.NewCredentialsData(context.Background(), partialCluster, r.seedClient), newCluster)
-			if err != nil {
+			credentialsData := resources.NewCredentialsData(context.Background(), partialCluster, r.seedClient)
+			if err := resources.CopyCredentials(credentialsData, newCluster); err != nil {
 				return fmt.Errorf("failed to get credentials: %v", err)
 			}
+			if err := resources.CopyAuditSinkCredentials(credentialsData, newCluster); err != nil {
+				return fmt.Errorf("failed to get audit sink credentials: %v", err)
+			}
 			if err := kubernetesprovider.CreateOrUpdateCredentialSecretForCluster(ctx, r.seedClient, newCluster); err != nil {
 				return err
 			}
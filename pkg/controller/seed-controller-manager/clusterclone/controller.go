@@ -0,0 +1,255 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterclone
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	clusterclient "k8c.io/kubermatic/v2/pkg/cluster/client"
+	predicateutil "k8c.io/kubermatic/v2/pkg/controller/util/predicate"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	ControllerName = "kubermatic_cluster_clone_controller"
+
+	// RestoreName is the name of the EtcdRestore that restores the backup
+	// of the source cluster into the cloned cluster.
+	RestoreName = "clone"
+)
+
+// UserClusterClientProvider provides functionality to get a user cluster client
+type UserClusterClientProvider interface {
+	GetClient(ctx context.Context, c *kubermaticv1.Cluster, options ...clusterclient.ConfigOption) (ctrlruntimeclient.Client, error)
+}
+
+type Reconciler struct {
+	ctrlruntimeclient.Client
+
+	workerName                    string
+	recorder                      record.EventRecorder
+	userClusterConnectionProvider UserClusterClientProvider
+	log                           *zap.SugaredLogger
+}
+
+// Add creates a new cluster clone controller
+func Add(mgr manager.Manager, numWorkers int, workerName string, userClusterConnectionProvider UserClusterClientProvider, log *zap.SugaredLogger) error {
+	reconciler := &Reconciler{
+		Client: mgr.GetClient(),
+
+		workerName:                    workerName,
+		recorder:                      mgr.GetEventRecorderFor(ControllerName),
+		userClusterConnectionProvider: userClusterConnectionProvider,
+		log:                           log.Named(ControllerName),
+	}
+
+	c, err := controller.New(ControllerName, mgr, controller.Options{
+		Reconciler:              reconciler,
+		MaxConcurrentReconciles: numWorkers,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create controller: %v", err)
+	}
+
+	clonePending := predicateutil.Factory(func(o ctrlruntimeclient.Object) bool {
+		return o.(*kubermaticv1.Cluster).IsClonePending()
+	})
+	if err := c.Watch(&source.Kind{Type: &kubermaticv1.Cluster{}}, &handler.EnqueueRequestForObject{}, clonePending); err != nil {
+		return fmt.Errorf("failed to create cluster watch: %v", err)
+	}
+
+	enqueueCluster := handler.EnqueueRequestsFromMapFunc(func(o ctrlruntimeclient.Object) []reconcile.Request {
+		restore := o.(*kubermaticv1.EtcdRestore)
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: restore.Spec.Cluster.Name}}}
+	})
+	if err := c.Watch(&source.Kind{Type: &kubermaticv1.EtcdRestore{}}, enqueueCluster, predicateutil.ByName(RestoreName)); err != nil {
+		return fmt.Errorf("failed to create restore watch: %v", err)
+	}
+
+	return nil
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	log := r.log.With("cluster", request.Name)
+
+	cluster := &kubermaticv1.Cluster{}
+	if err := r.Get(ctx, request.NamespacedName, cluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if cluster.Labels[kubermaticv1.WorkerNameLabelKey] != r.workerName {
+		return reconcile.Result{}, nil
+	}
+
+	if !cluster.IsClonePending() || cluster.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	result, err := r.reconcile(ctx, log, cluster)
+	if err != nil {
+		log.Errorw("Reconciling failed", zap.Error(err))
+		r.recorder.Event(cluster, corev1.EventTypeWarning, "ReconcilingError", err.Error())
+	}
+
+	if result == nil {
+		result = &reconcile.Result{}
+	}
+	return *result, err
+}
+
+func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	restoreSpec, err := parseCloneRequest(cluster)
+	if err != nil {
+		// the request can never succeed, so it is dropped to not keep the cluster scaled down forever
+		r.recorder.Eventf(cluster, corev1.EventTypeWarning, "CloneFailed", "Invalid clone request: %v", err)
+		return nil, r.removeCloneRequest(ctx, cluster)
+	}
+
+	// the cluster namespace is created by the cluster controller
+	if cluster.Status.NamespaceName == "" {
+		log.Debug("Waiting for cluster namespace")
+		return &reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: cluster.Status.NamespaceName}, namespace); err != nil {
+		if kerrors.IsNotFound(err) {
+			log.Debug("Waiting for cluster namespace")
+			return &reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+		return nil, fmt.Errorf("failed to get cluster namespace: %v", err)
+	}
+
+	restore, err := r.ensureRestore(ctx, cluster, restoreSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	if restore.Status.Phase != kubermaticv1.EtcdRestorePhaseCompleted {
+		log.Debug("Waiting for backup to be restored")
+		return nil, nil
+	}
+
+	if cluster.Status.ExtendedHealth.Apiserver != kubermaticv1.HealthStatusUp {
+		log.Debug("Waiting for apiserver")
+		return &reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	userClusterClient, err := r.userClusterConnectionProvider.GetClient(ctx, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user cluster client: %v", err)
+	}
+
+	if err := detachFromSourceCluster(ctx, userClusterClient); err != nil {
+		return nil, fmt.Errorf("failed to detach cluster from the resources of cluster %s: %v", restoreSpec.SourceClusterName, err)
+	}
+
+	log.Infow("Cluster has been cloned", "source", restoreSpec.SourceClusterName, "backup", restoreSpec.BackupName)
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, "ClusterCloned",
+		"Restored backup %s of cluster %s, all machine deployments have been scaled to zero and all persistent volumes removed", restoreSpec.BackupName, restoreSpec.SourceClusterName)
+
+	return nil, r.removeCloneRequest(ctx, cluster)
+}
+
+func parseCloneRequest(cluster *kubermaticv1.Cluster) (*kubermaticv1.EtcdRestoreSpec, error) {
+	spec := &kubermaticv1.EtcdRestoreSpec{}
+	if err := json.Unmarshal([]byte(cluster.Annotations[kubermaticv1.CloneRequestAnnotation]), spec); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal clone request: %v", err)
+	}
+
+	if spec.SourceClusterName == "" {
+		return nil, fmt.Errorf("no source cluster given")
+	}
+	if spec.BackupName == "" {
+		return nil, fmt.Errorf("no backup name given")
+	}
+
+	return spec, nil
+}
+
+// ensureRestore creates the EtcdRestore that restores the backup of the source cluster, once
+// the clone has the encryption keys of the source cluster.
+func (r *Reconciler) ensureRestore(ctx context.Context, cluster *kubermaticv1.Cluster, spec *kubermaticv1.EtcdRestoreSpec) (*kubermaticv1.EtcdRestore, error) {
+	restore := &kubermaticv1.EtcdRestore{}
+	key := types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: RestoreName}
+
+	err := r.Get(ctx, key, restore)
+	if err == nil || !kerrors.IsNotFound(err) {
+		return restore, err
+	}
+
+	source := &kubermaticv1.Cluster{}
+	if err := r.Get(ctx, types.NamespacedName{Name: spec.SourceClusterName}, source); err != nil {
+		return nil, fmt.Errorf("failed to get source cluster: %v", err)
+	}
+	if err := r.copyEncryption(ctx, cluster, source); err != nil {
+		return nil, err
+	}
+
+	restore = &kubermaticv1.EtcdRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      key.Name,
+			Namespace: key.Namespace,
+			Labels: map[string]string{
+				kubermaticv1.ProjectIDLabelKey: cluster.Labels[kubermaticv1.ProjectIDLabelKey],
+			},
+			OwnerReferences: []metav1.OwnerReference{resources.GetClusterRef(cluster)},
+		},
+		Spec: *spec,
+	}
+
+	restore.Spec.Name = RestoreName
+	restore.Spec.Cluster = corev1.ObjectReference{
+		APIVersion: kubermaticv1.SchemeGroupVersion.String(),
+		Kind:       kubermaticv1.ClusterKindName,
+		Name:       cluster.Name,
+		UID:        cluster.UID,
+	}
+
+	if err := r.Create(ctx, restore); err != nil {
+		return nil, fmt.Errorf("failed to create EtcdRestore: %v", err)
+	}
+
+	return restore, nil
+}
+
+func (r *Reconciler) removeCloneRequest(ctx context.Context, cluster *kubermaticv1.Cluster) error {
+	oldCluster := cluster.DeepCopy()
+	delete(cluster.Annotations, kubermaticv1.CloneRequestAnnotation)
+	return r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster))
+}
@@ -0,0 +1,402 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterclone
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+	clusterclient "k8c.io/kubermatic/v2/pkg/cluster/client"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	clusterName = "clone"
+	projectID   = "testproject"
+	workerName  = ""
+)
+
+func init() {
+	if err := clusterv1alpha1.SchemeBuilder.AddToScheme(scheme.Scheme); err != nil {
+		panic(fmt.Sprintf("failed to add clusterv1alpha1 to scheme: %v", err))
+	}
+}
+
+func genCluster(cloneRequest string, apiserver kubermaticv1.HealthStatus) *kubermaticv1.Cluster {
+	return &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: clusterName,
+			Annotations: map[string]string{
+				kubermaticv1.CloneRequestAnnotation: cloneRequest,
+			},
+			Labels: map[string]string{
+				kubermaticv1.ProjectIDLabelKey: projectID,
+			},
+		},
+		Status: kubermaticv1.ClusterStatus{
+			NamespaceName: "cluster-" + clusterName,
+			ExtendedHealth: kubermaticv1.ExtendedClusterHealth{
+				Apiserver: apiserver,
+			},
+		},
+	}
+}
+
+func genSourceCluster(encryption *kubermaticv1.ClusterEncryptionStatus) *kubermaticv1.Cluster {
+	return &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "source",
+		},
+		Status: kubermaticv1.ClusterStatus{
+			NamespaceName: "cluster-source",
+			Encryption:    encryption,
+		},
+	}
+}
+
+func genSecret(namespace, name string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{"key": []byte(name)},
+	}
+}
+
+func genNamespace() *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "cluster-" + clusterName}}
+}
+
+func genRestore(phase kubermaticv1.EtcdRestorePhase) *kubermaticv1.EtcdRestore {
+	return &kubermaticv1.EtcdRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      RestoreName,
+			Namespace: "cluster-" + clusterName,
+		},
+		Spec: kubermaticv1.EtcdRestoreSpec{
+			Name:              RestoreName,
+			Cluster:           corev1.ObjectReference{Name: clusterName},
+			BackupName:        "nightly",
+			SourceClusterName: "source",
+		},
+		Status: kubermaticv1.EtcdRestoreStatus{
+			Phase: phase,
+		},
+	}
+}
+
+func genUserClusterObjects() []ctrlruntimeclient.Object {
+	return []ctrlruntimeclient.Object{
+		&clusterv1alpha1.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "workers", Namespace: metav1.NamespaceSystem},
+			Spec:       clusterv1alpha1.MachineDeploymentSpec{Replicas: pointer.Int32Ptr(3)},
+		},
+		&clusterv1alpha1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "workers-abc", Namespace: metav1.NamespaceSystem},
+		},
+		&clusterv1alpha1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "workers-abc-def",
+				Namespace:  metav1.NamespaceSystem,
+				Finalizers: []string{"machine-delete-finalizer"},
+			},
+		},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "workers-abc-def"},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "ingress",
+				Namespace:  "default",
+				Finalizers: []string{loadBalancerCleanupFinalizer},
+			},
+			Spec: corev1.ServiceSpec{
+				Type:  corev1.ServiceTypeLoadBalancer,
+				Ports: []corev1.ServicePort{{Port: 443, NodePort: 30443}},
+			},
+		},
+		&corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "data",
+				Finalizers: []string{"kubernetes.io/pv-protection", "external-provisioner.volume.kubernetes.io/finalizer"},
+			},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete,
+			},
+		},
+		&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "data",
+				Namespace:  "default",
+				Finalizers: []string{"kubernetes.io/pvc-protection"},
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{VolumeName: "data"},
+		},
+		&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "default"},
+		},
+		&storagev1.VolumeAttachment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "csi-abcdef",
+				Finalizers: []string{"external-attacher/csi-example-com"},
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "default-token-abcde", Namespace: "default"},
+			Type:       corev1.SecretTypeServiceAccountToken,
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+			Type:       corev1.SecretTypeOpaque,
+		},
+	}
+}
+
+const cloneRequest = `{"name":"","cluster":{},"backupName":"nightly","sourceClusterName":"source","destination":"s3"}`
+
+func TestReconcile(t *testing.T) {
+	encryptionStatus := &kubermaticv1.ClusterEncryptionStatus{
+		Keys:               []kubermaticv1.EncryptionKey{{Provider: kubermaticv1.EncryptionProviderAESCBC, Name: "key-0"}},
+		EncryptedResources: []string{"secrets"},
+	}
+
+	testCases := []struct {
+		name          string
+		seedObjects   []ctrlruntimeclient.Object
+		validate      func(seedClient, userClusterClient ctrlruntimeclient.Client) error
+		expectRequeue bool
+	}{
+		{
+			name:          "restore is not created before the cluster namespace exists",
+			seedObjects:   []ctrlruntimeclient.Object{genCluster(cloneRequest, kubermaticv1.HealthStatusDown)},
+			expectRequeue: true,
+			validate: func(seedClient, _ ctrlruntimeclient.Client) error {
+				restores := &kubermaticv1.EtcdRestoreList{}
+				if err := seedClient.List(context.Background(), restores); err != nil {
+					return err
+				}
+				if len(restores.Items) > 0 {
+					return fmt.Errorf("expected no restores, got %d", len(restores.Items))
+				}
+				return nil
+			},
+		},
+		{
+			name:        "restore of the source cluster backup is created",
+			seedObjects: []ctrlruntimeclient.Object{genCluster(cloneRequest, kubermaticv1.HealthStatusDown), genNamespace(), genSourceCluster(nil)},
+			validate: func(seedClient, _ ctrlruntimeclient.Client) error {
+				if err := seedClient.Get(context.Background(), types.NamespacedName{Namespace: "cluster-" + clusterName, Name: resources.EncryptionKeysSecretName}, &corev1.Secret{}); !kerrors.IsNotFound(err) {
+					return fmt.Errorf("expected no encryption keys for an unencrypted source cluster, got %v", err)
+				}
+
+				restore := &kubermaticv1.EtcdRestore{}
+				if err := seedClient.Get(context.Background(), types.NamespacedName{Namespace: "cluster-" + clusterName, Name: RestoreName}, restore); err != nil {
+					return err
+				}
+				if restore.Spec.Cluster.Name != clusterName {
+					return fmt.Errorf("expected restore into cluster %q, got %q", clusterName, restore.Spec.Cluster.Name)
+				}
+				if restore.BackupClusterName() != "source" || restore.Spec.BackupName != "nightly" || restore.Spec.Destination != "s3" {
+					return fmt.Errorf("restore does not match clone request: %+v", restore.Spec)
+				}
+				if restore.Labels[kubermaticv1.ProjectIDLabelKey] != projectID {
+					return fmt.Errorf("expected restore to have project label %q", projectID)
+				}
+				return expectCloneRequest(seedClient, true)
+			},
+		},
+		{
+			name: "encryption keys of the source cluster are copied before the restore",
+			seedObjects: []ctrlruntimeclient.Object{
+				genCluster(cloneRequest, kubermaticv1.HealthStatusDown),
+				genNamespace(),
+				genSourceCluster(encryptionStatus),
+				genSecret("cluster-source", resources.EncryptionKeysSecretName),
+				genSecret("cluster-source", resources.EncryptionConfigurationSecretName),
+			},
+			validate: func(seedClient, _ ctrlruntimeclient.Client) error {
+				ctx := context.Background()
+
+				for _, name := range []string{resources.EncryptionKeysSecretName, resources.EncryptionConfigurationSecretName} {
+					secret := &corev1.Secret{}
+					if err := seedClient.Get(ctx, types.NamespacedName{Namespace: "cluster-" + clusterName, Name: name}, secret); err != nil {
+						return fmt.Errorf("expected Secret %s to be copied: %v", name, err)
+					}
+					if string(secret.Data["key"]) != name {
+						return fmt.Errorf("Secret %s has unexpected data", name)
+					}
+				}
+
+				cluster := &kubermaticv1.Cluster{}
+				if err := seedClient.Get(ctx, types.NamespacedName{Name: clusterName}, cluster); err != nil {
+					return err
+				}
+				if !reflect.DeepEqual(cluster.Status.Encryption, encryptionStatus) {
+					return fmt.Errorf("expected encryption status %+v, got %+v", encryptionStatus, cluster.Status.Encryption)
+				}
+
+				return seedClient.Get(ctx, types.NamespacedName{Namespace: "cluster-" + clusterName, Name: RestoreName}, &kubermaticv1.EtcdRestore{})
+			},
+		},
+		{
+			name: "cluster is not detached before the backup has been restored",
+			seedObjects: []ctrlruntimeclient.Object{
+				genCluster(cloneRequest, kubermaticv1.HealthStatusUp),
+				genNamespace(),
+				genRestore(kubermaticv1.EtcdRestorePhaseStsRebuilding),
+			},
+			validate: func(seedClient, userClusterClient ctrlruntimeclient.Client) error {
+				if err := expectCloneRequest(seedClient, true); err != nil {
+					return err
+				}
+				return userClusterClient.Get(context.Background(), types.NamespacedName{Name: "workers-abc-def"}, &corev1.Node{})
+			},
+		},
+		{
+			name: "cluster is detached from the resources of the source cluster",
+			seedObjects: []ctrlruntimeclient.Object{
+				genCluster(cloneRequest, kubermaticv1.HealthStatusUp),
+				genNamespace(),
+				genRestore(kubermaticv1.EtcdRestorePhaseCompleted),
+			},
+			validate: func(seedClient, userClusterClient ctrlruntimeclient.Client) error {
+				ctx := context.Background()
+
+				md := &clusterv1alpha1.MachineDeployment{}
+				if err := userClusterClient.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: "workers"}, md); err != nil {
+					return err
+				}
+				if md.Spec.Replicas == nil || *md.Spec.Replicas != 0 {
+					return fmt.Errorf("expected machine deployment to be scaled to zero, got %v replicas", md.Spec.Replicas)
+				}
+
+				for _, obj := range []ctrlruntimeclient.Object{
+					&clusterv1alpha1.MachineSet{ObjectMeta: metav1.ObjectMeta{Name: "workers-abc", Namespace: metav1.NamespaceSystem}},
+					&clusterv1alpha1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "workers-abc-def", Namespace: metav1.NamespaceSystem}},
+					&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "workers-abc-def"}},
+					&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "default-token-abcde", Namespace: "default"}},
+					&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "data"}},
+					&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default"}},
+					&storagev1.VolumeAttachment{ObjectMeta: metav1.ObjectMeta{Name: "csi-abcdef"}},
+				} {
+					if err := userClusterClient.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(obj), obj); !kerrors.IsNotFound(err) {
+						return fmt.Errorf("expected %T %s to be deleted, got %v", obj, obj.GetName(), err)
+					}
+				}
+
+				if err := userClusterClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "app"}, &corev1.Secret{}); err != nil {
+					return fmt.Errorf("expected other secrets to be kept: %v", err)
+				}
+
+				service := &corev1.Service{}
+				if err := userClusterClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "ingress"}, service); err != nil {
+					return err
+				}
+				if service.Spec.Type != corev1.ServiceTypeClusterIP || len(service.Finalizers) > 0 || service.Spec.Ports[0].NodePort != 0 {
+					return fmt.Errorf("expected service to be detached from its load balancer, got %+v", service)
+				}
+
+				if err := userClusterClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "pending"}, &corev1.PersistentVolumeClaim{}); err != nil {
+					return fmt.Errorf("expected unbound claims to be kept: %v", err)
+				}
+
+				return expectCloneRequest(seedClient, false)
+			},
+		},
+		{
+			name:        "invalid clone request is dropped",
+			seedObjects: []ctrlruntimeclient.Object{genCluster(`{"backupName":"nightly"}`, kubermaticv1.HealthStatusUp), genNamespace()},
+			validate: func(seedClient, _ ctrlruntimeclient.Client) error {
+				return expectCloneRequest(seedClient, false)
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			seedClient := fakectrlruntimeclient.
+				NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(test.seedObjects...).
+				Build()
+
+			userClusterClient := fakectrlruntimeclient.
+				NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(genUserClusterObjects()...).
+				Build()
+
+			r := &Reconciler{
+				Client:                        seedClient,
+				workerName:                    workerName,
+				recorder:                      &record.FakeRecorder{},
+				userClusterConnectionProvider: &fakeClientProvider{client: userClusterClient},
+				log:                           zap.NewNop().Sugar(),
+			}
+
+			result, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: clusterName}})
+			if err != nil {
+				t.Fatalf("reconciling failed: %v", err)
+			}
+			if requeue := result.RequeueAfter > 0; requeue != test.expectRequeue {
+				t.Errorf("expected requeue to be %v, got %v", test.expectRequeue, requeue)
+			}
+
+			if err := test.validate(seedClient, userClusterClient); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func expectCloneRequest(client ctrlruntimeclient.Client, expected bool) error {
+	cluster := &kubermaticv1.Cluster{}
+	if err := client.Get(context.Background(), types.NamespacedName{Name: clusterName}, cluster); err != nil {
+		return err
+	}
+	if cluster.IsClonePending() != expected {
+		return fmt.Errorf("expected clone request to be present: %v, got %v", expected, cluster.IsClonePending())
+	}
+	return nil
+}
+
+type fakeClientProvider struct {
+	client ctrlruntimeclient.Client
+}
+
+func (f *fakeClientProvider) GetClient(ctx context.Context, c *kubermaticv1.Cluster, options ...clusterclient.ConfigOption) (ctrlruntimeclient.Client, error) {
+	return f.client, nil
+}
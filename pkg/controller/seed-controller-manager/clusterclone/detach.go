@@ -0,0 +1,261 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterclone
+
+import (
+	"context"
+	"fmt"
+
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/utils/pointer"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// loadBalancerCleanupFinalizer is set by the service controller on services of type
+// LoadBalancer. If it is present when the service changes its type, the load balancer
+// is deleted.
+const loadBalancerCleanupFinalizer = "service.kubernetes.io/load-balancer-cleanup"
+
+// detachFromSourceCluster removes all references to the cloud resources of the source
+// cluster from the restored data, so that neither the controllers of the clone nor the
+// deletion of the clone can modify them.
+func detachFromSourceCluster(ctx context.Context, client ctrlruntimeclient.Client) error {
+	if err := scaleDownMachineDeployments(ctx, client); err != nil {
+		return fmt.Errorf("failed to scale down machine deployments: %v", err)
+	}
+	if err := removeMachines(ctx, client); err != nil {
+		return fmt.Errorf("failed to remove machines: %v", err)
+	}
+	if err := removeNodes(ctx, client); err != nil {
+		return fmt.Errorf("failed to remove nodes: %v", err)
+	}
+	if err := detachLoadBalancers(ctx, client); err != nil {
+		return fmt.Errorf("failed to detach load balancers: %v", err)
+	}
+	if err := removePersistentVolumes(ctx, client); err != nil {
+		return fmt.Errorf("failed to remove persistent volumes: %v", err)
+	}
+	if err := removeServiceAccountTokens(ctx, client); err != nil {
+		return fmt.Errorf("failed to remove service account tokens: %v", err)
+	}
+	return nil
+}
+
+func scaleDownMachineDeployments(ctx context.Context, client ctrlruntimeclient.Client) error {
+	machineDeployments := &clusterv1alpha1.MachineDeploymentList{}
+	if err := client.List(ctx, machineDeployments); err != nil {
+		return err
+	}
+
+	for _, md := range machineDeployments.Items {
+		if md.Spec.Replicas != nil && *md.Spec.Replicas == 0 {
+			continue
+		}
+
+		oldMD := md.DeepCopy()
+		md.Spec.Replicas = pointer.Int32Ptr(0)
+		if err := client.Patch(ctx, &md, ctrlruntimeclient.MergeFrom(oldMD)); err != nil {
+			return fmt.Errorf("failed to scale down %s/%s: %v", md.Namespace, md.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// removeMachines deletes all machine sets and machines. The finalizers of the machines
+// are removed first, so that the machine controller never deletes their instances, which
+// still belong to the source cluster.
+func removeMachines(ctx context.Context, client ctrlruntimeclient.Client) error {
+	machineSets := &clusterv1alpha1.MachineSetList{}
+	if err := client.List(ctx, machineSets); err != nil {
+		return err
+	}
+
+	for _, ms := range machineSets.Items {
+		if err := client.Delete(ctx, &ms); ctrlruntimeclient.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete machine set %s/%s: %v", ms.Namespace, ms.Name, err)
+		}
+	}
+
+	machines := &clusterv1alpha1.MachineList{}
+	if err := client.List(ctx, machines); err != nil {
+		return err
+	}
+
+	for _, machine := range machines.Items {
+		if len(machine.Finalizers) > 0 {
+			oldMachine := machine.DeepCopy()
+			machine.Finalizers = nil
+			if err := client.Patch(ctx, &machine, ctrlruntimeclient.MergeFrom(oldMachine)); err != nil {
+				return fmt.Errorf("failed to remove finalizers from machine %s/%s: %v", machine.Namespace, machine.Name, err)
+			}
+		}
+
+		if err := client.Delete(ctx, &machine); ctrlruntimeclient.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete machine %s/%s: %v", machine.Namespace, machine.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func removeNodes(ctx context.Context, client ctrlruntimeclient.Client) error {
+	nodes := &corev1.NodeList{}
+	if err := client.List(ctx, nodes); err != nil {
+		return err
+	}
+
+	for _, node := range nodes.Items {
+		if err := client.Delete(ctx, &node); ctrlruntimeclient.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete node %s: %v", node.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// detachLoadBalancers changes all services of type LoadBalancer to ClusterIP. Load balancers
+// are identified by the UID of their service, which is the same in the clone, so the cloud
+// controller of the clone would otherwise take them over.
+func detachLoadBalancers(ctx context.Context, client ctrlruntimeclient.Client) error {
+	services := &corev1.ServiceList{}
+	if err := client.List(ctx, services); err != nil {
+		return err
+	}
+
+	for _, service := range services.Items {
+		if service.Spec.Type != corev1.ServiceTypeLoadBalancer && !kuberneteshelper.HasFinalizer(&service, loadBalancerCleanupFinalizer) {
+			continue
+		}
+
+		oldService := service.DeepCopy()
+		kuberneteshelper.RemoveFinalizer(&service, loadBalancerCleanupFinalizer)
+		if service.Spec.Type == corev1.ServiceTypeLoadBalancer {
+			service.Spec.Type = corev1.ServiceTypeClusterIP
+			service.Spec.ExternalTrafficPolicy = ""
+			service.Spec.HealthCheckNodePort = 0
+			service.Spec.LoadBalancerIP = ""
+			service.Spec.LoadBalancerSourceRanges = nil
+			service.Spec.AllocateLoadBalancerNodePorts = nil
+			for i := range service.Spec.Ports {
+				service.Spec.Ports[i].NodePort = 0
+			}
+		}
+
+		if err := client.Patch(ctx, &service, ctrlruntimeclient.MergeFrom(oldService)); err != nil {
+			return fmt.Errorf("failed to detach service %s/%s: %v", service.Namespace, service.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// removePersistentVolumes deletes all persistent volumes, their claims and attachments, which
+// refer to the disks of the source cluster, so the clone never attaches or deletes them. The
+// volumes are retained first, so deleting them or their claims leaves the disks alone. Claims
+// of stateful sets are recreated with new volumes.
+func removePersistentVolumes(ctx context.Context, client ctrlruntimeclient.Client) error {
+	volumes := &corev1.PersistentVolumeList{}
+	if err := client.List(ctx, volumes); err != nil {
+		return err
+	}
+
+	for _, volume := range volumes.Items {
+		if volume.Spec.PersistentVolumeReclaimPolicy != corev1.PersistentVolumeReclaimRetain || len(volume.Finalizers) > 0 {
+			oldVolume := volume.DeepCopy()
+			volume.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimRetain
+			volume.Finalizers = nil
+			if err := client.Patch(ctx, &volume, ctrlruntimeclient.MergeFrom(oldVolume)); err != nil {
+				return fmt.Errorf("failed to retain persistent volume %s: %v", volume.Name, err)
+			}
+		}
+
+		if err := client.Delete(ctx, &volume); ctrlruntimeclient.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete persistent volume %s: %v", volume.Name, err)
+		}
+	}
+
+	claims := &corev1.PersistentVolumeClaimList{}
+	if err := client.List(ctx, claims); err != nil {
+		return err
+	}
+
+	for _, claim := range claims.Items {
+		if claim.Spec.VolumeName == "" {
+			continue
+		}
+
+		if len(claim.Finalizers) > 0 {
+			oldClaim := claim.DeepCopy()
+			claim.Finalizers = nil
+			if err := client.Patch(ctx, &claim, ctrlruntimeclient.MergeFrom(oldClaim)); err != nil {
+				return fmt.Errorf("failed to remove finalizers from persistent volume claim %s/%s: %v", claim.Namespace, claim.Name, err)
+			}
+		}
+
+		if err := client.Delete(ctx, &claim); ctrlruntimeclient.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete persistent volume claim %s/%s: %v", claim.Namespace, claim.Name, err)
+		}
+	}
+
+	// the CSI attacher would otherwise detach the disks from the instances of the source cluster
+	attachments := &storagev1.VolumeAttachmentList{}
+	if err := client.List(ctx, attachments); err != nil {
+		return err
+	}
+
+	for _, attachment := range attachments.Items {
+		if len(attachment.Finalizers) > 0 {
+			oldAttachment := attachment.DeepCopy()
+			attachment.Finalizers = nil
+			if err := client.Patch(ctx, &attachment, ctrlruntimeclient.MergeFrom(oldAttachment)); err != nil {
+				return fmt.Errorf("failed to remove finalizers from volume attachment %s: %v", attachment.Name, err)
+			}
+		}
+
+		if err := client.Delete(ctx, &attachment); ctrlruntimeclient.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete volume attachment %s: %v", attachment.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// removeServiceAccountTokens deletes all service account tokens, which are signed with the
+// service account key of the source cluster. The controller manager recreates them.
+func removeServiceAccountTokens(ctx context.Context, client ctrlruntimeclient.Client) error {
+	secrets := &corev1.SecretList{}
+	if err := client.List(ctx, secrets); err != nil {
+		return err
+	}
+
+	for _, secret := range secrets.Items {
+		if secret.Type != corev1.SecretTypeServiceAccountToken {
+			continue
+		}
+
+		if err := client.Delete(ctx, &secret); ctrlruntimeclient.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete secret %s/%s: %v", secret.Namespace, secret.Name, err)
+		}
+	}
+
+	return nil
+}
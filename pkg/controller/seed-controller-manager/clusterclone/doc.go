@@ -0,0 +1,30 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package clusterclone contains a controller that seeds clusters created by the
+REST API's clone-cluster endpoint with the etcd backup of their source cluster.
+
+The restored data still refers to the machines, nodes, load balancers and
+volumes of the source cluster. Until the controller has detached the clone
+from them, the controllers that could act on these cloud resources (machine
+controller, controller manager, cloud controller manager and cluster
+autoscaler) are kept scaled down.
+
+The backup of an encrypted source cluster can only be read with its keys, so
+they are copied into the clone before the backup is restored.
+*/
+package clusterclone
@@ -0,0 +1,67 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterclone
+
+import (
+	"context"
+	"fmt"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// copyEncryption copies the encryption keys and configuration of the source cluster into the clone,
+// as the restored etcd data is encrypted with them. The encryption controller leaves the clone alone
+// until it has been restored, so it does not generate keys of its own in the meantime.
+func (r *Reconciler) copyEncryption(ctx context.Context, cluster, source *kubermaticv1.Cluster) error {
+	if source.Status.Encryption == nil || cluster.Status.Encryption != nil {
+		return nil
+	}
+
+	for _, name := range []string{resources.EncryptionKeysSecretName, resources.EncryptionConfigurationSecretName} {
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: source.Status.NamespaceName, Name: name}, secret); err != nil {
+			return fmt.Errorf("failed to get Secret %s of cluster %s: %v", name, source.Name, err)
+		}
+
+		copied := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       cluster.Status.NamespaceName,
+				OwnerReferences: []metav1.OwnerReference{resources.GetClusterRef(cluster)},
+			},
+			Data: secret.Data,
+		}
+		if err := r.Create(ctx, copied); err != nil && !kerrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create Secret %s: %v", name, err)
+		}
+	}
+
+	oldCluster := cluster.DeepCopy()
+	cluster.Status.Encryption = source.Status.Encryption.DeepCopy()
+	if err := r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+		return fmt.Errorf("failed to update encryption status: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,395 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentialrotation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	clusterclient "k8c.io/kubermatic/v2/pkg/cluster/client"
+	controllerutil "k8c.io/kubermatic/v2/pkg/controller/util"
+	predicateutil "k8c.io/kubermatic/v2/pkg/controller/util/predicate"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1/helper"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const ControllerName = "kubermatic_credential_rotation_controller"
+
+// cloudConfigSecrets maps the cloud-config ConfigMaps in the cluster namespace to the
+// Secrets the user cluster controller syncs them into.
+var cloudConfigSecrets = map[string]string{
+	resources.CloudConfigConfigMapName:    resources.CloudConfigSecretName,
+	resources.CSICloudConfigConfigMapName: resources.CSICloudConfigSecretName,
+}
+
+// UserClusterClientProvider provides functionality to get a user cluster client
+type UserClusterClientProvider interface {
+	GetClient(ctx context.Context, c *kubermaticv1.Cluster, options ...clusterclient.ConfigOption) (ctrlruntimeclient.Client, error)
+}
+
+type Reconciler struct {
+	ctrlruntimeclient.Client
+
+	workerName                    string
+	recorder                      record.EventRecorder
+	userClusterConnectionProvider UserClusterClientProvider
+	log                           *zap.SugaredLogger
+	versions                      kubermatic.Versions
+}
+
+// Add creates a new credential rotation controller
+func Add(
+	mgr manager.Manager,
+	numWorkers int,
+	workerName string,
+	userClusterConnectionProvider UserClusterClientProvider,
+	log *zap.SugaredLogger,
+	versions kubermatic.Versions,
+) error {
+	reconciler := &Reconciler{
+		Client: mgr.GetClient(),
+
+		workerName:                    workerName,
+		recorder:                      mgr.GetEventRecorderFor(ControllerName),
+		userClusterConnectionProvider: userClusterConnectionProvider,
+		log:                           log.Named(ControllerName),
+		versions:                      versions,
+	}
+
+	c, err := controller.New(ControllerName, mgr, controller.Options{
+		Reconciler:              reconciler,
+		MaxConcurrentReconciles: numWorkers,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create controller: %v", err)
+	}
+
+	if err := c.Watch(&source.Kind{Type: &kubermaticv1.Cluster{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return fmt.Errorf("failed to create cluster watch: %v", err)
+	}
+
+	if err := c.Watch(&source.Kind{Type: &appsv1.Deployment{}}, controllerutil.EnqueueClusterForNamespacedObject(mgr.GetClient())); err != nil {
+		return fmt.Errorf("failed to create deployment watch: %v", err)
+	}
+
+	credentialSecrets := predicateutil.Factory(func(o ctrlruntimeclient.Object) bool {
+		return o.GetNamespace() == resources.KubermaticNamespace && strings.HasPrefix(o.GetName(), kubermaticv1.CredentialPrefix+"-")
+	})
+	if err := c.Watch(&source.Kind{Type: &corev1.Secret{}}, enqueueClustersForCredentialSecret(mgr.GetClient()), credentialSecrets); err != nil {
+		return fmt.Errorf("failed to create secret watch: %v", err)
+	}
+
+	return nil
+}
+
+// enqueueClustersForCredentialSecret enqueues the cluster whose cloud credentials are stored in the secret.
+func enqueueClustersForCredentialSecret(client ctrlruntimeclient.Client) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(o ctrlruntimeclient.Object) []reconcile.Request {
+		clusters := &kubermaticv1.ClusterList{}
+		if err := client.List(context.Background(), clusters); err != nil {
+			utilruntime.HandleError(fmt.Errorf("failed to list Clusters: %v", err))
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for _, cluster := range clusters.Items {
+			if cluster.GetSecretName() == o.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: cluster.Name}})
+			}
+		}
+		return requests
+	})
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	log := r.log.With("cluster", request.Name)
+
+	cluster := &kubermaticv1.Cluster{}
+	if err := r.Get(ctx, request.NamespacedName, cluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if cluster.Labels[kubermaticv1.WorkerNameLabelKey] != r.workerName {
+		return reconcile.Result{}, nil
+	}
+
+	if cluster.Spec.Pause || cluster.DeletionTimestamp != nil || cluster.Status.NamespaceName == "" {
+		return reconcile.Result{}, nil
+	}
+
+	result, err := r.reconcile(ctx, log, cluster)
+	if err != nil {
+		log.Errorw("Reconciling failed", zap.Error(err))
+		r.recorder.Event(cluster, corev1.EventTypeWarning, "ReconcilingError", err.Error())
+	}
+
+	if result == nil {
+		result = &reconcile.Result{}
+	}
+	return *result, err
+}
+
+// reconcile compares the checksum of the credentials with the kubermatic.io/credentials-revision
+// annotation of the cluster, which the cluster controller copies to the pod templates of all control
+// plane components using them. Once these have been restarted and the cloud-config has been synced
+// into the user cluster, the workloads mounting it are restarted as well and the
+// CloudCredentialsRotated condition is set.
+func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	revision, err := resources.GetCredentialsChecksum(resources.NewCredentialsData(ctx, cluster, r))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cloud credentials: %v", err)
+	}
+
+	if current := cluster.Annotations[kubermaticv1.CredentialsRevisionAnnotation]; current != revision {
+		// the first revision only records the credentials the cluster was created with
+		return nil, r.startRotation(ctx, log, cluster, revision, current != "")
+	}
+
+	_, condition := kubermaticv1helper.GetClusterCondition(cluster, kubermaticv1.ClusterConditionCloudCredentialsRotated)
+	if condition == nil || condition.Status == corev1.ConditionTrue {
+		return nil, nil
+	}
+
+	pending, err := r.pendingDeployments(ctx, cluster, revision)
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) > 0 {
+		log.Debugw("Waiting for control plane components to be restarted", "deployments", pending)
+		return nil, nil
+	}
+
+	if cluster.Status.ExtendedHealth.Apiserver != kubermaticv1.HealthStatusUp {
+		log.Debug("Waiting for apiserver")
+		return &reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	userClusterClient, err := r.userClusterConnectionProvider.GetClient(ctx, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user cluster client: %v", err)
+	}
+
+	synced, err := r.cloudConfigSynced(ctx, cluster, userClusterClient)
+	if err != nil {
+		return nil, err
+	}
+	if !synced {
+		log.Debug("Waiting for the cloud-config to be synced into the user cluster")
+		return &reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	if err := restartCloudConfigWorkloads(ctx, userClusterClient, revision); err != nil {
+		return nil, fmt.Errorf("failed to restart user cluster workloads: %v", err)
+	}
+
+	oldCluster := cluster.DeepCopy()
+	kubermaticv1helper.SetClusterCondition(
+		cluster,
+		r.versions,
+		kubermaticv1.ClusterConditionCloudCredentialsRotated,
+		corev1.ConditionTrue,
+		kubermaticv1.ReasonCredentialsRotationSucceeded,
+		"All components have been restarted with the current cloud credentials",
+	)
+	if err := r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+		return nil, fmt.Errorf("failed to update cluster condition: %v", err)
+	}
+
+	log.Info("Cloud credentials have been rotated")
+	r.recorder.Event(cluster, corev1.EventTypeNormal, "CredentialsRotated", "All components have been restarted with the rotated cloud credentials")
+
+	return nil, nil
+}
+
+// startRotation records the new credentials revision in the cluster, which makes the cluster
+// controller restart all control plane components that use the credentials.
+func (r *Reconciler) startRotation(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster, revision string, rotated bool) error {
+	oldCluster := cluster.DeepCopy()
+	if cluster.Annotations == nil {
+		cluster.Annotations = map[string]string{}
+	}
+	cluster.Annotations[kubermaticv1.CredentialsRevisionAnnotation] = revision
+
+	if rotated {
+		kubermaticv1helper.SetClusterCondition(
+			cluster,
+			r.versions,
+			kubermaticv1.ClusterConditionCloudCredentialsRotated,
+			corev1.ConditionFalse,
+			kubermaticv1.ReasonCredentialsRotationInProgress,
+			"Restarting all components that use the cloud credentials",
+		)
+	}
+
+	if err := r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+		return fmt.Errorf("failed to update credentials revision: %v", err)
+	}
+
+	if rotated {
+		log.Info("Cloud credentials have changed, restarting control plane")
+		r.recorder.Event(cluster, corev1.EventTypeNormal, "CredentialsRotationStarted", "Cloud credentials have changed, restarting all components that use them")
+	}
+
+	return nil
+}
+
+// pendingDeployments returns the names of the control plane deployments that use the cloud credentials
+// and have not yet been rolled out with the given credentials revision.
+func (r *Reconciler) pendingDeployments(ctx context.Context, cluster *kubermaticv1.Cluster, revision string) ([]string, error) {
+	deployments := &appsv1.DeploymentList{}
+	if err := r.List(ctx, deployments, ctrlruntimeclient.InNamespace(cluster.Status.NamespaceName)); err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %v", err)
+	}
+
+	var pending []string
+	for _, deployment := range deployments.Items {
+		current, ok := deployment.Spec.Template.Annotations[kubermaticv1.CredentialsRevisionAnnotation]
+		if !ok {
+			continue
+		}
+		if current != revision || !rolledOut(&deployment) {
+			pending = append(pending, deployment.Name)
+		}
+	}
+
+	return pending, nil
+}
+
+func rolledOut(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+
+	return deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.Replicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas
+}
+
+// cloudConfigSynced checks whether the user cluster controller has synced the cloud-config
+// of the cluster namespace into the user cluster.
+func (r *Reconciler) cloudConfigSynced(ctx context.Context, cluster *kubermaticv1.Cluster, userClusterClient ctrlruntimeclient.Client) (bool, error) {
+	for configMapName, secretName := range cloudConfigSecrets {
+		configMap := &corev1.ConfigMap{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: configMapName}, configMap); err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return false, fmt.Errorf("failed to get ConfigMap %s: %v", configMapName, err)
+		}
+
+		secret := &corev1.Secret{}
+		if err := userClusterClient.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: secretName}, secret); err != nil {
+			if kerrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to get Secret %s: %v", secretName, err)
+		}
+
+		if !bytes.Equal(secret.Data[resources.CloudConfigSecretKey], []byte(configMap.Data[resources.CloudConfigConfigMapKey])) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// restartCloudConfigWorkloads restarts all workloads in the kube-system namespace of the user cluster
+// that mount the cloud-config, like CSI drivers, by annotating their pod templates with the revision.
+func restartCloudConfigWorkloads(ctx context.Context, client ctrlruntimeclient.Client, revision string) error {
+	deployments := &appsv1.DeploymentList{}
+	if err := client.List(ctx, deployments, ctrlruntimeclient.InNamespace(metav1.NamespaceSystem)); err != nil {
+		return fmt.Errorf("failed to list deployments: %v", err)
+	}
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		if err := restartWorkload(ctx, client, deployment, &deployment.Spec.Template, revision); err != nil {
+			return err
+		}
+	}
+
+	daemonSets := &appsv1.DaemonSetList{}
+	if err := client.List(ctx, daemonSets, ctrlruntimeclient.InNamespace(metav1.NamespaceSystem)); err != nil {
+		return fmt.Errorf("failed to list daemonsets: %v", err)
+	}
+	for i := range daemonSets.Items {
+		daemonSet := &daemonSets.Items[i]
+		if err := restartWorkload(ctx, client, daemonSet, &daemonSet.Spec.Template, revision); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// restartWorkload annotates the pod template of the workload with the revision, if it mounts the cloud-config.
+// template must point into obj.
+func restartWorkload(ctx context.Context, client ctrlruntimeclient.Client, obj ctrlruntimeclient.Object, template *corev1.PodTemplateSpec, revision string) error {
+	if !mountsCloudConfig(template.Spec) || template.Annotations[kubermaticv1.CredentialsRevisionAnnotation] == revision {
+		return nil
+	}
+
+	oldObj := obj.DeepCopyObject().(ctrlruntimeclient.Object)
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[kubermaticv1.CredentialsRevisionAnnotation] = revision
+
+	if err := client.Patch(ctx, obj, ctrlruntimeclient.MergeFrom(oldObj)); err != nil {
+		return fmt.Errorf("failed to restart %s: %v", obj.GetName(), err)
+	}
+	return nil
+}
+
+func mountsCloudConfig(spec corev1.PodSpec) bool {
+	for _, volume := range spec.Volumes {
+		if volume.Secret == nil {
+			continue
+		}
+		for _, secretName := range cloudConfigSecrets {
+			if volume.Secret.SecretName == secretName {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,308 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentialrotation
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"go.uber.org/zap"
+
+	clusterclient "k8c.io/kubermatic/v2/pkg/cluster/client"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1/helper"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	clusterName      = "testcluster"
+	clusterNamespace = "cluster-" + clusterName
+	workerName       = ""
+	cloudConfig      = "token = new"
+)
+
+func genCluster(revision string, rotationStatus corev1.ConditionStatus) *kubermaticv1.Cluster {
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        clusterName,
+			Annotations: map[string]string{},
+		},
+		Spec: kubermaticv1.ClusterSpec{
+			Cloud: kubermaticv1.CloudSpec{
+				Hetzner: &kubermaticv1.HetznerCloudSpec{Token: "new"},
+			},
+		},
+		Status: kubermaticv1.ClusterStatus{
+			NamespaceName: clusterNamespace,
+			ExtendedHealth: kubermaticv1.ExtendedClusterHealth{
+				Apiserver: kubermaticv1.HealthStatusUp,
+			},
+		},
+	}
+
+	if revision != "" {
+		cluster.Annotations[kubermaticv1.CredentialsRevisionAnnotation] = revision
+	}
+	if rotationStatus != "" {
+		kubermaticv1helper.SetClusterCondition(cluster, kubermatic.Versions{}, kubermaticv1.ClusterConditionCloudCredentialsRotated,
+			rotationStatus, kubermaticv1.ReasonCredentialsRotationInProgress, "")
+	}
+
+	return cluster
+}
+
+func currentRevision(t *testing.T) string {
+	revision, err := resources.GetCredentialsChecksum(resources.NewCredentialsData(context.Background(), genCluster("", ""), nil))
+	if err != nil {
+		t.Fatalf("failed to compute credentials revision: %v", err)
+	}
+	return revision
+}
+
+func genDeployment(revision string, rolledOut bool) *appsv1.Deployment {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resources.MachineControllerDeploymentName,
+			Namespace: clusterNamespace,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: pointer.Int32Ptr(2),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{kubermaticv1.CredentialsRevisionAnnotation: revision},
+				},
+			},
+		},
+		Status: appsv1.DeploymentStatus{
+			Replicas:          2,
+			UpdatedReplicas:   1,
+			AvailableReplicas: 2,
+		},
+	}
+	if rolledOut {
+		deployment.Status.UpdatedReplicas = 2
+	}
+	return deployment
+}
+
+func genCloudConfigConfigMap() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resources.CSICloudConfigConfigMapName,
+			Namespace: clusterNamespace,
+		},
+		Data: map[string]string{resources.CloudConfigConfigMapKey: cloudConfig},
+	}
+}
+
+func genUserClusterObjects(config string) []ctrlruntimeclient.Object {
+	return []ctrlruntimeclient.Object{
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      resources.CSICloudConfigSecretName,
+				Namespace: metav1.NamespaceSystem,
+			},
+			Data: map[string][]byte{resources.CloudConfigSecretKey: []byte(config)},
+		},
+		&appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "csi-node",
+				Namespace: metav1.NamespaceSystem,
+			},
+			Spec: appsv1.DaemonSetSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Volumes: []corev1.Volume{{
+							Name: "cloud-config",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{SecretName: resources.CSICloudConfigSecretName},
+							},
+						}},
+					},
+				},
+			},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "coredns",
+				Namespace: metav1.NamespaceSystem,
+			},
+		},
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	revision := currentRevision(t)
+
+	testCases := []struct {
+		name                   string
+		seedObjects            []ctrlruntimeclient.Object
+		userClusterObjects     []ctrlruntimeclient.Object
+		expectRequeue          bool
+		expectedRotationStatus corev1.ConditionStatus
+		expectRestartedCSI     bool
+	}{
+		{
+			name:        "first revision is recorded without rotating",
+			seedObjects: []ctrlruntimeclient.Object{genCluster("", "")},
+		},
+		{
+			name:                   "changed credentials start a rotation",
+			seedObjects:            []ctrlruntimeclient.Object{genCluster("old", "")},
+			expectedRotationStatus: corev1.ConditionFalse,
+		},
+		{
+			name: "rotation waits for control plane components to be updated",
+			seedObjects: []ctrlruntimeclient.Object{
+				genCluster(revision, corev1.ConditionFalse),
+				genDeployment("old", true),
+				genCloudConfigConfigMap(),
+			},
+			userClusterObjects:     genUserClusterObjects(cloudConfig),
+			expectedRotationStatus: corev1.ConditionFalse,
+		},
+		{
+			name: "rotation waits for control plane components to be rolled out",
+			seedObjects: []ctrlruntimeclient.Object{
+				genCluster(revision, corev1.ConditionFalse),
+				genDeployment(revision, false),
+				genCloudConfigConfigMap(),
+			},
+			userClusterObjects:     genUserClusterObjects(cloudConfig),
+			expectedRotationStatus: corev1.ConditionFalse,
+		},
+		{
+			name: "rotation waits for the cloud-config to be synced into the user cluster",
+			seedObjects: []ctrlruntimeclient.Object{
+				genCluster(revision, corev1.ConditionFalse),
+				genDeployment(revision, true),
+				genCloudConfigConfigMap(),
+			},
+			userClusterObjects:     genUserClusterObjects("token = old"),
+			expectRequeue:          true,
+			expectedRotationStatus: corev1.ConditionFalse,
+		},
+		{
+			name: "user cluster workloads are restarted and rotation is completed",
+			seedObjects: []ctrlruntimeclient.Object{
+				genCluster(revision, corev1.ConditionFalse),
+				genDeployment(revision, true),
+				genCloudConfigConfigMap(),
+			},
+			userClusterObjects:     genUserClusterObjects(cloudConfig),
+			expectedRotationStatus: corev1.ConditionTrue,
+			expectRestartedCSI:     true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			seedClient := fakectrlruntimeclient.
+				NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(test.seedObjects...).
+				Build()
+
+			userClusterClient := fakectrlruntimeclient.
+				NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(test.userClusterObjects...).
+				Build()
+
+			r := &Reconciler{
+				Client:                        seedClient,
+				workerName:                    workerName,
+				recorder:                      &record.FakeRecorder{},
+				userClusterConnectionProvider: &fakeClientProvider{client: userClusterClient},
+				log:                           zap.NewNop().Sugar(),
+			}
+
+			result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: clusterName}})
+			if err != nil {
+				t.Fatalf("reconciling failed: %v", err)
+			}
+			if requeue := result.RequeueAfter > 0; requeue != test.expectRequeue {
+				t.Errorf("expected requeue to be %v, got %v", test.expectRequeue, requeue)
+			}
+
+			cluster := &kubermaticv1.Cluster{}
+			if err := seedClient.Get(ctx, types.NamespacedName{Name: clusterName}, cluster); err != nil {
+				t.Fatalf("failed to get cluster: %v", err)
+			}
+			if current := cluster.Annotations[kubermaticv1.CredentialsRevisionAnnotation]; current != revision {
+				t.Errorf("expected credentials revision %q, got %q", revision, current)
+			}
+
+			var rotationStatus corev1.ConditionStatus
+			if _, condition := kubermaticv1helper.GetClusterCondition(cluster, kubermaticv1.ClusterConditionCloudCredentialsRotated); condition != nil {
+				rotationStatus = condition.Status
+			}
+			if rotationStatus != test.expectedRotationStatus {
+				t.Errorf("expected rotation condition status %q, got %q", test.expectedRotationStatus, rotationStatus)
+			}
+
+			if err := validateUserClusterWorkloads(userClusterClient, revision, test.expectRestartedCSI); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func validateUserClusterWorkloads(client ctrlruntimeclient.Client, revision string, expectRestartedCSI bool) error {
+	ctx := context.Background()
+
+	daemonSet := &appsv1.DaemonSet{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: "csi-node"}, daemonSet); err != nil {
+		return ctrlruntimeclient.IgnoreNotFound(err)
+	}
+	if restarted := daemonSet.Spec.Template.Annotations[kubermaticv1.CredentialsRevisionAnnotation] == revision; restarted != expectRestartedCSI {
+		return fmt.Errorf("expected CSI daemonset to be restarted: %v, got %v", expectRestartedCSI, restarted)
+	}
+
+	deployment := &appsv1.Deployment{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: "coredns"}, deployment); err != nil {
+		return err
+	}
+	if _, ok := deployment.Spec.Template.Annotations[kubermaticv1.CredentialsRevisionAnnotation]; ok {
+		return fmt.Errorf("expected workloads without cloud-config not to be restarted")
+	}
+
+	return nil
+}
+
+type fakeClientProvider struct {
+	client ctrlruntimeclient.Client
+}
+
+func (f *fakeClientProvider) GetClient(ctx context.Context, c *kubermaticv1.Cluster, options ...clusterclient.ConfigOption) (ctrlruntimeclient.Client, error) {
+	return f.client, nil
+}
@@ -0,0 +1,556 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"bytes"
+	"context"
+	cryptorand "crypto/rand"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	clusterclient "k8c.io/kubermatic/v2/pkg/cluster/client"
+	controllerutil "k8c.io/kubermatic/v2/pkg/controller/util"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1/helper"
+	"k8c.io/kubermatic/v2/pkg/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/apiserver"
+	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	ControllerName = "kubermatic_encryption_controller"
+
+	// keySize is the size of the keys generated for aescbc and secretbox.
+	keySize = 32
+	// rewritePageSize is the number of objects that are listed at once when rewriting resources.
+	rewritePageSize = 500
+)
+
+var (
+	defaultEncryptedResources = []string{"secrets"}
+
+	identityKey = kubermaticv1.EncryptionKey{Provider: kubermaticv1.EncryptionProviderIdentity, Name: "identity"}
+)
+
+// UserClusterClientProvider provides functionality to get a user cluster client
+type UserClusterClientProvider interface {
+	GetClient(ctx context.Context, c *kubermaticv1.Cluster, options ...clusterclient.ConfigOption) (ctrlruntimeclient.Client, error)
+}
+
+type Reconciler struct {
+	ctrlruntimeclient.Client
+
+	workerName                    string
+	recorder                      record.EventRecorder
+	userClusterConnectionProvider UserClusterClientProvider
+	log                           *zap.SugaredLogger
+	versions                      kubermatic.Versions
+}
+
+// Add creates a new encryption controller
+func Add(
+	mgr manager.Manager,
+	numWorkers int,
+	workerName string,
+	userClusterConnectionProvider UserClusterClientProvider,
+	log *zap.SugaredLogger,
+	versions kubermatic.Versions,
+) error {
+	reconciler := &Reconciler{
+		Client: mgr.GetClient(),
+
+		workerName:                    workerName,
+		recorder:                      mgr.GetEventRecorderFor(ControllerName),
+		userClusterConnectionProvider: userClusterConnectionProvider,
+		log:                           log.Named(ControllerName),
+		versions:                      versions,
+	}
+
+	c, err := controller.New(ControllerName, mgr, controller.Options{
+		Reconciler:              reconciler,
+		MaxConcurrentReconciles: numWorkers,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create controller: %v", err)
+	}
+
+	if err := c.Watch(&source.Kind{Type: &kubermaticv1.Cluster{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return fmt.Errorf("failed to create cluster watch: %v", err)
+	}
+
+	if err := c.Watch(&source.Kind{Type: &appsv1.Deployment{}}, controllerutil.EnqueueClusterForNamespacedObject(mgr.GetClient())); err != nil {
+		return fmt.Errorf("failed to create deployment watch: %v", err)
+	}
+
+	return nil
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	log := r.log.With("cluster", request.Name)
+
+	cluster := &kubermaticv1.Cluster{}
+	if err := r.Get(ctx, request.NamespacedName, cluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if cluster.Labels[kubermaticv1.WorkerNameLabelKey] != r.workerName {
+		return reconcile.Result{}, nil
+	}
+
+	if cluster.Spec.Pause || cluster.DeletionTimestamp != nil || cluster.Status.NamespaceName == "" {
+		return reconcile.Result{}, nil
+	}
+
+	// clones get the keys of their source cluster from the clone controller
+	if cluster.IsClonePending() {
+		return reconcile.Result{}, nil
+	}
+
+	result, err := r.reconcile(ctx, log, cluster)
+	if err != nil {
+		log.Errorw("Reconciling failed", zap.Error(err))
+		r.recorder.Event(cluster, corev1.EventTypeWarning, "ReconcilingError", err.Error())
+	}
+
+	if result == nil {
+		result = &reconcile.Result{}
+	}
+	return *result, err
+}
+
+// reconcile moves the encryption configuration of the apiserver one step closer to the desired
+// key and resources. Every step is only taken once the apiserver has been rolled out with the
+// configuration of the previous one:
+//
+//  1. the new key is added to the configuration, so all apiservers can decrypt resources with it
+//  2. the new key is moved to the front, so resources are encrypted with it
+//  3. all resources are rewritten and the previous keys are removed
+//
+// Disabling the encryption works the same way, with identity as the new key.
+func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	config := cluster.Spec.EncryptionConfiguration
+	enabled := config != nil && config.Enabled
+	current := cluster.Status.Encryption
+
+	if current == nil && !enabled {
+		return nil, nil
+	}
+
+	if current != nil {
+		changed, err := r.reconcileEncryptionConfiguration(ctx, cluster, current)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			log.Debug("Encryption configuration has changed, waiting for apiserver")
+			return &reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+
+		rolledOut, err := r.apiserverRolledOut(ctx, cluster)
+		if err != nil {
+			return nil, err
+		}
+		if !rolledOut {
+			log.Debug("Waiting for the apiserver to be rolled out with the current encryption configuration")
+			return nil, nil
+		}
+	} else {
+		current = &kubermaticv1.ClusterEncryptionStatus{}
+	}
+
+	desiredKey := desiredEncryptionKey(config)
+	desiredResources := desiredEncryptedResources(config)
+
+	next, message, rewrite := nextEncryptionStep(current, desiredKey, desiredResources)
+	if next == nil {
+		return nil, r.setEncryptionReady(ctx, cluster, desiredKey)
+	}
+
+	if rewrite {
+		return nil, r.rewrite(ctx, log, cluster, current, next, desiredKey)
+	}
+
+	if _, err := r.reconcileEncryptionConfiguration(ctx, cluster, next); err != nil {
+		return nil, err
+	}
+
+	oldCluster := cluster.DeepCopy()
+	cluster.Status.Encryption = next
+	kubermaticv1helper.SetClusterCondition(
+		cluster,
+		r.versions,
+		kubermaticv1.ClusterConditionEncryptionReady,
+		corev1.ConditionFalse,
+		kubermaticv1.ReasonEncryptionKeyRolloutInProgress,
+		message,
+	)
+	if err := r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+		return nil, fmt.Errorf("failed to update encryption status: %v", err)
+	}
+
+	log.Infow("Updating encryption configuration", "step", message)
+	r.recorder.Event(cluster, corev1.EventTypeNormal, "EncryptionKeyRollout", message)
+
+	return nil, nil
+}
+
+// nextEncryptionStep returns the next encryption status on the way to the desired key and
+// resources, together with a description of the step and whether all resources have to be
+// rewritten before the status can be applied. It returns nil if the desired state has been reached.
+func nextEncryptionStep(current *kubermaticv1.ClusterEncryptionStatus, desiredKey kubermaticv1.EncryptionKey, desiredResources []string) (*kubermaticv1.ClusterEncryptionStatus, string, bool) {
+	next := current.DeepCopy()
+
+	index := -1
+	for i, key := range current.Keys {
+		if key.Provider == desiredKey.Provider && key.Name == desiredKey.Name {
+			index = i
+			break
+		}
+	}
+
+	switch {
+	case index == -1:
+		if len(next.Keys) == 0 {
+			// until now the apiserver stored all resources unencrypted
+			next.Keys = []kubermaticv1.EncryptionKey{identityKey}
+		}
+		next.Keys = append(next.Keys, desiredKey)
+		return next, fmt.Sprintf("Adding %s key %s to the apiserver", desiredKey.Provider, desiredKey.Name), false
+
+	case !apiequality.Semantic.DeepEqual(current.Keys[index].KMS, desiredKey.KMS):
+		next.Keys[index] = desiredKey
+		return next, fmt.Sprintf("Updating the configuration of KMS plugin %s", desiredKey.Name), false
+
+	case index > 0:
+		next.Keys = append([]kubermaticv1.EncryptionKey{desiredKey}, append(next.Keys[:index:index], next.Keys[index+1:]...)...)
+		return next, fmt.Sprintf("Encrypting resources with %s key %s", desiredKey.Provider, desiredKey.Name), false
+
+	case len(current.Keys) > 1 || !sets.NewString(current.EncryptedResources...).Equal(sets.NewString(desiredResources...)):
+		next.Keys = []kubermaticv1.EncryptionKey{desiredKey}
+		next.EncryptedResources = desiredResources
+		return next, fmt.Sprintf("Rewriting all resources with %s key %s", desiredKey.Provider, desiredKey.Name), true
+	}
+
+	return nil, "", false
+}
+
+// rewrite rewrites all resources that are or were encrypted, so they are stored with the desired
+// key, and then removes all other keys.
+func (r *Reconciler) rewrite(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster, current, next *kubermaticv1.ClusterEncryptionStatus, desiredKey kubermaticv1.EncryptionKey) error {
+	if cluster.Status.ExtendedHealth.Apiserver != kubermaticv1.HealthStatusUp {
+		log.Debug("Waiting for apiserver")
+		return nil
+	}
+
+	_, condition := kubermaticv1helper.GetClusterCondition(cluster, kubermaticv1.ClusterConditionEncryptionReady)
+	if condition == nil || condition.Reason != kubermaticv1.ReasonEncryptionRewriteFailed {
+		if err := r.setCondition(ctx, cluster, corev1.ConditionFalse, kubermaticv1.ReasonEncryptionRewriteInProgress,
+			fmt.Sprintf("Rewriting all resources with %s key %s", desiredKey.Provider, desiredKey.Name)); err != nil {
+			return err
+		}
+	}
+
+	userClusterClient, err := r.userClusterConnectionProvider.GetClient(ctx, cluster)
+	if err != nil {
+		return fmt.Errorf("failed to get user cluster client: %v", err)
+	}
+
+	resourceNames := sets.NewString(current.EncryptedResources...).Insert(next.EncryptedResources...).List()
+	log.Infow("Rewriting resources", "resources", resourceNames)
+	if err := rewriteResources(ctx, userClusterClient, userClusterClient.RESTMapper(), resourceNames); err != nil {
+		if condErr := r.setCondition(ctx, cluster, corev1.ConditionFalse, kubermaticv1.ReasonEncryptionRewriteFailed, err.Error()); condErr != nil {
+			log.Errorw("Failed to update cluster condition", zap.Error(condErr))
+		}
+		return err
+	}
+
+	if desiredKey.Provider == kubermaticv1.EncryptionProviderIdentity {
+		// all resources are stored unencrypted again, the apiserver does not need an encryption
+		// configuration anymore
+		next = nil
+	} else if _, err := r.reconcileEncryptionConfiguration(ctx, cluster, next); err != nil {
+		return err
+	}
+
+	oldCluster := cluster.DeepCopy()
+	cluster.Status.Encryption = next
+	setEncryptionReadyCondition(cluster, r.versions, desiredKey)
+	if err := r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+		return fmt.Errorf("failed to update encryption status: %v", err)
+	}
+
+	if next == nil {
+		log.Info("All resources have been decrypted")
+		r.recorder.Event(cluster, corev1.EventTypeNormal, "EncryptionDisabled", "All resources have been decrypted")
+	} else {
+		log.Infow("All resources have been encrypted", "key", desiredKey.Name)
+		r.recorder.Eventf(cluster, corev1.EventTypeNormal, "EncryptionKeyRotated", "All resources have been rewritten with %s key %s", desiredKey.Provider, desiredKey.Name)
+	}
+
+	return nil
+}
+
+func (r *Reconciler) setEncryptionReady(ctx context.Context, cluster *kubermaticv1.Cluster, desiredKey kubermaticv1.EncryptionKey) error {
+	oldCluster := cluster.DeepCopy()
+	setEncryptionReadyCondition(cluster, r.versions, desiredKey)
+	if apiequality.Semantic.DeepEqual(oldCluster.Status, cluster.Status) {
+		return nil
+	}
+	if err := r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+		return fmt.Errorf("failed to update cluster condition: %v", err)
+	}
+	return nil
+}
+
+func setEncryptionReadyCondition(cluster *kubermaticv1.Cluster, versions kubermatic.Versions, desiredKey kubermaticv1.EncryptionKey) {
+	reason := kubermaticv1.ReasonEncryptionSucceeded
+	message := fmt.Sprintf("All resources are encrypted with %s key %s", desiredKey.Provider, desiredKey.Name)
+	if desiredKey.Provider == kubermaticv1.EncryptionProviderIdentity {
+		reason = kubermaticv1.ReasonEncryptionDisabled
+		message = "All resources are stored unencrypted"
+	}
+	kubermaticv1helper.SetClusterCondition(cluster, versions, kubermaticv1.ClusterConditionEncryptionReady, corev1.ConditionTrue, reason, message)
+}
+
+func (r *Reconciler) setCondition(ctx context.Context, cluster *kubermaticv1.Cluster, status corev1.ConditionStatus, reason, message string) error {
+	oldCluster := cluster.DeepCopy()
+	kubermaticv1helper.SetClusterCondition(cluster, r.versions, kubermaticv1.ClusterConditionEncryptionReady, status, reason, message)
+	if err := r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+		return fmt.Errorf("failed to update cluster condition: %v", err)
+	}
+	return nil
+}
+
+// desiredEncryptionKey returns the key resources of the cluster should be encrypted with.
+func desiredEncryptionKey(config *kubermaticv1.EncryptionConfiguration) kubermaticv1.EncryptionKey {
+	switch {
+	case config == nil || !config.Enabled:
+		return identityKey
+	case config.KMS != nil:
+		return kubermaticv1.EncryptionKey{
+			Provider: kubermaticv1.EncryptionProviderKMS,
+			Name:     config.KMS.Name,
+			KMS:      config.KMS.DeepCopy(),
+		}
+	}
+
+	provider := config.Provider
+	if provider == "" {
+		provider = kubermaticv1.EncryptionProviderAESCBC
+	}
+	return kubermaticv1.EncryptionKey{
+		Provider: provider,
+		Name:     fmt.Sprintf("key-%d", config.KeyGeneration),
+	}
+}
+
+// desiredEncryptedResources returns the resources of the cluster that should be encrypted.
+func desiredEncryptedResources(config *kubermaticv1.EncryptionConfiguration) []string {
+	if config == nil || !config.Enabled {
+		return nil
+	}
+	if len(config.Resources) == 0 {
+		return defaultEncryptedResources
+	}
+	return sets.NewString(config.Resources...).List()
+}
+
+// reconcileEncryptionConfiguration makes sure the keys of the encryption status exist and renders
+// the encryption configuration of the apiserver for it. It returns whether the configuration changed.
+func (r *Reconciler) reconcileEncryptionConfiguration(ctx context.Context, cluster *kubermaticv1.Cluster, encryption *kubermaticv1.ClusterEncryptionStatus) (bool, error) {
+	keySecrets, err := r.reconcileKeys(ctx, cluster, encryption.Keys)
+	if err != nil {
+		return false, err
+	}
+
+	encryptedResources := desiredEncryptedResources(cluster.Spec.EncryptionConfiguration)
+	decryptedResources := sets.NewString(encryption.EncryptedResources...).Delete(encryptedResources...).List()
+
+	config, err := apiserver.EncryptionConfiguration(encryption.Keys, encryptedResources, decryptedResources, keySecrets)
+	if err != nil {
+		return false, fmt.Errorf("failed to render encryption configuration: %v", err)
+	}
+
+	return r.reconcileSecret(ctx, cluster, resources.EncryptionConfigurationSecretName, map[string][]byte{
+		resources.EncryptionConfigurationSecretKey: config,
+	})
+}
+
+// reconcileKeys generates the keys that do not exist yet and returns the secrets of all keys.
+func (r *Reconciler) reconcileKeys(ctx context.Context, cluster *kubermaticv1.Cluster, keys []kubermaticv1.EncryptionKey) (map[string][]byte, error) {
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: resources.EncryptionKeysSecretName}, secret); err != nil && !kerrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get encryption keys: %v", err)
+	}
+
+	data := map[string][]byte{}
+	for k, v := range secret.Data {
+		data[k] = v
+	}
+
+	for _, key := range keys {
+		if key.Provider != kubermaticv1.EncryptionProviderAESCBC && key.Provider != kubermaticv1.EncryptionProviderSecretbox {
+			continue
+		}
+		if _, exists := data[apiserver.EncryptionKeySecretKey(key)]; exists {
+			continue
+		}
+		secret := make([]byte, keySize)
+		if _, err := cryptorand.Read(secret); err != nil {
+			return nil, fmt.Errorf("failed to generate key: %v", err)
+		}
+		data[apiserver.EncryptionKeySecretKey(key)] = secret
+	}
+
+	if _, err := r.reconcileSecret(ctx, cluster, resources.EncryptionKeysSecretName, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// reconcileSecret creates or updates the Secret in the cluster namespace with the given data and
+// returns whether it changed.
+func (r *Reconciler) reconcileSecret(ctx context.Context, cluster *kubermaticv1.Cluster, name string, data map[string][]byte) (bool, error) {
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: name}, secret)
+	if kerrors.IsNotFound(err) {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       cluster.Status.NamespaceName,
+				OwnerReferences: []metav1.OwnerReference{resources.GetClusterRef(cluster)},
+			},
+			Data: data,
+		}
+		if err := r.Create(ctx, secret); err != nil {
+			return false, fmt.Errorf("failed to create Secret %s: %v", name, err)
+		}
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get Secret %s: %v", name, err)
+	}
+
+	if secretDataEqual(secret.Data, data) {
+		return false, nil
+	}
+
+	oldSecret := secret.DeepCopy()
+	secret.Data = data
+	if err := r.Patch(ctx, secret, ctrlruntimeclient.MergeFrom(oldSecret)); err != nil {
+		return false, fmt.Errorf("failed to update Secret %s: %v", name, err)
+	}
+	return true, nil
+}
+
+func secretDataEqual(a, b map[string][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if !bytes.Equal(v, b[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+// apiserverRolledOut checks whether all apiserver pods use the current encryption configuration.
+func (r *Reconciler) apiserverRolledOut(ctx context.Context, cluster *kubermaticv1.Cluster) (bool, error) {
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: resources.EncryptionConfigurationSecretName}, secret); err != nil {
+		return false, fmt.Errorf("failed to get encryption configuration: %v", err)
+	}
+
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: resources.ApiserverDeploymentName}, deployment); err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get apiserver deployment: %v", err)
+	}
+
+	revisionLabel := fmt.Sprintf("%s-secret-revision", resources.EncryptionConfigurationSecretName)
+	if deployment.Spec.Template.Labels[revisionLabel] != secret.ResourceVersion {
+		return false, nil
+	}
+
+	// a rollout that exceeded its progress deadline is waited for as well
+	complete, err := kubernetes.IsDeploymentRolloutComplete(deployment, 0)
+	return complete && err == nil, nil
+}
+
+// rewriteResources updates all objects of the given resources in the user cluster without changing
+// them, which makes the apiserver store them with its current encryption configuration.
+func rewriteResources(ctx context.Context, client ctrlruntimeclient.Client, mapper meta.RESTMapper, resourceNames []string) error {
+	for _, resourceName := range resourceNames {
+		gvk, err := mapper.KindFor(schema.ParseGroupResource(resourceName).WithVersion(""))
+		if err != nil {
+			return fmt.Errorf("failed to find kind of resource %s: %v", resourceName, err)
+		}
+
+		listOpts := &ctrlruntimeclient.ListOptions{Limit: rewritePageSize}
+		for {
+			list := &unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+			if err := client.List(ctx, list, listOpts); err != nil {
+				return fmt.Errorf("failed to list %s: %v", resourceName, err)
+			}
+
+			for i := range list.Items {
+				obj := &list.Items[i]
+				// objects that were changed or deleted in the meantime have been rewritten anyway
+				if err := client.Update(ctx, obj); err != nil && !kerrors.IsConflict(err) && !kerrors.IsNotFound(err) {
+					return fmt.Errorf("failed to rewrite %s %s: %v", resourceName, ctrlruntimeclient.ObjectKeyFromObject(obj), err)
+				}
+			}
+
+			listOpts.Continue = list.GetContinue()
+			if listOpts.Continue == "" {
+				break
+			}
+		}
+	}
+
+	return nil
+}
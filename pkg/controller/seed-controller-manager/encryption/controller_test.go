@@ -0,0 +1,376 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	clusterclient "k8c.io/kubermatic/v2/pkg/cluster/client"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1/helper"
+	"k8c.io/kubermatic/v2/pkg/resources"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	clusterName      = "testcluster"
+	clusterNamespace = "cluster-" + clusterName
+	workerName       = ""
+)
+
+func genCluster(config *kubermaticv1.EncryptionConfiguration) *kubermaticv1.Cluster {
+	return &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: clusterName,
+		},
+		Spec: kubermaticv1.ClusterSpec{
+			EncryptionConfiguration: config,
+		},
+		Status: kubermaticv1.ClusterStatus{
+			NamespaceName: clusterNamespace,
+			ExtendedHealth: kubermaticv1.ExtendedClusterHealth{
+				Apiserver: kubermaticv1.HealthStatusUp,
+			},
+		},
+	}
+}
+
+func genApiserverDeployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resources.ApiserverDeploymentName,
+			Namespace: clusterNamespace,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: pointer.Int32Ptr(1),
+		},
+		Status: appsv1.DeploymentStatus{
+			Replicas:          1,
+			UpdatedReplicas:   1,
+			AvailableReplicas: 1,
+		},
+	}
+}
+
+// rolloutApiserver updates the apiserver deployment like the kubernetes controller does once the
+// encryption configuration changed.
+func rolloutApiserver(ctx context.Context, client ctrlruntimeclient.Client) error {
+	secret := &corev1.Secret{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: clusterNamespace, Name: resources.EncryptionConfigurationSecretName}, secret); err != nil {
+		return ctrlruntimeclient.IgnoreNotFound(err)
+	}
+
+	deployment := &appsv1.Deployment{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: clusterNamespace, Name: resources.ApiserverDeploymentName}, deployment); err != nil {
+		return err
+	}
+	deployment.Spec.Template.Labels = map[string]string{
+		fmt.Sprintf("%s-secret-revision", resources.EncryptionConfigurationSecretName): secret.ResourceVersion,
+	}
+	return client.Update(ctx, deployment)
+}
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+
+	seedClient := fakectrlruntimeclient.
+		NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(genCluster(&kubermaticv1.EncryptionConfiguration{Enabled: true}), genApiserverDeployment()).
+		Build()
+
+	userClusterClient := &restMapperClient{
+		Client: fakectrlruntimeclient.
+			NewClientBuilder().
+			WithScheme(scheme.Scheme).
+			WithObjects(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "token",
+					Namespace: metav1.NamespaceSystem,
+				},
+			}).
+			Build(),
+		mapper: testRESTMapper(),
+	}
+
+	r := &Reconciler{
+		Client:                        seedClient,
+		workerName:                    workerName,
+		recorder:                      &record.FakeRecorder{},
+		userClusterConnectionProvider: &fakeClientProvider{client: userClusterClient},
+		log:                           zap.NewNop().Sugar(),
+	}
+
+	// reconcileUntilReady reconciles the cluster and rolls out the apiserver until the encryption is
+	// ready, and returns the encryption configurations the apiserver has been rolled out with.
+	reconcileUntilReady := func(t *testing.T) []string {
+		var configs []string
+		for i := 0; i < 10; i++ {
+			if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: clusterName}}); err != nil {
+				t.Fatalf("reconciling failed: %v", err)
+			}
+
+			cluster := &kubermaticv1.Cluster{}
+			if err := seedClient.Get(ctx, types.NamespacedName{Name: clusterName}, cluster); err != nil {
+				t.Fatalf("failed to get cluster: %v", err)
+			}
+
+			secret := &corev1.Secret{}
+			if err := seedClient.Get(ctx, types.NamespacedName{Namespace: clusterNamespace, Name: resources.EncryptionConfigurationSecretName}, secret); err == nil {
+				config := string(secret.Data[resources.EncryptionConfigurationSecretKey])
+				if len(configs) == 0 || configs[len(configs)-1] != config {
+					configs = append(configs, config)
+				}
+			}
+
+			if encryptionDone(cluster) {
+				return configs
+			}
+
+			if err := rolloutApiserver(ctx, seedClient); err != nil {
+				t.Fatalf("failed to roll out apiserver: %v", err)
+			}
+		}
+		t.Fatal("encryption did not become ready")
+		return nil
+	}
+
+	getCluster := func(t *testing.T) *kubermaticv1.Cluster {
+		cluster := &kubermaticv1.Cluster{}
+		if err := seedClient.Get(ctx, types.NamespacedName{Name: clusterName}, cluster); err != nil {
+			t.Fatalf("failed to get cluster: %v", err)
+		}
+		return cluster
+	}
+
+	t.Run("enabling encryption", func(t *testing.T) {
+		configs := reconcileUntilReady(t)
+		// identity and the new key, then the new key first; removing identity after the rewrite does
+		// not change the configuration, as unencrypted resources can always be read
+		if len(configs) != 2 {
+			t.Fatalf("expected 2 encryption configurations to be rolled out, got %d", len(configs))
+		}
+		if !strings.HasPrefix(providersOf(configs[0]), "identity,aescbc") {
+			t.Errorf("expected new key to be added after identity, got providers %s", providersOf(configs[0]))
+		}
+		if !strings.HasPrefix(providersOf(configs[1]), "aescbc,identity") {
+			t.Errorf("expected new key to be moved to the front, got providers %s", providersOf(configs[1]))
+		}
+
+		cluster := getCluster(t)
+		keys := cluster.Status.Encryption.Keys
+		if len(keys) != 1 || keys[0].Provider != kubermaticv1.EncryptionProviderAESCBC || keys[0].Name != "key-0" {
+			t.Errorf("expected only aescbc key key-0, got %+v", keys)
+		}
+	})
+
+	t.Run("rotating the key", func(t *testing.T) {
+		cluster := getCluster(t)
+		oldCluster := cluster.DeepCopy()
+		cluster.Spec.EncryptionConfiguration.KeyGeneration = 1
+		if err := seedClient.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+			t.Fatalf("failed to update cluster: %v", err)
+		}
+
+		reconcileUntilReady(t)
+
+		keys := getCluster(t).Status.Encryption.Keys
+		if len(keys) != 1 || keys[0].Name != "key-1" {
+			t.Errorf("expected only key key-1, got %+v", keys)
+		}
+
+		secret := &corev1.Secret{}
+		if err := seedClient.Get(ctx, types.NamespacedName{Namespace: clusterNamespace, Name: resources.EncryptionKeysSecretName}, secret); err != nil {
+			t.Fatalf("failed to get encryption keys: %v", err)
+		}
+		if len(secret.Data["aescbc-key-1"]) != keySize {
+			t.Errorf("expected a %d byte key to be generated, got %d bytes", keySize, len(secret.Data["aescbc-key-1"]))
+		}
+	})
+
+	t.Run("disabling encryption", func(t *testing.T) {
+		cluster := getCluster(t)
+		oldCluster := cluster.DeepCopy()
+		cluster.Spec.EncryptionConfiguration.Enabled = false
+		if err := seedClient.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+			t.Fatalf("failed to update cluster: %v", err)
+		}
+
+		reconcileUntilReady(t)
+
+		cluster = getCluster(t)
+		if cluster.Status.Encryption != nil {
+			t.Errorf("expected encryption status to be removed, got %+v", cluster.Status.Encryption)
+		}
+		_, condition := kubermaticv1helper.GetClusterCondition(cluster, kubermaticv1.ClusterConditionEncryptionReady)
+		if condition.Reason != kubermaticv1.ReasonEncryptionDisabled {
+			t.Errorf("expected condition reason %q, got %q", kubermaticv1.ReasonEncryptionDisabled, condition.Reason)
+		}
+	})
+}
+
+func TestNextEncryptionStep(t *testing.T) {
+	aescbcKey := kubermaticv1.EncryptionKey{Provider: kubermaticv1.EncryptionProviderAESCBC, Name: "key-1"}
+	oldKey := kubermaticv1.EncryptionKey{Provider: kubermaticv1.EncryptionProviderAESCBC, Name: "key-0"}
+	kmsKey := kubermaticv1.EncryptionKey{
+		Provider: kubermaticv1.EncryptionProviderKMS,
+		Name:     "vault",
+		KMS: &kubermaticv1.KMSEncryptionConfiguration{
+			Name:     "vault",
+			Endpoint: "unix:///var/run/kmsplugin/vault.sock",
+		},
+	}
+
+	testCases := []struct {
+		name            string
+		current         kubermaticv1.ClusterEncryptionStatus
+		desiredKey      kubermaticv1.EncryptionKey
+		expectedKeys    []kubermaticv1.EncryptionKey
+		expectedRewrite bool
+	}{
+		{
+			name:         "key is added after identity",
+			desiredKey:   aescbcKey,
+			expectedKeys: []kubermaticv1.EncryptionKey{identityKey, aescbcKey},
+		},
+		{
+			name:         "key is added after the previous key",
+			current:      kubermaticv1.ClusterEncryptionStatus{Keys: []kubermaticv1.EncryptionKey{oldKey}, EncryptedResources: []string{"secrets"}},
+			desiredKey:   aescbcKey,
+			expectedKeys: []kubermaticv1.EncryptionKey{oldKey, aescbcKey},
+		},
+		{
+			name:         "key is moved to the front",
+			current:      kubermaticv1.ClusterEncryptionStatus{Keys: []kubermaticv1.EncryptionKey{oldKey, aescbcKey}, EncryptedResources: []string{"secrets"}},
+			desiredKey:   aescbcKey,
+			expectedKeys: []kubermaticv1.EncryptionKey{aescbcKey, oldKey},
+		},
+		{
+			name:            "resources are rewritten to remove the previous key",
+			current:         kubermaticv1.ClusterEncryptionStatus{Keys: []kubermaticv1.EncryptionKey{aescbcKey, oldKey}, EncryptedResources: []string{"secrets"}},
+			desiredKey:      aescbcKey,
+			expectedKeys:    []kubermaticv1.EncryptionKey{aescbcKey},
+			expectedRewrite: true,
+		},
+		{
+			name: "KMS plugin configuration is updated",
+			current: kubermaticv1.ClusterEncryptionStatus{
+				Keys: []kubermaticv1.EncryptionKey{{
+					Provider: kubermaticv1.EncryptionProviderKMS,
+					Name:     "vault",
+					KMS:      &kubermaticv1.KMSEncryptionConfiguration{Name: "vault", Endpoint: "unix:///var/run/kmsplugin/old.sock"},
+				}},
+				EncryptedResources: []string{"secrets"},
+			},
+			desiredKey:   kmsKey,
+			expectedKeys: []kubermaticv1.EncryptionKey{kmsKey},
+		},
+		{
+			name:       "nothing to do",
+			current:    kubermaticv1.ClusterEncryptionStatus{Keys: []kubermaticv1.EncryptionKey{aescbcKey}, EncryptedResources: []string{"secrets"}},
+			desiredKey: aescbcKey,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			next, _, rewrite := nextEncryptionStep(&test.current, test.desiredKey, []string{"secrets"})
+
+			var keys []kubermaticv1.EncryptionKey
+			if next != nil {
+				keys = next.Keys
+			}
+			if fmt.Sprintf("%+v", keys) != fmt.Sprintf("%+v", test.expectedKeys) {
+				t.Errorf("expected keys %+v, got %+v", test.expectedKeys, keys)
+			}
+			if rewrite != test.expectedRewrite {
+				t.Errorf("expected rewrite to be %v, got %v", test.expectedRewrite, rewrite)
+			}
+		})
+	}
+}
+
+// encryptionDone tells if the encryption status of the cluster matches its spec and is ready.
+func encryptionDone(cluster *kubermaticv1.Cluster) bool {
+	_, condition := kubermaticv1helper.GetClusterCondition(cluster, kubermaticv1.ClusterConditionEncryptionReady)
+	if condition == nil || condition.Status != corev1.ConditionTrue {
+		return false
+	}
+
+	config := cluster.Spec.EncryptionConfiguration
+	if config == nil || !config.Enabled {
+		return cluster.Status.Encryption == nil
+	}
+	if cluster.Status.Encryption == nil {
+		return false
+	}
+	next, _, _ := nextEncryptionStep(cluster.Status.Encryption, desiredEncryptionKey(config), desiredEncryptedResources(config))
+	return next == nil
+}
+
+// providersOf returns the providers of the first resource group of the encryption configuration.
+func providersOf(config string) string {
+	var providers []string
+	for _, line := range strings.Split(config, "\n") {
+		for _, provider := range []string{"aescbc", "secretbox", "kms", "identity"} {
+			if strings.TrimSpace(line) == "- "+provider+": {}" || strings.TrimSpace(line) == "- "+provider+":" {
+				providers = append(providers, provider)
+			}
+		}
+	}
+	return strings.Join(providers, ",")
+}
+
+func testRESTMapper() meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Secret"), meta.RESTScopeNamespace)
+	return mapper
+}
+
+// restMapperClient is a fake client with a RESTMapper, which the fake client does not provide.
+type restMapperClient struct {
+	ctrlruntimeclient.Client
+	mapper meta.RESTMapper
+}
+
+func (c *restMapperClient) RESTMapper() meta.RESTMapper {
+	return c.mapper
+}
+
+type fakeClientProvider struct {
+	client ctrlruntimeclient.Client
+}
+
+func (f *fakeClientProvider) GetClient(ctx context.Context, c *kubermaticv1.Cluster, options ...clusterclient.ConfigOption) (ctrlruntimeclient.Client, error) {
+	return f.client, nil
+}
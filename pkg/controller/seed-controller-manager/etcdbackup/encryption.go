@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdbackup
+
+import (
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	encryptionKeysVolumeName = "encryption-keys"
+	encryptionKeysMountPath  = "/etc/backup-encryption"
+)
+
+func isEncryptionEnabled(destination *kubermaticv1.BackupDestination) bool {
+	return destination != nil && destination.Encryption != nil && destination.Encryption.KeySecret != nil
+}
+
+// encryptionKeysVolume mounts all keys of the destination, so that backups encrypted with
+// previous keys can still be read.
+func encryptionKeysVolume(destination *kubermaticv1.BackupDestination) corev1.Volume {
+	return corev1.Volume{
+		Name: encryptionKeysVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: destination.Encryption.KeySecret.Name,
+			},
+		},
+	}
+}
+
+func encryptionKeysVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      encryptionKeysVolumeName,
+		MountPath: encryptionKeysMountPath,
+		ReadOnly:  true,
+	}
+}
+
+// backupEncrypterContainer encrypts the snapshot in the shared volume in place, so that
+// the store container only ever sees the encrypted snapshot.
+func (r *Reconciler) backupEncrypterContainer(destination *kubermaticv1.BackupDestination) corev1.Container {
+	return corev1.Container{
+		Name:  "backup-encrypter",
+		Image: r.etcdLauncherImageWithTag(),
+		Command: []string{
+			"/etcd-backup-encrypter",
+			"-file", "/backup/snapshot.db",
+			"-encryption-keys", encryptionKeysMountPath,
+			"-key-id", destination.Encryption.ActiveKey,
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      SharedVolumeName,
+				MountPath: "/backup",
+			},
+			encryptionKeysVolumeMount(),
+		},
+	}
+}
@@ -122,12 +122,14 @@ type Reconciler struct {
 	// backupContainerImage holds the image used for creating the etcd backup
 	// It must be configurable to cover offline use cases
 	backupContainerImage string
-	clock                clock.Clock
-	randStringGenerator  func() string
-	caBundle             resources.CABundle
-	recorder             record.EventRecorder
-	versions             kubermatic.Versions
-	seedGetter           provider.SeedGetter
+	// etcdLauncherImage holds the image containing the etcd revision shipper used for continuous backups
+	etcdLauncherImage   string
+	clock               clock.Clock
+	randStringGenerator func() string
+	caBundle            resources.CABundle
+	recorder            record.EventRecorder
+	versions            kubermatic.Versions
+	seedGetter          provider.SeedGetter
 }
 
 // Add creates a new Backup controller that is responsible for
@@ -141,6 +143,7 @@ func Add(
 	deleteContainer *corev1.Container,
 	cleanupContainer *corev1.Container,
 	backupContainerImage string,
+	etcdLauncherImage string,
 	versions kubermatic.Versions,
 	caBundle resources.CABundle,
 	seedGetter provider.SeedGetter,
@@ -161,6 +164,7 @@ func Add(
 		deleteContainer:      deleteContainer,
 		cleanupContainer:     cleanupContainer,
 		backupContainerImage: backupContainerImage,
+		etcdLauncherImage:    etcdLauncherImage,
 		recorder:             mgr.GetEventRecorderFor(ControllerName),
 		versions:             versions,
 		clock:                &clock.RealClock{},
@@ -250,19 +254,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 }
 
 func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster, seed *kubermaticv1.Seed) (*reconcile.Result, error) {
-	var destination *kubermaticv1.BackupDestination
-	if backupConfig.Spec.Destination != "" {
-		if seed.Spec.EtcdBackupRestore == nil {
-			return nil, errors.Errorf("can't find backup destination %q in Seed %q", backupConfig.Spec.Destination, seed.Name)
-		}
-		var ok bool
-		destination, ok = seed.Spec.EtcdBackupRestore.Destinations[backupConfig.Spec.Destination]
-		if !ok {
-			return nil, errors.Errorf("can't find backup destination %q in Seed %q", backupConfig.Spec.Destination, seed.Name)
-		}
-		if destination.Credentials == nil {
-			return nil, errors.Errorf("credentials not set for backup destination %q in Seed %q", backupConfig.Spec.Destination, seed.Name)
-		}
+	destination, err := getDestination(seed, backupConfig.Spec.Destination)
+	if err != nil {
+		return nil, err
+	}
+
+	replicaDestinations, err := getReplicaDestinations(backupConfig, seed)
+	if err != nil {
+		return nil, err
 	}
 
 	if err := r.ensureSecrets(ctx, cluster); err != nil {
@@ -273,8 +272,11 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, back
 		return nil, errors.Wrap(err, "failed to create backup configmaps")
 	}
 
+	if err := r.ensureRevisionShipper(ctx, backupConfig, cluster, destination); err != nil {
+		return nil, errors.Wrap(err, "failed to reconcile revision shipper")
+	}
+
 	var nextReconcile, totalReconcile *reconcile.Result
-	var err error
 	errorReconcile := &reconcile.Result{RequeueAfter: 1 * time.Minute}
 
 	if nextReconcile, err = r.ensurePendingBackupIsScheduled(ctx, backupConfig, cluster); err != nil {
@@ -289,6 +291,18 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, back
 
 	totalReconcile = minReconcile(totalReconcile, nextReconcile)
 
+	if nextReconcile, err = r.updateBackupVerifications(ctx, backupConfig, cluster, destination); err != nil {
+		return errorReconcile, errors.Wrap(err, "failed to update backup verifications")
+	}
+
+	totalReconcile = minReconcile(totalReconcile, nextReconcile)
+
+	if nextReconcile, err = r.updateBackupReplications(ctx, backupConfig, cluster, destination, replicaDestinations); err != nil {
+		return errorReconcile, errors.Wrap(err, "failed to update backup replications")
+	}
+
+	totalReconcile = minReconcile(totalReconcile, nextReconcile)
+
 	if nextReconcile, err = r.startPendingBackupDeleteJobs(ctx, backupConfig, cluster, destination); err != nil {
 		return errorReconcile, errors.Wrap(err, "failed to start pending backup delete jobs")
 	}
@@ -301,13 +315,25 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, back
 
 	totalReconcile = minReconcile(totalReconcile, nextReconcile)
 
+	if nextReconcile, err = r.startPendingReplicaDeleteJobs(ctx, backupConfig, cluster, replicaDestinations); err != nil {
+		return errorReconcile, errors.Wrap(err, "failed to start pending replica delete jobs")
+	}
+
+	totalReconcile = minReconcile(totalReconcile, nextReconcile)
+
+	if nextReconcile, err = r.updateRunningReplicaDeleteJobs(ctx, backupConfig, cluster, replicaDestinations); err != nil {
+		return errorReconcile, errors.Wrap(err, "failed to update running replica delete jobs")
+	}
+
+	totalReconcile = minReconcile(totalReconcile, nextReconcile)
+
 	if nextReconcile, err = r.deleteFinishedBackupJobs(ctx, backupConfig, cluster); err != nil {
 		return errorReconcile, errors.Wrap(err, "failed to delete finished backup jobs")
 	}
 
 	totalReconcile = minReconcile(totalReconcile, nextReconcile)
 
-	if nextReconcile, err = r.handleFinalization(ctx, backupConfig, cluster, destination); err != nil {
+	if nextReconcile, err = r.handleFinalization(ctx, backupConfig, cluster, destination, replicaDestinations); err != nil {
 		return errorReconcile, errors.Wrap(err, "failed to delete finished backup jobs")
 	}
 
@@ -316,6 +342,28 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, back
 	return totalReconcile, nil
 }
 
+// getDestination returns the backup destination with the given name, or nil if the legacy destination
+// configured in Seed.Spec.BackupRestore should be used.
+func getDestination(seed *kubermaticv1.Seed, name string) (*kubermaticv1.BackupDestination, error) {
+	if name == "" {
+		return nil, nil
+	}
+	if seed.Spec.EtcdBackupRestore == nil {
+		return nil, errors.Errorf("can't find backup destination %q in Seed %q", name, seed.Name)
+	}
+	destination, ok := seed.Spec.EtcdBackupRestore.Destinations[name]
+	if !ok {
+		return nil, errors.Errorf("can't find backup destination %q in Seed %q", name, seed.Name)
+	}
+	if destination.Credentials == nil && destination.GetType() != kubermaticv1.BackupDestinationTypeFilesystem {
+		return nil, errors.Errorf("credentials not set for backup destination %q in Seed %q", name, seed.Name)
+	}
+	if err := validateDestination(destination); err != nil {
+		return nil, errors.Wrapf(err, "invalid backup destination %q in Seed %q", name, seed.Name)
+	}
+	return destination, nil
+}
+
 func minReconcile(reconciles ...*reconcile.Result) *reconcile.Result {
 	var result *reconcile.Result
 	for _, r := range reconciles {
@@ -334,7 +382,7 @@ func (r *Reconciler) ensurePendingBackupIsScheduled(ctx context.Context, backupC
 		return nil, nil
 	}
 
-	if len(backupConfig.Status.CurrentBackups) > 2*backupConfig.GetKeptBackupsCount() {
+	if len(backupConfig.Status.CurrentBackups) > 2*maxKeptBackups(backupConfig) {
 		// keeping track of many backups already, don't schedule new ones.
 		if r.setBackupConfigCondition(
 			backupConfig,
@@ -516,7 +564,7 @@ func (r *Reconciler) startPendingBackupJobs(ctx context.Context, backupConfig *k
 	return returnReconcile, nil
 }
 
-// create any backup delete jobs that can be created, i.e. for all completed backups older than the last backupConfig.GetKeptBackupsCount() ones.
+// create any backup delete jobs that can be created, i.e. for all failed backups and all completed backups not selected by the backupConfig's retention policy.
 func (r *Reconciler) startPendingBackupDeleteJobs(ctx context.Context, backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster,
 	destination *kubermaticv1.BackupDestination) (*reconcile.Result, error) {
 	// one-shot backups are not deleted until their backupConfig is deleted
@@ -525,11 +573,6 @@ func (r *Reconciler) startPendingBackupDeleteJobs(ctx context.Context, backupCon
 	}
 
 	var backupsToDelete []*kubermaticv1.BackupStatus
-	keepCount := backupConfig.GetKeptBackupsCount()
-	if backupConfig.DeletionTimestamp != nil {
-		keepCount = 0
-	}
-	kept := 0
 	runningDeleteJobsCount := 0
 	for i := len(backupConfig.Status.CurrentBackups) - 1; i >= 0; i-- {
 		backup := &backupConfig.Status.CurrentBackups[i]
@@ -538,13 +581,9 @@ func (r *Reconciler) startPendingBackupDeleteJobs(ctx context.Context, backupCon
 		}
 		if backup.BackupPhase == kubermaticv1.BackupStatusPhaseFailed && backup.DeletePhase == "" {
 			backupsToDelete = append(backupsToDelete, backup)
-		} else if backup.BackupPhase == kubermaticv1.BackupStatusPhaseCompleted {
-			kept++
-			if kept > keepCount && backup.DeletePhase == "" {
-				backupsToDelete = append(backupsToDelete, backup)
-			}
 		}
 	}
+	backupsToDelete = append(backupsToDelete, backupsToRetire(backupConfig)...)
 
 	modified := false
 	for _, backup := range backupsToDelete {
@@ -570,7 +609,7 @@ func (r *Reconciler) startPendingBackupDeleteJobs(ctx context.Context, backupCon
 func (r *Reconciler) createBackupDeleteJob(ctx context.Context, backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster, backup *kubermaticv1.BackupStatus,
 	destination *kubermaticv1.BackupDestination) error {
 	if r.deleteContainer != nil {
-		job := r.backupDeleteJob(backupConfig, cluster, backup, destination)
+		job := r.backupDeleteJob(backupConfig, cluster, backup.BackupName, backup.DeleteJobName, destination)
 		if err := r.Create(ctx, job); err != nil && !kerrors.IsAlreadyExists(err) {
 			return errors.Wrapf(err, "error creating delete job for backup %s", backup.BackupName)
 		}
@@ -704,6 +743,14 @@ func (r *Reconciler) deleteFinishedBackupJobs(ctx context.Context, backupConfig
 			}
 		}
 
+		if backupJobDeleted {
+			verificationJobDeleted, err := r.deleteVerificationJob(ctx, backupConfig, &backup)
+			if err != nil {
+				return nil, err
+			}
+			backupJobDeleted = verificationJobDeleted
+		}
+
 		deleteJobDeleted := false
 		if backup.DeleteFinishedTime != nil {
 			var retentionTime time.Duration
@@ -739,7 +786,13 @@ func (r *Reconciler) deleteFinishedBackupJobs(ctx context.Context, backupConfig
 			}
 		}
 
-		if backupJobDeleted && deleteJobDeleted {
+		replicasDeleted, nextReconcile, err := r.deleteFinishedReplicaJobs(ctx, backupConfig, &backup)
+		if err != nil {
+			return nil, err
+		}
+		returnReconcile = minReconcile(returnReconcile, nextReconcile)
+
+		if backupJobDeleted && deleteJobDeleted && replicasDeleted {
 			// don't add backup to newBackups, which ends up deleting it from backupConfig.Status.CurrentBackups below
 			modified = true
 			continue
@@ -760,7 +813,7 @@ func (r *Reconciler) deleteFinishedBackupJobs(ctx context.Context, backupConfig
 }
 
 func (r *Reconciler) handleFinalization(ctx context.Context, backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster,
-	destination *kubermaticv1.BackupDestination) (*reconcile.Result, error) {
+	destination *kubermaticv1.BackupDestination, replicaDestinations map[string]*kubermaticv1.BackupDestination) (*reconcile.Result, error) {
 	if backupConfig.DeletionTimestamp == nil || len(backupConfig.Status.CurrentBackups) > 0 {
 		return nil, nil
 	}
@@ -768,49 +821,27 @@ func (r *Reconciler) handleFinalization(ctx context.Context, backupConfig *kuber
 	canRemoveFinalizer := true
 
 	if r.cleanupContainer != nil && r.deleteContainer == nil {
-		// Need to run, track and delete a cleanup job
-
-		cleanupJobName := fmt.Sprintf("%s-backup-%s-cleanup", cluster.Name, backupConfig.Name)
+		// Need to run, track and delete a cleanup job for every destination
 
-		if !backupConfig.Status.CleanupRunning {
-			// job not started before. start it
-			cleanupJob := r.cleanupJob(backupConfig, cluster, cleanupJobName, destination)
-			if err := r.Create(ctx, cleanupJob); err != nil && !kerrors.IsAlreadyExists(err) {
-				return nil, errors.Wrapf(err, "error creating cleanup job (%v)", cleanupJobName)
+		cleanupJobs := map[string]*kubermaticv1.BackupDestination{
+			fmt.Sprintf("%s-backup-%s-cleanup", cluster.Name, backupConfig.Name): destination,
+		}
+		for i, replica := range backupConfig.Spec.Replicas {
+			if replicaDestination := replicaDestinations[replica.Destination]; replicaDestination != nil {
+				cleanupJobs[fmt.Sprintf("%s-backup-%s-cleanup-replica-%d", cluster.Name, backupConfig.Name, i)] = replicaDestination
 			}
-			backupConfig.Status.CleanupRunning = true
-			canRemoveFinalizer = false
-
-		} else {
-			// job was started before. Re-acquire it and check completion status
-			cleanupJob := &batchv1.Job{}
-			err := r.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: cleanupJobName}, cleanupJob)
-			if err == nil {
-				jobSucceeded := getJobConditionIfTrue(cleanupJob, batchv1.JobComplete) != nil
-				jobFailed := getJobConditionIfTrue(cleanupJob, batchv1.JobFailed) != nil
-				if jobSucceeded || jobFailed {
-					// job completed either way. delete it.
-					if err := r.Delete(ctx, cleanupJob, ctrlruntimeclient.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !kerrors.IsNotFound(err) {
-						return nil, errors.Wrapf(err, "failed to delete finished cleanup job %s", cleanupJobName)
-					}
-					if jobFailed {
-						// job failed, restart it.
-						canRemoveFinalizer = false
-						cleanupJob := r.cleanupJob(backupConfig, cluster, cleanupJobName, destination)
-						if err := r.Create(ctx, cleanupJob); err != nil && !kerrors.IsAlreadyExists(err) {
-							return nil, errors.Wrapf(err, "error recreating cleanup job (%v)", cleanupJobName)
-						}
-					}
-				} else {
-					// job still running
-					canRemoveFinalizer = false
-				}
+		}
 
-			} else if !kerrors.IsNotFound(err) {
-				return nil, errors.Wrapf(err, "error getting cleanup job previously started (%v)", cleanupJobName)
+		for cleanupJobName, cleanupDestination := range cleanupJobs {
+			finished, err := r.reconcileCleanupJob(ctx, backupConfig, cluster, cleanupJobName, cleanupDestination)
+			if err != nil {
+				return nil, err
+			}
+			if !finished {
+				canRemoveFinalizer = false
 			}
-			// err IsNotFound or job deleted successfully => fall through
 		}
+		backupConfig.Status.CleanupRunning = true
 	}
 
 	returnReconcile := &reconcile.Result{RequeueAfter: 30 * time.Second}
@@ -827,52 +858,56 @@ func (r *Reconciler) handleFinalization(ctx context.Context, backupConfig *kuber
 	return returnReconcile, nil
 }
 
-func (r *Reconciler) backupJob(backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster, backupStatus *kubermaticv1.BackupStatus,
-	destination *kubermaticv1.BackupDestination) *batchv1.Job {
-	storeContainer := r.storeContainer.DeepCopy()
+// reconcileCleanupJob starts the cleanup job with the given name if cleanup has not been started yet,
+// and restarts it if it failed. It returns true once the job has completed and has been deleted.
+func (r *Reconciler) reconcileCleanupJob(ctx context.Context, backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster, cleanupJobName string,
+	destination *kubermaticv1.BackupDestination) (bool, error) {
+	if !backupConfig.Status.CleanupRunning {
+		// job not started before. start it
+		cleanupJob := r.cleanupJob(backupConfig, cluster, cleanupJobName, destination)
+		if err := r.Create(ctx, cleanupJob); err != nil && !kerrors.IsAlreadyExists(err) {
+			return false, errors.Wrapf(err, "error creating cleanup job (%v)", cleanupJobName)
+		}
+		return false, nil
+	}
 
-	// If destination is set, we need to set the credentials and backup bucket details to match the destination
-	if destination != nil {
-		storeContainer.Env = setEnvVar(storeContainer.Env, genSecretEnvVar(accessKeyIdEnvVarKey, accessKeyIdEnvVarKey, destination))
-		storeContainer.Env = setEnvVar(storeContainer.Env, genSecretEnvVar(secretAccessKeyEnvVarKey, secretAccessKeyEnvVarKey, destination))
-		storeContainer.Env = setEnvVar(storeContainer.Env, corev1.EnvVar{
-			Name:  bucketNameEnvVarKey,
-			Value: destination.BucketName,
-		})
-		storeContainer.Env = setEnvVar(storeContainer.Env, corev1.EnvVar{
-			Name:  backupEndpointEnvVarKey,
-			Value: destination.Endpoint,
-		})
+	// job was started before. Re-acquire it and check completion status
+	cleanupJob := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: cleanupJobName}, cleanupJob)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return false, errors.Wrapf(err, "error getting cleanup job previously started (%v)", cleanupJobName)
+		}
+		// err IsNotFound => job finished and was deleted before
+		return true, nil
 	}
 
-	storeContainer.Env = append(
-		storeContainer.Env,
-		corev1.EnvVar{
-			Name:  clusterEnvVarKey,
-			Value: cluster.Name,
-		},
-		corev1.EnvVar{
-			Name:  backupToCreateEnvVarKey,
-			Value: backupStatus.BackupName,
-		},
-		corev1.EnvVar{
-			Name:  backupScheduleEnvVarKey,
-			Value: backupConfig.Spec.Schedule,
-		},
-		corev1.EnvVar{
-			Name:  backupKeepCountEnvVarKey,
-			Value: strconv.Itoa(backupConfig.GetKeptBackupsCount()),
-		},
-		corev1.EnvVar{
-			Name:  backupConfigEnvVarKey,
-			Value: backupConfig.Name,
-		})
+	jobSucceeded := getJobConditionIfTrue(cleanupJob, batchv1.JobComplete) != nil
+	jobFailed := getJobConditionIfTrue(cleanupJob, batchv1.JobFailed) != nil
+	if !jobSucceeded && !jobFailed {
+		// job still running
+		return false, nil
+	}
 
-	storeContainer.VolumeMounts = append(storeContainer.VolumeMounts, corev1.VolumeMount{
-		Name:      "ca-bundle",
-		MountPath: "/etc/ca-bundle/",
-		ReadOnly:  true,
-	})
+	// job completed either way. delete it.
+	if err := r.Delete(ctx, cleanupJob, ctrlruntimeclient.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !kerrors.IsNotFound(err) {
+		return false, errors.Wrapf(err, "failed to delete finished cleanup job %s", cleanupJobName)
+	}
+	if jobFailed {
+		// job failed, restart it.
+		cleanupJob := r.cleanupJob(backupConfig, cluster, cleanupJobName, destination)
+		if err := r.Create(ctx, cleanupJob); err != nil && !kerrors.IsAlreadyExists(err) {
+			return false, errors.Wrapf(err, "error recreating cleanup job (%v)", cleanupJobName)
+		}
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (r *Reconciler) backupJob(backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster, backupStatus *kubermaticv1.BackupStatus,
+	destination *kubermaticv1.BackupDestination) *batchv1.Job {
+	storeContainer := r.backupStoreContainer(backupConfig, cluster, backupStatus, destination)
 
 	job := r.jobBase(backupConfig, cluster, backupStatus.JobName)
 
@@ -955,9 +990,79 @@ func (r *Reconciler) backupJob(backupConfig *kubermaticv1.EtcdBackupConfig, clus
 		},
 	}
 
+	if isEncryptionEnabled(destination) {
+		job.Spec.Template.Spec.InitContainers = append(job.Spec.Template.Spec.InitContainers, r.backupEncrypterContainer(destination))
+		job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, encryptionKeysVolume(destination))
+	}
+
+	addStoreVolume(destination, storeVolumeName, resources.EtcdBackupFilesystemMountPath, &job.Spec.Template.Spec.Containers[0], &job.Spec.Template.Spec)
+
 	return job
 }
 
+// backupStoreContainer returns the container uploading the snapshot in the shared volume to the destination.
+// Callers have to add the destination's volume using addStoreVolume.
+func (r *Reconciler) backupStoreContainer(backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster, backupStatus *kubermaticv1.BackupStatus,
+	destination *kubermaticv1.BackupDestination) *corev1.Container {
+	if usesBuiltinStore(destination) {
+		storeContainer := r.builtinStoreContainer("store-container", "upload", cluster, backupStatus.BackupName, destination)
+		storeContainer.VolumeMounts = []corev1.VolumeMount{
+			{
+				Name:      SharedVolumeName,
+				MountPath: "/backup",
+			},
+		}
+		return storeContainer
+	}
+
+	storeContainer := r.storeContainer.DeepCopy()
+
+	// If destination is set, we need to set the credentials and backup bucket details to match the destination
+	if destination != nil {
+		storeContainer.Env = setEnvVar(storeContainer.Env, genSecretEnvVar(accessKeyIdEnvVarKey, accessKeyIdEnvVarKey, destination))
+		storeContainer.Env = setEnvVar(storeContainer.Env, genSecretEnvVar(secretAccessKeyEnvVarKey, secretAccessKeyEnvVarKey, destination))
+		storeContainer.Env = setEnvVar(storeContainer.Env, corev1.EnvVar{
+			Name:  bucketNameEnvVarKey,
+			Value: destination.BucketName,
+		})
+		storeContainer.Env = setEnvVar(storeContainer.Env, corev1.EnvVar{
+			Name:  backupEndpointEnvVarKey,
+			Value: destination.Endpoint,
+		})
+	}
+
+	storeContainer.Env = append(
+		storeContainer.Env,
+		corev1.EnvVar{
+			Name:  clusterEnvVarKey,
+			Value: cluster.Name,
+		},
+		corev1.EnvVar{
+			Name:  backupToCreateEnvVarKey,
+			Value: backupStatus.BackupName,
+		},
+		corev1.EnvVar{
+			Name:  backupScheduleEnvVarKey,
+			Value: backupConfig.Spec.Schedule,
+		},
+		corev1.EnvVar{
+			Name:  backupKeepCountEnvVarKey,
+			Value: strconv.Itoa(backupConfig.GetKeptBackupsCount()),
+		},
+		corev1.EnvVar{
+			Name:  backupConfigEnvVarKey,
+			Value: backupConfig.Name,
+		})
+
+	storeContainer.VolumeMounts = append(storeContainer.VolumeMounts, corev1.VolumeMount{
+		Name:      "ca-bundle",
+		MountPath: "/etc/ca-bundle/",
+		ReadOnly:  true,
+	})
+
+	return storeContainer
+}
+
 func setEnvVar(envVars []corev1.EnvVar, newEnvVar corev1.EnvVar) []corev1.EnvVar {
 	for i, envVar := range envVars {
 		if strings.EqualFold(envVar.Name, newEnvVar.Name) {
@@ -981,8 +1086,16 @@ func genSecretEnvVar(name, key string, destination *kubermaticv1.BackupDestinati
 	}
 }
 
-func (r *Reconciler) backupDeleteJob(backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster, backupStatus *kubermaticv1.BackupStatus,
+func (r *Reconciler) backupDeleteJob(backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster, backupName, jobName string,
 	destination *kubermaticv1.BackupDestination) *batchv1.Job {
+	if usesBuiltinStore(destination) {
+		job := r.jobBase(backupConfig, cluster, jobName)
+		job.Spec.Template.Spec.Containers = []corev1.Container{*r.builtinStoreContainer("delete-container", "delete", cluster, backupName, destination)}
+		job.Spec.ActiveDeadlineSeconds = resources.Int64(4 * 60)
+		addStoreVolume(destination, storeVolumeName, resources.EtcdBackupFilesystemMountPath, &job.Spec.Template.Spec.Containers[0], &job.Spec.Template.Spec)
+		return job
+	}
+
 	deleteContainer := r.deleteContainer.DeepCopy()
 
 	// If destination is set, we need to set the credentials and backup bucket details to match the destination
@@ -1007,7 +1120,7 @@ func (r *Reconciler) backupDeleteJob(backupConfig *kubermaticv1.EtcdBackupConfig
 		},
 		corev1.EnvVar{
 			Name:  backupToDeleteEnvVarKey,
-			Value: backupStatus.BackupName,
+			Value: backupName,
 		},
 		corev1.EnvVar{
 			Name:  backupScheduleEnvVarKey,
@@ -1028,7 +1141,7 @@ func (r *Reconciler) backupDeleteJob(backupConfig *kubermaticv1.EtcdBackupConfig
 		ReadOnly:  true,
 	})
 
-	job := r.jobBase(backupConfig, cluster, backupStatus.DeleteJobName)
+	job := r.jobBase(backupConfig, cluster, jobName)
 	job.Spec.Template.Spec.Containers = []corev1.Container{*deleteContainer}
 	job.Spec.ActiveDeadlineSeconds = resources.Int64(4 * 60)
 	job.Spec.Template.Spec.Volumes = []corev1.Volume{
@@ -1048,6 +1161,13 @@ func (r *Reconciler) backupDeleteJob(backupConfig *kubermaticv1.EtcdBackupConfig
 
 func (r *Reconciler) cleanupJob(backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster, jobName string,
 	destination *kubermaticv1.BackupDestination) *batchv1.Job {
+	if usesBuiltinStore(destination) {
+		job := r.jobBase(backupConfig, cluster, jobName)
+		job.Spec.Template.Spec.Containers = []corev1.Container{*r.builtinStoreContainer("cleanup-container", "delete-all", cluster, "", destination)}
+		addStoreVolume(destination, storeVolumeName, resources.EtcdBackupFilesystemMountPath, &job.Spec.Template.Spec.Containers[0], &job.Spec.Template.Spec)
+		return job
+	}
+
 	cleanupContainer := r.cleanupContainer.DeepCopy()
 
 	// If destination is set, we need to set the credentials and backup bucket details to match the destination
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/go-test/deep"
+	"k8c.io/kubermatic/v2/pkg/backupstore"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/handler/test"
 	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
@@ -170,7 +171,7 @@ func genBackupDeleteJob(backupName string, jobName string) *batchv1.Job {
 			return test.GenTestSeed(), nil
 		},
 	}
-	job := reconciler.backupDeleteJob(backupConfig, cluster, backup, nil)
+	job := reconciler.backupDeleteJob(backupConfig, cluster, backup.BackupName, backup.DeleteJobName, nil)
 	job.ResourceVersion = "1"
 	// remove all env variables from the job so they're comparable against the
 	// ones we get from fake clusters during tests, where we strip the variables too
@@ -675,6 +676,7 @@ func TestStartPendingBackupDeleteJobs(t *testing.T) {
 		name              string
 		currentTime       time.Time
 		keep              int
+		retention         *kubermaticv1.EtcdBackupRetention
 		existingBackups   []kubermaticv1.BackupStatus
 		existingJobs      []batchv1.Job
 		expectedBackups   []kubermaticv1.BackupStatus
@@ -921,6 +923,46 @@ func TestStartPendingBackupDeleteJobs(t *testing.T) {
 				return *genBackupDeleteJob(fmt.Sprintf("testbackup-%v", i+1), fmt.Sprintf("testcluster-backup-testbackup-%v-delete", i+1))
 			}),
 		},
+		{
+			name:        "completed backups not selected by the retention policy are deleted",
+			currentTime: time.Unix(3*24*3600, 0).UTC(),
+			keep:        1,
+			retention:   &kubermaticv1.EtcdBackupRetention{Daily: 2},
+			existingBackups: genBackupStatusList(4, func(i int) kubermaticv1.BackupStatus {
+				// two backups per day
+				return kubermaticv1.BackupStatus{
+					ScheduledTime:      &metav1.Time{Time: time.Unix(int64(i)*12*3600, 0).UTC()},
+					BackupName:         fmt.Sprintf("testbackup-%v", i),
+					JobName:            fmt.Sprintf("testcluster-backup-testbackup-%v-create", i),
+					BackupFinishedTime: &metav1.Time{Time: time.Unix(int64(i)*12*3600+60, 0).UTC()},
+					BackupPhase:        kubermaticv1.BackupStatusPhaseCompleted,
+					BackupMessage:      "job completed",
+					DeleteJobName:      fmt.Sprintf("testcluster-backup-testbackup-%v-delete", i),
+				}
+			}),
+			existingJobs: []batchv1.Job{},
+			expectedBackups: genBackupStatusList(4, func(i int) kubermaticv1.BackupStatus {
+				result := kubermaticv1.BackupStatus{
+					ScheduledTime:      &metav1.Time{Time: time.Unix(int64(i)*12*3600, 0).UTC()},
+					BackupName:         fmt.Sprintf("testbackup-%v", i),
+					JobName:            fmt.Sprintf("testcluster-backup-testbackup-%v-create", i),
+					BackupFinishedTime: &metav1.Time{Time: time.Unix(int64(i)*12*3600+60, 0).UTC()},
+					BackupPhase:        kubermaticv1.BackupStatusPhaseCompleted,
+					BackupMessage:      "job completed",
+					DeleteJobName:      fmt.Sprintf("testcluster-backup-testbackup-%v-delete", i),
+				}
+				// the last backup of every day is kept
+				if i%2 == 0 {
+					result.DeletePhase = kubermaticv1.BackupStatusPhaseRunning
+				}
+				return result
+			}),
+			expectedReconcile: &reconcile.Result{RequeueAfter: assumedJobRuntime},
+			expectedJobs: []batchv1.Job{
+				*genBackupDeleteJob("testbackup-0", "testcluster-backup-testbackup-0-delete"),
+				*genBackupDeleteJob("testbackup-2", "testcluster-backup-testbackup-2-delete"),
+			},
+		},
 	}
 	for _, tc := range testCases {
 		tc := tc
@@ -933,6 +975,7 @@ func TestStartPendingBackupDeleteJobs(t *testing.T) {
 			backupConfig.SetCreationTimestamp(metav1.Time{Time: clock.Now()})
 			backupConfig.Spec.Schedule = "xxx" // must be non-empty
 			backupConfig.Spec.Keep = intPtr(tc.keep)
+			backupConfig.Spec.Retention = tc.retention
 			backupConfig.Status.CurrentBackups = tc.existingBackups
 
 			initObjs := []client.Object{
@@ -2030,3 +2073,623 @@ func containsEnvVar(envVars []corev1.EnvVar, envVar corev1.EnvVar) bool {
 	}
 	return false
 }
+
+func genVerifierPod(jobName string, exitCode int32, message string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName + "-pod",
+			Namespace: metav1.NamespaceSystem,
+			Labels:    map[string]string{jobNameLabelKey: jobName},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: verifierContainerName,
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: exitCode,
+							Message:  message,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestUpdateBackupVerifications(t *testing.T) {
+	testCases := []struct {
+		name              string
+		existingBackups   []kubermaticv1.BackupStatus
+		existingObjects   []client.Object
+		expectedBackups   []kubermaticv1.BackupStatus
+		expectedReconcile *reconcile.Result
+		expectedJobNames  []string
+	}{
+		{
+			name: "verification job is started for completed backups only",
+			existingBackups: []kubermaticv1.BackupStatus{
+				{
+					BackupName:  "testbackup-1970-01-01t00-01-00",
+					JobName:     "testcluster-backup-testbackup-create-aaaa",
+					BackupPhase: kubermaticv1.BackupStatusPhaseCompleted,
+				},
+				{
+					BackupName:  "testbackup-1970-01-01t00-02-00",
+					JobName:     "testcluster-backup-testbackup-create-bbbb",
+					BackupPhase: kubermaticv1.BackupStatusPhaseFailed,
+				},
+			},
+			expectedBackups: []kubermaticv1.BackupStatus{
+				{
+					BackupName:          "testbackup-1970-01-01t00-01-00",
+					JobName:             "testcluster-backup-testbackup-create-aaaa",
+					BackupPhase:         kubermaticv1.BackupStatusPhaseCompleted,
+					VerificationJobName: "testcluster-backup-testbackup-verify-xxxx",
+					VerificationPhase:   kubermaticv1.BackupStatusPhaseRunning,
+				},
+				{
+					BackupName:  "testbackup-1970-01-01t00-02-00",
+					JobName:     "testcluster-backup-testbackup-create-bbbb",
+					BackupPhase: kubermaticv1.BackupStatusPhaseFailed,
+				},
+			},
+			expectedReconcile: &reconcile.Result{RequeueAfter: assumedJobRuntime},
+			expectedJobNames:  []string{"testcluster-backup-testbackup-verify-xxxx"},
+		},
+		{
+			name: "verification results are read from the finished jobs",
+			existingBackups: []kubermaticv1.BackupStatus{
+				{
+					BackupName:          "testbackup-1970-01-01t00-01-00",
+					BackupPhase:         kubermaticv1.BackupStatusPhaseCompleted,
+					VerificationJobName: "testcluster-backup-testbackup-verify-aaaa",
+					VerificationPhase:   kubermaticv1.BackupStatusPhaseRunning,
+				},
+				{
+					BackupName:          "testbackup-1970-01-01t00-02-00",
+					BackupPhase:         kubermaticv1.BackupStatusPhaseCompleted,
+					VerificationJobName: "testcluster-backup-testbackup-verify-bbbb",
+					VerificationPhase:   kubermaticv1.BackupStatusPhaseRunning,
+				},
+			},
+			existingObjects: []client.Object{
+				jobAddCondition(&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "testcluster-backup-testbackup-verify-aaaa", Namespace: metav1.NamespaceSystem}},
+					batchv1.JobComplete, corev1.ConditionTrue, time.Unix(90, 0).UTC(), "job completed"),
+				genVerifierPod("testcluster-backup-testbackup-verify-aaaa", 0, `{"revision":42,"keyCount":7,"hash":"0000abcd","restored":false}`),
+				jobAddCondition(&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "testcluster-backup-testbackup-verify-bbbb", Namespace: metav1.NamespaceSystem}},
+					batchv1.JobFailed, corev1.ConditionTrue, time.Unix(80, 0).UTC(), "Job has reached the specified backoff limit"),
+				genVerifierPod("testcluster-backup-testbackup-verify-bbbb", 1, "snapshot is corrupt: invalid database"),
+			},
+			expectedBackups: []kubermaticv1.BackupStatus{
+				{
+					BackupName:               "testbackup-1970-01-01t00-01-00",
+					BackupPhase:              kubermaticv1.BackupStatusPhaseCompleted,
+					VerificationJobName:      "testcluster-backup-testbackup-verify-aaaa",
+					VerificationPhase:        kubermaticv1.BackupStatusPhaseCompleted,
+					VerificationMessage:      "job completed",
+					VerificationFinishedTime: &metav1.Time{Time: time.Unix(90, 0).UTC()},
+					SnapshotRevision:         42,
+					SnapshotKeyCount:         7,
+					SnapshotHash:             "0000abcd",
+				},
+				{
+					BackupName:               "testbackup-1970-01-01t00-02-00",
+					BackupPhase:              kubermaticv1.BackupStatusPhaseCompleted,
+					VerificationJobName:      "testcluster-backup-testbackup-verify-bbbb",
+					VerificationPhase:        kubermaticv1.BackupStatusPhaseFailed,
+					VerificationMessage:      "snapshot is corrupt: invalid database",
+					VerificationFinishedTime: &metav1.Time{Time: time.Unix(80, 0).UTC()},
+				},
+			},
+			expectedReconcile: nil,
+			expectedJobNames:  []string{"testcluster-backup-testbackup-verify-aaaa", "testcluster-backup-testbackup-verify-bbbb"},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			cluster := genTestCluster()
+			backupConfig := genBackupConfig(cluster, "testbackup")
+			backupConfig.Spec.Verification = &kubermaticv1.EtcdBackupVerification{Enabled: true}
+			backupConfig.Status.CurrentBackups = tc.existingBackups
+
+			initObjs := []client.Object{
+				cluster,
+				backupConfig,
+			}
+			initObjs = append(initObjs, tc.existingObjects...)
+
+			reconciler := Reconciler{
+				log:                 kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+				Client:              ctrlruntimefakeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(initObjs...).Build(),
+				scheme:              scheme.Scheme,
+				recorder:            record.NewFakeRecorder(10),
+				clock:               clock.NewFakeClock(time.Unix(100, 0).UTC()),
+				randStringGenerator: constRandStringGenerator("xxxx"),
+			}
+
+			reconcileAfter, err := reconciler.updateBackupVerifications(context.Background(), backupConfig, cluster, nil)
+			if err != nil {
+				t.Fatalf("updateBackupVerifications returned an error: %v", err)
+			}
+
+			readbackBackupConfig := &kubermaticv1.EtcdBackupConfig{}
+			if err := reconciler.Get(context.Background(), client.ObjectKey{Namespace: backupConfig.GetNamespace(), Name: backupConfig.GetName()}, readbackBackupConfig); err != nil {
+				t.Fatalf("Error reading back completed backupConfig: %v", err)
+			}
+
+			if diff := deep.Equal(readbackBackupConfig.Status.CurrentBackups, tc.expectedBackups); diff != nil {
+				t.Errorf("backups differ from expected, diff: %v", diff)
+			}
+
+			jobList := batchv1.JobList{}
+			if err := reconciler.List(context.Background(), &jobList); err != nil {
+				t.Fatalf("Error reading jobList: %v", err)
+			}
+			var jobNames []string
+			for _, job := range jobList.Items {
+				jobNames = append(jobNames, job.Name)
+			}
+			sort.Strings(jobNames)
+			if diff := deep.Equal(jobNames, tc.expectedJobNames); diff != nil {
+				t.Errorf("jobs differ from expected ones: %v", diff)
+			}
+
+			if deep.Equal(reconcileAfter, tc.expectedReconcile) != nil {
+				t.Errorf("reconcile time differs from expected, expected: %v, actual: %v", tc.expectedReconcile, reconcileAfter)
+			}
+		})
+	}
+}
+
+func TestBackupJobEncryption(t *testing.T) {
+	cluster := genTestCluster()
+	backupConfig := genBackupConfig(cluster, "testbackup")
+	backup := &kubermaticv1.BackupStatus{
+		BackupName: "testbackup-2021-05-01t10-00-00",
+		JobName:    "testcluster-backup-testbackup-create-abc",
+	}
+	reconciler := Reconciler{
+		storeContainer:    genStoreContainer(),
+		etcdLauncherImage: "etcd-launcher:v1.0.0",
+	}
+
+	job := reconciler.backupJob(backupConfig, cluster, backup, genDefaultBackupDestination())
+	if len(job.Spec.Template.Spec.InitContainers) != 1 {
+		t.Fatalf("expected only the backup-creator init container for unencrypted destinations, got %d", len(job.Spec.Template.Spec.InitContainers))
+	}
+
+	destination := genDefaultBackupDestination()
+	destination.Encryption = &kubermaticv1.BackupEncryption{
+		KeySecret: &corev1.SecretReference{Name: "backup-encryption-keys", Namespace: metav1.NamespaceSystem},
+		ActiveKey: "key-2",
+	}
+
+	job = reconciler.backupJob(backupConfig, cluster, backup, destination)
+	initContainers := job.Spec.Template.Spec.InitContainers
+	if len(initContainers) != 2 {
+		t.Fatalf("expected backup-creator and backup-encrypter init containers, got %d", len(initContainers))
+	}
+
+	encrypter := initContainers[1]
+	if encrypter.Name != "backup-encrypter" {
+		t.Fatalf("expected encrypter to run after the snapshot was created, got %q", encrypter.Name)
+	}
+	if diff := deep.Equal(encrypter.Command[len(encrypter.Command)-2:], []string{"-key-id", "key-2"}); diff != nil {
+		t.Errorf("encrypter does not use the active key: %v", diff)
+	}
+
+	var keyVolume *corev1.Volume
+	for i, volume := range job.Spec.Template.Spec.Volumes {
+		if volume.Name == encryptionKeysVolumeName {
+			keyVolume = &job.Spec.Template.Spec.Volumes[i]
+		}
+	}
+	if keyVolume == nil || keyVolume.Secret == nil || keyVolume.Secret.SecretName != "backup-encryption-keys" {
+		t.Errorf("expected the key secret to be mounted, got %v", keyVolume)
+	}
+}
+
+func TestBuiltinStoreJobs(t *testing.T) {
+	cluster := genTestCluster()
+	backupConfig := genBackupConfig(cluster, "testbackup")
+	backup := &kubermaticv1.BackupStatus{
+		BackupName: "testbackup-2021-05-01t10-00-00",
+		JobName:    "testcluster-backup-testbackup-create-abc",
+	}
+	reconciler := Reconciler{
+		storeContainer:    genStoreContainer(),
+		deleteContainer:   genDeleteContainer(),
+		etcdLauncherImage: "etcd-launcher:v1.0.0",
+	}
+
+	azure := genDefaultBackupDestination()
+	azure.Type = kubermaticv1.BackupDestinationTypeAzureBlob
+
+	job := reconciler.backupJob(backupConfig, cluster, backup, azure)
+	store := job.Spec.Template.Spec.Containers[0]
+	if store.Image != "etcd-launcher:v1.0.0" || store.Command[0] != "/etcd-backup-store" || store.Command[len(store.Command)-1] != "upload" {
+		t.Fatalf("expected etcd-backup-store to upload the backup, got %s %v", store.Image, store.Command)
+	}
+	if !containsEnvVar(store.Env, corev1.EnvVar{Name: backupstore.TypeEnvVar, Value: string(kubermaticv1.BackupDestinationTypeAzureBlob)}) {
+		t.Errorf("expected the store type to be passed to etcd-backup-store")
+	}
+	if !containsEnvVar(store.Env, genSecretEnvVar(backupstore.AzureAccountKeyKey, backupstore.AzureAccountKeyKey, azure)) {
+		t.Errorf("expected the Azure credentials to be passed to etcd-backup-store")
+	}
+
+	filesystem := &kubermaticv1.BackupDestination{
+		Type:       kubermaticv1.BackupDestinationTypeFilesystem,
+		BucketName: "backups",
+		Filesystem: &kubermaticv1.FilesystemBackupDestination{PersistentVolumeClaim: "etcd-backups"},
+	}
+
+	job = reconciler.backupDeleteJob(backupConfig, cluster, backup.BackupName, "testcluster-backup-testbackup-delete-abc", filesystem)
+	deleter := job.Spec.Template.Spec.Containers[0]
+	if diff := deep.Equal(deleter.Command[len(deleter.Command)-3:], []string{"-path", resources.EtcdBackupFilesystemMountPath, "delete"}); diff != nil {
+		t.Errorf("expected etcd-backup-store to delete the backup from the mounted volume: %v", diff)
+	}
+	if len(deleter.VolumeMounts) != 1 || deleter.VolumeMounts[0].MountPath != resources.EtcdBackupFilesystemMountPath {
+		t.Errorf("expected the volume to be mounted, got %v", deleter.VolumeMounts)
+	}
+	volumes := job.Spec.Template.Spec.Volumes
+	if len(volumes) != 1 || volumes[0].PersistentVolumeClaim == nil || volumes[0].PersistentVolumeClaim.ClaimName != "etcd-backups" {
+		t.Errorf("expected the claim to be used as volume, got %v", volumes)
+	}
+}
+
+func TestUpdateBackupReplications(t *testing.T) {
+	testCases := []struct {
+		name              string
+		existingBackups   []kubermaticv1.BackupStatus
+		existingObjects   []client.Object
+		expectedBackups   []kubermaticv1.BackupStatus
+		expectedReconcile *reconcile.Result
+		expectedJobNames  []string
+	}{
+		{
+			name: "replication is started for verified backups only",
+			existingBackups: []kubermaticv1.BackupStatus{
+				{
+					BackupName:        "testbackup-1970-01-01t00-01-00",
+					BackupPhase:       kubermaticv1.BackupStatusPhaseCompleted,
+					VerificationPhase: kubermaticv1.BackupStatusPhaseCompleted,
+				},
+				{
+					BackupName:        "testbackup-1970-01-01t00-02-00",
+					BackupPhase:       kubermaticv1.BackupStatusPhaseCompleted,
+					VerificationPhase: kubermaticv1.BackupStatusPhaseRunning,
+				},
+				{
+					BackupName:        "testbackup-1970-01-01t00-03-00",
+					BackupPhase:       kubermaticv1.BackupStatusPhaseCompleted,
+					VerificationPhase: kubermaticv1.BackupStatusPhaseFailed,
+				},
+			},
+			expectedBackups: []kubermaticv1.BackupStatus{
+				{
+					BackupName:        "testbackup-1970-01-01t00-01-00",
+					BackupPhase:       kubermaticv1.BackupStatusPhaseCompleted,
+					VerificationPhase: kubermaticv1.BackupStatusPhaseCompleted,
+					Replicas: []kubermaticv1.BackupReplicaStatus{
+						{
+							Destination:   "offsite",
+							JobName:       "testcluster-backup-testbackup-replicate-xxxx",
+							BackupPhase:   kubermaticv1.BackupStatusPhaseRunning,
+							DeleteJobName: "testcluster-backup-testbackup-delete-xxxx",
+						},
+					},
+				},
+				{
+					BackupName:        "testbackup-1970-01-01t00-02-00",
+					BackupPhase:       kubermaticv1.BackupStatusPhaseCompleted,
+					VerificationPhase: kubermaticv1.BackupStatusPhaseRunning,
+				},
+				{
+					BackupName:        "testbackup-1970-01-01t00-03-00",
+					BackupPhase:       kubermaticv1.BackupStatusPhaseCompleted,
+					VerificationPhase: kubermaticv1.BackupStatusPhaseFailed,
+				},
+			},
+			expectedReconcile: &reconcile.Result{RequeueAfter: assumedJobRuntime},
+			expectedJobNames:  []string{"testcluster-backup-testbackup-replicate-xxxx"},
+		},
+		{
+			name: "replica status is read from the finished jobs",
+			existingBackups: []kubermaticv1.BackupStatus{
+				{
+					BackupName:        "testbackup-1970-01-01t00-01-00",
+					BackupPhase:       kubermaticv1.BackupStatusPhaseCompleted,
+					VerificationPhase: kubermaticv1.BackupStatusPhaseCompleted,
+					Replicas: []kubermaticv1.BackupReplicaStatus{
+						{
+							Destination: "offsite",
+							JobName:     "testcluster-backup-testbackup-replicate-aaaa",
+							BackupPhase: kubermaticv1.BackupStatusPhaseRunning,
+						},
+					},
+				},
+				{
+					BackupName:        "testbackup-1970-01-01t00-02-00",
+					BackupPhase:       kubermaticv1.BackupStatusPhaseCompleted,
+					VerificationPhase: kubermaticv1.BackupStatusPhaseCompleted,
+					Replicas: []kubermaticv1.BackupReplicaStatus{
+						{
+							Destination: "offsite",
+							JobName:     "testcluster-backup-testbackup-replicate-bbbb",
+							BackupPhase: kubermaticv1.BackupStatusPhaseRunning,
+						},
+					},
+				},
+			},
+			existingObjects: []client.Object{
+				jobAddCondition(&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "testcluster-backup-testbackup-replicate-aaaa", Namespace: metav1.NamespaceSystem}},
+					batchv1.JobComplete, corev1.ConditionTrue, time.Unix(90, 0).UTC(), "job completed"),
+				jobAddCondition(&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "testcluster-backup-testbackup-replicate-bbbb", Namespace: metav1.NamespaceSystem}},
+					batchv1.JobFailed, corev1.ConditionTrue, time.Unix(80, 0).UTC(), "job failed"),
+			},
+			expectedBackups: []kubermaticv1.BackupStatus{
+				{
+					BackupName:        "testbackup-1970-01-01t00-01-00",
+					BackupPhase:       kubermaticv1.BackupStatusPhaseCompleted,
+					VerificationPhase: kubermaticv1.BackupStatusPhaseCompleted,
+					Replicas: []kubermaticv1.BackupReplicaStatus{
+						{
+							Destination:        "offsite",
+							JobName:            "testcluster-backup-testbackup-replicate-aaaa",
+							BackupPhase:        kubermaticv1.BackupStatusPhaseCompleted,
+							BackupMessage:      "job completed",
+							BackupFinishedTime: &metav1.Time{Time: time.Unix(90, 0).UTC()},
+						},
+					},
+				},
+				{
+					BackupName:        "testbackup-1970-01-01t00-02-00",
+					BackupPhase:       kubermaticv1.BackupStatusPhaseCompleted,
+					VerificationPhase: kubermaticv1.BackupStatusPhaseCompleted,
+					Replicas: []kubermaticv1.BackupReplicaStatus{
+						{
+							Destination:        "offsite",
+							JobName:            "testcluster-backup-testbackup-replicate-bbbb",
+							BackupPhase:        kubermaticv1.BackupStatusPhaseFailed,
+							BackupMessage:      "job failed",
+							BackupFinishedTime: &metav1.Time{Time: time.Unix(80, 0).UTC()},
+						},
+					},
+				},
+			},
+			expectedReconcile: nil,
+			expectedJobNames:  []string{"testcluster-backup-testbackup-replicate-aaaa", "testcluster-backup-testbackup-replicate-bbbb"},
+		},
+		{
+			name: "replication is cancelled if the backup was deleted before it could be replicated",
+			existingBackups: []kubermaticv1.BackupStatus{
+				{
+					BackupName:        "testbackup-1970-01-01t00-01-00",
+					BackupPhase:       kubermaticv1.BackupStatusPhaseCompleted,
+					VerificationPhase: kubermaticv1.BackupStatusPhaseCompleted,
+					DeletePhase:       kubermaticv1.BackupStatusPhaseRunning,
+					Replicas: []kubermaticv1.BackupReplicaStatus{
+						{
+							Destination: "offsite",
+							JobName:     "testcluster-backup-testbackup-replicate-aaaa",
+						},
+					},
+				},
+			},
+			expectedBackups: []kubermaticv1.BackupStatus{
+				{
+					BackupName:        "testbackup-1970-01-01t00-01-00",
+					BackupPhase:       kubermaticv1.BackupStatusPhaseCompleted,
+					VerificationPhase: kubermaticv1.BackupStatusPhaseCompleted,
+					DeletePhase:       kubermaticv1.BackupStatusPhaseRunning,
+					Replicas: []kubermaticv1.BackupReplicaStatus{
+						{
+							Destination:        "offsite",
+							JobName:            "testcluster-backup-testbackup-replicate-aaaa",
+							BackupPhase:        kubermaticv1.BackupStatusPhaseFailed,
+							BackupMessage:      "replication cancelled",
+							BackupFinishedTime: &metav1.Time{Time: time.Unix(100, 0).UTC()},
+							DeletePhase:        kubermaticv1.BackupStatusPhaseCompleted,
+							DeleteFinishedTime: &metav1.Time{Time: time.Unix(100, 0).UTC()},
+						},
+					},
+				},
+			},
+			expectedReconcile: nil,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			cluster := genTestCluster()
+			backupConfig := genBackupConfig(cluster, "testbackup")
+			backupConfig.Spec.Verification = &kubermaticv1.EtcdBackupVerification{Enabled: true}
+			backupConfig.Spec.Replicas = []kubermaticv1.EtcdBackupReplica{{Destination: "offsite"}}
+			backupConfig.Status.CurrentBackups = tc.existingBackups
+
+			initObjs := []client.Object{
+				cluster,
+				backupConfig,
+			}
+			initObjs = append(initObjs, tc.existingObjects...)
+
+			reconciler := Reconciler{
+				log:                 kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+				Client:              ctrlruntimefakeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(initObjs...).Build(),
+				scheme:              scheme.Scheme,
+				storeContainer:      genStoreContainer(),
+				recorder:            record.NewFakeRecorder(10),
+				clock:               clock.NewFakeClock(time.Unix(100, 0).UTC()),
+				randStringGenerator: constRandStringGenerator("xxxx"),
+			}
+
+			destinations := map[string]*kubermaticv1.BackupDestination{"offsite": genDefaultBackupDestination()}
+			reconcileAfter, err := reconciler.updateBackupReplications(context.Background(), backupConfig, cluster, nil, destinations)
+			if err != nil {
+				t.Fatalf("updateBackupReplications returned an error: %v", err)
+			}
+
+			readbackBackupConfig := &kubermaticv1.EtcdBackupConfig{}
+			if err := reconciler.Get(context.Background(), client.ObjectKey{Namespace: backupConfig.GetNamespace(), Name: backupConfig.GetName()}, readbackBackupConfig); err != nil {
+				t.Fatalf("Error reading back completed backupConfig: %v", err)
+			}
+
+			if diff := deep.Equal(readbackBackupConfig.Status.CurrentBackups, tc.expectedBackups); diff != nil {
+				t.Errorf("backups differ from expected, diff: %v", diff)
+			}
+
+			jobList := batchv1.JobList{}
+			if err := reconciler.List(context.Background(), &jobList); err != nil {
+				t.Fatalf("Error reading jobList: %v", err)
+			}
+			var jobNames []string
+			for _, job := range jobList.Items {
+				jobNames = append(jobNames, job.Name)
+			}
+			sort.Strings(jobNames)
+			if diff := deep.Equal(jobNames, tc.expectedJobNames); diff != nil {
+				t.Errorf("jobs differ from expected ones: %v", diff)
+			}
+
+			if deep.Equal(reconcileAfter, tc.expectedReconcile) != nil {
+				t.Errorf("reconcile time differs from expected, expected: %v, actual: %v", tc.expectedReconcile, reconcileAfter)
+			}
+		})
+	}
+}
+
+func TestStartPendingReplicaDeleteJobs(t *testing.T) {
+	cluster := genTestCluster()
+	backupConfig := genBackupConfig(cluster, "testbackup")
+	backupConfig.Spec.Schedule = "* * * * *"
+	backupConfig.Spec.Replicas = []kubermaticv1.EtcdBackupReplica{{Destination: "offsite", Keep: intPtr(1)}}
+	backupConfig.Status.CurrentBackups = genBackupStatusList(4, func(i int) kubermaticv1.BackupStatus {
+		var replicaPhase kubermaticv1.BackupStatusPhase = kubermaticv1.BackupStatusPhaseCompleted
+		if i == 3 {
+			replicaPhase = kubermaticv1.BackupStatusPhaseFailed
+		}
+		return kubermaticv1.BackupStatus{
+			ScheduledTime: &metav1.Time{Time: time.Unix(int64(i*60), 0).UTC()},
+			BackupName:    fmt.Sprintf("testbackup-%d", i),
+			BackupPhase:   kubermaticv1.BackupStatusPhaseCompleted,
+			Replicas: []kubermaticv1.BackupReplicaStatus{
+				{
+					Destination:   "offsite",
+					BackupPhase:   replicaPhase,
+					DeleteJobName: fmt.Sprintf("testcluster-backup-testbackup-delete-replica-%d", i),
+				},
+			},
+		}
+	})
+
+	reconciler := Reconciler{
+		log:             kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+		Client:          ctrlruntimefakeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(cluster, backupConfig).Build(),
+		scheme:          scheme.Scheme,
+		deleteContainer: genDeleteContainer(),
+		recorder:        record.NewFakeRecorder(10),
+		clock:           clock.NewFakeClock(time.Unix(300, 0).UTC()),
+	}
+
+	destinations := map[string]*kubermaticv1.BackupDestination{"offsite": genDefaultBackupDestination()}
+	if _, err := reconciler.startPendingReplicaDeleteJobs(context.Background(), backupConfig, cluster, destinations); err != nil {
+		t.Fatalf("startPendingReplicaDeleteJobs returned an error: %v", err)
+	}
+
+	// the failed replica is deleted, and of the completed ones only the newest one is kept
+	expectedDeletePhases := []kubermaticv1.BackupStatusPhase{
+		kubermaticv1.BackupStatusPhaseRunning,
+		kubermaticv1.BackupStatusPhaseRunning,
+		"",
+		kubermaticv1.BackupStatusPhaseRunning,
+	}
+	for i, backup := range backupConfig.Status.CurrentBackups {
+		if backup.Replicas[0].DeletePhase != expectedDeletePhases[i] {
+			t.Errorf("expected delete phase of replica of backup %d to be %q, got %q", i, expectedDeletePhases[i], backup.Replicas[0].DeletePhase)
+		}
+		// the primary destination keeps DefaultKeptBackupsCount backups
+		if backup.DeletePhase != "" {
+			t.Errorf("expected backup %d to be kept in the primary destination", i)
+		}
+	}
+
+	jobList := batchv1.JobList{}
+	if err := reconciler.List(context.Background(), &jobList); err != nil {
+		t.Fatalf("Error reading jobList: %v", err)
+	}
+	if len(jobList.Items) != 3 {
+		t.Fatalf("expected 3 delete jobs, got %d", len(jobList.Items))
+	}
+	for _, job := range jobList.Items {
+		if !containsEnvVar(job.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: bucketNameEnvVarKey, Value: genDefaultBackupDestination().BucketName}) {
+			t.Errorf("expected delete job %s to delete from the replica destination", job.Name)
+		}
+	}
+}
+
+func TestBackupReplicationJob(t *testing.T) {
+	cluster := genTestCluster()
+	backupConfig := genBackupConfig(cluster, "testbackup")
+	backupConfig.Spec.Replicas = []kubermaticv1.EtcdBackupReplica{{Destination: "offsite", Keep: intPtr(5)}}
+	backup := &kubermaticv1.BackupStatus{
+		BackupName: "testbackup-2021-05-01t10-00-00",
+	}
+	replica := &kubermaticv1.BackupReplicaStatus{
+		Destination: "offsite",
+		JobName:     "testcluster-backup-testbackup-replicate-abc",
+	}
+	reconciler := Reconciler{
+		storeContainer:    genStoreContainer(),
+		etcdLauncherImage: "etcd-launcher:v1.0.0",
+	}
+
+	source := genDefaultBackupDestination()
+	source.Encryption = &kubermaticv1.BackupEncryption{
+		KeySecret: &corev1.SecretReference{Name: "source-keys", Namespace: metav1.NamespaceSystem},
+		ActiveKey: "key-1",
+	}
+	destination := genDefaultBackupDestination()
+	destination.BucketName = "offsite"
+	destination.Encryption = &kubermaticv1.BackupEncryption{
+		KeySecret: &corev1.SecretReference{Name: "offsite-keys", Namespace: metav1.NamespaceSystem},
+		ActiveKey: "key-2",
+	}
+
+	job := reconciler.backupReplicationJob(backupConfig, cluster, backup, replica, source, destination)
+
+	initContainers := job.Spec.Template.Spec.InitContainers
+	if len(initContainers) != 2 || initContainers[0].Name != "backup-downloader" || initContainers[1].Name != "backup-encrypter" {
+		t.Fatalf("expected backup-downloader and backup-encrypter init containers, got %v", initContainers)
+	}
+	if !containsEnvVar(initContainers[0].Env, corev1.EnvVar{Name: bucketNameEnvVarKey, Value: source.BucketName}) {
+		t.Errorf("expected the downloader to download from the source destination")
+	}
+	if diff := deep.Equal(initContainers[0].Command[len(initContainers[0].Command)-2:], []string{"-encryption-keys", sourceEncryptionKeysMountPath}); diff != nil {
+		t.Errorf("downloader does not decrypt the backup: %v", diff)
+	}
+	if diff := deep.Equal(initContainers[1].Command[len(initContainers[1].Command)-2:], []string{"-key-id", "key-2"}); diff != nil {
+		t.Errorf("encrypter does not use the key of the replica destination: %v", diff)
+	}
+
+	storeEnv := job.Spec.Template.Spec.Containers[0].Env
+	if !containsEnvVar(storeEnv, corev1.EnvVar{Name: bucketNameEnvVarKey, Value: destination.BucketName}) {
+		t.Errorf("expected the store container to upload to the replica destination")
+	}
+	if !containsEnvVar(storeEnv, corev1.EnvVar{Name: backupKeepCountEnvVarKey, Value: "5"}) {
+		t.Errorf("expected the store container to use the keep count of the replica")
+	}
+
+	secrets := map[string]string{}
+	for _, volume := range job.Spec.Template.Spec.Volumes {
+		if volume.Secret != nil {
+			secrets[volume.Name] = volume.Secret.SecretName
+		}
+	}
+	if diff := deep.Equal(secrets, map[string]string{sourceEncryptionKeysVolumeName: "source-keys", encryptionKeysVolumeName: "offsite-keys"}); diff != nil {
+		t.Errorf("unexpected key secret volumes: %v", diff)
+	}
+}
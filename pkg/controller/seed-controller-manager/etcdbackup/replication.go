@@ -0,0 +1,510 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdbackup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	sourceEncryptionKeysVolumeName = "source-encryption-keys"
+	sourceEncryptionKeysMountPath  = "/etc/backup-source-encryption"
+)
+
+// getReplicaDestinations returns the destinations of all replicas configured in the backup config or
+// still tracked in its status. Destinations which are only tracked in the status and no longer exist
+// in the Seed are mapped to nil.
+func getReplicaDestinations(backupConfig *kubermaticv1.EtcdBackupConfig, seed *kubermaticv1.Seed) (map[string]*kubermaticv1.BackupDestination, error) {
+	destinations := map[string]*kubermaticv1.BackupDestination{}
+
+	for _, replica := range backupConfig.Spec.Replicas {
+		if replica.Destination == "" {
+			return nil, errors.New("replica destination must not be empty")
+		}
+		if replica.Destination == backupConfig.Spec.Destination {
+			return nil, errors.Errorf("replica destination %q is the destination of the backup config", replica.Destination)
+		}
+		if _, ok := destinations[replica.Destination]; ok {
+			return nil, errors.Errorf("replica destination %q is configured more than once", replica.Destination)
+		}
+		destination, err := getDestination(seed, replica.Destination)
+		if err != nil {
+			return nil, err
+		}
+		destinations[replica.Destination] = destination
+	}
+
+	for _, backup := range backupConfig.Status.CurrentBackups {
+		for _, replica := range backup.Replicas {
+			if _, ok := destinations[replica.Destination]; !ok {
+				// a missing destination is not an error here, its replicas just can't be deleted anymore
+				destination, _ := getDestination(seed, replica.Destination)
+				destinations[replica.Destination] = destination
+			}
+		}
+	}
+
+	return destinations, nil
+}
+
+func getReplicaSpec(backupConfig *kubermaticv1.EtcdBackupConfig, destination string) *kubermaticv1.EtcdBackupReplica {
+	for i := range backupConfig.Spec.Replicas {
+		if backupConfig.Spec.Replicas[i].Destination == destination {
+			return &backupConfig.Spec.Replicas[i]
+		}
+	}
+	return nil
+}
+
+func getReplicaStatus(backup *kubermaticv1.BackupStatus, destination string) *kubermaticv1.BackupReplicaStatus {
+	for i := range backup.Replicas {
+		if backup.Replicas[i].Destination == destination {
+			return &backup.Replicas[i]
+		}
+	}
+	return nil
+}
+
+// isReadyForReplication returns true if the backup has been uploaded to the destination of the backup
+// config and, if verification is enabled, has been verified successfully.
+func isReadyForReplication(backupConfig *kubermaticv1.EtcdBackupConfig, backup *kubermaticv1.BackupStatus) bool {
+	if backupConfig.DeletionTimestamp != nil || backup.BackupPhase != kubermaticv1.BackupStatusPhaseCompleted || backup.DeletePhase != "" {
+		return false
+	}
+	if backupConfig.IsVerificationEnabled() {
+		return backup.VerificationPhase == kubermaticv1.BackupStatusPhaseCompleted
+	}
+	return true
+}
+
+// create replication jobs for all completed backups which have not been replicated to all replica
+// destinations yet and update the status of replicas whose replication jobs have finished
+func (r *Reconciler) updateBackupReplications(ctx context.Context, backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster,
+	source *kubermaticv1.BackupDestination, destinations map[string]*kubermaticv1.BackupDestination) (*reconcile.Result, error) {
+	var returnReconcile *reconcile.Result
+
+	modified := false
+	for i := range backupConfig.Status.CurrentBackups {
+		backup := &backupConfig.Status.CurrentBackups[i]
+
+		if isReadyForReplication(backupConfig, backup) {
+			for _, replicaSpec := range backupConfig.Spec.Replicas {
+				if getReplicaStatus(backup, replicaSpec.Destination) == nil {
+					backup.Replicas = append(backup.Replicas, kubermaticv1.BackupReplicaStatus{
+						Destination:   replicaSpec.Destination,
+						JobName:       r.limitNameLength(fmt.Sprintf("%s-backup-%s-replicate-%s", cluster.Name, backupConfig.Name, r.randStringGenerator())),
+						DeleteJobName: r.limitNameLength(fmt.Sprintf("%s-backup-%s-delete-%s", cluster.Name, backupConfig.Name, r.randStringGenerator())),
+					})
+					modified = true
+				}
+			}
+		}
+
+		for j := range backup.Replicas {
+			replica := &backup.Replicas[j]
+
+			switch replica.BackupPhase {
+			case kubermaticv1.BackupStatusPhaseRunning:
+				job := &batchv1.Job{}
+				err := r.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: replica.JobName}, job)
+				if err != nil {
+					if !kerrors.IsNotFound(err) {
+						return nil, errors.Wrapf(err, "error getting replication job for backup %s", backup.BackupName)
+					}
+					replica.BackupPhase = kubermaticv1.BackupStatusPhaseFailed
+					replica.BackupMessage = "replication job deleted externally"
+					replica.BackupFinishedTime = &metav1.Time{Time: r.clock.Now()}
+					modified = true
+					continue
+				}
+
+				if cond := getJobConditionIfTrue(job, batchv1.JobComplete); cond != nil {
+					replica.BackupPhase = kubermaticv1.BackupStatusPhaseCompleted
+					replica.BackupMessage = cond.Message
+					replica.BackupFinishedTime = &cond.LastTransitionTime
+					modified = true
+				} else if cond := getJobConditionIfTrue(job, batchv1.JobFailed); cond != nil {
+					replica.BackupPhase = kubermaticv1.BackupStatusPhaseFailed
+					replica.BackupMessage = cond.Message
+					replica.BackupFinishedTime = &cond.LastTransitionTime
+					modified = true
+					r.recorder.Eventf(backupConfig, corev1.EventTypeWarning, "BackupReplicationFailed",
+						"replication of backup %s to destination %s failed: %s", backup.BackupName, replica.Destination, cond.Message)
+				} else {
+					// job still running
+					returnReconcile = minReconcile(returnReconcile, &reconcile.Result{RequeueAfter: assumedJobRuntime})
+				}
+
+			case "":
+				destination := destinations[replica.Destination]
+				if backup.DeletePhase != "" || backupConfig.DeletionTimestamp != nil || destination == nil || getReplicaSpec(backupConfig, replica.Destination) == nil {
+					// nothing has been uploaded, so there is nothing to delete either
+					now := &metav1.Time{Time: r.clock.Now()}
+					replica.BackupPhase = kubermaticv1.BackupStatusPhaseFailed
+					replica.BackupMessage = "replication cancelled"
+					replica.BackupFinishedTime = now
+					replica.DeletePhase = kubermaticv1.BackupStatusPhaseCompleted
+					replica.DeleteFinishedTime = now
+					modified = true
+					continue
+				}
+
+				job := r.backupReplicationJob(backupConfig, cluster, backup, replica, source, destination)
+				if err := r.Create(ctx, job); err != nil && !kerrors.IsAlreadyExists(err) {
+					return nil, errors.Wrapf(err, "error creating replication job for backup %s", backup.BackupName)
+				}
+				replica.BackupPhase = kubermaticv1.BackupStatusPhaseRunning
+				modified = true
+				returnReconcile = minReconcile(returnReconcile, &reconcile.Result{RequeueAfter: assumedJobRuntime})
+			}
+		}
+	}
+
+	if modified {
+		if err := r.Update(ctx, backupConfig); err != nil {
+			return nil, errors.Wrap(err, "failed to update backup config")
+		}
+	}
+
+	return returnReconcile, nil
+}
+
+// replicaDestinationNames returns the sorted names of all destinations backups of the config have been replicated to.
+func replicaDestinationNames(backupConfig *kubermaticv1.EtcdBackupConfig) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, backup := range backupConfig.Status.CurrentBackups {
+		for _, replica := range backup.Replicas {
+			if !seen[replica.Destination] {
+				seen[replica.Destination] = true
+				names = append(names, replica.Destination)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// create any replica delete jobs that can be created, i.e. for all failed replicas and all completed replicas
+// not selected by the retention policy of their destination.
+func (r *Reconciler) startPendingReplicaDeleteJobs(ctx context.Context, backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster,
+	destinations map[string]*kubermaticv1.BackupDestination) (*reconcile.Result, error) {
+	// one-shot backups are not deleted until their backupConfig is deleted
+	if backupConfig.Spec.Schedule == "" && backupConfig.DeletionTimestamp == nil {
+		return nil, nil
+	}
+
+	modified := false
+	for _, destinationName := range replicaDestinationNames(backupConfig) {
+		var backupsToDelete []*kubermaticv1.BackupStatus
+		runningDeleteJobsCount := 0
+		for i := len(backupConfig.Status.CurrentBackups) - 1; i >= 0; i-- {
+			backup := &backupConfig.Status.CurrentBackups[i]
+			replica := getReplicaStatus(backup, destinationName)
+			if replica == nil {
+				continue
+			}
+			if replica.DeletePhase == kubermaticv1.BackupStatusPhaseRunning {
+				runningDeleteJobsCount++
+			}
+			if replica.BackupPhase == kubermaticv1.BackupStatusPhaseFailed && replica.DeletePhase == "" {
+				backupsToDelete = append(backupsToDelete, backup)
+			}
+		}
+		backupsToDelete = append(backupsToDelete, backupsToRetireFromReplica(backupConfig, destinationName)...)
+
+		for _, backup := range backupsToDelete {
+			if runningDeleteJobsCount < maxSimultaneousDeleteJobsPerConfig {
+				if err := r.createReplicaDeleteJob(ctx, backupConfig, cluster, backup, getReplicaStatus(backup, destinationName), destinations[destinationName]); err != nil {
+					return nil, err
+				}
+				runningDeleteJobsCount++
+				modified = true
+			}
+		}
+	}
+
+	if modified {
+		if err := r.Update(ctx, backupConfig); err != nil {
+			return nil, errors.Wrap(err, "failed to update backup config")
+		}
+		return &reconcile.Result{RequeueAfter: assumedJobRuntime}, nil
+	}
+
+	return nil, nil
+}
+
+func (r *Reconciler) createReplicaDeleteJob(ctx context.Context, backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster, backup *kubermaticv1.BackupStatus,
+	replica *kubermaticv1.BackupReplicaStatus, destination *kubermaticv1.BackupDestination) error {
+	switch {
+	case destination == nil:
+		// the destination has been removed from the Seed, there is no way to delete the replica anymore
+		replica.DeletePhase = kubermaticv1.BackupStatusPhaseCompleted
+		replica.DeleteMessage = "destination no longer exists"
+		replica.DeleteFinishedTime = &metav1.Time{Time: r.clock.Now()}
+	case r.deleteContainer != nil:
+		job := r.backupDeleteJob(backupConfig, cluster, backup.BackupName, replica.DeleteJobName, destination)
+		if err := r.Create(ctx, job); err != nil && !kerrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "error creating delete job for replica of backup %s in destination %s", backup.BackupName, replica.Destination)
+		}
+		replica.DeletePhase = kubermaticv1.BackupStatusPhaseRunning
+	default:
+		// no deleteContainer configured. Just mark deletion as finished immediately.
+		replica.DeletePhase = kubermaticv1.BackupStatusPhaseCompleted
+		replica.DeleteFinishedTime = &metav1.Time{Time: r.clock.Now()}
+	}
+	return nil
+}
+
+// update status of all replica delete jobs that have completed and are still marked as running,
+// failed or vanished jobs are restarted
+func (r *Reconciler) updateRunningReplicaDeleteJobs(ctx context.Context, backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster,
+	destinations map[string]*kubermaticv1.BackupDestination) (*reconcile.Result, error) {
+	var returnReconcile *reconcile.Result
+
+	modified := false
+	for i := range backupConfig.Status.CurrentBackups {
+		backup := &backupConfig.Status.CurrentBackups[i]
+		for j := range backup.Replicas {
+			replica := &backup.Replicas[j]
+			if replica.DeletePhase != kubermaticv1.BackupStatusPhaseRunning {
+				continue
+			}
+
+			restartMessage := ""
+			job := &batchv1.Job{}
+			err := r.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: replica.DeleteJobName}, job)
+			if err != nil {
+				if !kerrors.IsNotFound(err) {
+					return nil, errors.Wrapf(err, "error getting delete job for replica of backup %s in destination %s", backup.BackupName, replica.Destination)
+				}
+				restartMessage = "job was deleted, restarted it"
+			} else if cond := getJobConditionIfTrue(job, batchv1.JobComplete); cond != nil {
+				replica.DeletePhase = kubermaticv1.BackupStatusPhaseCompleted
+				replica.DeleteMessage = cond.Message
+				replica.DeleteFinishedTime = &cond.LastTransitionTime
+				modified = true
+				returnReconcile = minReconcile(returnReconcile, &reconcile.Result{RequeueAfter: succeededJobRetentionTime})
+				continue
+			} else if cond := getJobConditionIfTrue(job, batchv1.JobFailed); cond != nil {
+				if err := r.Delete(ctx, job, ctrlruntimeclient.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !kerrors.IsNotFound(err) {
+					return nil, errors.Wrapf(err, "failed to delete failed delete job %s", replica.DeleteJobName)
+				}
+				restartMessage = fmt.Sprintf("Job failed: %s. Restarted.", cond.Message)
+			} else {
+				// job still running
+				returnReconcile = minReconcile(returnReconcile, &reconcile.Result{RequeueAfter: assumedJobRuntime})
+				continue
+			}
+
+			if err := r.createReplicaDeleteJob(ctx, backupConfig, cluster, backup, replica, destinations[replica.Destination]); err != nil {
+				return nil, err
+			}
+			if replica.DeletePhase == kubermaticv1.BackupStatusPhaseRunning {
+				replica.DeleteMessage = restartMessage
+			}
+			modified = true
+			returnReconcile = minReconcile(returnReconcile, &reconcile.Result{RequeueAfter: assumedJobRuntime})
+		}
+	}
+
+	if modified {
+		if err := r.Update(ctx, backupConfig); err != nil {
+			return nil, errors.Wrap(err, "failed to update backup config")
+		}
+	}
+
+	return returnReconcile, nil
+}
+
+// deleteFinishedReplicaJobs deletes the replication and delete jobs of the replicas of a backup once they
+// have been finished for a while. It returns true if the backup has been deleted from all replica
+// destinations and all of these jobs are gone.
+func (r *Reconciler) deleteFinishedReplicaJobs(ctx context.Context, backupConfig *kubermaticv1.EtcdBackupConfig, backup *kubermaticv1.BackupStatus) (bool, *reconcile.Result, error) {
+	var returnReconcile *reconcile.Result
+
+	allDeleted := true
+	for _, replica := range backup.Replicas {
+		replicationJobDeleted := replica.BackupFinishedTime == nil && replica.BackupPhase == ""
+		if replica.BackupFinishedTime != nil {
+			var retentionTime time.Duration
+			switch {
+			case backupConfig.DeletionTimestamp != nil:
+				retentionTime = 0
+			case replica.BackupPhase == kubermaticv1.BackupStatusPhaseCompleted:
+				retentionTime = succeededJobRetentionTime
+			default:
+				retentionTime = failedJobRetentionTime
+			}
+
+			if age := r.clock.Now().Sub(replica.BackupFinishedTime.Time); age < retentionTime {
+				returnReconcile = minReconcile(returnReconcile, &reconcile.Result{RequeueAfter: retentionTime - age})
+			} else {
+				if err := r.deleteJob(ctx, replica.JobName); err != nil {
+					return false, nil, errors.Wrapf(err, "backup %s: failed to delete replication job %s", backup.BackupName, replica.JobName)
+				}
+				replicationJobDeleted = true
+			}
+		}
+
+		deleteJobDeleted := false
+		if replica.DeleteFinishedTime != nil {
+			var retentionTime time.Duration
+			if backupConfig.DeletionTimestamp == nil {
+				retentionTime = succeededJobRetentionTime
+			}
+
+			if age := r.clock.Now().Sub(replica.DeleteFinishedTime.Time); age < retentionTime {
+				returnReconcile = minReconcile(returnReconcile, &reconcile.Result{RequeueAfter: retentionTime - age})
+			} else {
+				if err := r.deleteJob(ctx, replica.DeleteJobName); err != nil {
+					return false, nil, errors.Wrapf(err, "backup %s: failed to delete job %s", backup.BackupName, replica.DeleteJobName)
+				}
+				deleteJobDeleted = true
+			}
+		}
+
+		if !replicationJobDeleted || !deleteJobDeleted {
+			allDeleted = false
+		}
+	}
+
+	return allDeleted, returnReconcile, nil
+}
+
+// deleteJob deletes the job with the given name in the kube-system namespace, if it exists.
+func (r *Reconciler) deleteJob(ctx context.Context, name string) error {
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: name}, job)
+	if kerrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := r.Delete(ctx, job, ctrlruntimeclient.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// backupReplicationJob downloads the backup from the source destination into the shared volume and
+// uploads it to the replica destination using the regular store container. Encrypted backups are
+// decrypted after downloading and encrypted with the replica destination's key before uploading.
+func (r *Reconciler) backupReplicationJob(backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster, backupStatus *kubermaticv1.BackupStatus,
+	replica *kubermaticv1.BackupReplicaStatus, source, destination *kubermaticv1.BackupDestination) *batchv1.Job {
+	downloader := corev1.Container{
+		Name:  "backup-downloader",
+		Image: r.etcdLauncherImageWithTag(),
+		Command: []string{
+			"/etcd-backup-downloader",
+			"-cluster", cluster.Name,
+			"-backup", backupStatus.BackupName,
+			"-endpoint", fmt.Sprintf("$(%s)", backupEndpointEnvVarKey),
+			"-bucket", fmt.Sprintf("$(%s)", bucketNameEnvVarKey),
+			"-ca-bundle", "/etc/ca-bundle/" + resources.CABundleConfigMapKey,
+			"-file", "/backup/snapshot.db",
+		},
+		Env: storeEnvVars(source),
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      SharedVolumeName,
+				MountPath: "/backup",
+			},
+			{
+				Name:      "ca-bundle",
+				MountPath: "/etc/ca-bundle/",
+				ReadOnly:  true,
+			},
+		},
+	}
+
+	job := r.jobBase(backupConfig, cluster, replica.JobName)
+	// downloading and uploading takes longer than just uploading a snapshot
+	job.Spec.ActiveDeadlineSeconds = resources.Int64(10 * 60)
+
+	job.Spec.Template.Spec.Volumes = []corev1.Volume{
+		{
+			Name: SharedVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+		{
+			Name: "ca-bundle",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: caBundleConfigMapName(cluster),
+					},
+				},
+			},
+		},
+	}
+
+	downloader.Command = append(downloader.Command, storeArgs(source, sourceStoreMountPath)...)
+	addStoreVolume(source, sourceStoreVolumeName, sourceStoreMountPath, &downloader, &job.Spec.Template.Spec)
+
+	if isEncryptionEnabled(source) {
+		downloader.Command = append(downloader.Command, "-encryption-keys", sourceEncryptionKeysMountPath)
+		downloader.VolumeMounts = append(downloader.VolumeMounts, corev1.VolumeMount{
+			Name:      sourceEncryptionKeysVolumeName,
+			MountPath: sourceEncryptionKeysMountPath,
+			ReadOnly:  true,
+		})
+		sourceKeys := encryptionKeysVolume(source)
+		sourceKeys.Name = sourceEncryptionKeysVolumeName
+		job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, sourceKeys)
+	}
+
+	storeContainer := r.backupStoreContainer(backupConfig, cluster, backupStatus, destination)
+	if replicaSpec := getReplicaSpec(backupConfig, replica.Destination); replicaSpec != nil && (replicaSpec.Keep != nil || replicaSpec.Retention != nil) {
+		storeContainer.Env = setEnvVar(storeContainer.Env, corev1.EnvVar{
+			Name:  backupKeepCountEnvVarKey,
+			Value: strconv.Itoa(replicaSpec.GetKeptBackupsCount()),
+		})
+	}
+
+	job.Spec.Template.Spec.InitContainers = []corev1.Container{downloader}
+	job.Spec.Template.Spec.Containers = []corev1.Container{*storeContainer}
+
+	if isEncryptionEnabled(destination) {
+		job.Spec.Template.Spec.InitContainers = append(job.Spec.Template.Spec.InitContainers, r.backupEncrypterContainer(destination))
+		job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, encryptionKeysVolume(destination))
+	}
+
+	addStoreVolume(destination, storeVolumeName, resources.EtcdBackupFilesystemMountPath, &job.Spec.Template.Spec.Containers[0], &job.Spec.Template.Spec)
+
+	return job
+}
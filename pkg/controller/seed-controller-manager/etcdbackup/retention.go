@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdbackup
+
+import (
+	"time"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/etcd/retention"
+)
+
+// retentionPolicy returns the policy deciding which completed backups of the given config are kept.
+// Without a grandfather-father-son policy, the last GetKeptBackupsCount() backups are kept.
+func retentionPolicy(backupConfig *kubermaticv1.EtcdBackupConfig) retention.Policy {
+	return newRetentionPolicy(backupConfig.GetKeptBackupsCount(), backupConfig.Spec.Keep != nil, backupConfig.Spec.Retention)
+}
+
+// replicaRetentionPolicy returns the policy deciding which backups are kept in a replica destination.
+// Replicas without retention settings of their own use the policy of the backup config.
+func replicaRetentionPolicy(backupConfig *kubermaticv1.EtcdBackupConfig, replica *kubermaticv1.EtcdBackupReplica) retention.Policy {
+	if replica.Keep == nil && replica.Retention == nil {
+		return retentionPolicy(backupConfig)
+	}
+	return newRetentionPolicy(replica.GetKeptBackupsCount(), replica.Keep != nil, replica.Retention)
+}
+
+func newRetentionPolicy(keep int, keepSet bool, r *kubermaticv1.EtcdBackupRetention) retention.Policy {
+	policy := retention.Policy{
+		Last: keep,
+	}
+
+	if r != nil {
+		if !keepSet {
+			policy.Last = 0
+		}
+		policy.Hourly = limitRetentionCount(r.Hourly)
+		policy.Daily = limitRetentionCount(r.Daily)
+		policy.Weekly = limitRetentionCount(r.Weekly)
+		policy.Monthly = limitRetentionCount(r.Monthly)
+	}
+
+	return policy
+}
+
+// maxKeptBackups returns the maximum number of backups kept by any of the destinations of the backup config.
+func maxKeptBackups(backupConfig *kubermaticv1.EtcdBackupConfig) int {
+	max := retentionPolicy(backupConfig).MaxKept()
+	for i := range backupConfig.Spec.Replicas {
+		if kept := replicaRetentionPolicy(backupConfig, &backupConfig.Spec.Replicas[i]).MaxKept(); kept > max {
+			max = kept
+		}
+	}
+	return max
+}
+
+func limitRetentionCount(count int) int {
+	if count < 0 {
+		return 0
+	}
+	if count > kubermaticv1.MaxKeptBackupsCount {
+		return kubermaticv1.MaxKeptBackupsCount
+	}
+	return count
+}
+
+// backupsToRetire returns the completed backups which are no longer selected by the retention policy
+// of the backup config and have not been deleted yet.
+func backupsToRetire(backupConfig *kubermaticv1.EtcdBackupConfig) []*kubermaticv1.BackupStatus {
+	var (
+		completed []*kubermaticv1.BackupStatus
+		times     []time.Time
+	)
+	for i := len(backupConfig.Status.CurrentBackups) - 1; i >= 0; i-- {
+		backup := &backupConfig.Status.CurrentBackups[i]
+		if backup.BackupPhase == kubermaticv1.BackupStatusPhaseCompleted && backup.DeletePhase == "" {
+			completed = append(completed, backup)
+			times = append(times, backup.ScheduledTime.Time)
+		}
+	}
+
+	// a deleted backup config keeps nothing
+	if backupConfig.DeletionTimestamp != nil {
+		return completed
+	}
+
+	var retired []*kubermaticv1.BackupStatus
+	for i, keep := range retentionPolicy(backupConfig).Keep(times) {
+		if !keep {
+			retired = append(retired, completed[i])
+		}
+	}
+
+	return retired
+}
+
+// backupsToRetireFromReplica returns the backups whose replica in the given destination is no longer selected
+// by the destination's retention policy and has not been deleted yet. If the destination is no longer a
+// replica destination of the backup config, all of its replicas are retired.
+func backupsToRetireFromReplica(backupConfig *kubermaticv1.EtcdBackupConfig, destination string) []*kubermaticv1.BackupStatus {
+	var (
+		completed []*kubermaticv1.BackupStatus
+		times     []time.Time
+	)
+	for i := len(backupConfig.Status.CurrentBackups) - 1; i >= 0; i-- {
+		backup := &backupConfig.Status.CurrentBackups[i]
+		replica := getReplicaStatus(backup, destination)
+		if replica != nil && replica.BackupPhase == kubermaticv1.BackupStatusPhaseCompleted && replica.DeletePhase == "" {
+			completed = append(completed, backup)
+			times = append(times, backup.ScheduledTime.Time)
+		}
+	}
+
+	replicaSpec := getReplicaSpec(backupConfig, destination)
+	if backupConfig.DeletionTimestamp != nil || replicaSpec == nil {
+		return completed
+	}
+
+	var retired []*kubermaticv1.BackupStatus
+	for i, keep := range replicaRetentionPolicy(backupConfig, replicaSpec).Keep(times) {
+		if !keep {
+			retired = append(retired, completed[i])
+		}
+	}
+
+	return retired
+}
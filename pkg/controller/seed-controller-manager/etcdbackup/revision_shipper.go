@@ -0,0 +1,216 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdbackup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"k8c.io/kubermatic/v2/pkg/controller/operator/common"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/etcd"
+	"k8c.io/kubermatic/v2/pkg/resources/reconciling"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilpointer "k8s.io/utils/pointer"
+)
+
+const (
+	// revisionShipperLabel defines the label we use on all revision shipper deployments
+	revisionShipperLabel = "kubermatic-etcd-revision-shipper"
+)
+
+func revisionShipperName(backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster) string {
+	return fmt.Sprintf("%s-backup-%s-revision-shipper", cluster.Name, backupConfig.Name)
+}
+
+// ensureRevisionShipper makes sure that a revision shipper is running for backup configs with continuous
+// backups enabled and that it is removed once continuous backups are disabled or the config is deleted.
+func (r *Reconciler) ensureRevisionShipper(ctx context.Context, backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster,
+	destination *kubermaticv1.BackupDestination) error {
+	if !backupConfig.IsContinuousBackupEnabled() || backupConfig.DeletionTimestamp != nil || cluster.DeletionTimestamp != nil {
+		deployment := &appsv1.Deployment{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: revisionShipperName(backupConfig, cluster)}, deployment)
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to get revision shipper deployment")
+		}
+		if err := r.Delete(ctx, deployment); err != nil && !kerrors.IsNotFound(err) {
+			return errors.Wrap(err, "failed to delete revision shipper deployment")
+		}
+		return nil
+	}
+
+	creators := []reconciling.NamedDeploymentCreatorGetter{
+		r.revisionShipperDeploymentCreator(backupConfig, cluster, destination),
+	}
+
+	return reconciling.ReconcileDeployments(ctx, creators, metav1.NamespaceSystem, r.Client, common.OwnershipModifierFactory(cluster, r.scheme))
+}
+
+func (r *Reconciler) revisionShipperDeploymentCreator(backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster,
+	destination *kubermaticv1.BackupDestination) reconciling.NamedDeploymentCreatorGetter {
+	return func() (string, reconciling.DeploymentCreator) {
+		return revisionShipperName(backupConfig, cluster), func(d *appsv1.Deployment) (*appsv1.Deployment, error) {
+			labels := map[string]string{
+				resources.AppLabelKey:     revisionShipperLabel,
+				resources.ClusterLabelKey: cluster.Name,
+				BackupConfigNameLabelKey:  backupConfig.Name,
+			}
+
+			d.Labels = labels
+			d.Spec.Replicas = utilpointer.Int32Ptr(1)
+			d.Spec.Selector = &metav1.LabelSelector{MatchLabels: labels}
+			// never run two shippers at the same time, they would upload overlapping segments
+			d.Spec.Strategy = appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+			d.Spec.Template.Labels = labels
+
+			d.Spec.Template.Spec.Containers = []corev1.Container{
+				{
+					Name:  "revision-shipper",
+					Image: r.etcdLauncherImageWithTag(),
+					Command: []string{
+						"/etcd-revision-shipper",
+						"-cluster", cluster.Name,
+						"-etcd-endpoints", strings.Join(etcd.GetClientEndpoints(cluster.Status.NamespaceName), ","),
+						"-etcd-ca-file", "/etc/etcd/client/ca.crt",
+						"-etcd-cert-file", "/etc/etcd/client/backup-etcd-client.crt",
+						"-etcd-key-file", "/etc/etcd/client/backup-etcd-client.key",
+						"-endpoint", fmt.Sprintf("$(%s)", backupEndpointEnvVarKey),
+						"-bucket", fmt.Sprintf("$(%s)", bucketNameEnvVarKey),
+						"-ca-bundle", "/etc/ca-bundle/" + resources.CABundleConfigMapKey,
+						"-flush-interval", backupConfig.GetContinuousBackupFlushInterval().String(),
+						"-retention", backupConfig.GetContinuousBackupRetention().String(),
+					},
+					Env: storeEnvVars(destination),
+					VolumeMounts: []corev1.VolumeMount{
+						{
+							Name:      r.getEtcdSecretName(cluster),
+							MountPath: "/etc/etcd/client",
+							ReadOnly:  true,
+						},
+						{
+							Name:      "ca-bundle",
+							MountPath: "/etc/ca-bundle/",
+							ReadOnly:  true,
+						},
+					},
+				},
+			}
+
+			d.Spec.Template.Spec.Volumes = []corev1.Volume{
+				{
+					Name: r.getEtcdSecretName(cluster),
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: r.getEtcdSecretName(cluster),
+						},
+					},
+				},
+				{
+					Name: "ca-bundle",
+					VolumeSource: corev1.VolumeSource{
+						ConfigMap: &corev1.ConfigMapVolumeSource{
+							LocalObjectReference: corev1.LocalObjectReference{
+								Name: caBundleConfigMapName(cluster),
+							},
+						},
+					},
+				},
+			}
+
+			container := &d.Spec.Template.Spec.Containers[0]
+			container.Command = append(container.Command, storeArgs(destination, resources.EtcdBackupFilesystemMountPath)...)
+			addStoreVolume(destination, storeVolumeName, resources.EtcdBackupFilesystemMountPath, container, &d.Spec.Template.Spec)
+
+			if isEncryptionEnabled(destination) {
+				container.Command = append(container.Command,
+					"-encryption-keys", encryptionKeysMountPath,
+					"-encryption-key-id", destination.Encryption.ActiveKey,
+				)
+				container.VolumeMounts = append(container.VolumeMounts, encryptionKeysVolumeMount())
+				d.Spec.Template.Spec.Volumes = append(d.Spec.Template.Spec.Volumes, encryptionKeysVolume(destination))
+			}
+
+			return d, nil
+		}
+	}
+}
+
+func (r *Reconciler) etcdLauncherImageWithTag() string {
+	image := r.etcdLauncherImage
+	if !strings.Contains(image, ":") {
+		image = image + ":" + r.versions.Kubermatic
+	}
+	return image
+}
+
+// s3EnvVars returns the environment variables holding the S3 credentials, bucket and endpoint
+// of the destination, or of the legacy destination if no destination is given.
+func s3EnvVars(destination *kubermaticv1.BackupDestination) []corev1.EnvVar {
+	if destination != nil {
+		return []corev1.EnvVar{
+			genSecretEnvVar(accessKeyIdEnvVarKey, accessKeyIdEnvVarKey, destination),
+			genSecretEnvVar(secretAccessKeyEnvVarKey, secretAccessKeyEnvVarKey, destination),
+			{Name: bucketNameEnvVarKey, Value: destination.BucketName},
+			{Name: backupEndpointEnvVarKey, Value: destination.Endpoint},
+		}
+	}
+
+	return []corev1.EnvVar{
+		legacySecretEnvVar(accessKeyIdEnvVarKey, resources.EtcdBackupAndRestoreS3AccessKeyIDKey),
+		legacySecretEnvVar(secretAccessKeyEnvVarKey, resources.EtcdBackupAndRestoreS3SecretKeyAccessKeyKey),
+		legacyConfigMapEnvVar(bucketNameEnvVarKey, resources.EtcdRestoreS3BucketNameKey),
+		legacyConfigMapEnvVar(backupEndpointEnvVarKey, resources.EtcdRestoreS3EndpointKey),
+	}
+}
+
+// legacySecretEnvVar references a key in the backup-s3 secret used by the legacy backup destination.
+func legacySecretEnvVar(name, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: resources.EtcdRestoreS3CredentialsSecret},
+				Key:                  key,
+			},
+		},
+	}
+}
+
+// legacyConfigMapEnvVar references a key in the s3-settings configmap used by the legacy backup destination.
+func legacyConfigMapEnvVar(name, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: resources.EtcdRestoreS3SettingsConfigMap},
+				Key:                  key,
+			},
+		},
+	}
+}
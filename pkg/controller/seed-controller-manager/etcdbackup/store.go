@@ -0,0 +1,143 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdbackup
+
+import (
+	"fmt"
+
+	"k8c.io/kubermatic/v2/pkg/backupstore"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	storeVolumeName       = "backup-store"
+	sourceStoreVolumeName = "source-backup-store"
+	sourceStoreMountPath  = "/source-backup-store"
+)
+
+// usesBuiltinStore returns true if backups in the destination can't be handled by the configured
+// store and delete containers, which only support S3, and etcd-backup-store has to be used instead.
+func usesBuiltinStore(destination *kubermaticv1.BackupDestination) bool {
+	return destination != nil && destination.GetType() != kubermaticv1.BackupDestinationTypeS3
+}
+
+// validateDestination checks the type specific settings of the destination, credentials are checked by the caller.
+func validateDestination(destination *kubermaticv1.BackupDestination) error {
+	switch destination.GetType() {
+	case kubermaticv1.BackupDestinationTypeS3, kubermaticv1.BackupDestinationTypeAzureBlob, kubermaticv1.BackupDestinationTypeGCS:
+		return nil
+	case kubermaticv1.BackupDestinationTypeFilesystem:
+		if _, err := resources.BackupDestinationVolumeSource(destination); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown type %q", destination.Type)
+	}
+	return nil
+}
+
+// storeEnvVars returns the environment variables holding the type, credentials, bucket and endpoint
+// of the destination as expected by the backup tools, or those of the legacy destination if no
+// destination is given.
+func storeEnvVars(destination *kubermaticv1.BackupDestination) []corev1.EnvVar {
+	if !usesBuiltinStore(destination) {
+		return s3EnvVars(destination)
+	}
+
+	// bucket and endpoint are always set, as they are referenced in the tools' arguments
+	envVars := []corev1.EnvVar{
+		{Name: backupstore.TypeEnvVar, Value: string(destination.GetType())},
+		{Name: bucketNameEnvVarKey, Value: destination.BucketName},
+		{Name: backupEndpointEnvVarKey, Value: destination.Endpoint},
+	}
+
+	switch destination.GetType() {
+	case kubermaticv1.BackupDestinationTypeAzureBlob:
+		envVars = append(envVars,
+			genSecretEnvVar(backupstore.AzureAccountNameKey, backupstore.AzureAccountNameKey, destination),
+			genSecretEnvVar(backupstore.AzureAccountKeyKey, backupstore.AzureAccountKeyKey, destination),
+		)
+	case kubermaticv1.BackupDestinationTypeGCS:
+		envVars = append(envVars, genSecretEnvVar(backupstore.GCSServiceAccountKey, backupstore.GCSServiceAccountKey, destination))
+	}
+
+	return envVars
+}
+
+// storeArgs returns the arguments pointing the backup tools to the destination's volume mounted at the
+// given path, which is only needed for filesystem destinations.
+func storeArgs(destination *kubermaticv1.BackupDestination, mountPath string) []string {
+	if destination == nil || destination.GetType() != kubermaticv1.BackupDestinationTypeFilesystem {
+		return nil
+	}
+	return []string{"-path", mountPath}
+}
+
+// addStoreVolume mounts the volume of filesystem destinations into the container. Destinations
+// are validated before any job is created, so a misconfigured volume is not expected here.
+func addStoreVolume(destination *kubermaticv1.BackupDestination, volumeName, mountPath string, container *corev1.Container, podSpec *corev1.PodSpec) {
+	if destination == nil || destination.GetType() != kubermaticv1.BackupDestinationTypeFilesystem {
+		return
+	}
+
+	volumeSource, err := resources.BackupDestinationVolumeSource(destination)
+	if err != nil {
+		return
+	}
+
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      volumeName,
+		MountPath: mountPath,
+	})
+
+	for _, volume := range podSpec.Volumes {
+		if volume.Name == volumeName {
+			return
+		}
+	}
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name:         volumeName,
+		VolumeSource: *volumeSource,
+	})
+}
+
+// builtinStoreContainer returns an etcd-backup-store container running the given command
+// (upload, delete or delete-all) for the backup of the cluster in the destination.
+func (r *Reconciler) builtinStoreContainer(name, command string, cluster *kubermaticv1.Cluster, backupName string,
+	destination *kubermaticv1.BackupDestination) *corev1.Container {
+	args := []string{
+		"/etcd-backup-store",
+		"-cluster", cluster.Name,
+		"-endpoint", fmt.Sprintf("$(%s)", backupEndpointEnvVarKey),
+		"-bucket", fmt.Sprintf("$(%s)", bucketNameEnvVarKey),
+	}
+	if backupName != "" {
+		args = append(args, "-backup", backupName)
+	}
+	args = append(args, storeArgs(destination, resources.EtcdBackupFilesystemMountPath)...)
+	args = append(args, command)
+
+	return &corev1.Container{
+		Name:    name,
+		Image:   r.etcdLauncherImageWithTag(),
+		Command: args,
+		Env:     storeEnvVars(destination),
+	}
+}
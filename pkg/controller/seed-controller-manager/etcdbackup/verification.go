@@ -0,0 +1,252 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdbackup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/etcd/verification"
+	"k8c.io/kubermatic/v2/pkg/resources"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilpointer "k8s.io/utils/pointer"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	verifierContainerName = "backup-verifier"
+
+	// jobNameLabelKey is the label the job controller puts on all pods of a job
+	jobNameLabelKey = "job-name"
+)
+
+// create verification jobs for completed backups if verification is enabled
+// and update the status of backups whose verification jobs have finished
+func (r *Reconciler) updateBackupVerifications(ctx context.Context, backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster,
+	destination *kubermaticv1.BackupDestination) (*reconcile.Result, error) {
+	var returnReconcile *reconcile.Result
+
+	modified := false
+	for i := range backupConfig.Status.CurrentBackups {
+		backup := &backupConfig.Status.CurrentBackups[i]
+
+		switch {
+		case backup.VerificationPhase == kubermaticv1.BackupStatusPhaseRunning:
+			job := &batchv1.Job{}
+			err := r.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: backup.VerificationJobName}, job)
+			if err != nil {
+				if !kerrors.IsNotFound(err) {
+					return nil, errors.Wrapf(err, "error getting verification job for backup %s", backup.BackupName)
+				}
+				backup.VerificationPhase = kubermaticv1.BackupStatusPhaseFailed
+				backup.VerificationMessage = "verification job deleted externally"
+				backup.VerificationFinishedTime = &metav1.Time{Time: r.clock.Now()}
+				modified = true
+				continue
+			}
+
+			if cond := getJobConditionIfTrue(job, batchv1.JobComplete); cond != nil {
+				message, err := r.getVerifierTerminationMessage(ctx, job)
+				if err != nil {
+					return nil, errors.Wrapf(err, "error getting verification result for backup %s", backup.BackupName)
+				}
+				result, err := verification.Decode(message)
+				if err != nil {
+					backup.VerificationPhase = kubermaticv1.BackupStatusPhaseFailed
+					backup.VerificationMessage = err.Error()
+				} else {
+					backup.VerificationPhase = kubermaticv1.BackupStatusPhaseCompleted
+					backup.VerificationMessage = cond.Message
+					backup.SnapshotRevision = result.Revision
+					backup.SnapshotKeyCount = result.KeyCount
+					backup.SnapshotHash = result.Hash
+				}
+				backup.VerificationFinishedTime = &cond.LastTransitionTime
+				modified = true
+			} else if cond := getJobConditionIfTrue(job, batchv1.JobFailed); cond != nil {
+				message, err := r.getVerifierTerminationMessage(ctx, job)
+				if err != nil {
+					return nil, errors.Wrapf(err, "error getting verification result for backup %s", backup.BackupName)
+				}
+				if message == "" {
+					message = cond.Message
+				}
+				backup.VerificationPhase = kubermaticv1.BackupStatusPhaseFailed
+				backup.VerificationMessage = message
+				backup.VerificationFinishedTime = &cond.LastTransitionTime
+				modified = true
+			} else {
+				// job still running
+				returnReconcile = minReconcile(returnReconcile, &reconcile.Result{RequeueAfter: assumedJobRuntime})
+				continue
+			}
+
+			if backup.VerificationPhase == kubermaticv1.BackupStatusPhaseFailed {
+				r.recorder.Eventf(backupConfig, corev1.EventTypeWarning, "BackupVerificationFailed",
+					"verification of backup %s failed: %s", backup.BackupName, backup.VerificationMessage)
+			}
+
+		case backup.VerificationPhase == "" && backup.BackupPhase == kubermaticv1.BackupStatusPhaseCompleted && backup.DeletePhase == "" &&
+			backupConfig.IsVerificationEnabled() && backupConfig.DeletionTimestamp == nil:
+			backup.VerificationJobName = r.limitNameLength(fmt.Sprintf("%s-backup-%s-verify-%s", cluster.Name, backupConfig.Name, r.randStringGenerator()))
+
+			job := r.backupVerificationJob(backupConfig, cluster, backup, destination)
+			if err := r.Create(ctx, job); err != nil && !kerrors.IsAlreadyExists(err) {
+				return nil, errors.Wrapf(err, "error creating verification job for backup %s", backup.BackupName)
+			}
+			backup.VerificationPhase = kubermaticv1.BackupStatusPhaseRunning
+			modified = true
+			returnReconcile = minReconcile(returnReconcile, &reconcile.Result{RequeueAfter: assumedJobRuntime})
+		}
+	}
+
+	if modified {
+		if err := r.Update(ctx, backupConfig); err != nil {
+			return nil, errors.Wrap(err, "failed to update backup config")
+		}
+	}
+
+	return returnReconcile, nil
+}
+
+// getVerifierTerminationMessage returns the termination message of the most recently
+// terminated verifier container of the job, which contains the verification result.
+func (r *Reconciler) getVerifierTerminationMessage(ctx context.Context, job *batchv1.Job) (string, error) {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, ctrlruntimeclient.InNamespace(job.Namespace), ctrlruntimeclient.MatchingLabels{jobNameLabelKey: job.Name}); err != nil {
+		return "", err
+	}
+
+	var terminated []*corev1.ContainerStateTerminated
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name == verifierContainerName && status.State.Terminated != nil {
+				terminated = append(terminated, status.State.Terminated)
+			}
+		}
+	}
+	if len(terminated) == 0 {
+		return "", nil
+	}
+
+	sort.Slice(terminated, func(i, j int) bool {
+		return terminated[i].FinishedAt.Before(&terminated[j].FinishedAt)
+	})
+
+	return terminated[len(terminated)-1].Message, nil
+}
+
+// deleteVerificationJob deletes the verification job of a backup once the verification
+// has finished. It returns true if the job is gone.
+func (r *Reconciler) deleteVerificationJob(ctx context.Context, backupConfig *kubermaticv1.EtcdBackupConfig, backup *kubermaticv1.BackupStatus) (bool, error) {
+	if backup.VerificationJobName == "" {
+		return true, nil
+	}
+	if backup.VerificationPhase == kubermaticv1.BackupStatusPhaseRunning && backupConfig.DeletionTimestamp == nil {
+		return false, nil
+	}
+
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: backup.VerificationJobName}, job)
+	switch {
+	case kerrors.IsNotFound(err):
+		return true, nil
+	case err != nil:
+		return false, errors.Wrapf(err, "backup %s: failed to get verification job %s", backup.BackupName, backup.VerificationJobName)
+	}
+
+	if err := r.Delete(ctx, job, ctrlruntimeclient.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !kerrors.IsNotFound(err) {
+		return false, errors.Wrapf(err, "backup %s: failed to delete verification job %s", backup.BackupName, backup.VerificationJobName)
+	}
+
+	return true, nil
+}
+
+func (r *Reconciler) backupVerificationJob(backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster, backupStatus *kubermaticv1.BackupStatus,
+	destination *kubermaticv1.BackupDestination) *batchv1.Job {
+	command := []string{
+		"/etcd-backup-verifier",
+		"-cluster", cluster.Name,
+		"-backup", backupStatus.BackupName,
+		"-endpoint", fmt.Sprintf("$(%s)", backupEndpointEnvVarKey),
+		"-bucket", fmt.Sprintf("$(%s)", bucketNameEnvVarKey),
+		"-ca-bundle", "/etc/ca-bundle/" + resources.CABundleConfigMapKey,
+	}
+	command = append(command, storeArgs(destination, resources.EtcdBackupFilesystemMountPath)...)
+	if backupConfig.Spec.Verification.Restore {
+		command = append(command, "-restore")
+	}
+	if isEncryptionEnabled(destination) {
+		command = append(command, "-encryption-keys", encryptionKeysMountPath)
+	}
+
+	job := r.jobBase(backupConfig, cluster, backupStatus.VerificationJobName)
+	// verification failures are usually not transient, so don't retry for long and
+	// keep the failed pods around to read the verifier's termination message
+	job.Spec.BackoffLimit = utilpointer.Int32Ptr(1)
+	job.Spec.ActiveDeadlineSeconds = resources.Int64(10 * 60)
+	job.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyNever
+
+	job.Spec.Template.Spec.Containers = []corev1.Container{
+		{
+			Name:                     verifierContainerName,
+			Image:                    r.etcdLauncherImageWithTag(),
+			Command:                  command,
+			Env:                      storeEnvVars(destination),
+			TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+			VolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      "ca-bundle",
+					MountPath: "/etc/ca-bundle/",
+					ReadOnly:  true,
+				},
+			},
+		},
+	}
+
+	job.Spec.Template.Spec.Volumes = []corev1.Volume{
+		{
+			Name: "ca-bundle",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: caBundleConfigMapName(cluster),
+					},
+				},
+			},
+		},
+	}
+
+	if isEncryptionEnabled(destination) {
+		job.Spec.Template.Spec.Containers[0].VolumeMounts = append(job.Spec.Template.Spec.Containers[0].VolumeMounts, encryptionKeysVolumeMount())
+		job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, encryptionKeysVolume(destination))
+	}
+
+	addStoreVolume(destination, storeVolumeName, resources.EtcdBackupFilesystemMountPath, &job.Spec.Template.Spec.Containers[0], &job.Spec.Template.Spec)
+
+	return job
+}
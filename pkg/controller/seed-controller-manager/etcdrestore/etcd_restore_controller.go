@@ -18,16 +18,21 @@ package etcdrestore
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"reflect"
 	"time"
 
-	"github.com/minio/minio-go"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
+	"k8c.io/kubermatic/v2/pkg/backupstore"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1/helper"
+	"k8c.io/kubermatic/v2/pkg/etcd/encryption"
+	"k8c.io/kubermatic/v2/pkg/etcd/revisions"
 	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
 	"k8c.io/kubermatic/v2/pkg/provider"
 	"k8c.io/kubermatic/v2/pkg/resources"
@@ -175,30 +180,55 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, rest
 		}
 	}
 
-	var destination *kubermaticv1.BackupDestination
-	if restore.Spec.Destination != "" {
-		if seed.Spec.EtcdBackupRestore == nil {
-			return nil, errors.Errorf("can't find backup restore destination %q in Seed %q", restore.Spec.Destination, seed.Name)
-		}
-		var ok bool
-		destination, ok = seed.Spec.EtcdBackupRestore.Destinations[restore.Spec.Destination]
-		if !ok {
-			return nil, errors.Errorf("can't find backup restore destination %q in Seed %q", restore.Spec.Destination, seed.Name)
-		}
-		if destination.Credentials == nil {
-			return nil, errors.Errorf("credentials not set for backup destination %q in Seed %q", restore.Spec.Destination, seed.Name)
-		}
+	objectName := fmt.Sprintf("%s-%s", restore.BackupClusterName(), restore.Spec.BackupName)
+
+	destination, err := r.getRestoreDestination(ctx, log, restore, cluster, seed, objectName)
+	if err != nil {
+		return nil, err
 	}
 
 	// check that the backup to restore from exists and is accessible
-	s3Client, bucketName, err := resources.GetEtcdRestoreS3Client(ctx, restore, true, r.Client, cluster, destination)
+	store, err := resources.GetEtcdRestoreBackupStore(ctx, restore, true, r.Client, cluster, destination)
 	if err != nil {
-		return nil, fmt.Errorf("failed to obtain S3 client: %w", err)
+		return nil, fmt.Errorf("failed to obtain backup store client: %w", err)
 	}
 
-	objectName := fmt.Sprintf("%s-%s", cluster.GetName(), restore.Spec.BackupName)
-	if _, err := s3Client.StatObject(bucketName, objectName, minio.StatObjectOptions{}); err != nil {
-		return nil, fmt.Errorf("could not access backup object %s: %w", objectName, err)
+	// filesystem destinations are only mounted into the etcd pods, which report a missing backup themselves
+	if isAccessibleFromController(destination) {
+		if _, err := store.Stat(ctx, objectName); err != nil {
+			return nil, fmt.Errorf("could not access backup object %s: %w", objectName, err)
+		}
+
+		// for encrypted backups, make sure that the key they were encrypted with is still available,
+		// etcd-launcher would otherwise only notice after the etcd statefulset has been deleted
+		if err := r.checkBackupEncryptionKey(ctx, store, objectName, restore, cluster); err != nil {
+			return nil, err
+		}
+	}
+
+	// for point-in-time restores, make sure that revisions have been shipped at all;
+	// whether they cover the requested target can only be checked once the backup has
+	// been downloaded and its revision is known
+	if restore.IsPointInTimeRestore() {
+		if restore.Spec.RestoreToTime != nil && restore.Spec.RestoreToRevision > 0 {
+			return nil, errors.New("restoreToTime and restoreToRevision are mutually exclusive")
+		}
+
+		// like the backup itself, revisions in filesystem destinations can only be checked by etcd-launcher
+		if isAccessibleFromController(destination) {
+			segments, err := revisions.ListSegments(ctx, store, restore.BackupClusterName())
+			if err != nil {
+				return nil, fmt.Errorf("failed to list shipped revisions: %w", err)
+			}
+			if len(segments) == 0 {
+				return nil, fmt.Errorf("no shipped revisions found for cluster %s, is continuous backup enabled?", restore.BackupClusterName())
+			}
+		}
+	}
+
+	restoreVolume, err := restoreBackupVolumeAnnotation(destination)
+	if err != nil {
+		return nil, err
 	}
 
 	// before proceeding, ensure restore's namespace/name is stored in the ActiveRestoreAnnotationName cluster annotation
@@ -214,6 +244,9 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, rest
 			cluster.Annotations = map[string]string{}
 		}
 		cluster.Annotations[ActiveRestoreAnnotationName] = thisRestore
+		if restoreVolume != "" {
+			cluster.Annotations[resources.EtcdRestoreBackupVolumeAnnotation] = restoreVolume
+		}
 		if err := r.Client.Update(ctx, cluster); err != nil {
 			if kerrors.IsConflict(err) {
 				return &reconcile.Result{RequeueAfter: 1 * time.Minute}, nil
@@ -285,6 +318,150 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, rest
 	return r.rebuildEtcdStatefulset(ctx, log, restore, cluster)
 }
 
+// getRestoreDestination returns the backup destination to restore from. The first of the restore's destination
+// and fallback destinations which holds the backup is chosen and recorded in the restore's status, so that the
+// choice does not change once the restore has started. A nil destination means the legacy destination is used.
+func (r *Reconciler) getRestoreDestination(ctx context.Context, log *zap.SugaredLogger, restore *kubermaticv1.EtcdRestore, cluster *kubermaticv1.Cluster,
+	seed *kubermaticv1.Seed, objectName string) (*kubermaticv1.BackupDestination, error) {
+	if restore.Spec.Destination == "" {
+		return nil, nil
+	}
+
+	if restore.Status.Destination != "" {
+		return getSeedDestination(seed, restore.Status.Destination)
+	}
+
+	destinationName := restore.Spec.Destination
+	destination, err := getSeedDestination(seed, destinationName)
+	if err != nil {
+		return nil, err
+	}
+
+	// only probe the destinations if there is a choice to be made and the download credentials have not been
+	// fixed yet, in all other cases a missing backup is reported when accessing it
+	if len(restore.Spec.FallbackDestinations) > 0 && restore.Spec.BackupDownloadCredentialsSecret == "" {
+		destinationName, destination, err = r.findBackupDestination(ctx, log, restore, cluster, seed, objectName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.updateRestore(ctx, restore, func(restore *kubermaticv1.EtcdRestore) {
+		restore.Status.Destination = destinationName
+	}); err != nil {
+		return nil, fmt.Errorf("failed to set EtcdRestore destination: %v", err)
+	}
+
+	return destination, nil
+}
+
+// findBackupDestination returns the first of the restore's destination and fallback destinations which holds the backup.
+func (r *Reconciler) findBackupDestination(ctx context.Context, log *zap.SugaredLogger, restore *kubermaticv1.EtcdRestore, cluster *kubermaticv1.Cluster,
+	seed *kubermaticv1.Seed, objectName string) (string, *kubermaticv1.BackupDestination, error) {
+	candidates := append([]string{restore.Spec.Destination}, restore.Spec.FallbackDestinations...)
+
+	for _, name := range candidates {
+		destination, err := getSeedDestination(seed, name)
+		if err != nil {
+			log.Infow("skipping backup destination", "destination", name, zap.Error(err))
+			continue
+		}
+
+		if isAccessibleFromController(destination) {
+			store, err := resources.GetEtcdBackupDestinationStore(ctx, r.Client, cluster, destination)
+			if err != nil {
+				log.Infow("skipping backup destination", "destination", name, zap.Error(err))
+				continue
+			}
+
+			if _, err := store.Stat(ctx, objectName); err != nil {
+				log.Infow("backup not available in destination", "destination", name, zap.Error(err))
+				continue
+			}
+		}
+
+		if name != restore.Spec.Destination {
+			r.recorder.Eventf(restore, corev1.EventTypeWarning, "BackupDestinationFallback",
+				"backup %s is not available in destination %s, restoring from %s", restore.Spec.BackupName, restore.Spec.Destination, name)
+		}
+
+		return name, destination, nil
+	}
+
+	return "", nil, fmt.Errorf("backup object %s is not available in any of the destinations %v", objectName, candidates)
+}
+
+func getSeedDestination(seed *kubermaticv1.Seed, name string) (*kubermaticv1.BackupDestination, error) {
+	if seed.Spec.EtcdBackupRestore == nil {
+		return nil, errors.Errorf("can't find backup restore destination %q in Seed %q", name, seed.Name)
+	}
+	destination, ok := seed.Spec.EtcdBackupRestore.Destinations[name]
+	if !ok {
+		return nil, errors.Errorf("can't find backup restore destination %q in Seed %q", name, seed.Name)
+	}
+	if destination.Credentials == nil && destination.GetType() != kubermaticv1.BackupDestinationTypeFilesystem {
+		return nil, errors.Errorf("credentials not set for backup destination %q in Seed %q", name, seed.Name)
+	}
+	return destination, nil
+}
+
+// isAccessibleFromController returns false for filesystem destinations, whose volume is not mounted into the controller.
+func isAccessibleFromController(destination *kubermaticv1.BackupDestination) bool {
+	return destination == nil || destination.GetType() != kubermaticv1.BackupDestinationTypeFilesystem
+}
+
+// restoreBackupVolumeAnnotation returns the value of the EtcdRestoreBackupVolumeAnnotation for restoring
+// from the given destination, which is empty unless it is a filesystem destination.
+func restoreBackupVolumeAnnotation(destination *kubermaticv1.BackupDestination) (string, error) {
+	if isAccessibleFromController(destination) {
+		return "", nil
+	}
+
+	volumeSource, err := resources.BackupDestinationVolumeSource(destination)
+	if err != nil {
+		return "", err
+	}
+
+	encoded, err := json.Marshal(volumeSource)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode backup volume: %v", err)
+	}
+
+	return string(encoded), nil
+}
+
+func (r *Reconciler) checkBackupEncryptionKey(ctx context.Context, store backupstore.Store, objectName string, restore *kubermaticv1.EtcdRestore,
+	cluster *kubermaticv1.Cluster) error {
+	object, err := store.Download(ctx, objectName)
+	if err != nil {
+		return fmt.Errorf("could not access backup object %s: %w", objectName, err)
+	}
+	defer object.Close()
+
+	header, err := ioutil.ReadAll(io.LimitReader(object, int64(encryption.MaxHeaderLength)))
+	if err != nil {
+		return fmt.Errorf("could not read backup object %s: %w", objectName, err)
+	}
+	if !encryption.IsEncrypted(header) {
+		return nil
+	}
+
+	keyID, err := encryption.KeyID(header)
+	if err != nil {
+		return fmt.Errorf("invalid encrypted backup object %s: %w", objectName, err)
+	}
+
+	keys, err := resources.GetEtcdRestoreEncryptionKeys(ctx, restore, r.Client, cluster)
+	if err != nil {
+		return fmt.Errorf("failed to get backup encryption keys: %w", err)
+	}
+	if _, ok := keys[keyID]; !ok {
+		return fmt.Errorf("backup object %s is encrypted with key %q, which is not available", objectName, keyID)
+	}
+
+	return nil
+}
+
 func (r *Reconciler) rebuildEtcdStatefulset(ctx context.Context, log *zap.SugaredLogger, restore *kubermaticv1.EtcdRestore, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
 	log.Infof("rebuildEtcdStatefulset")
 
@@ -311,6 +488,7 @@ func (r *Reconciler) rebuildEtcdStatefulset(ctx context.Context, log *zap.Sugare
 
 	if err := r.updateCluster(ctx, cluster, func(cluster *kubermaticv1.Cluster) {
 		delete(cluster.Annotations, ActiveRestoreAnnotationName)
+		delete(cluster.Annotations, resources.EtcdRestoreBackupVolumeAnnotation)
 	}); err != nil {
 		return nil, fmt.Errorf("failed to clear cluster active restore annotation: %v", err)
 	}
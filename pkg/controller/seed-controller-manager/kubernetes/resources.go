@@ -44,10 +44,12 @@ import (
 	"k8c.io/kubermatic/v2/pkg/resources/scheduler"
 	"k8c.io/kubermatic/v2/pkg/resources/usercluster"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -55,6 +57,9 @@ const (
 	clusterIPUnknownRetryTimeout = 5 * time.Second
 )
 
+// caSecretNames are the secrets containing the CAs of the cluster.
+var caSecretNames = sets.NewString(resources.CASecretName, resources.FrontProxyCASecretName, resources.OpenVPNCASecretName)
+
 func (r *Reconciler) ensureResourcesAreDeployed(ctx context.Context, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
 	seed, err := r.seedGetter()
 	if err != nil {
@@ -235,7 +240,7 @@ func (r *Reconciler) ensureNamespaceExists(ctx context.Context, cluster *kuberma
 // GetServiceCreators returns all service creators that are currently in use
 func GetServiceCreators(data *resources.TemplateData) []reconciling.NamedServiceCreatorGetter {
 	creators := []reconciling.NamedServiceCreatorGetter{
-		apiserver.ServiceCreator(data.Cluster().Spec.ExposeStrategy, data.Cluster().Address.ExternalName),
+		apiserver.ServiceCreator(data.Cluster().Spec.ExposeStrategy, data.Cluster().Address.ExternalName, data.Cluster().Spec.APIServerAllowedIPRanges),
 		etcd.ServiceCreator(data),
 		dns.ServiceCreator(),
 		machinecontroller.ServiceCreator(),
@@ -268,14 +273,30 @@ func (r *Reconciler) ensureServices(ctx context.Context, c *kubermaticv1.Cluster
 
 // GetDeploymentCreators returns all DeploymentCreators that are currently in use
 func GetDeploymentCreators(data *resources.TemplateData, enableAPIserverOIDCAuthentication bool) []reconciling.NamedDeploymentCreatorGetter {
+	// A cloned cluster still contains the machines, nodes and load balancers of the cluster it
+	// was cloned from until the clusterclone controller has detached it from them. No controller
+	// that could act on these cloud resources must run until then.
+	scaleDownWhileClonePending := func(creator reconciling.NamedDeploymentCreatorGetter) reconciling.NamedDeploymentCreatorGetter {
+		if !data.Cluster().IsClonePending() {
+			return creator
+		}
+		return scaledDownDeploymentCreator(creator)
+	}
+
+	// Components that use the cloud credentials of the cluster are restarted whenever the
+	// credentials are rotated.
+	withCredentialsRevision := func(creator reconciling.NamedDeploymentCreatorGetter) reconciling.NamedDeploymentCreatorGetter {
+		return credentialsRevisionDeploymentCreator(creator, data.Cluster().Annotations[kubermaticv1.CredentialsRevisionAnnotation])
+	}
+
 	deployments := []reconciling.NamedDeploymentCreatorGetter{
 		dns.DeploymentCreator(data),
-		apiserver.DeploymentCreator(data, enableAPIserverOIDCAuthentication),
+		withCredentialsRevision(apiserver.DeploymentCreator(data, enableAPIserverOIDCAuthentication)),
 		scheduler.DeploymentCreator(data),
-		controllermanager.DeploymentCreator(data),
-		machinecontroller.DeploymentCreator(data),
-		machinecontroller.WebhookDeploymentCreator(data),
-		usercluster.DeploymentCreator(data),
+		withCredentialsRevision(scaleDownWhileClonePending(controllermanager.DeploymentCreator(data))),
+		withCredentialsRevision(scaleDownWhileClonePending(machinecontroller.DeploymentCreator(data))),
+		withCredentialsRevision(machinecontroller.WebhookDeploymentCreator(data)),
+		withCredentialsRevision(usercluster.DeploymentCreator(data)),
 		kubernetesdashboard.DeploymentCreator(data),
 	}
 
@@ -287,7 +308,7 @@ func GetDeploymentCreators(data *resources.TemplateData, enableAPIserverOIDCAuth
 	}
 
 	if data.Cluster().Annotations[kubermaticv1.AnnotationNameClusterAutoscalerEnabled] != "" {
-		deployments = append(deployments, clusterautoscaler.DeploymentCreator(data))
+		deployments = append(deployments, scaleDownWhileClonePending(clusterautoscaler.DeploymentCreator(data)))
 	}
 	// If CCM migration is ongoing defer the deployment of the CCM to the
 	// moment in which cloud controllers or the full in-tree cloud provider
@@ -295,12 +316,49 @@ func GetDeploymentCreators(data *resources.TemplateData, enableAPIserverOIDCAuth
 	if data.Cluster().Spec.Features[kubermaticv1.ClusterFeatureExternalCloudProvider] &&
 		(!metav1.HasAnnotation(data.Cluster().ObjectMeta, kubermaticv1.CCMMigrationNeededAnnotation) ||
 			data.KCMCloudControllersDeactivated()) {
-		deployments = append(deployments, cloudcontroller.DeploymentCreator(data))
+		deployments = append(deployments, withCredentialsRevision(scaleDownWhileClonePending(cloudcontroller.DeploymentCreator(data))))
 	}
 
 	return deployments
 }
 
+// scaledDownDeploymentCreator returns a creator for the deployment with no replicas.
+func scaledDownDeploymentCreator(creator reconciling.NamedDeploymentCreatorGetter) reconciling.NamedDeploymentCreatorGetter {
+	return func() (string, reconciling.DeploymentCreator) {
+		name, create := creator()
+		return name, func(dep *appsv1.Deployment) (*appsv1.Deployment, error) {
+			dep, err := create(dep)
+			if err != nil {
+				return nil, err
+			}
+			dep.Spec.Replicas = resources.Int32(0)
+			return dep, nil
+		}
+	}
+}
+
+// credentialsRevisionDeploymentCreator returns a creator for the deployment that annotates its pod template
+// with the revision of the cloud credentials, so that its pods are replaced once the credentials change.
+func credentialsRevisionDeploymentCreator(creator reconciling.NamedDeploymentCreatorGetter, revision string) reconciling.NamedDeploymentCreatorGetter {
+	return func() (string, reconciling.DeploymentCreator) {
+		name, create := creator()
+		return name, func(dep *appsv1.Deployment) (*appsv1.Deployment, error) {
+			dep, err := create(dep)
+			if err != nil {
+				return nil, err
+			}
+			if revision == "" {
+				return dep, nil
+			}
+			if dep.Spec.Template.Annotations == nil {
+				dep.Spec.Template.Annotations = map[string]string{}
+			}
+			dep.Spec.Template.Annotations[kubermaticv1.CredentialsRevisionAnnotation] = revision
+			return dep, nil
+		}
+	}
+}
+
 func (r *Reconciler) ensureDeployments(ctx context.Context, cluster *kubermaticv1.Cluster, data *resources.TemplateData) error {
 	creators := GetDeploymentCreators(data, r.features.KubernetesOIDCAuthentication)
 	return reconciling.ReconcileDeployments(ctx, creators, cluster.Status.NamespaceName, r, reconciling.OwnerRefWrapper(resources.GetClusterRef(cluster)))
@@ -332,6 +390,7 @@ func (r *Reconciler) GetSecretCreators(data *resources.TemplateData) []reconcili
 		apiserver.TokenViewerCreator(),
 		apiserver.TokenUsersCreator(data),
 		resources.ViewerKubeconfigCreator(data),
+		apiserver.AuditSinksSecretCreator(data),
 	}
 
 	if data.IsKonnectivityEnabled() {
@@ -360,11 +419,34 @@ func (r *Reconciler) GetSecretCreators(data *resources.TemplateData) []reconcili
 		creators = append(creators, resources.ServiceAccountSecretCreator(data))
 	}
 
+	if apiserver.AuditMLAIngestionEnabled(data) {
+		creators = append(creators, apiserver.AuditLogsMLACertificateCreator(data))
+	}
+
 	return creators
 }
 
+// certificateRenewalSecretCreator returns a creator for the secret that reissues its certificates once
+// the certificate rotation controller marked the secret for renewal. CAs are never reissued this way.
+func certificateRenewalSecretCreator(creator reconciling.NamedSecretCreatorGetter) reconciling.NamedSecretCreatorGetter {
+	return func() (string, reconciling.SecretCreator) {
+		name, create := creator()
+		return name, func(se *corev1.Secret) (*corev1.Secret, error) {
+			if _, renew := se.Annotations[resources.CertificateRenewalAnnotation]; renew && !caSecretNames.Has(name) {
+				delete(se.Annotations, resources.CertificateRenewalAnnotation)
+				// without the existing certificates the creator issues new ones
+				se.Data = nil
+			}
+			return create(se)
+		}
+	}
+}
+
 func (r *Reconciler) ensureSecrets(ctx context.Context, c *kubermaticv1.Cluster, data *resources.TemplateData) error {
 	namedSecretCreatorGetters := r.GetSecretCreators(data)
+	for i, creator := range namedSecretCreatorGetters {
+		namedSecretCreatorGetters[i] = certificateRenewalSecretCreator(creator)
+	}
 
 	if err := reconciling.ReconcileSecrets(ctx, namedSecretCreatorGetters, c.Status.NamespaceName, r.Client, reconciling.OwnerRefWrapper(resources.GetClusterRef(c))); err != nil {
 		return fmt.Errorf("failed to ensure that the Secret exists: %v", err)
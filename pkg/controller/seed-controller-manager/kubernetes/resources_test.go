@@ -26,6 +26,7 @@ import (
 	"k8c.io/kubermatic/v2/pkg/resources/apiserver"
 	"k8c.io/kubermatic/v2/pkg/resources/certificates"
 	"k8c.io/kubermatic/v2/pkg/resources/cloudcontroller"
+	"k8c.io/kubermatic/v2/pkg/resources/reconciling"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -339,6 +340,62 @@ func TestCloudControllerManagerDeployment(t *testing.T) {
 	}
 }
 
+func TestCertificateRenewalSecretCreator(t *testing.T) {
+	testCases := []struct {
+		name          string
+		secretName    string
+		annotations   map[string]string
+		wantReissued  bool
+		wantAnnotated bool
+	}{
+		{
+			name:       "secret not marked for renewal",
+			secretName: resources.ApiserverTLSSecretName,
+		},
+		{
+			name:         "secret marked for renewal",
+			secretName:   resources.ApiserverTLSSecretName,
+			annotations:  map[string]string{resources.CertificateRenewalAnnotation: "true"},
+			wantReissued: true,
+		},
+		{
+			name:          "CA marked for renewal",
+			secretName:    resources.CASecretName,
+			annotations:   map[string]string{resources.CertificateRenewalAnnotation: "true"},
+			wantAnnotated: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			creator := func() (string, reconciling.SecretCreator) {
+				return tc.secretName, func(se *corev1.Secret) (*corev1.Secret, error) {
+					if se.Data == nil {
+						se.Data = map[string][]byte{"tls.crt": []byte("new")}
+					}
+					return se, nil
+				}
+			}
+
+			_, create := certificateRenewalSecretCreator(creator)()
+			secret, err := create(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations},
+				Data:       map[string][]byte{"tls.crt": []byte("old")},
+			})
+			if err != nil {
+				t.Fatalf("failed to create secret: %v", err)
+			}
+
+			if reissued := string(secret.Data["tls.crt"]) == "new"; reissued != tc.wantReissued {
+				t.Errorf("want certificate reissued: %t got: %t", tc.wantReissued, reissued)
+			}
+			if _, annotated := secret.Annotations[resources.CertificateRenewalAnnotation]; annotated != tc.wantAnnotated {
+				t.Errorf("want renewal annotation: %t got: %t", tc.wantAnnotated, annotated)
+			}
+		})
+	}
+}
+
 type KCMDeploymentConfig struct {
 	Flags      []string
 	Generation int64
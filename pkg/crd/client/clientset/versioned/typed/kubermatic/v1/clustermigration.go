@@ -0,0 +1,168 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	scheme "k8c.io/kubermatic/v2/pkg/crd/client/clientset/versioned/scheme"
+	v1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// ClusterMigrationsGetter has a method to return a ClusterMigrationInterface.
+// A group's client should implement this interface.
+type ClusterMigrationsGetter interface {
+	ClusterMigrations() ClusterMigrationInterface
+}
+
+// ClusterMigrationInterface has methods to work with ClusterMigration resources.
+type ClusterMigrationInterface interface {
+	Create(ctx context.Context, clusterMigration *v1.ClusterMigration, opts metav1.CreateOptions) (*v1.ClusterMigration, error)
+	Update(ctx context.Context, clusterMigration *v1.ClusterMigration, opts metav1.UpdateOptions) (*v1.ClusterMigration, error)
+	UpdateStatus(ctx context.Context, clusterMigration *v1.ClusterMigration, opts metav1.UpdateOptions) (*v1.ClusterMigration, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.ClusterMigration, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.ClusterMigrationList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.ClusterMigration, err error)
+	ClusterMigrationExpansion
+}
+
+// clusterMigrations implements ClusterMigrationInterface
+type clusterMigrations struct {
+	client rest.Interface
+}
+
+// newClusterMigrations returns a ClusterMigrations
+func newClusterMigrations(c *KubermaticV1Client) *clusterMigrations {
+	return &clusterMigrations{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the clusterMigration, and returns the corresponding clusterMigration object, and an error if there is any.
+func (c *clusterMigrations) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.ClusterMigration, err error) {
+	result = &v1.ClusterMigration{}
+	err = c.client.Get().
+		Resource("clustermigrations").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of ClusterMigrations that match those selectors.
+func (c *clusterMigrations) List(ctx context.Context, opts metav1.ListOptions) (result *v1.ClusterMigrationList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.ClusterMigrationList{}
+	err = c.client.Get().
+		Resource("clustermigrations").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested clusterMigrations.
+func (c *clusterMigrations) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("clustermigrations").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a clusterMigration and creates it.  Returns the server's representation of the clusterMigration, and an error, if there is any.
+func (c *clusterMigrations) Create(ctx context.Context, clusterMigration *v1.ClusterMigration, opts metav1.CreateOptions) (result *v1.ClusterMigration, err error) {
+	result = &v1.ClusterMigration{}
+	err = c.client.Post().
+		Resource("clustermigrations").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(clusterMigration).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a clusterMigration and updates it. Returns the server's representation of the clusterMigration, and an error, if there is any.
+func (c *clusterMigrations) Update(ctx context.Context, clusterMigration *v1.ClusterMigration, opts metav1.UpdateOptions) (result *v1.ClusterMigration, err error) {
+	result = &v1.ClusterMigration{}
+	err = c.client.Put().
+		Resource("clustermigrations").
+		Name(clusterMigration.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(clusterMigration).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *clusterMigrations) UpdateStatus(ctx context.Context, clusterMigration *v1.ClusterMigration, opts metav1.UpdateOptions) (result *v1.ClusterMigration, err error) {
+	result = &v1.ClusterMigration{}
+	err = c.client.Put().
+		Resource("clustermigrations").
+		Name(clusterMigration.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(clusterMigration).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the clusterMigration and deletes it. Returns an error if one occurs.
+func (c *clusterMigrations) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("clustermigrations").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *clusterMigrations) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("clustermigrations").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched clusterMigration.
+func (c *clusterMigrations) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.ClusterMigration, err error) {
+	result = &v1.ClusterMigration{}
+	err = c.client.Patch(pt).
+		Resource("clustermigrations").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -0,0 +1,117 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeClusterMigrations implements ClusterMigrationInterface
+type FakeClusterMigrations struct {
+	Fake *FakeKubermaticV1
+}
+
+var clustermigrationsResource = schema.GroupVersionResource{Group: "kubermatic.k8s.io", Version: "v1", Resource: "clustermigrations"}
+
+var clustermigrationsKind = schema.GroupVersionKind{Group: "kubermatic.k8s.io", Version: "v1", Kind: "ClusterMigration"}
+
+// Get takes name of the clusterMigration, and returns the corresponding clusterMigration object, and an error if there is any.
+func (c *FakeClusterMigrations) Get(ctx context.Context, name string, options v1.GetOptions) (result *kubermaticv1.ClusterMigration, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(clustermigrationsResource, name), &kubermaticv1.ClusterMigration{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*kubermaticv1.ClusterMigration), err
+}
+
+// List takes label and field selectors, and returns the list of ClusterMigrations that match those selectors.
+func (c *FakeClusterMigrations) List(ctx context.Context, opts v1.ListOptions) (result *kubermaticv1.ClusterMigrationList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(clustermigrationsResource, clustermigrationsKind, opts), &kubermaticv1.ClusterMigrationList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &kubermaticv1.ClusterMigrationList{ListMeta: obj.(*kubermaticv1.ClusterMigrationList).ListMeta}
+	for _, item := range obj.(*kubermaticv1.ClusterMigrationList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested clusterMigrations.
+func (c *FakeClusterMigrations) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(clustermigrationsResource, opts))
+}
+
+// Create takes the representation of a clusterMigration and creates it.  Returns the server's representation of the clusterMigration, and an error, if there is any.
+func (c *FakeClusterMigrations) Create(ctx context.Context, clusterMigration *kubermaticv1.ClusterMigration, opts v1.CreateOptions) (result *kubermaticv1.ClusterMigration, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(clustermigrationsResource, clusterMigration), &kubermaticv1.ClusterMigration{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*kubermaticv1.ClusterMigration), err
+}
+
+// Update takes the representation of a clusterMigration and updates it. Returns the server's representation of the clusterMigration, and an error, if there is any.
+func (c *FakeClusterMigrations) Update(ctx context.Context, clusterMigration *kubermaticv1.ClusterMigration, opts v1.UpdateOptions) (result *kubermaticv1.ClusterMigration, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(clustermigrationsResource, clusterMigration), &kubermaticv1.ClusterMigration{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*kubermaticv1.ClusterMigration), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeClusterMigrations) UpdateStatus(ctx context.Context, clusterMigration *kubermaticv1.ClusterMigration, opts v1.UpdateOptions) (*kubermaticv1.ClusterMigration, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateSubresourceAction(clustermigrationsResource, "status", clusterMigration), &kubermaticv1.ClusterMigration{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*kubermaticv1.ClusterMigration), err
+}
+
+// Delete takes name of the clusterMigration and deletes it. Returns an error if one occurs.
+func (c *FakeClusterMigrations) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteAction(clustermigrationsResource, name), &kubermaticv1.ClusterMigration{})
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeClusterMigrations) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(clustermigrationsResource, listOpts)
+
+	_, err := c.Fake.Invokes(action, &kubermaticv1.ClusterMigrationList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched clusterMigration.
+func (c *FakeClusterMigrations) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *kubermaticv1.ClusterMigration, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(clustermigrationsResource, name, pt, data, subresources...), &kubermaticv1.ClusterMigration{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*kubermaticv1.ClusterMigration), err
+}
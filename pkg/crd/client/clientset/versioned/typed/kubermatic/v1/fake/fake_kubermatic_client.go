@@ -32,6 +32,10 @@ func (c *FakeKubermaticV1) Clusters() v1.ClusterInterface {
 	return &FakeClusters{c}
 }
 
+func (c *FakeKubermaticV1) ClusterMigrations() v1.ClusterMigrationInterface {
+	return &FakeClusterMigrations{c}
+}
+
 func (c *FakeKubermaticV1) ClusterTemplates() v1.ClusterTemplateInterface {
 	return &FakeClusterTemplates{c}
 }
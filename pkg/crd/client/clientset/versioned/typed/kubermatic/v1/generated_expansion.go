@@ -12,6 +12,8 @@ type AllowedRegistryExpansion interface{}
 
 type ClusterExpansion interface{}
 
+type ClusterMigrationExpansion interface{}
+
 type ClusterTemplateExpansion interface{}
 
 type ClusterTemplateInstanceExpansion interface{}
@@ -15,6 +15,7 @@ type KubermaticV1Interface interface {
 	AlertmanagersGetter
 	AllowedRegistriesGetter
 	ClustersGetter
+	ClusterMigrationsGetter
 	ClusterTemplatesGetter
 	ClusterTemplateInstancesGetter
 	ConstraintsGetter
@@ -56,6 +57,10 @@ func (c *KubermaticV1Client) Clusters() ClusterInterface {
 	return newClusters(c)
 }
 
+func (c *KubermaticV1Client) ClusterMigrations() ClusterMigrationInterface {
+	return newClusterMigrations(c)
+}
+
 func (c *KubermaticV1Client) ClusterTemplates() ClusterTemplateInterface {
 	return newClusterTemplates(c)
 }
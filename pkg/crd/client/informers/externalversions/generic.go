@@ -47,6 +47,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubermatic().V1().AllowedRegistries().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("clusters"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubermatic().V1().Clusters().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("clustermigrations"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubermatic().V1().ClusterMigrations().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("clustertemplates"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubermatic().V1().ClusterTemplates().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("clustertemplateinstances"):
@@ -18,6 +18,8 @@ type Interface interface {
 	AllowedRegistries() AllowedRegistryInformer
 	// Clusters returns a ClusterInformer.
 	Clusters() ClusterInformer
+	// ClusterMigrations returns a ClusterMigrationInformer.
+	ClusterMigrations() ClusterMigrationInformer
 	// ClusterTemplates returns a ClusterTemplateInformer.
 	ClusterTemplates() ClusterTemplateInformer
 	// ClusterTemplateInstances returns a ClusterTemplateInstanceInformer.
@@ -84,6 +86,11 @@ func (v *version) Clusters() ClusterInformer {
 	return &clusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// ClusterMigrations returns a ClusterMigrationInformer.
+func (v *version) ClusterMigrations() ClusterMigrationInformer {
+	return &clusterMigrationInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // ClusterTemplates returns a ClusterTemplateInformer.
 func (v *version) ClusterTemplates() ClusterTemplateInformer {
 	return &clusterTemplateInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
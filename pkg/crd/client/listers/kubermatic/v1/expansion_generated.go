@@ -30,6 +30,10 @@ type AllowedRegistryListerExpansion interface{}
 // ClusterLister.
 type ClusterListerExpansion interface{}
 
+// ClusterMigrationListerExpansion allows custom methods to be added to
+// ClusterMigrationLister.
+type ClusterMigrationListerExpansion interface{}
+
 // ClusterTemplateListerExpansion allows custom methods to be added to
 // ClusterTemplateLister.
 type ClusterTemplateListerExpansion interface{}
@@ -63,12 +63,27 @@ type EtcdRestoreSpec struct {
 	Cluster corev1.ObjectReference `json:"cluster"`
 	// BackupName is the name of the backup to restore from
 	BackupName string `json:"backupName"`
+	// SourceClusterName is the name of the cluster the backup was taken from. It only has to be set
+	// if the backup is restored into a different cluster, for example when cloning a cluster.
+	// If empty, the backup of Cluster is restored.
+	SourceClusterName string `json:"sourceClusterName,omitempty"`
 	// BackupDownloadCredentialsSecret is the name of a secret in the cluster-xxx namespace containing
 	// credentials needed to download the backup
 	BackupDownloadCredentialsSecret string `json:"backupDownloadCredentialsSecret,omitempty"`
 	// Destination indicates where the backup was stored. The destination name should correspond to a destination in
 	// the cluster's Seed.Spec.EtcdBackupRestore. If empty, it will use the legacy destination configured in Seed.Spec.BackupRestore
 	Destination string `json:"destination,omitempty"`
+	// FallbackDestinations are tried in order if the backup is not available in Destination, for example
+	// the replica destinations of the EtcdBackupConfig which created the backup.
+	FallbackDestinations []string `json:"fallbackDestinations,omitempty"`
+	// RestoreToTime is the point in time to restore to. The revisions shipped by a continuous backup
+	// after the backup named in BackupName was taken are replayed up to this time.
+	// Mutually exclusive with RestoreToRevision.
+	RestoreToTime *metav1.Time `json:"restoreToTime,omitempty"`
+	// RestoreToRevision is the etcd revision to restore to. The revisions shipped by a continuous backup
+	// after the backup named in BackupName was taken are replayed up to and including this revision.
+	// Mutually exclusive with RestoreToTime.
+	RestoreToRevision int64 `json:"restoreToRevision,omitempty"`
 }
 
 // EtcdRestoreList is a list of etcd restores
@@ -83,4 +98,21 @@ type EtcdRestoreList struct {
 type EtcdRestoreStatus struct {
 	Phase       EtcdRestorePhase `json:"phase"`
 	RestoreTime *metav1.Time     `json:"restoreTime,omitempty"`
+	// Destination is the destination the backup is restored from. It differs from Spec.Destination
+	// if the backup was only available in one of the fallback destinations.
+	Destination string `json:"destination,omitempty"`
+}
+
+// IsPointInTimeRestore returns true if continuously shipped revisions need to be replayed
+// on top of the backup.
+func (r *EtcdRestore) IsPointInTimeRestore() bool {
+	return r.Spec.RestoreToTime != nil || r.Spec.RestoreToRevision > 0
+}
+
+// BackupClusterName returns the name of the cluster the backup to restore was taken from.
+func (r *EtcdRestore) BackupClusterName() string {
+	if r.Spec.SourceClusterName != "" {
+		return r.Spec.SourceClusterName
+	}
+	return r.Spec.Cluster.Name
 }
@@ -21,6 +21,7 @@ import (
 
 	providerconfig "github.com/kubermatic/machine-controller/pkg/providerconfig/types"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -43,6 +44,8 @@ type ExternalCluster struct {
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
 	Spec ExternalClusterSpec `json:"spec"`
+
+	Status ExternalClusterStatus `json:"status,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -63,6 +66,85 @@ type ExternalClusterSpec struct {
 
 	KubeconfigReference *providerconfig.GlobalSecretKeySelector `json:"kubeconfigReference,omitempty"`
 	CloudSpec           *ExternalClusterCloudSpec               `json:"cloudSpec,omitempty"`
+
+	// KubeconfigRotation makes Kubermatic install a dedicated ServiceAccount in a cluster imported
+	// from a kubeconfig and periodically replace the credentials of the stored kubeconfig with a
+	// fresh token of it.
+	KubeconfigRotation *ExternalClusterKubeconfigRotation `json:"kubeconfigRotation,omitempty"`
+}
+
+// ExternalClusterKubeconfigRotation configures the rotation of the kubeconfig of an imported cluster.
+type ExternalClusterKubeconfigRotation struct {
+	// TokenValidity is how long the issued ServiceAccount tokens are valid, a new token is requested
+	// once less than a third of it is left. Defaults to 24h, must be at least 10m.
+	TokenValidity *metav1.Duration `json:"tokenValidity,omitempty"`
+}
+
+// ExternalClusterPhase is the lifecycle phase of an external cluster as reported by its cloud provider.
+type ExternalClusterPhase string
+
+const (
+	ExternalClusterPhaseProvisioning ExternalClusterPhase = "Provisioning"
+	ExternalClusterPhaseRunning      ExternalClusterPhase = "Running"
+	ExternalClusterPhaseReconciling  ExternalClusterPhase = "Reconciling"
+	ExternalClusterPhaseDeleting     ExternalClusterPhase = "Deleting"
+	ExternalClusterPhaseError        ExternalClusterPhase = "Error"
+	ExternalClusterPhaseUnknown      ExternalClusterPhase = "Unknown"
+)
+
+// ExternalClusterStatus denotes status information about an ExternalCluster.
+type ExternalClusterStatus struct {
+	// Phase is the lifecycle phase of the cluster on its cloud provider. It is only set
+	// for clusters that are backed by a GKE, EKS or AKS cluster.
+	Phase ExternalClusterPhase `json:"phase,omitempty"`
+	// StatusMessage contains the state reported by the cloud provider or the last
+	// error that occurred while talking to it.
+	StatusMessage string `json:"statusMessage,omitempty"`
+	// Version is the Kubernetes version reported by the API server of the cluster.
+	Version string `json:"version,omitempty"`
+	// NodeCount is the number of nodes in the cluster.
+	NodeCount int32 `json:"nodeCount,omitempty"`
+	// LastSeen is the last time the API server of the cluster could be reached.
+	LastSeen metav1.Time `json:"lastSeen,omitempty"`
+	// KubeconfigExpiry is the time the credentials of the stored kubeconfig expire. It is only
+	// set if the kubeconfig uses a client certificate or a token that carries an expiry.
+	KubeconfigExpiry *metav1.Time `json:"kubeconfigExpiry,omitempty"`
+	// Conditions contains conditions the cluster is in, the list is sorted by type.
+	Conditions []ExternalClusterCondition `json:"conditions,omitempty"`
+}
+
+// ExternalClusterConditionType is the type of an ExternalClusterCondition.
+type ExternalClusterConditionType string
+
+const (
+	// ExternalClusterConditionReachable tells if the API server of the cluster can be reached
+	// with the kubeconfig stored for it.
+	ExternalClusterConditionReachable ExternalClusterConditionType = "Reachable"
+	// ExternalClusterConditionNodesReady tells if all nodes of the cluster are ready.
+	ExternalClusterConditionNodesReady ExternalClusterConditionType = "NodesReady"
+	// ExternalClusterConditionKubeconfigValid tells if the credentials of the stored kubeconfig
+	// are neither expired nor about to expire.
+	ExternalClusterConditionKubeconfigValid ExternalClusterConditionType = "KubeconfigValid"
+)
+
+// ExternalClusterCondition describes a condition of an external cluster.
+type ExternalClusterCondition struct {
+	// Type of external cluster condition.
+	Type ExternalClusterConditionType `json:"type"`
+	// Status of the condition, one of True, False, Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+	// Last time we got an update on a given condition.
+	// +optional
+	LastHeartbeatTime metav1.Time `json:"lastHeartbeatTime,omitempty"`
+	// Last time the condition transit from one status to another.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// (brief) reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Human readable message indicating details about last transition.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // ExternalClusterCloudSpec mutually stores access data to a cloud provider.
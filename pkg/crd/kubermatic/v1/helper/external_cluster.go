@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helper
+
+import (
+	"sort"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func GetExternalClusterCondition(c *kubermaticv1.ExternalCluster, conditionType kubermaticv1.ExternalClusterConditionType) (int, *kubermaticv1.ExternalClusterCondition) {
+	for i, condition := range c.Status.Conditions {
+		if conditionType == condition.Type {
+			return i, &condition
+		}
+	}
+	return -1, nil
+}
+
+func ExternalClusterConditionHasStatus(c *kubermaticv1.ExternalCluster, conditionType kubermaticv1.ExternalClusterConditionType, status corev1.ConditionStatus) bool {
+	_, cond := GetExternalClusterCondition(c, conditionType)
+	if cond != nil {
+		return cond.Status == status
+	}
+
+	return false
+}
+
+// SetExternalClusterCondition sets a condition on the given external cluster. The
+// heartbeat and transition times are only updated if the condition changed.
+func SetExternalClusterCondition(
+	c *kubermaticv1.ExternalCluster,
+	conditionType kubermaticv1.ExternalClusterConditionType,
+	status corev1.ConditionStatus,
+	reason string,
+	message string,
+) {
+	newCondition := kubermaticv1.ExternalClusterCondition{
+		Type:    conditionType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}
+	pos, oldCondition := GetExternalClusterCondition(c, conditionType)
+	if oldCondition != nil {
+		// Reset the times before comparing
+		oldCondition.LastHeartbeatTime.Reset()
+		oldCondition.LastTransitionTime.Reset()
+		if apiequality.Semantic.DeepEqual(*oldCondition, newCondition) {
+			return
+		}
+	}
+
+	newCondition.LastHeartbeatTime = metav1.Now()
+	if oldCondition == nil || oldCondition.Status != status {
+		newCondition.LastTransitionTime = metav1.Now()
+	}
+
+	if oldCondition != nil {
+		c.Status.Conditions[pos] = newCondition
+	} else {
+		c.Status.Conditions = append(c.Status.Conditions, newCondition)
+	}
+	// Has to be sorted, otherwise we may end up creating patches that just re-arrange them.
+	sort.SliceStable(c.Status.Conditions, func(i, j int) bool {
+		return c.Status.Conditions[i].Type < c.Status.Conditions[j].Type
+	})
+}
@@ -22,6 +22,9 @@ import (
 	"strings"
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 func getProviderValue(s *kubermaticv1.PresetSpec, providerType kubermaticv1.ProviderType) reflect.Value {
@@ -95,6 +98,11 @@ func Validate(p *kubermaticv1.Preset, providerType kubermaticv1.ProviderType) er
 		return fmt.Errorf("provider %s does not implement validateable interface", providerField.Type().Name())
 	}
 
+	// credentials kept in an external secret store are only known once the preset is used
+	if p.Spec.ExternalSecrets != nil {
+		return nil
+	}
+
 	checker := providerField.Interface().(validateable)
 	if !checker.IsValid() {
 		return fmt.Errorf("required fields missing for provider spec: %s", providerType)
@@ -122,3 +130,63 @@ func RemoveProvider(p *kubermaticv1.Preset, providerType kubermaticv1.ProviderTy
 
 	return p
 }
+
+// IsAvailableForProject returns whether the preset can be used in the project. Presets that
+// are neither restricted to project IDs nor to project labels are available in all projects.
+func IsAvailableForProject(p *kubermaticv1.Preset, project *kubermaticv1.Project) (bool, error) {
+	if len(p.Spec.Projects) == 0 && p.Spec.ProjectSelector == nil {
+		return true, nil
+	}
+
+	for _, projectID := range p.Spec.Projects {
+		if projectID == project.Name {
+			return true, nil
+		}
+	}
+
+	if p.Spec.ProjectSelector == nil {
+		return false, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(p.Spec.ProjectSelector)
+	if err != nil {
+		return false, fmt.Errorf("invalid project selector of preset %s: %v", p.Name, err)
+	}
+
+	return selector.Matches(labels.Set(project.Labels)), nil
+}
+
+// SetExternalCredentials sets the credentials read from an external secret store on the preset. The keys
+// of data consist of the provider and the JSON name of the field, e.g. "hetzner.token". Credentials for
+// providers the preset is not configured for are ignored.
+func SetExternalCredentials(p *kubermaticv1.Preset, data map[string]string) error {
+	for key, value := range data {
+		parts := strings.SplitN(key, ".", 2)
+		if len(parts) != 2 || !kubermaticv1.IsProviderSupported(parts[0]) {
+			return fmt.Errorf("invalid key %q, expected <provider>.<field>", key)
+		}
+
+		hasProvider, provider := HasProvider(p, kubermaticv1.ProviderType(parts[0]))
+		if !hasProvider {
+			continue
+		}
+
+		field := getJSONField(reflect.Indirect(provider), parts[1])
+		if !field.IsValid() || field.Kind() != reflect.String {
+			return fmt.Errorf("invalid key %q, %s presets have no credential %q", key, parts[0], parts[1])
+		}
+		field.SetString(value)
+	}
+
+	return nil
+}
+
+func getJSONField(s reflect.Value, name string) reflect.Value {
+	for i := 0; i < s.NumField(); i++ {
+		tag := strings.Split(s.Type().Field(i).Tag.Get("json"), ",")[0]
+		if tag == name {
+			return s.Field(i)
+		}
+	}
+	return reflect.Value{}
+}
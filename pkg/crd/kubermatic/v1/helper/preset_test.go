@@ -0,0 +1,103 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helper
+
+import (
+	"testing"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsAvailableForProject(t *testing.T) {
+	project := &kubermaticv1.Project{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "my-project",
+			Labels: map[string]string{"team": "infra"},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		spec          kubermaticv1.PresetSpec
+		expected      bool
+		expectedError bool
+	}{
+		{
+			name:     "unrestricted presets are available in all projects",
+			expected: true,
+		},
+		{
+			name:     "presets are available in listed projects",
+			spec:     kubermaticv1.PresetSpec{Projects: []string{"other-project", "my-project"}},
+			expected: true,
+		},
+		{
+			name:     "presets are not available in other projects",
+			spec:     kubermaticv1.PresetSpec{Projects: []string{"other-project"}},
+			expected: false,
+		},
+		{
+			name: "presets are available in projects with matching labels",
+			spec: kubermaticv1.PresetSpec{
+				Projects:        []string{"other-project"},
+				ProjectSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "infra"}},
+			},
+			expected: true,
+		},
+		{
+			name: "presets are not available in projects with other labels",
+			spec: kubermaticv1.PresetSpec{
+				ProjectSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "apps"}},
+			},
+			expected: false,
+		},
+		{
+			name: "invalid selectors are reported",
+			spec: kubermaticv1.PresetSpec{
+				ProjectSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Unknown"}},
+				},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			preset := &kubermaticv1.Preset{
+				ObjectMeta: metav1.ObjectMeta{Name: "preset"},
+				Spec:       tc.spec,
+			}
+
+			available, err := IsAvailableForProject(preset, project)
+			if tc.expectedError {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if available != tc.expected {
+				t.Fatalf("expected available to be %v, got %v", tc.expected, available)
+			}
+		})
+	}
+}
@@ -42,7 +42,8 @@ type Preset struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec PresetSpec `json:"spec"`
+	Spec   PresetSpec   `json:"spec"`
+	Status PresetStatus `json:"status,omitempty"`
 }
 
 // Presets specifies default presets for supported providers
@@ -63,6 +64,19 @@ type PresetSpec struct {
 	AKS          *AKS          `json:"aks,omitempty"`
 
 	Fake *Fake `json:"fake,omitempty"`
+
+	// ExternalSecrets references an external secret store entry holding the credentials of the
+	// preset. They are resolved whenever the preset is used and take precedence over the
+	// credentials stored in the preset itself.
+	ExternalSecrets *PresetExternalSecrets `json:"externalSecrets,omitempty"`
+
+	// Projects restricts the preset to the projects with the given IDs.
+	Projects []string `json:"projects,omitempty"`
+	// ProjectSelector restricts the preset to the projects whose labels match the selector.
+	// A preset is available in a project that matches either Projects or ProjectSelector,
+	// if neither is set, it is available in all projects.
+	ProjectSelector *metav1.LabelSelector `json:"projectSelector,omitempty"`
+
 	// see RequiredEmails
 	RequiredEmailDomain string `json:"requiredEmailDomain,omitempty"`
 	// RequiredEmails: specify emails and domains
@@ -88,6 +102,31 @@ func (s *PresetSpec) SetEnabled(enabled bool) {
 	s.Enabled = &enabled
 }
 
+// PresetExternalSecrets references the credentials of a preset in an external secret store.
+// The keys of the secret consist of the provider and the field of the credential, e.g.
+// "aws.secretAccessKey" or "hetzner.token".
+type PresetExternalSecrets struct {
+	// Path is the path of the secret in the secret store, e.g. "secret/data/presets/aws"
+	// for a Vault KV version 2 engine mounted at "secret".
+	Path string `json:"path"`
+}
+
+// PresetStatus is the usage index of a preset, it is maintained by the preset usage controller.
+type PresetStatus struct {
+	// Clusters are the clusters that use the credentials of the preset.
+	Clusters []PresetClusterReference `json:"clusters,omitempty"`
+}
+
+// PresetClusterReference references a cluster that uses the credentials of a preset.
+type PresetClusterReference struct {
+	// Name is the name of the cluster.
+	Name string `json:"name"`
+	// ProjectID is the ID of the project the cluster belongs to.
+	ProjectID string `json:"projectID,omitempty"`
+	// Seed is the name of the seed the cluster is running on.
+	Seed string `json:"seed"`
+}
+
 type ProviderPreset struct {
 	Enabled    *bool  `json:"enabled,omitempty"`
 	Datacenter string `json:"datacenter,omitempty"`
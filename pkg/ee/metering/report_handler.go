@@ -28,15 +28,15 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
-	"github.com/minio/minio-go/v7"
-	minioCredentials "github.com/minio/minio-go/v7/pkg/credentials"
 
 	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
+	"k8c.io/kubermatic/v2/pkg/backupstore"
 	"k8c.io/kubermatic/v2/pkg/provider"
 	k8cerrors "k8c.io/kubermatic/v2/pkg/util/errors"
 
@@ -66,12 +66,6 @@ func ListReports(ctx context.Context, req interface{}, seedsGetter provider.Seed
 		return nil, err
 	}
 
-	listOptions := minio.ListObjectsOptions{
-		MaxKeys:    request.MaxKeys,
-		StartAfter: request.StartAfter,
-		Prefix:     ReportPrefix,
-	}
-
 	for _, seed := range seedsMap {
 
 		seedClient, err := seedClientGetter(seed)
@@ -79,7 +73,7 @@ func ListReports(ctx context.Context, req interface{}, seedsGetter provider.Seed
 			return nil, err
 		}
 
-		reports, err := getReportsForSeed(ctx, listOptions, seedClient)
+		reports, err := getReportsForSeed(ctx, request, seedClient)
 		if err != nil {
 			return nil, err
 		}
@@ -113,16 +107,20 @@ func GetReport(ctx context.Context, req interface{}, seedsGetter provider.SeedsG
 			return "", err
 		}
 
-		mc, bucket, err := getS3DataFromSeed(ctx, seedClient)
+		store, err := getReportStoreFromSeed(ctx, seedClient)
 		if err != nil {
 			return "", err
 		}
-		_, err = mc.StatObject(ctx, bucket, request.ReportName, minio.StatObjectOptions{})
+		_, err = store.Stat(ctx, request.ReportName)
 		if err != nil {
 			return "", err
 		}
 
-		presignedURL, err := mc.PresignedGetObject(ctx, bucket, request.ReportName, urlValidTime, nil)
+		presigner, ok := store.(backupstore.Presigner)
+		if !ok {
+			return "", errors.New("report store does not support presigned URLs")
+		}
+		presignedURL, err := presigner.PresignedURL(ctx, request.ReportName, urlValidTime)
 		if err != nil {
 			return "", err
 		}
@@ -134,65 +132,61 @@ func GetReport(ctx context.Context, req interface{}, seedsGetter provider.SeedsG
 	return "", k8cerrors.New(404, "report not found")
 }
 
-func getReportsForSeed(ctx context.Context, options minio.ListObjectsOptions, seedClient ctrlruntimeclient.Client) ([]apiv1.MeteringReport, error) {
+func getReportsForSeed(ctx context.Context, request listReportReq, seedClient ctrlruntimeclient.Client) ([]apiv1.MeteringReport, error) {
 
-	mc, s3bucket, err := getS3DataFromSeed(ctx, seedClient)
+	store, err := getReportStoreFromSeed(ctx, seedClient)
 	if err != nil {
 		return nil, err
 	}
 
-	var reports []apiv1.MeteringReport
+	objects, err := store.List(ctx, ReportPrefix)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Name < objects[j].Name
+	})
 
-	mcCtx, cancel := context.WithCancel(context.Background())
+	var reports []apiv1.MeteringReport
 
-	for report := range mc.ListObjects(mcCtx, s3bucket, options) {
-		if report.Err != nil {
-			cancel()
-			return nil, errors.New(report.Err.Error())
+	for _, report := range objects {
+		if report.Name <= request.StartAfter {
+			continue
 		}
 
 		reports = append(reports, apiv1.MeteringReport{
-			Name:         report.Key,
+			Name:         report.Name,
 			LastModified: report.LastModified,
 			Size:         report.Size,
 		})
-		if len(reports) == options.MaxKeys {
+		if len(reports) == request.MaxKeys {
 			break
 		}
 	}
-	cancel()
 
 	return reports, nil
 }
 
-func getS3DataFromSeed(ctx context.Context, seedClient ctrlruntimeclient.Client) (*minio.Client, string, error) {
+func getReportStoreFromSeed(ctx context.Context, seedClient ctrlruntimeclient.Client) (backupstore.Store, error) {
 
 	var s3 v1.Secret
 	err := seedClient.Get(ctx, secretNamespacedName, &s3)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
+	// reports are always fetched using TLS
 	s3endpoint := string(s3.Data[Endpoint])
-	s3accessKeyID := string(s3.Data[AccessKey])
-	s3secretAccessKey := string(s3.Data[SecretKey])
-
 	s3endpoint = strings.Replace(s3endpoint, "https://", "", 1)
 	s3endpoint = strings.Replace(s3endpoint, "http://", "", 1)
 
-	mc, err := minio.New(s3endpoint, &minio.Options{
-		Creds:  minioCredentials.NewStaticV4(s3accessKeyID, s3secretAccessKey, ""),
-		Secure: true,
+	return backupstore.New(ctx, backupstore.Config{
+		Type:            backupstore.TypeS3,
+		Endpoint:        s3endpoint,
+		Bucket:          string(s3.Data[Bucket]),
+		AccessKeyID:     string(s3.Data[AccessKey]),
+		SecretAccessKey: string(s3.Data[SecretKey]),
 	})
-
-	if err != nil {
-		return nil, "", err
-	}
-
-	s3bucket := string(s3.Data[Bucket])
-
-	return mc, s3bucket, nil
-
 }
 
 // swagger:parameters listMeteringReports
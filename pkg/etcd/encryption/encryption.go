@@ -0,0 +1,448 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package encryption implements the envelope format used to encrypt etcd backups
+// and shipped revisions before they are uploaded to a backup destination.
+//
+// Data is split into chunks which are sealed individually using AES-256-GCM. Every
+// encrypted stream starts with a header naming the key it was encrypted with, so
+// that keys can be rotated while older backups remain readable as long as their
+// key is still part of the key ring.
+package encryption
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// KeySize is the size of the raw AES-256 keys.
+	KeySize = 32
+	// MagicLength is the number of leading bytes IsEncrypted needs to detect encrypted data.
+	MagicLength = len(magic)
+
+	magic           = "KKPENC01"
+	chunkSize       = 64 * 1024
+	noncePrefixSize = 7
+	maxKeyIDLength  = 255
+
+	flagIntermediate byte = 0
+	flagFinal        byte = 1
+)
+
+var (
+	// ErrUnknownKey is returned when data was encrypted with a key that is not part of the key ring.
+	ErrUnknownKey = errors.New("data was encrypted with an unknown key")
+	// ErrTruncated is returned when an encrypted stream ends before its final chunk.
+	ErrTruncated = errors.New("encrypted data is truncated")
+)
+
+// KeyRing maps key IDs to raw AES-256 keys.
+type KeyRing map[string][]byte
+
+// ParseKey decodes a base64 encoded AES-256 key, as generated by `openssl rand -base64 32`.
+func ParseKey(encoded []byte) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil {
+		return nil, fmt.Errorf("key is not valid base64: %w", err)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key must be %d bytes long, but is %d bytes", KeySize, len(key))
+	}
+	return key, nil
+}
+
+// KeyRingFromSecretData parses every entry of a Secret as a key, using the entry name as key ID.
+func KeyRingFromSecretData(data map[string][]byte) (KeyRing, error) {
+	keys := KeyRing{}
+	for id, encoded := range data {
+		key, err := ParseKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", id, err)
+		}
+		keys[id] = key
+	}
+	return keys, nil
+}
+
+// LoadKeyRing reads all keys from a directory, usually a mounted Secret volume.
+func LoadKeyRing(dir string) (KeyRing, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string][]byte{}
+	for _, file := range files {
+		// skip the bookkeeping entries of mounted Secret volumes (..data etc.)
+		if strings.HasPrefix(file.Name(), ".") || file.IsDir() {
+			continue
+		}
+		content, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		data[file.Name()] = content
+	}
+
+	return KeyRingFromSecretData(data)
+}
+
+// IsEncrypted checks whether the given data starts with the envelope header.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(magic))
+}
+
+// IsEncryptedFile checks whether the given file contains encrypted data.
+func IsEncryptedFile(filename string) (bool, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	header := make([]byte, MagicLength)
+	if _, err := io.ReadFull(f, header); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return IsEncrypted(header), nil
+}
+
+// MaxHeaderLength is the number of leading bytes KeyID needs at most.
+const MaxHeaderLength = len(magic) + 1 + maxKeyIDLength
+
+// KeyID returns the ID of the key the given data was encrypted with. It only needs
+// the header of the encrypted data, i.e. the first MaxHeaderLength bytes.
+func KeyID(data []byte) (string, error) {
+	if !IsEncrypted(data) {
+		return "", errors.New("data is not encrypted")
+	}
+	if len(data) <= len(magic) {
+		return "", ErrTruncated
+	}
+
+	length := int(data[len(magic)])
+	if len(data) < len(magic)+1+length {
+		return "", ErrTruncated
+	}
+
+	return string(data[len(magic)+1 : len(magic)+1+length]), nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// nonce derives the nonce of a chunk from the per-stream random prefix, the chunk
+// counter and whether it is the final chunk. Binding the final flag into the nonce
+// prevents truncating a stream at a chunk boundary without being noticed.
+func nonce(prefix []byte, counter uint32, flag byte) []byte {
+	n := make([]byte, noncePrefixSize+5)
+	copy(n, prefix)
+	binary.BigEndian.PutUint32(n[noncePrefixSize:], counter)
+	n[noncePrefixSize+4] = flag
+	return n
+}
+
+type writer struct {
+	out     io.Writer
+	aead    cipher.AEAD
+	header  []byte
+	prefix  []byte
+	counter uint32
+	buf     []byte
+	closed  bool
+}
+
+// NewWriter returns a writer which encrypts everything written to it using the given
+// key and writes the result to w. Close must be called to write the final chunk; it
+// does not close w.
+func NewWriter(w io.Writer, keyID string, key []byte) (io.WriteCloser, error) {
+	if keyID == "" || len(keyID) > maxKeyIDLength {
+		return nil, fmt.Errorf("key ID must be between 1 and %d characters long", maxKeyIDLength)
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := make([]byte, noncePrefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	header := []byte(magic)
+	header = append(header, byte(len(keyID)))
+	header = append(header, keyID...)
+	header = append(header, prefix...)
+
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &writer{
+		out:    w,
+		aead:   aead,
+		header: header,
+		prefix: prefix,
+		buf:    make([]byte, 0, chunkSize),
+	}, nil
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, errors.New("write to closed writer")
+	}
+
+	written := 0
+	for len(p) > 0 {
+		// a full buffer is only sealed once more data arrives, because
+		// the last chunk has to be marked as final on Close
+		if len(w.buf) == chunkSize {
+			if err := w.seal(flagIntermediate); err != nil {
+				return written, err
+			}
+		}
+
+		n := copy(w.buf[len(w.buf):chunkSize], p)
+		w.buf = w.buf[:len(w.buf)+n]
+		p = p[n:]
+		written += n
+	}
+
+	return written, nil
+}
+
+func (w *writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	return w.seal(flagFinal)
+}
+
+func (w *writer) seal(flag byte) error {
+	if w.counter == ^uint32(0) {
+		return errors.New("too much data for a single stream")
+	}
+
+	ciphertext := w.aead.Seal(nil, nonce(w.prefix, w.counter, flag), w.buf, w.header)
+
+	record := make([]byte, 5, 5+len(ciphertext))
+	record[0] = flag
+	binary.BigEndian.PutUint32(record[1:], uint32(len(ciphertext)))
+	record = append(record, ciphertext...)
+
+	if _, err := w.out.Write(record); err != nil {
+		return err
+	}
+
+	w.counter++
+	w.buf = w.buf[:0]
+	return nil
+}
+
+type reader struct {
+	in      *bufio.Reader
+	aead    cipher.AEAD
+	header  []byte
+	prefix  []byte
+	counter uint32
+	buf     []byte
+	done    bool
+}
+
+// NewReader returns a reader which decrypts the data read from r. The key
+// is selected from the key ring based on the key ID stored in the header.
+func NewReader(r io.Reader, keys KeyRing) (io.Reader, error) {
+	in := bufio.NewReader(r)
+
+	header := make([]byte, len(magic)+1)
+	if _, err := io.ReadFull(in, header); err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	if !IsEncrypted(header) {
+		return nil, errors.New("data is not encrypted")
+	}
+
+	rest := make([]byte, int(header[len(magic)])+noncePrefixSize)
+	if _, err := io.ReadFull(in, rest); err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	header = append(header, rest...)
+
+	keyID := string(rest[:len(rest)-noncePrefixSize])
+	key, ok := keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, keyID)
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reader{
+		in:     in,
+		aead:   aead,
+		header: header,
+		prefix: rest[len(rest)-noncePrefixSize:],
+	}, nil
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (r *reader) open() error {
+	recordHeader := make([]byte, 5)
+	if _, err := io.ReadFull(r.in, recordHeader); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return ErrTruncated
+		}
+		return err
+	}
+
+	flag := recordHeader[0]
+	length := binary.BigEndian.Uint32(recordHeader[1:])
+	if flag != flagIntermediate && flag != flagFinal {
+		return fmt.Errorf("invalid chunk flag %d", flag)
+	}
+	if length > chunkSize+uint32(r.aead.Overhead()) {
+		return fmt.Errorf("chunk of %d bytes exceeds the maximum chunk size", length)
+	}
+
+	ciphertext := make([]byte, length)
+	if _, err := io.ReadFull(r.in, ciphertext); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return ErrTruncated
+		}
+		return err
+	}
+
+	plaintext, err := r.aead.Open(nil, nonce(r.prefix, r.counter, flag), ciphertext, r.header)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt chunk %d: %w", r.counter, err)
+	}
+
+	r.counter++
+	r.buf = plaintext
+
+	if flag == flagFinal {
+		if _, err := r.in.Peek(1); !errors.Is(err, io.EOF) {
+			return errors.New("unexpected data after final chunk")
+		}
+		r.done = true
+	}
+
+	return nil
+}
+
+// EncryptFile encrypts src using the given key and writes the result to dst.
+func EncryptFile(src, dst, keyID string, key []byte) error {
+	return transformFile(src, dst, func(out io.Writer, in io.Reader) error {
+		w, err := NewWriter(out, keyID, key)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(w, in); err != nil {
+			return err
+		}
+		return w.Close()
+	})
+}
+
+// DecryptFile decrypts src using the matching key of the key ring and writes the result to dst.
+func DecryptFile(src, dst string, keys KeyRing) error {
+	return transformFile(src, dst, func(out io.Writer, in io.Reader) error {
+		r, err := NewReader(in, keys)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(out, r)
+		return err
+	})
+}
+
+// DecryptFileInPlace decrypts the given file if it is encrypted and leaves it untouched
+// otherwise, so that backups taken before encryption was enabled can still be restored.
+// It returns whether the file was encrypted.
+func DecryptFileInPlace(filename string, keys KeyRing) (bool, error) {
+	encrypted, err := IsEncryptedFile(filename)
+	if err != nil || !encrypted {
+		return false, err
+	}
+
+	decrypted := filename + ".decrypted"
+	if err := DecryptFile(filename, decrypted, keys); err != nil {
+		return true, err
+	}
+
+	return true, os.Rename(decrypted, filename)
+}
+
+func transformFile(src, dst string, transform func(out io.Writer, in io.Reader) error) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	if err := transform(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"testing"
+)
+
+func genKey(t *testing.T) []byte {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return key
+}
+
+func encrypt(t *testing.T, plaintext []byte, keyID string, key []byte) []byte {
+	out := &bytes.Buffer{}
+	w, err := NewWriter(out, keyID, key)
+	if err != nil {
+		t.Fatalf("failed to create writer: %v", err)
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to close writer: %v", err)
+	}
+	return out.Bytes()
+}
+
+func decrypt(ciphertext []byte, keys KeyRing) ([]byte, error) {
+	r, err := NewReader(bytes.NewReader(ciphertext), keys)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(r)
+}
+
+func TestRoundtrip(t *testing.T) {
+	key := genKey(t)
+
+	for _, size := range []int{0, 1, chunkSize - 1, chunkSize, chunkSize + 1, 3*chunkSize + 17} {
+		plaintext := make([]byte, size)
+		if _, err := rand.Read(plaintext); err != nil {
+			t.Fatalf("failed to generate data: %v", err)
+		}
+
+		ciphertext := encrypt(t, plaintext, "key-1", key)
+		if !IsEncrypted(ciphertext) {
+			t.Fatalf("size %d: encrypted data is not detected as encrypted", size)
+		}
+
+		keyID, err := KeyID(ciphertext)
+		if err != nil || keyID != "key-1" {
+			t.Fatalf("size %d: expected key ID %q, got %q (%v)", size, "key-1", keyID, err)
+		}
+
+		decrypted, err := decrypt(ciphertext, KeyRing{"key-1": key})
+		if err != nil {
+			t.Fatalf("size %d: failed to decrypt: %v", size, err)
+		}
+		if !bytes.Equal(decrypted, plaintext) {
+			t.Fatalf("size %d: decrypted data differs from the original", size)
+		}
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	oldKey := genKey(t)
+	newKey := genKey(t)
+
+	oldBackup := encrypt(t, []byte("old"), "key-1", oldKey)
+	newBackup := encrypt(t, []byte("new"), "key-2", newKey)
+
+	keys := KeyRing{"key-1": oldKey, "key-2": newKey}
+	for name, backup := range map[string][]byte{"old": oldBackup, "new": newBackup} {
+		decrypted, err := decrypt(backup, keys)
+		if err != nil {
+			t.Fatalf("failed to decrypt %s backup: %v", name, err)
+		}
+		if string(decrypted) != name {
+			t.Fatalf("expected %q, got %q", name, decrypted)
+		}
+	}
+
+	if _, err := decrypt(oldBackup, KeyRing{"key-2": newKey}); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected ErrUnknownKey after removing the old key, got %v", err)
+	}
+}
+
+func TestTampering(t *testing.T) {
+	key := genKey(t)
+	keys := KeyRing{"key-1": key}
+	ciphertext := encrypt(t, bytes.Repeat([]byte("a"), 2*chunkSize+5), "key-1", key)
+
+	testCases := []struct {
+		name   string
+		modify func([]byte) []byte
+	}{
+		{
+			name: "flipped bit",
+			modify: func(data []byte) []byte {
+				data[len(data)/2] ^= 1
+				return data
+			},
+		},
+		{
+			name: "truncated at chunk boundary",
+			modify: func(data []byte) []byte {
+				headerLength := len(magic) + 1 + len("key-1") + noncePrefixSize
+				return data[:headerLength+5+chunkSize+16]
+			},
+		},
+		{
+			name: "trailing data",
+			modify: func(data []byte) []byte {
+				return append(data, 0)
+			},
+		},
+		{
+			name: "wrong key with same ID",
+			modify: func(data []byte) []byte {
+				keys["key-1"] = genKey(t)
+				return data
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := tc.modify(append([]byte{}, ciphertext...))
+			if _, err := decrypt(data, keys); err == nil {
+				t.Fatal("expected decryption to fail")
+			}
+		})
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	key := genKey(t)
+
+	parsed, err := ParseKey([]byte(base64.StdEncoding.EncodeToString(key) + "\n"))
+	if err != nil {
+		t.Fatalf("failed to parse key: %v", err)
+	}
+	if !bytes.Equal(parsed, key) {
+		t.Fatal("parsed key differs from original")
+	}
+
+	if _, err := ParseKey([]byte(base64.StdEncoding.EncodeToString(key[:16]))); err == nil {
+		t.Fatal("expected short key to be rejected")
+	}
+	if _, err := ParseKey(key); err == nil {
+		t.Fatal("expected raw key to be rejected")
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package retention implements the grandfather-father-son retention policy for
+// etcd backups. It is shared between the etcd backup controller, which decides
+// which backups of an EtcdBackupConfig to delete, and the s3-storeuploader used
+// by the legacy backup path.
+package retention
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Policy decides which backups are kept. A backup is kept if it is one of the
+// Last most recent backups, or if it is the newest backup of one of the most
+// recent Hourly hours, Daily days, Weekly weeks or Monthly months that contain
+// a backup. Periods are evaluated in UTC, weeks start on Monday.
+// The most recent backup is always kept.
+type Policy struct {
+	Last    int
+	Hourly  int
+	Daily   int
+	Weekly  int
+	Monthly int
+}
+
+// MaxKept returns the maximum number of backups the policy can keep.
+func (p Policy) MaxKept() int {
+	n := p.Last + p.Hourly + p.Daily + p.Weekly + p.Monthly
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// Keep returns, for every given backup time, whether the backup should be kept.
+// The times do not need to be sorted.
+func (p Policy) Keep(times []time.Time) []bool {
+	keep := make([]bool, len(times))
+	if len(times) == 0 {
+		return keep
+	}
+
+	// indices of the backups, newest first
+	order := make([]int, len(times))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return times[order[i]].After(times[order[j]])
+	})
+
+	keep[order[0]] = true
+	for i := 0; i < p.Last && i < len(order); i++ {
+		keep[order[i]] = true
+	}
+
+	buckets := []struct {
+		count int
+		key   func(time.Time) string
+	}{
+		{p.Hourly, func(t time.Time) string { return t.Format("2006-01-02T15") }},
+		{p.Daily, func(t time.Time) string { return t.Format("2006-01-02") }},
+		{p.Weekly, func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-%d", year, week)
+		}},
+		{p.Monthly, func(t time.Time) string { return t.Format("2006-01") }},
+	}
+
+	for _, bucket := range buckets {
+		remaining := bucket.count
+		lastKey := ""
+		for _, idx := range order {
+			if remaining <= 0 {
+				break
+			}
+			// as backups are ordered newest first, the first backup of every
+			// period is the newest one
+			if key := bucket.key(times[idx].UTC()); key != lastKey {
+				keep[idx] = true
+				lastKey = key
+				remaining--
+			}
+		}
+	}
+
+	return keep
+}
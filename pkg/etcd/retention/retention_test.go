@@ -0,0 +1,161 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retention
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKeep(t *testing.T) {
+	// a backup every 6 hours for 70 days, starting on Monday 2021-03-01
+	start := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	var every6h []time.Time
+	for i := 0; i < 70*4; i++ {
+		every6h = append(every6h, start.Add(time.Duration(i)*6*time.Hour))
+	}
+	newest := every6h[len(every6h)-1] // 2021-05-09T18:00
+
+	testCases := []struct {
+		name     string
+		policy   Policy
+		times    []time.Time
+		expected []time.Time
+	}{
+		{
+			name:     "no backups",
+			policy:   Policy{Last: 3},
+			times:    nil,
+			expected: nil,
+		},
+		{
+			name:   "last n backups",
+			policy: Policy{Last: 2},
+			times:  every6h,
+			expected: []time.Time{
+				newest.Add(-6 * time.Hour),
+				newest,
+			},
+		},
+		{
+			name:     "newest backup is always kept",
+			policy:   Policy{},
+			times:    every6h,
+			expected: []time.Time{newest},
+		},
+		{
+			name:   "hourly keeps the newest backup of every hour containing backups",
+			policy: Policy{Hourly: 3},
+			times:  every6h,
+			expected: []time.Time{
+				newest.Add(-12 * time.Hour),
+				newest.Add(-6 * time.Hour),
+				newest,
+			},
+		},
+		{
+			name:   "daily",
+			policy: Policy{Daily: 3},
+			times:  every6h,
+			expected: []time.Time{
+				newest.Add(-48 * time.Hour),
+				newest.Add(-24 * time.Hour),
+				newest,
+			},
+		},
+		{
+			name:   "weekly keeps the last backup of every week",
+			policy: Policy{Weekly: 3},
+			times:  every6h,
+			expected: []time.Time{
+				time.Date(2021, 4, 25, 18, 0, 0, 0, time.UTC), // Sunday
+				time.Date(2021, 5, 2, 18, 0, 0, 0, time.UTC),
+				newest,
+			},
+		},
+		{
+			name:   "monthly keeps the last backup of every month",
+			policy: Policy{Monthly: 5},
+			times:  every6h,
+			expected: []time.Time{
+				time.Date(2021, 3, 31, 18, 0, 0, 0, time.UTC),
+				time.Date(2021, 4, 30, 18, 0, 0, 0, time.UTC),
+				newest,
+			},
+		},
+		{
+			name:   "rules are combined",
+			policy: Policy{Last: 1, Daily: 2, Monthly: 2},
+			times:  every6h,
+			expected: []time.Time{
+				time.Date(2021, 4, 30, 18, 0, 0, 0, time.UTC),
+				newest.Add(-24 * time.Hour),
+				newest,
+			},
+		},
+		{
+			name:   "unsorted input",
+			policy: Policy{Daily: 2},
+			times: []time.Time{
+				time.Date(2021, 3, 2, 6, 0, 0, 0, time.UTC),
+				time.Date(2021, 3, 1, 6, 0, 0, 0, time.UTC),
+				time.Date(2021, 3, 2, 12, 0, 0, 0, time.UTC),
+				time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC),
+			},
+			expected: []time.Time{
+				time.Date(2021, 3, 2, 12, 0, 0, 0, time.UTC),
+				time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			keep := tc.policy.Keep(tc.times)
+			if len(keep) != len(tc.times) {
+				t.Fatalf("expected %d results, got %d", len(tc.times), len(keep))
+			}
+
+			expected := map[time.Time]bool{}
+			for _, e := range tc.expected {
+				expected[e] = true
+			}
+
+			kept := 0
+			for i, k := range keep {
+				if k {
+					kept++
+				}
+				if k != expected[tc.times[i]] {
+					t.Errorf("backup at %s: expected kept=%v, got %v", tc.times[i], expected[tc.times[i]], k)
+				}
+			}
+			if kept != len(tc.expected) {
+				t.Errorf("expected %d backups to be kept, got %d", len(tc.expected), kept)
+			}
+		})
+	}
+}
+
+func TestMaxKept(t *testing.T) {
+	if n := (Policy{}).MaxKept(); n != 1 {
+		t.Errorf("expected empty policy to keep 1 backup, got %d", n)
+	}
+	if n := (Policy{Last: 2, Hourly: 24, Daily: 7, Weekly: 4, Monthly: 12}).MaxKept(); n != 49 {
+		t.Errorf("expected 49, got %d", n)
+	}
+}
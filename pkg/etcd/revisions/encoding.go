@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revisions
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+)
+
+// maxEventSize is the upper bound for a single encoded event. etcd limits
+// requests to 1.5MiB by default, so anything above this indicates a corrupt segment.
+const maxEventSize = 16 * 1024 * 1024
+
+// Record is a batch of etcd events that were observed at the same time.
+type Record struct {
+	Time   time.Time
+	Events []*mvccpb.Event
+}
+
+// EncodeRecords writes the records to w. Each record is encoded as its timestamp
+// in nanoseconds and its number of events, followed by the length prefixed
+// protobuf encoding of each event.
+func EncodeRecords(w io.Writer, records []Record) error {
+	buf := make([]byte, binary.MaxVarintLen64)
+
+	writeVarint := func(v int64) error {
+		n := binary.PutVarint(buf, v)
+		_, err := w.Write(buf[:n])
+		return err
+	}
+
+	for _, record := range records {
+		if err := writeVarint(record.Time.UnixNano()); err != nil {
+			return err
+		}
+		if err := writeVarint(int64(len(record.Events))); err != nil {
+			return err
+		}
+		for _, event := range record.Events {
+			data, err := event.Marshal()
+			if err != nil {
+				return fmt.Errorf("failed to marshal event: %w", err)
+			}
+			if err := writeVarint(int64(len(data))); err != nil {
+				return err
+			}
+			if _, err := w.Write(data); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// DecodeRecords reads all records written by EncodeRecords from r.
+func DecodeRecords(r io.Reader) ([]Record, error) {
+	reader := bufio.NewReader(r)
+
+	var records []Record
+	for {
+		timestamp, err := binary.ReadVarint(reader)
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read record time: %w", err)
+		}
+
+		count, err := binary.ReadVarint(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read event count: %w", err)
+		}
+		if count < 0 {
+			return nil, fmt.Errorf("invalid event count %d", count)
+		}
+
+		record := Record{Time: time.Unix(0, timestamp)}
+		for i := int64(0); i < count; i++ {
+			size, err := binary.ReadVarint(reader)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read event size: %w", err)
+			}
+			if size < 0 || size > maxEventSize {
+				return nil, fmt.Errorf("invalid event size %d", size)
+			}
+
+			data := make([]byte, size)
+			if _, err := io.ReadFull(reader, data); err != nil {
+				return nil, fmt.Errorf("failed to read event: %w", err)
+			}
+
+			event := &mvccpb.Event{}
+			if err := event.Unmarshal(data); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal event: %w", err)
+			}
+			record.Events = append(record.Events, event)
+		}
+
+		records = append(records, record)
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revisions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// Applier is the subset of clientv3.Client needed to replay events.
+type Applier interface {
+	Txn(ctx context.Context) clientv3.Txn
+	TimeToLive(ctx context.Context, id clientv3.LeaseID, opts ...clientv3.LeaseOption) (*clientv3.LeaseTimeToLiveResponse, error)
+}
+
+// Replay applies all events from the records which happened after snapshotRevision
+// and are included in the target. The events of a revision are applied in a single
+// transaction, so that the revisions of the restored etcd correspond to the original
+// ones. Leases are not shipped, so keys attached to a lease are only replayed if the
+// lease exists in the snapshot and are deleted otherwise, as they would have been
+// removed once their lease expired. It returns the last replayed revision and whether
+// the target has been reached, i.e. whether later records exist that are not part of
+// the target.
+func Replay(ctx context.Context, applier Applier, records []Record, snapshotRevision int64, target Target) (int64, bool, error) {
+	r := &replayer{
+		applier:      applier,
+		lastRevision: snapshotRevision,
+		leases:       map[int64]bool{},
+	}
+
+	for _, record := range records {
+		for _, event := range record.Events {
+			revision := event.Kv.ModRevision
+			if revision <= r.lastRevision {
+				continue
+			}
+			if revision != r.revision {
+				if err := r.commit(ctx); err != nil {
+					return r.lastRevision, false, err
+				}
+				if !target.includes(revision, record.Time) {
+					return r.lastRevision, true, nil
+				}
+				r.revision = revision
+			}
+
+			op, err := r.op(ctx, event)
+			if err != nil {
+				return r.lastRevision, false, fmt.Errorf("failed to replay revision %d: %w", revision, err)
+			}
+			r.ops = append(r.ops, op)
+		}
+	}
+
+	if err := r.commit(ctx); err != nil {
+		return r.lastRevision, false, err
+	}
+	return r.lastRevision, false, nil
+}
+
+// replayer collects the operations of a revision.
+type replayer struct {
+	applier      Applier
+	lastRevision int64
+	revision     int64
+	ops          []clientv3.Op
+	// leases caches whether the leases exist in the snapshot.
+	leases map[int64]bool
+}
+
+func (r *replayer) op(ctx context.Context, event *mvccpb.Event) (clientv3.Op, error) {
+	key := string(event.Kv.Key)
+
+	switch event.Type {
+	case mvccpb.PUT:
+		if event.Kv.Lease == 0 {
+			return clientv3.OpPut(key, string(event.Kv.Value)), nil
+		}
+		exists, err := r.leaseExists(ctx, event.Kv.Lease)
+		if err != nil {
+			return clientv3.Op{}, err
+		}
+		if !exists {
+			return clientv3.OpDelete(key), nil
+		}
+		return clientv3.OpPut(key, string(event.Kv.Value), clientv3.WithLease(clientv3.LeaseID(event.Kv.Lease))), nil
+	case mvccpb.DELETE:
+		return clientv3.OpDelete(key), nil
+	default:
+		return clientv3.Op{}, fmt.Errorf("unknown event type %v", event.Type)
+	}
+}
+
+func (r *replayer) leaseExists(ctx context.Context, id int64) (bool, error) {
+	if exists, ok := r.leases[id]; ok {
+		return exists, nil
+	}
+
+	resp, err := r.applier.TimeToLive(ctx, clientv3.LeaseID(id))
+	if err != nil && !errors.Is(err, rpctypes.ErrLeaseNotFound) {
+		return false, fmt.Errorf("failed to get lease %x: %w", id, err)
+	}
+	exists := err == nil && resp.TTL > 0
+
+	r.leases[id] = exists
+	return exists, nil
+}
+
+// commit applies the operations of the current revision.
+func (r *replayer) commit(ctx context.Context) error {
+	if len(r.ops) == 0 {
+		return nil
+	}
+	if _, err := r.applier.Txn(ctx).Then(r.ops...).Commit(); err != nil {
+		return fmt.Errorf("failed to replay revision %d: %w", r.revision, err)
+	}
+	r.lastRevision = r.revision
+	r.ops = nil
+	return nil
+}
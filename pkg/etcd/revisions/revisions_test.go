@@ -0,0 +1,341 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revisions
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+func putEvent(key, value string, revision int64) *mvccpb.Event {
+	return &mvccpb.Event{
+		Type: mvccpb.PUT,
+		Kv:   &mvccpb.KeyValue{Key: []byte(key), Value: []byte(value), ModRevision: revision},
+	}
+}
+
+func deleteEvent(key string, revision int64) *mvccpb.Event {
+	return &mvccpb.Event{
+		Type: mvccpb.DELETE,
+		Kv:   &mvccpb.KeyValue{Key: []byte(key), ModRevision: revision},
+	}
+}
+
+func TestObjectNameRoundtrip(t *testing.T) {
+	segment := Segment{FirstRevision: 11, LastRevision: 42, Time: time.Unix(1620000000, 0)}
+
+	objectName := segment.ObjectName("testcluster")
+	if objectName != "testcluster-revisions/00000000000000000011-00000000000000000042-1620000000" {
+		t.Fatalf("unexpected object name %q", objectName)
+	}
+
+	parsed, err := ParseObjectName("testcluster", objectName)
+	if err != nil {
+		t.Fatalf("failed to parse object name: %v", err)
+	}
+	if diff := deep.Equal(parsed, segment); diff != nil {
+		t.Errorf("parsed segment differs from original: %v", diff)
+	}
+
+	if _, err := ParseObjectName("testcluster", "testcluster-backup-2021-05-01t10-00-00"); err == nil {
+		t.Error("expected snapshot object name to be rejected")
+	}
+}
+
+func TestEncodeDecodeRecords(t *testing.T) {
+	records := []Record{
+		{
+			Time:   time.Unix(0, 1620000000000000001),
+			Events: []*mvccpb.Event{putEvent("/a", "1", 5), putEvent("/b", "2", 5)},
+		},
+		{
+			Time:   time.Unix(0, 1620000000000000002),
+			Events: []*mvccpb.Event{deleteEvent("/a", 6)},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	if err := EncodeRecords(buf, records); err != nil {
+		t.Fatalf("failed to encode records: %v", err)
+	}
+
+	decoded, err := DecodeRecords(buf)
+	if err != nil {
+		t.Fatalf("failed to decode records: %v", err)
+	}
+	if diff := deep.Equal(decoded, records); diff != nil {
+		t.Errorf("decoded records differ from original: %v", diff)
+	}
+}
+
+func TestSelectSegments(t *testing.T) {
+	t1 := time.Unix(1000, 0)
+	t2 := time.Unix(2000, 0)
+	t3 := time.Unix(3000, 0)
+
+	segments := []Segment{
+		{FirstRevision: 21, LastRevision: 30, Time: t3},
+		{FirstRevision: 1, LastRevision: 10, Time: t1},
+		{FirstRevision: 11, LastRevision: 20, Time: t2},
+	}
+
+	testCases := []struct {
+		name             string
+		segments         []Segment
+		snapshotRevision int64
+		target           Target
+		expected         []Segment
+		expectErr        bool
+	}{
+		{
+			name:             "all segments after the snapshot",
+			segments:         segments,
+			snapshotRevision: 15,
+			expected:         []Segment{segments[2], segments[0]},
+		},
+		{
+			name:             "stop at target revision",
+			segments:         segments,
+			snapshotRevision: 5,
+			target:           Target{Revision: 18},
+			expected:         []Segment{segments[1], segments[2]},
+		},
+		{
+			name:             "stop at target time",
+			segments:         segments,
+			snapshotRevision: 5,
+			target:           Target{Time: &t1},
+			// segment times are truncated to seconds, so the first newer segment is always included
+			expected: []Segment{segments[1], segments[2]},
+		},
+		{
+			name:             "target revision not shipped yet",
+			segments:         segments,
+			snapshotRevision: 5,
+			target:           Target{Revision: 31},
+			expectErr:        true,
+		},
+		{
+			name:             "gap between segments",
+			segments:         []Segment{segments[1], segments[0]},
+			snapshotRevision: 5,
+			expectErr:        true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			selected, err := SelectSegments(tc.segments, tc.snapshotRevision, tc.target)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := deep.Equal(selected, tc.expected); diff != nil {
+				t.Errorf("unexpected segments: %v", diff)
+			}
+		})
+	}
+}
+
+type fakeApplier struct {
+	data   map[string]string
+	leases map[clientv3.LeaseID]bool
+	txns   [][]clientv3.Op
+}
+
+func (f *fakeApplier) Txn(_ context.Context) clientv3.Txn {
+	return &fakeTxn{applier: f}
+}
+
+func (f *fakeApplier) TimeToLive(_ context.Context, id clientv3.LeaseID, _ ...clientv3.LeaseOption) (*clientv3.LeaseTimeToLiveResponse, error) {
+	if !f.leases[id] {
+		return &clientv3.LeaseTimeToLiveResponse{ID: id, TTL: -1}, nil
+	}
+	return &clientv3.LeaseTimeToLiveResponse{ID: id, TTL: 60}, nil
+}
+
+type fakeTxn struct {
+	applier *fakeApplier
+	ops     []clientv3.Op
+}
+
+func (f *fakeTxn) If(_ ...clientv3.Cmp) clientv3.Txn {
+	return f
+}
+
+func (f *fakeTxn) Then(ops ...clientv3.Op) clientv3.Txn {
+	f.ops = append(f.ops, ops...)
+	return f
+}
+
+func (f *fakeTxn) Else(_ ...clientv3.Op) clientv3.Txn {
+	return f
+}
+
+func (f *fakeTxn) Commit() (*clientv3.TxnResponse, error) {
+	for _, op := range f.ops {
+		switch {
+		case op.IsPut():
+			f.applier.data[string(op.KeyBytes())] = string(op.ValueBytes())
+		case op.IsDelete():
+			delete(f.applier.data, string(op.KeyBytes()))
+		}
+	}
+	f.applier.txns = append(f.applier.txns, f.ops)
+	return &clientv3.TxnResponse{}, nil
+}
+
+func TestReplay(t *testing.T) {
+	t1 := time.Unix(1000, 0)
+	t2 := time.Unix(2000, 0)
+	t3 := time.Unix(3000, 0)
+
+	records := []Record{
+		{Time: t1, Events: []*mvccpb.Event{putEvent("/a", "1", 4), putEvent("/b", "1", 5)}},
+		{Time: t2, Events: []*mvccpb.Event{putEvent("/a", "2", 6), deleteEvent("/b", 7)}},
+		{Time: t3, Events: []*mvccpb.Event{putEvent("/c", "1", 8)}},
+	}
+
+	testCases := []struct {
+		name            string
+		target          Target
+		expectedData    map[string]string
+		expectedLast    int64
+		expectedReached bool
+	}{
+		{
+			name:         "replay everything",
+			expectedData: map[string]string{"/a": "2", "/c": "1"},
+			expectedLast: 8,
+		},
+		{
+			name:            "replay up to revision",
+			target:          Target{Revision: 6},
+			expectedData:    map[string]string{"/a": "2", "/b": "1"},
+			expectedLast:    6,
+			expectedReached: true,
+		},
+		{
+			name:            "replay up to time",
+			target:          Target{Time: &t2},
+			expectedData:    map[string]string{"/a": "2"},
+			expectedLast:    7,
+			expectedReached: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// the snapshot was taken at revision 4, so the first event must be skipped
+			applier := &fakeApplier{data: map[string]string{"/a": "1", "/b": "0"}}
+
+			last, reached, err := Replay(context.Background(), applier, records, 4, tc.target)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if last != tc.expectedLast {
+				t.Errorf("expected last revision %d, got %d", tc.expectedLast, last)
+			}
+			if reached != tc.expectedReached {
+				t.Errorf("expected reached to be %v, got %v", tc.expectedReached, reached)
+			}
+			if diff := deep.Equal(applier.data, tc.expectedData); diff != nil {
+				t.Errorf("unexpected data after replay: %v", diff)
+			}
+		})
+	}
+}
+
+func TestReplayTransactions(t *testing.T) {
+	leasedPutEvent := func(key, value string, revision, lease int64) *mvccpb.Event {
+		event := putEvent(key, value, revision)
+		event.Kv.Lease = lease
+		return event
+	}
+
+	records := []Record{
+		{Time: time.Unix(1000, 0), Events: []*mvccpb.Event{putEvent("/a", "1", 5), putEvent("/b", "1", 5), putEvent("/c", "1", 6)}},
+		// the events of a revision can be split across records
+		{Time: time.Unix(1000, 0), Events: []*mvccpb.Event{deleteEvent("/a", 6)}},
+		{Time: time.Unix(2000, 0), Events: []*mvccpb.Event{leasedPutEvent("/leased", "1", 7, 1), leasedPutEvent("/expired", "1", 7, 2)}},
+	}
+
+	testCases := []struct {
+		name         string
+		target       Target
+		expectedData map[string]string
+		expectedTxns [][]clientv3.Op
+		expectedLast int64
+	}{
+		{
+			name:         "replay everything",
+			expectedData: map[string]string{"/b": "1", "/c": "1", "/leased": "1"},
+			expectedTxns: [][]clientv3.Op{
+				{clientv3.OpPut("/a", "1"), clientv3.OpPut("/b", "1")},
+				{clientv3.OpPut("/c", "1"), clientv3.OpDelete("/a")},
+				{clientv3.OpPut("/leased", "1", clientv3.WithLease(1)), clientv3.OpDelete("/expired")},
+			},
+			expectedLast: 7,
+		},
+		{
+			name:         "replay up to revision",
+			target:       Target{Revision: 6},
+			expectedData: map[string]string{"/b": "1", "/c": "1", "/expired": "0"},
+			expectedTxns: [][]clientv3.Op{
+				{clientv3.OpPut("/a", "1"), clientv3.OpPut("/b", "1")},
+				{clientv3.OpPut("/c", "1"), clientv3.OpDelete("/a")},
+			},
+			expectedLast: 6,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// only the lease of /leased exists in the snapshot
+			applier := &fakeApplier{
+				data:   map[string]string{"/expired": "0"},
+				leases: map[clientv3.LeaseID]bool{1: true},
+			}
+
+			last, _, err := Replay(context.Background(), applier, records, 4, tc.target)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if last != tc.expectedLast {
+				t.Errorf("expected last revision %d, got %d", tc.expectedLast, last)
+			}
+			if !reflect.DeepEqual(applier.txns, tc.expectedTxns) {
+				t.Errorf("expected transactions %v, got %v", tc.expectedTxns, applier.txns)
+			}
+			if diff := deep.Equal(applier.data, tc.expectedData); diff != nil {
+				t.Errorf("unexpected data after replay: %v", diff)
+			}
+		})
+	}
+}
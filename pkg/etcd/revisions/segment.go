@@ -0,0 +1,156 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package revisions implements the continuous shipping of etcd revisions
+// to an S3 compatible storage and the replay of these revisions on top of
+// a restored snapshot, which allows point-in-time restores of etcd.
+package revisions
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// objectPrefixSuffix is appended to the cluster name to form the prefix of all
+// segment objects of a cluster. The slash makes sure that segments are never
+// mistaken for snapshots, which are stored as <cluster>-<backup name>.
+const objectPrefixSuffix = "-revisions/"
+
+// Segment is a contiguous range of etcd revisions stored as a single object.
+type Segment struct {
+	// FirstRevision is the first revision covered by the segment. Segments
+	// are contiguous, so FirstRevision is always the LastRevision of the
+	// previous segment + 1.
+	FirstRevision int64
+	// LastRevision is the last revision contained in the segment.
+	LastRevision int64
+	// Time is the time at which the last change in the segment was observed.
+	Time time.Time
+}
+
+// ObjectPrefix returns the prefix of all segment objects of the given cluster.
+func ObjectPrefix(clusterName string) string {
+	return clusterName + objectPrefixSuffix
+}
+
+// ObjectName returns the name of the object the segment is stored in.
+func (s Segment) ObjectName(clusterName string) string {
+	return fmt.Sprintf("%s%020d-%020d-%d", ObjectPrefix(clusterName), s.FirstRevision, s.LastRevision, s.Time.Unix())
+}
+
+// ParseObjectName parses a segment object name as created by ObjectName.
+func ParseObjectName(clusterName, objectName string) (Segment, error) {
+	prefix := ObjectPrefix(clusterName)
+	if !strings.HasPrefix(objectName, prefix) {
+		return Segment{}, fmt.Errorf("object %q does not have prefix %q", objectName, prefix)
+	}
+
+	parts := strings.Split(strings.TrimPrefix(objectName, prefix), "-")
+	if len(parts) != 3 {
+		return Segment{}, fmt.Errorf("object %q is not a revision segment", objectName)
+	}
+
+	first, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return Segment{}, fmt.Errorf("invalid first revision in %q: %w", objectName, err)
+	}
+	last, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return Segment{}, fmt.Errorf("invalid last revision in %q: %w", objectName, err)
+	}
+	timestamp, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return Segment{}, fmt.Errorf("invalid timestamp in %q: %w", objectName, err)
+	}
+
+	if last < first {
+		return Segment{}, fmt.Errorf("object %q ends before it starts", objectName)
+	}
+
+	return Segment{
+		FirstRevision: first,
+		LastRevision:  last,
+		Time:          time.Unix(timestamp, 0),
+	}, nil
+}
+
+// Target describes the point in time to which revisions are replayed. If both
+// fields are unset, all available revisions are replayed.
+type Target struct {
+	// Revision is the last revision to replay.
+	Revision int64
+	// Time is the time up to which changes are replayed.
+	Time *time.Time
+}
+
+// IsZero returns true if the target doesn't limit the replay.
+func (t Target) IsZero() bool {
+	return t.Revision <= 0 && t.Time == nil
+}
+
+// includes returns true if a change at the given revision and time must be replayed.
+func (t Target) includes(revision int64, timestamp time.Time) bool {
+	if t.Revision > 0 && revision > t.Revision {
+		return false
+	}
+	if t.Time != nil && timestamp.After(*t.Time) {
+		return false
+	}
+	return true
+}
+
+// SelectSegments returns the segments that need to be replayed on top of a
+// snapshot taken at snapshotRevision to reach the given target. It returns an
+// error if the available segments have a gap or do not reach the target revision.
+func SelectSegments(segments []Segment, snapshotRevision int64, target Target) ([]Segment, error) {
+	sorted := make([]Segment, len(segments))
+	copy(sorted, segments)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].FirstRevision < sorted[j].FirstRevision
+	})
+
+	var selected []Segment
+	next := snapshotRevision + 1
+	for _, segment := range sorted {
+		if segment.LastRevision < next {
+			// fully contained in the snapshot or in a previously selected segment
+			continue
+		}
+		if segment.FirstRevision > next {
+			return nil, fmt.Errorf("revisions %d to %d are missing", next, segment.FirstRevision-1)
+		}
+		if target.Revision > 0 && next > target.Revision {
+			break
+		}
+
+		selected = append(selected, segment)
+		next = segment.LastRevision + 1
+
+		if target.Time != nil && segment.Time.After(*target.Time) {
+			// every following segment only contains newer changes
+			break
+		}
+	}
+
+	if target.Revision > 0 && next <= target.Revision {
+		return nil, fmt.Errorf("revision %d has not been shipped yet, the latest available revision is %d", target.Revision, next-1)
+	}
+
+	return selected, nil
+}
@@ -0,0 +1,260 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revisions
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+
+	"k8c.io/kubermatic/v2/pkg/backupstore"
+	"k8c.io/kubermatic/v2/pkg/etcd/encryption"
+)
+
+// ListSegments returns all segments stored for the given cluster.
+func ListSegments(ctx context.Context, store backupstore.Store, clusterName string) ([]Segment, error) {
+	objects, err := store.List(ctx, ObjectPrefix(clusterName))
+	if err != nil {
+		return nil, err
+	}
+
+	var segments []Segment
+	for _, object := range objects {
+		segment, err := ParseObjectName(clusterName, object.Name)
+		if err != nil {
+			// not a segment, ignore it
+			continue
+		}
+		segments = append(segments, segment)
+	}
+
+	return segments, nil
+}
+
+// DownloadSegment downloads and decodes a single segment. Encrypted segments
+// are decrypted using the given key ring.
+func DownloadSegment(ctx context.Context, store backupstore.Store, clusterName string, segment Segment, keys encryption.KeyRing) ([]Record, error) {
+	object, err := store.Download(ctx, segment.ObjectName(clusterName))
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	buffered := bufio.NewReader(object)
+	header, err := buffered.Peek(encryption.MagicLength)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if !encryption.IsEncrypted(header) {
+		return DecodeRecords(buffered)
+	}
+
+	decrypted, err := encryption.NewReader(buffered, keys)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeRecords(decrypted)
+}
+
+// Shipper watches all keys of an etcd cluster and continuously uploads the
+// observed changes as segments.
+type Shipper struct {
+	Client      *clientv3.Client
+	Store       backupstore.Store
+	ClusterName string
+	// FlushInterval is the maximum time changes are buffered before they are uploaded.
+	FlushInterval time.Duration
+	// Retention is the time after which uploaded segments are deleted.
+	Retention time.Duration
+	// EncryptionKeyID and EncryptionKey are used to encrypt segments before
+	// they are uploaded. If no key is set, segments are stored unencrypted.
+	EncryptionKeyID string
+	EncryptionKey   []byte
+	Log             *zap.SugaredLogger
+
+	nextRevision int64
+	buffer       []Record
+}
+
+// Run ships revisions until the context is cancelled.
+func (s *Shipper) Run(ctx context.Context) error {
+	segments, err := ListSegments(ctx, s.Store, s.ClusterName)
+	if err != nil {
+		return fmt.Errorf("failed to list existing segments: %w", err)
+	}
+
+	for _, segment := range segments {
+		if segment.LastRevision >= s.nextRevision {
+			s.nextRevision = segment.LastRevision + 1
+		}
+	}
+
+	if s.nextRevision == 0 {
+		if err := s.startAtCurrentRevision(ctx); err != nil {
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(s.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.watch(ctx, ticker.C); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			// flush whatever has been collected so far, the context is already done
+			return s.flush(context.Background())
+		}
+	}
+}
+
+// watch consumes the watch stream until either the context is done or the
+// stream breaks because the next revision to ship has already been compacted.
+func (s *Shipper) watch(ctx context.Context, flush <-chan time.Time) error {
+	watchCtx, cancel := context.WithCancel(clientv3.WithRequireLeader(ctx))
+	defer cancel()
+
+	s.Log.Infow("Watching for changes", "revision", s.nextRevision)
+	watchChan := s.Client.Watch(watchCtx, "", clientv3.WithPrefix(), clientv3.WithRev(s.nextRevision))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case <-flush:
+			if err := s.flush(ctx); err != nil {
+				s.Log.Warnw("Failed to upload segment, will retry", zap.Error(err))
+			}
+			if err := s.deleteExpiredSegments(ctx); err != nil {
+				s.Log.Warnw("Failed to delete expired segments", zap.Error(err))
+			}
+
+		case resp, ok := <-watchChan:
+			if !ok {
+				return nil
+			}
+
+			if err := resp.Err(); err != nil {
+				if errors.Is(err, rpctypes.ErrCompacted) {
+					// the revisions we have not shipped yet are gone, there is nothing we can do
+					// except for starting over; a point-in-time restore across this gap will fail
+					s.Log.Warnw("Revisions have been compacted before they could be shipped", "revision", s.nextRevision, "compactRevision", resp.CompactRevision)
+					if err := s.flush(ctx); err != nil {
+						return fmt.Errorf("failed to upload segment: %w", err)
+					}
+					return s.startAtCurrentRevision(ctx)
+				}
+				return fmt.Errorf("watch failed: %w", err)
+			}
+
+			if len(resp.Events) > 0 {
+				events := make([]*mvccpb.Event, len(resp.Events))
+				for i, event := range resp.Events {
+					events[i] = (*mvccpb.Event)(event)
+				}
+				s.buffer = append(s.buffer, Record{Time: time.Now(), Events: events})
+			}
+		}
+	}
+}
+
+func (s *Shipper) startAtCurrentRevision(ctx context.Context) error {
+	resp, err := s.Client.Get(ctx, "/", clientv3.WithCountOnly())
+	if err != nil {
+		return fmt.Errorf("failed to determine current revision: %w", err)
+	}
+	s.nextRevision = resp.Header.Revision + 1
+	s.buffer = nil
+	return nil
+}
+
+func (s *Shipper) flush(ctx context.Context) error {
+	if len(s.buffer) == 0 {
+		return nil
+	}
+
+	last := s.buffer[len(s.buffer)-1]
+	segment := Segment{
+		FirstRevision: s.nextRevision,
+		LastRevision:  last.Events[len(last.Events)-1].Kv.ModRevision,
+		Time:          last.Time,
+	}
+
+	data := &bytes.Buffer{}
+	if err := s.encode(data); err != nil {
+		return err
+	}
+
+	objectName := segment.ObjectName(s.ClusterName)
+	s.Log.Debugw("Uploading segment", "object", objectName)
+	if err := s.Store.Upload(ctx, objectName, data, int64(data.Len())); err != nil {
+		return err
+	}
+
+	s.nextRevision = segment.LastRevision + 1
+	s.buffer = nil
+	return nil
+}
+
+func (s *Shipper) encode(data io.Writer) error {
+	if s.EncryptionKey == nil {
+		return EncodeRecords(data, s.buffer)
+	}
+
+	w, err := encryption.NewWriter(data, s.EncryptionKeyID, s.EncryptionKey)
+	if err != nil {
+		return err
+	}
+	if err := EncodeRecords(w, s.buffer); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func (s *Shipper) deleteExpiredSegments(ctx context.Context) error {
+	if s.Retention <= 0 {
+		return nil
+	}
+
+	segments, err := ListSegments(ctx, s.Store, s.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	threshold := time.Now().Add(-s.Retention)
+	for _, segment := range segments {
+		if segment.Time.Before(threshold) {
+			s.Log.Debugw("Deleting expired segment", "object", segment.ObjectName(s.ClusterName))
+			if err := s.Store.Delete(ctx, segment.ObjectName(s.ClusterName)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
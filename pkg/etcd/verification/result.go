@@ -0,0 +1,56 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package verification contains the result format shared between the etcd
+// backup verifier and the etcd backup controller. The verifier writes the
+// result as the termination message of its container, from where it is read
+// by the controller.
+package verification
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Result describes a successfully verified snapshot.
+type Result struct {
+	// Revision is the etcd revision at which the snapshot was taken.
+	Revision int64 `json:"revision"`
+	// KeyCount is the total number of keys in the snapshot.
+	KeyCount int64 `json:"keyCount"`
+	// Hash is the hash of the snapshot's keyspace, as reported by etcd.
+	Hash string `json:"hash"`
+	// Restored is true if the snapshot has also been restored successfully.
+	Restored bool `json:"restored"`
+}
+
+// Encode returns the result as a termination message.
+func (r *Result) Encode() (string, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Decode parses a termination message written by Encode.
+func Decode(message string) (*Result, error) {
+	result := &Result{}
+	if err := json.Unmarshal([]byte(message), result); err != nil {
+		return nil, fmt.Errorf("failed to parse verification result: %w", err)
+	}
+	return result, nil
+}
@@ -23,12 +23,14 @@ import (
 	"strings"
 	"time"
 
-	"github.com/minio/minio-go"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 
+	"k8c.io/kubermatic/v2/pkg/backupstore"
 	kubermaticclientset "k8c.io/kubermatic/v2/pkg/crd/client/clientset/versioned"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/etcd/revisions"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -37,17 +39,18 @@ type s3Exporter struct {
 	ObjectCount            *prometheus.Desc
 	ObjectLastModifiedDate *prometheus.Desc
 	EmptyObjectCount       *prometheus.Desc
+	LastVerifiedBackupTime *prometheus.Desc
 	QuerySuccess           *prometheus.Desc
 	kubermaticClient       kubermaticclientset.Interface
 	bucket                 string
-	minioClient            *minio.Client
+	store                  backupstore.Store
 	logger                 *zap.SugaredLogger
 }
 
-// MustRun starts a s3 exporter or panic
-func MustRun(minioClient *minio.Client, kubermaticClient kubermaticclientset.Interface, bucket, listenAddress string, logger *zap.SugaredLogger) {
+// MustRun starts a s3 exporter or panic. Despite its name, it exports the metrics of any kind of backup store.
+func MustRun(store backupstore.Store, kubermaticClient kubermaticclientset.Interface, bucket, listenAddress string, logger *zap.SugaredLogger) {
 	exporter := s3Exporter{}
-	exporter.minioClient = minioClient
+	exporter.store = store
 	exporter.kubermaticClient = kubermaticClient
 	exporter.bucket = bucket
 	exporter.logger = logger
@@ -64,6 +67,10 @@ func MustRun(minioClient *minio.Client, kubermaticClient kubermaticclientset.Int
 		"kubermatic_s3_empty_object_count",
 		"The amount of empty objects (size=0) partitioned by cluster",
 		[]string{"cluster"}, nil)
+	exporter.LastVerifiedBackupTime = prometheus.NewDesc(
+		"kubermatic_s3_last_verified_backup_time_seconds",
+		"Time at which the most recent backup was successfully verified, only exported for clusters with backup verification enabled",
+		[]string{"cluster"}, nil)
 	exporter.QuerySuccess = prometheus.NewDesc(
 		"kubermatic_s3_query_success",
 		"Whether querying the S3 was successful",
@@ -83,6 +90,7 @@ func (e *s3Exporter) Describe(ch chan<- *prometheus.Desc) {
 	ch <- e.ObjectCount
 	ch <- e.ObjectLastModifiedDate
 	ch <- e.EmptyObjectCount
+	ch <- e.LastVerifiedBackupTime
 	ch <- e.QuerySuccess
 }
 
@@ -97,33 +105,73 @@ func (e *s3Exporter) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
-	doneCh := make(chan struct{})
-	defer close(doneCh)
-
 	logger := e.logger.With("bucket", e.bucket)
 
-	var objects []minio.ObjectInfo
-	for listerObject := range e.minioClient.ListObjects(e.bucket, "", true, doneCh) {
-		if listerObject.Err != nil {
-			logger.Errorw("Error on object", "object", listerObject.Key, zap.Error(listerObject.Err))
-			ch <- prometheus.MustNewConstMetric(
-				e.QuerySuccess,
-				prometheus.GaugeValue,
-				float64(1))
-			return
-		}
-		objects = append(objects, listerObject)
+	objects, err := e.store.List(context.Background(), "")
+	if err != nil {
+		logger.Errorw("Failed to list objects", zap.Error(err))
+		ch <- prometheus.MustNewConstMetric(
+			e.QuerySuccess,
+			prometheus.GaugeValue,
+			float64(1))
+		return
 	}
 
 	for _, cluster := range clusters.Items {
 		e.setMetricsForCluster(ch, objects, cluster.Name)
 	}
+
+	backupConfigs, err := e.kubermaticClient.KubermaticV1().EtcdBackupConfigs("").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		logger.Errorw("Failed to list etcd backup configs", zap.Error(err))
+		return
+	}
+
+	for cluster, lastVerified := range getLastVerifiedBackupTimes(backupConfigs.Items) {
+		var value float64
+		if !lastVerified.IsZero() {
+			value = float64(lastVerified.Unix())
+		}
+		ch <- prometheus.MustNewConstMetric(
+			e.LastVerifiedBackupTime,
+			prometheus.GaugeValue,
+			value,
+			cluster)
+	}
 }
 
-func (e *s3Exporter) setMetricsForCluster(ch chan<- prometheus.Metric, allObjects []minio.ObjectInfo, clusterName string) {
-	var clusterObjects []minio.ObjectInfo
+// getLastVerifiedBackupTimes returns the time of the last successful backup verification
+// for every cluster with at least one backup config that has verification enabled. Clusters
+// without any verified backup yet are included with the zero time, so that alerts fire for them as well.
+func getLastVerifiedBackupTimes(backupConfigs []kubermaticv1.EtcdBackupConfig) map[string]time.Time {
+	result := map[string]time.Time{}
+	for _, backupConfig := range backupConfigs {
+		if !backupConfig.IsVerificationEnabled() {
+			continue
+		}
+
+		cluster := backupConfig.Spec.Cluster.Name
+		lastVerified := result[cluster]
+		for _, backup := range backupConfig.Status.CurrentBackups {
+			if backup.VerificationPhase == kubermaticv1.BackupStatusPhaseCompleted && backup.VerificationFinishedTime != nil &&
+				backup.VerificationFinishedTime.After(lastVerified) {
+				lastVerified = backup.VerificationFinishedTime.Time
+			}
+		}
+		result[cluster] = lastVerified
+	}
+
+	return result
+}
+
+func (e *s3Exporter) setMetricsForCluster(ch chan<- prometheus.Metric, allObjects []backupstore.ObjectInfo, clusterName string) {
+	var clusterObjects []backupstore.ObjectInfo
 	for _, object := range allObjects {
-		if strings.HasPrefix(object.Key, fmt.Sprintf("%s-", clusterName)) {
+		// shipped revisions of continuous backups are not backups on their own
+		if strings.HasPrefix(object.Name, revisions.ObjectPrefix(clusterName)) {
+			continue
+		}
+		if strings.HasPrefix(object.Name, fmt.Sprintf("%s-", clusterName)) {
 			clusterObjects = append(clusterObjects, object)
 		}
 	}
@@ -145,7 +193,7 @@ func (e *s3Exporter) setMetricsForCluster(ch chan<- prometheus.Metric, allObject
 		clusterName)
 }
 
-func getLastModifiedTimestamp(objects []minio.ObjectInfo) (lastmodifiedTimestamp time.Time) {
+func getLastModifiedTimestamp(objects []backupstore.ObjectInfo) (lastmodifiedTimestamp time.Time) {
 	for _, object := range objects {
 		if object.LastModified.After(lastmodifiedTimestamp) {
 			lastmodifiedTimestamp = object.LastModified
@@ -155,7 +203,7 @@ func getLastModifiedTimestamp(objects []minio.ObjectInfo) (lastmodifiedTimestamp
 	return lastmodifiedTimestamp
 }
 
-func getEmptyObjectCount(objects []minio.ObjectInfo) (emptyObjects int) {
+func getEmptyObjectCount(objects []backupstore.ObjectInfo) (emptyObjects int) {
 	for _, object := range objects {
 		if object.Size == 0 {
 			emptyObjects++
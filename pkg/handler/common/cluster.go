@@ -95,14 +95,14 @@ func CreateEndpoint(
 		return nil, common.KubernetesErrorToHTTPError(err)
 	}
 
-	partialCluster, err := GenerateCluster(ctx, projectID, body, seedsGetter, credentialManager, exposeStrategy, userInfoGetter, caBundle, configGetter)
+	project, err := common.GetProject(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, projectID, &provider.ProjectGetOptions{IncludeUninitialized: false})
 	if err != nil {
-		return nil, err
+		return nil, common.KubernetesErrorToHTTPError(err)
 	}
 
-	project, err := common.GetProject(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, projectID, &provider.ProjectGetOptions{IncludeUninitialized: false})
+	partialCluster, err := GenerateCluster(ctx, project, body, seedsGetter, credentialManager, exposeStrategy, userInfoGetter, caBundle, configGetter)
 	if err != nil {
-		return nil, common.KubernetesErrorToHTTPError(err)
+		return nil, err
 	}
 	existingClusters, err := clusterProvider.List(project, &provider.ClusterListOptions{ClusterSpecName: partialCluster.Spec.HumanReadableName})
 	if err != nil {
@@ -151,7 +151,7 @@ func CreateEndpoint(
 
 func GenerateCluster(
 	ctx context.Context,
-	projectID string,
+	project *kubermaticv1.Project,
 	body apiv1.CreateClusterSpec,
 	seedsGetter provider.SeedsGetter,
 	credentialManager provider.PresetProvider,
@@ -172,7 +172,11 @@ func GenerateCluster(
 
 	credentialName := body.Cluster.Credential
 	if len(credentialName) > 0 {
-		cloudSpec, err := credentialManager.SetCloudCredentials(adminUserInfo, credentialName, body.Cluster.Spec.Cloud, dc)
+		if err := checkPresetAvailability(adminUserInfo, credentialManager, credentialName, project); err != nil {
+			return nil, err
+		}
+
+		cloudSpec, err := credentialManager.SetCloudCredentials(ctx, adminUserInfo, credentialName, body.Cluster.Spec.Cloud, dc)
 		if err != nil {
 			return nil, errors.NewBadRequest("invalid credentials: %v", err)
 		}
@@ -235,16 +239,23 @@ func GenerateCluster(
 		}
 	}
 
+	// Remember the preset, so that its credentials can be rotated for all clusters using it.
+	if len(credentialName) > 0 {
+		partialCluster.Annotations[kubermaticv1.PresetNameAnnotation] = credentialName
+	}
+
 	// Owning project ID must be set early, because it will be inherited by some child objects,
 	// for example the credentials secret.
-	partialCluster.Labels[kubermaticv1.ProjectIDLabelKey] = projectID
+	partialCluster.Labels[kubermaticv1.ProjectIDLabelKey] = project.Name
 	partialCluster.Spec = *spec
+	resetAuditSinkCredentialsReferences(partialCluster.Spec.AuditLogging, nil)
 
-	// Enforce audit logging
+	// Enforce audit logging, keeping the audit policy and sinks of the cluster
 	if dc.Spec.EnforceAuditLogging {
-		partialCluster.Spec.AuditLogging = &kubermaticv1.AuditLoggingSettings{
-			Enabled: true,
+		if partialCluster.Spec.AuditLogging == nil {
+			partialCluster.Spec.AuditLogging = &kubermaticv1.AuditLoggingSettings{}
 		}
+		partialCluster.Spec.AuditLogging.Enabled = true
 	}
 
 	// Enforce PodSecurityPolicy
@@ -276,6 +287,71 @@ func GenerateCluster(
 	return partialCluster, nil
 }
 
+// checkPresetAvailability returns an error if the preset cannot be used in the project.
+func checkPresetAvailability(userInfo *provider.UserInfo, presetProvider provider.PresetProvider, presetName string, project *kubermaticv1.Project) error {
+	preset, err := presetProvider.GetStoredPreset(userInfo, presetName)
+	if err != nil {
+		return errors.NewBadRequest("invalid credentials: %v", err)
+	}
+
+	available, err := helper.IsAvailableForProject(preset, project)
+	if err != nil {
+		return err
+	}
+	if !available {
+		return errors.New(http.StatusForbidden, fmt.Sprintf("preset %s is not available in project %s", presetName, project.Name))
+	}
+
+	return nil
+}
+
+// resetAuditSinkCredentialsReferences drops the credentials references of audit sinks that are set
+// by users and keeps the ones of the same-named sinks of the existing settings instead. Users can
+// only give credentials inline, because the references are resolved with the privileges of the
+// seed-controller-manager.
+func resetAuditSinkCredentialsReferences(settings, existing *kubermaticv1.AuditLoggingSettings) {
+	if settings == nil {
+		return
+	}
+
+	existingSinks := map[string]kubermaticv1.AuditSink{}
+	if existing != nil {
+		for _, sink := range existing.Sinks {
+			existingSinks[sink.Name] = sink
+		}
+	}
+
+	for i := range settings.Sinks {
+		sink := &settings.Sinks[i]
+		existingSink := existingSinks[sink.Name]
+
+		if sink.Webhook != nil {
+			sink.Webhook.CredentialsReference = nil
+			if existingSink.Webhook != nil {
+				sink.Webhook.CredentialsReference = existingSink.Webhook.CredentialsReference
+			}
+		}
+		if sink.HTTP != nil {
+			sink.HTTP.CredentialsReference = nil
+			if existingSink.HTTP != nil {
+				sink.HTTP.CredentialsReference = existingSink.HTTP.CredentialsReference
+			}
+		}
+		if sink.Loki != nil {
+			sink.Loki.CredentialsReference = nil
+			if existingSink.Loki != nil {
+				sink.Loki.CredentialsReference = existingSink.Loki.CredentialsReference
+			}
+		}
+		if sink.S3 != nil {
+			sink.S3.CredentialsReference = nil
+			if existingSink.S3 != nil {
+				sink.S3.CredentialsReference = existingSink.S3.CredentialsReference
+			}
+		}
+	}
+}
+
 func GetClusters(ctx context.Context, userInfoGetter provider.UserInfoGetter, clusterProvider provider.ClusterProvider, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, seedsGetter provider.SeedsGetter, projectID string, configGetter provider.KubermaticConfigurationGetter) ([]*apiv1.Cluster, error) {
 	project, err := common.GetProject(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, projectID, nil)
 	if err != nil {
@@ -469,12 +545,14 @@ func PatchEndpoint(
 	newInternalCluster.Spec.UsePodNodeSelectorAdmissionPlugin = patchedCluster.Spec.UsePodNodeSelectorAdmissionPlugin
 	newInternalCluster.Spec.AdmissionPlugins = patchedCluster.Spec.AdmissionPlugins
 	newInternalCluster.Spec.AuditLogging = patchedCluster.Spec.AuditLogging
+	resetAuditSinkCredentialsReferences(newInternalCluster.Spec.AuditLogging, oldInternalCluster.Spec.AuditLogging)
 	newInternalCluster.Spec.UpdateWindow = patchedCluster.Spec.UpdateWindow
 	newInternalCluster.Spec.OPAIntegration = patchedCluster.Spec.OPAIntegration
 	newInternalCluster.Spec.PodNodeSelectorAdmissionPluginConfig = patchedCluster.Spec.PodNodeSelectorAdmissionPluginConfig
 	newInternalCluster.Spec.ServiceAccount = patchedCluster.Spec.ServiceAccount
 	newInternalCluster.Spec.MLA = patchedCluster.Spec.MLA
 	newInternalCluster.Spec.ContainerRuntime = patchedCluster.Spec.ContainerRuntime
+	newInternalCluster.Spec.APIServerAllowedIPRanges = patchedCluster.Spec.APIServerAllowedIPRanges
 	newInternalCluster.Spec.ClusterNetwork.KonnectivityEnabled = patchedCluster.Spec.ClusterNetwork.KonnectivityEnabled
 
 	incompatibleKubelets, err := common.CheckClusterVersionSkew(ctx, userInfoGetter, clusterProvider, newInternalCluster, projectID)
@@ -489,11 +567,12 @@ func PatchEndpoint(
 		return nil, err
 	}
 
-	// Enforce audit logging
+	// Enforce audit logging, keeping the audit policy and sinks of the cluster
 	if dc.Spec.EnforceAuditLogging {
-		newInternalCluster.Spec.AuditLogging = &kubermaticv1.AuditLoggingSettings{
-			Enabled: true,
+		if newInternalCluster.Spec.AuditLogging == nil {
+			newInternalCluster.Spec.AuditLogging = &kubermaticv1.AuditLoggingSettings{}
 		}
+		newInternalCluster.Spec.AuditLogging.Enabled = true
 	}
 
 	// Enforce PodSecurityPolicy
@@ -1002,6 +1081,7 @@ func ConvertInternalClusterToExternal(internalCluster *kubermaticv1.Cluster, dat
 			ContainerRuntime:                     internalCluster.Spec.ContainerRuntime,
 			ClusterNetwork:                       &internalCluster.Spec.ClusterNetwork,
 			CNIPlugin:                            internalCluster.Spec.CNIPlugin,
+			APIServerAllowedIPRanges:             internalCluster.Spec.APIServerAllowedIPRanges,
 		},
 		Status: apiv1.ClusterStatus{
 			Version:              internalCluster.Spec.Version,
@@ -0,0 +1,178 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+
+	providerconfig "github.com/kubermatic/machine-controller/pkg/providerconfig/types"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/handler/middleware"
+	"k8c.io/kubermatic/v2/pkg/handler/v1/common"
+	"k8c.io/kubermatic/v2/pkg/provider"
+	"k8c.io/kubermatic/v2/pkg/provider/cloud"
+	kubernetesprovider "k8c.io/kubermatic/v2/pkg/provider/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/util/errors"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RotateCredentialsEndpoint replaces the cloud credentials of the cluster, either with the credentials
+// given in cloudSpec or with the ones of the preset presetName. The credentials rotation controller
+// restarts all components that use them afterwards.
+func RotateCredentialsEndpoint(
+	ctx context.Context,
+	userInfoGetter provider.UserInfoGetter,
+	projectID string,
+	clusterID string,
+	cloudSpec kubermaticv1.CloudSpec,
+	presetName string,
+	seedsGetter provider.SeedsGetter,
+	projectProvider provider.ProjectProvider,
+	privilegedProjectProvider provider.PrivilegedProjectProvider,
+	presetProvider provider.PresetProvider,
+	caBundle *x509.CertPool,
+) (interface{}, error) {
+	clusterProvider := ctx.Value(middleware.ClusterProviderContextKey).(provider.ClusterProvider)
+	privilegedClusterProvider := ctx.Value(middleware.PrivilegedClusterProviderContextKey).(provider.PrivilegedClusterProvider)
+
+	project, err := common.GetProject(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, projectID, nil)
+	if err != nil {
+		return nil, common.KubernetesErrorToHTTPError(err)
+	}
+
+	cluster, err := GetInternalCluster(ctx, userInfoGetter, clusterProvider, privilegedClusterProvider, project, projectID, clusterID, &provider.ClusterGetOptions{})
+	if err != nil {
+		return nil, common.KubernetesErrorToHTTPError(err)
+	}
+
+	userInfo, err := userInfoGetter(ctx, "")
+	if err != nil {
+		return nil, errors.New(http.StatusInternalServerError, err.Error())
+	}
+	_, dc, err := provider.DatacenterFromSeedMap(userInfo, seedsGetter, cluster.Spec.Cloud.DatacenterName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting dc: %v", err)
+	}
+
+	if presetName != "" {
+		if err := checkPresetAvailability(userInfo, presetProvider, presetName, project); err != nil {
+			return nil, err
+		}
+
+		presetCloudSpec, err := presetProvider.SetCloudCredentials(ctx, userInfo, presetName, *cluster.Spec.Cloud.DeepCopy(), dc)
+		if err != nil {
+			return nil, errors.NewBadRequest("invalid credentials: %v", err)
+		}
+		cloudSpec = *presetCloudSpec
+	}
+
+	rotatedCluster, err := RotateCredentials(ctx, privilegedClusterProvider.GetSeedClusterAdminRuntimeClient(), cluster, cloudSpec, dc, caBundle)
+	if err != nil {
+		return nil, err
+	}
+
+	// clusters only follow a preset as long as they use its credentials
+	if presetName != "" {
+		if rotatedCluster.Annotations == nil {
+			rotatedCluster.Annotations = map[string]string{}
+		}
+		rotatedCluster.Annotations[kubermaticv1.PresetNameAnnotation] = presetName
+	} else {
+		delete(rotatedCluster.Annotations, kubermaticv1.PresetNameAnnotation)
+	}
+
+	if equality.Semantic.DeepEqual(cluster, rotatedCluster) {
+		return nil, nil
+	}
+
+	_, err = updateCluster(ctx, userInfoGetter, clusterProvider, privilegedClusterProvider, project, rotatedCluster)
+	return nil, common.KubernetesErrorToHTTPError(err)
+}
+
+// RotateCredentials validates the cloud credentials contained in cloudSpec against the cloud provider and
+// stores them in the credentials Secret of the cluster. Only the credentials are taken from cloudSpec, all
+// other cloud settings of the cluster are kept. The returned cluster has to be persisted by the caller.
+func RotateCredentials(ctx context.Context, seedClient ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster, cloudSpec kubermaticv1.CloudSpec, dc *kubermaticv1.Datacenter, caBundle *x509.CertPool) (*kubermaticv1.Cluster, error) {
+	providerName, err := provider.ClusterCloudProviderName(cluster.Spec.Cloud)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cloud spec of cluster: %v", err)
+	}
+	if providerName == "" || providerName == string(kubermaticv1.BringYourOwnCloudProvider) {
+		return nil, errors.NewBadRequest("cluster has no cloud credentials")
+	}
+
+	requestedProviderName, err := provider.ClusterCloudProviderName(cloudSpec)
+	if err != nil {
+		return nil, errors.NewBadRequest("invalid cloud spec: %v", err)
+	}
+	if requestedProviderName != providerName {
+		return nil, errors.NewBadRequest("expected credentials for provider %q, got %q", providerName, requestedProviderName)
+	}
+
+	dcProviderName, err := provider.DatacenterCloudProviderName(&dc.Spec)
+	if err != nil {
+		return nil, err
+	}
+	if dcProviderName != providerName {
+		return nil, fmt.Errorf("datacenter %q is not a %s datacenter", cluster.Spec.Cloud.DatacenterName, providerName)
+	}
+
+	requestedCluster := cluster.DeepCopy()
+	requestedCluster.Spec.Cloud = cloudSpec
+
+	rotatedCluster := cluster.DeepCopy()
+	if err := resources.CopyCredentials(inlineCredentialsData{cluster: requestedCluster}, rotatedCluster); err != nil {
+		return nil, errors.NewBadRequest("invalid credentials: %v", err)
+	}
+
+	secretKeySelector := provider.SecretKeySelectorValueFuncFactory(ctx, seedClient)
+	cloudProvider, err := cloud.Provider(dc, secretKeySelector, caBundle)
+	if err != nil {
+		return nil, err
+	}
+	if err := cloudProvider.ValidateCloudSpec(rotatedCluster.Spec.Cloud); err != nil {
+		return nil, errors.NewBadRequest("invalid credentials: %v", err)
+	}
+
+	// this moves the credentials out of the cluster into its credentials Secret
+	if err := kubernetesprovider.CreateOrUpdateCredentialSecretForCluster(ctx, seedClient, rotatedCluster); err != nil {
+		return nil, err
+	}
+
+	return rotatedCluster, nil
+}
+
+// inlineCredentialsData provides only the credentials that are set inline in the cloud spec
+// of the cluster, so that requests cannot read the credentials of other clusters by referencing
+// their Secrets.
+type inlineCredentialsData struct {
+	cluster *kubermaticv1.Cluster
+}
+
+func (d inlineCredentialsData) Cluster() *kubermaticv1.Cluster {
+	return d.cluster
+}
+
+func (d inlineCredentialsData) GetGlobalSecretKeySelectorValue(_ *providerconfig.GlobalSecretKeySelector, key string) (string, error) {
+	return "", fmt.Errorf("no value given for %q", key)
+}
@@ -91,6 +91,7 @@ func NewTestRouting(
 	etcdRestoreProjectProviderGetter provider.EtcdRestoreProjectProviderGetter,
 	backupCredentialsProviderGetter provider.BackupCredentialsProviderGetter,
 	privilegedMLAAdminSettingProviderGetter provider.PrivilegedMLAAdminSettingProviderGetter,
+	privilegedClusterMigrationProvider provider.PrivilegedClusterMigrationProvider,
 	masterClient client.Client,
 	featureGatesProvider provider.FeatureGatesProvider,
 	seedProvider provider.SeedProvider) http.Handler {
@@ -146,6 +147,7 @@ func NewTestRouting(
 		EtcdRestoreProjectProviderGetter:        etcdRestoreProjectProviderGetter,
 		BackupCredentialsProviderGetter:         backupCredentialsProviderGetter,
 		PrivilegedMLAAdminSettingProviderGetter: privilegedMLAAdminSettingProviderGetter,
+		PrivilegedClusterMigrationProvider:      privilegedClusterMigrationProvider,
 		SeedProvider:                            seedProvider,
 		Versions:                                kubermaticVersions,
 		CABundle:                                certificates.NewFakeCABundle().CertPool(),
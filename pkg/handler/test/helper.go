@@ -217,6 +217,7 @@ type newRoutingFunc func(
 	etcdRestoreProjectProviderGetter provider.EtcdRestoreProjectProviderGetter,
 	backupCredentialsProviderGetter provider.BackupCredentialsProviderGetter,
 	privilegedMLAAdminSettingProviderGetter provider.PrivilegedMLAAdminSettingProviderGetter,
+	privilegedClusterMigrationProvider provider.PrivilegedClusterMigrationProvider,
 	masterClient client.Client,
 	featureGatesProvider provider.FeatureGatesProvider,
 	seedProvider provider.SeedProvider,
@@ -349,7 +350,7 @@ func initTestEndpoint(user apiv1.User, seedsGetter provider.SeedsGetter, kubeObj
 		return nil, fmt.Errorf("can not find clusterprovider for cluster %q", seed.Name)
 	}
 
-	credentialsManager, err := kubernetes.NewPresetProvider(ctx, fakeClient, "", true)
+	credentialsManager, err := kubernetes.NewPresetProvider(ctx, fakeClient, "", true, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -513,6 +514,11 @@ func initTestEndpoint(user apiv1.User, seedsGetter provider.SeedsGetter, kubeObj
 		return nil, fmt.Errorf("can not find privilegedMLAAdminSettingProvider for cluster %q", seed.Name)
 	}
 
+	privilegedClusterMigrationProvider, err := kubernetes.NewClusterMigrationPrivilegedProvider(fakeClient)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	seedProvider := kubernetes.NewSeedProvider(fakeClient)
 	if err != nil {
 		return nil, nil, err
@@ -589,6 +595,7 @@ func initTestEndpoint(user apiv1.User, seedsGetter provider.SeedsGetter, kubeObj
 		etcdRestoreProjectProviderGetter,
 		backupCredentialsProviderGetter,
 		privilegedMLAAdminSettingProviderGetter,
+		privilegedClusterMigrationProvider,
 		fakeClient,
 		featureGatesProvider,
 		seedProvider,
@@ -27,6 +27,7 @@ import (
 
 	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
 	apiv2 "k8c.io/kubermatic/v2/pkg/api/v2"
+	"k8c.io/kubermatic/v2/pkg/backupstore"
 	"k8c.io/kubermatic/v2/pkg/handler/middleware"
 	"k8c.io/kubermatic/v2/pkg/handler/v1/common"
 	"k8c.io/kubermatic/v2/pkg/provider"
@@ -137,7 +138,7 @@ func DecodeBackupCredentialsReq(c context.Context, r *http.Request) (interface{}
 }
 
 func convertAPIToInternalBackupCredentials(bc *apiv2.BackupCredentials) *v1.Secret {
-	return &v1.Secret{
+	secret := &v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      GenBackupCredentialsSecretName(bc.Destination),
 			Namespace: metav1.NamespaceSystem,
@@ -147,6 +148,16 @@ func convertAPIToInternalBackupCredentials(bc *apiv2.BackupCredentials) *v1.Secr
 			resources.EtcdBackupAndRestoreS3SecretKeyAccessKeyKey: bc.S3BackupCredentials.SecretAccessKey,
 		},
 	}
+
+	if bc.AzureBlobBackupCredentials != nil {
+		secret.StringData[backupstore.AzureAccountNameKey] = bc.AzureBlobBackupCredentials.AccountName
+		secret.StringData[backupstore.AzureAccountKeyKey] = bc.AzureBlobBackupCredentials.AccountKey
+	}
+	if bc.GCSBackupCredentials != nil {
+		secret.StringData[backupstore.GCSServiceAccountKey] = bc.GCSBackupCredentials.ServiceAccount
+	}
+
+	return secret
 }
 
 // GenBackupCredentialsSecretName generates etcd backup credentials secret name. If backup destination is not set, then use the legacy credentials secret
@@ -154,6 +154,33 @@ func TestCreateOrUpdateEndpoint(t *testing.T) {
 			}(),
 			ExpectedHTTPStatusCode: http.StatusOK,
 		},
+		{
+			Name:     "create backup credentials for given seed with azureblob destination",
+			SeedName: test.GenTestSeed().Name,
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				test.GenTestSeed(func(seed *kubermaticv1.Seed) {
+					seed.Spec.EtcdBackupRestore = &kubermaticv1.EtcdBackupRestore{
+						Destinations: map[string]*kubermaticv1.BackupDestination{
+							"azure": {
+								Type:       kubermaticv1.BackupDestinationTypeAzureBlob,
+								BucketName: "testcontainer",
+							},
+						},
+					}
+				}),
+				test.GenDefaultCluster(),
+				test.GenAdminUser("John", "john@acme.com", true),
+			),
+			ExistingAPIUser: test.GenAPIUser("John", "john@acme.com"),
+			BackupCredentials: &apiv2.BackupCredentials{
+				Destination: "azure",
+				AzureBlobBackupCredentials: &apiv2.AzureBlobBackupCredentials{
+					AccountName: "account",
+					AccountKey:  "key",
+				},
+			},
+			ExpectedHTTPStatusCode: http.StatusOK,
+		},
 		{
 			Name:     "can't manage backup credentials for non-existing seed destination",
 			SeedName: "nothere",
@@ -1007,6 +1007,24 @@ func TestPatchCluster(t *testing.T) {
 				}(),
 			),
 		},
+		// scenario 9
+		{
+			Name:             "scenario 9: restrict the apiserver to allowed IP ranges",
+			Body:             `{"spec":{"apiServerAllowedIPRanges":{"cidrBlocks":["10.0.0.0/8","192.168.1.1/32"]}}}`,
+			ExpectedResponse: `{"id":"keen-snyder","name":"clusterAbc","creationTimestamp":"2013-02-03T19:54:00Z","type":"kubernetes","spec":{"cloud":{"dc":"fake-dc","fake":{}},"version":"9.9.9","oidc":{},"enableUserSSHKeyAgent":false,"clusterNetwork":{"services":{"cidrBlocks":null},"pods":{"cidrBlocks":null},"dnsDomain":"","proxyMode":""},"apiServerAllowedIPRanges":{"cidrBlocks":["10.0.0.0/8","192.168.1.1/32"]}},"status":{"version":"9.9.9","url":"https://w225mx4z66.asia-east1-a-1.cloud.kubermatic.io:31885","externalCCMMigration":"Unsupported"}}`,
+			cluster:          "keen-snyder",
+			HTTPStatus:       http.StatusOK,
+			project:          test.GenDefaultProject().Name,
+			ExistingAPIUser:  test.GenDefaultAPIUser(),
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				test.GenTestSeed(),
+				func() *kubermaticv1.Cluster {
+					cluster := test.GenCluster("keen-snyder", "clusterAbc", test.GenDefaultProject().Name, time.Date(2013, 02, 03, 19, 54, 0, 0, time.UTC))
+					cluster.Spec.Cloud.DatacenterName = fakeDC
+					return cluster
+				}(),
+			),
+		},
 	}
 
 	for _, tc := range testcases {
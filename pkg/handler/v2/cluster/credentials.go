@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-kit/kit/endpoint"
+
+	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	handlercommon "k8c.io/kubermatic/v2/pkg/handler/common"
+	"k8c.io/kubermatic/v2/pkg/handler/v1/common"
+	"k8c.io/kubermatic/v2/pkg/provider"
+	"k8c.io/kubermatic/v2/pkg/util/errors"
+)
+
+func RotateCredentialsEndpoint(projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, seedsGetter provider.SeedsGetter, presetProvider provider.PresetProvider, userInfoGetter provider.UserInfoGetter, caBundle *x509.CertPool) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(RotateCredentialsReq)
+		if !ok {
+			return nil, errors.NewWrongMethod(request, RotateCredentialsReq{})
+		}
+		if err := req.Validate(); err != nil {
+			return nil, errors.NewBadRequest("invalid request: %v", err)
+		}
+		return handlercommon.RotateCredentialsEndpoint(ctx, userInfoGetter, req.ProjectID, req.ClusterID, req.Body.Cloud, req.Body.Credential, seedsGetter, projectProvider, privilegedProjectProvider, presetProvider, caBundle)
+	}
+}
+
+// RotateCredentialsReq defines HTTP request for rotateClusterCredentialsV2 endpoint
+// swagger:parameters rotateClusterCredentialsV2
+type RotateCredentialsReq struct {
+	common.ProjectReq
+	// in: path
+	// required: true
+	ClusterID string `json:"cluster_id"`
+
+	// in: body
+	// required: true
+	Body RotateCredentialsBody
+}
+
+// RotateCredentialsBody contains the new credentials of the cluster. Either Cloud or Credential
+// must be set.
+type RotateCredentialsBody struct {
+	// Cloud contains the new credentials in the same format as the cloud spec of the cluster
+	Cloud kubermaticv1.CloudSpec `json:"cloud,omitempty"`
+	// Credential is the name of the preset to take the new credentials from
+	Credential string `json:"credential,omitempty"`
+}
+
+// GetSeedCluster returns the SeedCluster object
+func (req RotateCredentialsReq) GetSeedCluster() apiv1.SeedCluster {
+	return apiv1.SeedCluster{
+		ClusterID: req.ClusterID,
+	}
+}
+
+// Validate validates RotateCredentialsEndpoint request
+func (req RotateCredentialsReq) Validate() error {
+	providerName, err := provider.ClusterCloudProviderName(req.Body.Cloud)
+	if err != nil {
+		return err
+	}
+	if req.Body.Credential == "" && providerName == "" {
+		return fmt.Errorf("either cloud credentials or a preset must be given")
+	}
+	if req.Body.Credential != "" && providerName != "" {
+		return fmt.Errorf("cloud credentials and a preset must not be given at the same time")
+	}
+	return nil
+}
+
+func DecodeRotateCredentialsReq(c context.Context, r *http.Request) (interface{}, error) {
+	var req RotateCredentialsReq
+	projectReq, err := common.DecodeProjectRequest(c, r)
+	if err != nil {
+		return nil, err
+	}
+	req.ProjectReq = projectReq.(common.ProjectReq)
+	clusterID, err := common.DecodeClusterID(c, r)
+	if err != nil {
+		return nil, err
+	}
+	req.ClusterID = clusterID
+
+	if err := json.NewDecoder(r.Body).Decode(&req.Body); err != nil {
+		return nil, errors.NewBadRequest("unable to parse request body: %v", err)
+	}
+
+	return req, nil
+}
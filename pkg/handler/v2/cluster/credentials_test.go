@@ -0,0 +1,149 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/handler/test"
+	"k8c.io/kubermatic/v2/pkg/handler/test/hack"
+
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func genFakeDCCluster(presetName string) *kubermaticv1.Cluster {
+	cluster := test.GenDefaultCluster()
+	cluster.Spec.Cloud.DatacenterName = "fake-dc"
+	if presetName != "" {
+		cluster.Annotations = map[string]string{kubermaticv1.PresetNameAnnotation: presetName}
+	}
+	return cluster
+}
+
+func TestRotateClusterCredentials(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		Name                   string
+		Body                   string
+		HTTPStatus             int
+		ExistingCluster        *kubermaticv1.Cluster
+		ExistingAPIUser        *apiv1.User
+		ExistingKubermaticObjs []ctrlruntimeclient.Object
+		ExpectedPresetName     string
+	}{
+		{
+			Name:                   "scenario 1: rotate the credentials using a preset",
+			Body:                   fmt.Sprintf(`{"credential":"%s"}`, test.TestFakeCredential),
+			HTTPStatus:             http.StatusOK,
+			ExistingCluster:        genFakeDCCluster(""),
+			ExistingAPIUser:        test.GenDefaultAPIUser(),
+			ExistingKubermaticObjs: []ctrlruntimeclient.Object{test.GenTestSeed()},
+			ExpectedPresetName:     test.TestFakeCredential,
+		},
+		{
+			Name:                   "scenario 2: rotating to inline credentials stops following the preset",
+			Body:                   `{"cloud":{"fake":{"token":"new-token"}}}`,
+			HTTPStatus:             http.StatusOK,
+			ExistingCluster:        genFakeDCCluster(test.TestFakeCredential),
+			ExistingAPIUser:        test.GenDefaultAPIUser(),
+			ExistingKubermaticObjs: []ctrlruntimeclient.Object{test.GenTestSeed()},
+		},
+		{
+			Name:                   "scenario 3: the admin John can rotate Bob's cluster credentials",
+			Body:                   fmt.Sprintf(`{"credential":"%s"}`, test.TestFakeCredential),
+			HTTPStatus:             http.StatusOK,
+			ExistingCluster:        genFakeDCCluster(""),
+			ExistingAPIUser:        test.GenAPIUser("John", "john@acme.com"),
+			ExistingKubermaticObjs: []ctrlruntimeclient.Object{test.GenTestSeed(), genUser("John", "john@acme.com", true)},
+			ExpectedPresetName:     test.TestFakeCredential,
+		},
+		{
+			Name:                   "scenario 4: credentials for a different provider are rejected",
+			Body:                   `{"cloud":{"hetzner":{"token":"new-token"}}}`,
+			HTTPStatus:             http.StatusBadRequest,
+			ExistingCluster:        genFakeDCCluster(test.TestFakeCredential),
+			ExistingAPIUser:        test.GenDefaultAPIUser(),
+			ExistingKubermaticObjs: []ctrlruntimeclient.Object{test.GenTestSeed()},
+			ExpectedPresetName:     test.TestFakeCredential,
+		},
+		{
+			Name:                   "scenario 5: either credentials or a preset must be given",
+			Body:                   `{}`,
+			HTTPStatus:             http.StatusBadRequest,
+			ExistingCluster:        genFakeDCCluster(""),
+			ExistingAPIUser:        test.GenDefaultAPIUser(),
+			ExistingKubermaticObjs: []ctrlruntimeclient.Object{test.GenTestSeed()},
+		},
+		{
+			Name:                   "scenario 6: the user John cannot rotate Bob's cluster credentials",
+			Body:                   fmt.Sprintf(`{"credential":"%s"}`, test.TestFakeCredential),
+			HTTPStatus:             http.StatusForbidden,
+			ExistingCluster:        genFakeDCCluster(""),
+			ExistingAPIUser:        test.GenAPIUser("John", "john@acme.com"),
+			ExistingKubermaticObjs: []ctrlruntimeclient.Object{test.GenTestSeed(), genUser("John", "john@acme.com", false)},
+		},
+		{
+			Name:            "scenario 7: presets restricted to other projects cannot be used",
+			Body:            `{"credential":"restricted"}`,
+			HTTPStatus:      http.StatusForbidden,
+			ExistingCluster: genFakeDCCluster(""),
+			ExistingAPIUser: test.GenDefaultAPIUser(),
+			ExistingKubermaticObjs: []ctrlruntimeclient.Object{test.GenTestSeed(), func() *kubermaticv1.Preset {
+				preset := test.GenDefaultPreset()
+				preset.Name = "restricted"
+				preset.Spec.Projects = []string{"other-project"}
+				return preset
+			}()},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v2/projects/%s/clusters/%s/credentials/rotate",
+				test.GenDefaultProject().Name, tc.ExistingCluster.Name), strings.NewReader(tc.Body))
+			res := httptest.NewRecorder()
+
+			kubermaticObj := test.GenDefaultKubermaticObjects(append(tc.ExistingKubermaticObjs, tc.ExistingCluster)...)
+			ep, cs, err := test.CreateTestEndpointAndGetClients(*tc.ExistingAPIUser, nil, nil, nil, kubermaticObj, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+
+			ep.ServeHTTP(res, req)
+
+			if res.Code != tc.HTTPStatus {
+				t.Fatalf("Expected HTTP status code %d, got %d: %s", tc.HTTPStatus, res.Code, res.Body.String())
+			}
+
+			cluster := &kubermaticv1.Cluster{}
+			if err := cs.FakeClient.Get(context.TODO(), ctrlruntimeclient.ObjectKeyFromObject(tc.ExistingCluster), cluster); err != nil {
+				t.Fatalf("failed to get cluster: %v", err)
+			}
+			if presetName := cluster.Annotations[kubermaticv1.PresetNameAnnotation]; presetName != tc.ExpectedPresetName {
+				t.Fatalf("expected cluster to use preset %q, got %q", tc.ExpectedPresetName, presetName)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustermigration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/go-kit/kit/endpoint"
+
+	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
+	apiv2 "k8c.io/kubermatic/v2/pkg/api/v2"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	handlercommon "k8c.io/kubermatic/v2/pkg/handler/common"
+	"k8c.io/kubermatic/v2/pkg/handler/middleware"
+	"k8c.io/kubermatic/v2/pkg/handler/v1/common"
+	"k8c.io/kubermatic/v2/pkg/handler/v2/cluster"
+	"k8c.io/kubermatic/v2/pkg/provider"
+	"k8c.io/kubermatic/v2/pkg/util/errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+func CreateEndpoint(userInfoGetter provider.UserInfoGetter, projectProvider provider.ProjectProvider,
+	privilegedProjectProvider provider.PrivilegedProjectProvider, seedsGetter provider.SeedsGetter,
+	clusterMigrationProvider provider.PrivilegedClusterMigrationProvider) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createClusterMigrationReq)
+
+		adminUserInfo, err := userInfoGetter(ctx, "")
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		if !adminUserInfo.IsAdmin {
+			return nil, errors.New(http.StatusForbidden,
+				fmt.Sprintf("forbidden: \"%s\" doesn't have admin rights", adminUserInfo.Email))
+		}
+
+		c, err := handlercommon.GetCluster(ctx, projectProvider, privilegedProjectProvider, userInfoGetter, req.ProjectID, req.ClusterID, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		clusterProvider, ok := ctx.Value(middleware.ClusterProviderContextKey).(provider.ClusterProvider)
+		if !ok {
+			return nil, errors.New(http.StatusInternalServerError, "no cluster in request")
+		}
+
+		seeds, err := seedsGetter()
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		sourceSeed, ok := seeds[clusterProvider.GetSeedName()]
+		if !ok {
+			return nil, errors.New(http.StatusInternalServerError, fmt.Sprintf("Seed %q not found", clusterProvider.GetSeedName()))
+		}
+
+		if err := req.validate(sourceSeed, seeds); err != nil {
+			return nil, err
+		}
+
+		migrations, err := clusterMigrationProvider.ListUnsecured(c.Name)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		for _, migration := range migrations.Items {
+			if !migration.IsFinished() {
+				return nil, errors.NewAlreadyExists("cluster migration", migration.Name)
+			}
+		}
+
+		migration := &kubermaticv1.ClusterMigration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: fmt.Sprintf("%s-%s", c.Name, rand.String(5)),
+				Labels: map[string]string{
+					kubermaticv1.ProjectIDLabelKey: req.ProjectID,
+				},
+			},
+			Spec: kubermaticv1.ClusterMigrationSpec{
+				ClusterName:      c.Name,
+				SourceSeed:       sourceSeed.Name,
+				TargetSeed:       req.Body.TargetSeed,
+				TargetDatacenter: req.Body.TargetDatacenter,
+				Destination:      req.Body.Destination,
+			},
+		}
+
+		migration, err = clusterMigrationProvider.CreateUnsecured(migration)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		return convertInternalToAPIClusterMigration(migration), nil
+	}
+}
+
+// createClusterMigrationReq represents a request for migrating a cluster to another seed
+// swagger:parameters createClusterMigration
+type createClusterMigrationReq struct {
+	cluster.GetClusterReq
+	// in: body
+	Body cmBody
+}
+
+type cmBody struct {
+	// TargetSeed is the name of the seed the control plane will be moved to
+	TargetSeed string `json:"targetSeed"`
+	// TargetDatacenter optionally overrides the datacenter of the cluster. It must belong to the target seed.
+	TargetDatacenter string `json:"targetDatacenter,omitempty"`
+	// Destination is the name of the backup destination used to transfer the etcd data. It must be
+	// configured in both the source and the target seed.
+	Destination string `json:"destination"`
+}
+
+func (r *createClusterMigrationReq) validate(sourceSeed *kubermaticv1.Seed, seeds map[string]*kubermaticv1.Seed) error {
+	if r.Body.TargetSeed == "" {
+		return errors.NewBadRequest("target seed cannot be empty")
+	}
+	if r.Body.TargetSeed == sourceSeed.Name {
+		return errors.NewBadRequest("cluster is already running on seed %q", sourceSeed.Name)
+	}
+	targetSeed, ok := seeds[r.Body.TargetSeed]
+	if !ok {
+		return errors.NewBadRequest("seed %q does not exist", r.Body.TargetSeed)
+	}
+	if r.Body.TargetDatacenter != "" {
+		if _, ok := targetSeed.Spec.Datacenters[r.Body.TargetDatacenter]; !ok {
+			return errors.NewBadRequest("datacenter %q does not exist in seed %q", r.Body.TargetDatacenter, targetSeed.Name)
+		}
+	}
+	if r.Body.Destination == "" {
+		return errors.NewBadRequest("destination cannot be empty")
+	}
+	for _, seed := range []*kubermaticv1.Seed{sourceSeed, targetSeed} {
+		if seed.Spec.EtcdBackupRestore == nil || seed.Spec.EtcdBackupRestore.Destinations[r.Body.Destination] == nil {
+			return errors.NewBadRequest("backup destination %q is not configured in seed %q", r.Body.Destination, seed.Name)
+		}
+	}
+	return nil
+}
+
+func DecodeCreateClusterMigrationReq(c context.Context, r *http.Request) (interface{}, error) {
+	var req createClusterMigrationReq
+	cr, err := cluster.DecodeGetClusterReq(c, r)
+	if err != nil {
+		return nil, err
+	}
+	req.GetClusterReq = cr.(cluster.GetClusterReq)
+
+	if err = json.NewDecoder(r.Body).Decode(&req.Body); err != nil {
+		return nil, errors.NewBadRequest("unable to parse body: %v", err)
+	}
+	return req, nil
+}
+
+func GetEndpoint(userInfoGetter provider.UserInfoGetter, projectProvider provider.ProjectProvider,
+	privilegedProjectProvider provider.PrivilegedProjectProvider, clusterMigrationProvider provider.PrivilegedClusterMigrationProvider) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(getClusterMigrationReq)
+
+		c, err := handlercommon.GetCluster(ctx, projectProvider, privilegedProjectProvider, userInfoGetter, req.ProjectID, req.ClusterID, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		migrations, err := clusterMigrationProvider.ListUnsecured(c.Name)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		if len(migrations.Items) == 0 {
+			return nil, errors.NewNotFound("cluster migration", c.Name)
+		}
+
+		// return the most recent migration
+		sort.Slice(migrations.Items, func(i, j int) bool {
+			return migrations.Items[j].CreationTimestamp.Before(&migrations.Items[i].CreationTimestamp)
+		})
+
+		return convertInternalToAPIClusterMigration(&migrations.Items[0]), nil
+	}
+}
+
+// getClusterMigrationReq represents a request for getting the migration of a cluster
+// swagger:parameters getClusterMigration
+type getClusterMigrationReq struct {
+	cluster.GetClusterReq
+}
+
+func DecodeGetClusterMigrationReq(c context.Context, r *http.Request) (interface{}, error) {
+	var req getClusterMigrationReq
+	cr, err := cluster.DecodeGetClusterReq(c, r)
+	if err != nil {
+		return nil, err
+	}
+	req.GetClusterReq = cr.(cluster.GetClusterReq)
+
+	return req, nil
+}
+
+func convertInternalToAPIClusterMigration(migration *kubermaticv1.ClusterMigration) *apiv2.ClusterMigration {
+	apiMigration := &apiv2.ClusterMigration{
+		Name: migration.Name,
+		Spec: apiv2.ClusterMigrationSpec{
+			ClusterID:        migration.Spec.ClusterName,
+			SourceSeed:       migration.Spec.SourceSeed,
+			TargetSeed:       migration.Spec.TargetSeed,
+			TargetDatacenter: migration.Spec.TargetDatacenter,
+			Destination:      migration.Spec.Destination,
+		},
+		Status: apiv2.ClusterMigrationStatus{
+			Phase:      migration.Status.Phase,
+			Message:    migration.Status.Message,
+			BackupName: migration.Status.BackupName,
+		},
+	}
+
+	if migration.Status.TargetAddress != nil {
+		apiMigration.Status.TargetURL = migration.Status.TargetAddress.URL
+		apiMigration.Status.TargetIP = migration.Status.TargetAddress.IP
+	}
+	if migration.Status.StartTime != nil {
+		startTime := apiv1.NewTime(migration.Status.StartTime.Time)
+		apiMigration.Status.StartTime = &startTime
+	}
+	if migration.Status.CompletionTime != nil {
+		completionTime := apiv1.NewTime(migration.Status.CompletionTime.Time)
+		apiMigration.Status.CompletionTime = &completionTime
+	}
+
+	return apiMigration
+}
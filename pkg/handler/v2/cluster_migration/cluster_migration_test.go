@@ -0,0 +1,210 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustermigration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
+	apiv2 "k8c.io/kubermatic/v2/pkg/api/v2"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/handler/test"
+	"k8c.io/kubermatic/v2/pkg/handler/test/hack"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func genClusterMigration(name string, created time.Time, phase kubermaticv1.ClusterMigrationPhase) *kubermaticv1.ClusterMigration {
+	return &kubermaticv1.ClusterMigration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: kubermaticv1.ClusterMigrationSpec{
+			ClusterName: test.GenDefaultCluster().Name,
+			SourceSeed:  "us-central1",
+			TargetSeed:  "europe-west3",
+			Destination: "s3",
+		},
+		Status: kubermaticv1.ClusterMigrationStatus{
+			Phase: phase,
+		},
+	}
+}
+
+func TestCreateEndpoint(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		Name                      string
+		Body                      string
+		ExistingKubermaticObjects []ctrlruntimeclient.Object
+		ExistingAPIUser           *apiv1.User
+		ExpectedHTTPStatusCode    int
+	}{
+		{
+			Name: "user bob cannot migrate his cluster because only admins can",
+			Body: `{"targetSeed":"europe-west3","destination":"s3"}`,
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				test.GenTestSeed(),
+				test.GenDefaultCluster(),
+			),
+			ExistingAPIUser:        test.GenDefaultAPIUser(),
+			ExpectedHTTPStatusCode: http.StatusForbidden,
+		},
+		{
+			Name: "admin cannot migrate a cluster to the seed it is already running on",
+			Body: `{"targetSeed":"us-central1","destination":"s3"}`,
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				test.GenTestSeed(),
+				test.GenDefaultCluster(),
+				test.GenAdminUser("John", "john@acme.com", true),
+			),
+			ExistingAPIUser:        test.GenAPIUser("John", "john@acme.com"),
+			ExpectedHTTPStatusCode: http.StatusBadRequest,
+		},
+		{
+			Name: "admin cannot migrate a cluster to a seed that does not exist",
+			Body: `{"targetSeed":"europe-west3","destination":"s3"}`,
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				test.GenTestSeed(),
+				test.GenDefaultCluster(),
+				test.GenAdminUser("John", "john@acme.com", true),
+			),
+			ExistingAPIUser:        test.GenAPIUser("John", "john@acme.com"),
+			ExpectedHTTPStatusCode: http.StatusBadRequest,
+		},
+		{
+			Name: "admin cannot migrate a cluster without a target seed",
+			Body: `{"destination":"s3"}`,
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				test.GenTestSeed(),
+				test.GenDefaultCluster(),
+				test.GenAdminUser("John", "john@acme.com", true),
+			),
+			ExistingAPIUser:        test.GenAPIUser("John", "john@acme.com"),
+			ExpectedHTTPStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			requestURL := fmt.Sprintf("/api/v2/projects/%s/clusters/%s/migrate", test.GenDefaultProject().Name, test.GenDefaultCluster().Name)
+			req := httptest.NewRequest(http.MethodPost, requestURL, bytes.NewBufferString(tc.Body))
+			resp := httptest.NewRecorder()
+
+			ep, err := test.CreateTestEndpoint(*tc.ExistingAPIUser, nil, tc.ExistingKubermaticObjects, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint: %v", err)
+			}
+			ep.ServeHTTP(resp, req)
+
+			if resp.Code != tc.ExpectedHTTPStatusCode {
+				t.Fatalf("Expected HTTP status code %d, got %d: %s", tc.ExpectedHTTPStatusCode, resp.Code, resp.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetEndpoint(t *testing.T) {
+	t.Parallel()
+	now := time.Now().Truncate(time.Second)
+
+	testCases := []struct {
+		Name                      string
+		ExistingKubermaticObjects []ctrlruntimeclient.Object
+		ExistingAPIUser           *apiv1.User
+		ExpectedHTTPStatusCode    int
+		ExpectedResponse          *apiv2.ClusterMigration
+	}{
+		{
+			Name: "user bob gets the most recent migration of his cluster",
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				test.GenTestSeed(),
+				test.GenDefaultCluster(),
+				genClusterMigration("old", now.Add(-time.Hour), kubermaticv1.ClusterMigrationPhaseFailed),
+				genClusterMigration("new", now, kubermaticv1.ClusterMigrationPhaseBackingUp),
+			),
+			ExistingAPIUser:        test.GenDefaultAPIUser(),
+			ExpectedHTTPStatusCode: http.StatusOK,
+			ExpectedResponse: &apiv2.ClusterMigration{
+				Name: "new",
+				Spec: apiv2.ClusterMigrationSpec{
+					ClusterID:   test.GenDefaultCluster().Name,
+					SourceSeed:  "us-central1",
+					TargetSeed:  "europe-west3",
+					Destination: "s3",
+				},
+				Status: apiv2.ClusterMigrationStatus{
+					Phase: kubermaticv1.ClusterMigrationPhaseBackingUp,
+				},
+			},
+		},
+		{
+			Name: "user bob gets a migration of a cluster that has never been migrated",
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				test.GenTestSeed(),
+				test.GenDefaultCluster(),
+			),
+			ExistingAPIUser:        test.GenDefaultAPIUser(),
+			ExpectedHTTPStatusCode: http.StatusNotFound,
+		},
+		{
+			Name: "user john cannot get the migration of bob's cluster",
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				test.GenTestSeed(),
+				test.GenDefaultCluster(),
+				test.GenAdminUser("John", "john@acme.com", false),
+				genClusterMigration("new", now, kubermaticv1.ClusterMigrationPhaseBackingUp),
+			),
+			ExistingAPIUser:        test.GenAPIUser("John", "john@acme.com"),
+			ExpectedHTTPStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			requestURL := fmt.Sprintf("/api/v2/projects/%s/clusters/%s/migrate", test.GenDefaultProject().Name, test.GenDefaultCluster().Name)
+			req := httptest.NewRequest(http.MethodGet, requestURL, nil)
+			resp := httptest.NewRecorder()
+
+			ep, err := test.CreateTestEndpoint(*tc.ExistingAPIUser, nil, tc.ExistingKubermaticObjects, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint: %v", err)
+			}
+			ep.ServeHTTP(resp, req)
+
+			if resp.Code != tc.ExpectedHTTPStatusCode {
+				t.Fatalf("Expected HTTP status code %d, got %d: %s", tc.ExpectedHTTPStatusCode, resp.Code, resp.Body.String())
+			}
+			if resp.Code == http.StatusOK {
+				b, err := json.Marshal(tc.ExpectedResponse)
+				if err != nil {
+					t.Fatalf("failed to marshal expected response: %v", err)
+				}
+
+				test.CompareWithResult(t, resp, string(b))
+			}
+		})
+	}
+}
@@ -91,7 +91,7 @@ func CreateEndpoint(
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
 
-		partialCluster, err := handlercommon.GenerateCluster(ctx, req.ProjectID, req.Body.CreateClusterSpec, seedsGetter, credentialManager, exposeStrategy, userInfoGetter, caBundle, configGetter)
+		partialCluster, err := handlercommon.GenerateCluster(ctx, project, req.Body.CreateClusterSpec, seedsGetter, credentialManager, exposeStrategy, userInfoGetter, caBundle, configGetter)
 		if err != nil {
 			return nil, err
 		}
@@ -116,7 +116,8 @@ func CreateEndpoint(
 		if err != nil {
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
-		if !isBYO {
+		hasAuditSinks := partialCluster.Spec.AuditLogging != nil && len(partialCluster.Spec.AuditLogging.Sinks) > 0
+		if !isBYO || hasAuditSinks {
 			kuberneteshelper.AddFinalizer(newClusterTemplate, apiv1.CredentialsSecretsCleanupFinalizer)
 		}
 
@@ -495,6 +496,7 @@ func convertInternalClusterTemplatetoExternal(template *kubermaticv1.ClusterTemp
 				ServiceAccount:                       template.Spec.ServiceAccount,
 				MLA:                                  template.Spec.MLA,
 				ContainerRuntime:                     template.Spec.ContainerRuntime,
+				APIServerAllowedIPRanges:             template.Spec.APIServerAllowedIPRanges,
 			},
 		},
 		NodeDeployment: md,
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/go-kit/kit/endpoint"
 	"github.com/gorilla/mux"
@@ -226,8 +227,17 @@ func PatchEndpoint(userInfoGetter provider.UserInfoGetter, projectProvider provi
 		}
 		newEBC := originalEBC.DeepCopy()
 		newEBC.Spec.Keep = req.Body.Keep
+		newEBC.Spec.Retention, err = convertAPIToInternalBackupRetention(req.Body.Retention)
+		if err != nil {
+			return nil, err
+		}
 		newEBC.Spec.Schedule = req.Body.Schedule
 		newEBC.Spec.Destination = req.Body.Destination
+		newEBC.Spec.Continuous, err = convertAPIToInternalContinuousBackup(req.Body.Continuous)
+		if err != nil {
+			return nil, err
+		}
+		newEBC.Spec.Verification = convertAPIToInternalBackupVerification(req.Body.Verification)
 
 		// apply patch
 		ebc, err := patchEtcdBackupConfig(ctx, userInfoGetter, req.ProjectID, originalEBC, newEBC)
@@ -360,10 +370,13 @@ func convertInternalToAPIEtcdBackupConfig(ebc *kubermaticv1.EtcdBackupConfig) *a
 			}(),
 		},
 		Spec: apiv2.EtcdBackupConfigSpec{
-			ClusterID:   ebc.Spec.Cluster.Name,
-			Schedule:    ebc.Spec.Schedule,
-			Keep:        ebc.Spec.Keep,
-			Destination: ebc.Spec.Destination,
+			ClusterID:    ebc.Spec.Cluster.Name,
+			Schedule:     ebc.Spec.Schedule,
+			Keep:         ebc.Spec.Keep,
+			Retention:    convertInternalToAPIBackupRetention(ebc.Spec.Retention),
+			Destination:  ebc.Spec.Destination,
+			Continuous:   convertInternalToAPIContinuousBackup(ebc.Spec.Continuous),
+			Verification: convertInternalToAPIBackupVerification(ebc.Spec.Verification),
 		},
 		Status: apiv2.EtcdBackupConfigStatus{
 			CurrentBackups: []apiv2.BackupStatus{},
@@ -395,18 +408,28 @@ func convertInternalToAPIEtcdBackupConfig(ebc *kubermaticv1.EtcdBackupConfig) *a
 		}
 
 		apiBackupStatus := apiv2.BackupStatus{
-			ScheduledTime:      &scheduledTime,
-			BackupName:         backupStatus.BackupName,
-			JobName:            backupStatus.JobName,
-			BackupStartTime:    &backupStartTime,
-			BackupFinishedTime: &backupFinishedTime,
-			BackupPhase:        backupStatus.BackupPhase,
-			BackupMessage:      backupStatus.BackupMessage,
-			DeleteJobName:      backupStatus.DeleteJobName,
-			DeleteStartTime:    &deleteStartTime,
-			DeleteFinishedTime: &deleteFinishedTime,
-			DeletePhase:        backupStatus.DeletePhase,
-			DeleteMessage:      backupStatus.DeleteMessage,
+			ScheduledTime:       &scheduledTime,
+			BackupName:          backupStatus.BackupName,
+			JobName:             backupStatus.JobName,
+			BackupStartTime:     &backupStartTime,
+			BackupFinishedTime:  &backupFinishedTime,
+			BackupPhase:         backupStatus.BackupPhase,
+			BackupMessage:       backupStatus.BackupMessage,
+			DeleteJobName:       backupStatus.DeleteJobName,
+			DeleteStartTime:     &deleteStartTime,
+			DeleteFinishedTime:  &deleteFinishedTime,
+			DeletePhase:         backupStatus.DeletePhase,
+			DeleteMessage:       backupStatus.DeleteMessage,
+			VerificationJobName: backupStatus.VerificationJobName,
+			VerificationPhase:   backupStatus.VerificationPhase,
+			VerificationMessage: backupStatus.VerificationMessage,
+			SnapshotRevision:    backupStatus.SnapshotRevision,
+			SnapshotKeyCount:    backupStatus.SnapshotKeyCount,
+			SnapshotHash:        backupStatus.SnapshotHash,
+		}
+		if backupStatus.VerificationFinishedTime != nil {
+			verificationFinishedTime := apiv1.NewTime(backupStatus.VerificationFinishedTime.Time)
+			apiBackupStatus.VerificationFinishedTime = &verificationFinishedTime
 		}
 		etcdBackupConfig.Status.CurrentBackups = append(etcdBackupConfig.Status.CurrentBackups, apiBackupStatus)
 	}
@@ -436,6 +459,16 @@ func convertAPIToInternalEtcdBackupConfig(name string, ebcSpec *apiv2.EtcdBackup
 		return nil, errors.New(http.StatusInternalServerError, fmt.Sprintf("error getting cluster object reference: %v", err))
 	}
 
+	continuous, err := convertAPIToInternalContinuousBackup(ebcSpec.Continuous)
+	if err != nil {
+		return nil, err
+	}
+
+	backupRetention, err := convertAPIToInternalBackupRetention(ebcSpec.Retention)
+	if err != nil {
+		return nil, err
+	}
+
 	return &kubermaticv1.EtcdBackupConfig{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      rand.String(10),
@@ -445,15 +478,116 @@ func convertAPIToInternalEtcdBackupConfig(name string, ebcSpec *apiv2.EtcdBackup
 			},
 		},
 		Spec: kubermaticv1.EtcdBackupConfigSpec{
-			Name:        name,
-			Cluster:     *clusterObjectRef,
-			Schedule:    ebcSpec.Schedule,
-			Keep:        ebcSpec.Keep,
-			Destination: ebcSpec.Destination,
+			Name:         name,
+			Cluster:      *clusterObjectRef,
+			Schedule:     ebcSpec.Schedule,
+			Keep:         ebcSpec.Keep,
+			Retention:    backupRetention,
+			Destination:  ebcSpec.Destination,
+			Continuous:   continuous,
+			Verification: convertAPIToInternalBackupVerification(ebcSpec.Verification),
 		},
 	}, nil
 }
 
+func convertInternalToAPIContinuousBackup(continuous *kubermaticv1.EtcdContinuousBackup) *apiv2.EtcdContinuousBackup {
+	if continuous == nil {
+		return nil
+	}
+
+	apiContinuous := &apiv2.EtcdContinuousBackup{
+		Enabled: continuous.Enabled,
+	}
+	if continuous.FlushInterval != nil {
+		apiContinuous.FlushInterval = continuous.FlushInterval.Duration.String()
+	}
+	if continuous.Retention != nil {
+		apiContinuous.Retention = continuous.Retention.Duration.String()
+	}
+
+	return apiContinuous
+}
+
+func convertAPIToInternalContinuousBackup(continuous *apiv2.EtcdContinuousBackup) (*kubermaticv1.EtcdContinuousBackup, error) {
+	if continuous == nil {
+		return nil, nil
+	}
+
+	internalContinuous := &kubermaticv1.EtcdContinuousBackup{
+		Enabled: continuous.Enabled,
+	}
+	if continuous.FlushInterval != "" {
+		flushInterval, err := time.ParseDuration(continuous.FlushInterval)
+		if err != nil || flushInterval <= 0 {
+			return nil, errors.NewBadRequest("invalid flush interval %q, must be a positive duration", continuous.FlushInterval)
+		}
+		internalContinuous.FlushInterval = &metav1.Duration{Duration: flushInterval}
+	}
+	if continuous.Retention != "" {
+		retention, err := time.ParseDuration(continuous.Retention)
+		if err != nil || retention < 0 {
+			return nil, errors.NewBadRequest("invalid retention %q, must be a non-negative duration", continuous.Retention)
+		}
+		internalContinuous.Retention = &metav1.Duration{Duration: retention}
+	}
+
+	return internalContinuous, nil
+}
+
+func convertInternalToAPIBackupVerification(verification *kubermaticv1.EtcdBackupVerification) *apiv2.EtcdBackupVerification {
+	if verification == nil {
+		return nil
+	}
+
+	return &apiv2.EtcdBackupVerification{
+		Enabled: verification.Enabled,
+		Restore: verification.Restore,
+	}
+}
+
+func convertAPIToInternalBackupVerification(verification *apiv2.EtcdBackupVerification) *kubermaticv1.EtcdBackupVerification {
+	if verification == nil {
+		return nil
+	}
+
+	return &kubermaticv1.EtcdBackupVerification{
+		Enabled: verification.Enabled,
+		Restore: verification.Restore,
+	}
+}
+
+func convertInternalToAPIBackupRetention(backupRetention *kubermaticv1.EtcdBackupRetention) *apiv2.EtcdBackupRetention {
+	if backupRetention == nil {
+		return nil
+	}
+
+	return &apiv2.EtcdBackupRetention{
+		Hourly:  backupRetention.Hourly,
+		Daily:   backupRetention.Daily,
+		Weekly:  backupRetention.Weekly,
+		Monthly: backupRetention.Monthly,
+	}
+}
+
+func convertAPIToInternalBackupRetention(backupRetention *apiv2.EtcdBackupRetention) (*kubermaticv1.EtcdBackupRetention, error) {
+	if backupRetention == nil {
+		return nil, nil
+	}
+
+	for _, count := range []int{backupRetention.Hourly, backupRetention.Daily, backupRetention.Weekly, backupRetention.Monthly} {
+		if count < 0 || count > kubermaticv1.MaxKeptBackupsCount {
+			return nil, errors.NewBadRequest("invalid retention count %d, must be between 0 and %d", count, kubermaticv1.MaxKeptBackupsCount)
+		}
+	}
+
+	return &kubermaticv1.EtcdBackupRetention{
+		Hourly:  backupRetention.Hourly,
+		Daily:   backupRetention.Daily,
+		Weekly:  backupRetention.Weekly,
+		Monthly: backupRetention.Monthly,
+	}, nil
+}
+
 func GenEtcdBackupConfigID(ebcName, clusterName string) string {
 	return fmt.Sprintf("%s-%s", clusterName, ebcName)
 }
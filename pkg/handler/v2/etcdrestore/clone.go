@@ -0,0 +1,222 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdrestore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-kit/kit/endpoint"
+
+	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
+	apiv2 "k8c.io/kubermatic/v2/pkg/api/v2"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	handlercommon "k8c.io/kubermatic/v2/pkg/handler/common"
+	"k8c.io/kubermatic/v2/pkg/handler/middleware"
+	"k8c.io/kubermatic/v2/pkg/handler/v1/common"
+	"k8c.io/kubermatic/v2/pkg/handler/v2/cluster"
+	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/provider"
+	kubernetesprovider "k8c.io/kubermatic/v2/pkg/provider/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/util/errors"
+	"k8c.io/kubermatic/v2/pkg/version"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+// CloneClusterEndpoint creates a new cluster from an etcd backup of an existing cluster. The new cluster gets
+// its own ID and certificates, the backup is restored into it by the cluster clone controller.
+func CloneClusterEndpoint(userInfoGetter provider.UserInfoGetter, projectProvider provider.ProjectProvider,
+	privilegedProjectProvider provider.PrivilegedProjectProvider, seedsGetter provider.SeedsGetter, configGetter provider.KubermaticConfigurationGetter) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(cloneClusterReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		clusterProvider := ctx.Value(middleware.ClusterProviderContextKey).(provider.ClusterProvider)
+		privilegedClusterProvider := ctx.Value(middleware.PrivilegedClusterProviderContextKey).(provider.PrivilegedClusterProvider)
+
+		project, err := common.GetProject(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, req.ProjectID, nil)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		source, err := handlercommon.GetInternalCluster(ctx, userInfoGetter, clusterProvider, privilegedClusterProvider, project, req.ProjectID, req.ClusterID, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		adminUserInfo, err := userInfoGetter(ctx, "")
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		seed, dc, err := provider.DatacenterFromSeedMap(adminUserInfo, seedsGetter, source.Spec.Cloud.DatacenterName)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		if req.Body.Destination != "" {
+			if seed.Spec.EtcdBackupRestore == nil || seed.Spec.EtcdBackupRestore.Destinations[req.Body.Destination] == nil {
+				return nil, errors.NewBadRequest("backup destination %q does not exist in seed %q", req.Body.Destination, seed.Name)
+			}
+		}
+
+		if req.Body.Name == "" {
+			req.Body.Name = fmt.Sprintf("%s-clone", source.Spec.HumanReadableName)
+		}
+
+		existingClusters, err := clusterProvider.List(project, &provider.ClusterListOptions{ClusterSpecName: req.Body.Name})
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		if len(existingClusters.Items) > 0 {
+			return nil, errors.NewAlreadyExists("cluster", req.Body.Name)
+		}
+
+		clone, err := genClonedCluster(source, &req.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		seedClient := privilegedClusterProvider.GetSeedClusterAdminRuntimeClient()
+		credentialsData := resources.NewCredentialsData(ctx, source, seedClient)
+		if err := resources.CopyCredentials(credentialsData, clone); err != nil {
+			return nil, fmt.Errorf("failed to get credentials: %v", err)
+		}
+		if err := resources.CopyAuditSinkCredentials(credentialsData, clone); err != nil {
+			return nil, fmt.Errorf("failed to get audit sink credentials: %v", err)
+		}
+		if err := kubernetesprovider.CreateOrUpdateCredentialSecretForCluster(ctx, seedClient, clone); err != nil {
+			return nil, err
+		}
+		kuberneteshelper.AddFinalizer(clone, apiv1.CredentialsSecretsCleanupFinalizer)
+
+		clone, err = createClonedCluster(ctx, userInfoGetter, clusterProvider, privilegedClusterProvider, project, clone)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		config, err := configGetter(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return handlercommon.ConvertInternalClusterToExternal(clone, dc, true, version.NewFromConfiguration(config).GetIncompatibilities()...), nil
+	}
+}
+
+// cloneClusterReq represents a request for creating a new cluster from an etcd backup of an existing cluster
+// swagger:parameters cloneCluster
+type cloneClusterReq struct {
+	cluster.GetClusterReq
+	// in: body
+	Body apiv2.ClusterCloneSpec
+}
+
+func (r *cloneClusterReq) validate() error {
+	if r.Body.BackupName == "" {
+		return errors.NewBadRequest("backup name cannot be empty")
+	}
+	if r.Body.RestoreToTime != nil && r.Body.RestoreToRevision != 0 {
+		return errors.NewBadRequest("restoreToTime and restoreToRevision are mutually exclusive")
+	}
+	if r.Body.RestoreToRevision < 0 {
+		return errors.NewBadRequest("restoreToRevision must not be negative")
+	}
+	return nil
+}
+
+func DecodeCloneClusterReq(c context.Context, r *http.Request) (interface{}, error) {
+	var req cloneClusterReq
+	cr, err := cluster.DecodeGetClusterReq(c, r)
+	if err != nil {
+		return nil, err
+	}
+	req.GetClusterReq = cr.(cluster.GetClusterReq)
+
+	if err = json.NewDecoder(r.Body).Decode(&req.Body); err != nil {
+		return nil, errors.NewBadRequest("unable to parse the input: %v", err)
+	}
+	return req, nil
+}
+
+// genClonedCluster returns a copy of the source cluster that will be seeded with the given backup. The
+// cloud provider resources that were created for the source cluster are shared, but as the clone does not
+// get their cleanup finalizers, they are not removed when the clone is deleted.
+func genClonedCluster(source *kubermaticv1.Cluster, spec *apiv2.ClusterCloneSpec) (*kubermaticv1.Cluster, error) {
+	restoreSpec := kubermaticv1.EtcdRestoreSpec{
+		BackupName:        spec.BackupName,
+		SourceClusterName: source.Name,
+		Destination:       spec.Destination,
+		RestoreToRevision: spec.RestoreToRevision,
+	}
+	if spec.RestoreToTime != nil {
+		restoreSpec.RestoreToTime = &metav1.Time{Time: spec.RestoreToTime.Time}
+	}
+
+	cloneRequest, err := json.Marshal(restoreSpec)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal clone request: %v", err)
+	}
+
+	clone := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   rand.String(10),
+			Labels: map[string]string{},
+			Annotations: map[string]string{
+				kubermaticv1.CloneRequestAnnotation: string(cloneRequest),
+			},
+		},
+		Spec: *source.Spec.DeepCopy(),
+	}
+
+	for k, v := range source.Labels {
+		clone.Labels[k] = v
+	}
+	if value, ok := source.Annotations[kubermaticv1.AnnotationNameClusterAutoscalerEnabled]; ok {
+		clone.Annotations[kubermaticv1.AnnotationNameClusterAutoscalerEnabled] = value
+	}
+
+	clone.Spec.HumanReadableName = spec.Name
+	clone.Spec.Pause = false
+	clone.Spec.PauseReason = ""
+
+	return clone, nil
+}
+
+func createClonedCluster(ctx context.Context, userInfoGetter provider.UserInfoGetter, clusterProvider provider.ClusterProvider,
+	privilegedClusterProvider provider.PrivilegedClusterProvider, project *kubermaticv1.Project, cluster *kubermaticv1.Cluster) (*kubermaticv1.Cluster, error) {
+	adminUserInfo, err := userInfoGetter(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	if adminUserInfo.IsAdmin {
+		return privilegedClusterProvider.NewUnsecured(project, cluster, adminUserInfo.Email)
+	}
+	userInfo, err := userInfoGetter(ctx, project.Name)
+	if err != nil {
+		return nil, err
+	}
+	return clusterProvider.New(project, userInfo, cluster)
+}
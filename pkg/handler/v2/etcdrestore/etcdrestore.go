@@ -98,6 +98,12 @@ func (r *createEtcdRestoreReq) validate() error {
 	if r.Body.Spec.BackupName == "" {
 		return errors.NewBadRequest("backup name cannot be empty")
 	}
+	if r.Body.Spec.RestoreToTime != nil && r.Body.Spec.RestoreToRevision != 0 {
+		return errors.NewBadRequest("restoreToTime and restoreToRevision are mutually exclusive")
+	}
+	if r.Body.Spec.RestoreToRevision < 0 {
+		return errors.NewBadRequest("restoreToRevision must not be negative")
+	}
 	// NOTE we can check if the backup really exists on S3 or if the backup secret exists (if set), but the restore status will give this info as well
 	return nil
 }
@@ -283,11 +289,16 @@ func convertInternalToAPIEtcdRestore(er *kubermaticv1.EtcdRestore) *apiv2.EtcdRe
 			ClusterID:                       er.Spec.Cluster.Name,
 			BackupName:                      er.Spec.BackupName,
 			BackupDownloadCredentialsSecret: er.Spec.BackupDownloadCredentialsSecret,
+			RestoreToRevision:               er.Spec.RestoreToRevision,
 		},
 		Status: apiv2.EtcdRestoreStatus{
 			Phase: er.Status.Phase,
 		},
 	}
+	if er.Spec.RestoreToTime != nil {
+		restoreToTime := apiv1.NewTime(er.Spec.RestoreToTime.Time)
+		etcdRestore.Spec.RestoreToTime = &restoreToTime
+	}
 	if er.Status.RestoreTime != nil {
 		restoreTime := apiv1.NewTime(er.Status.RestoreTime.Time)
 		etcdRestore.Status.RestoreTime = &restoreTime
@@ -302,7 +313,7 @@ func convertAPIToInternalEtcdRestore(name string, erSpec *apiv2.EtcdRestoreSpec,
 		return nil, errors.New(http.StatusInternalServerError, fmt.Sprintf("error getting cluster object reference: %v", err))
 	}
 
-	return &kubermaticv1.EtcdRestore{
+	etcdRestore := &kubermaticv1.EtcdRestore{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
 			Namespace: cluster.Status.NamespaceName,
@@ -315,8 +326,14 @@ func convertAPIToInternalEtcdRestore(name string, erSpec *apiv2.EtcdRestoreSpec,
 			Cluster:                         *clusterObjectRef,
 			BackupName:                      erSpec.BackupName,
 			BackupDownloadCredentialsSecret: erSpec.BackupDownloadCredentialsSecret,
+			RestoreToRevision:               erSpec.RestoreToRevision,
 		},
-	}, nil
+	}
+	if erSpec.RestoreToTime != nil {
+		etcdRestore.Spec.RestoreToTime = &metav1.Time{Time: erSpec.RestoreToTime.Time}
+	}
+
+	return etcdRestore, nil
 }
 
 func createEtcdRestore(ctx context.Context, userInfoGetter provider.UserInfoGetter, projectID string, etcdRestore *kubermaticv1.EtcdRestore) (*kubermaticv1.EtcdRestore, error) {
@@ -18,6 +18,7 @@ package etcdrestore_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -31,6 +32,7 @@ import (
 	"k8c.io/kubermatic/v2/pkg/handler/test"
 	"k8c.io/kubermatic/v2/pkg/handler/test/hack"
 
+	"k8s.io/apimachinery/pkg/types"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -527,3 +529,129 @@ func TestProjectListEndpoint(t *testing.T) {
 		})
 	}
 }
+
+func TestCloneClusterEndpoint(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		Name                      string
+		ExistingKubermaticObjects []ctrlruntimeclient.Object
+		ExistingAPIUser           *apiv1.User
+		Body                      apiv2.ClusterCloneSpec
+		ExpectedHTTPStatusCode    int
+		ExpectedName              string
+	}{
+		{
+			Name: "clone cluster from backup",
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				test.GenTestSeed(),
+				test.GenDefaultCluster(),
+			),
+			ExistingAPIUser:        test.GenDefaultAPIUser(),
+			Body:                   apiv2.ClusterCloneSpec{BackupName: "nightly"},
+			ExpectedHTTPStatusCode: http.StatusCreated,
+			ExpectedName:           fmt.Sprintf("%s-clone", test.DefaultClusterName),
+		},
+		{
+			Name: "admin user john can clone bob's cluster",
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				test.GenTestSeed(),
+				test.GenDefaultCluster(),
+				test.GenAdminUser("John", "john@acme.com", true),
+			),
+			ExistingAPIUser:        test.GenAPIUser("John", "john@acme.com"),
+			Body:                   apiv2.ClusterCloneSpec{Name: "debugging", BackupName: "nightly"},
+			ExpectedHTTPStatusCode: http.StatusCreated,
+			ExpectedName:           "debugging",
+		},
+		{
+			Name: "user john cannot clone bob's cluster",
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				test.GenTestSeed(),
+				test.GenDefaultCluster(),
+				test.GenAdminUser("John", "john@acme.com", false),
+			),
+			ExistingAPIUser:        test.GenAPIUser("John", "john@acme.com"),
+			Body:                   apiv2.ClusterCloneSpec{BackupName: "nightly"},
+			ExpectedHTTPStatusCode: http.StatusForbidden,
+		},
+		{
+			Name: "backup name is required",
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				test.GenTestSeed(),
+				test.GenDefaultCluster(),
+			),
+			ExistingAPIUser:        test.GenDefaultAPIUser(),
+			Body:                   apiv2.ClusterCloneSpec{},
+			ExpectedHTTPStatusCode: http.StatusBadRequest,
+		},
+		{
+			Name: "backup destination must exist",
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				test.GenTestSeed(),
+				test.GenDefaultCluster(),
+			),
+			ExistingAPIUser:        test.GenDefaultAPIUser(),
+			Body:                   apiv2.ClusterCloneSpec{BackupName: "nightly", Destination: "missing"},
+			ExpectedHTTPStatusCode: http.StatusBadRequest,
+		},
+		{
+			Name: "cluster name must be unique",
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				test.GenTestSeed(),
+				test.GenDefaultCluster(),
+			),
+			ExistingAPIUser:        test.GenDefaultAPIUser(),
+			Body:                   apiv2.ClusterCloneSpec{Name: test.DefaultClusterName, BackupName: "nightly"},
+			ExpectedHTTPStatusCode: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			requestURL := fmt.Sprintf("/api/v2/projects/%s/clusters/%s/clone", test.GenDefaultProject().Name, test.GenDefaultCluster().Name)
+			body, err := json.Marshal(tc.Body)
+			if err != nil {
+				t.Fatalf("failed marshalling clone spec: %v", err)
+			}
+			req := httptest.NewRequest(http.MethodPost, requestURL, bytes.NewBuffer(body))
+			resp := httptest.NewRecorder()
+
+			ep, clients, err := test.CreateTestEndpointAndGetClients(*tc.ExistingAPIUser, nil, nil, nil, tc.ExistingKubermaticObjects, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to: %v", err)
+			}
+			ep.ServeHTTP(resp, req)
+
+			if resp.Code != tc.ExpectedHTTPStatusCode {
+				t.Fatalf("Expected HTTP status code %d, got %d: %s", tc.ExpectedHTTPStatusCode, resp.Code, resp.Body.String())
+			}
+			if resp.Code != http.StatusCreated {
+				return
+			}
+
+			apiCluster := &apiv1.Cluster{}
+			if err := json.Unmarshal(resp.Body.Bytes(), apiCluster); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if apiCluster.Name != tc.ExpectedName {
+				t.Errorf("Expected cluster name %q, got %q", tc.ExpectedName, apiCluster.Name)
+			}
+			if apiCluster.ID == test.GenDefaultCluster().Name {
+				t.Fatal("Expected the clone to get a new cluster ID")
+			}
+
+			clone := &kubermaticv1.Cluster{}
+			if err := clients.FakeClient.Get(context.Background(), types.NamespacedName{Name: apiCluster.ID}, clone); err != nil {
+				t.Fatalf("failed to get cloned cluster: %v", err)
+			}
+
+			restoreSpec := &kubermaticv1.EtcdRestoreSpec{}
+			if err := json.Unmarshal([]byte(clone.Annotations[kubermaticv1.CloneRequestAnnotation]), restoreSpec); err != nil {
+				t.Fatalf("failed to unmarshal clone request: %v", err)
+			}
+			if restoreSpec.SourceClusterName != test.GenDefaultCluster().Name || restoreSpec.BackupName != tc.Body.BackupName {
+				t.Errorf("Clone request %+v does not match the source cluster and backup", restoreSpec)
+			}
+		})
+	}
+}
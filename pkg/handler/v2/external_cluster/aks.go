@@ -19,6 +19,7 @@ package externalcluster
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/Azure/azure-sdk-for-go/profiles/latest/containerservice/mgmt/containerservice"
 	"github.com/Azure/go-autorest/autorest/azure/auth"
@@ -35,7 +36,13 @@ import (
 	"k8c.io/kubermatic/v2/pkg/util/errors"
 )
 
-const AKSNodepoolNameLabel = "kubernetes.azure.com/agentpool"
+const (
+	AKSNodepoolNameLabel = "kubernetes.azure.com/agentpool"
+
+	aksDefaultNodePoolName = "nodepool1"
+	aksDefaultNodeCount    = 3
+	aksDefaultVMSize       = "Standard_DS2_v2"
+)
 
 func createAKSCluster(ctx context.Context, name string, userInfoGetter provider.UserInfoGetter, project *kubermaticapiv1.Project, cloud *apiv2.ExternalClusterCloudSpec, clusterProvider provider.ExternalClusterProvider, privilegedClusterProvider provider.PrivilegedExternalClusterProvider) (*kubermaticapiv1.ExternalCluster, error) {
 	if cloud.AKS.Name == "" || cloud.AKS.TenantID == "" || cloud.AKS.SubscriptionID == "" || cloud.AKS.ClientID == "" || cloud.AKS.ClientSecret == "" || cloud.AKS.ResourceGroup == "" {
@@ -49,6 +56,7 @@ func createAKSCluster(ctx context.Context, name string, userInfoGetter provider.
 			ResourceGroup: cloud.AKS.ResourceGroup,
 		},
 	}
+
 	keyRef, err := clusterProvider.CreateOrUpdateCredentialSecretForCluster(ctx, cloud, project.Name, newCluster.Name)
 	if err != nil {
 		return nil, common.KubernetesErrorToHTTPError(err)
@@ -56,9 +64,81 @@ func createAKSCluster(ctx context.Context, name string, userInfoGetter provider.
 	kuberneteshelper.AddFinalizer(newCluster, apiv1.CredentialsSecretsCleanupFinalizer)
 	newCluster.Spec.CloudSpec.AKS.CredentialsReference = keyRef
 
+	if cloud.AKS.ClusterSpec != nil {
+		// the cluster is owned by Kubermatic and is removed from AKS together with the external cluster
+		return createNewCloudCluster(ctx, userInfoGetter, clusterProvider, privilegedClusterProvider, newCluster, project, "AKS", func() error {
+			return createNewAKSCluster(ctx, cloud.AKS)
+		})
+	}
+
 	return createNewCluster(ctx, userInfoGetter, clusterProvider, privilegedClusterProvider, newCluster, project)
 }
 
+func createNewAKSCluster(ctx context.Context, aksCloudSpec *apiv2.AKSCloudSpec) error {
+	clusterSpec := aksCloudSpec.ClusterSpec
+	if clusterSpec.Location == "" {
+		return fmt.Errorf("the location can not be empty")
+	}
+
+	aksClient, err := getAKSClusterClient(azure.Credentials{
+		TenantID:       aksCloudSpec.TenantID,
+		SubscriptionID: aksCloudSpec.SubscriptionID,
+		ClientID:       aksCloudSpec.ClientID,
+		ClientSecret:   aksCloudSpec.ClientSecret,
+	})
+	if err != nil {
+		return err
+	}
+
+	// CreateOrUpdate would silently take over and reconfigure an existing cluster
+	existingCluster, err := aksClient.Get(ctx, aksCloudSpec.ResourceGroup, aksCloudSpec.Name)
+	if err == nil {
+		return fmt.Errorf("cluster %s already exists in resource group %s", aksCloudSpec.Name, aksCloudSpec.ResourceGroup)
+	}
+	if existingCluster.Response.Response == nil || existingCluster.StatusCode != http.StatusNotFound {
+		return err
+	}
+
+	dnsPrefix := clusterSpec.DNSPrefix
+	if dnsPrefix == "" {
+		dnsPrefix = aksCloudSpec.Name
+	}
+	nodeCount := clusterSpec.NodeCount
+	if nodeCount == 0 {
+		nodeCount = aksDefaultNodeCount
+	}
+	vmSize := clusterSpec.VMSize
+	if vmSize == "" {
+		vmSize = aksDefaultVMSize
+	}
+	nodePoolName := aksDefaultNodePoolName
+
+	newCluster := containerservice.ManagedCluster{
+		Location: &clusterSpec.Location,
+		ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+			DNSPrefix: &dnsPrefix,
+			AgentPoolProfiles: &[]containerservice.ManagedClusterAgentPoolProfile{
+				{
+					Name:   &nodePoolName,
+					Count:  &nodeCount,
+					VMSize: &vmSize,
+					Mode:   containerservice.AgentPoolModeSystem,
+				},
+			},
+			ServicePrincipalProfile: &containerservice.ManagedClusterServicePrincipalProfile{
+				ClientID: &aksCloudSpec.ClientID,
+				Secret:   &aksCloudSpec.ClientSecret,
+			},
+		},
+	}
+	if clusterSpec.KubernetesVersion != "" {
+		newCluster.KubernetesVersion = &clusterSpec.KubernetesVersion
+	}
+
+	_, err = aksClient.CreateOrUpdate(ctx, aksCloudSpec.ResourceGroup, aksCloudSpec.Name, newCluster)
+	return err
+}
+
 func patchAKSCluster(ctx context.Context, old, new *apiv2.ExternalCluster, secretKeySelector provider.SecretKeySelectorValueFunc, cloud *kubermaticapiv1.ExternalClusterCloudSpec) (*apiv2.ExternalCluster, error) {
 	clusterName := cloud.AKS.Name
 	resourceGroup := cloud.AKS.ResourceGroup
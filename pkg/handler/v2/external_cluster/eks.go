@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/eks"
 
 	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
@@ -52,6 +53,7 @@ func createEKSCluster(ctx context.Context, name string, userInfoGetter provider.
 			Region: cloud.EKS.Region,
 		},
 	}
+
 	keyRef, err := clusterProvider.CreateOrUpdateCredentialSecretForCluster(ctx, cloud, project.Name, newCluster.Name)
 	if err != nil {
 		return nil, common.KubernetesErrorToHTTPError(err)
@@ -59,9 +61,42 @@ func createEKSCluster(ctx context.Context, name string, userInfoGetter provider.
 	kuberneteshelper.AddFinalizer(newCluster, apiv1.CredentialsSecretsCleanupFinalizer)
 	newCluster.Spec.CloudSpec.EKS.CredentialsReference = keyRef
 
+	if cloud.EKS.ClusterSpec != nil {
+		// the cluster is owned by Kubermatic and is removed from EKS together with the external cluster
+		return createNewCloudCluster(ctx, userInfoGetter, clusterProvider, privilegedClusterProvider, newCluster, project, "EKS", func() error {
+			return createNewEKSCluster(cloud.EKS)
+		})
+	}
+
 	return createNewCluster(ctx, userInfoGetter, clusterProvider, privilegedClusterProvider, newCluster, project)
 }
 
+func createNewEKSCluster(eksCloudSpec *apiv2.EKSCloudSpec) error {
+	clusterSpec := eksCloudSpec.ClusterSpec
+	if clusterSpec.RoleArn == "" || len(clusterSpec.SubnetIDs) == 0 {
+		return fmt.Errorf("the role ARN and subnets can not be empty")
+	}
+
+	client, err := awsprovider.GetClientSet(eksCloudSpec.AccessKeyID, eksCloudSpec.SecretAccessKey, "", "", eksCloudSpec.Region)
+	if err != nil {
+		return err
+	}
+
+	createInput := &eks.CreateClusterInput{
+		Name:    aws.String(eksCloudSpec.Name),
+		RoleArn: aws.String(clusterSpec.RoleArn),
+		ResourcesVpcConfig: &eks.VpcConfigRequest{
+			SubnetIds:        aws.StringSlice(clusterSpec.SubnetIDs),
+			SecurityGroupIds: aws.StringSlice(clusterSpec.SecurityGroupIDs),
+		},
+	}
+	if clusterSpec.Version != "" {
+		createInput.Version = aws.String(clusterSpec.Version)
+	}
+	_, err = client.EKS.CreateCluster(createInput)
+	return err
+}
+
 func patchEKSCluster(old, new *apiv2.ExternalCluster, secretKeySelector provider.SecretKeySelectorValueFunc, cloudSpec *kubermaticapiv1.ExternalClusterCloudSpec) (*apiv2.ExternalCluster, error) {
 
 	accessKeyID, secretAccessKey, err := awsprovider.GetCredentialsForEKSCluster(*cloudSpec, secretKeySelector)
@@ -30,10 +30,12 @@ import (
 	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
 	apiv2 "k8c.io/kubermatic/v2/pkg/api/v2"
 	kubermaticapiv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1/helper"
 	handlercommon "k8c.io/kubermatic/v2/pkg/handler/common"
 	"k8c.io/kubermatic/v2/pkg/handler/v1/common"
 	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
 	"k8c.io/kubermatic/v2/pkg/provider"
+	ksemver "k8c.io/kubermatic/v2/pkg/semver"
 	"k8c.io/kubermatic/v2/pkg/util/errors"
 
 	corev1 "k8s.io/api/core/v1"
@@ -71,7 +73,7 @@ func CreateEndpoint(userInfoGetter provider.UserInfoGetter, projectProvider prov
 		}
 		var preset *kubermaticapiv1.Preset
 		if len(req.Credential) > 0 {
-			preset, err = presetProvider.GetPreset(userInfo, req.Credential)
+			preset, err = presetProvider.GetPreset(ctx, userInfo, req.Credential)
 			if err != nil {
 				return nil, errors.New(http.StatusInternalServerError, fmt.Sprintf("can not get preset %s for user %s", req.Credential, userInfo.Email))
 			}
@@ -96,6 +98,9 @@ func CreateEndpoint(userInfoGetter provider.UserInfoGetter, projectProvider prov
 			}
 
 			newCluster := genExternalCluster(req.Body.Name, project.Name)
+			if req.Body.RotateKubeconfig {
+				newCluster.Spec.KubeconfigRotation = &kubermaticapiv1.ExternalClusterKubeconfigRotation{}
+			}
 
 			kuberneteshelper.AddFinalizer(newCluster, apiv1.ExternalClusterKubeconfigCleanupFinalizer)
 
@@ -109,7 +114,7 @@ func CreateEndpoint(userInfoGetter provider.UserInfoGetter, projectProvider prov
 			}
 			return convertClusterToAPI(createdCluster), nil
 		}
-		// import GKE cluster
+		// import or create GKE cluster
 		if cloud.GKE != nil {
 			if preset != nil {
 				if credentials := preset.Spec.GCP; credentials != nil {
@@ -122,7 +127,7 @@ func CreateEndpoint(userInfoGetter provider.UserInfoGetter, projectProvider prov
 			}
 			return convertClusterToAPI(createdCluster), nil
 		}
-		// import EKS cluster
+		// import or create EKS cluster
 		if cloud.EKS != nil {
 			if preset != nil {
 				if credentials := preset.Spec.AWS; credentials != nil {
@@ -138,7 +143,7 @@ func CreateEndpoint(userInfoGetter provider.UserInfoGetter, projectProvider prov
 
 			return convertClusterToAPI(createdCluster), nil
 		}
-		// import AKS cluster
+		// import or create AKS cluster
 		if cloud.AKS != nil {
 			if preset != nil {
 				if credentials := preset.Spec.Azure; credentials != nil {
@@ -336,12 +341,22 @@ func GetEndpoint(userInfoGetter provider.UserInfoGetter, projectProvider provide
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
 
+		apiCluster := convertClusterToAPI(cluster)
+		if !isControlPlaneReachable(cluster) {
+			// fall back to the version observed during the last successful health check
+			if version, err := ksemver.NewSemver(cluster.Status.Version); err == nil {
+				apiCluster.Spec = apiv1.ClusterSpec{
+					Version: *version,
+				}
+			}
+			return apiCluster, nil
+		}
+
 		version, err := clusterProvider.GetVersion(cluster)
 		if err != nil {
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
 
-		apiCluster := convertClusterToAPI(cluster)
 		apiCluster.Spec = apiv1.ClusterSpec{
 			Version: *version,
 		}
@@ -456,6 +471,9 @@ func PatchEndpoint(userInfoGetter provider.UserInfoGetter, projectProvider provi
 		if err != nil {
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
+		if !isControlPlaneReachable(cluster) {
+			return nil, errors.NewBadRequest("cluster can not be patched, the control plane of the cluster is not reachable")
+		}
 
 		version, err := clusterProvider.GetVersion(cluster)
 		if err != nil {
@@ -796,6 +814,28 @@ func createNewCluster(ctx context.Context, userInfoGetter provider.UserInfoGette
 	return clusterProvider.New(userInfo, project, cluster)
 }
 
+// createNewCloudCluster persists the external cluster before create is called to create its cluster at the
+// cloud provider. The cleanup finalizer, which deletes the cloud cluster together with the external cluster,
+// is only added once the cloud cluster was created. This way, a failed create, e.g. because a cloud cluster
+// with the same name exists already, never deletes a cloud cluster that does not belong to the external
+// cluster. If the cloud cluster can not be created, the external cluster is deleted right away.
+func createNewCloudCluster(ctx context.Context, userInfoGetter provider.UserInfoGetter, clusterProvider provider.ExternalClusterProvider, privilegedClusterProvider provider.PrivilegedExternalClusterProvider, cluster *kubermaticapiv1.ExternalCluster, project *kubermaticapiv1.Project, providerName string, create func() error) (*kubermaticapiv1.ExternalCluster, error) {
+	newCluster, err := createNewCluster(ctx, userInfoGetter, clusterProvider, privilegedClusterProvider, cluster, project)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := create(); err != nil {
+		if deleteErr := deleteCluster(ctx, userInfoGetter, clusterProvider, privilegedClusterProvider, project.Name, newCluster); deleteErr != nil {
+			return nil, errors.NewBadRequest("failed to create %s cluster: %v, failed to delete external cluster %s: %v", providerName, err, newCluster.Name, deleteErr)
+		}
+		return nil, errors.NewBadRequest("failed to create %s cluster: %v", providerName, err)
+	}
+
+	kuberneteshelper.AddFinalizer(newCluster, apiv1.ExternalClusterCloudCleanupFinalizer)
+	return updateCluster(ctx, userInfoGetter, clusterProvider, privilegedClusterProvider, project.Name, newCluster)
+}
+
 func convertClusterToAPI(internalCluster *kubermaticapiv1.ExternalCluster) *apiv2.ExternalCluster {
 	cluster := &apiv2.ExternalCluster{
 		Cluster: apiv1.Cluster{
@@ -814,6 +854,31 @@ func convertClusterToAPI(internalCluster *kubermaticapiv1.ExternalCluster) *apiv
 			Labels: internalCluster.Labels,
 			Type:   apiv1.KubernetesClusterType,
 		},
+		Status: apiv2.ExternalClusterStatus{
+			Phase:         internalCluster.Status.Phase,
+			StatusMessage: internalCluster.Status.StatusMessage,
+			Version:       internalCluster.Status.Version,
+			NodeCount:     internalCluster.Status.NodeCount,
+		},
+	}
+
+	if !internalCluster.Status.LastSeen.IsZero() {
+		lastSeen := apiv1.NewTime(internalCluster.Status.LastSeen.Time)
+		cluster.Status.LastSeen = &lastSeen
+	}
+	if internalCluster.Status.KubeconfigExpiry != nil {
+		kubeconfigExpiry := apiv1.NewTime(internalCluster.Status.KubeconfigExpiry.Time)
+		cluster.Status.KubeconfigExpiry = &kubeconfigExpiry
+	}
+	for _, condition := range internalCluster.Status.Conditions {
+		cluster.Status.Conditions = append(cluster.Status.Conditions, apiv2.ExternalClusterCondition{
+			Type:               condition.Type,
+			Status:             condition.Status,
+			LastHeartbeatTime:  apiv1.NewTime(condition.LastHeartbeatTime.Time),
+			LastTransitionTime: apiv1.NewTime(condition.LastTransitionTime.Time),
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+		})
 	}
 
 	cloud := internalCluster.Spec.CloudSpec
@@ -836,6 +901,21 @@ func convertClusterToAPI(internalCluster *kubermaticapiv1.ExternalCluster) *apiv
 	return cluster
 }
 
+// isControlPlaneReachable tells if the kubeconfig of the cluster can be used. Clusters imported from
+// a kubeconfig have no phase, they are expected to be reachable unless the last health check failed.
+func isControlPlaneReachable(cluster *kubermaticapiv1.ExternalCluster) bool {
+	if helper.ExternalClusterConditionHasStatus(cluster, kubermaticapiv1.ExternalClusterConditionReachable, corev1.ConditionFalse) {
+		return false
+	}
+
+	switch cluster.Status.Phase {
+	case "", kubermaticapiv1.ExternalClusterPhaseRunning, kubermaticapiv1.ExternalClusterPhaseReconciling:
+		return true
+	default:
+		return false
+	}
+}
+
 func getCluster(ctx context.Context, userInfoGetter provider.UserInfoGetter, clusterProvider provider.ExternalClusterProvider, privilegedClusterProvider provider.PrivilegedExternalClusterProvider, projectID, clusterName string) (*kubermaticapiv1.ExternalCluster, error) {
 	adminUserInfo, err := userInfoGetter(ctx, "")
 	if err != nil {
@@ -899,6 +979,9 @@ type body struct {
 	// Kubeconfig Base64 encoded kubeconfig
 	Kubeconfig string                          `json:"kubeconfig,omitempty"`
 	Cloud      *apiv2.ExternalClusterCloudSpec `json:"cloud,omitempty"`
+	// RotateKubeconfig makes Kubermatic install a dedicated ServiceAccount in a cluster imported
+	// from a kubeconfig and periodically replace the credentials of the kubeconfig with its tokens
+	RotateKubeconfig bool `json:"rotateKubeconfig,omitempty"`
 }
 
 func GetKubeconfigEndpoint(userInfoGetter provider.UserInfoGetter, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, clusterProvider provider.ExternalClusterProvider, privilegedClusterProvider provider.PrivilegedExternalClusterProvider, settingsProvider provider.SettingsProvider) endpoint.Endpoint {
@@ -0,0 +1,115 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalcluster
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/provider"
+	kubernetesprovider "k8c.io/kubermatic/v2/pkg/provider/kubernetes"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// finalizerRecordingClient records the finalizers of the external clusters it deletes.
+type finalizerRecordingClient struct {
+	ctrlruntimeclient.Client
+	deletedFinalizers map[string][]string
+}
+
+func (c *finalizerRecordingClient) Delete(ctx context.Context, obj ctrlruntimeclient.Object, opts ...ctrlruntimeclient.DeleteOption) error {
+	c.deletedFinalizers[obj.GetName()] = obj.GetFinalizers()
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func TestCreateNewCloudCluster(t *testing.T) {
+	testCases := []struct {
+		name              string
+		createErr         error
+		expectedErr       bool
+		expectedFinalizer bool
+	}{
+		{
+			name:              "cloud cluster is created",
+			expectedFinalizer: true,
+		},
+		{
+			name:        "cloud cluster with the same name exists already",
+			createErr:   errors.New("ResourceInUseException: Cluster already exists with name: test"),
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			client := &finalizerRecordingClient{
+				Client:            fakectrlruntimeclient.NewClientBuilder().Build(),
+				deletedFinalizers: map[string][]string{},
+			}
+			clusterProvider, err := kubernetesprovider.NewExternalClusterProvider(nil, client)
+			if err != nil {
+				t.Fatalf("failed to create external cluster provider: %v", err)
+			}
+			userInfoGetter := func(ctx context.Context, projectID string) (*provider.UserInfo, error) {
+				return &provider.UserInfo{Email: "admin@example.com", IsAdmin: true}, nil
+			}
+			project := &kubermaticv1.Project{ObjectMeta: metav1.ObjectMeta{Name: "project"}}
+			cluster := &kubermaticv1.ExternalCluster{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+
+			_, err = createNewCloudCluster(ctx, userInfoGetter, clusterProvider, clusterProvider, cluster, project, "EKS", func() error {
+				return tc.createErr
+			})
+			if (err != nil) != tc.expectedErr {
+				t.Fatalf("expected error: %t, got: %v", tc.expectedErr, err)
+			}
+
+			existing := &kubermaticv1.ExternalCluster{}
+			err = client.Get(ctx, types.NamespacedName{Name: "test"}, existing)
+			if tc.expectedErr {
+				if !kerrors.IsNotFound(err) {
+					t.Fatalf("expected the external cluster to be deleted, got: %v", err)
+				}
+				finalizers, deleted := client.deletedFinalizers["test"]
+				if !deleted {
+					t.Fatal("expected the external cluster to be deleted")
+				}
+				for _, finalizer := range finalizers {
+					if finalizer == apiv1.ExternalClusterCloudCleanupFinalizer {
+						t.Fatal("expected the external cluster to be deleted without deleting the existing cloud cluster")
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to get external cluster: %v", err)
+			}
+			if kuberneteshelper.HasFinalizer(existing, apiv1.ExternalClusterCloudCleanupFinalizer) != tc.expectedFinalizer {
+				t.Fatalf("expected cloud cleanup finalizer: %t, got finalizers %v", tc.expectedFinalizer, existing.Finalizers)
+			}
+		})
+	}
+}
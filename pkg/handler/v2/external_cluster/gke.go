@@ -253,6 +253,7 @@ func createGKECluster(ctx context.Context, name string, userInfoGetter provider.
 			Zone: cloud.GKE.Zone,
 		},
 	}
+
 	keyRef, err := clusterProvider.CreateOrUpdateCredentialSecretForCluster(ctx, cloud, project.Name, newCluster.Name)
 	if err != nil {
 		return nil, common.KubernetesErrorToHTTPError(err)
@@ -260,9 +261,41 @@ func createGKECluster(ctx context.Context, name string, userInfoGetter provider.
 	kuberneteshelper.AddFinalizer(newCluster, apiv1.CredentialsSecretsCleanupFinalizer)
 	newCluster.Spec.CloudSpec.GKE.CredentialsReference = keyRef
 
+	if cloud.GKE.ClusterSpec != nil {
+		// the cluster is owned by Kubermatic and is removed from GKE together with the external cluster
+		return createNewCloudCluster(ctx, userInfoGetter, clusterProvider, privilegedClusterProvider, newCluster, project, "GKE", func() error {
+			return createNewGKECluster(ctx, cloud.GKE)
+		})
+	}
+
 	return createNewCluster(ctx, userInfoGetter, clusterProvider, privilegedClusterProvider, newCluster, project)
 }
 
+func createNewGKECluster(ctx context.Context, gkeCloudSpec *apiv2.GKECloudSpec) error {
+	svc, project, err := gcp.ConnectToContainerService(gkeCloudSpec.ServiceAccount)
+	if err != nil {
+		return err
+	}
+
+	clusterSpec := gkeCloudSpec.ClusterSpec
+	cluster := &container.Cluster{
+		Name:                  gkeCloudSpec.Name,
+		InitialClusterVersion: clusterSpec.InitialClusterVersion,
+		InitialNodeCount:      clusterSpec.InitialNodeCount,
+		Network:               clusterSpec.Network,
+		Subnetwork:            clusterSpec.Subnetwork,
+	}
+	if clusterSpec.MachineType != "" {
+		cluster.NodeConfig = &container.NodeConfig{
+			MachineType: clusterSpec.MachineType,
+		}
+	}
+
+	req := svc.Projects.Zones.Clusters.Create(project, gkeCloudSpec.Zone, &container.CreateClusterRequest{Cluster: cluster})
+	_, err = req.Context(ctx).Do()
+	return err
+}
+
 func patchGKECluster(ctx context.Context, old, new *apiv2.ExternalCluster, secretKeySelector provider.SecretKeySelectorValueFunc, credentialsReference *providerconfig.GlobalSecretKeySelector) (*apiv2.ExternalCluster, error) {
 	sa, err := secretKeySelector(credentialsReference, resources.GCPServiceAccount)
 	if err != nil {
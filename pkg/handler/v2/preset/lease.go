@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preset
+
+import (
+	"context"
+	"crypto/x509"
+	"time"
+
+	"go.uber.org/zap"
+
+	v2 "k8c.io/kubermatic/v2/pkg/api/v2"
+	crdapiv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/provider"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// LeasedCredentialsRefresher copies the credentials of presets which are leased by an external secret
+// store to the clusters using them whenever the store hands out new ones, so that the credentials of
+// the clusters do not run out with their lease. The secret store has to be cached for less than the
+// lease, so that new credentials are read while the old ones are still valid.
+type LeasedCredentialsRefresher struct {
+	presetProvider provider.PresetProvider
+	rotate         presetClustersRotator
+	log            *zap.SugaredLogger
+
+	// refreshed are the resolved presets whose credentials were copied to all of their clusters
+	refreshed map[string]crdapiv1.PresetSpec
+}
+
+// presetClustersRotator copies the current credentials of the preset to all clusters that were created with it.
+type presetClustersRotator = func(ctx context.Context, userInfo *provider.UserInfo, presetName string) (*v2.PresetCredentialsRotation, error)
+
+func NewLeasedCredentialsRefresher(presetProvider provider.PresetProvider, seedsGetter provider.SeedsGetter, clusterProviderGetter provider.ClusterProviderGetter, caBundle *x509.CertPool, log *zap.SugaredLogger) *LeasedCredentialsRefresher {
+	return &LeasedCredentialsRefresher{
+		presetProvider: presetProvider,
+		rotate: func(ctx context.Context, userInfo *provider.UserInfo, presetName string) (*v2.PresetCredentialsRotation, error) {
+			return rotatePresetClusters(ctx, presetProvider, seedsGetter, clusterProviderGetter, userInfo, presetName, caBundle)
+		},
+		log:       log,
+		refreshed: map[string]crdapiv1.PresetSpec{},
+	}
+}
+
+// Start checks the leased credentials every interval until the context is done.
+func (r *LeasedCredentialsRefresher) Start(ctx context.Context, interval time.Duration) {
+	wait.UntilWithContext(ctx, r.refresh, interval)
+}
+
+func (r *LeasedCredentialsRefresher) refresh(ctx context.Context) {
+	userInfo := &provider.UserInfo{IsAdmin: true}
+
+	presets, err := r.presetProvider.GetPresets(userInfo)
+	if err != nil {
+		r.log.Errorw("failed to list presets", zap.Error(err))
+		return
+	}
+
+	leased := map[string]crdapiv1.PresetSpec{}
+	for i := range presets {
+		preset := &presets[i]
+		log := r.log.With("preset", preset.Name)
+
+		lease, err := r.presetProvider.GetCredentialsLease(ctx, preset)
+		if err != nil {
+			log.Errorw("failed to get the lease of the credentials", zap.Error(err))
+			continue
+		}
+		if lease == 0 {
+			continue
+		}
+
+		// the preset is resolved before the clusters are rotated, so that credentials handed out
+		// in between are copied again next time
+		resolved, err := r.presetProvider.GetPreset(ctx, userInfo, preset.Name)
+		if err != nil {
+			log.Errorw("failed to resolve the credentials", zap.Error(err))
+			continue
+		}
+		if refreshed, ok := r.refreshed[preset.Name]; ok && equality.Semantic.DeepEqual(refreshed, resolved.Spec) {
+			leased[preset.Name] = refreshed
+			continue
+		}
+
+		result, err := r.rotate(ctx, userInfo, preset.Name)
+		if err != nil {
+			log.Errorw("failed to copy the renewed credentials to the clusters", zap.Error(err))
+			continue
+		}
+		for cluster, failure := range result.FailedClusters {
+			log.Errorw("failed to copy the renewed credentials to the cluster", "cluster", cluster, "error", failure)
+		}
+		// clusters that failed are retried next time
+		if len(result.FailedClusters) == 0 {
+			leased[preset.Name] = resolved.Spec
+		}
+		log.Debugw("copied the renewed credentials to the clusters", "clusters", result.RotatedClusters)
+	}
+
+	r.refreshed = leased
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preset
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	v2 "k8c.io/kubermatic/v2/pkg/api/v2"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+	"k8c.io/kubermatic/v2/pkg/provider"
+	"k8c.io/kubermatic/v2/pkg/provider/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/secretstore"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type fakeSecretStore struct {
+	secrets map[string]*secretstore.Secret
+}
+
+func (s *fakeSecretStore) Read(_ context.Context, path string) (*secretstore.Secret, error) {
+	secret, ok := s.secrets[path]
+	if !ok {
+		return nil, secretstore.ErrNotFound
+	}
+	return secret, nil
+}
+
+func genExternalPreset(name string) *kubermaticv1.Preset {
+	return &kubermaticv1.Preset{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: kubermaticv1.PresetSpec{
+			Hetzner: &kubermaticv1.Hetzner{},
+			ExternalSecrets: &kubermaticv1.PresetExternalSecrets{
+				Path: name,
+			},
+		},
+	}
+}
+
+func TestLeasedCredentialsRefresher(t *testing.T) {
+	ctx := context.Background()
+
+	store := &fakeSecretStore{secrets: map[string]*secretstore.Secret{
+		"leased": {Data: map[string]string{"hetzner.token": "first"}, LeaseDuration: time.Hour},
+		"static": {Data: map[string]string{"hetzner.token": "static"}},
+	}}
+	client := fakectrlruntimeclient.
+		NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(
+			genExternalPreset("leased"),
+			genExternalPreset("static"),
+			&kubermaticv1.Preset{
+				ObjectMeta: metav1.ObjectMeta{Name: "stored"},
+				Spec: kubermaticv1.PresetSpec{
+					Hetzner:        &kubermaticv1.Hetzner{Token: "stored"},
+					RequiredEmails: []string{"example.com"},
+				},
+			},
+		).
+		Build()
+
+	presetProvider, err := kubernetes.NewPresetProvider(ctx, client, "", true, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var rotated []string
+	var failedClusters map[string]string
+	refresher := NewLeasedCredentialsRefresher(presetProvider, nil, nil, nil, kubermaticlog.Logger)
+	refresher.rotate = func(ctx context.Context, userInfo *provider.UserInfo, presetName string) (*v2.PresetCredentialsRotation, error) {
+		preset, err := presetProvider.GetPreset(ctx, userInfo, presetName)
+		if err != nil {
+			return nil, err
+		}
+		rotated = append(rotated, fmt.Sprintf("%s:%s", presetName, preset.Spec.Hetzner.Token))
+		return &v2.PresetCredentialsRotation{FailedClusters: failedClusters}, nil
+	}
+
+	steps := []struct {
+		name            string
+		update          func()
+		expectedRotated []string
+	}{
+		{
+			name:            "leased credentials are copied to the clusters",
+			expectedRotated: []string{"leased:first"},
+		},
+		{
+			name: "unchanged credentials are not copied again",
+		},
+		{
+			name: "renewed credentials are copied to the clusters",
+			update: func() {
+				store.secrets["leased"] = &secretstore.Secret{Data: map[string]string{"hetzner.token": "second"}, LeaseDuration: time.Hour}
+			},
+			expectedRotated: []string{"leased:second"},
+		},
+		{
+			name: "credentials are copied again if a cluster failed",
+			update: func() {
+				store.secrets["leased"] = &secretstore.Secret{Data: map[string]string{"hetzner.token": "third"}, LeaseDuration: time.Hour}
+				failedClusters = map[string]string{"cluster": "failed"}
+			},
+			expectedRotated: []string{"leased:third"},
+		},
+		{
+			name: "credentials are copied until all clusters succeeded",
+			update: func() {
+				failedClusters = nil
+			},
+			expectedRotated: []string{"leased:third"},
+		},
+		{
+			name: "credentials are not copied anymore once all clusters succeeded",
+		},
+	}
+
+	for _, step := range steps {
+		if step.update != nil {
+			step.update()
+		}
+		rotated = nil
+
+		refresher.refresh(ctx)
+
+		if fmt.Sprint(rotated) != fmt.Sprint(step.expectedRotated) {
+			t.Fatalf("%s: expected %v to be rotated, got %v", step.name, step.expectedRotated, rotated)
+		}
+	}
+}
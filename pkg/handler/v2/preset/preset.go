@@ -18,9 +18,11 @@ package preset
 
 import (
 	"context"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/go-kit/kit/endpoint"
@@ -29,11 +31,14 @@ import (
 	v2 "k8c.io/kubermatic/v2/pkg/api/v2"
 	crdapiv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1/helper"
+	handlercommon "k8c.io/kubermatic/v2/pkg/handler/common"
 	"k8c.io/kubermatic/v2/pkg/handler/v1/common"
 	"k8c.io/kubermatic/v2/pkg/provider"
 	"k8c.io/kubermatic/v2/pkg/util/errors"
 
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // listPresetsReq represents a request for a list of presets
@@ -41,16 +46,39 @@ import (
 type listPresetsReq struct {
 	// in: query
 	Disabled bool `json:"disabled,omitempty"`
+	// ProjectID limits the list to the presets that are available in the project
+	// in: query
+	ProjectID string `json:"project_id,omitempty"`
 }
 
 func DecodeListPresets(_ context.Context, r *http.Request) (interface{}, error) {
 	return listPresetsReq{
-		Disabled: r.URL.Query().Get("disabled") == "true",
+		Disabled:  r.URL.Query().Get("disabled") == "true",
+		ProjectID: r.URL.Query().Get("project_id"),
+	}, nil
+}
+
+// projectFilter returns a function that reports whether a preset is available in the project requested by
+// the user. If no project was requested, all presets are accepted.
+func (l listPresetsReq) projectFilter(ctx context.Context, userInfoGetter provider.UserInfoGetter, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider) (func(*crdapiv1.Preset) bool, error) {
+	if len(l.ProjectID) == 0 {
+		return func(*crdapiv1.Preset) bool { return true }, nil
+	}
+
+	project, err := common.GetProject(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, l.ProjectID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(preset *crdapiv1.Preset) bool {
+		// presets with an invalid project selector cannot be used in any project
+		available, err := helper.IsAvailableForProject(preset, project)
+		return err == nil && available
 	}, nil
 }
 
 // ListProviderPresets returns a list of preset names for the provider
-func ListPresets(presetProvider provider.PresetProvider, userInfoGetter provider.UserInfoGetter) endpoint.Endpoint {
+func ListPresets(presetProvider provider.PresetProvider, userInfoGetter provider.UserInfoGetter, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req, ok := request.(listPresetsReq)
 		if !ok {
@@ -62,6 +90,11 @@ func ListPresets(presetProvider provider.PresetProvider, userInfoGetter provider
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
 
+		availableInProject, err := req.projectFilter(ctx, userInfoGetter, projectProvider, privilegedProjectProvider)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
 		presetList := &v2.PresetList{Items: make([]v2.Preset, 0)}
 		presets, err := presetProvider.GetPresets(userInfo)
 		if err != nil {
@@ -75,6 +108,10 @@ func ListPresets(presetProvider provider.PresetProvider, userInfoGetter provider
 				continue
 			}
 
+			if !availableInProject(&preset) {
+				continue
+			}
+
 			presetList.Items = append(presetList.Items, newAPIPreset(&preset, enabled))
 		}
 
@@ -82,6 +119,59 @@ func ListPresets(presetProvider provider.PresetProvider, userInfoGetter provider
 	}
 }
 
+// getPresetStatusReq represents a request to get the status of a preset
+// swagger:parameters getPresetStatus
+type getPresetStatusReq struct {
+	// in: path
+	// required: true
+	PresetName string `json:"preset_name"`
+}
+
+func DecodeGetPresetStatus(_ context.Context, r *http.Request) (interface{}, error) {
+	return getPresetStatusReq{
+		PresetName: mux.Vars(r)["preset_name"],
+	}, nil
+}
+
+// GetPresetStatus returns whether the preset is enabled and the clusters that use its credentials. The clusters
+// are taken from the usage index maintained by the preset usage controller.
+func GetPresetStatus(presetProvider provider.PresetProvider, userInfoGetter provider.UserInfoGetter) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(getPresetStatusReq)
+		if !ok {
+			return nil, errors.NewBadRequest("invalid request")
+		}
+
+		userInfo, err := userInfoGetter(ctx, "")
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		if !userInfo.IsAdmin {
+			return nil, errors.New(http.StatusForbidden, "only admins can get the status of presets")
+		}
+
+		preset, err := presetProvider.GetStoredPreset(userInfo, req.PresetName)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		status := &v2.PresetStatus{
+			Enabled:  preset.Spec.IsEnabled(),
+			Clusters: make([]v2.PresetCluster, 0, len(preset.Status.Clusters)),
+		}
+		for _, cluster := range preset.Status.Clusters {
+			status.Clusters = append(status.Clusters, v2.PresetCluster{
+				ID:        cluster.Name,
+				ProjectID: cluster.ProjectID,
+				Seed:      cluster.Seed,
+			})
+		}
+
+		return status, nil
+	}
+}
+
 // updatePresetStatusReq represents a request to update preset status
 // swagger:parameters updatePresetStatus
 type updatePresetStatusReq struct {
@@ -144,7 +234,7 @@ func UpdatePresetStatus(presetProvider provider.PresetProvider, userInfoGetter p
 			return nil, errors.New(http.StatusForbidden, "only admins can update presets")
 		}
 
-		preset, err := presetProvider.GetPreset(userInfo, req.PresetName)
+		preset, err := presetProvider.GetStoredPreset(userInfo, req.PresetName)
 		if err != nil {
 			return nil, errors.New(http.StatusInternalServerError, err.Error())
 		}
@@ -208,7 +298,7 @@ func DecodeListProviderPresets(ctx context.Context, r *http.Request) (interface{
 }
 
 // ListProviderPresets returns a list of preset names for the provider
-func ListProviderPresets(presetProvider provider.PresetProvider, userInfoGetter provider.UserInfoGetter) endpoint.Endpoint {
+func ListProviderPresets(presetProvider provider.PresetProvider, userInfoGetter provider.UserInfoGetter, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req, ok := request.(listProviderPresetsReq)
 		if !ok {
@@ -224,6 +314,11 @@ func ListProviderPresets(presetProvider provider.PresetProvider, userInfoGetter
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
 
+		availableInProject, err := req.projectFilter(ctx, userInfoGetter, projectProvider, privilegedProjectProvider)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
 		presetList := &v2.PresetList{Items: make([]v2.Preset, 0)}
 		presets, err := presetProvider.GetPresets(userInfo)
 		if err != nil {
@@ -231,6 +326,10 @@ func ListProviderPresets(presetProvider provider.PresetProvider, userInfoGetter
 		}
 
 		for _, preset := range presets {
+			if !availableInProject(&preset) {
+				continue
+			}
+
 			providerType := crdapiv1.ProviderType(req.ProviderName)
 			providerPreset := helper.GetProviderPreset(&preset, providerType)
 
@@ -291,6 +390,12 @@ func (r createPresetReq) Validate() error {
 		return err
 	}
 
+	if r.Body.Spec.ProjectSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(r.Body.Spec.ProjectSelector); err != nil {
+			return fmt.Errorf("invalid project selector: %v", err)
+		}
+	}
+
 	for _, providerType := range crdapiv1.SupportedProviders {
 		if string(providerType) == r.ProviderName {
 			continue
@@ -337,7 +442,7 @@ func CreatePreset(presetProvider provider.PresetProvider, userInfoGetter provide
 			return "", errors.New(http.StatusForbidden, "only admins can update presets")
 		}
 
-		preset, err := presetProvider.GetPreset(userInfo, req.Body.Name)
+		preset, err := presetProvider.GetStoredPreset(userInfo, req.Body.Name)
 		if k8serrors.IsNotFound(err) {
 			return presetProvider.CreatePreset(&req.Body)
 		}
@@ -406,7 +511,7 @@ func UpdatePreset(presetProvider provider.PresetProvider, userInfoGetter provide
 			return "", errors.New(http.StatusForbidden, "only admins can update presets")
 		}
 
-		preset, err := presetProvider.GetPreset(userInfo, req.Body.Name)
+		preset, err := presetProvider.GetStoredPreset(userInfo, req.Body.Name)
 		if err != nil {
 			return nil, err
 		}
@@ -481,7 +586,7 @@ func DeletePreset(presetProvider provider.PresetProvider, userInfoGetter provide
 			return "", errors.New(http.StatusForbidden, "only admins can delete presets")
 		}
 
-		preset, err := presetProvider.GetPreset(userInfo, req.PresetName)
+		preset, err := presetProvider.GetStoredPreset(userInfo, req.PresetName)
 		if k8serrors.IsNotFound(err) {
 			return nil, errors.NewBadRequest("preset was not found.")
 		}
@@ -504,10 +609,125 @@ func DeletePreset(presetProvider provider.PresetProvider, userInfoGetter provide
 	}
 }
 
+// rotatePresetCredentialsReq represents a request to rotate the credentials of all clusters using a preset
+// swagger:parameters rotatePresetCredentials
+type rotatePresetCredentialsReq struct {
+	// in: path
+	// required: true
+	PresetName string `json:"preset_name"`
+}
+
+func DecodeRotatePresetCredentials(_ context.Context, r *http.Request) (interface{}, error) {
+	return rotatePresetCredentialsReq{
+		PresetName: mux.Vars(r)["preset_name"],
+	}, nil
+}
+
+// Validate validates rotatePresetCredentialsReq request
+func (r rotatePresetCredentialsReq) Validate() error {
+	if len(r.PresetName) == 0 {
+		return fmt.Errorf("the preset name cannot be empty")
+	}
+	return nil
+}
+
+// RotatePresetCredentials copies the current credentials of the preset to all clusters that were created with it.
+// Clusters whose credentials cannot be rotated are reported in the result and do not abort the rotation of the others.
+func RotatePresetCredentials(presetProvider provider.PresetProvider, seedsGetter provider.SeedsGetter, clusterProviderGetter provider.ClusterProviderGetter, userInfoGetter provider.UserInfoGetter, caBundle *x509.CertPool) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(rotatePresetCredentialsReq)
+		if !ok {
+			return nil, errors.NewBadRequest("invalid request")
+		}
+
+		err := req.Validate()
+		if err != nil {
+			return nil, errors.NewBadRequest(err.Error())
+		}
+
+		userInfo, err := userInfoGetter(ctx, "")
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		if !userInfo.IsAdmin {
+			return nil, errors.New(http.StatusForbidden, "only admins can rotate preset credentials")
+		}
+
+		if _, err := presetProvider.GetStoredPreset(userInfo, req.PresetName); err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		return rotatePresetClusters(ctx, presetProvider, seedsGetter, clusterProviderGetter, userInfo, req.PresetName, caBundle)
+	}
+}
+
+// rotatePresetClusters copies the current credentials of the preset to all clusters that were created with it.
+func rotatePresetClusters(ctx context.Context, presetProvider provider.PresetProvider, seedsGetter provider.SeedsGetter, clusterProviderGetter provider.ClusterProviderGetter, userInfo *provider.UserInfo, presetName string, caBundle *x509.CertPool) (*v2.PresetCredentialsRotation, error) {
+	seeds, err := seedsGetter()
+	if err != nil {
+		return nil, errors.New(http.StatusInternalServerError, fmt.Sprintf("failed to list seeds: %v", err))
+	}
+
+	result := &v2.PresetCredentialsRotation{RotatedClusters: []string{}}
+	for seedName, seed := range seeds {
+		clusterProvider, err := clusterProviderGetter(seed)
+		if err != nil {
+			return nil, errors.NewNotFound("cluster-provider", seedName)
+		}
+		clusters, err := clusterProvider.ListAll()
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		seedClient := clusterProvider.(provider.PrivilegedClusterProvider).GetSeedClusterAdminRuntimeClient()
+
+		for i := range clusters.Items {
+			cluster := &clusters.Items[i]
+			if cluster.Annotations[crdapiv1.PresetNameAnnotation] != presetName {
+				continue
+			}
+
+			if err := rotateClusterCredentials(ctx, seedClient, seed, cluster, presetProvider, userInfo, presetName, caBundle); err != nil {
+				if result.FailedClusters == nil {
+					result.FailedClusters = map[string]string{}
+				}
+				result.FailedClusters[cluster.Name] = err.Error()
+				continue
+			}
+			result.RotatedClusters = append(result.RotatedClusters, cluster.Name)
+		}
+	}
+	sort.Strings(result.RotatedClusters)
+
+	return result, nil
+}
+
+func rotateClusterCredentials(ctx context.Context, seedClient ctrlruntimeclient.Client, seed *crdapiv1.Seed, cluster *crdapiv1.Cluster, presetProvider provider.PresetProvider, userInfo *provider.UserInfo, presetName string, caBundle *x509.CertPool) error {
+	dc, ok := seed.Spec.Datacenters[cluster.Spec.Cloud.DatacenterName]
+	if !ok {
+		return fmt.Errorf("datacenter %q not found", cluster.Spec.Cloud.DatacenterName)
+	}
+
+	cloudSpec, err := presetProvider.SetCloudCredentials(ctx, userInfo, presetName, *cluster.Spec.Cloud.DeepCopy(), &dc)
+	if err != nil {
+		return err
+	}
+
+	rotatedCluster, err := handlercommon.RotateCredentials(ctx, seedClient, cluster, *cloudSpec, &dc, caBundle)
+	if err != nil {
+		return err
+	}
+
+	return seedClient.Patch(ctx, rotatedCluster, ctrlruntimeclient.MergeFrom(cluster))
+}
+
 func mergePresets(oldPreset *crdapiv1.Preset, newPreset *crdapiv1.Preset, providerType crdapiv1.ProviderType) *crdapiv1.Preset {
 	oldPreset = helper.OverrideProvider(oldPreset, providerType, newPreset)
 	oldPreset.Spec.RequiredEmails = newPreset.Spec.RequiredEmails
 	oldPreset.Spec.RequiredEmailDomain = newPreset.Spec.RequiredEmailDomain
+	oldPreset.Spec.ExternalSecrets = newPreset.Spec.ExternalSecrets
+	oldPreset.Spec.Projects = newPreset.Spec.Projects
+	oldPreset.Spec.ProjectSelector = newPreset.Spec.ProjectSelector
 	return oldPreset
 }
 
@@ -26,6 +26,7 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-test/deep"
 	"github.com/stretchr/testify/assert"
@@ -231,6 +232,7 @@ func TestListProviderPresets(t *testing.T) {
 		Disabled               bool
 		Provider               string
 		Datacenter             string
+		ProjectID              string
 		ExpectedResponse       *v2.PresetList
 		HTTPStatus             int
 		ExistingAPIUser        *apiv1.User
@@ -333,11 +335,48 @@ func TestListProviderPresets(t *testing.T) {
 			ExistingAPIUser:        test.GenDefaultAPIUser(),
 			ExistingKubermaticObjs: genPresets(),
 		},
+
+		// scenario 7
+		{
+			Name:      "scenario 7: list enabled digitalocean presets available in a project",
+			Disabled:  false,
+			Provider:  string(kubermaticv1.DigitaloceanCloudProvider),
+			ProjectID: test.GenDefaultProject().Name,
+			ExpectedResponse: &v2.PresetList{
+				Items: []v2.Preset{
+					{Name: "enabled-do", Enabled: true, Providers: []v2.PresetProvider{{Name: kubermaticv1.DigitaloceanCloudProvider, Enabled: true}}},
+					{Name: "enabled-do-with-dc", Enabled: true, Providers: []v2.PresetProvider{{Name: kubermaticv1.DigitaloceanCloudProvider, Enabled: true}}},
+					{Name: "enabled-do-with-acme-email", Enabled: true, Providers: []v2.PresetProvider{{Name: kubermaticv1.DigitaloceanCloudProvider, Enabled: true}}},
+					{Name: "enabled-multi-provider", Enabled: true, Providers: []v2.PresetProvider{{Name: kubermaticv1.AnexiaCloudProvider, Enabled: true}, {Name: kubermaticv1.DigitaloceanCloudProvider, Enabled: true}}},
+					{Name: "do-for-project", Enabled: true, Providers: []v2.PresetProvider{{Name: kubermaticv1.DigitaloceanCloudProvider, Enabled: true}}},
+				},
+			},
+			HTTPStatus:      http.StatusOK,
+			ExistingAPIUser: test.GenDefaultAPIUser(),
+			ExistingKubermaticObjs: append(genPresets(),
+				test.GenDefaultProject(),
+				test.GenDefaultOwnerBinding(),
+				&kubermaticv1.Preset{
+					ObjectMeta: v1.ObjectMeta{Name: "do-for-project"},
+					Spec: kubermaticv1.PresetSpec{
+						Digitalocean: &kubermaticv1.Digitalocean{Token: "token"},
+						Projects:     []string{test.GenDefaultProject().Name},
+					},
+				},
+				&kubermaticv1.Preset{
+					ObjectMeta: v1.ObjectMeta{Name: "do-for-other-project"},
+					Spec: kubermaticv1.PresetSpec{
+						Digitalocean: &kubermaticv1.Digitalocean{Token: "token"},
+						Projects:     []string{"other-project"},
+					},
+				},
+			),
+		},
 	}
 
 	for _, tc := range testcases {
 		t.Run(tc.Name, func(t *testing.T) {
-			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v2/providers/%s/presets?disabled=%v&datacenter=%s", tc.Provider, tc.Disabled, tc.Datacenter), strings.NewReader(""))
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v2/providers/%s/presets?disabled=%v&datacenter=%s&project_id=%s", tc.Provider, tc.Disabled, tc.Datacenter, tc.ProjectID), strings.NewReader(""))
 			res := httptest.NewRecorder()
 			// Tests need a default user otherwise, the GenDefaultAPIUser gets admin
 			kubermaticObj := []ctrlruntimeclient.Object{test.GenDefaultUser()}
@@ -1290,3 +1329,165 @@ func TestDeletePreset(t *testing.T) {
 		})
 	}
 }
+
+func TestRotatePresetCredentials(t *testing.T) {
+	t.Parallel()
+
+	genCluster := func(id, presetName string) *kubermaticv1.Cluster {
+		cluster := test.GenCluster(id, id, test.GenDefaultProject().Name, time.Date(2013, 02, 03, 19, 54, 0, 0, time.UTC))
+		cluster.Spec.Cloud.DatacenterName = "fake-dc"
+		if presetName != "" {
+			cluster.Annotations = map[string]string{kubermaticv1.PresetNameAnnotation: presetName}
+		}
+		return cluster
+	}
+
+	testcases := []struct {
+		Name             string
+		PresetName       string
+		HTTPStatus       int
+		ExistingClusters []ctrlruntimeclient.Object
+		ExistingAPIUser  *apiv1.User
+		ExpectedResponse string
+	}{
+		{
+			Name:       "scenario 1: admin rotates the credentials of all clusters using the preset",
+			PresetName: test.TestFakeCredential,
+			HTTPStatus: http.StatusOK,
+			ExistingClusters: []ctrlruntimeclient.Object{
+				genCluster("cluster-b", test.TestFakeCredential),
+				genCluster("cluster-a", test.TestFakeCredential),
+				genCluster("cluster-c", "other"),
+				genCluster("cluster-d", ""),
+			},
+			ExistingAPIUser:  test.GenDefaultAdminAPIUser(),
+			ExpectedResponse: `{"rotatedClusters":["cluster-a","cluster-b"]}`,
+		},
+		{
+			Name:       "scenario 2: clusters that cannot be rotated are reported",
+			PresetName: test.TestFakeCredential,
+			HTTPStatus: http.StatusOK,
+			ExistingClusters: []ctrlruntimeclient.Object{
+				genCluster("cluster-a", test.TestFakeCredential),
+				func() *kubermaticv1.Cluster {
+					cluster := genCluster("cluster-b", test.TestFakeCredential)
+					cluster.Spec.Cloud.DatacenterName = "missing-dc"
+					return cluster
+				}(),
+			},
+			ExistingAPIUser:  test.GenDefaultAdminAPIUser(),
+			ExpectedResponse: `{"rotatedClusters":["cluster-a"],"failedClusters":{"cluster-b":"datacenter \"missing-dc\" not found"}}`,
+		},
+		{
+			Name:             "scenario 3: unknown presets cannot be rotated",
+			PresetName:       "missing",
+			HTTPStatus:       http.StatusNotFound,
+			ExistingAPIUser:  test.GenDefaultAdminAPIUser(),
+			ExpectedResponse: `{"error":{"code":404,"message":"preset.kubermatic.k8s.io \"missing\" not found"}}`,
+		},
+		{
+			Name:             "scenario 4: regular users cannot rotate preset credentials",
+			PresetName:       test.TestFakeCredential,
+			HTTPStatus:       http.StatusForbidden,
+			ExistingAPIUser:  test.GenDefaultAPIUser(),
+			ExpectedResponse: `{"error":{"code":403,"message":"only admins can rotate preset credentials"}}`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v2/presets/%s/rotate", tc.PresetName), nil)
+			res := httptest.NewRecorder()
+
+			existingKubermaticObjs := append([]ctrlruntimeclient.Object{
+				test.APIUserToKubermaticUser(*tc.ExistingAPIUser),
+				test.GenDefaultPreset(),
+				test.GenTestSeed(),
+			}, tc.ExistingClusters...)
+
+			ep, err := test.CreateTestEndpoint(*tc.ExistingAPIUser, []ctrlruntimeclient.Object{}, existingKubermaticObjs, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+
+			ep.ServeHTTP(res, req)
+			assert.Equal(t, tc.HTTPStatus, res.Code)
+			test.CompareWithResult(t, res, tc.ExpectedResponse)
+		})
+	}
+}
+
+func TestGetPresetStatus(t *testing.T) {
+	t.Parallel()
+
+	genPresetWithUsage := func() *kubermaticv1.Preset {
+		preset := test.GenDefaultPreset()
+		preset.Status.Clusters = []kubermaticv1.PresetClusterReference{
+			{Name: "cluster-a", ProjectID: test.GenDefaultProject().Name, Seed: "us-central1"},
+		}
+		return preset
+	}
+
+	testcases := []struct {
+		Name             string
+		PresetName       string
+		ExistingPreset   *kubermaticv1.Preset
+		ExistingAPIUser  *apiv1.User
+		HTTPStatus       int
+		ExpectedResponse string
+	}{
+		{
+			Name:             "scenario 1: admin gets the clusters using the preset",
+			PresetName:       test.TestFakeCredential,
+			ExistingPreset:   genPresetWithUsage(),
+			ExistingAPIUser:  test.GenDefaultAdminAPIUser(),
+			HTTPStatus:       http.StatusOK,
+			ExpectedResponse: `{"enabled":true,"clusters":[{"id":"cluster-a","projectID":"my-first-project-ID","seed":"us-central1"}]}`,
+		},
+		{
+			Name:             "scenario 2: unused presets have no clusters",
+			PresetName:       test.TestFakeCredential,
+			ExistingPreset:   test.GenDefaultPreset(),
+			ExistingAPIUser:  test.GenDefaultAdminAPIUser(),
+			HTTPStatus:       http.StatusOK,
+			ExpectedResponse: `{"enabled":true,"clusters":[]}`,
+		},
+		{
+			Name:             "scenario 3: unknown presets are not found",
+			PresetName:       "missing",
+			ExistingPreset:   test.GenDefaultPreset(),
+			ExistingAPIUser:  test.GenDefaultAdminAPIUser(),
+			HTTPStatus:       http.StatusNotFound,
+			ExpectedResponse: `{"error":{"code":404,"message":"preset.kubermatic.k8s.io \"missing\" not found"}}`,
+		},
+		{
+			Name:             "scenario 4: regular users cannot get the status of presets",
+			PresetName:       test.TestFakeCredential,
+			ExistingPreset:   genPresetWithUsage(),
+			ExistingAPIUser:  test.GenDefaultAPIUser(),
+			HTTPStatus:       http.StatusForbidden,
+			ExpectedResponse: `{"error":{"code":403,"message":"only admins can get the status of presets"}}`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v2/presets/%s/status", tc.PresetName), nil)
+			res := httptest.NewRecorder()
+
+			existingKubermaticObjs := []ctrlruntimeclient.Object{
+				test.APIUserToKubermaticUser(*tc.ExistingAPIUser),
+				tc.ExistingPreset,
+			}
+
+			ep, err := test.CreateTestEndpoint(*tc.ExistingAPIUser, []ctrlruntimeclient.Object{}, existingKubermaticObjs, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+
+			ep.ServeHTTP(res, req)
+			assert.Equal(t, tc.HTTPStatus, res.Code)
+			test.CompareWithResult(t, res, tc.ExpectedResponse)
+		})
+	}
+}
@@ -143,9 +143,9 @@ func DecodeEKSTypesReq(c context.Context, r *http.Request) (interface{}, error)
 	return req, nil
 }
 
-func getAWSPresetCredentials(userInfo *provider.UserInfo, presetName string, presetProvider provider.PresetProvider) (providercommon.AWSCredential, error) {
+func getAWSPresetCredentials(ctx context.Context, userInfo *provider.UserInfo, presetName string, presetProvider provider.PresetProvider) (providercommon.AWSCredential, error) {
 
-	preset, err := presetProvider.GetPreset(userInfo, presetName)
+	preset, err := presetProvider.GetPreset(ctx, userInfo, presetName)
 	if err != nil {
 		return providercommon.AWSCredential{}, fmt.Errorf("can not get preset %s for the user %s", presetName, userInfo.Email)
 	}
@@ -194,7 +194,7 @@ func ListEKSClustersEndpoint(userInfoGetter provider.UserInfoGetter, presetProvi
 
 		// Preset is used
 		if len(presetName) > 0 {
-			credential, err = getAWSPresetCredentials(userInfo, presetName, presetProvider)
+			credential, err = getAWSPresetCredentials(ctx, userInfo, presetName, presetProvider)
 			if err != nil {
 				return nil, fmt.Errorf("error getting preset credentials for AWS: %v", err)
 			}
@@ -266,7 +266,7 @@ func ListAWSRegionsEndpoint(userInfoGetter provider.UserInfoGetter, presetProvid
 
 		// Preset is used
 		if len(presetName) > 0 {
-			credential, err = getAWSPresetCredentials(userInfo, presetName, presetProvider)
+			credential, err = getAWSPresetCredentials(ctx, userInfo, presetName, presetProvider)
 			if err != nil {
 				return nil, fmt.Errorf("error getting preset credentials for AWS: %v", err)
 			}
@@ -121,7 +121,7 @@ func getAzureCredentialsFromReq(ctx context.Context, req azureCommonReq, userInf
 	}
 
 	if len(req.Credential) > 0 {
-		preset, err := presetProvider.GetPreset(userInfo, req.Credential)
+		preset, err := presetProvider.GetPreset(ctx, userInfo, req.Credential)
 		if err != nil {
 			return nil, errors.New(http.StatusInternalServerError, fmt.Sprintf("can not get preset %s for user %s", req.Credential, userInfo.Email))
 		}
@@ -418,7 +418,7 @@ func ListAKSClustersEndpoint(userInfoGetter provider.UserInfoGetter, presetsProv
 
 		// Preset is used
 		if len(presetName) > 0 {
-			credential, err = getAzurePresetCredentials(userInfo, presetName, presetsProvider)
+			credential, err = getAzurePresetCredentials(ctx, userInfo, presetName, presetsProvider)
 			if err != nil {
 				return nil, fmt.Errorf("error getting preset credentials for Azure: %v", err)
 			}
@@ -427,9 +427,9 @@ func ListAKSClustersEndpoint(userInfoGetter provider.UserInfoGetter, presetsProv
 	}
 }
 
-func getAzurePresetCredentials(userInfo *provider.UserInfo, presetName string, presetProvider provider.PresetProvider) (azure.Credentials, error) {
+func getAzurePresetCredentials(ctx context.Context, userInfo *provider.UserInfo, presetName string, presetProvider provider.PresetProvider) (azure.Credentials, error) {
 
-	preset, err := presetProvider.GetPreset(userInfo, presetName)
+	preset, err := presetProvider.GetPreset(ctx, userInfo, presetName)
 	if err != nil {
 		return azure.Credentials{}, fmt.Errorf("can not get preset %s for the user %s", presetName, userInfo.Email)
 	}
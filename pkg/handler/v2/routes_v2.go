@@ -32,6 +32,7 @@ import (
 	"k8c.io/kubermatic/v2/pkg/handler/v2/backupcredentials"
 	"k8c.io/kubermatic/v2/pkg/handler/v2/backupdestinations"
 	"k8c.io/kubermatic/v2/pkg/handler/v2/cluster"
+	clustermigration "k8c.io/kubermatic/v2/pkg/handler/v2/cluster_migration"
 	clustertemplate "k8c.io/kubermatic/v2/pkg/handler/v2/cluster_template"
 	"k8c.io/kubermatic/v2/pkg/handler/v2/constraint"
 	constrainttemplate "k8c.io/kubermatic/v2/pkg/handler/v2/constraint_template"
@@ -155,6 +156,10 @@ func (r Routing) RegisterV2(mux *mux.Router, metrics common.ServerMetrics) {
 		Path("/projects/{project_id}/clusters/{cluster_id}/nodes/upgrades").
 		Handler(r.upgradeClusterNodeDeployments())
 
+	mux.Methods(http.MethodPost).
+		Path("/projects/{project_id}/clusters/{cluster_id}/credentials/rotate").
+		Handler(r.rotateClusterCredentials())
+
 	mux.Methods(http.MethodGet).
 		Path("/projects/{project_id}/clusters/{cluster_id}/clusterroles").
 		Handler(r.listClusterRole())
@@ -593,10 +598,18 @@ func (r Routing) RegisterV2(mux *mux.Router, metrics common.ServerMetrics) {
 		Path("/presets").
 		Handler(r.listPresets())
 
+	mux.Methods(http.MethodGet).
+		Path("/presets/{preset_name}/status").
+		Handler(r.getPresetStatus())
+
 	mux.Methods(http.MethodPut).
 		Path("/presets/{preset_name}/status").
 		Handler(r.updatePresetStatus())
 
+	mux.Methods(http.MethodPost).
+		Path("/presets/{preset_name}/rotate").
+		Handler(r.rotatePresetCredentials())
+
 	mux.Methods(http.MethodGet).
 		Path("/providers/{provider_name}/presets").
 		Handler(r.listProviderPresets())
@@ -727,6 +740,10 @@ func (r Routing) RegisterV2(mux *mux.Router, metrics common.ServerMetrics) {
 		Path("/projects/{project_id}/etcdrestores").
 		Handler(r.listProjectEtcdRestore())
 
+	mux.Methods(http.MethodPost).
+		Path("/projects/{project_id}/clusters/{cluster_id}/clone").
+		Handler(r.cloneCluster())
+
 	// Defines a set of HTTP endpoints for managing etcd backup restores
 	mux.Methods(http.MethodPut).
 		Path("/seeds/{seed_name}/backupcredentials").
@@ -797,6 +814,15 @@ func (r Routing) RegisterV2(mux *mux.Router, metrics common.ServerMetrics) {
 	mux.Methods(http.MethodGet).
 		Path("/projects/{project_id}/clusters/{cluster_id}/backupdestinations").
 		Handler(r.getBackupDestinationNames())
+
+	// Defines a set of HTTP endpoints for migrating clusters between seeds
+	mux.Methods(http.MethodPost).
+		Path("/projects/{project_id}/clusters/{cluster_id}/migrate").
+		Handler(r.createClusterMigration())
+
+	mux.Methods(http.MethodGet).
+		Path("/projects/{project_id}/clusters/{cluster_id}/migrate").
+		Handler(r.getClusterMigration())
 }
 
 // swagger:route POST /api/v2/projects/{project_id}/clusters project createClusterV2
@@ -1170,6 +1196,35 @@ func (r Routing) upgradeClusterNodeDeployments() http.Handler {
 	)
 }
 
+// swagger:route POST /api/v2/projects/{project_id}/clusters/{cluster_id}/credentials/rotate project rotateClusterCredentialsV2
+//
+//    Rotates the cloud credentials of a cluster. All control plane components using them are restarted afterwards.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       default: errorResponse
+//       200: empty
+//       401: empty
+//       403: empty
+func (r Routing) rotateClusterCredentials() http.Handler {
+	return httptransport.NewServer(
+		endpoint.Chain(
+			middleware.TokenVerifier(r.tokenVerifiers, r.userProvider),
+			middleware.UserSaver(r.userProvider),
+			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+		)(cluster.RotateCredentialsEndpoint(r.projectProvider, r.privilegedProjectProvider, r.seedsGetter, r.presetProvider, r.userInfoGetter, r.caBundle)),
+		cluster.DecodeRotateCredentialsReq,
+		handler.EncodeJSON,
+		r.defaultServerOptions()...,
+	)
+}
+
 // swagger:route PUT /api/v2/projects/{project_id}/clusters/{cluster_id}/sshkeys/{key_id} project assignSSHKeyToClusterV2
 //
 //     Assigns an existing ssh key to the given cluster
@@ -1288,7 +1343,7 @@ func (r Routing) createExternalCluster() http.Handler {
 // Delete the external cluster
 // swagger:route DELETE /api/v2/projects/{project_id}/kubernetes/clusters/{cluster_id} project deleteExternalCluster
 //
-//     Deletes the specified external cluster
+//     Deletes the specified external cluster. Clusters which were created on GKE, EKS or AKS through the API are deleted from the provider as well.
 //
 //     Produces:
 //     - application/json
@@ -3717,13 +3772,37 @@ func (r Routing) listPresets() http.Handler {
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers, r.userProvider),
 			middleware.UserSaver(r.userProvider),
-		)(preset.ListPresets(r.presetProvider, r.userInfoGetter)),
+		)(preset.ListPresets(r.presetProvider, r.userInfoGetter, r.projectProvider, r.privilegedProjectProvider)),
 		preset.DecodeListPresets,
 		handler.EncodeJSON,
 		r.defaultServerOptions()...,
 	)
 }
 
+// swagger:route GET /api/v2/presets/{preset_name}/status preset getPresetStatus
+//
+//     Gets the status of a preset, including the clusters that use its credentials. Only available to admins.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       default: errorResponse
+//       200: PresetStatus
+//       401: empty
+//       403: empty
+func (r Routing) getPresetStatus() http.Handler {
+	return httptransport.NewServer(
+		endpoint.Chain(
+			middleware.TokenVerifier(r.tokenVerifiers, r.userProvider),
+			middleware.UserSaver(r.userProvider),
+		)(preset.GetPresetStatus(r.presetProvider, r.userInfoGetter)),
+		preset.DecodeGetPresetStatus,
+		handler.EncodeJSON,
+		r.defaultServerOptions()...,
+	)
+}
+
 // swagger:route PUT /api/v2/presets/{preset_name}/status preset updatePresetStatus
 //
 //     Updates the status of a preset. It can enable or disable it, so that it won't be listed by the list endpoints.
@@ -3752,6 +3831,30 @@ func (r Routing) updatePresetStatus() http.Handler {
 	)
 }
 
+// swagger:route POST /api/v2/presets/{preset_name}/rotate preset rotatePresetCredentials
+//
+//     Rotates the cloud credentials of all clusters that were created with the preset. Only available to admins.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       default: errorResponse
+//       200: PresetCredentialsRotation
+//       401: empty
+//       403: empty
+func (r Routing) rotatePresetCredentials() http.Handler {
+	return httptransport.NewServer(
+		endpoint.Chain(
+			middleware.TokenVerifier(r.tokenVerifiers, r.userProvider),
+			middleware.UserSaver(r.userProvider),
+		)(preset.RotatePresetCredentials(r.presetProvider, r.seedsGetter, r.clusterProviderGetter, r.userInfoGetter, r.caBundle)),
+		preset.DecodeRotatePresetCredentials,
+		handler.EncodeJSON,
+		r.defaultServerOptions()...,
+	)
+}
+
 // swagger:route GET /api/v2/providers/{provider_name}/presets preset listProviderPresets
 //
 //     Lists presets for the provider
@@ -3770,7 +3873,7 @@ func (r Routing) listProviderPresets() http.Handler {
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers, r.userProvider),
 			middleware.UserSaver(r.userProvider),
-		)(preset.ListProviderPresets(r.presetProvider, r.userInfoGetter)),
+		)(preset.ListProviderPresets(r.presetProvider, r.userInfoGetter, r.projectProvider, r.privilegedProjectProvider)),
 		preset.DecodeListProviderPresets,
 		handler.EncodeJSON,
 		r.defaultServerOptions()...,
@@ -4742,6 +4845,36 @@ func (r Routing) listProjectEtcdRestore() http.Handler {
 	)
 }
 
+// swagger:route POST /api/v2/projects/{project_id}/clusters/{cluster_id}/clone etcdrestore cloneCluster
+//
+//     Creates a new cluster from an etcd backup of the given cluster. The new cluster gets a new ID and new certificates,
+//     all of its machine deployments are scaled to zero and its persistent volumes are removed once the backup has been restored.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       default: errorResponse
+//       201: Cluster
+//       401: empty
+//       403: empty
+func (r Routing) cloneCluster() http.Handler {
+	return httptransport.NewServer(
+		endpoint.Chain(
+			middleware.TokenVerifier(r.tokenVerifiers, r.userProvider),
+			middleware.UserSaver(r.userProvider),
+			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+		)(etcdrestore.CloneClusterEndpoint(r.userInfoGetter, r.projectProvider, r.privilegedProjectProvider, r.seedsGetter, r.kubermaticConfigGetter)),
+		etcdrestore.DecodeCloneClusterReq,
+		handler.SetStatusCreatedHeader(handler.EncodeJSON),
+		r.defaultServerOptions()...,
+	)
+}
+
 // swagger:route PUT /api/v2/seeds/{seed_name}/backupcredentials backupcredentials createOrUpdateBackupCredentials
 //
 //     Creates or updates backup credentials for a given seed
@@ -5470,3 +5603,58 @@ func (r Routing) getBackupDestinationNames() http.Handler {
 		r.defaultServerOptions()...,
 	)
 }
+
+// swagger:route POST /api/v2/projects/{project_id}/clusters/{cluster_id}/migrate project createClusterMigration
+//
+//     Migrates the control plane of the cluster to another seed. Only available to admins.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       default: errorResponse
+//       201: ClusterMigration
+//       401: empty
+//       403: empty
+func (r Routing) createClusterMigration() http.Handler {
+	return httptransport.NewServer(
+		endpoint.Chain(
+			middleware.TokenVerifier(r.tokenVerifiers, r.userProvider),
+			middleware.UserSaver(r.userProvider),
+			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+		)(clustermigration.CreateEndpoint(r.userInfoGetter, r.projectProvider, r.privilegedProjectProvider, r.seedsGetter, r.privilegedClusterMigrationProvider)),
+		clustermigration.DecodeCreateClusterMigrationReq,
+		handler.SetStatusCreatedHeader(handler.EncodeJSON),
+		r.defaultServerOptions()...,
+	)
+}
+
+// swagger:route GET /api/v2/projects/{project_id}/clusters/{cluster_id}/migrate project getClusterMigration
+//
+//     Gets the most recent migration of the cluster.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       default: errorResponse
+//       200: ClusterMigration
+//       401: empty
+//       403: empty
+func (r Routing) getClusterMigration() http.Handler {
+	return httptransport.NewServer(
+		endpoint.Chain(
+			middleware.TokenVerifier(r.tokenVerifiers, r.userProvider),
+			middleware.UserSaver(r.userProvider),
+			middleware.SetClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+			middleware.SetPrivilegedClusterProvider(r.clusterProviderGetter, r.seedsGetter),
+		)(clustermigration.GetEndpoint(r.userInfoGetter, r.projectProvider, r.privilegedProjectProvider, r.privilegedClusterMigrationProvider)),
+		clustermigration.DecodeGetClusterMigrationReq,
+		handler.EncodeJSON,
+		r.defaultServerOptions()...,
+	)
+}
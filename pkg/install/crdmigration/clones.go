@@ -75,6 +75,7 @@ func cloneResourcesInCluster(ctx context.Context, logger logrus.FieldLogger, cli
 		{Kind: "AdmissionPlugin", cloner: cloneAdmissionPluginResourcesInCluster},
 		{Kind: "Alertmanager", cloner: cloneAlertmanagerResourcesInCluster},
 		{Kind: "AllowedRegistrie", cloner: cloneAllowedRegistryResourcesInCluster},
+		{Kind: "ClusterMigration", cloner: cloneClusterMigrationResourcesInCluster},
 		{Kind: "ClusterTemplate", cloner: cloneClusterTemplateResourcesInCluster},
 		{Kind: "ClusterTemplateInstance", cloner: cloneClusterTemplateInstanceResourcesInCluster},
 		{Kind: "ConstraintTemplate", cloner: cloneConstraintTemplateResourcesInCluster},
@@ -379,6 +380,26 @@ func cloneClusterResourcesInCluster(ctx context.Context, logger logrus.FieldLogg
 			},
 		}
 
+		if old := oldObject.Status.Encryption; old != nil {
+			newObject.Status.Encryption = &newv1.ClusterEncryptionStatus{
+				EncryptedResources: old.EncryptedResources,
+			}
+			for _, key := range old.Keys {
+				newObject.Status.Encryption.Keys = append(newObject.Status.Encryption.Keys, newv1.EncryptionKey{
+					Provider: newv1.EncryptionProvider(key.Provider),
+					Name:     key.Name,
+					KMS:      convertKMSEncryptionConfiguration(key.KMS),
+				})
+			}
+		}
+
+		if old := oldObject.Status.Certificates; old != nil {
+			newObject.Status.Certificates = &newv1.ClusterCertificatesStatus{}
+			for _, certificate := range old.Certificates {
+				newObject.Status.Certificates.Certificates = append(newObject.Status.Certificates.Certificates, newv1.CertificateExpiry(certificate))
+			}
+		}
+
 		if err := client.Status().Update(ctx, &newObject); err != nil {
 			return 0, fmt.Errorf("failed to update status on %s: %w", oldObject.Name, err)
 		}
@@ -387,6 +408,42 @@ func cloneClusterResourcesInCluster(ctx context.Context, logger logrus.FieldLogg
 	return len(oldObjects.Items), nil
 }
 
+func convertKMSEncryptionConfiguration(old *kubermaticv1.KMSEncryptionConfiguration) *newv1.KMSEncryptionConfiguration {
+	if old == nil {
+		return nil
+	}
+
+	return &newv1.KMSEncryptionConfiguration{
+		Name:     old.Name,
+		Endpoint: old.Endpoint,
+		Timeout:  old.Timeout,
+	}
+}
+
+func convertAuditSinks(oldSinks []kubermaticv1.AuditSink) []newv1.AuditSink {
+	var newSinks []newv1.AuditSink
+	for _, oldSink := range oldSinks {
+		newSink := newv1.AuditSink{
+			Name:    oldSink.Name,
+			Webhook: (*newv1.AuditWebhookSink)(oldSink.Webhook),
+			HTTP:    (*newv1.AuditHTTPSink)(oldSink.HTTP),
+			Loki:    (*newv1.AuditLokiSink)(oldSink.Loki),
+			S3:      (*newv1.AuditS3Sink)(oldSink.S3),
+		}
+
+		if oldSink.Syslog != nil {
+			newSink.Syslog = &newv1.AuditSyslogSink{
+				Address:  oldSink.Syslog.Address,
+				Protocol: newv1.AuditSyslogProtocol(oldSink.Syslog.Protocol),
+			}
+		}
+
+		newSinks = append(newSinks, newSink)
+	}
+
+	return newSinks
+}
+
 func convertAzureLoadBalancerSKU(old kubermaticv1.LBSKU) newv1.LBSKU {
 	if old == "" {
 		return newv1.AzureBasicLBSKU
@@ -446,6 +503,8 @@ func convertClusterSpec(old kubermaticv1.ClusterSpec) newv1.ClusterSpec {
 		ServiceAccount:                       (*newv1.ServiceAccountSettings)(old.ServiceAccount),
 		MLA:                                  (*newv1.MLASettings)(old.MLA),
 		ContainerRuntime:                     old.ContainerRuntime,
+		APIServerAllowedIPRanges:             (*newv1.NetworkRanges)(old.APIServerAllowedIPRanges),
+		CertificateRotation:                  (*newv1.CertificateRotationSettings)(old.CertificateRotation),
 	}
 
 	if old := old.Cloud.Azure; old != nil {
@@ -514,8 +573,22 @@ func convertClusterSpec(old kubermaticv1.ClusterSpec) newv1.ClusterSpec {
 
 	if old := old.AuditLogging; old != nil {
 		result.AuditLogging = &newv1.AuditLoggingSettings{
-			Enabled:      old.Enabled,
-			PolicyPreset: newv1.AuditPolicyPreset(old.PolicyPreset),
+			Enabled:             old.Enabled,
+			PolicyPreset:        newv1.AuditPolicyPreset(old.PolicyPreset),
+			Policy:              old.Policy,
+			PolicyName:          old.PolicyName,
+			Sinks:               convertAuditSinks(old.Sinks),
+			MLAIngestionEnabled: old.MLAIngestionEnabled,
+		}
+	}
+
+	if old := old.EncryptionConfiguration; old != nil {
+		result.EncryptionConfiguration = &newv1.EncryptionConfiguration{
+			Enabled:       old.Enabled,
+			Resources:     old.Resources,
+			Provider:      newv1.EncryptionProvider(old.Provider),
+			KeyGeneration: old.KeyGeneration,
+			KMS:           convertKMSEncryptionConfiguration(old.KMS),
 		}
 	}
 
@@ -712,6 +785,60 @@ func cloneAllowedRegistryResourcesInCluster(ctx context.Context, logger logrus.F
 	return len(oldObjects.Items), nil
 }
 
+func cloneClusterMigrationResourcesInCluster(ctx context.Context, logger logrus.FieldLogger, client ctrlruntimeclient.Client) (int, error) {
+	oldObjects := &kubermaticv1.ClusterMigrationList{}
+	if err := client.List(ctx, oldObjects); err != nil {
+		return 0, fmt.Errorf("failed to list objects: %w", err)
+	}
+
+	for _, oldObject := range oldObjects.Items {
+		newObject := newv1.ClusterMigration{
+			ObjectMeta: convertObjectMeta(oldObject.ObjectMeta),
+			Spec: newv1.ClusterMigrationSpec{
+				ClusterName:      oldObject.Spec.ClusterName,
+				SourceSeed:       oldObject.Spec.SourceSeed,
+				TargetSeed:       oldObject.Spec.TargetSeed,
+				TargetDatacenter: oldObject.Spec.TargetDatacenter,
+				Destination:      oldObject.Spec.Destination,
+			},
+		}
+
+		if err := ensureObject(ctx, client, &newObject, false); err != nil {
+			return 0, fmt.Errorf("failed to clone %s: %w", oldObject.Name, err)
+		}
+
+		newObject.Status = newv1.ClusterMigrationStatus{
+			Phase:                   newv1.ClusterMigrationPhase(oldObject.Status.Phase),
+			Message:                 oldObject.Status.Message,
+			BackupName:              oldObject.Status.BackupName,
+			SourceApiserverReplicas: oldObject.Status.SourceApiserverReplicas,
+			SourceAddress:           convertClusterMigrationAddress(oldObject.Status.SourceAddress),
+			TargetAddress:           convertClusterMigrationAddress(oldObject.Status.TargetAddress),
+			StartTime:               oldObject.Status.StartTime,
+			CompletionTime:          oldObject.Status.CompletionTime,
+		}
+
+		if err := client.Status().Update(ctx, &newObject); err != nil {
+			return 0, fmt.Errorf("failed to update status on %s: %w", oldObject.Name, err)
+		}
+	}
+
+	return len(oldObjects.Items), nil
+}
+
+func convertClusterMigrationAddress(oldAddress *kubermaticv1.ClusterMigrationAddress) *newv1.ClusterMigrationAddress {
+	if oldAddress == nil {
+		return nil
+	}
+
+	return &newv1.ClusterMigrationAddress{
+		URL:          oldAddress.URL,
+		ExternalName: oldAddress.ExternalName,
+		IP:           oldAddress.IP,
+		Port:         oldAddress.Port,
+	}
+}
+
 func cloneClusterTemplateResourcesInCluster(ctx context.Context, logger logrus.FieldLogger, client ctrlruntimeclient.Client) (int, error) {
 	oldObjects := &kubermaticv1.ClusterTemplateList{}
 	if err := client.List(ctx, oldObjects); err != nil {
@@ -843,6 +970,46 @@ func cloneEtcdBackupConfigResourcesInCluster(ctx context.Context, logger logrus.
 			},
 		}
 
+		if oldObject.Spec.Continuous != nil {
+			newObject.Spec.Continuous = &newv1.EtcdContinuousBackup{
+				Enabled:       oldObject.Spec.Continuous.Enabled,
+				FlushInterval: oldObject.Spec.Continuous.FlushInterval,
+				Retention:     oldObject.Spec.Continuous.Retention,
+			}
+		}
+
+		if oldObject.Spec.Verification != nil {
+			newObject.Spec.Verification = &newv1.EtcdBackupVerification{
+				Enabled: oldObject.Spec.Verification.Enabled,
+				Restore: oldObject.Spec.Verification.Restore,
+			}
+		}
+
+		if oldObject.Spec.Retention != nil {
+			newObject.Spec.Retention = &newv1.EtcdBackupRetention{
+				Hourly:  oldObject.Spec.Retention.Hourly,
+				Daily:   oldObject.Spec.Retention.Daily,
+				Weekly:  oldObject.Spec.Retention.Weekly,
+				Monthly: oldObject.Spec.Retention.Monthly,
+			}
+		}
+
+		for _, replica := range oldObject.Spec.Replicas {
+			newReplica := newv1.EtcdBackupReplica{
+				Destination: replica.Destination,
+				Keep:        replica.Keep,
+			}
+			if replica.Retention != nil {
+				newReplica.Retention = &newv1.EtcdBackupRetention{
+					Hourly:  replica.Retention.Hourly,
+					Daily:   replica.Retention.Daily,
+					Weekly:  replica.Retention.Weekly,
+					Monthly: replica.Retention.Monthly,
+				}
+			}
+			newObject.Spec.Replicas = append(newObject.Spec.Replicas, newReplica)
+		}
+
 		if err := ensureObject(ctx, client, &newObject, false); err != nil {
 			return 0, fmt.Errorf("failed to clone %s: %w", oldObject.Name, err)
 		}
@@ -863,6 +1030,21 @@ func cloneEtcdBackupConfigResourcesInCluster(ctx context.Context, logger logrus.
 		}
 
 		for _, backup := range oldObject.Status.CurrentBackups {
+			var replicas []newv1.BackupReplicaStatus
+			for _, replica := range backup.Replicas {
+				replicas = append(replicas, newv1.BackupReplicaStatus{
+					Destination:        replica.Destination,
+					JobName:            replica.JobName,
+					BackupFinishedTime: replica.BackupFinishedTime,
+					BackupPhase:        newv1.BackupStatusPhase(replica.BackupPhase),
+					BackupMessage:      replica.BackupMessage,
+					DeleteJobName:      replica.DeleteJobName,
+					DeleteFinishedTime: replica.DeleteFinishedTime,
+					DeletePhase:        newv1.BackupStatusPhase(replica.DeletePhase),
+					DeleteMessage:      replica.DeleteMessage,
+				})
+			}
+
 			newObject.Status.CurrentBackups = append(newObject.Status.CurrentBackups, newv1.BackupStatus{
 				ScheduledTime:      backup.ScheduledTime,
 				BackupName:         backup.BackupName,
@@ -876,6 +1058,15 @@ func cloneEtcdBackupConfigResourcesInCluster(ctx context.Context, logger logrus.
 				DeleteFinishedTime: backup.DeleteFinishedTime,
 				DeletePhase:        newv1.BackupStatusPhase(backup.DeletePhase),
 				DeleteMessage:      backup.DeleteMessage,
+
+				VerificationJobName:      backup.VerificationJobName,
+				VerificationFinishedTime: backup.VerificationFinishedTime,
+				VerificationPhase:        newv1.BackupStatusPhase(backup.VerificationPhase),
+				VerificationMessage:      backup.VerificationMessage,
+				SnapshotRevision:         backup.SnapshotRevision,
+				SnapshotKeyCount:         backup.SnapshotKeyCount,
+				SnapshotHash:             backup.SnapshotHash,
+				Replicas:                 replicas,
 			})
 		}
 
@@ -900,8 +1091,12 @@ func cloneEtcdRestoreResourcesInCluster(ctx context.Context, logger logrus.Field
 				Name:                            oldObject.Spec.Name,
 				BackupDownloadCredentialsSecret: oldObject.Spec.BackupDownloadCredentialsSecret,
 				BackupName:                      oldObject.Spec.BackupName,
+				SourceClusterName:               oldObject.Spec.SourceClusterName,
 				Cluster:                         migrateObjectReference(oldObject.Spec.Cluster, ""),
 				Destination:                     oldObject.Spec.Destination,
+				FallbackDestinations:            oldObject.Spec.FallbackDestinations,
+				RestoreToTime:                   oldObject.Spec.RestoreToTime,
+				RestoreToRevision:               oldObject.Spec.RestoreToRevision,
 			},
 		}
 
@@ -912,6 +1107,7 @@ func cloneEtcdRestoreResourcesInCluster(ctx context.Context, logger logrus.Field
 		newObject.Status = newv1.EtcdRestoreStatus{
 			Phase:       newv1.EtcdRestorePhase(oldObject.Status.Phase),
 			RestoreTime: oldObject.Status.RestoreTime,
+			Destination: oldObject.Status.Destination,
 		}
 
 		if err := client.Status().Update(ctx, &newObject); err != nil {
@@ -1048,8 +1244,10 @@ func clonePresetResourcesInCluster(ctx context.Context, logger logrus.FieldLogge
 		newObject := newv1.Preset{
 			ObjectMeta: convertObjectMeta(oldObject.ObjectMeta),
 			Spec: newv1.PresetSpec{
-				Enabled:        oldObject.Spec.Enabled,
-				RequiredEmails: oldObject.Spec.RequiredEmails,
+				Enabled:         oldObject.Spec.Enabled,
+				RequiredEmails:  oldObject.Spec.RequiredEmails,
+				Projects:        oldObject.Spec.Projects,
+				ProjectSelector: oldObject.Spec.ProjectSelector,
 			},
 		}
 
@@ -1217,6 +1415,12 @@ func clonePresetResourcesInCluster(ctx context.Context, logger logrus.FieldLogge
 			}
 		}
 
+		if oldSpec.ExternalSecrets != nil {
+			newObject.Spec.ExternalSecrets = &newv1.PresetExternalSecrets{
+				Path: oldSpec.ExternalSecrets.Path,
+			}
+		}
+
 		if err := ensureObject(ctx, client, &newObject, false); err != nil {
 			return 0, fmt.Errorf("failed to clone %s: %w", oldObject.Name, err)
 		}
@@ -1295,15 +1499,17 @@ func cloneSeedResourcesInCluster(ctx context.Context, logger logrus.FieldLogger,
 				Datacenters:      map[string]newv1.Datacenter{},
 				SeedDNSOverwrite: oldObject.Spec.SeedDNSOverwrite,
 				NodeportProxy: newv1.NodeportProxyConfig{
-					Disable:      oldObject.Spec.NodeportProxy.Disable,
-					Annotations:  oldObject.Spec.NodeportProxy.Annotations,
-					Envoy:        convertNodeportProxyComponent(oldObject.Spec.NodeportProxy.Envoy),
-					EnvoyManager: convertNodeportProxyComponent(oldObject.Spec.NodeportProxy.EnvoyManager),
-					Updater:      convertNodeportProxyComponent(oldObject.Spec.NodeportProxy.Updater),
+					Disable:           oldObject.Spec.NodeportProxy.Disable,
+					Annotations:       oldObject.Spec.NodeportProxy.Annotations,
+					PreserveSourceIPs: oldObject.Spec.NodeportProxy.PreserveSourceIPs,
+					Envoy:             convertNodeportProxyComponent(oldObject.Spec.NodeportProxy.Envoy),
+					EnvoyManager:      convertNodeportProxyComponent(oldObject.Spec.NodeportProxy.EnvoyManager),
+					Updater:           convertNodeportProxyComponent(oldObject.Spec.NodeportProxy.Updater),
 				},
 				ExposeStrategy:           newv1.ExposeStrategy(oldObject.Spec.ExposeStrategy),
 				DefaultComponentSettings: convertComponentSettings(oldObject.Spec.DefaultComponentSettings),
 				DefaultClusterTemplate:   oldObject.Spec.DefaultClusterTemplate,
+				AuditPolicies:            oldObject.Spec.AuditPolicies,
 			},
 		}
 
@@ -1343,10 +1549,23 @@ func cloneSeedResourcesInCluster(ctx context.Context, logger logrus.FieldLogger,
 			destinations := make(map[string]*newv1.BackupDestination)
 			for name, destination := range oldObject.Spec.EtcdBackupRestore.Destinations {
 				destinations[name] = &newv1.BackupDestination{
+					Type:        newv1.BackupDestinationType(destination.Type),
 					Endpoint:    destination.Endpoint,
 					BucketName:  destination.BucketName,
 					Credentials: destination.Credentials,
 				}
+				if destination.Filesystem != nil {
+					destinations[name].Filesystem = &newv1.FilesystemBackupDestination{
+						NFS:                   destination.Filesystem.NFS,
+						PersistentVolumeClaim: destination.Filesystem.PersistentVolumeClaim,
+					}
+				}
+				if destination.Encryption != nil {
+					destinations[name].Encryption = &newv1.BackupEncryption{
+						KeySecret: destination.Encryption.KeySecret,
+						ActiveKey: destination.Encryption.ActiveKey,
+					}
+				}
 			}
 			newObject.Spec.EtcdBackupRestore = &newv1.EtcdBackupRestore{
 				Destinations: destinations,
@@ -1371,17 +1590,26 @@ func convertComponentSettings(oldSettings kubermaticv1.ComponentSettings) newv1.
 		ControllerManager: convertControllerSettings(oldSettings.ControllerManager),
 		Scheduler:         convertControllerSettings(oldSettings.Scheduler),
 		Etcd: newv1.EtcdStatefulSetSettings{
-			ClusterSize:  oldSettings.Etcd.ClusterSize,
-			StorageClass: oldSettings.Etcd.StorageClass,
-			DiskSize:     oldSettings.Etcd.DiskSize,
-			Resources:    oldSettings.Etcd.Resources.DeepCopy(),
-			Tolerations:  oldSettings.Etcd.Tolerations,
+			ClusterSize:       oldSettings.Etcd.ClusterSize,
+			StorageClass:      oldSettings.Etcd.StorageClass,
+			DiskSize:          oldSettings.Etcd.DiskSize,
+			Resources:         oldSettings.Etcd.Resources.DeepCopy(),
+			Tolerations:       oldSettings.Etcd.Tolerations,
+			QuotaBackendBytes: oldSettings.Etcd.QuotaBackendBytes,
 		},
 		Prometheus: newv1.StatefulSetSettings{
 			Resources: oldSettings.Prometheus.Resources.DeepCopy(),
 		},
 	}
 
+	if oldDefrag := oldSettings.Etcd.Defragmentation; oldDefrag != nil {
+		newSettings.Etcd.Defragmentation = &newv1.EtcdDefragmentationSettings{
+			Disabled:                      oldDefrag.Disabled,
+			FragmentationThresholdPercent: oldDefrag.FragmentationThresholdPercent,
+			MinDBSize:                     oldDefrag.MinDBSize,
+		}
+	}
+
 	return newSettings
 }
 
@@ -1417,6 +1645,8 @@ func convertDatacenter(oldDC kubermaticv1.Datacenter) newv1.Datacenter {
 		Location: oldDC.Location,
 		Spec: newv1.DatacenterSpec{
 			EnforceAuditLogging:            oldDC.Spec.EnforceAuditLogging,
+			MinimumAuditPolicy:             oldDC.Spec.MinimumAuditPolicy,
+			DefaultAuditSinks:              convertAuditSinks(oldDC.Spec.DefaultAuditSinks),
 			EnforcePodSecurityPolicy:       oldDC.Spec.EnforcePodSecurityPolicy,
 			RequiredEmails:                 oldDC.Spec.RequiredEmailDomains,
 			ProviderReconciliationInterval: oldDC.Spec.ProviderReconciliationInterval,
@@ -1486,9 +1716,10 @@ func convertDatacenter(oldDC kubermaticv1.Datacenter) newv1.Datacenter {
 
 	if oldSpec.GCP != nil {
 		newDC.Spec.GCP = &newv1.DatacenterSpecGCP{
-			Region:       oldSpec.GCP.Region,
-			ZoneSuffixes: oldSpec.GCP.ZoneSuffixes,
-			Regional:     oldSpec.GCP.Regional,
+			Region:           oldSpec.GCP.Region,
+			ZoneSuffixes:     oldSpec.GCP.ZoneSuffixes,
+			DedicatedNetwork: oldSpec.GCP.DedicatedNetwork,
+			Regional:         oldSpec.GCP.Regional,
 		}
 	}
 
@@ -1502,8 +1733,9 @@ func convertDatacenter(oldDC kubermaticv1.Datacenter) newv1.Datacenter {
 
 	if oldSpec.Kubevirt != nil {
 		newDC.Spec.Kubevirt = &newv1.DatacenterSpecKubevirt{
-			DNSPolicy: oldSpec.Kubevirt.DNSPolicy,
-			DNSConfig: oldSpec.Kubevirt.DNSConfig.DeepCopy(),
+			DNSPolicy:     oldSpec.Kubevirt.DNSPolicy,
+			DNSConfig:     oldSpec.Kubevirt.DNSConfig.DeepCopy(),
+			ResourceQuota: oldSpec.Kubevirt.ResourceQuota.DeepCopy(),
 		}
 	}
 
@@ -72,6 +72,7 @@ var (
 		{Name: "AdmissionPlugin", Namespaced: false, MasterCluster: true, SeedCluster: false},
 		{Name: "Alertmanager", Namespaced: true, MasterCluster: false, SeedCluster: true},
 		{Name: "AllowedRegistry", Namespaced: false, MasterCluster: true, SeedCluster: false},
+		{Name: "ClusterMigration", Namespaced: false, MasterCluster: true, SeedCluster: false},
 		{Name: "ClusterTemplate", Namespaced: false, MasterCluster: true, SeedCluster: true},
 		{Name: "ClusterTemplateInstance", Namespaced: false, MasterCluster: false, SeedCluster: true},
 		{Name: "Constraint", Namespaced: true, MasterCluster: false, SeedCluster: true},
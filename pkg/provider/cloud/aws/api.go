@@ -198,6 +198,72 @@ func GetEKSClusterConfig(ctx context.Context, accessKeyID, secretAccessKey, clus
 	return &config, nil
 }
 
+// GetEKSClusterStatus returns the status reported by EKS for the given cluster.
+// provider.ErrNotFound is returned when the cluster does not exist.
+func GetEKSClusterStatus(accessKeyID, secretAccessKey, clusterName, region string) (string, error) {
+	client, err := GetClientSet(accessKeyID, secretAccessKey, "", "", region)
+	if err != nil {
+		return "", err
+	}
+
+	clusterOutput, err := client.EKS.DescribeCluster(&eks.DescribeClusterInput{Name: aws.String(clusterName)})
+	if err != nil {
+		if isEKSNotFound(err) {
+			return "", provider.ErrNotFound
+		}
+		return "", fmt.Errorf("error calling DescribeCluster: %w", err)
+	}
+	return aws.StringValue(clusterOutput.Cluster.Status), nil
+}
+
+// DeleteEKSCluster starts the deletion of the given EKS cluster. EKS refuses to delete clusters
+// which still have node groups, so those are deleted first and the cluster itself is only deleted
+// once all of them are gone. Clusters that do not exist are ignored.
+func DeleteEKSCluster(accessKeyID, secretAccessKey, clusterName, region string) error {
+	client, err := GetClientSet(accessKeyID, secretAccessKey, "", "", region)
+	if err != nil {
+		return err
+	}
+
+	nodeGroupsOutput, err := client.EKS.ListNodegroups(&eks.ListNodegroupsInput{ClusterName: aws.String(clusterName)})
+	if err != nil {
+		if isEKSNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("error calling ListNodegroups: %w", err)
+	}
+
+	if len(nodeGroupsOutput.Nodegroups) > 0 {
+		for _, nodeGroupName := range nodeGroupsOutput.Nodegroups {
+			_, err := client.EKS.DeleteNodegroup(&eks.DeleteNodegroupInput{
+				ClusterName:   aws.String(clusterName),
+				NodegroupName: nodeGroupName,
+			})
+			// node groups which are already being deleted are reported as in use
+			if err != nil && !isEKSNotFound(err) && !isEKSResourceInUse(err) {
+				return fmt.Errorf("failed to delete node group %s: %w", aws.StringValue(nodeGroupName), err)
+			}
+		}
+		return nil
+	}
+
+	_, err = client.EKS.DeleteCluster(&eks.DeleteClusterInput{Name: aws.String(clusterName)})
+	if err != nil && !isEKSNotFound(err) && !isEKSResourceInUse(err) {
+		return fmt.Errorf("error calling DeleteCluster: %w", err)
+	}
+	return nil
+}
+
+func isEKSNotFound(err error) bool {
+	awsErr, ok := err.(awserr.Error)
+	return ok && awsErr.Code() == eks.ErrCodeResourceNotFoundException
+}
+
+func isEKSResourceInUse(err error) bool {
+	awsErr, ok := err.(awserr.Error)
+	return ok && awsErr.Code() == eks.ErrCodeResourceInUseException
+}
+
 func GetCredentialsForEKSCluster(cloud kubermaticv1.ExternalClusterCloudSpec, secretKeySelector provider.SecretKeySelectorValueFunc) (accessKeyID, secretAccessKey string, err error) {
 	accessKeyID = cloud.EKS.AccessKeyID
 	secretAccessKey = cloud.EKS.SecretAccessKey
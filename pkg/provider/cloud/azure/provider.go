@@ -472,6 +472,51 @@ func GetAKSCLusterConfig(ctx context.Context, tenantID, subscriptionID, clientID
 	return config, nil
 }
 
+// GetAKSClusterStatus returns the provisioning state reported by AKS for the given cluster.
+// provider.ErrNotFound is returned when the cluster does not exist.
+func GetAKSClusterStatus(ctx context.Context, cred Credentials, clusterName, resourceGroupName string) (string, error) {
+	aksClient, err := getAKSClient(cred)
+	if err != nil {
+		return "", err
+	}
+
+	aksCluster, err := aksClient.Get(ctx, resourceGroupName, clusterName)
+	if err != nil {
+		if isNotFound(aksCluster.Response) {
+			return "", provider.ErrNotFound
+		}
+		return "", fmt.Errorf("cannot get AKS managed cluster %v from resource group %v: %w", clusterName, resourceGroupName, err)
+	}
+	if aksCluster.ManagedClusterProperties == nil || aksCluster.ProvisioningState == nil {
+		return "", nil
+	}
+	return *aksCluster.ProvisioningState, nil
+}
+
+// DeleteAKSCluster starts the deletion of the given AKS cluster. Clusters that do not exist are ignored.
+func DeleteAKSCluster(ctx context.Context, cred Credentials, clusterName, resourceGroupName string) error {
+	aksClient, err := getAKSClient(cred)
+	if err != nil {
+		return err
+	}
+
+	future, err := aksClient.Delete(ctx, resourceGroupName, clusterName)
+	if err != nil && !isNotFound(autorest.Response{Response: future.Response()}) {
+		return fmt.Errorf("cannot delete AKS managed cluster %v from resource group %v: %w", clusterName, resourceGroupName, err)
+	}
+	return nil
+}
+
+func getAKSClient(cred Credentials) (*containerservice.ManagedClustersClient, error) {
+	var err error
+	aksClient := containerservice.NewManagedClustersClient(cred.SubscriptionID)
+	aksClient.Authorizer, err = auth.NewClientCredentialsConfig(cred.ClientID, cred.ClientSecret, cred.TenantID).Authorizer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create authorizer: %s", err.Error())
+	}
+	return &aksClient, nil
+}
+
 func GetCredentialsForAKSCluster(cloud kubermaticv1.ExternalClusterCloudSpec, secretKeySelector provider.SecretKeySelectorValueFunc) (Credentials, error) {
 	tenantID := cloud.AKS.TenantID
 	subscriptionID := cloud.AKS.SubscriptionID
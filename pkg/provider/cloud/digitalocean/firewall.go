@@ -0,0 +1,238 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package digitalocean
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/digitalocean/godo"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/provider"
+	"k8c.io/kubermatic/v2/pkg/resources"
+)
+
+// allPorts is how the DigitalOcean API refers to all ports of a protocol.
+const allPorts = "0"
+
+var anywhere = []string{"0.0.0.0/0", "::/0"}
+
+func reconcileFirewall(ctx context.Context, client *godo.Client, cluster *kubermaticv1.Cluster, update provider.ClusterUpdater) (*kubermaticv1.Cluster, error) {
+	firewall, err := getFirewall(ctx, client, cluster)
+	if err != nil {
+		return cluster, err
+	}
+
+	// a pre-existing firewall chosen by the user is not reconciled
+	if firewall != nil && !isOwnedBy(firewall, cluster) {
+		if cluster.Spec.Cloud.Digitalocean.FirewallID == "" {
+			return cluster, fmt.Errorf("firewall %q already exists, but is not owned by the cluster", firewall.Name)
+		}
+		return cluster, nil
+	}
+
+	lowPort, highPort := getNodePortRange(cluster)
+	request := firewallRequest(cluster, lowPort, highPort)
+
+	if firewall == nil {
+		firewall, _, err = client.Firewalls.Create(ctx, request)
+		if err != nil {
+			return cluster, fmt.Errorf("failed to create firewall %q: %w", request.Name, err)
+		}
+	} else if !firewallEqual(firewall, request) {
+		firewall, _, err = client.Firewalls.Update(ctx, firewall.ID, request)
+		if err != nil {
+			return cluster, fmt.Errorf("failed to update firewall %q: %w", request.Name, err)
+		}
+	}
+
+	return update(cluster.Name, func(updatedCluster *kubermaticv1.Cluster) {
+		updatedCluster.Spec.Cloud.Digitalocean.FirewallID = firewall.ID
+		kuberneteshelper.AddFinalizer(updatedCluster, FinalizerFirewall)
+	})
+}
+
+// getFirewall returns the firewall of the cluster. Unless it was chosen by the user, it is
+// looked up by name as well, in case it was created but the cluster could not be updated afterwards.
+func getFirewall(ctx context.Context, client *godo.Client, cluster *kubermaticv1.Cluster) (*godo.Firewall, error) {
+	if id := cluster.Spec.Cloud.Digitalocean.FirewallID; id != "" {
+		firewall, _, err := client.Firewalls.Get(ctx, id)
+		if err == nil {
+			return firewall, nil
+		}
+		if !isNotFound(err) {
+			return nil, fmt.Errorf("failed to get firewall %q: %w", id, err)
+		}
+		if !kuberneteshelper.HasFinalizer(cluster, FinalizerFirewall) {
+			return nil, fmt.Errorf("firewall %q not found", id)
+		}
+	}
+
+	opts := &godo.ListOptions{}
+	for {
+		firewalls, resp, err := client.Firewalls.List(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list firewalls: %w", err)
+		}
+		for i := range firewalls {
+			if isOwnedBy(&firewalls[i], cluster) {
+				return &firewalls[i], nil
+			}
+		}
+		if resp.Links == nil || resp.Links.IsLastPage() {
+			return nil, nil
+		}
+		page, err := resp.Links.CurrentPage()
+		if err != nil {
+			return nil, err
+		}
+		opts.Page = page + 1
+	}
+}
+
+// isOwnedBy returns true if the firewall was created for the cluster. Firewalls have no labels,
+// so the name and the tag of the cluster's droplets identify it.
+func isOwnedBy(firewall *godo.Firewall, cluster *kubermaticv1.Cluster) bool {
+	if firewall.Name != resourceName(cluster) {
+		return false
+	}
+	for _, tag := range firewall.Tags {
+		if tag == clusterTag(cluster) {
+			return true
+		}
+	}
+	return false
+}
+
+func getNodePortRange(cluster *kubermaticv1.Cluster) (int, int) {
+	return resources.NewTemplateDataBuilder().
+		WithNodePortRange(cluster.Spec.ComponentsOverride.Apiserver.NodePortRange).
+		WithCluster(cluster).
+		Build().
+		NodePorts()
+}
+
+// nodePortSources returns the addresses allowed to access the nodeports. By default, this is
+// the external address of the cluster, under which the seed's nodeport-proxy exposes both
+// the apiserver and the konnectivity server. It is only known once the cluster is exposed.
+func nodePortSources(cluster *kubermaticv1.Cluster) []string {
+	if cluster.Spec.Cloud.Digitalocean.NodePortsAllowedIPRange != "" {
+		return []string{cluster.Spec.Cloud.Digitalocean.NodePortsAllowedIPRange}
+	}
+	if cluster.Address.IP != "" {
+		return []string{cluster.Address.IP}
+	}
+	return nil
+}
+
+// firewallRequest returns the desired firewall of the cluster. DigitalOcean cloud firewalls drop
+// everything not explicitly allowed, including the traffic between the droplets and all
+// outbound traffic.
+func firewallRequest(cluster *kubermaticv1.Cluster, lowPort, highPort int) *godo.FirewallRequest {
+	clusterSources := &godo.Sources{Tags: []string{clusterTag(cluster)}}
+	nodePorts := fmt.Sprintf("%d-%d", lowPort, highPort)
+
+	inbound := []godo.InboundRule{
+		{Protocol: "tcp", PortRange: allPorts, Sources: clusterSources},
+		{Protocol: "udp", PortRange: allPorts, Sources: clusterSources},
+		{Protocol: "tcp", PortRange: fmt.Sprintf("%d", provider.DefaultSSHPort), Sources: &godo.Sources{Addresses: anywhere}},
+		{Protocol: "icmp", Sources: &godo.Sources{Addresses: anywhere}},
+	}
+	if sources := nodePortSources(cluster); len(sources) > 0 {
+		inbound = append(inbound,
+			godo.InboundRule{Protocol: "tcp", PortRange: nodePorts, Sources: &godo.Sources{Addresses: sources}},
+			godo.InboundRule{Protocol: "udp", PortRange: nodePorts, Sources: &godo.Sources{Addresses: sources}},
+		)
+	}
+
+	return &godo.FirewallRequest{
+		Name:         resourceName(cluster),
+		InboundRules: inbound,
+		OutboundRules: []godo.OutboundRule{
+			{Protocol: "tcp", PortRange: allPorts, Destinations: &godo.Destinations{Addresses: anywhere}},
+			{Protocol: "udp", PortRange: allPorts, Destinations: &godo.Destinations{Addresses: anywhere}},
+			{Protocol: "icmp", Destinations: &godo.Destinations{Addresses: anywhere}},
+		},
+		Tags: []string{clusterTag(cluster)},
+	}
+}
+
+// firewallEqual compares the rules and tags of the firewall regardless of their order.
+func firewallEqual(firewall *godo.Firewall, request *godo.FirewallRequest) bool {
+	current := ruleKeys(firewall.InboundRules, firewall.OutboundRules)
+	desired := ruleKeys(request.InboundRules, request.OutboundRules)
+
+	return stringsEqual(current, desired) && stringsEqual(firewall.Tags, request.Tags)
+}
+
+func ruleKeys(inbound []godo.InboundRule, outbound []godo.OutboundRule) []string {
+	keys := make([]string, 0, len(inbound)+len(outbound))
+	for _, rule := range inbound {
+		var addresses, tags []string
+		if rule.Sources != nil {
+			addresses, tags = rule.Sources.Addresses, rule.Sources.Tags
+		}
+		keys = append(keys, ruleKey("in", rule.Protocol, rule.PortRange, addresses, tags))
+	}
+	for _, rule := range outbound {
+		var addresses, tags []string
+		if rule.Destinations != nil {
+			addresses, tags = rule.Destinations.Addresses, rule.Destinations.Tags
+		}
+		keys = append(keys, ruleKey("out", rule.Protocol, rule.PortRange, addresses, tags))
+	}
+	return keys
+}
+
+func ruleKey(direction, protocol, portRange string, addresses, tags []string) string {
+	// ICMP has no ports, but the API reports all ports for it
+	if protocol == "icmp" {
+		portRange = ""
+	}
+
+	return fmt.Sprintf("%s/%s/%s/%s/%s", direction, protocol, portRange, sortedList(addresses), sortedList(tags))
+}
+
+func sortedList(list []string) string {
+	sorted := append([]string{}, list...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+func stringsEqual(a, b []string) bool {
+	return len(a) == len(b) && sortedList(a) == sortedList(b)
+}
+
+func deleteFirewall(ctx context.Context, client *godo.Client, cluster *kubermaticv1.Cluster) error {
+	firewall, err := getFirewall(ctx, client, cluster)
+	if err != nil {
+		return err
+	}
+	if firewall == nil || !isOwnedBy(firewall, cluster) {
+		return nil
+	}
+
+	_, err = client.Firewalls.Delete(ctx, firewall.ID)
+	if isNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -19,50 +19,149 @@ package digitalocean
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
 
 	"github.com/digitalocean/godo"
+	"go.uber.org/zap"
 	"golang.org/x/oauth2"
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/log"
 	"k8c.io/kubermatic/v2/pkg/provider"
 	"k8c.io/kubermatic/v2/pkg/resources"
 )
 
+const (
+	resourceNamePrefix = "kubernetes-"
+
+	// FinalizerFirewall will instruct the deletion of the firewall
+	FinalizerFirewall = "kubermatic.io/cleanup-digitalocean-firewall"
+	// FinalizerTag will instruct the deletion of the cluster tag
+	FinalizerTag = "kubermatic.io/cleanup-digitalocean-tag"
+)
+
 type digitalocean struct {
+	log               *zap.SugaredLogger
+	ctx               context.Context
 	secretKeySelector provider.SecretKeySelectorValueFunc
 }
 
 // NewCloudProvider creates a new digitalocean provider.
 func NewCloudProvider(secretKeyGetter provider.SecretKeySelectorValueFunc) provider.CloudProvider {
 	return &digitalocean{
+		log:               log.Logger,
+		ctx:               context.TODO(),
 		secretKeySelector: secretKeyGetter,
 	}
 }
 
-var _ provider.CloudProvider = &digitalocean{}
+var _ provider.ReconcilingCloudProvider = &digitalocean{}
+
+func (do *digitalocean) getClient(cloud kubermaticv1.CloudSpec) (*godo.Client, error) {
+	token, err := GetCredentialsForCluster(cloud, do.secretKeySelector)
+	if err != nil {
+		return nil, err
+	}
+
+	static := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+	return godo.NewClient(oauth2.NewClient(context.Background(), static)), nil
+}
 
 func (do *digitalocean) DefaultCloudSpec(spec *kubermaticv1.CloudSpec) error {
 	return nil
 }
 
 func (do *digitalocean) ValidateCloudSpec(spec kubermaticv1.CloudSpec) error {
-	token, err := GetCredentialsForCluster(spec, do.secretKeySelector)
+	client, err := do.getClient(spec)
 	if err != nil {
 		return err
 	}
 
-	static := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	client := godo.NewClient(oauth2.NewClient(context.Background(), static))
+	if _, _, err = client.Regions.List(context.Background(), nil); err != nil {
+		return err
+	}
+
+	if spec.Digitalocean.FirewallID != "" {
+		if _, _, err := client.Firewalls.Get(context.Background(), spec.Digitalocean.FirewallID); err != nil {
+			return fmt.Errorf("failed to get firewall %q: %w", spec.Digitalocean.FirewallID, err)
+		}
+	}
 
-	_, _, err = client.Regions.List(context.Background(), nil)
-	return err
+	return nil
 }
 
 func (do *digitalocean) InitializeCloudProvider(cluster *kubermaticv1.Cluster, update provider.ClusterUpdater) (*kubermaticv1.Cluster, error) {
+	return do.reconcileCluster(cluster, update, false)
+}
+
+// ReconcileCluster
+func (do *digitalocean) ReconcileCluster(cluster *kubermaticv1.Cluster, update provider.ClusterUpdater) (*kubermaticv1.Cluster, error) {
+	return do.reconcileCluster(cluster, update, true)
+}
+
+func (do *digitalocean) reconcileCluster(cluster *kubermaticv1.Cluster, update provider.ClusterUpdater, force bool) (*kubermaticv1.Cluster, error) {
+	logger := do.log.With("cluster", cluster.Name)
+
+	client, err := do.getClient(cluster.Spec.Cloud)
+	if err != nil {
+		return nil, err
+	}
+
+	if force || !kuberneteshelper.HasFinalizer(cluster, FinalizerTag) {
+		logger.Infow("reconciling tag", "tag", clusterTag(cluster))
+		cluster, err = reconcileTag(do.ctx, client, cluster, update)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if force || cluster.Spec.Cloud.Digitalocean.FirewallID == "" {
+		logger.Infow("reconciling firewall", "firewall", resourceName(cluster))
+		cluster, err = reconcileFirewall(do.ctx, client, cluster, update)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return cluster, nil
 }
 
-func (do *digitalocean) CleanUpCloudProvider(cluster *kubermaticv1.Cluster, _ provider.ClusterUpdater) (*kubermaticv1.Cluster, error) {
+func (do *digitalocean) CleanUpCloudProvider(cluster *kubermaticv1.Cluster, update provider.ClusterUpdater) (*kubermaticv1.Cluster, error) {
+	logger := do.log.With("cluster", cluster.Name)
+
+	client, err := do.getClient(cluster.Spec.Cloud)
+	if err != nil {
+		return nil, err
+	}
+
+	if kuberneteshelper.HasFinalizer(cluster, FinalizerFirewall) {
+		logger.Infow("deleting firewall", "firewall", cluster.Spec.Cloud.Digitalocean.FirewallID)
+		if err := deleteFirewall(do.ctx, client, cluster); err != nil {
+			return cluster, fmt.Errorf("failed to delete firewall %q: %w", cluster.Spec.Cloud.Digitalocean.FirewallID, err)
+		}
+		cluster, err = update(cluster.Name, func(updatedCluster *kubermaticv1.Cluster) {
+			kuberneteshelper.RemoveFinalizer(updatedCluster, FinalizerFirewall)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if kuberneteshelper.HasFinalizer(cluster, FinalizerTag) {
+		logger.Infow("deleting tag", "tag", clusterTag(cluster))
+		if err := deleteTag(do.ctx, client, cluster); err != nil {
+			return cluster, fmt.Errorf("failed to delete tag %q: %w", clusterTag(cluster), err)
+		}
+		cluster, err = update(cluster.Name, func(updatedCluster *kubermaticv1.Cluster) {
+			kuberneteshelper.RemoveFinalizer(updatedCluster, FinalizerTag)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return cluster, nil
 }
 
@@ -87,3 +186,17 @@ func GetCredentialsForCluster(cloud kubermaticv1.CloudSpec, secretKeySelector pr
 
 	return accessToken, nil
 }
+
+func resourceName(cluster *kubermaticv1.Cluster) string {
+	return resourceNamePrefix + cluster.Name
+}
+
+// clusterTag is the tag the machine-controller puts on all droplets of the cluster.
+func clusterTag(cluster *kubermaticv1.Cluster) string {
+	return fmt.Sprintf("kubernetes-cluster-%s", cluster.Name)
+}
+
+func isNotFound(err error) bool {
+	var errResponse *godo.ErrorResponse
+	return errors.As(err, &errResponse) && errResponse.Response != nil && errResponse.Response.StatusCode == http.StatusNotFound
+}
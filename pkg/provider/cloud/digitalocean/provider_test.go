@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package digitalocean
+
+import (
+	"testing"
+
+	"github.com/digitalocean/godo"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testCluster(spec kubermaticv1.DigitaloceanCloudSpec, ip string) *kubermaticv1.Cluster {
+	return &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "abcd"},
+		Spec: kubermaticv1.ClusterSpec{
+			Cloud: kubermaticv1.CloudSpec{Digitalocean: &spec},
+		},
+		Address: kubermaticv1.ClusterAddress{IP: ip},
+	}
+}
+
+func TestNodePortSources(t *testing.T) {
+	testCases := []struct {
+		name     string
+		cluster  *kubermaticv1.Cluster
+		expected string
+	}{
+		{
+			name:     "not exposed yet",
+			cluster:  testCluster(kubermaticv1.DigitaloceanCloudSpec{}, ""),
+			expected: "",
+		},
+		{
+			name:     "seed address",
+			cluster:  testCluster(kubermaticv1.DigitaloceanCloudSpec{}, "1.2.3.4"),
+			expected: "1.2.3.4",
+		},
+		{
+			name:     "configured range",
+			cluster:  testCluster(kubermaticv1.DigitaloceanCloudSpec{NodePortsAllowedIPRange: "0.0.0.0/0"}, "1.2.3.4"),
+			expected: "0.0.0.0/0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if sources := sortedList(nodePortSources(tc.cluster)); sources != tc.expected {
+				t.Errorf("expected sources %q, got %q", tc.expected, sources)
+			}
+		})
+	}
+}
+
+func TestFirewallRequest(t *testing.T) {
+	request := firewallRequest(testCluster(kubermaticv1.DigitaloceanCloudSpec{}, ""), 30000, 32767)
+	for _, rule := range request.InboundRules {
+		if rule.PortRange == "30000-32767" {
+			t.Errorf("expected no nodeport rules before the cluster is exposed, got %+v", rule)
+		}
+	}
+
+	request = firewallRequest(testCluster(kubermaticv1.DigitaloceanCloudSpec{}, "1.2.3.4"), 30000, 32767)
+	nodePortRules := 0
+	for _, rule := range request.InboundRules {
+		if rule.PortRange == "30000-32767" {
+			nodePortRules++
+			if sortedList(rule.Sources.Addresses) != "1.2.3.4" {
+				t.Errorf("expected nodeports to be restricted to the seed address, got %v", rule.Sources.Addresses)
+			}
+		}
+	}
+	if nodePortRules != 2 {
+		t.Errorf("expected tcp and udp nodeport rules, got %d", nodePortRules)
+	}
+	if sortedList(request.Tags) != "kubernetes-cluster-abcd" {
+		t.Errorf("expected firewall to be applied to the cluster tag, got %v", request.Tags)
+	}
+}
+
+func TestFirewallEqual(t *testing.T) {
+	cluster := testCluster(kubermaticv1.DigitaloceanCloudSpec{}, "1.2.3.4")
+	request := firewallRequest(cluster, 30000, 32767)
+
+	// the API returns the rules in any order and reports all ports for ICMP
+	firewall := &godo.Firewall{Name: request.Name, Tags: request.Tags}
+	for i := len(request.InboundRules) - 1; i >= 0; i-- {
+		rule := request.InboundRules[i]
+		if rule.Protocol == "icmp" {
+			rule.PortRange = allPorts
+		}
+		firewall.InboundRules = append(firewall.InboundRules, rule)
+	}
+	firewall.OutboundRules = append(firewall.OutboundRules, request.OutboundRules...)
+
+	if !firewallEqual(firewall, request) {
+		t.Error("expected firewall to match the request")
+	}
+	if !isOwnedBy(firewall, cluster) {
+		t.Error("expected firewall to be owned by the cluster")
+	}
+
+	firewall.InboundRules[0].Sources = &godo.Sources{Addresses: anywhere}
+	if firewallEqual(firewall, request) {
+		t.Error("expected changed sources to be detected")
+	}
+
+	firewall.Tags = nil
+	if isOwnedBy(firewall, cluster) {
+		t.Error("expected firewall without the cluster tag not to be owned by the cluster")
+	}
+}
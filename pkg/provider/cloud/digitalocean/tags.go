@@ -0,0 +1,56 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package digitalocean
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/digitalocean/godo"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/provider"
+)
+
+// reconcileTag makes sure the cluster tag exists, as firewalls can only be applied to existing tags.
+// The machine-controller would otherwise create it along with the first droplet.
+func reconcileTag(ctx context.Context, client *godo.Client, cluster *kubermaticv1.Cluster, update provider.ClusterUpdater) (*kubermaticv1.Cluster, error) {
+	name := clusterTag(cluster)
+
+	_, _, err := client.Tags.Get(ctx, name)
+	if err != nil {
+		if !isNotFound(err) {
+			return cluster, fmt.Errorf("failed to get tag %q: %w", name, err)
+		}
+		if _, _, err := client.Tags.Create(ctx, &godo.TagCreateRequest{Name: name}); err != nil {
+			return cluster, fmt.Errorf("failed to create tag %q: %w", name, err)
+		}
+	}
+
+	return update(cluster.Name, func(updatedCluster *kubermaticv1.Cluster) {
+		kuberneteshelper.AddFinalizer(updatedCluster, FinalizerTag)
+	})
+}
+
+func deleteTag(ctx context.Context, client *godo.Client, cluster *kubermaticv1.Cluster) error {
+	_, err := client.Tags.Delete(ctx, clusterTag(cluster))
+	if isNotFound(err) {
+		return nil
+	}
+	return err
+}
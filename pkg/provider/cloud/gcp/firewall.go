@@ -0,0 +1,223 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcp
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"google.golang.org/api/compute/v1"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/provider"
+	"k8c.io/kubermatic/v2/pkg/resources"
+)
+
+// firewallRule is a firewall rule of the cluster along with the finalizer tracking it.
+type firewallRule struct {
+	finalizer string
+	firewall  *compute.Firewall
+}
+
+// resourceDescription marks the firewall rules and networks owned by the cluster, as they have no labels.
+func resourceDescription(cluster *kubermaticv1.Cluster) string {
+	return fmt.Sprintf("Managed by Kubermatic for cluster %s", cluster.Name)
+}
+
+func getNodePortRange(cluster *kubermaticv1.Cluster) (int, int) {
+	return resources.NewTemplateDataBuilder().
+		WithNodePortRange(cluster.Spec.ComponentsOverride.Apiserver.NodePortRange).
+		WithCluster(cluster).
+		Build().
+		NodePorts()
+}
+
+// firewallRules returns the desired firewall rules of the cluster, applying to all machines
+// with the cluster tag.
+func firewallRules(cluster *kubermaticv1.Cluster, lowPort, highPort int) []firewallRule {
+	nodePortsAllowedIPRange := cluster.Spec.Cloud.GCP.NodePortsAllowedIPRange
+	if nodePortsAllowedIPRange == "" {
+		nodePortsAllowedIPRange = "0.0.0.0/0"
+	}
+
+	tag := fmt.Sprintf("kubernetes-cluster-%s", cluster.Name)
+	nodePorts := fmt.Sprintf("%d-%d", lowPort, highPort)
+
+	return []firewallRule{
+		// allow traffic within the same cluster
+		{
+			finalizer: firewallSelfCleanupFinalizer,
+			firewall: &compute.Firewall{
+				Name:        fmt.Sprintf("firewall-%s-self", cluster.Name),
+				Description: resourceDescription(cluster),
+				Network:     cluster.Spec.Cloud.GCP.Network,
+				Allowed: []*compute.FirewallAllowed{
+					{IPProtocol: "tcp"},
+					{IPProtocol: "udp"},
+					{IPProtocol: "icmp"},
+					{IPProtocol: "esp"},
+					{IPProtocol: "ah"},
+					{IPProtocol: "sctp"},
+					{IPProtocol: "ipip"},
+				},
+				TargetTags: []string{tag},
+				SourceTags: []string{tag},
+			},
+		},
+		// allow ICMP from everywhere
+		{
+			finalizer: firewallICMPCleanupFinalizer,
+			firewall: &compute.Firewall{
+				Name:        fmt.Sprintf("firewall-%s-icmp", cluster.Name),
+				Description: resourceDescription(cluster),
+				Network:     cluster.Spec.Cloud.GCP.Network,
+				Allowed: []*compute.FirewallAllowed{
+					{IPProtocol: "icmp"},
+				},
+				TargetTags:   []string{tag},
+				SourceRanges: []string{"0.0.0.0/0"},
+			},
+		},
+		// open nodePorts for TCP and UDP
+		{
+			finalizer: firewallNodePortCleanupFinalizer,
+			firewall: &compute.Firewall{
+				Name:        fmt.Sprintf("firewall-%s-nodeport", cluster.Name),
+				Description: resourceDescription(cluster),
+				Network:     cluster.Spec.Cloud.GCP.Network,
+				Allowed: []*compute.FirewallAllowed{
+					{IPProtocol: "tcp", Ports: []string{nodePorts}},
+					{IPProtocol: "udp", Ports: []string{nodePorts}},
+				},
+				TargetTags:   []string{tag},
+				SourceRanges: []string{nodePortsAllowedIPRange},
+			},
+		},
+	}
+}
+
+func reconcileFirewallRule(svc *compute.Service, projectID string, cluster *kubermaticv1.Cluster, rule firewallRule, update provider.ClusterUpdater) (*kubermaticv1.Cluster, error) {
+	name := rule.firewall.Name
+
+	existing, err := svc.Firewalls.Get(projectID, name).Do()
+	if err != nil && !isHTTPError(err, http.StatusNotFound) {
+		return cluster, fmt.Errorf("failed to get firewall rule %s: %v", name, err)
+	}
+
+	switch {
+	case existing == nil:
+		op, err := svc.Firewalls.Insert(projectID, rule.firewall).Do()
+		if err != nil {
+			return cluster, fmt.Errorf("failed to create firewall rule %s: %v", name, err)
+		}
+		if err := waitForOperation(svc, projectID, op); err != nil {
+			return cluster, fmt.Errorf("failed to create firewall rule %s: %v", name, err)
+		}
+	case existing.Description != resourceDescription(cluster):
+		return cluster, fmt.Errorf("firewall rule %s already exists, but is not owned by the cluster", name)
+	case !firewallRuleEqual(existing, rule.firewall):
+		rule.firewall.Network = existing.Network
+		op, err := svc.Firewalls.Update(projectID, name, rule.firewall).Do()
+		if err != nil {
+			return cluster, fmt.Errorf("failed to update firewall rule %s: %v", name, err)
+		}
+		if err := waitForOperation(svc, projectID, op); err != nil {
+			return cluster, fmt.Errorf("failed to update firewall rule %s: %v", name, err)
+		}
+	}
+
+	return update(cluster.Name, func(cluster *kubermaticv1.Cluster) {
+		kuberneteshelper.AddFinalizer(cluster, rule.finalizer)
+	})
+}
+
+// firewallRuleEqual compares the allowed traffic of two firewall rules regardless of its order.
+// The network of a firewall rule can not be changed and is not compared.
+func firewallRuleEqual(a, b *compute.Firewall) bool {
+	allowed := func(firewall *compute.Firewall) string {
+		result := make([]string, len(firewall.Allowed))
+		for i, allowed := range firewall.Allowed {
+			result[i] = allowed.IPProtocol + ":" + sortedList(allowed.Ports)
+		}
+		return sortedList(result)
+	}
+
+	return allowed(a) == allowed(b) &&
+		sortedList(a.SourceRanges) == sortedList(b.SourceRanges) &&
+		sortedList(a.SourceTags) == sortedList(b.SourceTags) &&
+		sortedList(a.TargetTags) == sortedList(b.TargetTags)
+}
+
+func sortedList(list []string) string {
+	sorted := append([]string{}, list...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+func deleteFirewallRule(svc *compute.Service, projectID string, name string) error {
+	_, err := svc.Firewalls.Delete(projectID, name).Do()
+	// we ignore a Google API "not found" error
+	if err != nil && !isHTTPError(err, http.StatusNotFound) {
+		return err
+	}
+	return nil
+}
+
+// AdoptFirewallRules marks the firewall rules of clusters created before they were reconciled
+// as owned by the cluster. Rules without a tracking finalizer are left alone.
+func (g *GCP) AdoptFirewallRules(cluster *kubermaticv1.Cluster) error {
+	serviceAccount, err := GetCredentialsForCluster(cluster.Spec.Cloud, g.secretKeySelector)
+	if err != nil {
+		return err
+	}
+
+	svc, projectID, err := ConnectToComputeService(serviceAccount)
+	if err != nil {
+		return err
+	}
+
+	lowPort, highPort := getNodePortRange(cluster)
+	for _, rule := range firewallRules(cluster, lowPort, highPort) {
+		if !kuberneteshelper.HasFinalizer(cluster, rule.finalizer) {
+			continue
+		}
+
+		existing, err := svc.Firewalls.Get(projectID, rule.firewall.Name).Do()
+		if err != nil {
+			if isHTTPError(err, http.StatusNotFound) {
+				continue
+			}
+			return fmt.Errorf("failed to get firewall rule %s: %v", rule.firewall.Name, err)
+		}
+		if existing.Description != "" {
+			continue
+		}
+
+		op, err := svc.Firewalls.Patch(projectID, rule.firewall.Name, &compute.Firewall{Description: resourceDescription(cluster)}).Do()
+		if err != nil {
+			return fmt.Errorf("failed to update firewall rule %s: %v", rule.firewall.Name, err)
+		}
+		if err := waitForOperation(svc, projectID, op); err != nil {
+			return fmt.Errorf("failed to update firewall rule %s: %v", rule.firewall.Name, err)
+		}
+	}
+
+	return nil
+}
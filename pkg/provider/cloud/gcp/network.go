@@ -0,0 +1,142 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcp
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+
+	"google.golang.org/api/compute/v1"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/provider"
+)
+
+const (
+	resourceNamePrefix = "kubernetes-"
+
+	// subnetworkIPRange is the primary range of a dedicated subnetwork. It is the only
+	// subnetwork in the network, so it can be the same for every cluster.
+	subnetworkIPRange = "10.0.0.0/16"
+)
+
+func networkName(cluster *kubermaticv1.Cluster) string {
+	return resourceNamePrefix + cluster.Name
+}
+
+// reconcileNetwork sets up the network of a cluster that does not specify one. Unless the
+// datacenter asks for dedicated networks, the default network of the project is used.
+func reconcileNetwork(svc *compute.Service, projectID string, dc *kubermaticv1.DatacenterSpecGCP, cluster *kubermaticv1.Cluster, update provider.ClusterUpdater) (*kubermaticv1.Cluster, error) {
+	if !dc.DedicatedNetwork && !kuberneteshelper.HasFinalizer(cluster, networkCleanupFinalizer) {
+		return update(cluster.Name, func(cluster *kubermaticv1.Cluster) {
+			cluster.Spec.Cloud.GCP.Network = DefaultNetwork
+		})
+	}
+
+	name := networkName(cluster)
+
+	network, err := svc.Networks.Get(projectID, name).Do()
+	if err != nil && !isHTTPError(err, http.StatusNotFound) {
+		return cluster, fmt.Errorf("failed to get network %s: %v", name, err)
+	}
+
+	if network == nil {
+		op, err := svc.Networks.Insert(projectID, &compute.Network{
+			Name:                  name,
+			Description:           resourceDescription(cluster),
+			AutoCreateSubnetworks: false,
+			RoutingConfig:         &compute.NetworkRoutingConfig{RoutingMode: "REGIONAL"},
+			ForceSendFields:       []string{"AutoCreateSubnetworks"},
+		}).Do()
+		if err != nil {
+			return cluster, fmt.Errorf("failed to create network %s: %v", name, err)
+		}
+		if err := waitForOperation(svc, projectID, op); err != nil {
+			return cluster, fmt.Errorf("failed to create network %s: %v", name, err)
+		}
+
+		network, err = svc.Networks.Get(projectID, name).Do()
+		if err != nil {
+			return cluster, fmt.Errorf("failed to get network %s: %v", name, err)
+		}
+	} else if network.Description != resourceDescription(cluster) {
+		return cluster, fmt.Errorf("network %s already exists, but is not owned by the cluster", name)
+	}
+
+	subnetwork, err := svc.Subnetworks.Get(projectID, dc.Region, name).Do()
+	if err != nil && !isHTTPError(err, http.StatusNotFound) {
+		return cluster, fmt.Errorf("failed to get subnetwork %s: %v", name, err)
+	}
+
+	if subnetwork == nil {
+		op, err := svc.Subnetworks.Insert(projectID, dc.Region, &compute.Subnetwork{
+			Name:        name,
+			Description: resourceDescription(cluster),
+			Network:     network.SelfLink,
+			IpCidrRange: subnetworkIPRange,
+		}).Do()
+		if err != nil {
+			return cluster, fmt.Errorf("failed to create subnetwork %s: %v", name, err)
+		}
+		if err := waitForOperation(svc, projectID, op); err != nil {
+			return cluster, fmt.Errorf("failed to create subnetwork %s: %v", name, err)
+		}
+	}
+
+	return update(cluster.Name, func(cluster *kubermaticv1.Cluster) {
+		cluster.Spec.Cloud.GCP.Network = path.Join("global", "networks", name)
+		cluster.Spec.Cloud.GCP.Subnetwork = path.Join("regions", dc.Region, "subnetworks", name)
+		kuberneteshelper.AddFinalizer(cluster, networkCleanupFinalizer)
+	})
+}
+
+// deleteNetwork deletes the dedicated network of the cluster along with its subnetwork.
+func deleteNetwork(svc *compute.Service, projectID string, dc *kubermaticv1.DatacenterSpecGCP, cluster *kubermaticv1.Cluster) error {
+	name := networkName(cluster)
+
+	network, err := svc.Networks.Get(projectID, name).Do()
+	if err != nil {
+		if isHTTPError(err, http.StatusNotFound) {
+			return nil
+		}
+		return err
+	}
+	if network.Description != resourceDescription(cluster) {
+		return nil
+	}
+
+	op, err := svc.Subnetworks.Delete(projectID, dc.Region, name).Do()
+	if err != nil && !isHTTPError(err, http.StatusNotFound) {
+		return err
+	}
+	if err == nil {
+		if err := waitForOperation(svc, projectID, op); err != nil {
+			return err
+		}
+	}
+
+	op, err = svc.Networks.Delete(projectID, name).Do()
+	if err != nil {
+		if isHTTPError(err, http.StatusNotFound) {
+			return nil
+		}
+		return err
+	}
+	return waitForOperation(svc, projectID, op)
+}
@@ -51,41 +51,74 @@ const (
 	firewallICMPCleanupFinalizer     = "kubermatic.io/cleanup-gcp-firewall-icmp"
 	firewallNodePortCleanupFinalizer = "kubermatic.io/cleanup-gcp-firewall-nodeport"
 	routesCleanupFinalizer           = "kubermatic.io/cleanup-gcp-routes"
+	networkCleanupFinalizer          = "kubermatic.io/cleanup-gcp-network"
 
 	k8sNodeRouteTag          = "k8s-node-route"
 	k8sNodeRoutePrefixRegexp = "kubernetes-.*"
 )
 
-type gcp struct {
+type GCP struct {
+	dc                *kubermaticv1.DatacenterSpecGCP
 	secretKeySelector provider.SecretKeySelectorValueFunc
 	log               *zap.SugaredLogger
 }
 
 // NewCloudProvider creates a new gcp provider.
-func NewCloudProvider(secretKeyGetter provider.SecretKeySelectorValueFunc) provider.CloudProvider {
-	return &gcp{
+func NewCloudProvider(dc *kubermaticv1.Datacenter, secretKeyGetter provider.SecretKeySelectorValueFunc) (provider.CloudProvider, error) {
+	if dc.Spec.GCP == nil {
+		return nil, errors.New("datacenter is not a GCP datacenter")
+	}
+
+	return &GCP{
+		dc:                dc.Spec.GCP,
 		secretKeySelector: secretKeyGetter,
 		log:               log.Logger,
-	}
+	}, nil
 }
 
-var _ provider.CloudProvider = &gcp{}
+var _ provider.ReconcilingCloudProvider = &GCP{}
 
-// TODO: update behaviour of all these methods
 // InitializeCloudProvider initializes a cluster.
-func (g *gcp) InitializeCloudProvider(cluster *kubermaticv1.Cluster, update provider.ClusterUpdater) (*kubermaticv1.Cluster, error) {
-	var err error
-	if cluster.Spec.Cloud.GCP.Network == "" && cluster.Spec.Cloud.GCP.Subnetwork == "" {
-		cluster, err = update(cluster.Name, func(cluster *kubermaticv1.Cluster) {
-			cluster.Spec.Cloud.GCP.Network = DefaultNetwork
-		})
+func (g *GCP) InitializeCloudProvider(cluster *kubermaticv1.Cluster, update provider.ClusterUpdater) (*kubermaticv1.Cluster, error) {
+	return g.reconcileCluster(cluster, update, false)
+}
+
+// ReconcileCluster reconciles the network and firewall rules of the cluster.
+func (g *GCP) ReconcileCluster(cluster *kubermaticv1.Cluster, update provider.ClusterUpdater) (*kubermaticv1.Cluster, error) {
+	return g.reconcileCluster(cluster, update, true)
+}
+
+func (g *GCP) reconcileCluster(cluster *kubermaticv1.Cluster, update provider.ClusterUpdater, force bool) (*kubermaticv1.Cluster, error) {
+	logger := g.log.With("cluster", cluster.Name)
+
+	serviceAccount, err := GetCredentialsForCluster(cluster.Spec.Cloud, g.secretKeySelector)
+	if err != nil {
+		return nil, err
+	}
+
+	svc, projectID, err := ConnectToComputeService(serviceAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	if (cluster.Spec.Cloud.GCP.Network == "" && cluster.Spec.Cloud.GCP.Subnetwork == "") ||
+		(force && kuberneteshelper.HasFinalizer(cluster, networkCleanupFinalizer)) {
+		logger.Infow("reconciling network", "network", cluster.Spec.Cloud.GCP.Network)
+		cluster, err = reconcileNetwork(svc, projectID, g.dc, cluster, update)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	if err := g.ensureFirewallRules(cluster, update); err != nil {
-		return nil, err
+	lowPort, highPort := getNodePortRange(cluster)
+	for _, rule := range firewallRules(cluster, lowPort, highPort) {
+		if force || !kuberneteshelper.HasFinalizer(cluster, rule.finalizer) {
+			logger.Infow("reconciling firewall rule", "rule", rule.firewall.Name)
+			cluster, err = reconcileFirewallRule(svc, projectID, cluster, rule, update)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	// add the routes cleanup finalizer
@@ -100,18 +133,13 @@ func (g *gcp) InitializeCloudProvider(cluster *kubermaticv1.Cluster, update prov
 	return cluster, nil
 }
 
-// TODO: Hey, you! Yes, you! Why don't you implement reconciling for GCP? Would be really cool :)
-// func (g *gcp) ReconcileCluster(cluster *kubermaticv1.Cluster, update provider.ClusterUpdater) (*kubermaticv1.Cluster, error) {
-// 	return cluster, nil
-// }
-
 // DefaultCloudSpec adds defaults to the cloud spec.
-func (g *gcp) DefaultCloudSpec(spec *kubermaticv1.CloudSpec) error {
+func (g *GCP) DefaultCloudSpec(spec *kubermaticv1.CloudSpec) error {
 	return nil
 }
 
 // ValidateCloudSpec validates the given CloudSpec.
-func (g *gcp) ValidateCloudSpec(spec kubermaticv1.CloudSpec) error {
+func (g *GCP) ValidateCloudSpec(spec kubermaticv1.CloudSpec) error {
 	sa, err := GetCredentialsForCluster(spec, g.secretKeySelector)
 	if err != nil {
 		return err
@@ -123,7 +151,7 @@ func (g *gcp) ValidateCloudSpec(spec kubermaticv1.CloudSpec) error {
 }
 
 // CleanUpCloudProvider removes firewall rules and related finalizer.
-func (g *gcp) CleanUpCloudProvider(cluster *kubermaticv1.Cluster, update provider.ClusterUpdater) (*kubermaticv1.Cluster, error) {
+func (g *GCP) CleanUpCloudProvider(cluster *kubermaticv1.Cluster, update provider.ClusterUpdater) (*kubermaticv1.Cluster, error) {
 
 	serviceAccount, err := GetCredentialsForCluster(cluster.Spec.Cloud, g.secretKeySelector)
 	if err != nil {
@@ -135,67 +163,45 @@ func (g *gcp) CleanUpCloudProvider(cluster *kubermaticv1.Cluster, update provide
 		return nil, err
 	}
 
-	firewallService := compute.NewFirewallsService(svc)
-
-	selfRuleName := fmt.Sprintf("firewall-%s-self", cluster.Name)
-	icmpRuleName := fmt.Sprintf("firewall-%s-icmp", cluster.Name)
-	nodePortRuleName := fmt.Sprintf("firewall-%s-nodeport", cluster.Name)
-
-	if kuberneteshelper.HasFinalizer(cluster, firewallSelfCleanupFinalizer) {
-		_, err = firewallService.Delete(projectID, selfRuleName).Do()
-		// we ignore a Google API "not found" error
-		if err != nil && !isHTTPError(err, http.StatusNotFound) {
-			return nil, fmt.Errorf("failed to delete firewall rule %s: %v", selfRuleName, err)
-		}
-
-		cluster, err = update(cluster.Name, func(cluster *kubermaticv1.Cluster) {
-			kuberneteshelper.RemoveFinalizer(cluster, firewallSelfCleanupFinalizer)
-		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to remove %s finalizer: %v", firewallSelfCleanupFinalizer, err)
+	lowPort, highPort := getNodePortRange(cluster)
+	for _, rule := range firewallRules(cluster, lowPort, highPort) {
+		if !kuberneteshelper.HasFinalizer(cluster, rule.finalizer) {
+			continue
 		}
-	}
-
-	if kuberneteshelper.HasFinalizer(cluster, firewallICMPCleanupFinalizer) {
-		_, err = firewallService.Delete(projectID, icmpRuleName).Do()
-		// we ignore a Google API "not found" error
-		if err != nil && !isHTTPError(err, http.StatusNotFound) {
-			return nil, fmt.Errorf("failed to delete firewall rule %s: %v", icmpRuleName, err)
+		if err := deleteFirewallRule(svc, projectID, rule.firewall.Name); err != nil {
+			return nil, fmt.Errorf("failed to delete firewall rule %s: %v", rule.firewall.Name, err)
 		}
 
 		cluster, err = update(cluster.Name, func(cluster *kubermaticv1.Cluster) {
-			kuberneteshelper.RemoveFinalizer(cluster, firewallICMPCleanupFinalizer)
+			kuberneteshelper.RemoveFinalizer(cluster, rule.finalizer)
 		})
 		if err != nil {
-			return nil, fmt.Errorf("failed to remove %s finalizer: %v", firewallICMPCleanupFinalizer, err)
+			return nil, fmt.Errorf("failed to remove %s finalizer: %v", rule.finalizer, err)
 		}
 	}
 
-	// remove the nodeport firewall rule
-	if kuberneteshelper.HasFinalizer(cluster, firewallNodePortCleanupFinalizer) {
-		_, err = firewallService.Delete(projectID, nodePortRuleName).Do()
-		// we ignore a Google API "not found" error
-		if err != nil && !isHTTPError(err, http.StatusNotFound) {
-			return nil, fmt.Errorf("failed to delete firewall rule %s: %v", nodePortRuleName, err)
+	if kuberneteshelper.HasFinalizer(cluster, routesCleanupFinalizer) {
+		if err := g.cleanUnusedRoutes(cluster); err != nil {
+			return nil, err
 		}
-
 		cluster, err = update(cluster.Name, func(cluster *kubermaticv1.Cluster) {
-			kuberneteshelper.RemoveFinalizer(cluster, firewallNodePortCleanupFinalizer)
+			kuberneteshelper.RemoveFinalizer(cluster, routesCleanupFinalizer)
 		})
 		if err != nil {
-			return nil, fmt.Errorf("failed to remove %s finalizer: %v", firewallNodePortCleanupFinalizer, err)
+			return nil, fmt.Errorf("failed to remove %s finalizer: %v", routesCleanupFinalizer, err)
 		}
 	}
 
-	if kuberneteshelper.HasFinalizer(cluster, routesCleanupFinalizer) {
-		if err := g.cleanUnusedRoutes(cluster); err != nil {
-			return nil, err
+	// the network can only be deleted once all other resources in it are gone
+	if kuberneteshelper.HasFinalizer(cluster, networkCleanupFinalizer) {
+		if err := deleteNetwork(svc, projectID, g.dc, cluster); err != nil {
+			return nil, fmt.Errorf("failed to delete network %s: %v", cluster.Spec.Cloud.GCP.Network, err)
 		}
 		cluster, err = update(cluster.Name, func(cluster *kubermaticv1.Cluster) {
-			kuberneteshelper.RemoveFinalizer(cluster, routesCleanupFinalizer)
+			kuberneteshelper.RemoveFinalizer(cluster, networkCleanupFinalizer)
 		})
 		if err != nil {
-			return nil, fmt.Errorf("failed to remove %s finalizer: %v", routesCleanupFinalizer, err)
+			return nil, fmt.Errorf("failed to remove %s finalizer: %v", networkCleanupFinalizer, err)
 		}
 	}
 
@@ -293,6 +299,36 @@ func GetGKECLusterConfig(ctx context.Context, sa, clusterName, zone string) (*ap
 	return &config, nil
 }
 
+// GetGKEClusterStatus returns the status reported by GKE for the given cluster.
+// provider.ErrNotFound is returned when the cluster does not exist.
+func GetGKEClusterStatus(ctx context.Context, sa, clusterName, zone string) (string, error) {
+	svc, project, err := ConnectToContainerService(sa)
+	if err != nil {
+		return "", err
+	}
+	cluster, err := svc.Projects.Zones.Clusters.Get(project, zone, clusterName).Context(ctx).Do()
+	if err != nil {
+		if isHTTPError(err, http.StatusNotFound) {
+			return "", provider.ErrNotFound
+		}
+		return "", fmt.Errorf("cannot get cluster for project=%s: %w", project, err)
+	}
+	return cluster.Status, nil
+}
+
+// DeleteGKECluster starts the deletion of the given GKE cluster. Clusters that do not exist are ignored.
+func DeleteGKECluster(ctx context.Context, sa, clusterName, zone string) error {
+	svc, project, err := ConnectToContainerService(sa)
+	if err != nil {
+		return err
+	}
+	_, err = svc.Projects.Zones.Clusters.Delete(project, zone, clusterName).Context(ctx).Do()
+	if err != nil && !isHTTPError(err, http.StatusNotFound) {
+		return fmt.Errorf("cannot delete cluster for project=%s: %w", project, err)
+	}
+	return nil
+}
+
 func createClient(ctx context.Context, serviceAccount string, scope string) (*http.Client, string, error) {
 	b, err := base64.StdEncoding.DecodeString(serviceAccount)
 	if err != nil {
@@ -318,142 +354,8 @@ func createClient(ctx context.Context, serviceAccount string, scope string) (*ht
 	return client, projectID, nil
 }
 
-func (g *gcp) ensureFirewallRules(cluster *kubermaticv1.Cluster, update provider.ClusterUpdater) error {
-	serviceAccount, err := GetCredentialsForCluster(cluster.Spec.Cloud, g.secretKeySelector)
-	if err != nil {
-		return err
-	}
-
-	svc, projectID, err := ConnectToComputeService(serviceAccount)
-	if err != nil {
-		return err
-	}
-
-	// Retrieve nodePort range from cluster
-	nodePortRangeLow, nodePortRangeHigh := resources.NewTemplateDataBuilder().
-		WithNodePortRange(cluster.Spec.ComponentsOverride.Apiserver.NodePortRange).
-		WithCluster(cluster).
-		Build().
-		NodePorts()
-
-	nodePortsAllowedIPRange := cluster.Spec.Cloud.GCP.NodePortsAllowedIPRange
-	if nodePortsAllowedIPRange == "" {
-		nodePortsAllowedIPRange = "0.0.0.0/0"
-	}
-
-	firewallService := compute.NewFirewallsService(svc)
-	tag := fmt.Sprintf("kubernetes-cluster-%s", cluster.Name)
-	selfRuleName := fmt.Sprintf("firewall-%s-self", cluster.Name)
-	icmpRuleName := fmt.Sprintf("firewall-%s-icmp", cluster.Name)
-	nodePortRuleName := fmt.Sprintf("firewall-%s-nodeport", cluster.Name)
-
-	// allow traffic within the same cluster
-	if !kuberneteshelper.HasFinalizer(cluster, firewallSelfCleanupFinalizer) {
-		_, err = firewallService.Insert(projectID, &compute.Firewall{
-			Name:    selfRuleName,
-			Network: cluster.Spec.Cloud.GCP.Network,
-			Allowed: []*compute.FirewallAllowed{
-				{
-					IPProtocol: "tcp",
-				},
-				{
-					IPProtocol: "udp",
-				},
-				{
-					IPProtocol: "icmp",
-				},
-				{
-					IPProtocol: "esp",
-				},
-				{
-					IPProtocol: "ah",
-				},
-				{
-					IPProtocol: "sctp",
-				},
-				{
-					IPProtocol: "ipip",
-				},
-			},
-			TargetTags: []string{tag},
-			SourceTags: []string{tag},
-		}).Do()
-		// we ignore a Google API "already exists" error
-		if err != nil && !isHTTPError(err, http.StatusConflict) {
-			return fmt.Errorf("failed to create firewall rule %s: %v", selfRuleName, err)
-		}
-
-		cluster, err = update(cluster.Name, func(cluster *kubermaticv1.Cluster) {
-			kuberneteshelper.AddFinalizer(cluster, firewallSelfCleanupFinalizer)
-		})
-		if err != nil {
-			return fmt.Errorf("failed to add %s finalizer: %v", firewallSelfCleanupFinalizer, err)
-		}
-	}
-
-	// allow ICMP from everywhere
-	if !kuberneteshelper.HasFinalizer(cluster, firewallICMPCleanupFinalizer) {
-		_, err = firewallService.Insert(projectID, &compute.Firewall{
-			Name:    icmpRuleName,
-			Network: cluster.Spec.Cloud.GCP.Network,
-			Allowed: []*compute.FirewallAllowed{
-				{
-					IPProtocol: "icmp",
-				},
-			},
-			TargetTags: []string{tag},
-		}).Do()
-		// we ignore a Google API "already exists" error
-		if err != nil && !isHTTPError(err, http.StatusConflict) {
-			return fmt.Errorf("failed to create firewall rule %s: %v", icmpRuleName, err)
-		}
-
-		newCluster, err := update(cluster.Name, func(cluster *kubermaticv1.Cluster) {
-			kuberneteshelper.AddFinalizer(cluster, firewallICMPCleanupFinalizer)
-		})
-		if err != nil {
-			return fmt.Errorf("failed to add %s finalizer: %v", firewallICMPCleanupFinalizer, err)
-		}
-		*cluster = *newCluster
-	}
-
-	// open nodePorts for TCP and UDP
-	if !kuberneteshelper.HasFinalizer(cluster, firewallNodePortCleanupFinalizer) {
-		_, err = firewallService.Insert(projectID, &compute.Firewall{
-			Name:    nodePortRuleName,
-			Network: cluster.Spec.Cloud.GCP.Network,
-			Allowed: []*compute.FirewallAllowed{
-				{
-					IPProtocol: "tcp",
-					Ports:      []string{fmt.Sprintf("%d-%d", nodePortRangeLow, nodePortRangeHigh)},
-				},
-				{
-					IPProtocol: "udp",
-					Ports:      []string{fmt.Sprintf("%d-%d", nodePortRangeLow, nodePortRangeHigh)},
-				},
-			},
-			TargetTags:   []string{tag},
-			SourceRanges: []string{nodePortsAllowedIPRange},
-		}).Do()
-		// we ignore a Google API "already exists" error
-		if err != nil && !isHTTPError(err, http.StatusConflict) {
-			return fmt.Errorf("failed to create firewall rule %s: %v", nodePortRuleName, err)
-		}
-
-		newCluster, err := update(cluster.Name, func(cluster *kubermaticv1.Cluster) {
-			kuberneteshelper.AddFinalizer(cluster, firewallNodePortCleanupFinalizer)
-		})
-		if err != nil {
-			return fmt.Errorf("failed to add %s finalizer: %v", firewallNodePortCleanupFinalizer, err)
-		}
-		*cluster = *newCluster
-	}
-
-	return err
-}
-
 // ValidateCloudSpecUpdate verifies whether an update of cloud spec is valid and permitted
-func (g *gcp) ValidateCloudSpecUpdate(oldSpec kubermaticv1.CloudSpec, newSpec kubermaticv1.CloudSpec) error {
+func (g *GCP) ValidateCloudSpecUpdate(oldSpec kubermaticv1.CloudSpec, newSpec kubermaticv1.CloudSpec) error {
 	return nil
 }
 
@@ -474,6 +376,27 @@ func GetCredentialsForCluster(cloud kubermaticv1.CloudSpec, secretKeySelector pr
 	return serviceAccount, nil
 }
 
+// waitForOperation waits until the given global or regional operation is done.
+func waitForOperation(svc *compute.Service, projectID string, op *compute.Operation) error {
+	name := op.Name
+	for op.Status != "DONE" {
+		var err error
+		if op.Region != "" {
+			op, err = svc.RegionOperations.Wait(projectID, path.Base(op.Region), name).Do()
+		} else {
+			op, err = svc.GlobalOperations.Wait(projectID, name).Do()
+		}
+		if err != nil {
+			return fmt.Errorf("failed to wait for operation %s: %v", name, err)
+		}
+	}
+
+	if op.Error != nil && len(op.Error.Errors) > 0 {
+		return fmt.Errorf("operation %s failed: %s", op.Name, op.Error.Errors[0].Message)
+	}
+	return nil
+}
+
 // isHTTPError returns true if the given error is of a specific HTTP status code.
 func isHTTPError(err error, status int) bool {
 	gerr, ok := err.(*googleapi.Error)
@@ -481,7 +404,7 @@ func isHTTPError(err error, status int) bool {
 }
 
 // cleanUnusedRoutes finds and remove unused gcp routes
-func (g *gcp) cleanUnusedRoutes(cluster *kubermaticv1.Cluster) error {
+func (g *GCP) cleanUnusedRoutes(cluster *kubermaticv1.Cluster) error {
 	serviceAccount, err := GetCredentialsForCluster(cluster.Spec.Cloud, g.secretKeySelector)
 	if err != nil {
 		return fmt.Errorf("failed to get GCP service account: %v", err)
@@ -522,7 +445,7 @@ func (g *gcp) cleanUnusedRoutes(cluster *kubermaticv1.Cluster) error {
 }
 
 // networkURL checks the network name and retuen the network URL based on it
-func (g *gcp) networkURL(project, network string) string {
+func (g *GCP) networkURL(project, network string) string {
 	url, err := url.Parse(network)
 	if err == nil && url.Host != "" {
 		return network
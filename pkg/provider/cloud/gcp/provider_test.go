@@ -22,6 +22,8 @@ import (
 	"google.golang.org/api/compute/v1"
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestIsClusterRoute(t *testing.T) {
@@ -68,3 +70,36 @@ func TestIsClusterRoute(t *testing.T) {
 		})
 	}
 }
+
+func TestFirewallRuleEqual(t *testing.T) {
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "abcd"},
+		Spec: kubermaticv1.ClusterSpec{
+			Cloud: kubermaticv1.CloudSpec{GCP: &kubermaticv1.GCPCloudSpec{Network: DefaultNetwork}},
+		},
+	}
+
+	for _, rule := range firewallRules(cluster, 30000, 32767) {
+		// the API returns the allowed protocols in any order
+		existing := *rule.firewall
+		existing.Allowed = nil
+		for i := len(rule.firewall.Allowed) - 1; i >= 0; i-- {
+			existing.Allowed = append(existing.Allowed, rule.firewall.Allowed[i])
+		}
+		if !firewallRuleEqual(&existing, rule.firewall) {
+			t.Errorf("expected firewall rule %s to be unchanged", rule.firewall.Name)
+		}
+	}
+
+	nodePortRule := firewallRules(cluster, 30000, 32767)[2].firewall
+
+	cluster.Spec.Cloud.GCP.NodePortsAllowedIPRange = "10.0.0.0/8"
+	if firewallRuleEqual(nodePortRule, firewallRules(cluster, 30000, 32767)[2].firewall) {
+		t.Error("expected a changed nodeport source range to be detected")
+	}
+
+	cluster.Spec.Cloud.GCP.NodePortsAllowedIPRange = ""
+	if firewallRuleEqual(nodePortRule, firewallRules(cluster, 30000, 31000)[2].firewall) {
+		t.Error("expected a changed nodeport range to be detected")
+	}
+}
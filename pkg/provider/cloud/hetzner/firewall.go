@@ -0,0 +1,185 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hetzner
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/hetznercloud/hcloud-go/hcloud"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/provider"
+	"k8c.io/kubermatic/v2/pkg/resources"
+)
+
+func firewallName(cluster *kubermaticv1.Cluster) string {
+	if cluster.Spec.Cloud.Hetzner.Firewall != "" {
+		return cluster.Spec.Cloud.Hetzner.Firewall
+	}
+	return resourceNamePrefix + cluster.Name
+}
+
+func reconcileFirewall(ctx context.Context, client *hcloud.Client, cluster *kubermaticv1.Cluster, update provider.ClusterUpdater) (*kubermaticv1.Cluster, error) {
+	name := firewallName(cluster)
+
+	firewall, _, err := client.Firewall.GetByName(ctx, name)
+	if err != nil {
+		return cluster, fmt.Errorf("failed to get firewall %q: %w", name, err)
+	}
+
+	// a pre-existing firewall chosen by the user is not reconciled
+	if firewall != nil && !isOwnedBy(firewall.Labels, cluster) {
+		if cluster.Spec.Cloud.Hetzner.Firewall == "" {
+			return cluster, fmt.Errorf("firewall %q already exists, but is not owned by the cluster", name)
+		}
+		return cluster, nil
+	}
+
+	lowPort, highPort := getNodePortRange(cluster)
+	rules := firewallRules(lowPort, highPort)
+
+	if firewall == nil {
+		if _, _, err := client.Firewall.Create(ctx, hcloud.FirewallCreateOpts{
+			Name:   name,
+			Labels: ownershipLabels(cluster),
+			Rules:  rules,
+		}); err != nil {
+			return cluster, fmt.Errorf("failed to create firewall %q: %w", name, err)
+		}
+	} else if !rulesEqual(firewall.Rules, rules) {
+		if _, _, err := client.Firewall.SetRules(ctx, firewall, hcloud.FirewallSetRulesOpts{Rules: rules}); err != nil {
+			return cluster, fmt.Errorf("failed to update rules of firewall %q: %w", name, err)
+		}
+	}
+
+	return update(cluster.Name, func(updatedCluster *kubermaticv1.Cluster) {
+		updatedCluster.Spec.Cloud.Hetzner.Firewall = name
+		kuberneteshelper.AddFinalizer(updatedCluster, FinalizerFirewall)
+	})
+}
+
+func getNodePortRange(cluster *kubermaticv1.Cluster) (int, int) {
+	return resources.NewTemplateDataBuilder().
+		WithNodePortRange(cluster.Spec.ComponentsOverride.Apiserver.NodePortRange).
+		WithCluster(cluster).
+		Build().
+		NodePorts()
+}
+
+// firewallRules returns the inbound rules of the machines' public interfaces. Outbound traffic
+// and traffic within the private network is not filtered by Hetzner Cloud firewalls.
+func firewallRules(lowPort, highPort int) []hcloud.FirewallRule {
+	_, anyIPv4, _ := net.ParseCIDR("0.0.0.0/0")
+	_, anyIPv6, _ := net.ParseCIDR("::/0")
+	anywhere := []net.IPNet{*anyIPv4, *anyIPv6}
+
+	sshPort := fmt.Sprintf("%d", provider.DefaultSSHPort)
+	nodePorts := fmt.Sprintf("%d-%d", lowPort, highPort)
+
+	return []hcloud.FirewallRule{
+		{
+			Direction:   hcloud.FirewallRuleDirectionIn,
+			Protocol:    hcloud.FirewallRuleProtocolTCP,
+			Port:        &sshPort,
+			SourceIPs:   anywhere,
+			Description: hcloud.String("ssh"),
+		},
+		{
+			Direction:   hcloud.FirewallRuleDirectionIn,
+			Protocol:    hcloud.FirewallRuleProtocolICMP,
+			SourceIPs:   anywhere,
+			Description: hcloud.String("icmp"),
+		},
+		{
+			Direction:   hcloud.FirewallRuleDirectionIn,
+			Protocol:    hcloud.FirewallRuleProtocolTCP,
+			Port:        &nodePorts,
+			SourceIPs:   anywhere,
+			Description: hcloud.String("tcp nodeports"),
+		},
+		{
+			Direction:   hcloud.FirewallRuleDirectionIn,
+			Protocol:    hcloud.FirewallRuleProtocolUDP,
+			Port:        &nodePorts,
+			SourceIPs:   anywhere,
+			Description: hcloud.String("udp nodeports"),
+		},
+	}
+}
+
+// rulesEqual compares the rules regardless of their order and descriptions.
+func rulesEqual(a, b []hcloud.FirewallRule) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	keys := func(rules []hcloud.FirewallRule) []string {
+		result := make([]string, len(rules))
+		for i, rule := range rules {
+			result[i] = ruleKey(rule)
+		}
+		sort.Strings(result)
+		return result
+	}
+
+	aKeys, bKeys := keys(a), keys(b)
+	for i := range aKeys {
+		if aKeys[i] != bKeys[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func ruleKey(rule hcloud.FirewallRule) string {
+	ips := func(nets []net.IPNet) string {
+		result := make([]string, len(nets))
+		for i, n := range nets {
+			result[i] = n.String()
+		}
+		sort.Strings(result)
+		return strings.Join(result, ",")
+	}
+
+	port := ""
+	if rule.Port != nil {
+		port = *rule.Port
+	}
+
+	return fmt.Sprintf("%s/%s/%s/%s/%s", rule.Direction, rule.Protocol, port, ips(rule.SourceIPs), ips(rule.DestinationIPs))
+}
+
+func deleteFirewall(ctx context.Context, client *hcloud.Client, cluster *kubermaticv1.Cluster) error {
+	firewall, _, err := client.Firewall.GetByName(ctx, firewallName(cluster))
+	if err != nil {
+		return err
+	}
+	if firewall == nil || !isOwnedBy(firewall.Labels, cluster) {
+		return nil
+	}
+
+	_, err = client.Firewall.Delete(ctx, firewall)
+	if hcloud.IsError(err, hcloud.ErrorCodeNotFound) {
+		return nil
+	}
+	return err
+}
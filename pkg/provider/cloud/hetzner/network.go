@@ -0,0 +1,132 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hetzner
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/hetznercloud/hcloud-go/hcloud"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/provider"
+)
+
+const (
+	networkIPRange = "10.0.0.0/16"
+	subnetIPRange  = "10.0.0.0/24"
+)
+
+func networkName(cluster *kubermaticv1.Cluster) string {
+	if cluster.Spec.Cloud.Hetzner.Network != "" {
+		return cluster.Spec.Cloud.Hetzner.Network
+	}
+	return resourceNamePrefix + cluster.Name
+}
+
+func reconcileNetwork(ctx context.Context, client *hcloud.Client, dc *kubermaticv1.DatacenterSpecHetzner, cluster *kubermaticv1.Cluster, update provider.ClusterUpdater) (*kubermaticv1.Cluster, error) {
+	name := networkName(cluster)
+
+	network, _, err := client.Network.GetByName(ctx, name)
+	if err != nil {
+		return cluster, fmt.Errorf("failed to get network %q: %w", name, err)
+	}
+
+	// a pre-existing network chosen by the user is not reconciled
+	if network != nil && !isOwnedBy(network.Labels, cluster) {
+		if cluster.Spec.Cloud.Hetzner.Network == "" {
+			return cluster, fmt.Errorf("network %q already exists, but is not owned by the cluster", name)
+		}
+		return cluster, nil
+	}
+
+	zone, err := networkZone(ctx, client, dc)
+	if err != nil {
+		return cluster, err
+	}
+	subnet := targetSubnet(zone)
+
+	if network == nil {
+		_, ipRange, _ := net.ParseCIDR(networkIPRange)
+		if _, _, err := client.Network.Create(ctx, hcloud.NetworkCreateOpts{
+			Name:    name,
+			IPRange: ipRange,
+			Subnets: []hcloud.NetworkSubnet{subnet},
+			Labels:  ownershipLabels(cluster),
+		}); err != nil {
+			return cluster, fmt.Errorf("failed to create network %q: %w", name, err)
+		}
+	} else if !hasSubnet(network, subnet) {
+		if _, _, err := client.Network.AddSubnet(ctx, network, hcloud.NetworkAddSubnetOpts{Subnet: subnet}); err != nil {
+			return cluster, fmt.Errorf("failed to add subnet to network %q: %w", name, err)
+		}
+	}
+
+	return update(cluster.Name, func(updatedCluster *kubermaticv1.Cluster) {
+		updatedCluster.Spec.Cloud.Hetzner.Network = name
+		kuberneteshelper.AddFinalizer(updatedCluster, FinalizerNetwork)
+	})
+}
+
+// networkZone returns the network zone of the datacenter's location, subnets can only be used
+// by servers in the same zone.
+func networkZone(ctx context.Context, client *hcloud.Client, dc *kubermaticv1.DatacenterSpecHetzner) (hcloud.NetworkZone, error) {
+	datacenter, _, err := client.Datacenter.GetByName(ctx, dc.Datacenter)
+	if err != nil {
+		return "", fmt.Errorf("failed to get datacenter %q: %w", dc.Datacenter, err)
+	}
+	if datacenter == nil || datacenter.Location == nil {
+		return "", fmt.Errorf("datacenter %q not found", dc.Datacenter)
+	}
+	return datacenter.Location.NetworkZone, nil
+}
+
+func targetSubnet(zone hcloud.NetworkZone) hcloud.NetworkSubnet {
+	_, ipRange, _ := net.ParseCIDR(subnetIPRange)
+	return hcloud.NetworkSubnet{
+		Type:        hcloud.NetworkSubnetTypeCloud,
+		IPRange:     ipRange,
+		NetworkZone: zone,
+	}
+}
+
+func hasSubnet(network *hcloud.Network, subnet hcloud.NetworkSubnet) bool {
+	for _, existing := range network.Subnets {
+		if existing.NetworkZone == subnet.NetworkZone && existing.IPRange != nil && existing.IPRange.String() == subnet.IPRange.String() {
+			return true
+		}
+	}
+	return false
+}
+
+func deleteNetwork(ctx context.Context, client *hcloud.Client, cluster *kubermaticv1.Cluster) error {
+	network, _, err := client.Network.GetByName(ctx, networkName(cluster))
+	if err != nil {
+		return err
+	}
+	if network == nil || !isOwnedBy(network.Labels, cluster) {
+		return nil
+	}
+
+	_, err = client.Network.Delete(ctx, network)
+	if hcloud.IsError(err, hcloud.ErrorCodeNotFound) {
+		return nil
+	}
+	return err
+}
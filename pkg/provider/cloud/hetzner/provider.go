@@ -19,27 +19,62 @@ package hetzner
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/hetznercloud/hcloud-go/hcloud"
+	"go.uber.org/zap"
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/log"
 	"k8c.io/kubermatic/v2/pkg/provider"
 	"k8c.io/kubermatic/v2/pkg/resources"
 )
 
+const (
+	resourceNamePrefix = "kubernetes-"
+
+	// clusterLabelKey marks the resources created for and owned by a cluster.
+	clusterLabelKey = "kubermatic-cluster"
+
+	// FinalizerNetwork will instruct the deletion of the network
+	FinalizerNetwork = "kubermatic.io/cleanup-hetzner-network"
+	// FinalizerFirewall will instruct the deletion of the firewall
+	FinalizerFirewall = "kubermatic.io/cleanup-hetzner-firewall"
+)
+
 type hetzner struct {
+	dc                *kubermaticv1.DatacenterSpecHetzner
+	log               *zap.SugaredLogger
+	ctx               context.Context
 	secretKeySelector provider.SecretKeySelectorValueFunc
 }
 
 // NewCloudProvider creates a new hetzner provider.
-func NewCloudProvider(secretKeyGetter provider.SecretKeySelectorValueFunc) provider.CloudProvider {
+func NewCloudProvider(dc *kubermaticv1.Datacenter, secretKeyGetter provider.SecretKeySelectorValueFunc) (provider.CloudProvider, error) {
+	if dc.Spec.Hetzner == nil {
+		return nil, errors.New("datacenter is not a Hetzner datacenter")
+	}
+
 	return &hetzner{
+		dc:                dc.Spec.Hetzner,
+		log:               log.Logger,
+		ctx:               context.TODO(),
 		secretKeySelector: secretKeyGetter,
-	}
+	}, nil
 }
 
-var _ provider.CloudProvider = &hetzner{}
+var _ provider.ReconcilingCloudProvider = &hetzner{}
+
+func (h *hetzner) getClient(cloud kubermaticv1.CloudSpec) (*hcloud.Client, error) {
+	hetznerToken, err := GetCredentialsForCluster(cloud, h.secretKeySelector)
+	if err != nil {
+		return nil, err
+	}
+
+	return hcloud.NewClient(hcloud.WithToken(hetznerToken)), nil
+}
 
 // DefaultCloudSpec
 func (h *hetzner) DefaultCloudSpec(spec *kubermaticv1.CloudSpec) error {
@@ -48,13 +83,11 @@ func (h *hetzner) DefaultCloudSpec(spec *kubermaticv1.CloudSpec) error {
 
 // ValidateCloudSpec
 func (h *hetzner) ValidateCloudSpec(spec kubermaticv1.CloudSpec) error {
-	hetznerToken, err := GetCredentialsForCluster(spec, h.secretKeySelector)
+	client, err := h.getClient(spec)
 	if err != nil {
 		return err
 	}
 
-	client := hcloud.NewClient(hcloud.WithToken(hetznerToken))
-
 	timeout, cancel := context.WithTimeout(context.TODO(), 10*time.Second)
 	defer cancel()
 
@@ -65,17 +98,96 @@ func (h *hetzner) ValidateCloudSpec(spec kubermaticv1.CloudSpec) error {
 		// this validates network and implicitly the token
 		_, _, err = client.Network.GetByName(timeout, spec.Hetzner.Network)
 	}
+	if err != nil {
+		return err
+	}
+
+	if spec.Hetzner.Firewall != "" {
+		firewall, _, err := client.Firewall.GetByName(timeout, spec.Hetzner.Firewall)
+		if err != nil {
+			return err
+		}
+		if firewall == nil {
+			return fmt.Errorf("firewall %q not found", spec.Hetzner.Firewall)
+		}
+	}
 
-	return err
+	return nil
 }
 
 // InitializeCloudProvider
 func (h *hetzner) InitializeCloudProvider(cluster *kubermaticv1.Cluster, update provider.ClusterUpdater) (*kubermaticv1.Cluster, error) {
+	return h.reconcileCluster(cluster, update, false)
+}
+
+// ReconcileCluster
+func (h *hetzner) ReconcileCluster(cluster *kubermaticv1.Cluster, update provider.ClusterUpdater) (*kubermaticv1.Cluster, error) {
+	return h.reconcileCluster(cluster, update, true)
+}
+
+func (h *hetzner) reconcileCluster(cluster *kubermaticv1.Cluster, update provider.ClusterUpdater, force bool) (*kubermaticv1.Cluster, error) {
+	logger := h.log.With("cluster", cluster.Name)
+
+	client, err := h.getClient(cluster.Spec.Cloud)
+	if err != nil {
+		return nil, err
+	}
+
+	// a network configured on the datacenter is shared by all its clusters and not managed by us
+	if (force || cluster.Spec.Cloud.Hetzner.Network == "") && (cluster.Spec.Cloud.Hetzner.Network != "" || h.dc.Network == "") {
+		logger.Infow("reconciling network", "network", networkName(cluster))
+		cluster, err = reconcileNetwork(h.ctx, client, h.dc, cluster, update)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if force || cluster.Spec.Cloud.Hetzner.Firewall == "" {
+		logger.Infow("reconciling firewall", "firewall", firewallName(cluster))
+		cluster, err = reconcileFirewall(h.ctx, client, cluster, update)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return cluster, nil
 }
 
 // CleanUpCloudProvider
-func (h *hetzner) CleanUpCloudProvider(cluster *kubermaticv1.Cluster, _ provider.ClusterUpdater) (*kubermaticv1.Cluster, error) {
+func (h *hetzner) CleanUpCloudProvider(cluster *kubermaticv1.Cluster, update provider.ClusterUpdater) (*kubermaticv1.Cluster, error) {
+	logger := h.log.With("cluster", cluster.Name)
+
+	client, err := h.getClient(cluster.Spec.Cloud)
+	if err != nil {
+		return nil, err
+	}
+
+	if kuberneteshelper.HasFinalizer(cluster, FinalizerFirewall) {
+		logger.Infow("deleting firewall", "firewall", cluster.Spec.Cloud.Hetzner.Firewall)
+		if err := deleteFirewall(h.ctx, client, cluster); err != nil {
+			return cluster, fmt.Errorf("failed to delete firewall %q: %v", cluster.Spec.Cloud.Hetzner.Firewall, err)
+		}
+		cluster, err = update(cluster.Name, func(updatedCluster *kubermaticv1.Cluster) {
+			kuberneteshelper.RemoveFinalizer(updatedCluster, FinalizerFirewall)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if kuberneteshelper.HasFinalizer(cluster, FinalizerNetwork) {
+		logger.Infow("deleting network", "network", cluster.Spec.Cloud.Hetzner.Network)
+		if err := deleteNetwork(h.ctx, client, cluster); err != nil {
+			return cluster, fmt.Errorf("failed to delete network %q: %v", cluster.Spec.Cloud.Hetzner.Network, err)
+		}
+		cluster, err = update(cluster.Name, func(updatedCluster *kubermaticv1.Cluster) {
+			kuberneteshelper.RemoveFinalizer(updatedCluster, FinalizerNetwork)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return cluster, nil
 }
 
@@ -100,3 +212,12 @@ func GetCredentialsForCluster(cloud kubermaticv1.CloudSpec, secretKeySelector pr
 
 	return hetznerToken, nil
 }
+
+// isOwnedBy returns true if the resource with the given labels was created for the cluster.
+func isOwnedBy(labels map[string]string, cluster *kubermaticv1.Cluster) bool {
+	return labels[clusterLabelKey] == cluster.Name
+}
+
+func ownershipLabels(cluster *kubermaticv1.Cluster) map[string]string {
+	return map[string]string{clusterLabelKey: cluster.Name}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hetzner
+
+import (
+	"net"
+	"testing"
+
+	"github.com/hetznercloud/hcloud-go/hcloud"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testCluster(spec kubermaticv1.HetznerCloudSpec) *kubermaticv1.Cluster {
+	return &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "abcd"},
+		Spec: kubermaticv1.ClusterSpec{
+			Cloud: kubermaticv1.CloudSpec{Hetzner: &spec},
+		},
+	}
+}
+
+func TestResourceNames(t *testing.T) {
+	cluster := testCluster(kubermaticv1.HetznerCloudSpec{})
+	if name := networkName(cluster); name != "kubernetes-abcd" {
+		t.Errorf("expected generated network name, got %q", name)
+	}
+	if name := firewallName(cluster); name != "kubernetes-abcd" {
+		t.Errorf("expected generated firewall name, got %q", name)
+	}
+
+	cluster = testCluster(kubermaticv1.HetznerCloudSpec{Network: "shared", Firewall: "custom"})
+	if name := networkName(cluster); name != "shared" {
+		t.Errorf("expected configured network name, got %q", name)
+	}
+	if name := firewallName(cluster); name != "custom" {
+		t.Errorf("expected configured firewall name, got %q", name)
+	}
+}
+
+func TestIsOwnedBy(t *testing.T) {
+	cluster := testCluster(kubermaticv1.HetznerCloudSpec{})
+
+	if !isOwnedBy(ownershipLabels(cluster), cluster) {
+		t.Error("expected resources with the ownership labels to be owned by the cluster")
+	}
+	if isOwnedBy(map[string]string{clusterLabelKey: "other"}, cluster) {
+		t.Error("expected resources of other clusters not to be owned by the cluster")
+	}
+	if isOwnedBy(nil, cluster) {
+		t.Error("expected unlabeled resources not to be owned by the cluster")
+	}
+}
+
+func TestRulesEqual(t *testing.T) {
+	rules := firewallRules(30000, 32767)
+
+	reversed := make([]hcloud.FirewallRule, len(rules))
+	for i, rule := range rules {
+		rule.Description = nil
+		reversed[len(rules)-1-i] = rule
+	}
+	if !rulesEqual(rules, reversed) {
+		t.Error("expected rules to be equal regardless of order and descriptions")
+	}
+
+	if rulesEqual(rules, firewallRules(30000, 31000)) {
+		t.Error("expected changed node port range to be detected")
+	}
+	if rulesEqual(rules, rules[1:]) {
+		t.Error("expected missing rule to be detected")
+	}
+}
+
+func TestHasSubnet(t *testing.T) {
+	subnet := targetSubnet(hcloud.NetworkZoneEUCentral)
+	_, otherRange, _ := net.ParseCIDR("10.1.0.0/24")
+
+	network := &hcloud.Network{Subnets: []hcloud.NetworkSubnet{
+		{Type: hcloud.NetworkSubnetTypeCloud, IPRange: otherRange, NetworkZone: hcloud.NetworkZoneEUCentral},
+	}}
+	if hasSubnet(network, subnet) {
+		t.Error("expected subnet with a different range not to match")
+	}
+
+	network.Subnets = append(network.Subnets, targetSubnet(hcloud.NetworkZoneEUCentral))
+	if !hasSubnet(network, subnet) {
+		t.Error("expected subnet to be found")
+	}
+}
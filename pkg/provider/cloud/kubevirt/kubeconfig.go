@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubevirt
+
+import (
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// scopedKubeconfig builds a kubeconfig for the service account token in the given secret. The address
+// of the infra cluster is taken from its admin kubeconfig. The namespace of the cluster is set as the
+// default namespace, as the KubeVirt cloud controller manager uses it for its resources.
+func scopedKubeconfig(adminKubeconfig []byte, secret *corev1.Secret, namespace string) ([]byte, error) {
+	admin, err := clientcmd.Load(adminKubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kubeconfig: %w", err)
+	}
+
+	kubeContext, ok := admin.Contexts[admin.CurrentContext]
+	if !ok {
+		return nil, fmt.Errorf("kubeconfig has no context %q", admin.CurrentContext)
+	}
+	cluster, ok := admin.Clusters[kubeContext.Cluster]
+	if !ok {
+		return nil, fmt.Errorf("kubeconfig has no cluster %q", kubeContext.Cluster)
+	}
+
+	token := secret.Data[corev1.ServiceAccountTokenKey]
+	if len(token) == 0 {
+		return nil, errors.New("service account token has not been issued yet")
+	}
+
+	const name = "kubevirt"
+	config := clientcmdapi.NewConfig()
+	config.Clusters[name] = &clientcmdapi.Cluster{
+		Server:                   cluster.Server,
+		CertificateAuthorityData: secret.Data[corev1.ServiceAccountRootCAKey],
+	}
+	config.AuthInfos[name] = &clientcmdapi.AuthInfo{
+		Token: string(token),
+	}
+	config.Contexts[name] = &clientcmdapi.Context{
+		Cluster:   name,
+		AuthInfo:  name,
+		Namespace: namespace,
+	}
+	config.CurrentContext = name
+
+	return clientcmd.Write(*config)
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubevirt
+
+import (
+	"context"
+	"fmt"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/reconciling"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	resourceQuotaName  = "kubermatic-cluster"
+	networkPolicyName  = "kubermatic-cluster-isolation"
+	serviceAccountName = "kubermatic-cluster"
+	tokenSecretName    = "kubermatic-cluster-token"
+)
+
+func namespaceName(cluster *kubermaticv1.Cluster) string {
+	return "cluster-" + cluster.Name
+}
+
+// reconcileNamespace ensures the namespace of the cluster in the KubeVirt infra cluster, together with
+// its resource quota, the network isolation and the service account used by the cluster.
+func reconcileNamespace(ctx context.Context, client ctrlruntimeclient.Client, dc *kubermaticv1.DatacenterSpecKubevirt, cluster *kubermaticv1.Cluster) error {
+	namespace := namespaceName(cluster)
+
+	if err := reconciling.ReconcileNamespaces(ctx, []reconciling.NamedNamespaceCreatorGetter{namespaceCreator(cluster)}, "", client); err != nil {
+		return err
+	}
+
+	if err := reconcileResourceQuota(ctx, client, namespace, dc.ResourceQuota); err != nil {
+		return err
+	}
+
+	if err := reconciling.ReconcileNetworkPolicies(ctx, []reconciling.NamedNetworkPolicyCreatorGetter{networkPolicyCreator}, namespace, client); err != nil {
+		return err
+	}
+
+	if err := reconciling.ReconcileServiceAccounts(ctx, []reconciling.NamedServiceAccountCreatorGetter{serviceAccountCreator}, namespace, client); err != nil {
+		return err
+	}
+
+	if err := reconciling.ReconcileRoles(ctx, []reconciling.NamedRoleCreatorGetter{roleCreator}, namespace, client); err != nil {
+		return err
+	}
+
+	if err := reconciling.ReconcileRoleBindings(ctx, []reconciling.NamedRoleBindingCreatorGetter{roleBindingCreator(namespace)}, namespace, client); err != nil {
+		return err
+	}
+
+	return reconciling.ReconcileSecrets(ctx, []reconciling.NamedSecretCreatorGetter{tokenSecretCreator}, namespace, client)
+}
+
+func namespaceCreator(cluster *kubermaticv1.Cluster) reconciling.NamedNamespaceCreatorGetter {
+	return func() (string, reconciling.NamespaceCreator) {
+		return namespaceName(cluster), func(ns *corev1.Namespace) (*corev1.Namespace, error) {
+			if ns.Labels == nil {
+				ns.Labels = map[string]string{}
+			}
+			ns.Labels[resources.ClusterLabelKey] = cluster.Name
+
+			return ns, nil
+		}
+	}
+}
+
+// reconcileResourceQuota ensures the quota configured in the datacenter. There is no
+// reconciling helper for resource quotas, so they are handled here.
+func reconcileResourceQuota(ctx context.Context, client ctrlruntimeclient.Client, namespace string, hard corev1.ResourceList) error {
+	quota := &corev1.ResourceQuota{}
+	err := client.Get(ctx, ctrlruntimeclient.ObjectKey{Namespace: namespace, Name: resourceQuotaName}, quota)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get resource quota: %w", err)
+	}
+	exists := err == nil
+
+	if len(hard) == 0 {
+		if exists {
+			if err := client.Delete(ctx, quota); err != nil && !kerrors.IsNotFound(err) {
+				return fmt.Errorf("failed to delete resource quota: %w", err)
+			}
+		}
+		return nil
+	}
+
+	if !exists {
+		quota = &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      resourceQuotaName,
+				Namespace: namespace,
+			},
+			Spec: corev1.ResourceQuotaSpec{
+				Hard: hard,
+			},
+		}
+		if err := client.Create(ctx, quota); err != nil {
+			return fmt.Errorf("failed to create resource quota: %w", err)
+		}
+		return nil
+	}
+
+	if equality.Semantic.DeepEqual(quota.Spec.Hard, hard) {
+		return nil
+	}
+
+	quota.Spec.Hard = hard
+	if err := client.Update(ctx, quota); err != nil {
+		return fmt.Errorf("failed to update resource quota: %w", err)
+	}
+
+	return nil
+}
+
+// networkPolicyCreator isolates the virtual machines of a cluster from the ones of all other clusters:
+// ingress is only allowed from the same namespace and from namespaces that do not belong to a user
+// cluster, like the ones of an ingress controller or of KubeVirt itself.
+func networkPolicyCreator() (string, reconciling.NetworkPolicyCreator) {
+	return networkPolicyName, func(np *networkingv1.NetworkPolicy) (*networkingv1.NetworkPolicy, error) {
+		np.Spec = networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					From: []networkingv1.NetworkPolicyPeer{
+						{
+							PodSelector: &metav1.LabelSelector{},
+						},
+						{
+							NamespaceSelector: &metav1.LabelSelector{
+								MatchExpressions: []metav1.LabelSelectorRequirement{
+									{
+										Key:      resources.ClusterLabelKey,
+										Operator: metav1.LabelSelectorOpDoesNotExist,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		return np, nil
+	}
+}
+
+func serviceAccountCreator() (string, reconciling.ServiceAccountCreator) {
+	return serviceAccountName, func(sa *corev1.ServiceAccount) (*corev1.ServiceAccount, error) {
+		return sa, nil
+	}
+}
+
+// roleCreator grants the permissions machine-controller and the KubeVirt cloud controller manager
+// need to manage the virtual machines, their disks and load balancers of a cluster.
+func roleCreator() (string, reconciling.RoleCreator) {
+	return serviceAccountName, func(r *rbacv1.Role) (*rbacv1.Role, error) {
+		r.Rules = []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"configmaps", "events", "persistentvolumeclaims", "pods", "secrets", "services"},
+				Verbs:     []string{"*"},
+			},
+			{
+				APIGroups: []string{"kubevirt.io"},
+				Resources: []string{"virtualmachines", "virtualmachineinstances"},
+				Verbs:     []string{"*"},
+			},
+			{
+				APIGroups: []string{"subresources.kubevirt.io"},
+				Resources: []string{"*"},
+				Verbs:     []string{"*"},
+			},
+			{
+				APIGroups: []string{"cdi.kubevirt.io"},
+				Resources: []string{"datavolumes"},
+				Verbs:     []string{"*"},
+			},
+		}
+
+		return r, nil
+	}
+}
+
+func roleBindingCreator(namespace string) reconciling.NamedRoleBindingCreatorGetter {
+	return func() (string, reconciling.RoleBindingCreator) {
+		return serviceAccountName, func(rb *rbacv1.RoleBinding) (*rbacv1.RoleBinding, error) {
+			rb.RoleRef = rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "Role",
+				Name:     serviceAccountName,
+			}
+			rb.Subjects = []rbacv1.Subject{
+				{
+					Kind:      rbacv1.ServiceAccountKind,
+					Name:      serviceAccountName,
+					Namespace: namespace,
+				},
+			}
+
+			return rb, nil
+		}
+	}
+}
+
+// tokenSecretCreator requests a token for the service account; its data is filled in by the
+// token controller of the infra cluster.
+func tokenSecretCreator() (string, reconciling.SecretCreator) {
+	return tokenSecretName, func(s *corev1.Secret) (*corev1.Secret, error) {
+		if s.Annotations == nil {
+			s.Annotations = map[string]string{}
+		}
+		s.Annotations[corev1.ServiceAccountNameKey] = serviceAccountName
+		s.Type = corev1.SecretTypeServiceAccountToken
+
+		return s, nil
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubevirt
+
+import (
+	"context"
+	"testing"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileNamespace(t *testing.T) {
+	ctx := context.Background()
+	client := fakectrlruntimeclient.NewClientBuilder().Build()
+	cluster := &kubermaticv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "abcd"}}
+	dc := &kubermaticv1.DatacenterSpecKubevirt{
+		ResourceQuota: corev1.ResourceList{
+			corev1.ResourceRequestsCPU: resource.MustParse("8"),
+		},
+	}
+
+	if err := reconcileNamespace(ctx, client, dc, cluster); err != nil {
+		t.Fatalf("failed to reconcile namespace: %v", err)
+	}
+
+	ns := &corev1.Namespace{}
+	if err := client.Get(ctx, ctrlruntimeclient.ObjectKey{Name: "cluster-abcd"}, ns); err != nil {
+		t.Fatalf("failed to get namespace: %v", err)
+	}
+	if ns.Labels[resources.ClusterLabelKey] != cluster.Name {
+		t.Errorf("expected namespace to be labelled with the cluster name, got labels %v", ns.Labels)
+	}
+
+	quota := &corev1.ResourceQuota{}
+	quotaKey := ctrlruntimeclient.ObjectKey{Namespace: ns.Name, Name: resourceQuotaName}
+	if err := client.Get(ctx, quotaKey, quota); err != nil {
+		t.Fatalf("failed to get resource quota: %v", err)
+	}
+	if cpu := quota.Spec.Hard[corev1.ResourceRequestsCPU]; cpu.String() != "8" {
+		t.Errorf("expected a CPU quota of 8, got %s", cpu.String())
+	}
+
+	secret := &corev1.Secret{}
+	if err := client.Get(ctx, ctrlruntimeclient.ObjectKey{Namespace: ns.Name, Name: tokenSecretName}, secret); err != nil {
+		t.Fatalf("failed to get token secret: %v", err)
+	}
+	if secret.Type != corev1.SecretTypeServiceAccountToken || secret.Annotations[corev1.ServiceAccountNameKey] != serviceAccountName {
+		t.Errorf("expected a token secret for service account %q, got %v", serviceAccountName, secret)
+	}
+
+	// updating the datacenter must update the quota
+	dc.ResourceQuota[corev1.ResourceRequestsCPU] = resource.MustParse("16")
+	if err := reconcileNamespace(ctx, client, dc, cluster); err != nil {
+		t.Fatalf("failed to reconcile namespace: %v", err)
+	}
+	if err := client.Get(ctx, quotaKey, quota); err != nil {
+		t.Fatalf("failed to get resource quota: %v", err)
+	}
+	if cpu := quota.Spec.Hard[corev1.ResourceRequestsCPU]; cpu.String() != "16" {
+		t.Errorf("expected a CPU quota of 16, got %s", cpu.String())
+	}
+
+	// removing it from the datacenter must remove the quota
+	dc.ResourceQuota = nil
+	if err := reconcileNamespace(ctx, client, dc, cluster); err != nil {
+		t.Fatalf("failed to reconcile namespace: %v", err)
+	}
+	if err := client.Get(ctx, quotaKey, quota); !kerrors.IsNotFound(err) {
+		t.Errorf("expected resource quota to be deleted, got %v", err)
+	}
+}
@@ -17,44 +17,85 @@ limitations under the License.
 package kubevirt
 
 import (
+	"context"
 	"encoding/base64"
 	"errors"
+	"fmt"
 
-	v1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/log"
 	"k8c.io/kubermatic/v2/pkg/provider"
 	"k8c.io/kubermatic/v2/pkg/resources"
 
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/clientcmd"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-type kubevirt struct {
+const (
+	// FinalizerNamespace will instruct the deletion of the cluster namespace in the KubeVirt infra cluster
+	FinalizerNamespace = "kubermatic.io/cleanup-kubevirt-namespace"
+
+	// SharedNamespaceAnnotation marks clusters that were created before every cluster got its own
+	// namespace in the KubeVirt infra cluster. Their virtual machines live in the namespaces configured
+	// in the node specs, so these clusters keep using the kubeconfig of the infra cluster.
+	SharedNamespaceAnnotation = "kubevirt.kubermatic.io/shared-namespace"
+)
+
+// Provider manages the namespace of a user cluster in the KubeVirt infra cluster.
+type Provider struct {
+	dc                *kubermaticv1.DatacenterSpecKubevirt
+	log               *zap.SugaredLogger
+	ctx               context.Context
 	secretKeySelector provider.SecretKeySelectorValueFunc
 }
 
-func NewCloudProvider(secretKeyGetter provider.SecretKeySelectorValueFunc) provider.CloudProvider {
-	return &kubevirt{
-		secretKeySelector: secretKeyGetter,
+// NewCloudProvider creates a new kubevirt provider.
+func NewCloudProvider(dc *kubermaticv1.Datacenter, secretKeyGetter provider.SecretKeySelectorValueFunc) (provider.CloudProvider, error) {
+	if dc.Spec.Kubevirt == nil {
+		return nil, errors.New("datacenter is not a KubeVirt datacenter")
 	}
+
+	return &Provider{
+		dc:                dc.Spec.Kubevirt,
+		log:               log.Logger,
+		ctx:               context.TODO(),
+		secretKeySelector: secretKeyGetter,
+	}, nil
 }
 
-var _ provider.CloudProvider = &kubevirt{}
+var _ provider.ReconcilingCloudProvider = &Provider{}
+
+func (k *Provider) getClient(cloud kubermaticv1.CloudSpec) (ctrlruntimeclient.Client, error) {
+	kubeconfig, err := GetCredentialsForCluster(cloud, k.secretKeySelector)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := clientcmd.RESTConfigFromKubeConfig(decodeKubeconfig(kubeconfig))
+	if err != nil {
+		return nil, err
+	}
 
-func (k *kubevirt) DefaultCloudSpec(spec *v1.CloudSpec) error {
+	return ctrlruntimeclient.New(config, ctrlruntimeclient.Options{})
+}
+
+func (k *Provider) DefaultCloudSpec(spec *kubermaticv1.CloudSpec) error {
 	return nil
 }
 
-func (k *kubevirt) ValidateCloudSpec(spec v1.CloudSpec) error {
+func (k *Provider) ValidateCloudSpec(spec kubermaticv1.CloudSpec) error {
 	kubeconfig, err := GetCredentialsForCluster(spec, k.secretKeySelector)
 	if err != nil {
 		return err
 	}
 
-	config, err := base64.StdEncoding.DecodeString(kubeconfig)
-	if err != nil {
-		// if the decoding failed, the kubeconfig is sent already decoded without the need of decoding it,
-		// for example the value has been read from Vault during the ci tests, which is saved as json format.
-		config = []byte(kubeconfig)
-	}
+	config := decodeKubeconfig(kubeconfig)
 
 	_, err = clientcmd.RESTConfigFromKubeConfig(config)
 	if err != nil {
@@ -66,20 +107,110 @@ func (k *kubevirt) ValidateCloudSpec(spec v1.CloudSpec) error {
 	return nil
 }
 
-func (k *kubevirt) InitializeCloudProvider(c *v1.Cluster, p provider.ClusterUpdater) (*v1.Cluster, error) {
-	return c, nil
+func (k *Provider) InitializeCloudProvider(cluster *kubermaticv1.Cluster, update provider.ClusterUpdater) (*kubermaticv1.Cluster, error) {
+	return k.reconcileCluster(cluster, update, false)
+}
+
+// ReconcileCluster
+func (k *Provider) ReconcileCluster(cluster *kubermaticv1.Cluster, update provider.ClusterUpdater) (*kubermaticv1.Cluster, error) {
+	return k.reconcileCluster(cluster, update, true)
+}
+
+func (k *Provider) reconcileCluster(cluster *kubermaticv1.Cluster, update provider.ClusterUpdater, force bool) (*kubermaticv1.Cluster, error) {
+	if !isIsolated(cluster) {
+		return cluster, nil
+	}
+
+	if !force && kuberneteshelper.HasFinalizer(cluster, FinalizerNamespace) && cluster.Spec.Cloud.Kubevirt.Namespace != "" {
+		return cluster, nil
+	}
+
+	client, err := k.getClient(cluster.Spec.Cloud)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := namespaceName(cluster)
+	k.log.With("cluster", cluster.Name).Infow("reconciling namespace in KubeVirt infra cluster", "namespace", namespace)
+
+	// add the finalizer first, so the namespace gets cleaned up even if one of the following steps fails
+	cluster, err = update(cluster.Name, func(c *kubermaticv1.Cluster) {
+		kuberneteshelper.AddFinalizer(c, FinalizerNamespace)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := reconcileNamespace(k.ctx, client, k.dc, cluster); err != nil {
+		return nil, err
+	}
+
+	return update(cluster.Name, func(c *kubermaticv1.Cluster) {
+		c.Spec.Cloud.Kubevirt.Namespace = namespace
+	})
 }
 
-func (k *kubevirt) CleanUpCloudProvider(c *v1.Cluster, p provider.ClusterUpdater) (*v1.Cluster, error) {
-	return c, nil
+func (k *Provider) CleanUpCloudProvider(cluster *kubermaticv1.Cluster, update provider.ClusterUpdater) (*kubermaticv1.Cluster, error) {
+	if !kuberneteshelper.HasFinalizer(cluster, FinalizerNamespace) {
+		return cluster, nil
+	}
+
+	client, err := k.getClient(cluster.Spec.Cloud)
+	if err != nil {
+		return nil, err
+	}
+
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: namespaceName(cluster),
+		},
+	}
+	if err := client.Delete(k.ctx, ns); err != nil && !kerrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to delete namespace %q: %w", ns.Name, err)
+	}
+
+	return update(cluster.Name, func(c *kubermaticv1.Cluster) {
+		kuberneteshelper.RemoveFinalizer(c, FinalizerNamespace)
+	})
 }
 
-func (k *kubevirt) ValidateCloudSpecUpdate(oldSpec v1.CloudSpec, newSpec v1.CloudSpec) error {
+func (k *Provider) ValidateCloudSpecUpdate(oldSpec kubermaticv1.CloudSpec, newSpec kubermaticv1.CloudSpec) error {
 	return nil
 }
 
+// ScopedKubeconfig returns a kubeconfig for the KubeVirt infra cluster, which is only allowed to manage
+// the virtual machines in the namespace of the given cluster.
+func (k *Provider) ScopedKubeconfig(cluster *kubermaticv1.Cluster) (string, error) {
+	kubeconfig, err := GetCredentialsForCluster(cluster.Spec.Cloud, k.secretKeySelector)
+	if err != nil {
+		return "", err
+	}
+
+	client, err := k.getClient(cluster.Spec.Cloud)
+	if err != nil {
+		return "", err
+	}
+
+	secret := &corev1.Secret{}
+	if err := client.Get(k.ctx, ctrlruntimeclient.ObjectKey{Namespace: namespaceName(cluster), Name: tokenSecretName}, secret); err != nil {
+		return "", fmt.Errorf("failed to get service account token: %w", err)
+	}
+
+	config, err := scopedKubeconfig(decodeKubeconfig(kubeconfig), secret, namespaceName(cluster))
+	if err != nil {
+		return "", err
+	}
+
+	return string(config), nil
+}
+
+// isIsolated returns whether the cluster gets its own namespace in the KubeVirt infra cluster.
+func isIsolated(cluster *kubermaticv1.Cluster) bool {
+	return cluster.Annotations[SharedNamespaceAnnotation] != "true"
+}
+
 // GetCredentialsForCluster returns the credentials for the passed in cloud spec or an error
-func GetCredentialsForCluster(cloud v1.CloudSpec, secretKeySelector provider.SecretKeySelectorValueFunc) (kubeconfig string, err error) {
+func GetCredentialsForCluster(cloud kubermaticv1.CloudSpec, secretKeySelector provider.SecretKeySelectorValueFunc) (kubeconfig string, err error) {
 	kubeconfig = cloud.Kubevirt.Kubeconfig
 
 	if kubeconfig == "" {
@@ -94,3 +225,14 @@ func GetCredentialsForCluster(cloud v1.CloudSpec, secretKeySelector provider.Sec
 
 	return kubeconfig, nil
 }
+
+func decodeKubeconfig(kubeconfig string) []byte {
+	config, err := base64.StdEncoding.DecodeString(kubeconfig)
+	if err != nil {
+		// if the decoding failed, the kubeconfig is sent already decoded without the need of decoding it,
+		// for example the value has been read from Vault during the ci tests, which is saved as json format.
+		config = []byte(kubeconfig)
+	}
+
+	return config
+}
@@ -61,19 +61,19 @@ func Provider(
 		return packet.NewCloudProvider(secretKeyGetter), nil
 	}
 	if datacenter.Spec.Hetzner != nil {
-		return hetzner.NewCloudProvider(secretKeyGetter), nil
+		return hetzner.NewCloudProvider(datacenter, secretKeyGetter)
 	}
 	if datacenter.Spec.VSphere != nil {
 		return vsphere.NewCloudProvider(datacenter, secretKeyGetter, caBundle)
 	}
 	if datacenter.Spec.GCP != nil {
-		return gcp.NewCloudProvider(secretKeyGetter), nil
+		return gcp.NewCloudProvider(datacenter, secretKeyGetter)
 	}
 	if datacenter.Spec.Fake != nil {
 		return fake.NewCloudProvider(), nil
 	}
 	if datacenter.Spec.Kubevirt != nil {
-		return kubevirt.NewCloudProvider(secretKeyGetter), nil
+		return kubevirt.NewCloudProvider(datacenter, secretKeyGetter)
 	}
 	if datacenter.Spec.Alibaba != nil {
 		return alibaba.NewCloudProvider(datacenter, secretKeyGetter)
@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PrivilegedClusterMigrationProvider struct that holds required components in order to manage cluster migrations
+type PrivilegedClusterMigrationProvider struct {
+	clientPrivileged ctrlruntimeclient.Client
+}
+
+// NewClusterMigrationPrivilegedProvider returns a cluster migration provider
+func NewClusterMigrationPrivilegedProvider(client ctrlruntimeclient.Client) (*PrivilegedClusterMigrationProvider, error) {
+	return &PrivilegedClusterMigrationProvider{
+		clientPrivileged: client,
+	}, nil
+}
+
+// CreateUnsecured creates a cluster migration
+func (p *PrivilegedClusterMigrationProvider) CreateUnsecured(migration *kubermaticv1.ClusterMigration) (*kubermaticv1.ClusterMigration, error) {
+	if err := p.clientPrivileged.Create(context.Background(), migration); err != nil {
+		return nil, err
+	}
+
+	return migration, nil
+}
+
+// ListUnsecured lists the migrations of the given cluster
+func (p *PrivilegedClusterMigrationProvider) ListUnsecured(clusterName string) (*kubermaticv1.ClusterMigrationList, error) {
+	allMigrations := &kubermaticv1.ClusterMigrationList{}
+	if err := p.clientPrivileged.List(context.Background(), allMigrations); err != nil {
+		return nil, err
+	}
+
+	migrations := &kubermaticv1.ClusterMigrationList{}
+	for _, migration := range allMigrations.Items {
+		if migration.Spec.ClusterName == clusterName {
+			migrations.Items = append(migrations.Items, migration)
+		}
+	}
+
+	return migrations, nil
+}
@@ -35,8 +35,12 @@ import (
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-// CreateOrUpdateCredentialSecretForCluster creates a new secret for a credential.
+// CreateOrUpdateCredentialSecretForCluster creates a new secret for a credential. The credentials of the
+// audit sinks are moved into a secret of their own.
 func CreateOrUpdateCredentialSecretForCluster(ctx context.Context, seedClient ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster) error {
+	if err := createOrUpdateAuditSinksSecret(ctx, seedClient, cluster); err != nil {
+		return err
+	}
 	if cluster.Spec.Cloud.AWS != nil {
 		return createOrUpdateAWSSecret(ctx, seedClient, cluster)
 	}
@@ -74,8 +78,10 @@ func CreateOrUpdateCredentialSecretForCluster(ctx context.Context, seedClient ct
 }
 
 func ensureCredentialSecret(ctx context.Context, seedClient ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster, secretData map[string][]byte) (*providerconfig.GlobalSecretKeySelector, error) {
-	name := cluster.GetSecretName()
+	return ensureSecret(ctx, seedClient, cluster, cluster.GetSecretName(), secretData)
+}
 
+func ensureSecret(ctx context.Context, seedClient ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster, name string, secretData map[string][]byte) (*providerconfig.GlobalSecretKeySelector, error) {
 	namespacedName := types.NamespacedName{Namespace: resources.KubermaticNamespace, Name: name}
 	existingSecret := &corev1.Secret{}
 	if err := seedClient.Get(ctx, namespacedName, existingSecret); err != nil && !kerrors.IsNotFound(err) {
@@ -465,3 +471,104 @@ func createOrUpdateAnexiaSecret(ctx context.Context, seedClient ctrlruntimeclien
 
 	return nil
 }
+
+// createOrUpdateAuditSinksSecret moves the inline credentials of the audit sinks of the cluster into
+// the audit sinks credentials secret of the cluster. The credentials of every sink are stored with the
+// name of the sink as prefix, which is the key of its credentials reference.
+func createOrUpdateAuditSinksSecret(ctx context.Context, seedClient ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster) error {
+	if cluster.Spec.AuditLogging == nil {
+		return nil
+	}
+
+	name := cluster.GetAuditSinksSecretName()
+	existingSecret := &corev1.Secret{}
+	if err := seedClient.Get(ctx, types.NamespacedName{Namespace: resources.KubermaticNamespace, Name: name}, existingSecret); err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("failed to probe for secret %q: %v", name, err)
+	}
+
+	secretData := map[string][]byte{}
+	migrated := false
+	for i := range cluster.Spec.AuditLogging.Sinks {
+		sink := &cluster.Spec.AuditLogging.Sinks[i]
+		ref := &providerconfig.GlobalSecretKeySelector{Key: sink.Name}
+
+		var credentials map[string]*string
+		switch {
+		case sink.Webhook != nil:
+			credentials = map[string]*string{resources.AuditSinkToken: &sink.Webhook.Token}
+		case sink.HTTP != nil:
+			credentials = map[string]*string{resources.AuditSinkUsername: &sink.HTTP.Username, resources.AuditSinkPassword: &sink.HTTP.Password}
+		case sink.Loki != nil:
+			credentials = map[string]*string{resources.AuditSinkUsername: &sink.Loki.Username, resources.AuditSinkPassword: &sink.Loki.Password}
+		case sink.S3 != nil:
+			credentials = map[string]*string{resources.AWSAccessKeyID: &sink.S3.AccessKeyID, resources.AWSSecretAccessKey: &sink.S3.SecretAccessKey}
+		}
+
+		inline := false
+		for _, value := range credentials {
+			if *value != "" {
+				inline = true
+			}
+		}
+
+		for key, value := range credentials {
+			secretKey := resources.AuditSinkCredentialKey(ref, key)
+			if inline {
+				secretData[secretKey] = []byte(*value)
+				*value = ""
+			} else if existingValue, ok := existingSecret.Data[secretKey]; ok && auditSinkCredentialsReference(sink) != nil {
+				// keep the credentials that were moved into the secret before
+				secretData[secretKey] = existingValue
+			}
+		}
+
+		if inline {
+			migrated = true
+			setAuditSinkCredentialsReference(sink, ref)
+		}
+	}
+
+	// already migrated
+	if !migrated {
+		return nil
+	}
+
+	secretRef, err := ensureSecret(ctx, seedClient, cluster, name, secretData)
+	if err != nil {
+		return err
+	}
+	for i := range cluster.Spec.AuditLogging.Sinks {
+		if ref := auditSinkCredentialsReference(&cluster.Spec.AuditLogging.Sinks[i]); ref != nil && ref.Name == "" {
+			ref.ObjectReference = secretRef.ObjectReference
+		}
+	}
+
+	return nil
+}
+
+func auditSinkCredentialsReference(sink *kubermaticv1.AuditSink) *providerconfig.GlobalSecretKeySelector {
+	switch {
+	case sink.Webhook != nil:
+		return sink.Webhook.CredentialsReference
+	case sink.HTTP != nil:
+		return sink.HTTP.CredentialsReference
+	case sink.Loki != nil:
+		return sink.Loki.CredentialsReference
+	case sink.S3 != nil:
+		return sink.S3.CredentialsReference
+	}
+	return nil
+}
+
+func setAuditSinkCredentialsReference(sink *kubermaticv1.AuditSink, ref *providerconfig.GlobalSecretKeySelector) {
+	switch {
+	case sink.Webhook != nil:
+		sink.Webhook.CredentialsReference = ref
+	case sink.HTTP != nil:
+		sink.HTTP.CredentialsReference = ref
+	case sink.Loki != nil:
+		sink.Loki.CredentialsReference = ref
+	case sink.S3 != nil:
+		sink.S3.CredentialsReference = ref
+	}
+}
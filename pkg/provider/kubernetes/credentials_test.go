@@ -0,0 +1,151 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	providerconfig "github.com/kubermatic/machine-controller/pkg/providerconfig/types"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCreateOrUpdateAuditSinksSecret(t *testing.T) {
+	ownSecret := corev1.ObjectReference{Name: "credential-audit-sinks-abcd", Namespace: resources.KubermaticNamespace}
+
+	testCases := []struct {
+		name         string
+		sinks        []kubermaticv1.AuditSink
+		existingData map[string][]byte
+		expectedRefs map[string]*providerconfig.GlobalSecretKeySelector
+		expectedData map[string]string
+	}{
+		{
+			name:         "no credentials",
+			sinks:        []kubermaticv1.AuditSink{{Name: "syslog", Syslog: &kubermaticv1.AuditSyslogSink{Address: "syslog.example.com:514"}}},
+			expectedRefs: map[string]*providerconfig.GlobalSecretKeySelector{"syslog": nil},
+		},
+		{
+			name: "inline credentials are moved into the secret",
+			sinks: []kubermaticv1.AuditSink{
+				{Name: "loki", Loki: &kubermaticv1.AuditLokiSink{URL: "https://loki.example.com", Username: "audit", Password: "loki-password"}},
+				{Name: "s3", S3: &kubermaticv1.AuditS3Sink{Bucket: "audit", Region: "eu-central-1", AccessKeyID: "id", SecretAccessKey: "s3-secret"}},
+			},
+			expectedRefs: map[string]*providerconfig.GlobalSecretKeySelector{
+				"loki": {ObjectReference: ownSecret, Key: "loki"},
+				"s3":   {ObjectReference: ownSecret, Key: "s3"},
+			},
+			expectedData: map[string]string{
+				"loki-username":      "audit",
+				"loki-password":      "loki-password",
+				"s3-accessKeyId":     "id",
+				"s3-secretAccessKey": "s3-secret",
+			},
+		},
+		{
+			name: "credentials of other sinks are kept",
+			sinks: []kubermaticv1.AuditSink{
+				{Name: "webhook", Webhook: &kubermaticv1.AuditWebhookSink{URL: "https://audit.example.com", Token: "new-token"}},
+				{Name: "http", HTTP: &kubermaticv1.AuditHTTPSink{URL: "https://audit.example.com", CredentialsReference: &providerconfig.GlobalSecretKeySelector{ObjectReference: ownSecret, Key: "http"}}},
+			},
+			existingData: map[string][]byte{
+				"webhook-token": []byte("old-token"),
+				"http-username": []byte("audit"),
+				"http-password": []byte("http-password"),
+				"removed-token": []byte("removed"),
+			},
+			expectedRefs: map[string]*providerconfig.GlobalSecretKeySelector{
+				"webhook": {ObjectReference: ownSecret, Key: "webhook"},
+				"http":    {ObjectReference: ownSecret, Key: "http"},
+			},
+			expectedData: map[string]string{
+				"webhook-token": "new-token",
+				"http-username": "audit",
+				"http-password": "http-password",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			cluster := &kubermaticv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "abcd",
+					Labels: map[string]string{kubermaticv1.ProjectIDLabelKey: "project"},
+				},
+				Spec: kubermaticv1.ClusterSpec{
+					AuditLogging: &kubermaticv1.AuditLoggingSettings{Enabled: true, Sinks: tc.sinks},
+				},
+			}
+
+			var objects []ctrlruntimeclient.Object
+			if tc.existingData != nil {
+				objects = append(objects, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: ownSecret.Name, Namespace: ownSecret.Namespace},
+					Data:       tc.existingData,
+				})
+			}
+			client := fakectrlruntimeclient.NewClientBuilder().WithObjects(objects...).Build()
+
+			if err := createOrUpdateAuditSinksSecret(ctx, client, cluster); err != nil {
+				t.Fatalf("failed to create audit sinks secret: %v", err)
+			}
+
+			for _, sink := range cluster.Spec.AuditLogging.Sinks {
+				sink := sink
+				ref := auditSinkCredentialsReference(&sink)
+				expected := tc.expectedRefs[sink.Name]
+				if (ref == nil) != (expected == nil) || (ref != nil && *ref != *expected) {
+					t.Errorf("expected sink %q to reference %v, got %v", sink.Name, expected, ref)
+				}
+			}
+			for _, sink := range cluster.Spec.AuditLogging.Sinks {
+				if (sink.Webhook != nil && sink.Webhook.Token != "") || (sink.Loki != nil && sink.Loki.Password != "") || (sink.S3 != nil && sink.S3.SecretAccessKey != "") {
+					t.Errorf("expected the inline credentials of sink %q to be removed", sink.Name)
+				}
+			}
+
+			secret := &corev1.Secret{}
+			err := client.Get(ctx, types.NamespacedName{Name: ownSecret.Name, Namespace: ownSecret.Namespace}, secret)
+			if tc.expectedData == nil {
+				if err == nil {
+					t.Fatal("expected no audit sinks secret to be created")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to get audit sinks secret: %v", err)
+			}
+			if len(secret.Data) != len(tc.expectedData) {
+				t.Errorf("expected secret data with keys %v, got %d keys", tc.expectedData, len(secret.Data))
+			}
+			for key, value := range tc.expectedData {
+				if string(secret.Data[key]) != value {
+					t.Errorf("expected %q in key %q, got %q", value, key, secret.Data[key])
+				}
+			}
+		})
+	}
+}
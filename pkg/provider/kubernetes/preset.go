@@ -22,10 +22,12 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"time"
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1/helper"
 	"k8c.io/kubermatic/v2/pkg/provider"
+	"k8c.io/kubermatic/v2/pkg/secretstore"
 	"k8c.io/kubermatic/v2/pkg/util/email"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -45,6 +47,12 @@ type presetUpdater = func(preset *kubermaticv1.Preset) (*kubermaticv1.Preset, er
 // presetDeleter is a function to delete a preset
 type presetDeleter = func(preset *kubermaticv1.Preset) (*kubermaticv1.Preset, error)
 
+// presetResolver is a function to resolve the credentials kept in an external secret store
+type presetResolver = func(ctx context.Context, preset *kubermaticv1.Preset) (*kubermaticv1.Preset, error)
+
+// presetLeaseGetter is a function to get the lease of the credentials kept in an external secret store
+type presetLeaseGetter = func(ctx context.Context, preset *kubermaticv1.Preset) (time.Duration, error)
+
 // LoadPresets loads the custom presets for supported providers
 func LoadPresets(yamlContent []byte) (*kubermaticv1.PresetList, error) {
 	s := struct {
@@ -147,15 +155,58 @@ func presetDeleterFactory(ctx context.Context, client ctrlruntimeclient.Client)
 	}, nil
 }
 
+func presetResolverFactory(secretStore secretstore.Store) presetResolver {
+	return func(ctx context.Context, preset *kubermaticv1.Preset) (*kubermaticv1.Preset, error) {
+		if preset.Spec.ExternalSecrets == nil {
+			return preset, nil
+		}
+		if secretStore == nil {
+			return nil, fmt.Errorf("preset %s references external secrets, but no secret store is configured", preset.Name)
+		}
+
+		secret, err := secretStore.Read(ctx, preset.Spec.ExternalSecrets.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the credentials of preset %s: %v", preset.Name, err)
+		}
+
+		// presets can be shared with other callers, e.g. when they are loaded from a file
+		resolved := preset.DeepCopy()
+		if err := helper.SetExternalCredentials(resolved, secret.Data); err != nil {
+			return nil, fmt.Errorf("invalid credentials for preset %s: %v", preset.Name, err)
+		}
+
+		return resolved, nil
+	}
+}
+
+func presetLeaseGetterFactory(secretStore secretstore.Store) presetLeaseGetter {
+	return func(ctx context.Context, preset *kubermaticv1.Preset) (time.Duration, error) {
+		if preset.Spec.ExternalSecrets == nil || secretStore == nil {
+			return 0, nil
+		}
+
+		secret, err := secretStore.Read(ctx, preset.Spec.ExternalSecrets.Path)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read the credentials of preset %s: %v", preset.Name, err)
+		}
+
+		return secret.LeaseDuration, nil
+	}
+}
+
 // PresetProvider is a object to handle presets from a predefined config
 type PresetProvider struct {
-	getter  presetsGetter
-	creator presetCreator
-	patcher presetUpdater
-	deleter presetDeleter
+	getter      presetsGetter
+	creator     presetCreator
+	patcher     presetUpdater
+	deleter     presetDeleter
+	resolver    presetResolver
+	leaseGetter presetLeaseGetter
 }
 
-func NewPresetProvider(ctx context.Context, client ctrlruntimeclient.Client, presetsFile string, dynamicPresets bool) (*PresetProvider, error) {
+// NewPresetProvider returns a PresetProvider. The secretStore is used to resolve credentials
+// of presets that reference external secrets, it can be nil if no external store is used.
+func NewPresetProvider(ctx context.Context, client ctrlruntimeclient.Client, presetsFile string, dynamicPresets bool, secretStore secretstore.Store) (*PresetProvider, error) {
 	getter, err := presetsGetterFactory(ctx, client, presetsFile, dynamicPresets)
 	if err != nil {
 		return nil, err
@@ -176,7 +227,10 @@ func NewPresetProvider(ctx context.Context, client ctrlruntimeclient.Client, pre
 		return nil, err
 	}
 
-	return &PresetProvider{getter, creator, patcher, deleter}, nil
+	resolver := presetResolverFactory(secretStore)
+	leaseGetter := presetLeaseGetterFactory(secretStore)
+
+	return &PresetProvider{getter, creator, patcher, deleter, resolver, leaseGetter}, nil
 }
 
 func (m *PresetProvider) CreatePreset(preset *kubermaticv1.Preset) (*kubermaticv1.Preset, error) {
@@ -192,8 +246,26 @@ func (m *PresetProvider) GetPresets(userInfo *provider.UserInfo) ([]kubermaticv1
 	return m.getter(userInfo)
 }
 
-// GetPreset returns preset with the name which belong to the specific email group
-func (m *PresetProvider) GetPreset(userInfo *provider.UserInfo, name string) (*kubermaticv1.Preset, error) {
+// GetPreset returns preset with the name which belong to the specific email group. Credentials kept
+// in an external secret store are resolved.
+func (m *PresetProvider) GetPreset(ctx context.Context, userInfo *provider.UserInfo, name string) (*kubermaticv1.Preset, error) {
+	preset, err := m.GetStoredPreset(userInfo, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.resolver(ctx, preset)
+}
+
+// GetCredentialsLease returns how long the credentials of the preset kept in an external secret store
+// are leased for. It is zero if the credentials are not leased and valid until they are changed.
+func (m *PresetProvider) GetCredentialsLease(ctx context.Context, preset *kubermaticv1.Preset) (time.Duration, error) {
+	return m.leaseGetter(ctx, preset)
+}
+
+// GetStoredPreset returns preset with the name which belong to the specific email group as it is stored,
+// without resolving external credentials.
+func (m *PresetProvider) GetStoredPreset(userInfo *provider.UserInfo, name string) (*kubermaticv1.Preset, error) {
 	presets, err := m.getter(userInfo)
 	if err != nil {
 		return nil, err
@@ -226,6 +298,11 @@ func filterOutPresets(userInfo *provider.UserInfo, list *kubermaticv1.PresetList
 	var result []kubermaticv1.Preset
 
 	for _, preset := range list.Items {
+		if userInfo.IsAdmin {
+			result = append(result, preset)
+			continue
+		}
+
 		requirements := preset.Spec.RequiredEmails
 		if legacy := preset.Spec.RequiredEmailDomain; len(legacy) != 0 {
 			requirements = append(requirements, legacy)
@@ -236,7 +313,7 @@ func filterOutPresets(userInfo *provider.UserInfo, list *kubermaticv1.PresetList
 			return nil, err
 		}
 
-		if matches || userInfo.IsAdmin {
+		if matches {
 			result = append(result, preset)
 		}
 	}
@@ -244,43 +321,43 @@ func filterOutPresets(userInfo *provider.UserInfo, list *kubermaticv1.PresetList
 	return result, nil
 }
 
-func (m *PresetProvider) SetCloudCredentials(userInfo *provider.UserInfo, presetName string, cloud kubermaticv1.CloudSpec, dc *kubermaticv1.Datacenter) (*kubermaticv1.CloudSpec, error) {
+func (m *PresetProvider) SetCloudCredentials(ctx context.Context, userInfo *provider.UserInfo, presetName string, cloud kubermaticv1.CloudSpec, dc *kubermaticv1.Datacenter) (*kubermaticv1.CloudSpec, error) {
 
 	if cloud.VSphere != nil {
-		return m.setVsphereCredentials(userInfo, presetName, cloud, dc)
+		return m.setVsphereCredentials(ctx, userInfo, presetName, cloud, dc)
 	}
 	if cloud.Openstack != nil {
-		return m.setOpenStackCredentials(userInfo, presetName, cloud, dc)
+		return m.setOpenStackCredentials(ctx, userInfo, presetName, cloud, dc)
 	}
 	if cloud.Azure != nil {
-		return m.setAzureCredentials(userInfo, presetName, cloud)
+		return m.setAzureCredentials(ctx, userInfo, presetName, cloud)
 	}
 	if cloud.Digitalocean != nil {
-		return m.setDigitalOceanCredentials(userInfo, presetName, cloud)
+		return m.setDigitalOceanCredentials(ctx, userInfo, presetName, cloud)
 	}
 	if cloud.Packet != nil {
-		return m.setPacketCredentials(userInfo, presetName, cloud)
+		return m.setPacketCredentials(ctx, userInfo, presetName, cloud)
 	}
 	if cloud.Hetzner != nil {
-		return m.setHetznerCredentials(userInfo, presetName, cloud)
+		return m.setHetznerCredentials(ctx, userInfo, presetName, cloud)
 	}
 	if cloud.AWS != nil {
-		return m.setAWSCredentials(userInfo, presetName, cloud)
+		return m.setAWSCredentials(ctx, userInfo, presetName, cloud)
 	}
 	if cloud.GCP != nil {
-		return m.setGCPCredentials(userInfo, presetName, cloud)
+		return m.setGCPCredentials(ctx, userInfo, presetName, cloud)
 	}
 	if cloud.Fake != nil {
-		return m.setFakeCredentials(userInfo, presetName, cloud)
+		return m.setFakeCredentials(ctx, userInfo, presetName, cloud)
 	}
 	if cloud.Kubevirt != nil {
-		return m.setKubevirtCredentials(userInfo, presetName, cloud)
+		return m.setKubevirtCredentials(ctx, userInfo, presetName, cloud)
 	}
 	if cloud.Alibaba != nil {
-		return m.setAlibabaCredentials(userInfo, presetName, cloud)
+		return m.setAlibabaCredentials(ctx, userInfo, presetName, cloud)
 	}
 	if cloud.Anexia != nil {
-		return m.setAnexiaCredentials(userInfo, presetName, cloud)
+		return m.setAnexiaCredentials(ctx, userInfo, presetName, cloud)
 	}
 
 	return nil, fmt.Errorf("can not find provider to set credentials")
@@ -290,8 +367,8 @@ func emptyCredentialError(preset, provider string) error {
 	return fmt.Errorf("the preset %s doesn't contain credential for %s provider", preset, provider)
 }
 
-func (m *PresetProvider) setFakeCredentials(userInfo *provider.UserInfo, presetName string, cloud kubermaticv1.CloudSpec) (*kubermaticv1.CloudSpec, error) {
-	preset, err := m.GetPreset(userInfo, presetName)
+func (m *PresetProvider) setFakeCredentials(ctx context.Context, userInfo *provider.UserInfo, presetName string, cloud kubermaticv1.CloudSpec) (*kubermaticv1.CloudSpec, error) {
+	preset, err := m.GetPreset(ctx, userInfo, presetName)
 	if err != nil {
 		return nil, err
 	}
@@ -304,8 +381,8 @@ func (m *PresetProvider) setFakeCredentials(userInfo *provider.UserInfo, presetN
 
 }
 
-func (m *PresetProvider) setKubevirtCredentials(userInfo *provider.UserInfo, presetName string, cloud kubermaticv1.CloudSpec) (*kubermaticv1.CloudSpec, error) {
-	preset, err := m.GetPreset(userInfo, presetName)
+func (m *PresetProvider) setKubevirtCredentials(ctx context.Context, userInfo *provider.UserInfo, presetName string, cloud kubermaticv1.CloudSpec) (*kubermaticv1.CloudSpec, error) {
+	preset, err := m.GetPreset(ctx, userInfo, presetName)
 	if err != nil {
 		return nil, err
 	}
@@ -318,8 +395,8 @@ func (m *PresetProvider) setKubevirtCredentials(userInfo *provider.UserInfo, pre
 	return &cloud, nil
 }
 
-func (m *PresetProvider) setGCPCredentials(userInfo *provider.UserInfo, presetName string, cloud kubermaticv1.CloudSpec) (*kubermaticv1.CloudSpec, error) {
-	preset, err := m.GetPreset(userInfo, presetName)
+func (m *PresetProvider) setGCPCredentials(ctx context.Context, userInfo *provider.UserInfo, presetName string, cloud kubermaticv1.CloudSpec) (*kubermaticv1.CloudSpec, error) {
+	preset, err := m.GetPreset(ctx, userInfo, presetName)
 	if err != nil {
 		return nil, err
 	}
@@ -336,8 +413,8 @@ func (m *PresetProvider) setGCPCredentials(userInfo *provider.UserInfo, presetNa
 
 }
 
-func (m *PresetProvider) setAWSCredentials(userInfo *provider.UserInfo, presetName string, cloud kubermaticv1.CloudSpec) (*kubermaticv1.CloudSpec, error) {
-	preset, err := m.GetPreset(userInfo, presetName)
+func (m *PresetProvider) setAWSCredentials(ctx context.Context, userInfo *provider.UserInfo, presetName string, cloud kubermaticv1.CloudSpec) (*kubermaticv1.CloudSpec, error) {
+	preset, err := m.GetPreset(ctx, userInfo, presetName)
 	if err != nil {
 		return nil, err
 	}
@@ -361,8 +438,8 @@ func (m *PresetProvider) setAWSCredentials(userInfo *provider.UserInfo, presetNa
 	return &cloud, nil
 }
 
-func (m *PresetProvider) setHetznerCredentials(userInfo *provider.UserInfo, presetName string, cloud kubermaticv1.CloudSpec) (*kubermaticv1.CloudSpec, error) {
-	preset, err := m.GetPreset(userInfo, presetName)
+func (m *PresetProvider) setHetznerCredentials(ctx context.Context, userInfo *provider.UserInfo, presetName string, cloud kubermaticv1.CloudSpec) (*kubermaticv1.CloudSpec, error) {
+	preset, err := m.GetPreset(ctx, userInfo, presetName)
 	if err != nil {
 		return nil, err
 	}
@@ -375,8 +452,8 @@ func (m *PresetProvider) setHetznerCredentials(userInfo *provider.UserInfo, pres
 	return &cloud, nil
 }
 
-func (m *PresetProvider) setPacketCredentials(userInfo *provider.UserInfo, presetName string, cloud kubermaticv1.CloudSpec) (*kubermaticv1.CloudSpec, error) {
-	preset, err := m.GetPreset(userInfo, presetName)
+func (m *PresetProvider) setPacketCredentials(ctx context.Context, userInfo *provider.UserInfo, presetName string, cloud kubermaticv1.CloudSpec) (*kubermaticv1.CloudSpec, error) {
+	preset, err := m.GetPreset(ctx, userInfo, presetName)
 	if err != nil {
 		return nil, err
 	}
@@ -397,8 +474,8 @@ func (m *PresetProvider) setPacketCredentials(userInfo *provider.UserInfo, prese
 
 }
 
-func (m *PresetProvider) setDigitalOceanCredentials(userInfo *provider.UserInfo, presetName string, cloud kubermaticv1.CloudSpec) (*kubermaticv1.CloudSpec, error) {
-	preset, err := m.GetPreset(userInfo, presetName)
+func (m *PresetProvider) setDigitalOceanCredentials(ctx context.Context, userInfo *provider.UserInfo, presetName string, cloud kubermaticv1.CloudSpec) (*kubermaticv1.CloudSpec, error) {
+	preset, err := m.GetPreset(ctx, userInfo, presetName)
 	if err != nil {
 		return nil, err
 	}
@@ -411,8 +488,8 @@ func (m *PresetProvider) setDigitalOceanCredentials(userInfo *provider.UserInfo,
 
 }
 
-func (m *PresetProvider) setAzureCredentials(userInfo *provider.UserInfo, presetName string, cloud kubermaticv1.CloudSpec) (*kubermaticv1.CloudSpec, error) {
-	preset, err := m.GetPreset(userInfo, presetName)
+func (m *PresetProvider) setAzureCredentials(ctx context.Context, userInfo *provider.UserInfo, presetName string, cloud kubermaticv1.CloudSpec) (*kubermaticv1.CloudSpec, error) {
+	preset, err := m.GetPreset(ctx, userInfo, presetName)
 	if err != nil {
 		return nil, err
 	}
@@ -436,8 +513,8 @@ func (m *PresetProvider) setAzureCredentials(userInfo *provider.UserInfo, preset
 
 }
 
-func (m *PresetProvider) setOpenStackCredentials(userInfo *provider.UserInfo, presetName string, cloud kubermaticv1.CloudSpec, dc *kubermaticv1.Datacenter) (*kubermaticv1.CloudSpec, error) {
-	preset, err := m.GetPreset(userInfo, presetName)
+func (m *PresetProvider) setOpenStackCredentials(ctx context.Context, userInfo *provider.UserInfo, presetName string, cloud kubermaticv1.CloudSpec, dc *kubermaticv1.Datacenter) (*kubermaticv1.CloudSpec, error) {
+	preset, err := m.GetPreset(ctx, userInfo, presetName)
 	if err != nil {
 		return nil, err
 	}
@@ -472,8 +549,8 @@ func (m *PresetProvider) setOpenStackCredentials(userInfo *provider.UserInfo, pr
 
 }
 
-func (m *PresetProvider) setVsphereCredentials(userInfo *provider.UserInfo, presetName string, cloud kubermaticv1.CloudSpec, dc *kubermaticv1.Datacenter) (*kubermaticv1.CloudSpec, error) {
-	preset, err := m.GetPreset(userInfo, presetName)
+func (m *PresetProvider) setVsphereCredentials(ctx context.Context, userInfo *provider.UserInfo, presetName string, cloud kubermaticv1.CloudSpec, dc *kubermaticv1.Datacenter) (*kubermaticv1.CloudSpec, error) {
+	preset, err := m.GetPreset(ctx, userInfo, presetName)
 	if err != nil {
 		return nil, err
 	}
@@ -495,8 +572,8 @@ func (m *PresetProvider) setVsphereCredentials(userInfo *provider.UserInfo, pres
 
 }
 
-func (m *PresetProvider) setAlibabaCredentials(userInfo *provider.UserInfo, presetName string, cloud kubermaticv1.CloudSpec) (*kubermaticv1.CloudSpec, error) {
-	preset, err := m.GetPreset(userInfo, presetName)
+func (m *PresetProvider) setAlibabaCredentials(ctx context.Context, userInfo *provider.UserInfo, presetName string, cloud kubermaticv1.CloudSpec) (*kubermaticv1.CloudSpec, error) {
+	preset, err := m.GetPreset(ctx, userInfo, presetName)
 	if err != nil {
 		return nil, err
 	}
@@ -511,8 +588,8 @@ func (m *PresetProvider) setAlibabaCredentials(userInfo *provider.UserInfo, pres
 	return &cloud, nil
 }
 
-func (m *PresetProvider) setAnexiaCredentials(userInfo *provider.UserInfo, presetName string, cloud kubermaticv1.CloudSpec) (*kubermaticv1.CloudSpec, error) {
-	preset, err := m.GetPreset(userInfo, presetName)
+func (m *PresetProvider) setAnexiaCredentials(ctx context.Context, userInfo *provider.UserInfo, presetName string, cloud kubermaticv1.CloudSpec) (*kubermaticv1.CloudSpec, error) {
+	preset, err := m.GetPreset(ctx, userInfo, presetName)
 	if err != nil {
 		return nil, err
 	}
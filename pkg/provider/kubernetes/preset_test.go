@@ -19,10 +19,12 @@ package kubernetes_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/provider"
 	"k8c.io/kubermatic/v2/pkg/provider/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/secretstore"
 
 	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -188,11 +190,11 @@ func TestGetPreset(t *testing.T) {
 				WithObjects(tc.presets...).
 				Build()
 
-			provider, err := kubernetes.NewPresetProvider(context.Background(), fakeClient, "", true)
+			provider, err := kubernetes.NewPresetProvider(context.Background(), fakeClient, "", true, nil)
 			if err != nil {
 				t.Fatal(err)
 			}
-			preset, err := provider.GetPreset(&tc.userInfo, tc.presetName)
+			preset, err := provider.GetPreset(context.Background(), &tc.userInfo, tc.presetName)
 			if len(tc.expectedError) > 0 {
 				if err == nil {
 					t.Fatalf("expected error")
@@ -587,7 +589,7 @@ func TestGetPresets(t *testing.T) {
 				WithObjects(tc.presets...).
 				Build()
 
-			provider, err := kubernetes.NewPresetProvider(context.Background(), fakeClient, "", true)
+			provider, err := kubernetes.NewPresetProvider(context.Background(), fakeClient, "", true, nil)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -935,11 +937,11 @@ func TestCredentialEndpoint(t *testing.T) {
 				WithObjects(tc.presets...).
 				Build()
 
-			provider, err := kubernetes.NewPresetProvider(context.Background(), fakeClient, "", true)
+			provider, err := kubernetes.NewPresetProvider(context.Background(), fakeClient, "", true, nil)
 			if err != nil {
 				t.Fatal(err)
 			}
-			cloudResult, err := provider.SetCloudCredentials(&tc.userInfo, tc.presetName, tc.cloudSpec, tc.dc)
+			cloudResult, err := provider.SetCloudCredentials(context.Background(), &tc.userInfo, tc.presetName, tc.cloudSpec, tc.dc)
 
 			if len(tc.expectedError) > 0 {
 				if err == nil {
@@ -955,3 +957,127 @@ func TestCredentialEndpoint(t *testing.T) {
 		})
 	}
 }
+
+type fakeSecretStore struct {
+	secrets       map[string]map[string]string
+	leaseDuration time.Duration
+}
+
+func (s *fakeSecretStore) Read(_ context.Context, path string) (*secretstore.Secret, error) {
+	data, ok := s.secrets[path]
+	if !ok {
+		return nil, secretstore.ErrNotFound
+	}
+	return &secretstore.Secret{Data: data, LeaseDuration: s.leaseDuration}, nil
+}
+
+func TestExternalPresetCredentials(t *testing.T) {
+	t.Parallel()
+
+	preset := &kubermaticv1.Preset{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+		Spec: kubermaticv1.PresetSpec{
+			Hetzner: &kubermaticv1.Hetzner{
+				Network: "network",
+			},
+			ExternalSecrets: &kubermaticv1.PresetExternalSecrets{
+				Path: "secret/data/presets/test",
+			},
+		},
+	}
+
+	testcases := []struct {
+		name              string
+		secretStore       secretstore.Store
+		expectedCloudSpec *kubermaticv1.CloudSpec
+		expectedLease     time.Duration
+		expectedError     string
+	}{
+		{
+			name: "test 1: credentials are read from the secret store",
+			secretStore: &fakeSecretStore{secrets: map[string]map[string]string{
+				"secret/data/presets/test": {
+					"hetzner.token": "token",
+					// credentials of providers the preset is not configured for are ignored
+					"aws.secretAccessKey": "secret",
+				},
+			}},
+			expectedCloudSpec: &kubermaticv1.CloudSpec{Hetzner: &kubermaticv1.HetznerCloudSpec{Token: "token", Network: "network"}},
+		},
+		{
+			name:          "test 2: missing secrets cannot be resolved",
+			secretStore:   &fakeSecretStore{},
+			expectedError: "failed to read the credentials of preset test: secret not found",
+		},
+		{
+			name: "test 3: unknown credentials are rejected",
+			secretStore: &fakeSecretStore{secrets: map[string]map[string]string{
+				"secret/data/presets/test": {"hetzner.password": "password"},
+			}},
+			expectedError: `invalid credentials for preset test: invalid key "hetzner.password", hetzner presets have no credential "password"`,
+		},
+		{
+			name:          "test 4: external secrets require a secret store",
+			expectedError: "preset test references external secrets, but no secret store is configured",
+		},
+		{
+			name: "test 5: leased credentials are resolved",
+			secretStore: &fakeSecretStore{
+				secrets: map[string]map[string]string{
+					"secret/data/presets/test": {"hetzner.token": "token"},
+				},
+				leaseDuration: time.Hour,
+			},
+			expectedCloudSpec: &kubermaticv1.CloudSpec{Hetzner: &kubermaticv1.HetznerCloudSpec{Token: "token", Network: "network"}},
+			expectedLease:     time.Hour,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := fakectrlruntimeclient.
+				NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(preset.DeepCopy()).
+				Build()
+
+			presetProvider, err := kubernetes.NewPresetProvider(context.Background(), fakeClient, "", true, tc.secretStore)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			userInfo := &provider.UserInfo{Email: "test@example.com"}
+			cloudResult, err := presetProvider.SetCloudCredentials(context.Background(), userInfo, preset.Name, kubermaticv1.CloudSpec{Hetzner: &kubermaticv1.HetznerCloudSpec{}}, nil)
+
+			if len(tc.expectedError) > 0 {
+				if err == nil {
+					t.Fatalf("expected error")
+				}
+				if err.Error() != tc.expectedError {
+					t.Fatalf("expected: %s, got %v", tc.expectedError, err)
+				}
+			} else if !equality.Semantic.DeepEqual(cloudResult, tc.expectedCloudSpec) {
+				t.Fatalf("expected: %v, got %v", tc.expectedCloudSpec, cloudResult)
+			}
+
+			stored, err := presetProvider.GetStoredPreset(userInfo, preset.Name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(tc.expectedError) == 0 {
+				lease, err := presetProvider.GetCredentialsLease(context.Background(), stored)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if lease != tc.expectedLease {
+					t.Fatalf("expected lease %v, got %v", tc.expectedLease, lease)
+				}
+			}
+			if stored.Spec.Hetzner.Token != "" {
+				t.Fatalf("expected the stored preset not to contain external credentials")
+			}
+		})
+	}
+}
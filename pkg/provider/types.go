@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	providerconfig "github.com/kubermatic/machine-controller/pkg/providerconfig/types"
 	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
@@ -743,9 +744,17 @@ type PresetProvider interface {
 	CreatePreset(preset *kubermaticv1.Preset) (*kubermaticv1.Preset, error)
 	UpdatePreset(preset *kubermaticv1.Preset) (*kubermaticv1.Preset, error)
 	GetPresets(userInfo *UserInfo) ([]kubermaticv1.Preset, error)
-	GetPreset(userInfo *UserInfo, name string) (*kubermaticv1.Preset, error)
+	// GetPreset returns the preset with the credentials kept in an external secret store resolved.
+	GetPreset(ctx context.Context, userInfo *UserInfo, name string) (*kubermaticv1.Preset, error)
+	// GetStoredPreset returns the preset as it is stored, without resolving external credentials.
+	// It has to be used for presets that are going to be updated.
+	GetStoredPreset(userInfo *UserInfo, name string) (*kubermaticv1.Preset, error)
+	// GetCredentialsLease returns how long the credentials kept in an external secret store are leased
+	// for, it is zero for credentials that are not leased. Leased credentials have to be resolved again
+	// and copied to the clusters using them before the lease ends.
+	GetCredentialsLease(ctx context.Context, preset *kubermaticv1.Preset) (time.Duration, error)
 	DeletePreset(preset *kubermaticv1.Preset) (*kubermaticv1.Preset, error)
-	SetCloudCredentials(userInfo *UserInfo, presetName string, cloud kubermaticv1.CloudSpec, dc *kubermaticv1.Datacenter) (*kubermaticv1.CloudSpec, error)
+	SetCloudCredentials(ctx context.Context, userInfo *UserInfo, presetName string, cloud kubermaticv1.CloudSpec, dc *kubermaticv1.Datacenter) (*kubermaticv1.CloudSpec, error)
 }
 
 // AdmissionPluginsProvider declares the set of methods for interacting with admission plugins
@@ -1075,6 +1084,21 @@ type PrivilegedAllowedRegistryProvider interface {
 	DeleteUnsecured(name string) error
 }
 
+// PrivilegedClusterMigrationProvider declares the set of method for interacting with cluster migrations
+type PrivilegedClusterMigrationProvider interface {
+	// CreateUnsecured creates the given cluster migration
+	//
+	// Note that this function:
+	// is unsafe in a sense that it uses privileged account to create the resource
+	CreateUnsecured(migration *kubermaticv1.ClusterMigration) (*kubermaticv1.ClusterMigration, error)
+
+	// ListUnsecured gets a list of all migrations of the given cluster
+	//
+	// Note that this function:
+	// is unsafe in a sense that it uses privileged account to get the resources
+	ListUnsecured(clusterName string) (*kubermaticv1.ClusterMigrationList, error)
+}
+
 // EtcdBackupConfigProvider declares the set of method for interacting with etcd backup configs
 type EtcdBackupConfigProvider interface {
 	// Create creates the given etcdBackupConfig
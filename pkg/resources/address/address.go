@@ -131,6 +131,9 @@ func (m *ModifiersBuilder) Build(ctx context.Context) ([]func(*kubermaticv1.Clus
 		} else {
 			externalName = frontProxyLBServiceHostname
 		}
+	} else if name := m.cluster.Annotations[kubermaticv1.ExternalNameAnnotation]; name != "" {
+		// clusters migrated from another seed keep their original name
+		externalName = name
 	} else {
 		externalName = fmt.Sprintf("%s.%s.%s", m.cluster.Name, subdomain, m.externalURL)
 	}
@@ -49,6 +49,8 @@ func testLookupFunction(host string) ([]net.IP, error) {
 	case "fake-cluster.europe-west3-c.dev.kubermatic.io":
 		fallthrough
 	case "fake-cluster.alias-europe-west3-c.dev.kubermatic.io":
+		fallthrough
+	case "fake-cluster.old-seed.dev.kubermatic.io":
 		return []net.IP{net.IPv4(34, 89, 181, 151)}, nil
 	case loadbBalancerHostName:
 		return []net.IP{net.IPv4(34, 89, 181, 151)}, nil
@@ -76,6 +78,7 @@ func TestSyncClusterAddress(t *testing.T) {
 		apiserverService     corev1.Service
 		frontproxyService    corev1.Service
 		exposeStrategy       kubermaticv1.ExposeStrategy
+		annotations          map[string]string
 		seedDNSOverwrite     string
 		expectedExternalName string
 		expectedIP           string
@@ -185,6 +188,26 @@ func TestSyncClusterAddress(t *testing.T) {
 			expectedPort:         int32(32000),
 			expectedURL:          fmt.Sprintf("https://%s.alias-europe-west3-c.%s:32000", fakeClusterName, fakeExternalURL),
 		},
+		{
+			name: "Verify properties for service type NodePort with external name annotation",
+			apiserverService: corev1.Service{
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeNodePort,
+					Ports: []corev1.ServicePort{
+						{
+							Port:       int32(32000),
+							TargetPort: intstr.FromInt(32000),
+							NodePort:   32000,
+						},
+					},
+				}},
+			exposeStrategy:       kubermaticv1.ExposeStrategyNodePort,
+			annotations:          map[string]string{kubermaticv1.ExternalNameAnnotation: "fake-cluster.old-seed.dev.kubermatic.io"},
+			expectedExternalName: "fake-cluster.old-seed.dev.kubermatic.io",
+			expectedIP:           externalIP,
+			expectedPort:         int32(32000),
+			expectedURL:          "https://fake-cluster.old-seed.dev.kubermatic.io:32000",
+		},
 		{
 			name: "Verify error when service has less than one ports",
 			apiserverService: corev1.Service{
@@ -199,7 +222,8 @@ func TestSyncClusterAddress(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			cluster := &kubermaticv1.Cluster{
 				ObjectMeta: metav1.ObjectMeta{
-					Name: fakeClusterName,
+					Name:        fakeClusterName,
+					Annotations: tc.annotations,
 				},
 				Spec: kubermaticv1.ClusterSpec{
 					Cloud: kubermaticv1.CloudSpec{
@@ -0,0 +1,533 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	providerconfig "github.com/kubermatic/machine-controller/pkg/providerconfig/types"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/certificates"
+	"k8c.io/kubermatic/v2/pkg/resources/reconciling"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	auditLogsSidecarName = "audit-logs"
+	fluentBitImage       = "fluent/fluent-bit:1.8.15"
+
+	auditLogMountPath           = "/var/log/kubernetes/audit"
+	auditSinksMountPath         = "/etc/kubernetes/audit-sinks"
+	auditMLACertificatesPath    = "/etc/kubernetes/audit-mla"
+	auditSinksVolumeName        = resources.AuditSinksSecretName
+	auditMLACertificatesVolume  = resources.AuditLogsMLACertificatesSecretName
+	auditFluentBitConfigKey     = "fluent-bit.conf"
+	auditWebhookKubeconfigKey   = "webhook-kubeconfig"
+	auditS3AccessKeyIDKey       = "s3-access-key-id"
+	auditS3SecretAccessKeyKey   = "s3-secret-access-key"
+	auditMLAClientCertSecretKey = "client.crt"
+	auditMLAClientKeySecretKey  = "client.key"
+
+	// the raw audit log lines are shipped to most sinks, while HTTP sinks receive the parsed events
+	auditRawTag   = "audit.raw"
+	auditEventTag = "audit.event"
+)
+
+// auditLoggingEnabled tells if audit logging is enabled for the cluster.
+func auditLoggingEnabled(cluster *kubermaticv1.Cluster) bool {
+	return cluster.Spec.AuditLogging != nil && cluster.Spec.AuditLogging.Enabled
+}
+
+// auditSinks returns the audit sinks of the cluster, which default to the ones of its datacenter.
+func auditSinks(data *resources.TemplateData) []kubermaticv1.AuditSink {
+	if !auditLoggingEnabled(data.Cluster()) {
+		return nil
+	}
+	if sinks := data.Cluster().Spec.AuditLogging.Sinks; len(sinks) > 0 {
+		return sinks
+	}
+	if dc := data.DC(); dc != nil {
+		return dc.Spec.DefaultAuditSinks
+	}
+	return nil
+}
+
+// AuditMLAIngestionEnabled tells if audit events are sent to the user cluster MLA stack of the seed.
+func AuditMLAIngestionEnabled(data *resources.TemplateData) bool {
+	return auditLoggingEnabled(data.Cluster()) && data.Cluster().Spec.AuditLogging.MLAIngestionEnabled && data.UserClusterMLAEnabled()
+}
+
+func auditWebhookSink(data *resources.TemplateData) *kubermaticv1.AuditWebhookSink {
+	for _, sink := range auditSinks(data) {
+		if sink.Webhook != nil {
+			return sink.Webhook
+		}
+	}
+	return nil
+}
+
+func auditS3Sink(data *resources.TemplateData) *kubermaticv1.AuditS3Sink {
+	for _, sink := range auditSinks(data) {
+		if sink.S3 != nil {
+			return sink.S3
+		}
+	}
+	return nil
+}
+
+// auditLogsSidecarNeeded tells if audit events need to be shipped from the audit log by the audit-logs
+// sidecar, which is the case unless all of them are sent to a webhook by the apiserver itself.
+func auditLogsSidecarNeeded(data *resources.TemplateData) bool {
+	if !auditLoggingEnabled(data.Cluster()) {
+		return false
+	}
+	sinks := auditSinks(data)
+	if len(sinks) == 0 || AuditMLAIngestionEnabled(data) {
+		return true
+	}
+	for _, sink := range sinks {
+		if sink.Webhook == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// AuditSinksSecretCreator returns a creator for the secret with the configuration of the audit sinks.
+// It contains the credentials of the sinks as well, which are given inline or resolved from the
+// referenced secrets.
+func AuditSinksSecretCreator(data *resources.TemplateData) reconciling.NamedSecretCreatorGetter {
+	return func() (string, reconciling.SecretCreator) {
+		return resources.AuditSinksSecretName, func(se *corev1.Secret) (*corev1.Secret, error) {
+			se.Data = map[string][]byte{}
+			if !auditLoggingEnabled(data.Cluster()) {
+				return se, nil
+			}
+
+			config, err := fluentBitConfig(data)
+			if err != nil {
+				return nil, err
+			}
+			se.Data[auditFluentBitConfigKey] = []byte(config)
+
+			if webhook := auditWebhookSink(data); webhook != nil {
+				kubeconfig, err := auditWebhookKubeconfig(data, webhook)
+				if err != nil {
+					return nil, fmt.Errorf("failed to create kubeconfig for audit webhook: %v", err)
+				}
+				se.Data[auditWebhookKubeconfigKey] = kubeconfig
+			}
+
+			// fluent-bit only reads the S3 credentials from the environment
+			if s3 := auditS3Sink(data); s3 != nil {
+				accessKeyID, err := resources.GetAuditSinkCredential(data, s3.AccessKeyID, s3.CredentialsReference, resources.AWSAccessKeyID)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get credentials of S3 audit sink: %v", err)
+				}
+				secretAccessKey, err := resources.GetAuditSinkCredential(data, s3.SecretAccessKey, s3.CredentialsReference, resources.AWSSecretAccessKey)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get credentials of S3 audit sink: %v", err)
+				}
+				se.Data[auditS3AccessKeyIDKey] = []byte(accessKeyID)
+				se.Data[auditS3SecretAccessKeyKey] = []byte(secretAccessKey)
+			}
+
+			return se, nil
+		}
+	}
+}
+
+// AuditLogsMLACertificateCreator returns a creator for the client certificate the audit-logs sidecar
+// sends audit events to the MLA Gateway of the cluster with.
+func AuditLogsMLACertificateCreator(data *resources.TemplateData) reconciling.NamedSecretCreatorGetter {
+	return func() (string, reconciling.SecretCreator) {
+		return resources.AuditLogsMLACertificatesSecretName, certificates.GetECDSAClientCertificateCreator(
+			resources.AuditLogsMLACertificatesSecretName,
+			resources.AuditLogsMLACertificateCommonName,
+			[]string{},
+			auditMLAClientCertSecretKey,
+			auditMLAClientKeySecretKey,
+			data.GetMLAGatewayCA,
+		)
+	}
+}
+
+func auditWebhookKubeconfig(data *resources.TemplateData, webhook *kubermaticv1.AuditWebhookSink) ([]byte, error) {
+	cluster := clientcmdapi.Cluster{
+		Server:                   webhook.URL,
+		CertificateAuthorityData: []byte(webhook.CABundle),
+	}
+	token, err := resources.GetAuditSinkCredential(data, webhook.Token, webhook.CredentialsReference, resources.AuditSinkToken)
+	if err != nil {
+		return nil, err
+	}
+	authInfo := clientcmdapi.AuthInfo{Token: token}
+
+	return yaml.Marshal(clientcmdapi.Config{
+		APIVersion:     "v1",
+		Kind:           "Config",
+		Clusters:       []clientcmdapi.NamedCluster{{Name: "audit-webhook", Cluster: cluster}},
+		AuthInfos:      []clientcmdapi.NamedAuthInfo{{Name: "audit-webhook", AuthInfo: authInfo}},
+		Contexts:       []clientcmdapi.NamedContext{{Name: "audit-webhook", Context: clientcmdapi.Context{Cluster: "audit-webhook", AuthInfo: "audit-webhook"}}},
+		CurrentContext: "audit-webhook",
+	})
+}
+
+// fluentBitSection is a section of the fluent-bit configuration with its parameters in order.
+type fluentBitSection struct {
+	name   string
+	params [][2]string
+}
+
+func (s *fluentBitSection) set(key, value string) {
+	s.params = append(s.params, [2]string{key, value})
+}
+
+// fluentBitConfig returns the configuration of the audit-logs sidecar, which ships the audit log to
+// all sinks except for webhooks.
+func fluentBitConfig(data *resources.TemplateData) (string, error) {
+	service := &fluentBitSection{name: "SERVICE"}
+	service.set("Flush", "5")
+	service.set("Log_Level", "info")
+	service.set("Parsers_File", "/fluent-bit/etc/parsers.conf")
+
+	rawInput := &fluentBitSection{name: "INPUT"}
+	rawInput.set("Name", "tail")
+	rawInput.set("Tag", auditRawTag)
+	rawInput.set("Path", filepath.Join(auditLogMountPath, "audit.log"))
+	rawInput.set("DB", filepath.Join(auditLogMountPath, "fluentbit.db"))
+	sections := []*fluentBitSection{service, rawInput}
+
+	var outputs []*fluentBitSection
+	parsedEvents := false
+	for _, sink := range auditSinks(data) {
+		output := &fluentBitSection{name: "OUTPUT"}
+		var err error
+		switch {
+		case sink.HTTP != nil:
+			parsedEvents = true
+			err = httpOutput(data, output, sink.HTTP)
+		case sink.Loki != nil:
+			err = lokiOutput(data, output, sink.Loki)
+		case sink.S3 != nil:
+			s3Output(data, output, sink.S3)
+		case sink.Syslog != nil:
+			err = syslogOutput(output, sink.Syslog)
+		default:
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("invalid audit sink %q: %v", sink.Name, err)
+		}
+		outputs = append(outputs, output)
+	}
+
+	if AuditMLAIngestionEnabled(data) {
+		outputs = append(outputs, mlaOutput(data))
+	}
+
+	// without any sinks, audit events are written to the log of the sidecar
+	if len(outputs) == 0 && auditWebhookSink(data) == nil {
+		output := &fluentBitSection{name: "OUTPUT"}
+		output.set("Name", "stdout")
+		output.set("Match", auditRawTag)
+		outputs = append(outputs, output)
+	}
+
+	if parsedEvents {
+		eventInput := &fluentBitSection{name: "INPUT"}
+		eventInput.set("Name", "tail")
+		eventInput.set("Tag", auditEventTag)
+		eventInput.set("Path", filepath.Join(auditLogMountPath, "audit.log"))
+		eventInput.set("DB", filepath.Join(auditLogMountPath, "fluentbit-events.db"))
+		eventInput.set("Parser", "json")
+		sections = append(sections, eventInput)
+	}
+	sections = append(sections, outputs...)
+
+	var config strings.Builder
+	for i, section := range sections {
+		if i > 0 {
+			config.WriteString("\n")
+		}
+		fmt.Fprintf(&config, "[%s]\n", section.name)
+		for _, param := range section.params {
+			fmt.Fprintf(&config, "    %-20s %s\n", param[0], param[1])
+		}
+	}
+	return config.String(), nil
+}
+
+// setEndpoint sets the host, port, URI and TLS parameters of an output for the URL. It returns the
+// path of the URL.
+func setEndpoint(output *fluentBitSection, rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("URL %q must use http or https", rawURL)
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	output.set("Host", u.Hostname())
+	output.set("Port", port)
+	if u.Scheme == "https" {
+		output.set("tls", "On")
+		output.set("tls.verify", "On")
+	}
+	return u.EscapedPath(), nil
+}
+
+// setHTTPCredentials sets the basic auth parameters of an output from the given credentials or the
+// referenced secret.
+func setHTTPCredentials(data *resources.TemplateData, output *fluentBitSection, username, password string, ref *providerconfig.GlobalSecretKeySelector) error {
+	username, err := resources.GetAuditSinkCredential(data, username, ref, resources.AuditSinkUsername)
+	if err != nil {
+		return err
+	}
+	password, err = resources.GetAuditSinkCredential(data, password, ref, resources.AuditSinkPassword)
+	if err != nil {
+		return err
+	}
+	if username == "" && password == "" {
+		return nil
+	}
+	output.set("HTTP_User", username)
+	output.set("HTTP_Passwd", password)
+	return nil
+}
+
+func httpOutput(data *resources.TemplateData, output *fluentBitSection, sink *kubermaticv1.AuditHTTPSink) error {
+	output.set("Name", "http")
+	output.set("Match", auditEventTag)
+	path, err := setEndpoint(output, sink.URL)
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		path = "/"
+	}
+	output.set("URI", path)
+	output.set("Format", "json_lines")
+	output.set("json_date_key", "false")
+	return setHTTPCredentials(data, output, sink.Username, sink.Password, sink.CredentialsReference)
+}
+
+func lokiOutput(data *resources.TemplateData, output *fluentBitSection, sink *kubermaticv1.AuditLokiSink) error {
+	output.set("Name", "loki")
+	output.set("Match", auditRawTag)
+	path, err := setEndpoint(output, sink.URL)
+	if err != nil {
+		return err
+	}
+	output.set("URI", strings.TrimSuffix(path, "/")+"/loki/api/v1/push")
+	if sink.TenantID != "" {
+		output.set("tenant_id", sink.TenantID)
+	}
+	output.set("labels", fmt.Sprintf("job=audit-logs, cluster=%s", data.Cluster().Name))
+	output.set("drop_single_key", "raw")
+	return setHTTPCredentials(data, output, sink.Username, sink.Password, sink.CredentialsReference)
+}
+
+func s3Output(data *resources.TemplateData, output *fluentBitSection, sink *kubermaticv1.AuditS3Sink) {
+	output.set("Name", "s3")
+	output.set("Match", auditRawTag)
+	output.set("bucket", sink.Bucket)
+	output.set("region", sink.Region)
+	if sink.Endpoint != "" {
+		output.set("endpoint", sink.Endpoint)
+	}
+	output.set("log_key", "log")
+	output.set("total_file_size", "50M")
+	output.set("upload_timeout", "10m")
+	output.set("s3_key_format", fmt.Sprintf("/audit-logs/%s/%%Y/%%m/%%d/%%H-%%M-%%S", data.Cluster().Name))
+}
+
+func syslogOutput(output *fluentBitSection, sink *kubermaticv1.AuditSyslogSink) error {
+	host, port, err := net.SplitHostPort(sink.Address)
+	if err != nil {
+		return err
+	}
+	mode := sink.Protocol
+	if mode == "" {
+		mode = kubermaticv1.AuditSyslogProtocolTCP
+	}
+
+	output.set("Name", "syslog")
+	output.set("Match", auditRawTag)
+	output.set("Host", host)
+	output.set("Port", port)
+	output.set("Mode", string(mode))
+	output.set("Syslog_Format", "rfc5424")
+	output.set("Syslog_Appname_Preset", "kube-apiserver")
+	output.set("Syslog_Message_Key", "log")
+	return nil
+}
+
+// mlaOutput returns the output to the MLA Gateway of the cluster, which stores the audit events in
+// the Loki of the seed with the cluster as tenant.
+func mlaOutput(data *resources.TemplateData) *fluentBitSection {
+	output := &fluentBitSection{name: "OUTPUT"}
+	output.set("Name", "loki")
+	output.set("Match", auditRawTag)
+	output.set("Host", fmt.Sprintf("%s.%s.svc.cluster.local", resources.MLAGatewayExternalServiceName, data.Cluster().Status.NamespaceName))
+	output.set("Port", "80")
+	output.set("tls", "On")
+	output.set("tls.verify", "On")
+	// the certificate of the MLA Gateway is only valid for its external name
+	output.set("tls.vhost", resources.MLAGatewaySNIPrefix+data.Cluster().Address.ExternalName)
+	output.set("tls.ca_file", filepath.Join(auditMLACertificatesPath, resources.CACertSecretKey))
+	output.set("tls.crt_file", filepath.Join(auditMLACertificatesPath, auditMLAClientCertSecretKey))
+	output.set("tls.key_file", filepath.Join(auditMLACertificatesPath, auditMLAClientKeySecretKey))
+	output.set("labels", "job=audit-logs")
+	output.set("drop_single_key", "raw")
+	return output
+}
+
+func getAuditSinksFlags(data *resources.TemplateData) []string {
+	if auditWebhookSink(data) == nil {
+		return nil
+	}
+	return []string{
+		"--audit-webhook-config-file", filepath.Join(auditSinksMountPath, auditWebhookKubeconfigKey),
+		"--audit-webhook-mode", "batch",
+	}
+}
+
+func getAuditSinksVolumeMounts(data *resources.TemplateData) []corev1.VolumeMount {
+	if auditWebhookSink(data) == nil {
+		return nil
+	}
+	return []corev1.VolumeMount{
+		{
+			Name:      auditSinksVolumeName,
+			MountPath: auditSinksMountPath,
+			ReadOnly:  true,
+		},
+	}
+}
+
+func getAuditSinksVolumes(data *resources.TemplateData) []corev1.Volume {
+	if auditWebhookSink(data) == nil && !auditLogsSidecarNeeded(data) {
+		return nil
+	}
+
+	vs := []corev1.Volume{
+		{
+			Name: auditSinksVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: resources.AuditSinksSecretName,
+				},
+			},
+		},
+	}
+	if AuditMLAIngestionEnabled(data) {
+		vs = append(vs, corev1.Volume{
+			Name: auditMLACertificatesVolume,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: resources.AuditLogsMLACertificatesSecretName,
+				},
+			},
+		})
+	}
+
+	return vs
+}
+
+// auditLogsSidecar returns the sidecar that ships the audit log of the apiserver to the audit sinks
+// or, if there are none, to its own log.
+func auditLogsSidecar(data *resources.TemplateData) corev1.Container {
+	container := corev1.Container{
+		Name:    auditLogsSidecarName,
+		Image:   data.ImageRegistry(resources.RegistryDocker) + "/" + fluentBitImage,
+		Command: []string{"/fluent-bit/bin/fluent-bit"},
+		Args:    []string{"-c", filepath.Join(auditSinksMountPath, auditFluentBitConfigKey)},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      resources.AuditLogVolumeName,
+				MountPath: auditLogMountPath,
+				ReadOnly:  false,
+			},
+			{
+				Name:      auditSinksVolumeName,
+				MountPath: auditSinksMountPath,
+				ReadOnly:  true,
+			},
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("10Mi"),
+				corev1.ResourceCPU:    resource.MustParse("5m"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("60Mi"),
+				corev1.ResourceCPU:    resource.MustParse("50m"),
+			},
+		},
+	}
+
+	if auditS3Sink(data) != nil {
+		container.Env = []corev1.EnvVar{
+			{
+				Name: "AWS_ACCESS_KEY_ID",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: resources.AuditSinksSecretName},
+						Key:                  auditS3AccessKeyIDKey,
+					},
+				},
+			},
+			{
+				Name: "AWS_SECRET_ACCESS_KEY",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: resources.AuditSinksSecretName},
+						Key:                  auditS3SecretAccessKeyKey,
+					},
+				},
+			},
+		}
+	}
+
+	if AuditMLAIngestionEnabled(data) {
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      auditMLACertificatesVolume,
+			MountPath: auditMLACertificatesPath,
+			ReadOnly:  true,
+		})
+	}
+
+	return container
+}
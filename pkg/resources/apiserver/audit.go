@@ -17,11 +17,17 @@ limitations under the License.
 package apiserver
 
 import (
+	"fmt"
+	"strings"
+
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/resources"
 	"k8c.io/kubermatic/v2/pkg/resources/reconciling"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	"sigs.k8s.io/yaml"
 )
 
 var auditPolicies = map[kubermaticv1.AuditPolicyPreset]string{
@@ -88,26 +94,363 @@ rules:
 func AuditConfigMapCreator(data *resources.TemplateData) reconciling.NamedConfigMapCreatorGetter {
 	return func() (string, reconciling.ConfigMapCreator) {
 		return resources.AuditConfigMapName, func(cm *corev1.ConfigMap) (*corev1.ConfigMap, error) {
-			// set the audit policy preset so we generate a ConfigMap in any case.
-			// It won't be used if audit logging is not enabled
-			preset := kubermaticv1.AuditPolicyPreset("")
-			if data.Cluster().Spec.AuditLogging != nil && data.Cluster().Spec.AuditLogging.Enabled && data.Cluster().Spec.AuditLogging.PolicyPreset != "" {
-				preset = data.Cluster().Spec.AuditLogging.PolicyPreset
+			settings := data.Cluster().Spec.AuditLogging
+			enabled := settings != nil && settings.Enabled
+
+			minimumPolicyName, minimumPolicy, err := minimumAuditPolicy(data)
+			if err != nil {
+				return nil, err
 			}
 
-			// if the policyPreset field is empty, only update the ConfigMap on creation
-			if preset != "" || cm.Data == nil {
+			var policy string
+			switch {
+			// a custom policy takes precedence over the policies of the seed and the presets
+			case enabled && settings.Policy != "":
+				policy = settings.Policy
+			case enabled && settings.PolicyName != "":
+				if policy, err = seedAuditPolicy(data, settings.PolicyName); err != nil {
+					return nil, err
+				}
+			default:
+				// set the audit policy preset so we generate a ConfigMap in any case.
+				// It won't be used if audit logging is not enabled
+				preset := kubermaticv1.AuditPolicyPreset("")
+				if enabled && settings.PolicyPreset != "" {
+					preset = settings.PolicyPreset
+				}
+
+				// if the policyPreset field is empty, only update the ConfigMap on creation
+				if preset == "" && cm.Data != nil && minimumPolicy == "" {
+					return cm, nil
+				}
 
 				// if the preset is empty, set it to 'metadata' to generate a valid audit policy
 				if preset == "" {
 					preset = kubermaticv1.AuditPolicyMetadata
 				}
+				policy = auditPolicies[preset]
+			}
 
-				cm.Data = map[string]string{
-					"policy.yaml": auditPolicies[preset],
+			if minimumPolicy != "" {
+				if policy, err = mergeAuditPolicies(minimumPolicyName, minimumPolicy, policy); err != nil {
+					return nil, fmt.Errorf("failed to enforce minimum audit policy %q: %v", minimumPolicyName, err)
 				}
 			}
+
+			cm.Data = map[string]string{
+				"policy.yaml": policy,
+			}
 			return cm, nil
 		}
 	}
 }
+
+// seedAuditPolicy returns the audit policy with the given name from the audit policies of the seed.
+func seedAuditPolicy(data *resources.TemplateData, name string) (string, error) {
+	if seed := data.Seed(); seed != nil {
+		if policy, ok := seed.Spec.AuditPolicies[name]; ok {
+			return policy, nil
+		}
+	}
+	return "", fmt.Errorf("audit policy %q does not exist in the seed", name)
+}
+
+// minimumAuditPolicy returns the name and the policy the datacenter of the cluster enforces as
+// minimum, if any.
+func minimumAuditPolicy(data *resources.TemplateData) (string, string, error) {
+	dc := data.DC()
+	if !auditLoggingEnabled(data.Cluster()) || dc == nil || !dc.Spec.EnforceAuditLogging || dc.Spec.MinimumAuditPolicy == "" {
+		return "", "", nil
+	}
+	policy, err := seedAuditPolicy(data, dc.Spec.MinimumAuditPolicy)
+	if err != nil {
+		return "", "", err
+	}
+	return dc.Spec.MinimumAuditPolicy, policy, nil
+}
+
+// mergeAuditPolicies enforces the minimum policy on the policy: every request is logged at the
+// higher of the levels both policies select for it. As the first matching rule of an audit policy
+// applies, the merged policy has a rule for every pair of a minimum and a policy rule which matches
+// the requests both rules match, ordered by the minimum rule first. They are followed by the rules
+// of both policies for requests the other policy has no rule for.
+// Stages are only omitted if both policies omit them.
+func mergeAuditPolicies(minimumName, minimum, policy string) (string, error) {
+	minimumPolicy := auditv1.Policy{}
+	if err := yaml.Unmarshal([]byte(minimum), &minimumPolicy); err != nil {
+		return "", fmt.Errorf("failed to parse minimum audit policy: %v", err)
+	}
+	merged := auditv1.Policy{}
+	if err := yaml.Unmarshal([]byte(policy), &merged); err != nil {
+		return "", fmt.Errorf("failed to parse audit policy: %v", err)
+	}
+
+	rules := []auditv1.PolicyRule{}
+	for _, minimumRule := range minimumPolicy.Rules {
+		minimumStages := unionStages(minimumPolicy.OmitStages, minimumRule.OmitStages)
+		for _, rule := range merged.Rules {
+			if intersection, ok := intersectAuditRules(minimumRule, rule); ok {
+				intersection.Level = higherAuditLevel(minimumRule.Level, rule.Level)
+				intersection.OmitStages = intersectStages(minimumStages, unionStages(merged.OmitStages, rule.OmitStages))
+				rules = append(rules, intersection)
+			}
+		}
+		// requests no rule of the policy matches are not logged by it
+		minimumRule.OmitStages = intersectStages(minimumStages, merged.OmitStages)
+		rules = append(rules, minimumRule)
+	}
+	for _, rule := range merged.Rules {
+		rule.OmitStages = intersectStages(unionStages(merged.OmitStages, rule.OmitStages), minimumPolicy.OmitStages)
+		rules = append(rules, rule)
+	}
+	merged.Rules = rules
+	merged.OmitStages = intersectStages(merged.OmitStages, minimumPolicy.OmitStages)
+
+	encoded, err := yaml.Marshal(merged)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("# minimumAuditPolicy: %s\n%s", minimumName, encoded), nil
+}
+
+// auditLevels are the audit levels in ascending order.
+var auditLevels = []auditv1.Level{auditv1.LevelNone, auditv1.LevelMetadata, auditv1.LevelRequest, auditv1.LevelRequestResponse}
+
+func higherAuditLevel(a, b auditv1.Level) auditv1.Level {
+	for i := len(auditLevels) - 1; i >= 0; i-- {
+		if a == auditLevels[i] || b == auditLevels[i] {
+			return auditLevels[i]
+		}
+	}
+	return a
+}
+
+// intersectAuditRules returns a rule matching the requests both the minimum rule and the rule match,
+// and false if there are no such requests. Where the intersection cannot be expressed by a rule,
+// e.g. for user groups, the returned rule matches the requests of the minimum rule instead, which
+// still enforces the minimum as the minimum rule is matched first in the merged policy.
+func intersectAuditRules(minimum, rule auditv1.PolicyRule) (auditv1.PolicyRule, bool) {
+	intersection := auditv1.PolicyRule{}
+
+	var ok bool
+	if intersection.Users, ok = intersectStrings(minimum.Users, rule.Users); !ok {
+		return intersection, false
+	}
+	if intersection.Verbs, ok = intersectStrings(minimum.Verbs, rule.Verbs); !ok {
+		return intersection, false
+	}
+	intersection.UserGroups = intersectUserGroups(minimum.UserGroups, rule.UserGroups)
+
+	minimumResourceRequests, minimumNonResourceRequests := auditRuleRequests(minimum)
+	resourceRequests, nonResourceRequests := auditRuleRequests(rule)
+	switch {
+	case minimumResourceRequests && resourceRequests:
+		if intersection.Namespaces, ok = intersectStrings(minimum.Namespaces, rule.Namespaces); !ok {
+			return intersection, false
+		}
+		if intersection.Resources, ok = intersectGroupResources(minimum.Resources, rule.Resources); !ok {
+			return intersection, false
+		}
+		return intersection, true
+	case minimumNonResourceRequests && nonResourceRequests:
+		if intersection.NonResourceURLs, ok = intersectNonResourceURLs(minimum.NonResourceURLs, rule.NonResourceURLs); !ok {
+			return intersection, false
+		}
+		return intersection, true
+	default:
+		return intersection, false
+	}
+}
+
+// auditRuleRequests returns whether the rule matches resource and non-resource requests.
+func auditRuleRequests(rule auditv1.PolicyRule) (bool, bool) {
+	if len(rule.Namespaces) > 0 || len(rule.Resources) > 0 {
+		return true, false
+	}
+	if len(rule.NonResourceURLs) > 0 {
+		return false, true
+	}
+	return true, true
+}
+
+// intersectStrings intersects two lists of which an empty one matches everything.
+func intersectStrings(a, b []string) ([]string, bool) {
+	if len(a) == 0 {
+		return b, true
+	}
+	if len(b) == 0 {
+		return a, true
+	}
+	intersection := []string{}
+	for _, value := range a {
+		if sets.NewString(b...).Has(value) {
+			intersection = append(intersection, value)
+		}
+	}
+	return intersection, len(intersection) > 0
+}
+
+// intersectUserGroups intersects the user groups of two rules. A user with groups of both lists
+// matches both rules, so only a subset can be used as intersection and otherwise the groups of
+// the minimum rule are kept.
+func intersectUserGroups(minimum, groups []string) []string {
+	if len(minimum) == 0 || len(groups) > 0 && sets.NewString(minimum...).IsSuperset(sets.NewString(groups...)) {
+		return groups
+	}
+	return minimum
+}
+
+// intersectGroupResources intersects the resources of two rules.
+func intersectGroupResources(minimum, resources []auditv1.GroupResources) ([]auditv1.GroupResources, bool) {
+	if len(minimum) == 0 {
+		return resources, true
+	}
+	if len(resources) == 0 {
+		return minimum, true
+	}
+	intersection := []auditv1.GroupResources{}
+	for _, minimumGroup := range minimum {
+		for _, group := range resources {
+			if minimumGroup.Group != group.Group {
+				continue
+			}
+			if len(minimumGroup.Resources) == 0 {
+				intersection = append(intersection, group)
+				continue
+			}
+			if len(group.Resources) == 0 {
+				intersection = append(intersection, minimumGroup)
+				continue
+			}
+
+			names, ok := intersectStrings(minimumGroup.ResourceNames, group.ResourceNames)
+			if !ok {
+				continue
+			}
+			groupResources := []string{}
+			for _, minimumResource := range minimumGroup.Resources {
+				for _, resource := range group.Resources {
+					if r, ok := intersectResource(minimumResource, resource); ok && !sets.NewString(groupResources...).Has(r) {
+						groupResources = append(groupResources, r)
+					}
+				}
+			}
+			if len(groupResources) > 0 {
+				intersection = append(intersection, auditv1.GroupResources{Group: group.Group, Resources: groupResources, ResourceNames: names})
+			}
+		}
+	}
+	return intersection, len(intersection) > 0
+}
+
+// intersectResource intersects two resources of a group, which may be "*", "resource/*" or
+// "*/subresource" patterns.
+func intersectResource(minimum, resource string) (string, bool) {
+	switch {
+	case minimum == "*" || minimum == resource:
+		return resource, true
+	case resource == "*":
+		return minimum, true
+	case !isResourcePattern(minimum):
+		return minimum, resourceMatches(minimum, resource)
+	case !isResourcePattern(resource):
+		return resource, resourceMatches(resource, minimum)
+	case strings.HasPrefix(minimum, "*/") && strings.HasSuffix(resource, "/*"):
+		return strings.TrimSuffix(resource, "/*") + strings.TrimPrefix(minimum, "*"), true
+	case strings.HasSuffix(minimum, "/*") && strings.HasPrefix(resource, "*/"):
+		return strings.TrimSuffix(minimum, "/*") + strings.TrimPrefix(resource, "*"), true
+	case strings.HasPrefix(minimum, "*/") && strings.HasPrefix(resource, "*/"),
+		strings.HasSuffix(minimum, "/*") && strings.HasSuffix(resource, "/*"):
+		return "", false
+	default:
+		return minimum, true
+	}
+}
+
+func isResourcePattern(resource string) bool {
+	return resource == "*" || strings.HasPrefix(resource, "*/") || strings.HasSuffix(resource, "/*")
+}
+
+// resourceMatches returns whether the pattern matches the resource the same way the API server does.
+func resourceMatches(resource, pattern string) bool {
+	name, subresource := resource, ""
+	if i := strings.Index(resource, "/"); i >= 0 {
+		name, subresource = resource[:i], resource[i+1:]
+	}
+	switch {
+	case pattern == "*" || pattern == resource:
+		return true
+	case subresource != "" && strings.HasPrefix(pattern, "*/"):
+		return subresource == strings.TrimPrefix(pattern, "*/")
+	case strings.HasSuffix(pattern, "/*"):
+		return name == strings.TrimSuffix(pattern, "/*")
+	default:
+		return false
+	}
+}
+
+// intersectNonResourceURLs intersects the non-resource URLs of two rules, which may end with "*".
+func intersectNonResourceURLs(minimum, urls []string) ([]string, bool) {
+	if len(minimum) == 0 {
+		return urls, true
+	}
+	if len(urls) == 0 {
+		return minimum, true
+	}
+	intersection := []string{}
+	for _, minimumURL := range minimum {
+		for _, url := range urls {
+			if u, ok := intersectNonResourceURL(minimumURL, url); ok && !sets.NewString(intersection...).Has(u) {
+				intersection = append(intersection, u)
+			}
+		}
+	}
+	return intersection, len(intersection) > 0
+}
+
+func intersectNonResourceURL(minimum, url string) (string, bool) {
+	minimumPrefix, prefix := strings.TrimRight(minimum, "*"), strings.TrimRight(url, "*")
+	switch {
+	case minimum == url:
+		return url, true
+	case !strings.HasSuffix(minimum, "*"):
+		return minimum, strings.HasSuffix(url, "*") && strings.HasPrefix(minimum, prefix)
+	case !strings.HasSuffix(url, "*"):
+		return url, strings.HasPrefix(url, minimumPrefix)
+	case strings.HasPrefix(minimumPrefix, prefix):
+		return minimum, true
+	case strings.HasPrefix(prefix, minimumPrefix):
+		return url, true
+	default:
+		return "", false
+	}
+}
+
+func unionStages(a, b []auditv1.Stage) []auditv1.Stage {
+	union := append([]auditv1.Stage{}, a...)
+	for _, stage := range b {
+		if !hasStage(union, stage) {
+			union = append(union, stage)
+		}
+	}
+	return union
+}
+
+func intersectStages(a, b []auditv1.Stage) []auditv1.Stage {
+	intersection := []auditv1.Stage{}
+	for _, stage := range a {
+		if hasStage(b, stage) {
+			intersection = append(intersection, stage)
+		}
+	}
+	return intersection
+}
+
+func hasStage(stages []auditv1.Stage, stage auditv1.Stage) bool {
+	for _, s := range stages {
+		if s == stage {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"strings"
+	"testing"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/audit/policy"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+const testMinimumAuditPolicy = `apiVersion: audit.k8s.io/v1
+kind: Policy
+omitStages:
+  - "RequestReceived"
+  - "ResponseStarted"
+rules:
+  - level: None
+    users: ["system:kube-proxy"]
+    verbs: ["watch"]
+  - level: RequestResponse
+    resources:
+      - group: ""
+        resources: ["secrets", "configmaps/*"]
+  - level: Request
+    userGroups: ["system:serviceaccounts"]
+    resources:
+      - group: ""
+        resources: ["*/exec"]
+  - level: Request
+    nonResourceURLs: ["/api*", "/version"]
+  - level: Metadata
+    omitStages:
+      - "ResponseComplete"
+`
+
+const testCustomAuditPolicy = `apiVersion: audit.k8s.io/v1
+kind: Policy
+rules:
+  - level: None
+    nonResourceURLs: ["/healthz*", "/version"]
+  - level: None
+    namespaces: ["kube-system"]
+    verbs: ["get", "list", "watch"]
+  - level: Metadata
+    resources:
+      - group: ""
+        resources: ["secrets"]
+        resourceNames: ["token"]
+  - level: RequestResponse
+    resources:
+      - group: ""
+        resources: ["pods/*", "secrets"]
+      - group: "apps"
+  - level: Request
+    users: ["system:kube-proxy"]
+`
+
+func TestMergeAuditPolicies(t *testing.T) {
+	testCases := []struct {
+		name    string
+		minimum string
+		policy  string
+	}{
+		{
+			name:    "catch-all minimum rule does not lower the levels of the policy",
+			minimum: auditPolicies[kubermaticv1.AuditPolicyMetadata],
+			policy:  auditPolicies[kubermaticv1.AuditPolicyRecommended],
+		},
+		{
+			name:    "minimum is enforced on the metadata preset",
+			minimum: testMinimumAuditPolicy,
+			policy:  auditPolicies[kubermaticv1.AuditPolicyMetadata],
+		},
+		{
+			name:    "minimum is enforced on the recommended preset",
+			minimum: testMinimumAuditPolicy,
+			policy:  auditPolicies[kubermaticv1.AuditPolicyRecommended],
+		},
+		{
+			name:    "minimum is enforced on the minimal preset",
+			minimum: testMinimumAuditPolicy,
+			policy:  auditPolicies[kubermaticv1.AuditPolicyMinimal],
+		},
+		{
+			name:    "minimum is enforced on a custom policy",
+			minimum: testMinimumAuditPolicy,
+			policy:  testCustomAuditPolicy,
+		},
+		{
+			name:    "custom policy is enforced on the minimal preset",
+			minimum: testCustomAuditPolicy,
+			policy:  auditPolicies[kubermaticv1.AuditPolicyMinimal],
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			merged, err := mergeAuditPolicies("test", tc.minimum, tc.policy)
+			if err != nil {
+				t.Fatalf("failed to merge audit policies: %v", err)
+			}
+
+			minimumChecker := auditPolicyChecker(t, tc.minimum)
+			policyChecker := auditPolicyChecker(t, tc.policy)
+			mergedChecker := auditPolicyChecker(t, merged)
+
+			for _, attributes := range testAuditAttributes() {
+				minimumLevel, minimumStages := minimumChecker.LevelAndStages(attributes)
+				level, stages := policyChecker.LevelAndStages(attributes)
+				mergedLevel, mergedStages := mergedChecker.LevelAndStages(attributes)
+
+				expectedLevel := level
+				if level.Less(minimumLevel) {
+					expectedLevel = minimumLevel
+				}
+				if mergedLevel != expectedLevel {
+					t.Errorf("expected level %s for %s, got %s", expectedLevel, describeAuditAttributes(attributes), mergedLevel)
+				}
+
+				expectedStages := map[auditinternal.Stage]bool{}
+				for _, stage := range minimumStages {
+					for _, s := range stages {
+						if stage == s {
+							expectedStages[stage] = true
+						}
+					}
+				}
+				for _, stage := range mergedStages {
+					if !expectedStages[stage] {
+						t.Errorf("expected stage %s not to be omitted for %s", stage, describeAuditAttributes(attributes))
+					}
+					delete(expectedStages, stage)
+				}
+				for stage := range expectedStages {
+					t.Errorf("expected stage %s to be omitted for %s", stage, describeAuditAttributes(attributes))
+				}
+			}
+		})
+	}
+}
+
+func auditPolicyChecker(t *testing.T, p string) policy.Checker {
+	parsed, err := policy.LoadPolicyFromBytes([]byte(p))
+	if err != nil {
+		t.Fatalf("failed to load audit policy: %v", err)
+	}
+	return policy.NewChecker(parsed)
+}
+
+// testAuditAttributes returns requests for every combination of the users, verbs and resources the
+// test policies distinguish.
+func testAuditAttributes() []authorizer.Attributes {
+	users := []user.Info{
+		&user.DefaultInfo{Name: "admin", Groups: []string{"system:masters"}},
+		&user.DefaultInfo{Name: "system:kube-proxy"},
+		&user.DefaultInfo{Name: "system:serviceaccount:default:test", Groups: []string{"system:serviceaccounts"}},
+	}
+	verbs := []string{"get", "watch", "create", "delete"}
+	requests := []authorizer.AttributesRecord{
+		{ResourceRequest: true, Resource: "pods", Namespace: "default"},
+		{ResourceRequest: true, Resource: "pods", Subresource: "exec", Namespace: "default"},
+		{ResourceRequest: true, Resource: "pods", Subresource: "log", Namespace: "kube-system"},
+		{ResourceRequest: true, Resource: "services", Subresource: "proxy", Namespace: "default"},
+		{ResourceRequest: true, Resource: "secrets", Namespace: "default", Name: "token"},
+		{ResourceRequest: true, Resource: "secrets", Namespace: "kube-system", Name: "other"},
+		{ResourceRequest: true, Resource: "configmaps", Namespace: "default"},
+		{ResourceRequest: true, Resource: "configmaps", Subresource: "status", Namespace: "default"},
+		{ResourceRequest: true, APIGroup: "apps", Resource: "deployments", Namespace: "kube-system"},
+		{ResourceRequest: true, Resource: "nodes"},
+		{Path: "/api/v1"},
+		{Path: "/apis"},
+		{Path: "/version"},
+		{Path: "/healthz/ready"},
+		{Path: "/metrics"},
+	}
+
+	attributes := []authorizer.Attributes{}
+	for _, u := range users {
+		for _, verb := range verbs {
+			for _, request := range requests {
+				request.User = u
+				request.Verb = verb
+				attributes = append(attributes, request)
+			}
+		}
+	}
+	return attributes
+}
+
+func describeAuditAttributes(attributes authorizer.Attributes) string {
+	if !attributes.IsResourceRequest() {
+		return strings.Join([]string{attributes.GetUser().GetName(), attributes.GetVerb(), attributes.GetPath()}, " ")
+	}
+	resource := attributes.GetResource()
+	if attributes.GetSubresource() != "" {
+		resource += "/" + attributes.GetSubresource()
+	}
+	return strings.Join([]string{attributes.GetUser().GetName(), attributes.GetVerb(), attributes.GetAPIGroup(), resource, attributes.GetNamespace(), attributes.GetName()}, " ")
+}
@@ -71,8 +71,10 @@ func DeploymentCreator(data *resources.TemplateData, enableOIDCAuthentication bo
 			}
 			dep.Spec.Template.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: resources.ImagePullSecretName}}
 
-			volumes := getVolumes(data.IsKonnectivityEnabled())
-			volumeMounts := getVolumeMounts(data.IsKonnectivityEnabled())
+			volumes := append(getVolumes(data.IsKonnectivityEnabled()), getEncryptionVolumes(data.Cluster())...)
+			volumes = append(volumes, getAuditSinksVolumes(data)...)
+			volumeMounts := append(getVolumeMounts(data.IsKonnectivityEnabled()), getEncryptionVolumeMounts(data.Cluster())...)
+			volumeMounts = append(volumeMounts, getAuditSinksVolumeMounts(data)...)
 
 			podLabels, err := data.GetPodTemplateLabels(name, volumes, nil)
 			if err != nil {
@@ -125,8 +127,7 @@ func DeploymentCreator(data *resources.TemplateData, enableOIDCAuthentication bo
 				}
 			}
 
-			auditLogEnabled := data.Cluster().Spec.AuditLogging != nil && data.Cluster().Spec.AuditLogging.Enabled
-			flags, err := getApiserverFlags(data, etcdEndpoints, enableOIDCAuthentication, auditLogEnabled)
+			flags, err := getApiserverFlags(data, etcdEndpoints, enableOIDCAuthentication, auditLoggingEnabled(data.Cluster()))
 			if err != nil {
 				return nil, err
 			}
@@ -207,32 +208,8 @@ func DeploymentCreator(data *resources.TemplateData, enableOIDCAuthentication bo
 				return nil, fmt.Errorf("failed to set resource requirements: %v", err)
 			}
 
-			if auditLogEnabled {
-				dep.Spec.Template.Spec.Containers = append(dep.Spec.Template.Spec.Containers,
-					corev1.Container{
-						Name:    "audit-logs",
-						Image:   "docker.io/fluent/fluent-bit:1.2.2",
-						Command: []string{"/fluent-bit/bin/fluent-bit"},
-						Args:    []string{"-i", "tail", "-p", "path=/var/log/kubernetes/audit/audit.log", "-p", "db=/var/log/kubernetes/audit/fluentbit.db", "-o", "stdout"},
-						VolumeMounts: []corev1.VolumeMount{
-							{
-								Name:      resources.AuditLogVolumeName,
-								MountPath: "/var/log/kubernetes/audit",
-								ReadOnly:  false,
-							},
-						},
-						Resources: corev1.ResourceRequirements{
-							Requests: corev1.ResourceList{
-								corev1.ResourceMemory: resource.MustParse("10Mi"),
-								corev1.ResourceCPU:    resource.MustParse("5m"),
-							},
-							Limits: corev1.ResourceList{
-								corev1.ResourceMemory: resource.MustParse("60Mi"),
-								corev1.ResourceCPU:    resource.MustParse("50m"),
-							},
-						},
-					},
-				)
+			if auditLogsSidecarNeeded(data) {
+				dep.Spec.Template.Spec.Containers = append(dep.Spec.Template.Spec.Containers, auditLogsSidecar(data))
 			}
 
 			dep.Spec.Template.Spec.Affinity = resources.HostnameAntiAffinity(name, data.Cluster().Name)
@@ -428,7 +405,12 @@ func getApiserverFlags(data *resources.TemplateData, etcdEndpoints []string, ena
 		)
 	}
 
-	if fg := data.GetCSIMigrationFeatureGates(); len(fg) > 0 {
+	fg := data.GetCSIMigrationFeatureGates()
+	// KMS v2 is in beta and enabled by default since Kubernetes 1.27
+	if usesKMS(cluster) && cluster.Spec.Version.Semver().Minor() < 27 {
+		fg = append(fg, "KMSv2=true")
+	}
+	if len(fg) > 0 {
 		flags = append(flags, "--feature-gates")
 		flags = append(flags, strings.Join(fg, ","))
 	}
@@ -438,6 +420,9 @@ func getApiserverFlags(data *resources.TemplateData, etcdEndpoints []string, ena
 			"/etc/kubernetes/konnectivity/egress-selector-configuration.yaml")
 	}
 
+	flags = append(flags, getEncryptionFlags(cluster)...)
+	flags = append(flags, getAuditSinksFlags(data)...)
+
 	return flags, nil
 }
 
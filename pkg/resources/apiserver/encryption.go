@@ -0,0 +1,252 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	encryptionConfigurationMountPath = "/etc/kubernetes/encryption-configuration"
+
+	// KMSPluginSocketDir is the directory on the seed nodes that contains the sockets of KMS plugins.
+	// It is mounted into the apiserver pods at the same path.
+	KMSPluginSocketDir  = "/var/run/kmsplugin"
+	kmsPluginVolumeName = "kms-plugin"
+
+	defaultKMSTimeout = 3 * time.Second
+)
+
+// The EncryptionConfiguration types of k8s.io/apiserver do not support KMS v2 yet, so the
+// configuration is rendered from these types.
+type encryptionConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	Resources []resourceConfiguration `json:"resources"`
+}
+
+type resourceConfiguration struct {
+	Resources []string                `json:"resources"`
+	Providers []providerConfiguration `json:"providers"`
+}
+
+type providerConfiguration struct {
+	AESCBC    *keysConfiguration `json:"aescbc,omitempty"`
+	Secretbox *keysConfiguration `json:"secretbox,omitempty"`
+	KMS       *kmsConfiguration  `json:"kms,omitempty"`
+	Identity  *struct{}          `json:"identity,omitempty"`
+}
+
+type keysConfiguration struct {
+	Keys []keyConfiguration `json:"keys"`
+}
+
+type keyConfiguration struct {
+	Name   string `json:"name"`
+	Secret string `json:"secret"`
+}
+
+type kmsConfiguration struct {
+	APIVersion string          `json:"apiVersion"`
+	Name       string          `json:"name"`
+	Endpoint   string          `json:"endpoint"`
+	Timeout    metav1.Duration `json:"timeout"`
+}
+
+// EncryptionKeySecretKey returns the key of the encryption keys Secret that contains the given key.
+func EncryptionKeySecretKey(key kubermaticv1.EncryptionKey) string {
+	return fmt.Sprintf("%s-%s", key.Provider, key.Name)
+}
+
+// EncryptionConfiguration renders the encryption configuration of the apiserver. The resources
+// are encrypted with the first of the keys and can be decrypted with all of them, as well as read
+// if they are stored unencrypted. The decrypted resources are written unencrypted, but can still
+// be decrypted with the keys until they have been rewritten. keySecrets contains the secrets of
+// the keys generated by Kubermatic, by their EncryptionKeySecretKey.
+func EncryptionConfiguration(keys []kubermaticv1.EncryptionKey, encryptedResources, decryptedResources []string, keySecrets map[string][]byte) ([]byte, error) {
+	var providers []providerConfiguration
+	hasIdentity := false
+	for _, key := range keys {
+		provider, err := encryptionProvider(key, keySecrets)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, provider)
+		hasIdentity = hasIdentity || key.Provider == kubermaticv1.EncryptionProviderIdentity
+	}
+
+	config := encryptionConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "EncryptionConfiguration",
+			APIVersion: "apiserver.config.k8s.io/v1",
+		},
+	}
+
+	if len(encryptedResources) > 0 {
+		resourceProviders := providers
+		if !hasIdentity {
+			resourceProviders = append(resourceProviders, providerConfiguration{Identity: &struct{}{}})
+		}
+		config.Resources = append(config.Resources, resourceConfiguration{
+			Resources: encryptedResources,
+			Providers: resourceProviders,
+		})
+	}
+
+	if len(decryptedResources) > 0 {
+		resourceProviders := []providerConfiguration{{Identity: &struct{}{}}}
+		for i, key := range keys {
+			if key.Provider != kubermaticv1.EncryptionProviderIdentity {
+				resourceProviders = append(resourceProviders, providers[i])
+			}
+		}
+		config.Resources = append(config.Resources, resourceConfiguration{
+			Resources: decryptedResources,
+			Providers: resourceProviders,
+		})
+	}
+
+	return yaml.Marshal(config)
+}
+
+func encryptionProvider(key kubermaticv1.EncryptionKey, keySecrets map[string][]byte) (providerConfiguration, error) {
+	switch key.Provider {
+	case kubermaticv1.EncryptionProviderIdentity:
+		return providerConfiguration{Identity: &struct{}{}}, nil
+
+	case kubermaticv1.EncryptionProviderKMS:
+		if key.KMS == nil {
+			return providerConfiguration{}, fmt.Errorf("KMS key %s has no KMS configuration", key.Name)
+		}
+		timeout := metav1.Duration{Duration: defaultKMSTimeout}
+		if key.KMS.Timeout != nil {
+			timeout = *key.KMS.Timeout
+		}
+		return providerConfiguration{
+			KMS: &kmsConfiguration{
+				APIVersion: "v2",
+				Name:       key.KMS.Name,
+				Endpoint:   key.KMS.Endpoint,
+				Timeout:    timeout,
+			},
+		}, nil
+
+	case kubermaticv1.EncryptionProviderAESCBC, kubermaticv1.EncryptionProviderSecretbox:
+		secret, ok := keySecrets[EncryptionKeySecretKey(key)]
+		if !ok {
+			return providerConfiguration{}, fmt.Errorf("no secret for %s key %s", key.Provider, key.Name)
+		}
+		keys := &keysConfiguration{
+			Keys: []keyConfiguration{
+				{
+					Name:   key.Name,
+					Secret: base64.StdEncoding.EncodeToString(secret),
+				},
+			},
+		}
+		if key.Provider == kubermaticv1.EncryptionProviderAESCBC {
+			return providerConfiguration{AESCBC: keys}, nil
+		}
+		return providerConfiguration{Secretbox: keys}, nil
+
+	default:
+		return providerConfiguration{}, fmt.Errorf("unknown encryption provider %q", key.Provider)
+	}
+}
+
+// usesKMS tells if the apiserver of the cluster is configured with a KMS plugin.
+func usesKMS(cluster *kubermaticv1.Cluster) bool {
+	if cluster.Status.Encryption == nil {
+		return false
+	}
+	for _, key := range cluster.Status.Encryption.Keys {
+		if key.Provider == kubermaticv1.EncryptionProviderKMS {
+			return true
+		}
+	}
+	return false
+}
+
+func getEncryptionFlags(cluster *kubermaticv1.Cluster) []string {
+	if cluster.Status.Encryption == nil {
+		return nil
+	}
+	return []string{"--encryption-provider-config", filepath.Join(encryptionConfigurationMountPath, resources.EncryptionConfigurationSecretKey)}
+}
+
+func getEncryptionVolumeMounts(cluster *kubermaticv1.Cluster) []corev1.VolumeMount {
+	if cluster.Status.Encryption == nil {
+		return nil
+	}
+
+	vms := []corev1.VolumeMount{
+		{
+			Name:      resources.EncryptionConfigurationSecretName,
+			MountPath: encryptionConfigurationMountPath,
+			ReadOnly:  true,
+		},
+	}
+	if usesKMS(cluster) {
+		vms = append(vms, corev1.VolumeMount{
+			Name:      kmsPluginVolumeName,
+			MountPath: KMSPluginSocketDir,
+		})
+	}
+
+	return vms
+}
+
+func getEncryptionVolumes(cluster *kubermaticv1.Cluster) []corev1.Volume {
+	if cluster.Status.Encryption == nil {
+		return nil
+	}
+
+	vs := []corev1.Volume{
+		{
+			Name: resources.EncryptionConfigurationSecretName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: resources.EncryptionConfigurationSecretName,
+				},
+			},
+		},
+	}
+	if usesKMS(cluster) {
+		hostPathType := corev1.HostPathDirectory
+		vs = append(vs, corev1.Volume{
+			Name: kmsPluginVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: KMSPluginSocketDir,
+					Type: &hostPathType,
+				},
+			},
+		})
+	}
+
+	return vs
+}
@@ -30,7 +30,7 @@ import (
 )
 
 // ServiceCreator returns the function to reconcile the external API server service
-func ServiceCreator(exposeStrategy kubermaticv1.ExposeStrategy, externalURL string) reconciling.NamedServiceCreatorGetter {
+func ServiceCreator(exposeStrategy kubermaticv1.ExposeStrategy, externalURL string, allowedIPRanges *kubermaticv1.NetworkRanges) reconciling.NamedServiceCreatorGetter {
 	return func() (string, reconciling.ServiceCreator) {
 		return resources.ApiserverServiceName, func(se *corev1.Service) (*corev1.Service, error) {
 			if se.Annotations == nil {
@@ -63,6 +63,15 @@ func ServiceCreator(exposeStrategy kubermaticv1.ExposeStrategy, externalURL stri
 				return nil, fmt.Errorf("unsupported expose strategy: %q", exposeStrategy)
 			}
 
+			// With the LoadBalancer expose strategy the source IPs are lost
+			// behind the front LoadBalancer, which enforces the allowed
+			// ranges itself.
+			if exposeStrategy != kubermaticv1.ExposeStrategyLoadBalancer && allowedIPRanges != nil && len(allowedIPRanges.CIDRBlocks) > 0 {
+				se.Annotations[nodeportproxy.SourceRangesAnnotationKey] = strings.Join(allowedIPRanges.CIDRBlocks, ",")
+			} else {
+				delete(se.Annotations, nodeportproxy.SourceRangesAnnotationKey)
+			}
+
 			se.Spec.Selector = map[string]string{
 				resources.AppLabelKey: name,
 			}
@@ -20,8 +20,10 @@ import (
 	"testing"
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources/nodeportproxy"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -48,7 +50,7 @@ func TestServiceCreatorRequiresExposeStrategy(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			_, creator := ServiceCreator(tc.exposeStrategy, tc.internalService)()
+			_, creator := ServiceCreator(tc.exposeStrategy, tc.internalService, nil)()
 			_, err := creator(&corev1.Service{})
 			if (err != nil) != tc.errExpected {
 				t.Errorf("Expected err: %t, but got err %v", tc.errExpected, err)
@@ -132,7 +134,7 @@ func TestServiceCreatorSetsPort(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			_, creator := ServiceCreator(tc.exposeStrategy, tc.internalService)()
+			_, creator := ServiceCreator(tc.exposeStrategy, tc.internalService, nil)()
 			svc, err := creator(tc.inService)
 			if err != nil {
 				t.Fatalf("Unexpected error: %v", err)
@@ -155,3 +157,58 @@ func TestServiceCreatorSetsPort(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceCreatorSetsSourceRanges(t *testing.T) {
+	allowedIPRanges := &kubermaticv1.NetworkRanges{CIDRBlocks: []string{"10.0.0.0/8", "192.168.1.1/32"}}
+
+	testCases := []struct {
+		name               string
+		exposeStrategy     kubermaticv1.ExposeStrategy
+		allowedIPRanges    *kubermaticv1.NetworkRanges
+		inService          *corev1.Service
+		expectedAnnotation string
+	}{
+		{
+			name:               "NodePort service gets the source ranges annotation",
+			exposeStrategy:     kubermaticv1.ExposeStrategyNodePort,
+			allowedIPRanges:    allowedIPRanges,
+			inService:          &corev1.Service{},
+			expectedAnnotation: "10.0.0.0/8,192.168.1.1/32",
+		},
+		{
+			name:               "Tunneling service gets the source ranges annotation",
+			exposeStrategy:     kubermaticv1.ExposeStrategyTunneling,
+			allowedIPRanges:    allowedIPRanges,
+			inService:          &corev1.Service{},
+			expectedAnnotation: "10.0.0.0/8,192.168.1.1/32",
+		},
+		{
+			name:            "LoadBalancer service does not get the source ranges annotation",
+			exposeStrategy:  kubermaticv1.ExposeStrategyLoadBalancer,
+			allowedIPRanges: allowedIPRanges,
+			inService:       &corev1.Service{},
+		},
+		{
+			name:           "Source ranges annotation is removed when ranges are unset",
+			exposeStrategy: kubermaticv1.ExposeStrategyNodePort,
+			inService: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{nodeportproxy.SourceRangesAnnotationKey: "10.0.0.0/8"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, creator := ServiceCreator(tc.exposeStrategy, "", tc.allowedIPRanges)()
+			svc, err := creator(tc.inService)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if annotation := svc.Annotations[nodeportproxy.SourceRangesAnnotationKey]; annotation != tc.expectedAnnotation {
+				t.Errorf("Expected source ranges annotation to be %q but was %q", tc.expectedAnnotation, annotation)
+			}
+		})
+	}
+}
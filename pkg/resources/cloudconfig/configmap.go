@@ -22,6 +22,8 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"path"
+	"strings"
 
 	aws "github.com/kubermatic/machine-controller/pkg/cloudprovider/provider/aws/types"
 	azure "github.com/kubermatic/machine-controller/pkg/cloudprovider/provider/azure/types"
@@ -244,6 +246,12 @@ func CloudConfig(
 			// so it has to be kept this way.
 			// tl;dr: use "default" or a full network URL, not "global/networks/default"
 			cloud.GCP.Network = "default"
+		} else if strings.HasPrefix(cloud.GCP.Network, "global/networks/") {
+			// same for dedicated networks of the cluster
+			cloud.GCP.Network = path.Base(cloud.GCP.Network)
+		}
+		if strings.HasPrefix(cloud.GCP.Subnetwork, "regions/") {
+			cloud.GCP.Subnetwork = path.Base(cloud.GCP.Subnetwork)
 		}
 
 		gcpCloudConfig := &gce.CloudConfig{
@@ -47,7 +47,8 @@ func ExternalCloudControllerFeatureSupported(dc *kubermaticv1.Datacenter, cluste
 		return !isOTC(dc.Spec.Openstack) && OpenStackCloudControllerSupported(cluster.Spec.Version)
 
 	case cluster.Spec.Cloud.Hetzner != nil:
-		return dc.Spec.Hetzner.Network != ""
+		// clusters without a network on the datacenter get their own network
+		return true
 
 	case cluster.Spec.Cloud.VSphere != nil:
 		supported, err := version.IsSupported(cluster.Spec.Version.Semver(), kubermaticv1.VSphereCloudProvider, incompatibilities, operatorv1alpha1.ExternalCloudProviderCondition)
@@ -18,11 +18,15 @@ package resources
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
 
 	providerconfig "github.com/kubermatic/machine-controller/pkg/providerconfig/types"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/provider"
 
+	corev1 "k8s.io/api/core/v1"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -188,6 +192,22 @@ func GetCredentials(data CredentialsData) (Credentials, error) {
 	return credentials, err
 }
 
+// GetCredentialsChecksum returns a checksum over all cloud credentials of the cluster. It changes
+// whenever the credentials are rotated, no matter whether they are stored inline or in a Secret.
+func GetCredentialsChecksum(data CredentialsData) (string, error) {
+	credentials, err := GetCredentials(data)
+	if err != nil {
+		return "", err
+	}
+
+	encoded, err := json.Marshal(credentials)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode credentials: %v", err)
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(encoded)), nil
+}
+
 func CopyCredentials(data CredentialsData, cluster *kubermaticv1.Cluster) error {
 	credentials := Credentials{}
 	var err error
@@ -250,7 +270,7 @@ func CopyCredentials(data CredentialsData, cluster *kubermaticv1.Cluster) error
 		cluster.Spec.Cloud.Packet.APIKey = credentials.Packet.APIKey
 	}
 	if data.Cluster().Spec.Cloud.Kubevirt != nil {
-		if credentials.Kubevirt, err = GetKubevirtCredentials(data); err != nil {
+		if credentials.Kubevirt, err = getKubevirtInfraCredentials(data); err != nil {
 			return err
 		}
 		cluster.Spec.Cloud.Kubevirt.Kubeconfig = credentials.Kubevirt.KubeConfig
@@ -279,6 +299,66 @@ func CopyCredentials(data CredentialsData, cluster *kubermaticv1.Cluster) error
 	return nil
 }
 
+// CopyAuditSinkCredentials copies the credentials of the audit sinks of the cluster of data into the
+// same-named audit sinks of cluster. They are copied inline, so that they are moved into the audit
+// sinks credentials secret of cluster, which is the only secret its sinks can reference.
+func CopyAuditSinkCredentials(data CredentialsData, cluster *kubermaticv1.Cluster) error {
+	source := data.Cluster().Spec.AuditLogging
+	if source == nil || cluster.Spec.AuditLogging == nil {
+		return nil
+	}
+
+	sourceSinks := map[string]kubermaticv1.AuditSink{}
+	for _, sink := range source.Sinks {
+		sourceSinks[sink.Name] = sink
+	}
+
+	// the audit logging settings are usually shared with the source cluster
+	cluster.Spec.AuditLogging = cluster.Spec.AuditLogging.DeepCopy()
+
+	var err error
+	for i := range cluster.Spec.AuditLogging.Sinks {
+		sink := &cluster.Spec.AuditLogging.Sinks[i]
+		sourceSink := sourceSinks[sink.Name]
+
+		if sink.Webhook != nil && sourceSink.Webhook != nil {
+			if sink.Webhook.Token, err = GetAuditSinkCredential(data, sourceSink.Webhook.Token, sourceSink.Webhook.CredentialsReference, AuditSinkToken); err != nil {
+				return err
+			}
+			sink.Webhook.CredentialsReference = nil
+		}
+		if sink.HTTP != nil && sourceSink.HTTP != nil {
+			if sink.HTTP.Username, err = GetAuditSinkCredential(data, sourceSink.HTTP.Username, sourceSink.HTTP.CredentialsReference, AuditSinkUsername); err != nil {
+				return err
+			}
+			if sink.HTTP.Password, err = GetAuditSinkCredential(data, sourceSink.HTTP.Password, sourceSink.HTTP.CredentialsReference, AuditSinkPassword); err != nil {
+				return err
+			}
+			sink.HTTP.CredentialsReference = nil
+		}
+		if sink.Loki != nil && sourceSink.Loki != nil {
+			if sink.Loki.Username, err = GetAuditSinkCredential(data, sourceSink.Loki.Username, sourceSink.Loki.CredentialsReference, AuditSinkUsername); err != nil {
+				return err
+			}
+			if sink.Loki.Password, err = GetAuditSinkCredential(data, sourceSink.Loki.Password, sourceSink.Loki.CredentialsReference, AuditSinkPassword); err != nil {
+				return err
+			}
+			sink.Loki.CredentialsReference = nil
+		}
+		if sink.S3 != nil && sourceSink.S3 != nil {
+			if sink.S3.AccessKeyID, err = GetAuditSinkCredential(data, sourceSink.S3.AccessKeyID, sourceSink.S3.CredentialsReference, AWSAccessKeyID); err != nil {
+				return err
+			}
+			if sink.S3.SecretAccessKey, err = GetAuditSinkCredential(data, sourceSink.S3.SecretAccessKey, sourceSink.S3.CredentialsReference, AWSSecretAccessKey); err != nil {
+				return err
+			}
+			sink.S3.CredentialsReference = nil
+		}
+	}
+
+	return nil
+}
+
 func GetAWSCredentials(data CredentialsData) (AWSCredentials, error) {
 	spec := data.Cluster().Spec.Cloud.AWS
 	awsCredentials := AWSCredentials{}
@@ -527,7 +607,29 @@ func GetPacketCredentials(data CredentialsData) (PacketCredentials, error) {
 	return packetCredentials, nil
 }
 
+// GetKubevirtCredentials returns the kubeconfig that is handed to the components of the cluster. For
+// clusters with their own namespace in the KubeVirt infra cluster, this kubeconfig is restricted to that
+// namespace.
 func GetKubevirtCredentials(data CredentialsData) (KubevirtCredentials, error) {
+	cluster := data.Cluster()
+	if cluster.Spec.Cloud.Kubevirt.Namespace == "" {
+		return getKubevirtInfraCredentials(data)
+	}
+
+	kubeconfig, err := data.GetGlobalSecretKeySelectorValue(&providerconfig.GlobalSecretKeySelector{
+		ObjectReference: corev1.ObjectReference{
+			Name:      KubevirtInfraKubeconfigSecretName,
+			Namespace: cluster.Status.NamespaceName,
+		},
+	}, KubevirtKubeConfig)
+	if err != nil {
+		return KubevirtCredentials{}, err
+	}
+
+	return KubevirtCredentials{KubeConfig: kubeconfig}, nil
+}
+
+func getKubevirtInfraCredentials(data CredentialsData) (KubevirtCredentials, error) {
 	spec := data.Cluster().Spec.Cloud.Kubevirt
 	kubevirtCredentials := KubevirtCredentials{}
 	var err error
@@ -621,3 +723,21 @@ func GetAnexiaCredentials(data CredentialsData) (AnexiaCredentials, error) {
 
 	return anexiaCredentials, nil
 }
+
+// AuditSinkCredentialKey returns the key of a credential of an audit sink in the referenced secret,
+// which is prefixed with the key of the reference if it is set.
+func AuditSinkCredentialKey(configVar *providerconfig.GlobalSecretKeySelector, key string) string {
+	if configVar.Key == "" {
+		return key
+	}
+	return fmt.Sprintf("%s-%s", configVar.Key, key)
+}
+
+// GetAuditSinkCredential returns a credential of an audit sink, which is either given inline or read
+// from the referenced secret. It is empty if neither is set.
+func GetAuditSinkCredential(data CredentialsData, value string, configVar *providerconfig.GlobalSecretKeySelector, key string) (string, error) {
+	if value != "" || configVar == nil {
+		return value, nil
+	}
+	return data.GetGlobalSecretKeySelectorValue(configVar, AuditSinkCredentialKey(configVar, key))
+}
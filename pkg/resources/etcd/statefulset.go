@@ -18,6 +18,7 @@ package etcd
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"text/template"
@@ -40,6 +41,16 @@ const (
 	name = "etcd"
 
 	dataDir = "/var/run/etcd/pod_${POD_NAME}/"
+
+	// launcherMetricsPort is the port etcd-launcher exposes its defragmentation metrics on.
+	launcherMetricsPort = 2382
+)
+
+var (
+	// defaultQuotaBackendBytes is the space quota etcd uses if none is configured.
+	defaultQuotaBackendBytes = resource.MustParse("2Gi")
+	minQuotaBackendBytes     = resource.MustParse("2Gi")
+	maxQuotaBackendBytes     = resource.MustParse("8Gi")
 )
 
 var (
@@ -188,8 +199,16 @@ func StatefulSetCreator(data etcdStatefulSetCreatorData, enableDataCorruptionChe
 					Name:          "peer-tls",
 				})
 
+				etcdPorts = append(etcdPorts, corev1.ContainerPort{
+					ContainerPort: launcherMetricsPort,
+					Protocol:      corev1.ProtocolTCP,
+					Name:          "metrics",
+				})
+
 				set.Spec.Template.ObjectMeta.Annotations = map[string]string{
 					resources.EtcdTLSEnabledAnnotation: "",
+					"prometheus.io/scrape":             "true",
+					"prometheus.io/port":               strconv.Itoa(launcherMetricsPort),
 				}
 
 				if enableTLSOnly {
@@ -197,7 +216,8 @@ func StatefulSetCreator(data etcdStatefulSetCreatorData, enableDataCorruptionChe
 				}
 			}
 
-			etcdStartCmd, err := getEtcdCommand(data.Cluster().Name, data.Cluster().Status.NamespaceName, enableDataCorruptionChecks, launcherEnabled)
+			quota := quotaBackendBytes(data.Cluster().Spec.ComponentsOverride.Etcd, getDiskSize(data, set))
+			etcdStartCmd, err := getEtcdCommand(data.Cluster().Name, data.Cluster().Status.NamespaceName, enableDataCorruptionChecks, launcherEnabled, quota)
 			if err != nil {
 				return nil, err
 			}
@@ -280,6 +300,20 @@ func StatefulSetCreator(data etcdStatefulSetCreatorData, enableDataCorruptionChe
 				set.Spec.Template.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = antiAffinities
 			}
 
+			// while restoring from a filesystem backup destination, etcd-launcher reads the backup from its volume
+			restoreVolume, err := getRestoreBackupVolume(data.Cluster())
+			if err != nil {
+				return nil, err
+			}
+			if restoreVolume != nil {
+				volumes = append(volumes, *restoreVolume)
+				set.Spec.Template.Spec.Containers[0].VolumeMounts = append(set.Spec.Template.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+					Name:      restoreVolume.Name,
+					MountPath: resources.EtcdBackupFilesystemMountPath,
+					ReadOnly:  true,
+				})
+			}
+
 			set.Spec.Template.Spec.Volumes = volumes
 
 			// Make sure we don't change volume claim template of existing sts
@@ -288,11 +322,7 @@ func StatefulSetCreator(data etcdStatefulSetCreatorData, enableDataCorruptionChe
 				if storageClass == "" {
 					storageClass = "kubermatic-fast"
 				}
-				diskSize := data.Cluster().Spec.ComponentsOverride.Etcd.DiskSize
-				if diskSize == nil {
-					d := data.EtcdDiskSize()
-					diskSize = &d
-				}
+				diskSize := getDiskSize(data, set)
 				set.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{
 					{
 						ObjectMeta: metav1.ObjectMeta{
@@ -303,7 +333,7 @@ func StatefulSetCreator(data etcdStatefulSetCreatorData, enableDataCorruptionChe
 							StorageClassName: resources.String(storageClass),
 							AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
 							Resources: corev1.ResourceRequirements{
-								Requests: corev1.ResourceList{corev1.ResourceStorage: *diskSize},
+								Requests: corev1.ResourceList{corev1.ResourceStorage: diskSize},
 							},
 						},
 					},
@@ -315,6 +345,21 @@ func StatefulSetCreator(data etcdStatefulSetCreatorData, enableDataCorruptionChe
 	}
 }
 
+// getRestoreBackupVolume returns the volume recorded in the cluster's EtcdRestoreBackupVolumeAnnotation, if any.
+func getRestoreBackupVolume(cluster *kubermaticv1.Cluster) (*corev1.Volume, error) {
+	value := cluster.Annotations[resources.EtcdRestoreBackupVolumeAnnotation]
+	if value == "" {
+		return nil, nil
+	}
+
+	volume := &corev1.Volume{Name: "backup-store"}
+	if err := json.Unmarshal([]byte(value), &volume.VolumeSource); err != nil {
+		return nil, fmt.Errorf("failed to parse %s annotation: %v", resources.EtcdRestoreBackupVolumeAnnotation, err)
+	}
+
+	return volume, nil
+}
+
 func getVolumes() []corev1.Volume {
 	return []corev1.Volume{
 		{
@@ -377,7 +422,7 @@ func computeReplicas(data etcdStatefulSetCreatorData, set *appsv1.StatefulSet) i
 	if !data.Cluster().Spec.Features[kubermaticv1.ClusterFeatureEtcdLauncher] {
 		return kubermaticv1.DefaultEtcdClusterSize
 	}
-	etcdClusterSize := getClusterSize(data.Cluster().Spec.ComponentsOverride.Etcd)
+	etcdClusterSize := data.Cluster().Spec.ComponentsOverride.Etcd.GetClusterSize()
 	if set.Spec.Replicas == nil { // new replicaset
 		return etcdClusterSize
 	}
@@ -386,27 +431,54 @@ func computeReplicas(data etcdStatefulSetCreatorData, set *appsv1.StatefulSet) i
 	if etcdClusterSize == replicas {
 		return replicas
 	}
+	if etcdClusterSize < replicas {
+		// the statefulset deletes the pod with the highest ordinal, so etcd-launcher has to remove its
+		// member from the etcd cluster first, otherwise quorum is lost until the dead member is cleaned up
+		if data.Cluster().Annotations[resources.EtcdRemovedMemberAnnotation] == fmt.Sprintf("%s-%d", resources.EtcdStatefulSetName, replicas-1) {
+			return replicas - 1
+		}
+		return replicas
+	}
 	isEtcdHealthy := data.Cluster().Status.ExtendedHealth.Etcd == kubermaticv1.HealthStatusUp
 	if isEtcdHealthy { // no scaling until we are healthy
-		if etcdClusterSize > replicas {
-			return replicas + 1
-		}
-		return replicas - 1
+		return replicas + 1
 	}
 	return replicas
 }
 
-func getClusterSize(settings kubermaticv1.EtcdStatefulSetSettings) int32 {
-	if settings.ClusterSize == nil {
-		return kubermaticv1.DefaultEtcdClusterSize
+// getDiskSize returns the size of the etcd data volumes. Volume claim templates of existing
+// statefulsets can't be changed, so their size takes precedence over the cluster's settings.
+func getDiskSize(data etcdStatefulSetCreatorData, set *appsv1.StatefulSet) resource.Quantity {
+	for _, claim := range set.Spec.VolumeClaimTemplates {
+		if size, ok := claim.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+			return size
+		}
+	}
+	if diskSize := data.Cluster().Spec.ComponentsOverride.Etcd.DiskSize; diskSize != nil {
+		return *diskSize
 	}
-	if *settings.ClusterSize < kubermaticv1.MinEtcdClusterSize {
-		return kubermaticv1.MinEtcdClusterSize
+	return data.EtcdDiskSize()
+}
+
+// quotaBackendBytes returns the configured etcd space quota or, if none is configured, 75% of the
+// disk size clamped between 2Gi and 8Gi, leaving room for the write-ahead log and snapshots.
+// 0 is returned if the quota equals etcd's default, so that the flag is only added to the etcd
+// command (and the StatefulSet rolled) where it actually changes the quota.
+func quotaBackendBytes(settings kubermaticv1.EtcdStatefulSetSettings, diskSize resource.Quantity) int64 {
+	quota := diskSize.Value() / 4 * 3
+	switch {
+	case settings.QuotaBackendBytes != nil:
+		quota = settings.QuotaBackendBytes.Value()
+	case quota < minQuotaBackendBytes.Value():
+		quota = minQuotaBackendBytes.Value()
+	case quota > maxQuotaBackendBytes.Value():
+		quota = maxQuotaBackendBytes.Value()
 	}
-	if *settings.ClusterSize > kubermaticv1.MaxEtcdClusterSize {
-		return kubermaticv1.MaxEtcdClusterSize
+
+	if quota == defaultQuotaBackendBytes.Value() {
+		return 0
 	}
-	return *settings.ClusterSize
+	return quota
 }
 
 type commandTplData struct {
@@ -418,7 +490,7 @@ type commandTplData struct {
 	EnableCorruptionCheck bool
 }
 
-func getEtcdCommand(name, namespace string, enableCorruptionCheck, launcherEnabled bool) ([]string, error) {
+func getEtcdCommand(name, namespace string, enableCorruptionCheck, launcherEnabled bool, quotaBackendBytes int64) ([]string, error) {
 	if launcherEnabled {
 		command := []string{"/opt/bin/etcd-launcher",
 			"-namespace", "$(NAMESPACE)",
@@ -429,6 +501,9 @@ func getEtcdCommand(name, namespace string, enableCorruptionCheck, launcherEnabl
 		if enableCorruptionCheck {
 			command = append(command, "-enable-corruption-check")
 		}
+		if quotaBackendBytes > 0 {
+			command = append(command, "-quota-backend-bytes", strconv.FormatInt(quotaBackendBytes, 10))
+		}
 		return command, nil
 	}
 
@@ -22,7 +22,12 @@ import (
 	"strings"
 	"testing"
 
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
 	testhelper "k8c.io/kubermatic/v2/pkg/test"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 var update = flag.Bool("update", false, "update .golden files")
@@ -35,6 +40,7 @@ func TestGetEtcdCommand(t *testing.T) {
 		clusterNamespace      string
 		enableCorruptionCheck bool
 		launcherEnabled       bool
+		quotaBackendBytes     int64
 		expectedArgs          int
 	}{
 		{
@@ -44,6 +50,14 @@ func TestGetEtcdCommand(t *testing.T) {
 			launcherEnabled:  true,
 			expectedArgs:     11,
 		},
+		{
+			name:              "with-launcher-quota",
+			clusterName:       "62m9k9tqlm",
+			clusterNamespace:  "cluster-62m9k9tqlm",
+			launcherEnabled:   true,
+			quotaBackendBytes: 2147483648,
+			expectedArgs:      13,
+		},
 		{
 			name:                  "with-corruption-flags",
 			clusterName:           "lg69pmx8wf",
@@ -56,7 +70,7 @@ func TestGetEtcdCommand(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			args, err := getEtcdCommand(test.clusterName, test.clusterNamespace, test.enableCorruptionCheck, test.launcherEnabled)
+			args, err := getEtcdCommand(test.clusterName, test.clusterNamespace, test.enableCorruptionCheck, test.launcherEnabled, test.quotaBackendBytes)
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
@@ -73,3 +87,130 @@ func TestGetEtcdCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestQuotaBackendBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings kubermaticv1.EtcdStatefulSetSettings
+		diskSize string
+		expected string
+	}{
+		{
+			name:     "small disks use the default quota",
+			diskSize: "1Gi",
+			expected: "0",
+		},
+		{
+			name: "configured default quota is omitted",
+			settings: kubermaticv1.EtcdStatefulSetSettings{
+				QuotaBackendBytes: resource.NewQuantity(2<<30, resource.BinarySI),
+			},
+			diskSize: "50Gi",
+			expected: "0",
+		},
+		{
+			name:     "quota is derived from the disk size",
+			diskSize: "8Gi",
+			expected: "6Gi",
+		},
+		{
+			name:     "large disks use the maximum quota",
+			diskSize: "50Gi",
+			expected: "8Gi",
+		},
+		{
+			name: "configured quota takes precedence",
+			settings: kubermaticv1.EtcdStatefulSetSettings{
+				QuotaBackendBytes: resource.NewQuantity(1<<30, resource.BinarySI),
+			},
+			diskSize: "50Gi",
+			expected: "1Gi",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			quota := quotaBackendBytes(test.settings, resource.MustParse(test.diskSize))
+			if expected := resource.MustParse(test.expected); quota != expected.Value() {
+				t.Errorf("expected quota of %d bytes, got %d", expected.Value(), quota)
+			}
+		})
+	}
+}
+
+type computeReplicasData struct {
+	etcdStatefulSetCreatorData
+	cluster *kubermaticv1.Cluster
+}
+
+func (d computeReplicasData) Cluster() *kubermaticv1.Cluster {
+	return d.cluster
+}
+
+func TestComputeReplicas(t *testing.T) {
+	tests := []struct {
+		name           string
+		clusterSize    int32
+		replicas       int32
+		healthy        bool
+		removedMember  string
+		expectedResult int32
+	}{
+		{
+			name:           "scale up one member at a time",
+			clusterSize:    5,
+			replicas:       3,
+			healthy:        true,
+			expectedResult: 4,
+		},
+		{
+			name:           "no scale up while unhealthy",
+			clusterSize:    5,
+			replicas:       3,
+			expectedResult: 3,
+		},
+		{
+			name:           "no scale down before the member was removed",
+			clusterSize:    3,
+			replicas:       5,
+			healthy:        true,
+			expectedResult: 5,
+		},
+		{
+			name:           "scale down after the last member was removed",
+			clusterSize:    3,
+			replicas:       5,
+			removedMember:  "etcd-4",
+			expectedResult: 4,
+		},
+		{
+			name:           "no further scale down for a previously removed member",
+			clusterSize:    3,
+			replicas:       4,
+			healthy:        true,
+			removedMember:  "etcd-4",
+			expectedResult: 4,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cluster := &kubermaticv1.Cluster{}
+			cluster.Spec.Features = map[string]bool{kubermaticv1.ClusterFeatureEtcdLauncher: true}
+			cluster.Spec.ComponentsOverride.Etcd.ClusterSize = &test.clusterSize
+			if test.healthy {
+				cluster.Status.ExtendedHealth.Etcd = kubermaticv1.HealthStatusUp
+			}
+			if test.removedMember != "" {
+				cluster.Annotations = map[string]string{resources.EtcdRemovedMemberAnnotation: test.removedMember}
+			}
+
+			set := &appsv1.StatefulSet{}
+			set.Spec.Replicas = &test.replicas
+
+			if replicas := computeReplicas(computeReplicasData{cluster: cluster}, set); replicas != test.expectedResult {
+				t.Errorf("expected %d replicas, got %d", test.expectedResult, replicas)
+			}
+		})
+	}
+}
@@ -0,0 +1 @@
+/opt/bin/etcd-launcher -namespace $(NAMESPACE) -pod-name $(POD_NAME) -pod-ip $(POD_IP) -api-version $(ETCDCTL_API) -token $(TOKEN) -quota-backend-bytes 2147483648
\ No newline at end of file
@@ -264,12 +264,24 @@ func getOpenstackProviderSpec(c *kubermaticv1.Cluster, nodeSpec apiv1.NodeSpec,
 }
 
 func getHetznerProviderSpec(c *kubermaticv1.Cluster, nodeSpec apiv1.NodeSpec, dc *kubermaticv1.Datacenter) (*runtime.RawExtension, error) {
+	var network, firewall string
+	if c.Spec.Cloud.Hetzner != nil {
+		network = c.Spec.Cloud.Hetzner.Network
+		firewall = c.Spec.Cloud.Hetzner.Firewall
+	}
+	if network == "" {
+		network = dc.Spec.Hetzner.Network
+	}
+
 	config := hetzner.RawConfig{
 		Datacenter: providerconfig.ConfigVarString{Value: dc.Spec.Hetzner.Datacenter},
 		Location:   providerconfig.ConfigVarString{Value: dc.Spec.Hetzner.Location},
-		Networks:   []providerconfig.ConfigVarString{{Value: dc.Spec.Hetzner.Network}},
+		Networks:   []providerconfig.ConfigVarString{{Value: network}},
 		ServerType: providerconfig.ConfigVarString{Value: nodeSpec.Cloud.Hetzner.Type},
 	}
+	if firewall != "" {
+		config.Firewalls = []providerconfig.ConfigVarString{{Value: firewall}}
+	}
 
 	ext := &runtime.RawExtension{}
 	b, err := json.Marshal(config)
@@ -381,13 +393,19 @@ func getGCPProviderSpec(c *kubermaticv1.Cluster, nodeSpec apiv1.NodeSpec, dc *ku
 	return ext, nil
 }
 
-func getKubevirtProviderSpec(nodeSpec apiv1.NodeSpec, dc *kubermaticv1.Datacenter) (*runtime.RawExtension, error) {
+func getKubevirtProviderSpec(c *kubermaticv1.Cluster, nodeSpec apiv1.NodeSpec, dc *kubermaticv1.Datacenter) (*runtime.RawExtension, error) {
+	// clusters with their own namespace in the infra cluster can only place virtual machines there
+	namespace := nodeSpec.Cloud.Kubevirt.Namespace
+	if c.Spec.Cloud.Kubevirt != nil && c.Spec.Cloud.Kubevirt.Namespace != "" {
+		namespace = c.Spec.Cloud.Kubevirt.Namespace
+	}
+
 	config := kubevirt.RawConfig{
 		CPUs:             providerconfig.ConfigVarString{Value: nodeSpec.Cloud.Kubevirt.CPUs},
 		PVCSize:          providerconfig.ConfigVarString{Value: nodeSpec.Cloud.Kubevirt.PVCSize},
 		StorageClassName: providerconfig.ConfigVarString{Value: nodeSpec.Cloud.Kubevirt.StorageClassName},
 		SourceURL:        providerconfig.ConfigVarString{Value: nodeSpec.Cloud.Kubevirt.SourceURL},
-		Namespace:        providerconfig.ConfigVarString{Value: nodeSpec.Cloud.Kubevirt.Namespace},
+		Namespace:        providerconfig.ConfigVarString{Value: namespace},
 		Memory:           providerconfig.ConfigVarString{Value: nodeSpec.Cloud.Kubevirt.Memory},
 		DNSPolicy:        providerconfig.ConfigVarString{Value: dc.Spec.Kubevirt.DNSPolicy},
 		DNSConfig:        dc.Spec.Kubevirt.DNSConfig,
@@ -50,6 +50,11 @@ const (
 	// exposed and the hostname, this is only used when the ExposeType is
 	// SNIType.
 	PortHostMappingAnnotationKey = "nodeport-proxy.k8s.io/port-mapping"
+	// SourceRangesAnnotationKey contains the comma separated list of CIDRs the
+	// service can be accessed from. The service can be accessed from anywhere
+	// if it is not set. The client IPs are only preserved if the seed has
+	// preserveSourceIPs enabled for the nodeport-proxy.
+	SourceRangesAnnotationKey = "nodeport-proxy.k8s.io/source-ranges"
 )
 
 // ExposeType defines the strategy used to expose the service.
@@ -447,6 +452,13 @@ func FrontLoadBalancerServiceCreator(data *resources.TemplateData) reconciling.N
 				// Load-balance across nodes in all zones to ensure HA if nodes in a DNS-selected zone are not available
 				s.Annotations["service.beta.kubernetes.io/aws-load-balancer-cross-zone-load-balancing-enabled"] = "true"
 			}
+			// The source IPs are not preserved up to the envoy, so access
+			// to the apiserver must be restricted by the LoadBalancer itself.
+			if ranges := data.Cluster().Spec.APIServerAllowedIPRanges; ranges != nil && len(ranges.CIDRBlocks) > 0 {
+				s.Spec.LoadBalancerSourceRanges = ranges.CIDRBlocks
+			} else {
+				s.Spec.LoadBalancerSourceRanges = nil
+			}
 			s.Spec.Selector = resources.BaseAppLabels(envoyAppLabelValue, nil)
 			return s, nil
 		}
@@ -28,11 +28,14 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	minio "github.com/minio/minio-go"
 
+	"k8c.io/kubermatic/v2/pkg/backupstore"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/etcd/encryption"
 	"k8c.io/kubermatic/v2/pkg/resources/certificates/triple"
 	"k8c.io/kubermatic/v2/pkg/resources/reconciling"
 
@@ -174,6 +177,10 @@ const (
 	KubeletClientCertificatesSecretName = "kubelet-client-certificates"
 	// ServiceAccountKeySecretName is the name for the secret containing the service account key
 	ServiceAccountKeySecretName = "service-account-key"
+	// EncryptionKeysSecretName is the name for the secret containing the keys used to encrypt resources of the user cluster
+	EncryptionKeysSecretName = "encryption-keys"
+	// EncryptionConfigurationSecretName is the name for the secret containing the encryption configuration of the apiserver
+	EncryptionConfigurationSecretName = "apiserver-encryption-configuration"
 	// TokensSecretName is the name for the secret containing the user tokens
 	TokensSecretName = "tokens"
 	// ViewerTokenSecretName is the name for the secret containing the viewer token
@@ -198,8 +205,20 @@ const (
 	GoogleServiceAccountSecretName = "google-service-account"
 	// GoogleServiceAccountVolumeName is the name of the volume containing the Google Service Account secret.
 	GoogleServiceAccountVolumeName = "google-service-account-volume"
+	// KubevirtInfraKubeconfigSecretName is the name of the secret that contains the kubeconfig for the
+	// namespace of the cluster in the KubeVirt infra cluster.
+	KubevirtInfraKubeconfigSecretName = "kubevirt-infra-kubeconfig"
 	// AuditLogVolumeName is the name of the volume that hold the audit log of the apiserver.
 	AuditLogVolumeName = "audit-log"
+	// AuditSinksSecretName is the name of the secret that contains the configuration of the sinks audit
+	// events of the apiserver are sent to.
+	AuditSinksSecretName = "audit-sinks"
+	// AuditLogsMLACertificatesSecretName is the name of the secret that contains the client certificate
+	// the audit-logs sidecar of the apiserver sends audit events to the MLA Gateway with.
+	AuditLogsMLACertificatesSecretName = "audit-logs-mla-certificates"
+	// AuditLogsMLACertificateCommonName is the common name of the client certificate the audit-logs
+	// sidecar of the apiserver sends audit events to the MLA Gateway with.
+	AuditLogsMLACertificateCommonName = "audit-logs"
 	// KubernetesDashboardKeyHolderSecretName is the name of the secret that contains JWE token encryption key
 	// used by the Kubernetes Dashboard
 	KubernetesDashboardKeyHolderSecretName = "kubernetes-dashboard-key-holder"
@@ -459,6 +478,8 @@ const (
 	ServiceAccountKeySecretKey = "sa.key"
 	// ServiceAccountKeyPublicKey is the public key for the service account signer key
 	ServiceAccountKeyPublicKey = "sa.pub"
+	// EncryptionConfigurationSecretKey encryption-configuration.yaml
+	EncryptionConfigurationSecretKey = "encryption-configuration.yaml"
 	// KubeconfigSecretKey kubeconfig
 	KubeconfigSecretKey = "kubeconfig"
 	// TokensSecretKey tokens.csv
@@ -494,6 +515,23 @@ const (
 	EtcdRestoreS3BucketNameKey     = "BUCKET_NAME"
 	EtcdRestoreS3EndpointKey       = "ENDPOINT"
 	EtcdRestoreDefaultS3SEndpoint  = "s3.amazonaws.com"
+	// EtcdRestoreEncryptionKeyPrefix prefixes the backup encryption keys which are copied
+	// into the BackupDownloadCredentialsSecret of an EtcdRestore.
+	EtcdRestoreEncryptionKeyPrefix = "ENCRYPTION_KEY_"
+	// EtcdBackupFilesystemMountPath is the path the volume of a filesystem backup destination is mounted at.
+	EtcdBackupFilesystemMountPath = "/backup-store"
+	// EtcdRestoreBackupVolumeAnnotation is the cluster annotation holding the JSON encoded volume source of the
+	// filesystem backup destination a restore is running from, the etcd pods mount it while the restore is active.
+	EtcdRestoreBackupVolumeAnnotation = "kubermatic.io/restore-backup-volume"
+	// EtcdReplaceMemberAnnotation is the cluster annotation naming the etcd pod whose member should be removed from
+	// the etcd cluster, wiped and re-joined. etcd-launcher removes the annotation once it picked up the request.
+	EtcdReplaceMemberAnnotation = "kubermatic.io/etcd-replace-member"
+	// EtcdRemovedMemberAnnotation is the cluster annotation etcd-launcher records the etcd pod in whose member it
+	// removed from the etcd cluster to scale it down. The statefulset is only scaled down once it names the last pod.
+	EtcdRemovedMemberAnnotation = "kubermatic.io/etcd-removed-member"
+	// CertificateRenewalAnnotation marks a Secret whose certificates should be reissued by the next
+	// reconciliation of the cluster, regardless of their remaining validity.
+	CertificateRenewalAnnotation = "kubermatic.io/renew-certificate"
 
 	// KubeconfigDefaultContextKey is the context key used for all kubeconfigs
 	KubeconfigDefaultContextKey = "default"
@@ -572,6 +610,10 @@ const (
 
 	AnexiaToken = "token"
 
+	AuditSinkToken    = "token"
+	AuditSinkUsername = "username"
+	AuditSinkPassword = "password"
+
 	UserSSHKeys = "usersshkeys"
 )
 
@@ -1275,12 +1317,41 @@ func BackupCABundleConfigMapName(cluster *kubermaticv1.Cluster) string {
 // one can optionally be created from a well-known secret and configmap in kube-system, or from a specified backup destination
 func GetEtcdRestoreS3Client(ctx context.Context, restore *kubermaticv1.EtcdRestore, createSecretIfMissing bool, client ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster,
 	destination *kubermaticv1.BackupDestination) (*minio.Client, string, error) {
+	secretData, err := getEtcdRestoreSecretData(ctx, restore, createSecretIfMissing, client, cluster, destination)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if backupstore.Type(secretData[backupstore.TypeEnvVar]) != backupstore.TypeS3 {
+		return nil, "", fmt.Errorf("backup destination of type %q is not S3 compatible", secretData[backupstore.TypeEnvVar])
+	}
+
+	return newEtcdBackupS3Client(ctx, client, cluster, secretData)
+}
+
+// GetEtcdRestoreBackupStore returns the store for downloading the backup for a given EtcdRestore, which
+// unlike GetEtcdRestoreS3Client supports all types of backup destinations. Filesystem destinations can only
+// be accessed from pods which mount the destination's volume at EtcdBackupFilesystemMountPath.
+func GetEtcdRestoreBackupStore(ctx context.Context, restore *kubermaticv1.EtcdRestore, createSecretIfMissing bool, client ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster,
+	destination *kubermaticv1.BackupDestination) (backupstore.Store, error) {
+	secretData, err := getEtcdRestoreSecretData(ctx, restore, createSecretIfMissing, client, cluster, destination)
+	if err != nil {
+		return nil, err
+	}
+
+	return newEtcdBackupStore(ctx, client, cluster, secretData)
+}
+
+// getEtcdRestoreSecretData returns the content of the BackupDownloadCredentialsSecret of the given EtcdRestore,
+// creating the secret first if it is missing and createSecretIfMissing is set.
+func getEtcdRestoreSecretData(ctx context.Context, restore *kubermaticv1.EtcdRestore, createSecretIfMissing bool, client ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster,
+	destination *kubermaticv1.BackupDestination) (map[string]string, error) {
 	secretData := make(map[string]string)
 
 	if restore.Spec.BackupDownloadCredentialsSecret != "" {
 		secret := &corev1.Secret{}
 		if err := client.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: restore.Spec.BackupDownloadCredentialsSecret}, secret); err != nil {
-			return nil, "", fmt.Errorf("failed to get BackupDownloadCredentialsSecret credentials secret %v: %v", restore.Spec.BackupDownloadCredentialsSecret, err)
+			return nil, fmt.Errorf("failed to get BackupDownloadCredentialsSecret credentials secret %v: %v", restore.Spec.BackupDownloadCredentialsSecret, err)
 		}
 
 		for k, v := range secret.Data {
@@ -1288,29 +1359,39 @@ func GetEtcdRestoreS3Client(ctx context.Context, restore *kubermaticv1.EtcdResto
 		}
 	} else {
 		if !createSecretIfMissing {
-			return nil, "", fmt.Errorf("BackupDownloadCredentialsSecret not set")
+			return nil, fmt.Errorf("BackupDownloadCredentialsSecret not set")
 		}
 
 		// if destination is set, we need to use the backup credentials and bucket info from it to create the BackupDownloadCredentialsSecret
 		if destination != nil {
-			credsSecret := &corev1.Secret{}
-			if err := client.Get(ctx, types.NamespacedName{Namespace: destination.Credentials.Namespace, Name: destination.Credentials.Name}, credsSecret); err != nil {
-				return nil, "", fmt.Errorf("failed to get s3 credentials secret %v/%v: %w", destination.Credentials.Namespace, destination.Credentials.Name, err)
+			destinationData, err := getBackupDestinationData(ctx, client, destination)
+			if err != nil {
+				return nil, err
 			}
-			for k, v := range credsSecret.Data {
-				secretData[k] = string(v)
+			for k, v := range destinationData {
+				secretData[k] = v
+			}
+
+			// all keys are copied, as older backups might have been encrypted with a previous key
+			if destination.Encryption != nil && destination.Encryption.KeySecret != nil {
+				keySecret := &corev1.Secret{}
+				keySecretRef := destination.Encryption.KeySecret
+				if err := client.Get(ctx, types.NamespacedName{Namespace: keySecretRef.Namespace, Name: keySecretRef.Name}, keySecret); err != nil {
+					return nil, fmt.Errorf("failed to get encryption key secret %v/%v: %w", keySecretRef.Namespace, keySecretRef.Name, err)
+				}
+				for k, v := range keySecret.Data {
+					secretData[EtcdRestoreEncryptionKeyPrefix+k] = string(v)
+				}
 			}
-			secretData[EtcdRestoreS3BucketNameKey] = destination.BucketName
-			secretData[EtcdRestoreS3EndpointKey] = destination.Endpoint
 		} else {
 			// else create BackupDownloadCredentialsSecret containing values from kube-system/backup-s3 / kube-system/s3-settings
 			credsSecret := &corev1.Secret{}
 			if err := client.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: EtcdRestoreS3CredentialsSecret}, credsSecret); err != nil {
-				return nil, "", fmt.Errorf("failed to get s3 credentials secret %v/%v: %w", metav1.NamespaceSystem, EtcdRestoreS3CredentialsSecret, err)
+				return nil, fmt.Errorf("failed to get s3 credentials secret %v/%v: %w", metav1.NamespaceSystem, EtcdRestoreS3CredentialsSecret, err)
 			}
 			settingsConfigMap := &corev1.ConfigMap{}
 			if err := client.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: EtcdRestoreS3SettingsConfigMap}, settingsConfigMap); err != nil {
-				return nil, "", fmt.Errorf("failed to get s3 settings configmap %v/%v: %w", metav1.NamespaceSystem, EtcdRestoreS3SettingsConfigMap, err)
+				return nil, fmt.Errorf("failed to get s3 settings configmap %v/%v: %w", metav1.NamespaceSystem, EtcdRestoreS3SettingsConfigMap, err)
 			}
 			for k, v := range credsSecret.Data {
 				secretData[k] = string(v)
@@ -1339,16 +1420,131 @@ func GetEtcdRestoreS3Client(ctx context.Context, restore *kubermaticv1.EtcdResto
 			ctx,
 			types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: secretName},
 			wrappedCreator, client, &corev1.Secret{}, false); err != nil {
-			return nil, "", fmt.Errorf("failed to ensure Secret %s: %w", secretName, err)
+			return nil, fmt.Errorf("failed to ensure Secret %s: %w", secretName, err)
 		}
 
 		oldRestore := restore.DeepCopy()
 		restore.Spec.BackupDownloadCredentialsSecret = secretName
 		if err := client.Patch(ctx, restore, ctrlruntimeclient.MergeFrom(oldRestore)); err != nil {
-			return nil, "", fmt.Errorf("failed to write etcdrestore.backupDownloadCredentialsSecret: %w", err)
+			return nil, fmt.Errorf("failed to write etcdrestore.backupDownloadCredentialsSecret: %w", err)
 		}
 	}
 
+	// secrets created before the destination type was introduced can only refer to S3 destinations
+	if secretData[backupstore.TypeEnvVar] == "" {
+		secretData[backupstore.TypeEnvVar] = string(backupstore.TypeS3)
+	}
+
+	return secretData, nil
+}
+
+// GetEtcdBackupDestinationS3Client returns an S3 client for accessing the backups of a cluster in the given
+// backup destination, without creating a BackupDownloadCredentialsSecret.
+func GetEtcdBackupDestinationS3Client(ctx context.Context, client ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster,
+	destination *kubermaticv1.BackupDestination) (*minio.Client, string, error) {
+	if destination.GetType() != kubermaticv1.BackupDestinationTypeS3 {
+		return nil, "", fmt.Errorf("backup destination of type %q is not S3 compatible", destination.GetType())
+	}
+
+	secretData, err := getBackupDestinationData(ctx, client, destination)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return newEtcdBackupS3Client(ctx, client, cluster, secretData)
+}
+
+// GetEtcdBackupDestinationStore returns the store for accessing the backups of a cluster in the given backup
+// destination, without creating a BackupDownloadCredentialsSecret.
+func GetEtcdBackupDestinationStore(ctx context.Context, client ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster,
+	destination *kubermaticv1.BackupDestination) (backupstore.Store, error) {
+	secretData, err := getBackupDestinationData(ctx, client, destination)
+	if err != nil {
+		return nil, err
+	}
+
+	return newEtcdBackupStore(ctx, client, cluster, secretData)
+}
+
+// getBackupDestinationData returns the credentials of the destination together with its type, bucket and endpoint,
+// in the format used by the BackupDownloadCredentialsSecret.
+func getBackupDestinationData(ctx context.Context, client ctrlruntimeclient.Client, destination *kubermaticv1.BackupDestination) (map[string]string, error) {
+	secretData := map[string]string{
+		backupstore.TypeEnvVar:     string(destination.GetType()),
+		EtcdRestoreS3BucketNameKey: destination.BucketName,
+		EtcdRestoreS3EndpointKey:   destination.Endpoint,
+	}
+
+	if destination.Credentials == nil {
+		if destination.GetType() == kubermaticv1.BackupDestinationTypeFilesystem {
+			return secretData, nil
+		}
+		return nil, fmt.Errorf("credentials not set for backup destination")
+	}
+
+	credsSecret := &corev1.Secret{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: destination.Credentials.Namespace, Name: destination.Credentials.Name}, credsSecret); err != nil {
+		return nil, fmt.Errorf("failed to get backup credentials secret %v/%v: %w", destination.Credentials.Namespace, destination.Credentials.Name, err)
+	}
+	for k, v := range credsSecret.Data {
+		// the destination's settings take precedence over whatever else is in the secret
+		if _, ok := secretData[k]; !ok {
+			secretData[k] = string(v)
+		}
+	}
+
+	return secretData, nil
+}
+
+// newEtcdBackupStore creates the store described by the data of a BackupDownloadCredentialsSecret.
+func newEtcdBackupStore(ctx context.Context, client ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster, secretData map[string]string) (backupstore.Store, error) {
+	storeType := backupstore.Type(secretData[backupstore.TypeEnvVar])
+
+	cfg := backupstore.Config{
+		Type:              storeType,
+		Endpoint:          secretData[EtcdRestoreS3EndpointKey],
+		Bucket:            secretData[EtcdRestoreS3BucketNameKey],
+		AzureAccountName:  secretData[backupstore.AzureAccountNameKey],
+		AzureAccountKey:   secretData[backupstore.AzureAccountKeyKey],
+		GCSServiceAccount: secretData[backupstore.GCSServiceAccountKey],
+		Path:              EtcdBackupFilesystemMountPath,
+	}
+
+	if storeType == backupstore.TypeS3 {
+		// reuse the S3 client setup, which takes care of the endpoint defaulting and the CA bundle
+		s3Client, bucketName, err := newEtcdBackupS3Client(ctx, client, cluster, secretData)
+		if err != nil {
+			return nil, err
+		}
+		return backupstore.NewS3Store(s3Client, bucketName), nil
+	}
+
+	return backupstore.New(ctx, cfg)
+}
+
+// BackupDestinationVolumeSource returns the volume backing a filesystem backup destination. As claims are
+// resolved in the namespace of the pod, the same volume source can be used in every namespace.
+func BackupDestinationVolumeSource(destination *kubermaticv1.BackupDestination) (*corev1.VolumeSource, error) {
+	fs := destination.Filesystem
+	if fs == nil {
+		return nil, errors.New("filesystem backup destination has no volume configured")
+	}
+
+	switch {
+	case fs.NFS != nil && fs.PersistentVolumeClaim != "":
+		return nil, errors.New("filesystem backup destination must not configure both nfs and persistentVolumeClaim")
+	case fs.NFS != nil:
+		return &corev1.VolumeSource{NFS: fs.NFS.DeepCopy()}, nil
+	case fs.PersistentVolumeClaim != "":
+		return &corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: fs.PersistentVolumeClaim},
+		}, nil
+	default:
+		return nil, errors.New("filesystem backup destination has no volume configured")
+	}
+}
+
+func newEtcdBackupS3Client(ctx context.Context, client ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster, secretData map[string]string) (*minio.Client, string, error) {
 	accessKeyID := secretData[EtcdBackupAndRestoreS3AccessKeyIDKey]
 	secretAccessKey := secretData[EtcdBackupAndRestoreS3SecretKeyAccessKeyKey]
 	bucketName := secretData[EtcdRestoreS3BucketNameKey]
@@ -1389,3 +1585,26 @@ func GetEtcdRestoreS3Client(ctx context.Context, restore *kubermaticv1.EtcdResto
 
 	return s3Client, bucketName, nil
 }
+
+// GetEtcdRestoreEncryptionKeys returns the keys required to decrypt the backup of a given EtcdRestore,
+// taken from its BackupDownloadCredentialsSecret. The key ring is empty if the backup destination
+// does not use encryption.
+func GetEtcdRestoreEncryptionKeys(ctx context.Context, restore *kubermaticv1.EtcdRestore, client ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster) (encryption.KeyRing, error) {
+	if restore.Spec.BackupDownloadCredentialsSecret == "" {
+		return nil, fmt.Errorf("BackupDownloadCredentialsSecret not set")
+	}
+
+	secret := &corev1.Secret{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: restore.Spec.BackupDownloadCredentialsSecret}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get BackupDownloadCredentialsSecret credentials secret %v: %w", restore.Spec.BackupDownloadCredentialsSecret, err)
+	}
+
+	keyData := map[string][]byte{}
+	for k, v := range secret.Data {
+		if strings.HasPrefix(k, EtcdRestoreEncryptionKeyPrefix) {
+			keyData[strings.TrimPrefix(k, EtcdRestoreEncryptionKeyPrefix)] = v
+		}
+	}
+
+	return encryption.KeyRingFromSecretData(keyData)
+}
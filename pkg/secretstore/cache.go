@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type cachedSecret struct {
+	secret  *Secret
+	expires time.Time
+}
+
+// cachingStore keeps secrets for a while, so that not every use of a secret reaches the store
+// and dynamic secret engines do not issue new credentials for every request.
+type cachingStore struct {
+	store Store
+	ttl   time.Duration
+	now   func() time.Time
+
+	lock    sync.Mutex
+	secrets map[string]cachedSecret
+}
+
+// NewCachingStore returns a Store that caches the secrets read from store for ttl. Leased secrets
+// are only cached for half of their lease, so that they are still valid for a while after
+// they have been handed out.
+func NewCachingStore(store Store, ttl time.Duration) Store {
+	return &cachingStore{
+		store:   store,
+		ttl:     ttl,
+		now:     time.Now,
+		secrets: map[string]cachedSecret{},
+	}
+}
+
+func (s *cachingStore) Read(ctx context.Context, path string) (*Secret, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.now()
+	if cached, ok := s.secrets[path]; ok && now.Before(cached.expires) {
+		return cached.secret, nil
+	}
+
+	secret, err := s.store.Read(ctx, path)
+	if err != nil {
+		delete(s.secrets, path)
+		return nil, err
+	}
+
+	ttl := s.ttl
+	if secret.LeaseDuration > 0 && secret.LeaseDuration/2 < ttl {
+		ttl = secret.LeaseDuration / 2
+	}
+	s.secrets[path] = cachedSecret{secret: secret, expires: now.Add(ttl)}
+
+	return secret, nil
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+const (
+	// VaultAddressEnvVar, VaultTokenEnvVar, VaultNamespaceEnvVar and VaultCACertEnvVar are the
+	// environment variables used by the Vault CLI, they are used if the flags are not given.
+	VaultAddressEnvVar   = "VAULT_ADDR"
+	VaultTokenEnvVar     = "VAULT_TOKEN"
+	VaultNamespaceEnvVar = "VAULT_NAMESPACE"
+	VaultCACertEnvVar    = "VAULT_CACERT"
+)
+
+// Options exports the secret store flags.
+type Options struct {
+	Type           string
+	VaultAddress   string
+	VaultToken     string
+	VaultNamespace string
+	VaultCACert    string
+	CacheTTL       time.Duration
+}
+
+func NewDefaultOptions() Options {
+	return Options{
+		CacheTTL: 5 * time.Minute,
+	}
+}
+
+func (o *Options) AddFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.Type, "secret-store", o.Type, "The kind of external secret store presets can reference credentials in, only vault is supported. If not set, presets cannot use external secrets")
+	fs.StringVar(&o.VaultAddress, "vault-address", o.VaultAddress, "The address of the Vault server, defaults to the VAULT_ADDR environment variable")
+	fs.StringVar(&o.VaultToken, "vault-token", o.VaultToken, "The token to authenticate against Vault, defaults to the VAULT_TOKEN environment variable")
+	fs.StringVar(&o.VaultNamespace, "vault-namespace", o.VaultNamespace, "The Vault Enterprise namespace, defaults to the VAULT_NAMESPACE environment variable")
+	fs.StringVar(&o.VaultCACert, "vault-ca-cert", o.VaultCACert, "Filename of the CA bundle to verify the Vault server with, defaults to the VAULT_CACERT environment variable")
+	fs.DurationVar(&o.CacheTTL, "secret-store-cache-ttl", o.CacheTTL, "How long secrets read from the external secret store are cached, leased secrets are cached for half of their lease at most")
+}
+
+// Enabled returns whether an external secret store has been configured.
+func (o *Options) Enabled() bool {
+	return o.Type != ""
+}
+
+// Config returns the store config, filling unset values from the environment.
+func (o *Options) Config() (Config, error) {
+	cfg := Config{
+		Type:      Type(o.Type),
+		Address:   valueOrEnv(o.VaultAddress, VaultAddressEnvVar),
+		Token:     valueOrEnv(o.VaultToken, VaultTokenEnvVar),
+		Namespace: valueOrEnv(o.VaultNamespace, VaultNamespaceEnvVar),
+	}
+
+	if caCert := valueOrEnv(o.VaultCACert, VaultCACertEnvVar); caCert != "" {
+		pem, err := ioutil.ReadFile(caCert)
+		if err != nil {
+			return cfg, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return cfg, errors.New("CA bundle does not contain any valid certificates")
+		}
+	}
+
+	return cfg, nil
+}
+
+// Store creates the store described by the options, or returns nil if no store is configured.
+func (o *Options) Store() (Store, error) {
+	if !o.Enabled() {
+		return nil, nil
+	}
+
+	cfg, err := o.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.CacheTTL > 0 {
+		store = NewCachingStore(store, o.CacheTTL)
+	}
+	return store, nil
+}
+
+func valueOrEnv(value, envVar string) string {
+	if value != "" {
+		return value
+	}
+	return os.Getenv(envVar)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package secretstore provides read access to secrets kept in an external secret store,
+// so that credentials do not have to be stored in Kubernetes objects.
+package secretstore
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Type is the kind of the external secret store.
+type Type string
+
+const (
+	TypeVault Type = "vault"
+)
+
+// ErrNotFound is returned if a secret does not exist.
+var ErrNotFound = errors.New("secret not found")
+
+// Secret is a secret read from a store.
+type Secret struct {
+	Data map[string]string
+	// LeaseDuration is the time the secret is valid for. It is zero if the store does not
+	// lease the secret, in which case it is valid until it is changed in the store.
+	LeaseDuration time.Duration
+}
+
+// Store is an external secret store.
+type Store interface {
+	// Read returns the secret at the given path, or ErrNotFound.
+	Read(ctx context.Context, path string) (*Secret, error)
+}
+
+// Config configures a Store. Only the fields relevant for the Type need to be set.
+type Config struct {
+	Type Type
+	// Address is the URL of the store, e.g. https://vault.example.com:8200.
+	Address string
+	// Token authenticates against the store.
+	Token string
+	// Namespace is the Vault Enterprise namespace, it is optional.
+	Namespace string
+	// RootCAs are used to verify the store's certificate, the system pool is used if nil.
+	RootCAs *x509.CertPool
+}
+
+// New returns the Store described by the config.
+func New(cfg Config) (Store, error) {
+	switch cfg.Type {
+	case TypeVault:
+		return newVaultStore(cfg)
+	default:
+		return nil, fmt.Errorf("unknown secret store type %q", cfg.Type)
+	}
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+const testToken = "s.test"
+
+// newFakeVault serves the given responses by path like the HTTP API of a Vault dev server.
+func newFakeVault(t *testing.T, responses map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != testToken {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+			return
+		}
+		_, _ = w.Write([]byte(response))
+	}))
+}
+
+func TestVaultRead(t *testing.T) {
+	server := newFakeVault(t, map[string]string{
+		"/v1/secret/data/presets/kv2": `{"lease_duration":0,"data":{"data":{"hetzner.token":"token","packet.projectId":1234},"metadata":{"version":2}}}`,
+		"/v1/kv/presets/kv1":          `{"lease_duration":2764800,"data":{"hetzner.token":"token"}}`,
+		"/v1/aws/creds/presets":       `{"lease_id":"aws/creds/presets/abc","lease_duration":900,"renewable":true,"data":{"aws.accessKeyId":"key","aws.secretAccessKey":"secret"}}`,
+		"/v1/secret/data/deleted":     `{"data":{"data":null,"metadata":{"deletion_time":"2021-01-01T00:00:00Z"}}}`,
+	})
+	defer server.Close()
+
+	testCases := []struct {
+		name           string
+		path           string
+		token          string
+		expectedSecret *Secret
+		expectedErr    error
+	}{
+		{
+			name:  "KV version 2 secrets are unwrapped",
+			path:  "secret/data/presets/kv2",
+			token: testToken,
+			expectedSecret: &Secret{
+				Data: map[string]string{"hetzner.token": "token", "packet.projectId": "1234"},
+			},
+		},
+		{
+			name:  "KV version 1 secrets are read as they are and are not leased",
+			path:  "/kv/presets/kv1",
+			token: testToken,
+			expectedSecret: &Secret{
+				Data: map[string]string{"hetzner.token": "token"},
+			},
+		},
+		{
+			name:  "dynamic secrets report their lease",
+			path:  "aws/creds/presets",
+			token: testToken,
+			expectedSecret: &Secret{
+				Data:          map[string]string{"aws.accessKeyId": "key", "aws.secretAccessKey": "secret"},
+				LeaseDuration: 15 * time.Minute,
+			},
+		},
+		{
+			name:        "deleted secrets are not found",
+			path:        "secret/data/deleted",
+			token:       testToken,
+			expectedErr: ErrNotFound,
+		},
+		{
+			name:        "missing secrets are not found",
+			path:        "secret/data/missing",
+			token:       testToken,
+			expectedErr: ErrNotFound,
+		},
+		{
+			name:  "invalid tokens are rejected",
+			path:  "secret/data/presets/kv2",
+			token: "invalid",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store, err := New(Config{Type: TypeVault, Address: server.URL, Token: tc.token})
+			if err != nil {
+				t.Fatalf("failed to create store: %v", err)
+			}
+
+			secret, err := store.Read(context.Background(), tc.path)
+			if tc.expectedSecret == nil {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+					t.Fatalf("expected error %v, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to read secret: %v", err)
+			}
+
+			if !equality.Semantic.DeepEqual(secret, tc.expectedSecret) {
+				t.Fatalf("expected secret %+v, got %+v", tc.expectedSecret, secret)
+			}
+		})
+	}
+}
+
+type countingStore struct {
+	reads  int
+	secret *Secret
+}
+
+func (s *countingStore) Read(_ context.Context, _ string) (*Secret, error) {
+	s.reads++
+	return s.secret, nil
+}
+
+func TestCachingStore(t *testing.T) {
+	testCases := []struct {
+		name          string
+		leaseDuration time.Duration
+		elapsed       time.Duration
+		expectedReads int
+	}{
+		{
+			name:          "secrets are cached",
+			elapsed:       4 * time.Minute,
+			expectedReads: 1,
+		},
+		{
+			name:          "secrets expire after the TTL",
+			elapsed:       5 * time.Minute,
+			expectedReads: 2,
+		},
+		{
+			name:          "leased secrets are cached for half of their lease",
+			leaseDuration: 2 * time.Minute,
+			elapsed:       time.Minute,
+			expectedReads: 2,
+		},
+		{
+			name:          "long leases do not extend the TTL",
+			leaseDuration: time.Hour,
+			elapsed:       5 * time.Minute,
+			expectedReads: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			backend := &countingStore{secret: &Secret{LeaseDuration: tc.leaseDuration}}
+			now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+			store := NewCachingStore(backend, 5*time.Minute).(*cachingStore)
+			store.now = func() time.Time { return now }
+
+			ctx := context.Background()
+			if _, err := store.Read(ctx, "path"); err != nil {
+				t.Fatalf("failed to read secret: %v", err)
+			}
+			now = now.Add(tc.elapsed)
+			if _, err := store.Read(ctx, "path"); err != nil {
+				t.Fatalf("failed to read secret: %v", err)
+			}
+
+			if backend.reads != tc.expectedReads {
+				t.Fatalf("expected %d reads, got %d", tc.expectedReads, backend.reads)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const vaultRequestTimeout = 10 * time.Second
+
+// vaultStore reads secrets through the HTTP API of HashiCorp Vault. Both KV version 1 and 2
+// as well as dynamic secret engines are supported. The leases of dynamic secrets are reported
+// but not renewed, the credentials have to be read again before their lease runs out.
+type vaultStore struct {
+	address   *url.URL
+	token     string
+	namespace string
+	client    *http.Client
+}
+
+type vaultResponse struct {
+	LeaseID       string                 `json:"lease_id"`
+	LeaseDuration int                    `json:"lease_duration"`
+	Data          map[string]interface{} `json:"data"`
+	Errors        []string               `json:"errors"`
+}
+
+func newVaultStore(cfg Config) (*vaultStore, error) {
+	if cfg.Address == "" || cfg.Token == "" {
+		return nil, errors.New("vault address and token must be set")
+	}
+
+	address, err := url.Parse(cfg.Address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vault address: %w", err)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.RootCAs != nil {
+		transport.TLSClientConfig = &tls.Config{RootCAs: cfg.RootCAs}
+	}
+
+	return &vaultStore{
+		address:   address,
+		token:     cfg.Token,
+		namespace: cfg.Namespace,
+		client:    &http.Client{Transport: transport, Timeout: vaultRequestTimeout},
+	}, nil
+}
+
+func (s *vaultStore) Read(ctx context.Context, path string) (*Secret, error) {
+	endpoint := *s.address
+	endpoint.Path = strings.TrimSuffix(endpoint.Path, "/") + "/v1/" + strings.TrimPrefix(path, "/")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", s.token)
+	if s.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", s.namespace)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret %q: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret %q: %w", path, err)
+	}
+
+	response := vaultResponse{}
+	// error responses are not necessarily JSON, so only the status decides whether parsing has to succeed
+	jsonErr := json.Unmarshal(body, &response)
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("failed to read secret %q: vault returned %s: %s", path, resp.Status, strings.Join(response.Errors, ", "))
+	case jsonErr != nil:
+		return nil, fmt.Errorf("failed to parse secret %q: %w", path, jsonErr)
+	}
+
+	data := response.Data
+	// KV version 2 engines wrap the secret together with its metadata,
+	// deleted secrets are returned without data
+	if _, ok := data["metadata"]; ok {
+		data, _ = data["data"].(map[string]interface{})
+	}
+	if data == nil {
+		return nil, ErrNotFound
+	}
+
+	secret := &Secret{
+		Data: make(map[string]string, len(data)),
+	}
+	// KV version 1 engines report their refresh interval as lease duration, but only
+	// dynamic secrets have a lease that ends
+	if response.LeaseID != "" {
+		secret.LeaseDuration = time.Duration(response.LeaseDuration) * time.Second
+	}
+	for key, value := range data {
+		str, err := stringValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for key %q of secret %q: %w", key, path, err)
+		}
+		secret.Data[key] = str
+	}
+
+	return secret, nil
+}
+
+// stringValue returns strings as they are and encodes all other values, e.g. numbers, as JSON.
+func stringValue(value interface{}) (string, error) {
+	if str, ok := value.(string); ok {
+		return str, nil
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
@@ -21,6 +21,7 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"os"
 	"path"
@@ -29,6 +30,9 @@ import (
 
 	"github.com/minio/minio-go"
 	"go.uber.org/zap"
+
+	"k8c.io/kubermatic/v2/pkg/etcd/encryption"
+	"k8c.io/kubermatic/v2/pkg/etcd/retention"
 )
 
 // prefix separator separates the prefix
@@ -43,6 +47,11 @@ type StoreUploader struct {
 	// client is a pointer to an initialized client
 	client *minio.Client
 	logger *zap.SugaredLogger
+
+	// encryptionKeyID and encryptionKey are used to encrypt files before
+	// they are uploaded, if set
+	encryptionKeyID string
+	encryptionKey   []byte
 }
 
 // New returns a new instance of the StoreUploader
@@ -66,6 +75,12 @@ func New(endpoint string, secure bool, accessKeyID, secretAccessKey string, logg
 	}, nil
 }
 
+// SetEncryptionKey makes the StoreUploader encrypt all files with the given key before uploading them
+func (u *StoreUploader) SetEncryptionKey(keyID string, key []byte) {
+	u.encryptionKeyID = keyID
+	u.encryptionKey = key
+}
+
 // Store uploads the given file to S3
 func (u *StoreUploader) Store(file, bucket, prefix string, createBucket bool) error {
 	if len(prefix) == 0 {
@@ -93,14 +108,43 @@ func (u *StoreUploader) Store(file, bucket, prefix string, createBucket bool) er
 	}
 
 	objectName := fmt.Sprintf("%s-%s-%s-%s", prefix, prefixSeparator, time.Now().Format("2006-01-02T15:04:05"), path.Base(file))
+
+	if u.encryptionKey != nil {
+		encryptedFile, err := u.encrypt(file)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt %s: %w", file, err)
+		}
+		defer os.Remove(encryptedFile)
+
+		logger.Debugw("Encrypted file", "key", u.encryptionKeyID)
+		file = encryptedFile
+	}
+
 	logger.Infow("Uploading file", "src", file, "dst", objectName)
 
 	_, err := u.client.FPutObject(bucket, objectName, file, minio.PutObjectOptions{})
 	return err
 }
 
-// DeleteOldBackups deletes revisions of all files of the given prefix which are older than max-revisions
-func (u *StoreUploader) DeleteOldBackups(bucket, prefix string, revisionsToKeep int) error {
+// encrypt writes an encrypted copy of the given file to a temporary
+// file and returns its name
+func (u *StoreUploader) encrypt(file string) (string, error) {
+	tmpFile, err := ioutil.TempFile("", "storeuploader")
+	if err != nil {
+		return "", err
+	}
+	tmpFile.Close()
+
+	if err := encryption.EncryptFile(file, tmpFile.Name(), u.encryptionKeyID, u.encryptionKey); err != nil {
+		os.Remove(tmpFile.Name())
+		return "", err
+	}
+
+	return tmpFile.Name(), nil
+}
+
+// DeleteOldBackups deletes revisions of all files of the given prefix which are not selected by the retention policy
+func (u *StoreUploader) DeleteOldBackups(bucket, prefix string, policy retention.Policy) error {
 	if len(prefix) == 0 {
 		return errors.New("prefix cannot be empty")
 	}
@@ -108,7 +152,8 @@ func (u *StoreUploader) DeleteOldBackups(bucket, prefix string, revisionsToKeep
 	doneCh := make(chan struct{})
 	defer close(doneCh)
 
-	logger := u.logger.With("bucket", bucket, "prefix", prefix, "keep", revisionsToKeep)
+	logger := u.logger.With("bucket", bucket, "prefix", prefix, "keep", policy.Last,
+		"hourly", policy.Hourly, "daily", policy.Daily, "weekly", policy.Weekly, "monthly", policy.Monthly)
 
 	logger.Debugw("Listing existing objects")
 
@@ -122,7 +167,7 @@ func (u *StoreUploader) DeleteOldBackups(bucket, prefix string, revisionsToKeep
 
 	logger.Debugw("Done listing bucket", "objects", len(existingObjects))
 
-	for _, object := range u.getObjectsToDelete(existingObjects, revisionsToKeep) {
+	for _, object := range u.getObjectsToDelete(existingObjects, policy) {
 		logger.Infow("Removing object", "object", object.Key)
 		if err := u.client.RemoveObject(bucket, object.Key); err != nil {
 			return err
@@ -165,23 +210,21 @@ func (u *StoreUploader) DeleteAll(bucket, prefix string) error {
 	return nil
 }
 
-func (u *StoreUploader) getObjectsToDelete(objects []minio.ObjectInfo, revisionsToKeep int) []minio.ObjectInfo {
-	if len(objects) <= revisionsToKeep {
-		return nil
-	}
-
+func (u *StoreUploader) getObjectsToDelete(objects []minio.ObjectInfo, policy retention.Policy) []minio.ObjectInfo {
 	sort.Slice(objects, func(i, j int) bool {
 		return objects[i].LastModified.Before(objects[j].LastModified)
 	})
 
-	numRevisionsToDelete := len(objects) - revisionsToKeep
+	times := make([]time.Time, len(objects))
+	for i, object := range objects {
+		times[i] = object.LastModified
+	}
 
 	var objectsToDelete []minio.ObjectInfo
-	for idx, object := range objects {
-		if idx >= numRevisionsToDelete {
-			return objectsToDelete
+	for i, keep := range policy.Keep(times) {
+		if !keep {
+			objectsToDelete = append(objectsToDelete, objects[i])
 		}
-		objectsToDelete = append(objectsToDelete, object)
 	}
 
 	return objectsToDelete
@@ -22,6 +22,8 @@ import (
 
 	"github.com/go-test/deep"
 	"github.com/minio/minio-go"
+
+	"k8c.io/kubermatic/v2/pkg/etcd/retention"
 )
 
 func TestGetObjectsToDelete(t *testing.T) {
@@ -29,11 +31,11 @@ func TestGetObjectsToDelete(t *testing.T) {
 		name             string
 		existingObjects  []minio.ObjectInfo
 		expectedToDelete []minio.ObjectInfo
-		revisions        int
+		policy           retention.Policy
 	}{
 		{
-			name:      "nothing gets deleted as revisions==existing-backups",
-			revisions: 1,
+			name:   "nothing gets deleted as revisions==existing-backups",
+			policy: retention.Policy{Last: 1},
 			existingObjects: []minio.ObjectInfo{
 				{
 					Key:          "foo",
@@ -43,8 +45,8 @@ func TestGetObjectsToDelete(t *testing.T) {
 			expectedToDelete: nil,
 		},
 		{
-			name:      "oldest should be deleted as revisions < existing-backups",
-			revisions: 1,
+			name:   "oldest should be deleted as revisions < existing-backups",
+			policy: retention.Policy{Last: 1},
 			existingObjects: []minio.ObjectInfo{
 				{
 					Key:          "foo",
@@ -62,6 +64,38 @@ func TestGetObjectsToDelete(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:   "only the newest backup of every day is kept",
+			policy: retention.Policy{Daily: 2},
+			existingObjects: []minio.ObjectInfo{
+				{
+					Key:          "day1-noon",
+					LastModified: time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC),
+				},
+				{
+					Key:          "day2-morning",
+					LastModified: time.Date(2021, 3, 2, 6, 0, 0, 0, time.UTC),
+				},
+				{
+					Key:          "day1-morning",
+					LastModified: time.Date(2021, 3, 1, 6, 0, 0, 0, time.UTC),
+				},
+				{
+					Key:          "day2-noon",
+					LastModified: time.Date(2021, 3, 2, 12, 0, 0, 0, time.UTC),
+				},
+			},
+			expectedToDelete: []minio.ObjectInfo{
+				{
+					Key:          "day1-morning",
+					LastModified: time.Date(2021, 3, 1, 6, 0, 0, 0, time.UTC),
+				},
+				{
+					Key:          "day2-morning",
+					LastModified: time.Date(2021, 3, 2, 6, 0, 0, 0, time.UTC),
+				},
+			},
+		},
 	}
 
 	uploader := StoreUploader{}
@@ -72,7 +106,7 @@ func TestGetObjectsToDelete(t *testing.T) {
 				t.Logf("existing object: %s - %s", object.LastModified.Format("2006-01-02T15:04:05"), object.Key)
 			}
 
-			gotToDelete := uploader.getObjectsToDelete(test.existingObjects, test.revisions)
+			gotToDelete := uploader.getObjectsToDelete(test.existingObjects, test.policy)
 			t.Log("objects to delete:")
 			for _, object := range gotToDelete {
 				t.Logf("existing object: %s - %s", object.LastModified.Format("2006-01-02T15:04:05"), object.Key)
@@ -28,6 +28,8 @@ type ClientOption func(*runtime.ClientOperation)
 
 // ClientService is the interface for Client methods
 type ClientService interface {
+	CloneCluster(params *CloneClusterParams, authInfo runtime.ClientAuthInfoWriter, opts ...ClientOption) (*CloneClusterCreated, error)
+
 	CreateEtcdRestore(params *CreateEtcdRestoreParams, authInfo runtime.ClientAuthInfoWriter, opts ...ClientOption) (*CreateEtcdRestoreCreated, error)
 
 	DeleteEtcdRestore(params *DeleteEtcdRestoreParams, authInfo runtime.ClientAuthInfoWriter, opts ...ClientOption) (*DeleteEtcdRestoreOK, error)
@@ -41,6 +43,46 @@ type ClientService interface {
 	SetTransport(transport runtime.ClientTransport)
 }
 
+/*
+  CloneCluster Creates a new cluster from an etcd backup of the given cluster. The new cluster gets a new ID and new certificates,
+
+  all of its machine deployments are scaled to zero once the backup has been restored.
+*/
+func (a *Client) CloneCluster(params *CloneClusterParams, authInfo runtime.ClientAuthInfoWriter, opts ...ClientOption) (*CloneClusterCreated, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewCloneClusterParams()
+	}
+	op := &runtime.ClientOperation{
+		ID:                 "cloneCluster",
+		Method:             "POST",
+		PathPattern:        "/api/v2/projects/{project_id}/clusters/{cluster_id}/clone",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"https"},
+		Params:             params,
+		Reader:             &CloneClusterReader{formats: a.formats},
+		AuthInfo:           authInfo,
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	}
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	result, err := a.transport.Submit(op)
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*CloneClusterCreated)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	unexpectedSuccess := result.(*CloneClusterDefault)
+	return nil, runtime.NewAPIError("unexpected success response: content available as default response in error", unexpectedSuccess, unexpectedSuccess.Code())
+}
+
 /*
   CreateEtcdRestore Creates a etcd backup restore for a given cluster
 */
@@ -22,6 +22,12 @@ type BackupCredentials struct {
 	// the backup credentials will be created. If set, it updates the credentials ref in the related Seed BackupDestination
 	Destination string `json:"destination,omitempty"`
 
+	// azureblob
+	Azureblob *AzureBlobBackupCredentials `json:"azureblob,omitempty"`
+
+	// gcs
+	Gcs *GCSBackupCredentials `json:"gcs,omitempty"`
+
 	// s3
 	S3 *S3BackupCredentials `json:"s3,omitempty"`
 }
@@ -30,6 +36,14 @@ type BackupCredentials struct {
 func (m *BackupCredentials) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateAzureblob(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateGcs(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateS3(formats); err != nil {
 		res = append(res, err)
 	}
@@ -40,6 +54,40 @@ func (m *BackupCredentials) Validate(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *BackupCredentials) validateAzureblob(formats strfmt.Registry) error {
+	if swag.IsZero(m.Azureblob) { // not required
+		return nil
+	}
+
+	if m.Azureblob != nil {
+		if err := m.Azureblob.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("azureblob")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *BackupCredentials) validateGcs(formats strfmt.Registry) error {
+	if swag.IsZero(m.Gcs) { // not required
+		return nil
+	}
+
+	if m.Gcs != nil {
+		if err := m.Gcs.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("gcs")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *BackupCredentials) validateS3(formats strfmt.Registry) error {
 	if swag.IsZero(m.S3) { // not required
 		return nil
@@ -61,6 +109,14 @@ func (m *BackupCredentials) validateS3(formats strfmt.Registry) error {
 func (m *BackupCredentials) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.contextValidateAzureblob(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.contextValidateGcs(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.contextValidateS3(ctx, formats); err != nil {
 		res = append(res, err)
 	}
@@ -71,6 +127,34 @@ func (m *BackupCredentials) ContextValidate(ctx context.Context, formats strfmt.
 	return nil
 }
 
+func (m *BackupCredentials) contextValidateAzureblob(ctx context.Context, formats strfmt.Registry) error {
+
+	if m.Azureblob != nil {
+		if err := m.Azureblob.ContextValidate(ctx, formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("azureblob")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *BackupCredentials) contextValidateGcs(ctx context.Context, formats strfmt.Registry) error {
+
+	if m.Gcs != nil {
+		if err := m.Gcs.ContextValidate(ctx, formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("gcs")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *BackupCredentials) contextValidateS3(ctx context.Context, formats strfmt.Registry) error {
 
 	if m.S3 != nil {
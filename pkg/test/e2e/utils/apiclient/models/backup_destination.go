@@ -18,14 +18,26 @@ import (
 // swagger:model BackupDestination
 type BackupDestination struct {
 
-	// BucketName is the bucket name to use for backup and restore.
+	// BucketName is the bucket name to use for backup and restore. For azureblob destinations, it is the
+	// name of the container, for filesystem destinations the directory in the volume backups are stored in.
 	BucketName string `json:"bucketName,omitempty"`
 
-	// Endpoint is the API endpoint to use for backup and restore.
+	// Endpoint is the API endpoint to use for backup and restore. For azureblob destinations, it is the
+	// storage endpoint suffix (e.g. core.windows.net) and defaults to the public Azure cloud. It is ignored
+	// for gcs and filesystem destinations.
 	Endpoint string `json:"endpoint,omitempty"`
 
 	// credentials
 	Credentials *SecretReference `json:"credentials,omitempty"`
+
+	// encryption
+	Encryption *BackupEncryption `json:"encryption,omitempty"`
+
+	// filesystem
+	Filesystem *FilesystemBackupDestination `json:"filesystem,omitempty"`
+
+	// type
+	Type BackupDestinationType `json:"type,omitempty"`
 }
 
 // Validate validates this backup destination
@@ -36,6 +48,18 @@ func (m *BackupDestination) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateEncryption(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateFilesystem(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateType(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -59,6 +83,55 @@ func (m *BackupDestination) validateCredentials(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *BackupDestination) validateEncryption(formats strfmt.Registry) error {
+	if swag.IsZero(m.Encryption) { // not required
+		return nil
+	}
+
+	if m.Encryption != nil {
+		if err := m.Encryption.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("encryption")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *BackupDestination) validateFilesystem(formats strfmt.Registry) error {
+	if swag.IsZero(m.Filesystem) { // not required
+		return nil
+	}
+
+	if m.Filesystem != nil {
+		if err := m.Filesystem.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("filesystem")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *BackupDestination) validateType(formats strfmt.Registry) error {
+	if swag.IsZero(m.Type) { // not required
+		return nil
+	}
+
+	if err := m.Type.Validate(formats); err != nil {
+		if ve, ok := err.(*errors.Validation); ok {
+			return ve.ValidateName("type")
+		}
+		return err
+	}
+
+	return nil
+}
+
 // ContextValidate validate this backup destination based on the context it is used
 func (m *BackupDestination) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
 	var res []error
@@ -67,6 +140,18 @@ func (m *BackupDestination) ContextValidate(ctx context.Context, formats strfmt.
 		res = append(res, err)
 	}
 
+	if err := m.contextValidateEncryption(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.contextValidateFilesystem(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.contextValidateType(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -87,6 +172,46 @@ func (m *BackupDestination) contextValidateCredentials(ctx context.Context, form
 	return nil
 }
 
+func (m *BackupDestination) contextValidateEncryption(ctx context.Context, formats strfmt.Registry) error {
+
+	if m.Encryption != nil {
+		if err := m.Encryption.ContextValidate(ctx, formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("encryption")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *BackupDestination) contextValidateFilesystem(ctx context.Context, formats strfmt.Registry) error {
+
+	if m.Filesystem != nil {
+		if err := m.Filesystem.ContextValidate(ctx, formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("filesystem")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *BackupDestination) contextValidateType(ctx context.Context, formats strfmt.Registry) error {
+
+	if err := m.Type.ContextValidate(ctx, formats); err != nil {
+		if ve, ok := err.(*errors.Validation); ok {
+			return ve.ValidateName("type")
+		}
+		return err
+	}
+
+	return nil
+}
+
 // MarshalBinary interface implementation
 func (m *BackupDestination) MarshalBinary() ([]byte, error) {
 	if m == nil {
@@ -33,6 +33,21 @@ type BackupStatus struct {
 	// job name
 	JobName string `json:"jobName,omitempty"`
 
+	// snapshot hash
+	SnapshotHash string `json:"snapshotHash,omitempty"`
+
+	// snapshot key count
+	SnapshotKeyCount int64 `json:"snapshotKeyCount,omitempty"`
+
+	// snapshot revision
+	SnapshotRevision int64 `json:"snapshotRevision,omitempty"`
+
+	// verification job name
+	VerificationJobName string `json:"verificationJobName,omitempty"`
+
+	// verification message
+	VerificationMessage string `json:"verificationMessage,omitempty"`
+
 	// backup finished time
 	// Format: date-time
 	BackupFinishedTime Time `json:"backupFinishedTime,omitempty"`
@@ -58,6 +73,13 @@ type BackupStatus struct {
 	// scheduled time
 	// Format: date-time
 	ScheduledTime Time `json:"scheduledTime,omitempty"`
+
+	// verification finished time
+	// Format: date-time
+	VerificationFinishedTime Time `json:"verificationFinishedTime,omitempty"`
+
+	// verification phase
+	VerificationPhase BackupStatusPhase `json:"verificationPhase,omitempty"`
 }
 
 // Validate validates this backup status
@@ -92,6 +114,14 @@ func (m *BackupStatus) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateVerificationFinishedTime(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateVerificationPhase(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -203,6 +233,36 @@ func (m *BackupStatus) validateScheduledTime(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *BackupStatus) validateVerificationFinishedTime(formats strfmt.Registry) error {
+	if swag.IsZero(m.VerificationFinishedTime) { // not required
+		return nil
+	}
+
+	if err := m.VerificationFinishedTime.Validate(formats); err != nil {
+		if ve, ok := err.(*errors.Validation); ok {
+			return ve.ValidateName("verificationFinishedTime")
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (m *BackupStatus) validateVerificationPhase(formats strfmt.Registry) error {
+	if swag.IsZero(m.VerificationPhase) { // not required
+		return nil
+	}
+
+	if err := m.VerificationPhase.Validate(formats); err != nil {
+		if ve, ok := err.(*errors.Validation); ok {
+			return ve.ValidateName("verificationPhase")
+		}
+		return err
+	}
+
+	return nil
+}
+
 // ContextValidate validate this backup status based on the context it is used
 func (m *BackupStatus) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
 	var res []error
@@ -235,6 +295,14 @@ func (m *BackupStatus) ContextValidate(ctx context.Context, formats strfmt.Regis
 		res = append(res, err)
 	}
 
+	if err := m.contextValidateVerificationFinishedTime(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.contextValidateVerificationPhase(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -325,6 +393,30 @@ func (m *BackupStatus) contextValidateScheduledTime(ctx context.Context, formats
 	return nil
 }
 
+func (m *BackupStatus) contextValidateVerificationFinishedTime(ctx context.Context, formats strfmt.Registry) error {
+
+	if err := m.VerificationFinishedTime.ContextValidate(ctx, formats); err != nil {
+		if ve, ok := err.(*errors.Validation); ok {
+			return ve.ValidateName("verificationFinishedTime")
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (m *BackupStatus) contextValidateVerificationPhase(ctx context.Context, formats strfmt.Registry) error {
+
+	if err := m.VerificationPhase.ContextValidate(ctx, formats); err != nil {
+		if ve, ok := err.(*errors.Validation); ok {
+			return ve.ValidateName("verificationPhase")
+		}
+		return err
+	}
+
+	return nil
+}
+
 // MarshalBinary interface implementation
 func (m *BackupStatus) MarshalBinary() ([]byte, error) {
 	if m == nil {
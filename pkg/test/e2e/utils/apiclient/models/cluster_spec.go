@@ -54,6 +54,9 @@ type ClusterSpec struct {
 	// If active the PodSecurityPolicy admission plugin is configured at the apiserver
 	UsePodSecurityPolicyAdmissionPlugin bool `json:"usePodSecurityPolicyAdmissionPlugin,omitempty"`
 
+	// api server allowed IP ranges
+	APIServerAllowedIPRanges *NetworkRanges `json:"apiServerAllowedIPRanges,omitempty"`
+
 	// audit logging
 	AuditLogging *AuditLoggingSettings `json:"auditLogging,omitempty"`
 
@@ -96,6 +99,10 @@ func (m *ClusterSpec) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateAPIServerAllowedIPRanges(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateAuditLogging(formats); err != nil {
 		res = append(res, err)
 	}
@@ -170,6 +177,23 @@ func (m *ClusterSpec) validateMachineNetworks(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *ClusterSpec) validateAPIServerAllowedIPRanges(formats strfmt.Registry) error {
+	if swag.IsZero(m.APIServerAllowedIPRanges) { // not required
+		return nil
+	}
+
+	if m.APIServerAllowedIPRanges != nil {
+		if err := m.APIServerAllowedIPRanges.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("apiServerAllowedIPRanges")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *ClusterSpec) validateAuditLogging(formats strfmt.Registry) error {
 	if swag.IsZero(m.AuditLogging) { // not required
 		return nil
@@ -363,6 +387,10 @@ func (m *ClusterSpec) ContextValidate(ctx context.Context, formats strfmt.Regist
 		res = append(res, err)
 	}
 
+	if err := m.contextValidateAPIServerAllowedIPRanges(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.contextValidateAuditLogging(ctx, formats); err != nil {
 		res = append(res, err)
 	}
@@ -431,6 +459,20 @@ func (m *ClusterSpec) contextValidateMachineNetworks(ctx context.Context, format
 	return nil
 }
 
+func (m *ClusterSpec) contextValidateAPIServerAllowedIPRanges(ctx context.Context, formats strfmt.Registry) error {
+
+	if m.APIServerAllowedIPRanges != nil {
+		if err := m.APIServerAllowedIPRanges.ContextValidate(ctx, formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("apiServerAllowedIPRanges")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *ClusterSpec) contextValidateAuditLogging(ctx context.Context, formats strfmt.Registry) error {
 
 	if m.AuditLogging != nil {
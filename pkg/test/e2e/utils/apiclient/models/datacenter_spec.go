@@ -22,8 +22,7 @@ type DatacenterSpec struct {
 	// It is used for informational purposes.
 	Country string `json:"country,omitempty"`
 
-	// EnforceAuditLogging enforces audit logging on every cluster within the DC,
-	// ignoring cluster-specific settings.
+	// EnforceAuditLogging enforces audit logging on every cluster within the DC.
 	EnforceAuditLogging bool `json:"enforceAuditLogging,omitempty"`
 
 	// EnforcePodSecurityPolicy enforces pod security policy plugin on every clusters within the DC,
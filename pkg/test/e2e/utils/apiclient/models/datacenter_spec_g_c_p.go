@@ -17,6 +17,10 @@ import (
 // swagger:model DatacenterSpecGCP
 type DatacenterSpecGCP struct {
 
+	// Optional: DedicatedNetwork creates a VPC network with a single subnetwork in the region
+	// for every cluster that does not specify a network, instead of using the default network.
+	DedicatedNetwork bool `json:"dedicatedNetwork,omitempty"`
+
 	// Region to use, for example "europe-west3", for a full list of regions see
 	// https://cloud.google.com/compute/docs/regions-zones/
 	Region string `json:"region,omitempty"`
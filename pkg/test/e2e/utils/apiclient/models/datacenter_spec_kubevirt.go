@@ -25,6 +25,9 @@ type DatacenterSpecKubevirt struct {
 
 	// dns config
 	DNSConfig *PodDNSConfig `json:"dns_config,omitempty"`
+
+	// resource quota
+	ResourceQuota ResourceList `json:"resource_quota,omitempty"`
 }
 
 // Validate validates this datacenter spec kubevirt
@@ -35,6 +38,10 @@ func (m *DatacenterSpecKubevirt) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateResourceQuota(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -58,6 +65,23 @@ func (m *DatacenterSpecKubevirt) validateDNSConfig(formats strfmt.Registry) erro
 	return nil
 }
 
+func (m *DatacenterSpecKubevirt) validateResourceQuota(formats strfmt.Registry) error {
+	if swag.IsZero(m.ResourceQuota) { // not required
+		return nil
+	}
+
+	if m.ResourceQuota != nil {
+		if err := m.ResourceQuota.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("resource_quota")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 // ContextValidate validate this datacenter spec kubevirt based on the context it is used
 func (m *DatacenterSpecKubevirt) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
 	var res []error
@@ -66,6 +90,10 @@ func (m *DatacenterSpecKubevirt) ContextValidate(ctx context.Context, formats st
 		res = append(res, err)
 	}
 
+	if err := m.contextValidateResourceQuota(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -86,6 +114,18 @@ func (m *DatacenterSpecKubevirt) contextValidateDNSConfig(ctx context.Context, f
 	return nil
 }
 
+func (m *DatacenterSpecKubevirt) contextValidateResourceQuota(ctx context.Context, formats strfmt.Registry) error {
+
+	if err := m.ResourceQuota.ContextValidate(ctx, formats); err != nil {
+		if ve, ok := err.(*errors.Validation); ok {
+			return ve.ValidateName("resource_quota")
+		}
+		return err
+	}
+
+	return nil
+}
+
 // MarshalBinary interface implementation
 func (m *DatacenterSpecKubevirt) MarshalBinary() ([]byte, error) {
 	if m == nil {
@@ -18,6 +18,14 @@ import (
 // swagger:model DigitaloceanCloudSpec
 type DigitaloceanCloudSpec struct {
 
+	// FirewallID is the ID of the cloud firewall applied to the droplets of the cluster.
+	// If set by the user, the firewall is used as-is and not managed by Kubermatic.
+	FirewallID string `json:"firewallID,omitempty"`
+
+	// NodePortsAllowedIPRange is the range of addresses allowed to access the cluster nodeports.
+	// If not specified, only the seed's nodeport-proxy and konnectivity address of the cluster is allowed.
+	NodePortsAllowedIPRange string `json:"nodePortsAllowedIPRange,omitempty"`
+
 	// token
 	Token string `json:"token,omitempty"`
 
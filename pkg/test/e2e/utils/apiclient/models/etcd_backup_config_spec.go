@@ -8,6 +8,7 @@ package models
 import (
 	"context"
 
+	"github.com/go-openapi/errors"
 	"github.com/go-openapi/strfmt"
 	"github.com/go-openapi/swag"
 )
@@ -32,15 +33,151 @@ type EtcdBackupConfigSpec struct {
 	// the backup. If not set, the backup is performed exactly
 	// once, immediately.
 	Schedule string `json:"schedule,omitempty"`
+
+	// continuous
+	Continuous *EtcdContinuousBackup `json:"continuous,omitempty"`
+
+	// retention
+	Retention *EtcdBackupRetention `json:"retention,omitempty"`
+
+	// verification
+	Verification *EtcdBackupVerification `json:"verification,omitempty"`
 }
 
 // Validate validates this etcd backup config spec
 func (m *EtcdBackupConfigSpec) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateContinuous(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateRetention(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateVerification(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *EtcdBackupConfigSpec) validateContinuous(formats strfmt.Registry) error {
+	if swag.IsZero(m.Continuous) { // not required
+		return nil
+	}
+
+	if m.Continuous != nil {
+		if err := m.Continuous.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("continuous")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *EtcdBackupConfigSpec) validateRetention(formats strfmt.Registry) error {
+	if swag.IsZero(m.Retention) { // not required
+		return nil
+	}
+
+	if m.Retention != nil {
+		if err := m.Retention.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("retention")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *EtcdBackupConfigSpec) validateVerification(formats strfmt.Registry) error {
+	if swag.IsZero(m.Verification) { // not required
+		return nil
+	}
+
+	if m.Verification != nil {
+		if err := m.Verification.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("verification")
+			}
+			return err
+		}
+	}
+
 	return nil
 }
 
-// ContextValidate validates this etcd backup config spec based on context it is used
+// ContextValidate validate this etcd backup config spec based on the context it is used
 func (m *EtcdBackupConfigSpec) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.contextValidateContinuous(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.contextValidateRetention(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.contextValidateVerification(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *EtcdBackupConfigSpec) contextValidateContinuous(ctx context.Context, formats strfmt.Registry) error {
+
+	if m.Continuous != nil {
+		if err := m.Continuous.ContextValidate(ctx, formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("continuous")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *EtcdBackupConfigSpec) contextValidateRetention(ctx context.Context, formats strfmt.Registry) error {
+
+	if m.Retention != nil {
+		if err := m.Retention.ContextValidate(ctx, formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("retention")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *EtcdBackupConfigSpec) contextValidateVerification(ctx context.Context, formats strfmt.Registry) error {
+
+	if m.Verification != nil {
+		if err := m.Verification.ContextValidate(ctx, formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("verification")
+			}
+			return err
+		}
+	}
+
 	return nil
 }
 
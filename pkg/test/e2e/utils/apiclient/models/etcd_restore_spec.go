@@ -8,6 +8,7 @@ package models
 import (
 	"context"
 
+	"github.com/go-openapi/errors"
 	"github.com/go-openapi/strfmt"
 	"github.com/go-openapi/swag"
 )
@@ -26,15 +27,68 @@ type EtcdRestoreSpec struct {
 
 	// ClusterID is the id of the cluster which will be restored from the backup
 	ClusterID string `json:"clusterId,omitempty"`
+
+	// RestoreToRevision is the etcd revision to restore to. Revisions shipped by a continuous backup after
+	// the backup was taken are replayed up to this revision. Mutually exclusive with RestoreToTime.
+	RestoreToRevision int64 `json:"restoreToRevision,omitempty"`
+
+	// restore to time
+	// Format: date-time
+	RestoreToTime Time `json:"restoreToTime,omitempty"`
 }
 
 // Validate validates this etcd restore spec
 func (m *EtcdRestoreSpec) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateRestoreToTime(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
 	return nil
 }
 
-// ContextValidate validates this etcd restore spec based on context it is used
+func (m *EtcdRestoreSpec) validateRestoreToTime(formats strfmt.Registry) error {
+	if swag.IsZero(m.RestoreToTime) { // not required
+		return nil
+	}
+
+	if err := m.RestoreToTime.Validate(formats); err != nil {
+		if ve, ok := err.(*errors.Validation); ok {
+			return ve.ValidateName("restoreToTime")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// ContextValidate validate this etcd restore spec based on the context it is used
 func (m *EtcdRestoreSpec) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.contextValidateRestoreToTime(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *EtcdRestoreSpec) contextValidateRestoreToTime(ctx context.Context, formats strfmt.Registry) error {
+
+	if err := m.RestoreToTime.ContextValidate(ctx, formats); err != nil {
+		if ve, ok := err.(*errors.Validation); ok {
+			return ve.ValidateName("restoreToTime")
+		}
+		return err
+	}
+
 	return nil
 }
 
@@ -18,10 +18,15 @@ import (
 // swagger:model HetznerCloudSpec
 type HetznerCloudSpec struct {
 
-	// Network is the pre-existing Hetzner network in which the machines are running.
+	// Firewall is the Hetzner Cloud firewall applied to the machines. If this is empty,
+	// a firewall owned by the cluster is created.
+	Firewall string `json:"firewall,omitempty"`
+
+	// Network is the Hetzner network in which the machines are running.
 	// While machines can be in multiple networks, a single one must be chosen for the
 	// HCloud CCM to work.
-	// If this is empty, the network configured on the datacenter will be used.
+	// If this is empty, the network configured on the datacenter will be used. If neither
+	// is set, a network owned by the cluster is created.
 	Network string `json:"network,omitempty"`
 
 	// Token is used to authenticate with the Hetzner cloud API.
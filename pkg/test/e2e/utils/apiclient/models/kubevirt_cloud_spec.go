@@ -21,6 +21,10 @@ type KubevirtCloudSpec struct {
 	// kubeconfig
 	Kubeconfig string `json:"kubeconfig,omitempty"`
 
+	// Namespace is the namespace created for the cluster in the KubeVirt infra cluster, which holds
+	// its virtual machines. Machine-controller and the CCM are restricted to this namespace.
+	Namespace string `json:"namespace,omitempty"`
+
 	// credentials reference
 	CredentialsReference *GlobalSecretKeySelector `json:"credentialsReference,omitempty"`
 }
@@ -22,9 +22,13 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/coreos/locksmith/pkg/timeutil"
+	providerconfig "github.com/kubermatic/machine-controller/pkg/providerconfig/types"
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
@@ -32,12 +36,16 @@ import (
 	"k8c.io/kubermatic/v2/pkg/provider/cloud"
 	kubernetesprovider "k8c.io/kubermatic/v2/pkg/provider/kubernetes"
 	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/apiserver"
 
 	"k8s.io/apimachinery/pkg/api/equality"
 	utilerror "k8s.io/apimachinery/pkg/util/errors"
 	kubenetutil "k8s.io/apimachinery/pkg/util/net"
 	"k8s.io/apimachinery/pkg/util/sets"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	"sigs.k8s.io/yaml"
 )
 
 var (
@@ -638,3 +646,310 @@ func ValidateNodePortRange(nodePortRange string, fldPath *field.Path, required b
 
 	return allErrs
 }
+
+// ValidateAPIServerAllowedIPRanges validates the source ranges the apiserver endpoint is
+// restricted to. The nodeport-proxy can only enforce them if the seed preserves the source IPs.
+func ValidateAPIServerAllowedIPRanges(spec *kubermaticv1.ClusterSpec, seed *kubermaticv1.Seed, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	ranges := spec.APIServerAllowedIPRanges
+	if ranges == nil || len(ranges.CIDRBlocks) == 0 {
+		return allErrs
+	}
+
+	if seed != nil && spec.ExposeStrategy != kubermaticv1.ExposeStrategyLoadBalancer && !seed.Spec.NodeportProxy.PreserveSourceIPs {
+		allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("seed %q does not preserve the source IPs of the nodeport-proxy, which is required to restrict the access with the %s expose strategy", seed.Name, spec.ExposeStrategy)))
+	}
+
+	for i, cidr := range ranges.CIDRBlocks {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("cidrBlocks").Index(i), cidr, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateEncryptionConfiguration validates the encryption at rest settings of the cluster. The
+// previous settings are only given for updates.
+func ValidateEncryptionConfiguration(spec, oldSpec *kubermaticv1.ClusterSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	config := spec.EncryptionConfiguration
+	if config == nil {
+		return allErrs
+	}
+
+	supportedProviders := sets.NewString("", string(kubermaticv1.EncryptionProviderAESCBC), string(kubermaticv1.EncryptionProviderSecretbox))
+	if !supportedProviders.Has(string(config.Provider)) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("provider"), config.Provider, supportedProviders.List()))
+	}
+
+	if config.KeyGeneration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("keyGeneration"), config.KeyGeneration, "key generation cannot be negative"))
+	}
+	if oldSpec != nil && oldSpec.EncryptionConfiguration != nil && config.KeyGeneration < oldSpec.EncryptionConfiguration.KeyGeneration {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("keyGeneration"), config.KeyGeneration, "key generation cannot be decreased"))
+	}
+
+	seen := sets.NewString()
+	for i, resource := range config.Resources {
+		switch {
+		case resource == "" || strings.Contains(resource, "*"):
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("resources").Index(i), resource, "resource must be a resource name like secrets or deployments.apps"))
+		case seen.Has(resource):
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("resources").Index(i), resource))
+		}
+		seen.Insert(resource)
+	}
+
+	if kms := config.KMS; kms != nil {
+		kmsPath := fldPath.Child("kms")
+		if kms.Name == "" {
+			allErrs = append(allErrs, field.Required(kmsPath.Child("name"), "KMS plugin name is required"))
+		} else if strings.Contains(kms.Name, ":") {
+			allErrs = append(allErrs, field.Invalid(kmsPath.Child("name"), kms.Name, "KMS plugin name must not contain ':'"))
+		}
+		socketPrefix := "unix://" + apiserver.KMSPluginSocketDir + "/"
+		if !strings.HasPrefix(kms.Endpoint, socketPrefix) || len(kms.Endpoint) == len(socketPrefix) {
+			allErrs = append(allErrs, field.Invalid(kmsPath.Child("endpoint"), kms.Endpoint, fmt.Sprintf("KMS plugin endpoint must be a unix socket in %s", apiserver.KMSPluginSocketDir)))
+		}
+		if kms.Timeout != nil && kms.Timeout.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(kmsPath.Child("timeout"), kms.Timeout.Duration.String(), "KMS plugin timeout must be positive"))
+		}
+		if spec.Version.Semver() != nil && spec.Version.Semver().LessThan(semver.MustParse("1.25.0")) {
+			allErrs = append(allErrs, field.Forbidden(kmsPath, "KMS v2 plugins require Kubernetes 1.25 or newer"))
+		}
+	}
+
+	return allErrs
+}
+
+const (
+	minCertificateRenewBefore = 30 * 24 * time.Hour
+	maxCertificateRenewBefore = 180 * 24 * time.Hour
+)
+
+// ValidateCertificateRotation validates the certificate rotation settings of the cluster.
+func ValidateCertificateRotation(spec *kubermaticv1.ClusterSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	settings := spec.CertificateRotation
+	if settings == nil {
+		return allErrs
+	}
+
+	if settings.RenewBefore != nil {
+		if renewBefore := settings.RenewBefore.Duration; renewBefore < minCertificateRenewBefore || renewBefore > maxCertificateRenewBefore {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("renewBefore"), settings.RenewBefore.Duration.String(),
+				fmt.Sprintf("must be between %v and %v", minCertificateRenewBefore, maxCertificateRenewBefore)))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateAuditLogging validates the audit logging settings of the cluster. Without a seed, the audit
+// policies of the seed and the enforcement of audit logging by the datacenter are not validated.
+func ValidateAuditLogging(spec *kubermaticv1.ClusterSpec, seed *kubermaticv1.Seed, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	settings := spec.AuditLogging
+	if seed != nil {
+		if dc, ok := seed.Spec.Datacenters[spec.Cloud.DatacenterName]; ok && dc.Spec.EnforceAuditLogging && (settings == nil || !settings.Enabled) {
+			allErrs = append(allErrs, field.Required(fldPath.Child("enabled"), fmt.Sprintf("audit logging is enforced by datacenter %q", spec.Cloud.DatacenterName)))
+		}
+	}
+
+	if settings == nil {
+		return allErrs
+	}
+
+	if settings.Policy != "" {
+		allErrs = append(allErrs, ValidateAuditPolicy(settings.Policy, fldPath.Child("policy"))...)
+	}
+	if settings.PolicyName != "" && seed != nil {
+		if _, ok := seed.Spec.AuditPolicies[settings.PolicyName]; !ok {
+			allErrs = append(allErrs, field.NotFound(fldPath.Child("policyName"), settings.PolicyName))
+		}
+	}
+
+	allErrs = append(allErrs, ValidateAuditSinks(settings.Sinks, fldPath.Child("sinks"))...)
+
+	return allErrs
+}
+
+var (
+	auditLevels = sets.NewString(string(auditv1.LevelNone), string(auditv1.LevelMetadata), string(auditv1.LevelRequest), string(auditv1.LevelRequestResponse))
+	auditStages = sets.NewString(string(auditv1.StageRequestReceived), string(auditv1.StageResponseStarted), string(auditv1.StageResponseComplete), string(auditv1.StagePanic))
+)
+
+// ValidateAuditPolicy validates an audit policy given as YAML.
+func ValidateAuditPolicy(policyYAML string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	policy := auditv1.Policy{}
+	if err := yaml.UnmarshalStrict([]byte(policyYAML), &policy); err != nil {
+		return append(allErrs, field.Invalid(fldPath, policyYAML, fmt.Sprintf("policy must be an audit policy: %v", err)))
+	}
+	if policy.APIVersion != auditv1.SchemeGroupVersion.String() || policy.Kind != "Policy" {
+		return append(allErrs, field.Invalid(fldPath, policyYAML, fmt.Sprintf("policy must be a Policy of %s", auditv1.SchemeGroupVersion)))
+	}
+	if len(policy.Rules) == 0 {
+		return append(allErrs, field.Invalid(fldPath, policyYAML, "policy must have at least one rule"))
+	}
+
+	allErrs = append(allErrs, validateAuditStages(policy.OmitStages, fldPath.Child("omitStages"))...)
+	for i, rule := range policy.Rules {
+		rulePath := fldPath.Child("rules").Index(i)
+		if !auditLevels.Has(string(rule.Level)) {
+			allErrs = append(allErrs, field.NotSupported(rulePath.Child("level"), rule.Level, auditLevels.List()))
+		}
+		allErrs = append(allErrs, validateAuditStages(rule.OmitStages, rulePath.Child("omitStages"))...)
+		if len(rule.Resources) > 0 && len(rule.NonResourceURLs) > 0 {
+			allErrs = append(allErrs, field.Invalid(rulePath.Child("nonResourceURLs"), rule.NonResourceURLs, "rules cannot apply to both resources and non-resource URLs"))
+		}
+		if len(rule.NonResourceURLs) > 0 && len(rule.Namespaces) > 0 {
+			allErrs = append(allErrs, field.Invalid(rulePath.Child("namespaces"), rule.Namespaces, "rules cannot apply to both non-resource URLs and namespaces"))
+		}
+		for j, nonResourceURL := range rule.NonResourceURLs {
+			if !strings.HasPrefix(nonResourceURL, "/") || strings.Contains(strings.TrimSuffix(nonResourceURL, "*"), "*") {
+				allErrs = append(allErrs, field.Invalid(rulePath.Child("nonResourceURLs").Index(j), nonResourceURL, "non-resource URLs must start with / and can only end with a wildcard"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateAuditStages(stages []auditv1.Stage, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, stage := range stages {
+		if !auditStages.Has(string(stage)) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Index(i), stage, auditStages.List()))
+		}
+	}
+	return allErrs
+}
+
+// ValidateAuditSinks validates the audit sinks of a cluster or datacenter. The apiserver only supports
+// a single webhook and the audit-logs sidecar reads the credentials of a single S3 sink.
+func ValidateAuditSinks(sinks []kubermaticv1.AuditSink, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := sets.NewString()
+	webhooks, s3Sinks := 0, 0
+	for i, sink := range sinks {
+		sinkPath := fldPath.Index(i)
+
+		if sink.Name == "" {
+			allErrs = append(allErrs, field.Required(sinkPath.Child("name"), "sink name is required"))
+		} else if errs := utilvalidation.IsDNS1123Label(sink.Name); len(errs) > 0 {
+			allErrs = append(allErrs, field.Invalid(sinkPath.Child("name"), sink.Name, strings.Join(errs, "; ")))
+		} else if names.Has(sink.Name) {
+			allErrs = append(allErrs, field.Duplicate(sinkPath.Child("name"), sink.Name))
+		}
+		names.Insert(sink.Name)
+
+		backends := 0
+		if webhook := sink.Webhook; webhook != nil {
+			backends++
+			webhooks++
+			if webhooks > 1 {
+				allErrs = append(allErrs, field.Forbidden(sinkPath.Child("webhook"), "only a single webhook sink is supported"))
+			}
+			if u, err := url.Parse(webhook.URL); err != nil || u.Scheme != "https" || u.Host == "" {
+				allErrs = append(allErrs, field.Invalid(sinkPath.Child("webhook", "url"), webhook.URL, "webhook URL must be a https URL"))
+			}
+		}
+		if http := sink.HTTP; http != nil {
+			backends++
+			allErrs = append(allErrs, validateAuditSinkURL(http.URL, sinkPath.Child("http", "url"))...)
+		}
+		if loki := sink.Loki; loki != nil {
+			backends++
+			allErrs = append(allErrs, validateAuditSinkURL(loki.URL, sinkPath.Child("loki", "url"))...)
+		}
+		if s3 := sink.S3; s3 != nil {
+			backends++
+			s3Sinks++
+			if s3Sinks > 1 {
+				allErrs = append(allErrs, field.Forbidden(sinkPath.Child("s3"), "only a single S3 sink is supported"))
+			}
+			if s3.Bucket == "" {
+				allErrs = append(allErrs, field.Required(sinkPath.Child("s3", "bucket"), "bucket is required"))
+			}
+			if s3.Region == "" {
+				allErrs = append(allErrs, field.Required(sinkPath.Child("s3", "region"), "region is required"))
+			}
+			if s3.Endpoint != "" {
+				allErrs = append(allErrs, validateAuditSinkURL(s3.Endpoint, sinkPath.Child("s3", "endpoint"))...)
+			}
+			if s3.CredentialsReference == nil && (s3.AccessKeyID == "" || s3.SecretAccessKey == "") {
+				allErrs = append(allErrs, field.Required(sinkPath.Child("s3", "credentialsReference"), "credentials are required"))
+			}
+		}
+		if syslog := sink.Syslog; syslog != nil {
+			backends++
+			if _, _, err := net.SplitHostPort(syslog.Address); err != nil {
+				allErrs = append(allErrs, field.Invalid(sinkPath.Child("syslog", "address"), syslog.Address, "address must be host:port"))
+			}
+			supportedProtocols := sets.NewString("", string(kubermaticv1.AuditSyslogProtocolUDP), string(kubermaticv1.AuditSyslogProtocolTCP), string(kubermaticv1.AuditSyslogProtocolTLS))
+			if !supportedProtocols.Has(string(syslog.Protocol)) {
+				allErrs = append(allErrs, field.NotSupported(sinkPath.Child("syslog", "protocol"), syslog.Protocol, supportedProtocols.List()))
+			}
+		}
+
+		if backends != 1 {
+			allErrs = append(allErrs, field.Invalid(sinkPath, sink.Name, "exactly one of webhook, http, loki, s3 or syslog must be configured"))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateAuditSinkCredentials validates that the audit sinks of a cluster only reference their
+// credentials in the audit sinks credentials secret of the cluster. The credentials are resolved by
+// the seed-controller-manager, so any other reference would give access to arbitrary secrets of the
+// seed, like the cloud credentials of other clusters.
+func ValidateAuditSinkCredentials(cluster *kubermaticv1.Cluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if cluster.Spec.AuditLogging == nil {
+		return allErrs
+	}
+
+	for i, sink := range cluster.Spec.AuditLogging.Sinks {
+		sinkPath := fldPath.Index(i)
+		refs := map[string]*providerconfig.GlobalSecretKeySelector{}
+		if sink.Webhook != nil {
+			refs["webhook"] = sink.Webhook.CredentialsReference
+		}
+		if sink.HTTP != nil {
+			refs["http"] = sink.HTTP.CredentialsReference
+		}
+		if sink.Loki != nil {
+			refs["loki"] = sink.Loki.CredentialsReference
+		}
+		if sink.S3 != nil {
+			refs["s3"] = sink.S3.CredentialsReference
+		}
+
+		for backend, ref := range refs {
+			if ref == nil {
+				continue
+			}
+			if ref.Namespace != resources.KubermaticNamespace || ref.Name != cluster.GetAuditSinksSecretName() || ref.Key != sink.Name {
+				allErrs = append(allErrs, field.Forbidden(sinkPath.Child(backend, "credentialsReference"), fmt.Sprintf("credentials can only be referenced from the key %q of secret %s/%s", sink.Name, resources.KubermaticNamespace, cluster.GetAuditSinksSecretName())))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateAuditSinkURL(rawURL string, fldPath *field.Path) field.ErrorList {
+	if u, err := url.Parse(rawURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return field.ErrorList{field.Invalid(fldPath, rawURL, "must be a http or https URL")}
+	}
+	return nil
+}
@@ -21,9 +21,14 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
+	providerconfig "github.com/kubermatic/machine-controller/pkg/providerconfig/types"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/semver"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/pointer"
 )
@@ -347,3 +352,496 @@ func TestValidateClusterNetworkingConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateEncryptionConfiguration(t *testing.T) {
+	tests := []struct {
+		name      string
+		version   string
+		config    *kubermaticv1.EncryptionConfiguration
+		oldConfig *kubermaticv1.EncryptionConfiguration
+		wantErr   bool
+	}{
+		{
+			name:    "no encryption configuration",
+			version: "1.22.5",
+			wantErr: false,
+		},
+		{
+			name:    "default encryption configuration",
+			version: "1.22.5",
+			config:  &kubermaticv1.EncryptionConfiguration{Enabled: true},
+			wantErr: false,
+		},
+		{
+			name:    "secretbox with additional resources",
+			version: "1.22.5",
+			config: &kubermaticv1.EncryptionConfiguration{
+				Enabled:   true,
+				Provider:  kubermaticv1.EncryptionProviderSecretbox,
+				Resources: []string{"secrets", "configmaps"},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "unsupported provider",
+			version: "1.22.5",
+			config: &kubermaticv1.EncryptionConfiguration{
+				Enabled:  true,
+				Provider: kubermaticv1.EncryptionProviderIdentity,
+			},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate resources",
+			version: "1.22.5",
+			config: &kubermaticv1.EncryptionConfiguration{
+				Enabled:   true,
+				Resources: []string{"secrets", "secrets"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "wildcard resources",
+			version: "1.22.5",
+			config: &kubermaticv1.EncryptionConfiguration{
+				Enabled:   true,
+				Resources: []string{"*.apps"},
+			},
+			wantErr: true,
+		},
+		{
+			name:      "decreased key generation",
+			version:   "1.22.5",
+			config:    &kubermaticv1.EncryptionConfiguration{Enabled: true, KeyGeneration: 1},
+			oldConfig: &kubermaticv1.EncryptionConfiguration{Enabled: true, KeyGeneration: 2},
+			wantErr:   true,
+		},
+		{
+			name:    "KMS plugin",
+			version: "1.25.0",
+			config: &kubermaticv1.EncryptionConfiguration{
+				Enabled: true,
+				KMS: &kubermaticv1.KMSEncryptionConfiguration{
+					Name:     "vault",
+					Endpoint: "unix:///var/run/kmsplugin/vault.sock",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "KMS plugin on unsupported version",
+			version: "1.22.5",
+			config: &kubermaticv1.EncryptionConfiguration{
+				Enabled: true,
+				KMS: &kubermaticv1.KMSEncryptionConfiguration{
+					Name:     "vault",
+					Endpoint: "unix:///var/run/kmsplugin/vault.sock",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "KMS plugin outside of the socket directory",
+			version: "1.25.0",
+			config: &kubermaticv1.EncryptionConfiguration{
+				Enabled: true,
+				KMS: &kubermaticv1.KMSEncryptionConfiguration{
+					Name:     "vault",
+					Endpoint: "unix:///tmp/vault.sock",
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := &kubermaticv1.ClusterSpec{
+				Version:                 *semver.NewSemverOrDie(test.version),
+				EncryptionConfiguration: test.config,
+			}
+			var oldSpec *kubermaticv1.ClusterSpec
+			if test.oldConfig != nil {
+				oldSpec = &kubermaticv1.ClusterSpec{
+					Version:                 *semver.NewSemverOrDie(test.version),
+					EncryptionConfiguration: test.oldConfig,
+				}
+			}
+			errs := ValidateEncryptionConfiguration(spec, oldSpec, field.NewPath("spec", "encryptionConfiguration"))
+
+			if test.wantErr == (len(errs) == 0) {
+				t.Errorf("Want error: %t, but got: \"%v\"", test.wantErr, errs)
+			}
+		})
+	}
+}
+
+func TestValidateCertificateRotation(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings *kubermaticv1.CertificateRotationSettings
+		wantErr  bool
+	}{
+		{
+			name:    "no certificate rotation settings",
+			wantErr: false,
+		},
+		{
+			name:     "renewal 60 days before expiry",
+			settings: &kubermaticv1.CertificateRotationSettings{RenewBefore: &metav1.Duration{Duration: 60 * 24 * time.Hour}},
+			wantErr:  false,
+		},
+		{
+			name:     "renewal too close to expiry",
+			settings: &kubermaticv1.CertificateRotationSettings{RenewBefore: &metav1.Duration{Duration: 24 * time.Hour}},
+			wantErr:  true,
+		},
+		{
+			name:     "renewal too early",
+			settings: &kubermaticv1.CertificateRotationSettings{RenewBefore: &metav1.Duration{Duration: 365 * 24 * time.Hour}},
+			wantErr:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := &kubermaticv1.ClusterSpec{CertificateRotation: test.settings}
+			errs := ValidateCertificateRotation(spec, field.NewPath("spec", "certificateRotation"))
+
+			if test.wantErr == (len(errs) == 0) {
+				t.Errorf("Want error: %t, but got: \"%v\"", test.wantErr, errs)
+			}
+		})
+	}
+}
+
+func TestValidateAPIServerAllowedIPRanges(t *testing.T) {
+	seed := &kubermaticv1.Seed{ObjectMeta: metav1.ObjectMeta{Name: "seed"}}
+	preservingSeed := seed.DeepCopy()
+	preservingSeed.Spec.NodeportProxy.PreserveSourceIPs = true
+
+	tests := []struct {
+		name           string
+		ranges         *kubermaticv1.NetworkRanges
+		exposeStrategy kubermaticv1.ExposeStrategy
+		seed           *kubermaticv1.Seed
+		wantErr        bool
+	}{
+		{
+			name:    "no allowed IP ranges",
+			seed:    seed,
+			wantErr: false,
+		},
+		{
+			name:    "empty allowed IP ranges",
+			ranges:  &kubermaticv1.NetworkRanges{},
+			seed:    seed,
+			wantErr: false,
+		},
+		{
+			name:    "valid IPv4 and IPv6 ranges",
+			ranges:  &kubermaticv1.NetworkRanges{CIDRBlocks: []string{"10.0.0.0/8", "192.168.1.1/32", "2001:db8::/32"}},
+			seed:    preservingSeed,
+			wantErr: false,
+		},
+		{
+			name:    "IP address without prefix length",
+			ranges:  &kubermaticv1.NetworkRanges{CIDRBlocks: []string{"10.0.0.0/8", "192.168.1.1"}},
+			seed:    preservingSeed,
+			wantErr: true,
+		},
+		{
+			name:    "invalid range",
+			ranges:  &kubermaticv1.NetworkRanges{CIDRBlocks: []string{"10.0.0.0/33"}},
+			seed:    preservingSeed,
+			wantErr: true,
+		},
+		{
+			name:           "ranges with the NodePort expose strategy in a seed without preserved source IPs",
+			ranges:         &kubermaticv1.NetworkRanges{CIDRBlocks: []string{"10.0.0.0/8"}},
+			exposeStrategy: kubermaticv1.ExposeStrategyNodePort,
+			seed:           seed,
+			wantErr:        true,
+		},
+		{
+			name:           "ranges with the Tunneling expose strategy in a seed without preserved source IPs",
+			ranges:         &kubermaticv1.NetworkRanges{CIDRBlocks: []string{"10.0.0.0/8"}},
+			exposeStrategy: kubermaticv1.ExposeStrategyTunneling,
+			seed:           seed,
+			wantErr:        true,
+		},
+		{
+			name:           "ranges with the LoadBalancer expose strategy in a seed without preserved source IPs",
+			ranges:         &kubermaticv1.NetworkRanges{CIDRBlocks: []string{"10.0.0.0/8"}},
+			exposeStrategy: kubermaticv1.ExposeStrategyLoadBalancer,
+			seed:           seed,
+			wantErr:        false,
+		},
+		{
+			name:           "ranges with the NodePort expose strategy in a seed with preserved source IPs",
+			ranges:         &kubermaticv1.NetworkRanges{CIDRBlocks: []string{"10.0.0.0/8"}},
+			exposeStrategy: kubermaticv1.ExposeStrategyNodePort,
+			seed:           preservingSeed,
+			wantErr:        false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := &kubermaticv1.ClusterSpec{
+				ExposeStrategy:           test.exposeStrategy,
+				APIServerAllowedIPRanges: test.ranges,
+			}
+			errs := ValidateAPIServerAllowedIPRanges(spec, test.seed, field.NewPath("spec", "apiServerAllowedIPRanges"))
+
+			if test.wantErr == (len(errs) == 0) {
+				t.Errorf("Want error: %t, but got: \"%v\"", test.wantErr, errs)
+			}
+		})
+	}
+}
+
+func TestValidateAuditLogging(t *testing.T) {
+	credentials := &providerconfig.GlobalSecretKeySelector{ObjectReference: corev1.ObjectReference{Name: "audit-sink", Namespace: "kubermatic"}}
+	seed := &kubermaticv1.Seed{
+		Spec: kubermaticv1.SeedSpec{
+			Datacenters: map[string]kubermaticv1.Datacenter{
+				"audited": {Spec: kubermaticv1.DatacenterSpec{EnforceAuditLogging: true}},
+			},
+			AuditPolicies: map[string]string{
+				"secrets": "apiVersion: audit.k8s.io/v1\nkind: Policy\nrules:\n- level: RequestResponse\n  resources:\n  - resources: [\"secrets\"]\n",
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		settings   *kubermaticv1.AuditLoggingSettings
+		datacenter string
+		wantErr    bool
+	}{
+		{
+			name:    "no audit logging settings",
+			wantErr: false,
+		},
+		{
+			name: "custom policy",
+			settings: &kubermaticv1.AuditLoggingSettings{
+				Enabled: true,
+				Policy:  "apiVersion: audit.k8s.io/v1\nkind: Policy\nrules:\n- level: Metadata\n",
+			},
+			wantErr: false,
+		},
+		{
+			name: "policy of wrong kind",
+			settings: &kubermaticv1.AuditLoggingSettings{
+				Policy: "apiVersion: v1\nkind: ConfigMap\n",
+			},
+			wantErr: true,
+		},
+		{
+			name: "policy with unknown fields",
+			settings: &kubermaticv1.AuditLoggingSettings{
+				Policy: "apiVersion: audit.k8s.io/v1\nkind: Policy\nrulez:\n- level: Metadata\n",
+			},
+			wantErr: true,
+		},
+		{
+			name: "policy with unknown level",
+			settings: &kubermaticv1.AuditLoggingSettings{
+				Policy: "apiVersion: audit.k8s.io/v1\nkind: Policy\nrules:\n- level: Everything\n",
+			},
+			wantErr: true,
+		},
+		{
+			name: "policy with wildcard in the middle of a non-resource URL",
+			settings: &kubermaticv1.AuditLoggingSettings{
+				Policy: "apiVersion: audit.k8s.io/v1\nkind: Policy\nrules:\n- level: None\n  nonResourceURLs: [\"/healthz*/foo\"]\n",
+			},
+			wantErr: true,
+		},
+		{
+			name: "policy of the seed",
+			settings: &kubermaticv1.AuditLoggingSettings{
+				Enabled:    true,
+				PolicyName: "secrets",
+			},
+			wantErr: false,
+		},
+		{
+			name: "policy missing in the seed",
+			settings: &kubermaticv1.AuditLoggingSettings{
+				Enabled:    true,
+				PolicyName: "pods",
+			},
+			wantErr: true,
+		},
+		{
+			name:       "audit logging enforced by datacenter",
+			settings:   &kubermaticv1.AuditLoggingSettings{Enabled: true},
+			datacenter: "audited",
+			wantErr:    false,
+		},
+		{
+			name:       "audit logging disabled in datacenter that enforces it",
+			datacenter: "audited",
+			wantErr:    true,
+		},
+		{
+			name: "one sink of every kind",
+			settings: &kubermaticv1.AuditLoggingSettings{
+				Sinks: []kubermaticv1.AuditSink{
+					{Name: "webhook", Webhook: &kubermaticv1.AuditWebhookSink{URL: "https://audit.example.com/events"}},
+					{Name: "http", HTTP: &kubermaticv1.AuditHTTPSink{URL: "http://audit.example.com"}},
+					{Name: "loki", Loki: &kubermaticv1.AuditLokiSink{URL: "https://loki.example.com", TenantID: "audit"}},
+					{Name: "s3", S3: &kubermaticv1.AuditS3Sink{Bucket: "audit", Region: "eu-central-1", CredentialsReference: credentials}},
+					{Name: "syslog", Syslog: &kubermaticv1.AuditSyslogSink{Address: "syslog.example.com:514", Protocol: kubermaticv1.AuditSyslogProtocolUDP}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "sink without backend",
+			settings: &kubermaticv1.AuditLoggingSettings{
+				Sinks: []kubermaticv1.AuditSink{{Name: "empty"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "sink with two backends",
+			settings: &kubermaticv1.AuditLoggingSettings{
+				Sinks: []kubermaticv1.AuditSink{{
+					Name:   "both",
+					HTTP:   &kubermaticv1.AuditHTTPSink{URL: "http://audit.example.com"},
+					Syslog: &kubermaticv1.AuditSyslogSink{Address: "syslog.example.com:514"},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate sink names",
+			settings: &kubermaticv1.AuditLoggingSettings{
+				Sinks: []kubermaticv1.AuditSink{
+					{Name: "audit", HTTP: &kubermaticv1.AuditHTTPSink{URL: "http://audit.example.com"}},
+					{Name: "audit", Loki: &kubermaticv1.AuditLokiSink{URL: "http://loki.example.com"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "two webhook sinks",
+			settings: &kubermaticv1.AuditLoggingSettings{
+				Sinks: []kubermaticv1.AuditSink{
+					{Name: "first", Webhook: &kubermaticv1.AuditWebhookSink{URL: "https://first.example.com"}},
+					{Name: "second", Webhook: &kubermaticv1.AuditWebhookSink{URL: "https://second.example.com"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "webhook without https",
+			settings: &kubermaticv1.AuditLoggingSettings{
+				Sinks: []kubermaticv1.AuditSink{{Name: "webhook", Webhook: &kubermaticv1.AuditWebhookSink{URL: "http://audit.example.com"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "S3 sink without credentials",
+			settings: &kubermaticv1.AuditLoggingSettings{
+				Sinks: []kubermaticv1.AuditSink{{Name: "s3", S3: &kubermaticv1.AuditS3Sink{Bucket: "audit", Region: "eu-central-1"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "S3 sink with inline credentials",
+			settings: &kubermaticv1.AuditLoggingSettings{
+				Sinks: []kubermaticv1.AuditSink{{Name: "s3", S3: &kubermaticv1.AuditS3Sink{Bucket: "audit", Region: "eu-central-1", AccessKeyID: "id", SecretAccessKey: "secret"}}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "sink name that is no DNS label",
+			settings: &kubermaticv1.AuditLoggingSettings{
+				Sinks: []kubermaticv1.AuditSink{{Name: "Audit Sink", HTTP: &kubermaticv1.AuditHTTPSink{URL: "http://audit.example.com"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "syslog sink without port",
+			settings: &kubermaticv1.AuditLoggingSettings{
+				Sinks: []kubermaticv1.AuditSink{{Name: "syslog", Syslog: &kubermaticv1.AuditSyslogSink{Address: "syslog.example.com"}}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := &kubermaticv1.ClusterSpec{
+				AuditLogging: test.settings,
+				Cloud:        kubermaticv1.CloudSpec{DatacenterName: test.datacenter},
+			}
+			errs := ValidateAuditLogging(spec, seed, field.NewPath("spec", "auditLogging"))
+
+			if test.wantErr == (len(errs) == 0) {
+				t.Errorf("Want error: %t, but got: \"%v\"", test.wantErr, errs)
+			}
+		})
+	}
+}
+
+func TestValidateAuditSinkCredentials(t *testing.T) {
+	tests := []struct {
+		name    string
+		sink    kubermaticv1.AuditSink
+		wantErr bool
+	}{
+		{
+			name: "inline credentials",
+			sink: kubermaticv1.AuditSink{Name: "http", HTTP: &kubermaticv1.AuditHTTPSink{URL: "https://audit.example.com", Username: "audit", Password: "secret"}},
+		},
+		{
+			name: "credentials in the audit sinks secret of the cluster",
+			sink: kubermaticv1.AuditSink{Name: "loki", Loki: &kubermaticv1.AuditLokiSink{
+				URL:                  "https://loki.example.com",
+				CredentialsReference: &providerconfig.GlobalSecretKeySelector{ObjectReference: corev1.ObjectReference{Name: "credential-audit-sinks-abcd", Namespace: "kubermatic"}, Key: "loki"},
+			}},
+		},
+		{
+			name: "cloud credentials of another cluster",
+			sink: kubermaticv1.AuditSink{Name: "http", HTTP: &kubermaticv1.AuditHTTPSink{
+				URL:                  "https://attacker.example.com",
+				CredentialsReference: &providerconfig.GlobalSecretKeySelector{ObjectReference: corev1.ObjectReference{Name: "credential-vsphere-efgh", Namespace: "kubermatic"}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "credentials of another sink of the cluster",
+			sink: kubermaticv1.AuditSink{Name: "http", HTTP: &kubermaticv1.AuditHTTPSink{
+				URL:                  "https://attacker.example.com",
+				CredentialsReference: &providerconfig.GlobalSecretKeySelector{ObjectReference: corev1.ObjectReference{Name: "credential-audit-sinks-abcd", Namespace: "kubermatic"}, Key: "s3"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "secret in the cluster namespace",
+			sink: kubermaticv1.AuditSink{Name: "s3", S3: &kubermaticv1.AuditS3Sink{
+				Bucket:               "audit",
+				Region:               "eu-central-1",
+				CredentialsReference: &providerconfig.GlobalSecretKeySelector{ObjectReference: corev1.ObjectReference{Name: "credential-audit-sinks-abcd", Namespace: "cluster-abcd"}, Key: "s3"},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cluster := &kubermaticv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "abcd"},
+				Spec: kubermaticv1.ClusterSpec{
+					AuditLogging: &kubermaticv1.AuditLoggingSettings{Enabled: true, Sinks: []kubermaticv1.AuditSink{test.sink}},
+				},
+			}
+			errs := ValidateAuditSinkCredentials(cluster, field.NewPath("spec", "auditLogging", "sinks"))
+
+			if test.wantErr == (len(errs) == 0) {
+				t.Errorf("Want error: %t, but got: \"%v\"", test.wantErr, errs)
+			}
+		})
+	}
+}
@@ -26,6 +26,7 @@ import (
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/features"
+	"k8c.io/kubermatic/v2/pkg/provider"
 	"k8c.io/kubermatic/v2/pkg/validation"
 
 	admissionv1 "k8s.io/api/admission/v1"
@@ -55,15 +56,17 @@ var (
 
 // AdmissionHandler for validating Kubermatic Cluster CRD.
 type AdmissionHandler struct {
-	log      logr.Logger
-	decoder  *admission.Decoder
-	features features.FeatureGate
+	log        logr.Logger
+	decoder    *admission.Decoder
+	features   features.FeatureGate
+	seedGetter provider.SeedGetter
 }
 
 // NewAdmissionHandler returns a new cluster validation AdmissionHandler.
-func NewAdmissionHandler(features features.FeatureGate) *AdmissionHandler {
+func NewAdmissionHandler(features features.FeatureGate, seedGetter provider.SeedGetter) *AdmissionHandler {
 	return &AdmissionHandler{
-		features: features,
+		features:   features,
+		seedGetter: seedGetter,
 	}
 }
 
@@ -131,6 +134,10 @@ func (h *AdmissionHandler) validateCreate(c *kubermaticv1.Cluster) field.ErrorLi
 	allErrs = append(allErrs, validation.ValidateNodePortRange(
 		c.Spec.ComponentsOverride.Apiserver.NodePortRange,
 		specFldPath.Child("componentsOverride", "apiserver", "nodePortRange"), true)...)
+	allErrs = append(allErrs, h.validateAPIServerAllowedIPRanges(c, specFldPath.Child("apiServerAllowedIPRanges"))...)
+	allErrs = append(allErrs, validation.ValidateEncryptionConfiguration(&c.Spec, nil, specFldPath.Child("encryptionConfiguration"))...)
+	allErrs = append(allErrs, validation.ValidateCertificateRotation(&c.Spec, specFldPath.Child("certificateRotation"))...)
+	allErrs = append(allErrs, h.validateAuditLogging(c, specFldPath.Child("auditLogging"))...)
 
 	return allErrs
 }
@@ -153,6 +160,10 @@ func (h *AdmissionHandler) validateUpdate(c, oldC *kubermaticv1.Cluster) field.E
 	allErrs = append(allErrs, validation.ValidateNodePortRange(
 		c.Spec.ComponentsOverride.Apiserver.NodePortRange,
 		specFldPath.Child("componentsOverride", "apiserver", "nodePortRange"), false)...)
+	allErrs = append(allErrs, h.validateAPIServerAllowedIPRanges(c, specFldPath.Child("apiServerAllowedIPRanges"))...)
+	allErrs = append(allErrs, validation.ValidateEncryptionConfiguration(&c.Spec, &oldC.Spec, specFldPath.Child("encryptionConfiguration"))...)
+	allErrs = append(allErrs, validation.ValidateCertificateRotation(&c.Spec, specFldPath.Child("certificateRotation"))...)
+	allErrs = append(allErrs, h.validateAuditLogging(c, specFldPath.Child("auditLogging"))...)
 
 	allErrs = append(allErrs, validateUpdateImmutability(c, oldC)...)
 	allErrs = append(allErrs, validateCNIUpdate(c.Spec.CNIPlugin, oldC.Spec.CNIPlugin, c.Labels)...)
@@ -160,6 +171,40 @@ func (h *AdmissionHandler) validateUpdate(c, oldC *kubermaticv1.Cluster) field.E
 	return allErrs
 }
 
+// validateAuditLogging validates the audit logging settings of the cluster, including the audit
+// policies and datacenters of the seed and the credentials referenced by the audit sinks.
+func (h *AdmissionHandler) validateAuditLogging(c *kubermaticv1.Cluster, fldPath *field.Path) field.ErrorList {
+	seed, err := h.seed()
+	if err != nil {
+		return field.ErrorList{field.InternalError(fldPath, err)}
+	}
+	allErrs := validation.ValidateAuditLogging(&c.Spec, seed, fldPath)
+	allErrs = append(allErrs, validation.ValidateAuditSinkCredentials(c, fldPath.Child("sinks"))...)
+	return allErrs
+}
+
+// validateAPIServerAllowedIPRanges validates the allowed IP ranges of the cluster against the
+// nodeport-proxy settings of the seed.
+func (h *AdmissionHandler) validateAPIServerAllowedIPRanges(c *kubermaticv1.Cluster, fldPath *field.Path) field.ErrorList {
+	seed, err := h.seed()
+	if err != nil {
+		return field.ErrorList{field.InternalError(fldPath, err)}
+	}
+	return validation.ValidateAPIServerAllowedIPRanges(&c.Spec, seed, fldPath)
+}
+
+// seed returns the seed the webhook runs in, or nil if it has no access to it.
+func (h *AdmissionHandler) seed() (*kubermaticv1.Seed, error) {
+	if h.seedGetter == nil {
+		return nil, nil
+	}
+	seed, err := h.seedGetter()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get seed: %v", err)
+	}
+	return seed, nil
+}
+
 func validateUpdateImmutability(c, oldC *kubermaticv1.Cluster) field.ErrorList {
 	// Immutability should be validated only for update requests
 	allErrs := field.ErrorList{}
@@ -0,0 +1,125 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrlruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	// unsafePresetDeletionLabel allows to delete presets that are still used by clusters.
+	unsafePresetDeletionLabel = "unsafe-preset-deletion"
+)
+
+// AdmissionHandler for validating Kubermatic Preset CRD.
+type AdmissionHandler struct {
+	log     logr.Logger
+	decoder *admission.Decoder
+}
+
+// NewAdmissionHandler returns a new preset validation AdmissionHandler.
+func NewAdmissionHandler() *AdmissionHandler {
+	return &AdmissionHandler{}
+}
+
+func (h *AdmissionHandler) InjectLogger(l logr.Logger) error {
+	h.log = l.WithName("preset-validation-handler")
+	return nil
+}
+
+func (h *AdmissionHandler) InjectDecoder(d *admission.Decoder) error {
+	h.decoder = d
+	return nil
+}
+
+func (h *AdmissionHandler) Handle(ctx context.Context, req webhook.AdmissionRequest) webhook.AdmissionResponse {
+	allErrs := field.ErrorList{}
+	var warnings []string
+	preset := &kubermaticv1.Preset{}
+	oldPreset := &kubermaticv1.Preset{}
+	switch req.Operation {
+	case admissionv1.Create:
+		if err := h.decoder.Decode(req, preset); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		allErrs = append(allErrs, validateSpec(preset)...)
+	case admissionv1.Update:
+		if err := h.decoder.Decode(req, preset); err != nil {
+			return admission.Errored(http.StatusBadRequest, fmt.Errorf("error occurred while decoding preset: %w", err))
+		}
+		if err := h.decoder.DecodeRaw(req.OldObject, oldPreset); err != nil {
+			return admission.Errored(http.StatusBadRequest, fmt.Errorf("error occurred while decoding old preset: %w", err))
+		}
+		allErrs = append(allErrs, validateSpec(preset)...)
+		// disabling a preset only prevents new clusters from using it, existing clusters keep their credentials
+		if oldPreset.Spec.IsEnabled() && !preset.Spec.IsEnabled() && len(oldPreset.Status.Clusters) > 0 {
+			warnings = append(warnings, fmt.Sprintf("preset %s is disabled, but still used by %d cluster(s)", preset.Name, len(oldPreset.Status.Clusters)))
+		}
+	case admissionv1.Delete:
+		if err := h.decoder.DecodeRaw(req.OldObject, oldPreset); err != nil {
+			return admission.Errored(http.StatusBadRequest, fmt.Errorf("error occurred while decoding old preset: %w", err))
+		}
+		allErrs = append(allErrs, validateDelete(oldPreset)...)
+	default:
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("%s not supported on preset resources", req.Operation))
+	}
+	if len(allErrs) > 0 {
+		return webhook.Denied(fmt.Sprintf("preset validation request %s denied: %v", req.UID, allErrs))
+	}
+	return webhook.Allowed(fmt.Sprintf("preset validation request %s allowed", req.UID)).WithWarnings(warnings...)
+}
+
+func validateSpec(p *kubermaticv1.Preset) field.ErrorList {
+	if p.Spec.ProjectSelector == nil {
+		return nil
+	}
+
+	if _, err := metav1.LabelSelectorAsSelector(p.Spec.ProjectSelector); err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath("spec", "projectSelector"), p.Spec.ProjectSelector, err.Error())}
+	}
+	return nil
+}
+
+func validateDelete(p *kubermaticv1.Preset) field.ErrorList {
+	if len(p.Status.Clusters) == 0 {
+		return nil
+	}
+
+	if _, ok := p.Labels[unsafePresetDeletionLabel]; ok {
+		return nil
+	}
+
+	return field.ErrorList{field.Forbidden(field.NewPath("status", "clusters"),
+		fmt.Sprintf("cannot delete preset %s, it is still used by %d cluster(s), rotate their credentials first or add the %s label", p.Name, len(p.Status.Clusters), unsafePresetDeletionLabel))}
+}
+
+func (h *AdmissionHandler) SetupWebhookWithManager(mgr ctrlruntime.Manager) {
+	mgr.GetWebhookServer().Register("/validate-kubermatic-k8s-io-preset", &webhook.Admission{Handler: h})
+}
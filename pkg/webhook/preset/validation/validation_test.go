@@ -0,0 +1,178 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-logr/logr"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var (
+	testScheme = runtime.NewScheme()
+)
+
+func init() {
+	_ = kubermaticv1.AddToScheme(testScheme)
+}
+
+func TestHandle(t *testing.T) {
+	usage := []kubermaticv1.PresetClusterReference{{Name: "cluster", ProjectID: "project", Seed: "seed"}}
+
+	tests := []struct {
+		name         string
+		operation    admissionv1.Operation
+		preset       rawPresetGen
+		oldPreset    rawPresetGen
+		wantAllowed  bool
+		wantWarnings bool
+	}{
+		{
+			name:        "Create preset success",
+			operation:   admissionv1.Create,
+			preset:      rawPresetGen{Name: "preset"},
+			wantAllowed: true,
+		},
+		{
+			name:      "Create preset with invalid project selector fails",
+			operation: admissionv1.Create,
+			preset: rawPresetGen{
+				Name: "preset",
+				ProjectSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Unknown"}},
+				},
+			},
+			wantAllowed: false,
+		},
+		{
+			name:        "Disable unused preset success",
+			operation:   admissionv1.Update,
+			preset:      rawPresetGen{Name: "preset", Enabled: pointer.BoolPtr(false)},
+			oldPreset:   rawPresetGen{Name: "preset"},
+			wantAllowed: true,
+		},
+		{
+			name:         "Disable used preset succeeds with a warning",
+			operation:    admissionv1.Update,
+			preset:       rawPresetGen{Name: "preset", Enabled: pointer.BoolPtr(false), Clusters: usage},
+			oldPreset:    rawPresetGen{Name: "preset", Clusters: usage},
+			wantAllowed:  true,
+			wantWarnings: true,
+		},
+		{
+			name:        "Delete unused preset success",
+			operation:   admissionv1.Delete,
+			oldPreset:   rawPresetGen{Name: "preset"},
+			wantAllowed: true,
+		},
+		{
+			name:        "Delete used preset fails",
+			operation:   admissionv1.Delete,
+			oldPreset:   rawPresetGen{Name: "preset", Clusters: usage},
+			wantAllowed: false,
+		},
+		{
+			name:      "Delete used preset with unsafe deletion label success",
+			operation: admissionv1.Delete,
+			oldPreset: rawPresetGen{
+				Name:     "preset",
+				Labels:   map[string]string{unsafePresetDeletionLabel: ""},
+				Clusters: usage,
+			},
+			wantAllowed: true,
+		},
+	}
+	for _, tt := range tests {
+		d, err := admission.NewDecoder(testScheme)
+		if err != nil {
+			t.Fatalf("error occurred while creating decoder: %v", err)
+		}
+		handler := AdmissionHandler{
+			log:     logr.Discard(),
+			decoder: d,
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			req := webhook.AdmissionRequest{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: tt.operation,
+					RequestKind: &metav1.GroupVersionKind{
+						Group:   kubermaticv1.GroupName,
+						Version: kubermaticv1.GroupVersion,
+						Kind:    "Preset",
+					},
+					Name: "preset",
+				},
+			}
+			if tt.operation != admissionv1.Delete {
+				req.Object = runtime.RawExtension{Raw: tt.preset.Do()}
+			}
+			if tt.operation != admissionv1.Create {
+				req.OldObject = runtime.RawExtension{Raw: tt.oldPreset.Do()}
+			}
+
+			res := handler.Handle(context.TODO(), req)
+			if res.Allowed != tt.wantAllowed {
+				t.Errorf("Allowed %t, but wanted %t", res.Allowed, tt.wantAllowed)
+				t.Logf("Response: %v", res)
+			}
+			if hasWarnings := len(res.Warnings) > 0; hasWarnings != tt.wantWarnings {
+				t.Errorf("Warnings %v, but wanted warnings: %t", res.Warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+type rawPresetGen struct {
+	Name            string
+	Labels          map[string]string
+	Enabled         *bool
+	ProjectSelector *metav1.LabelSelector
+	Clusters        []kubermaticv1.PresetClusterReference
+}
+
+func (r rawPresetGen) Do() []byte {
+	p := kubermaticv1.Preset{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "kubermatic.k8s.io/v1",
+			Kind:       "Preset",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   r.Name,
+			Labels: r.Labels,
+		},
+		Spec: kubermaticv1.PresetSpec{
+			Enabled:         r.Enabled,
+			ProjectSelector: r.ProjectSelector,
+		},
+		Status: kubermaticv1.PresetStatus{
+			Clusters: r.Clusters,
+		},
+	}
+	raw, _ := json.Marshal(p)
+	return raw
+}
@@ -26,9 +26,11 @@ import (
 	"k8c.io/kubermatic/v2/pkg/features"
 	"k8c.io/kubermatic/v2/pkg/provider"
 	"k8c.io/kubermatic/v2/pkg/util/workerlabel"
+	"k8c.io/kubermatic/v2/pkg/validation"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -110,6 +112,12 @@ func (v *validator) validate(ctx context.Context, subject *kubermaticv1.Seed, se
 		return errors.New("cannot create seed using Tunneling as a default expose strategy, the TunnelingExposeStrategy feature gate is not enabled")
 	}
 
+	for name, policy := range subject.Spec.AuditPolicies {
+		if errs := validation.ValidateAuditPolicy(policy, field.NewPath("spec", "auditPolicies").Key(name)); len(errs) > 0 {
+			return fmt.Errorf("audit policy %q is invalid: %v", name, errs.ToAggregate())
+		}
+	}
+
 	// this can be nil on new seed clusters
 	existingSeed := existingSeeds[subject.Name]
 
@@ -148,6 +156,15 @@ func (v *validator) validate(ctx context.Context, subject *kubermaticv1.Seed, se
 			return fmt.Errorf("datacenter %q has no provider defined", dcName)
 		}
 
+		if errs := validation.ValidateAuditSinks(dc.Spec.DefaultAuditSinks, field.NewPath("spec", "datacenters").Key(dcName).Child("spec", "defaultAuditSinks")); len(errs) > 0 {
+			return fmt.Errorf("datacenter %q is invalid: %v", dcName, errs.ToAggregate())
+		}
+		if policy := dc.Spec.MinimumAuditPolicy; policy != "" {
+			if _, ok := subject.Spec.AuditPolicies[policy]; !ok {
+				return fmt.Errorf("datacenter %q enforces audit policy %q, which does not exist", dcName, policy)
+			}
+		}
+
 		if existingSeed == nil {
 			continue
 		}